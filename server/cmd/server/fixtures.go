@@ -0,0 +1,14 @@
+//go:build fixtures
+
+package main
+
+import (
+	"wby/internal/app"
+	"wby/internal/fmi"
+	"wby/internal/fmi/fixtures"
+)
+
+// fixtureFMI serves the recorded FMI responses for FMI_MODE=fixtures.
+func fixtureFMI() (app.FMI, error) {
+	return fmi.NewFixtureClient(fixtures.FS), nil
+}
@@ -3,18 +3,14 @@ package main
 import (
 	"context"
 	"log/slog"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"wby/internal/api"
+	"wby/internal/app"
 	"wby/internal/config"
-	"wby/internal/fetcher"
-	"wby/internal/fmi"
-	"wby/internal/store"
-	"wby/internal/weather"
+	"wby/internal/version"
 )
 
 func main() {
@@ -25,50 +21,60 @@ func main() {
 	}))
 	slog.SetDefault(logger)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	build := version.Get()
+	slog.Info("starting wby server",
+		"version", build.Version,
+		"commit", build.Commit,
+		"build_date", build.BuildDate,
+		"go_version", build.GoVersion,
+	)
 
-	db, err := store.New(ctx, cfg.DatabaseURL)
-	if err != nil {
-		slog.Error("failed to connect to database", "err", err)
+	ctx := context.Background()
+
+	var opts []app.Option
+	switch cfg.FMIMode {
+	case "live":
+	case "fixtures":
+		f, err := fixtureFMI()
+		if err != nil {
+			slog.Error("FMI_MODE=fixtures unavailable", "err", err)
+			os.Exit(1)
+		}
+		slog.Warn("FMI_MODE=fixtures: serving recorded FMI responses instead of calling FMI")
+		opts = append(opts, app.WithFMI(f))
+	default:
+		slog.Error("unknown FMI_MODE, want live or fixtures", "mode", cfg.FMIMode)
 		os.Exit(1)
 	}
-	defer db.Close()
-
-	fmiClient := fmi.NewClient(cfg.FMIBaseURL, cfg.FMIAPIKey, cfg.FMITimeseriesURL)
 
-	svc := weather.NewService(db, fmiClient, 10*time.Minute)
-
-	f := fetcher.New(fmiClient, db)
-	go f.RunObservationLoop(ctx, 10*time.Minute)
-
-	mux := http.NewServeMux()
-	handler := api.NewHandler(svc)
-	handler.RegisterRoutes(mux)
-	signedMux := api.NewRequestSignatureMiddleware(cfg.ClientSecrets, cfg.RequestSignatureMaxAge)(mux)
-
-	srv := &http.Server{
-		Addr:         ":" + cfg.Port,
-		Handler:      signedMux,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
+	a, err := app.New(ctx, cfg, opts...)
+	if err != nil {
+		slog.Error("failed to build app", "err", err)
+		os.Exit(1)
+	}
+	a.Handle("GET /metrics", a.Metrics.Handler())
+	if err := a.Start(ctx); err != nil {
+		slog.Error("failed to start app", "err", err)
+		os.Exit(1)
 	}
-
-	go func() {
-		slog.Info("server starting", "port", cfg.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			slog.Error("server error", "err", err)
-			os.Exit(1)
-		}
-	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	exitCode := 0
+	select {
+	case <-quit:
+	case err := <-a.Errors():
+		slog.Error("server error", "err", err)
+		exitCode = 1
+	}
 
-	cancel()
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
-	srv.Shutdown(shutdownCtx)
+	if err := a.Stop(shutdownCtx); err != nil {
+		slog.Error("shutdown failed", "err", err)
+	}
 	slog.Info("server stopped")
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
 }
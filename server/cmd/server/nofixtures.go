@@ -0,0 +1,15 @@
+//go:build !fixtures
+
+package main
+
+import (
+	"errors"
+
+	"wby/internal/app"
+)
+
+// fixtureFMI fails in binaries built without the fixtures tag, which leave
+// the recorded FMI responses out.
+func fixtureFMI() (app.FMI, error) {
+	return nil, errors.New("built without fixtures; rebuild with -tags fixtures")
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"wby/internal/config"
+	"wby/internal/export"
+	"wby/internal/fetcher"
+	"wby/internal/fmi"
+	"wby/internal/seed"
+	"wby/internal/store"
+	"wby/internal/weather"
+)
+
+const usage = `usage: wby <command> [flags]
+
+commands:
+  seed --demo                   load the embedded demo dataset into DATABASE_URL
+  export --date <YYYY-MM-DD>    write the forecast/observation training export for a UTC day
+  backfill --since <RFC3339>    fetch and store the FMI observations made since then
+  bias --out <file> [--days N]  learn the BIAS_CORRECTION_FILE table from the last N days of forecast/observation pairs
+  accuracy --date <YYYY-MM-DD>  check a day's sunshine forecasts against FMI radiation observations
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "seed":
+		runSeed(os.Args[2:])
+	case "export":
+		runExport(os.Args[2:])
+	case "backfill":
+		runBackfill(os.Args[2:])
+	case "bias":
+		runBias(os.Args[2:])
+	case "accuracy":
+		runAccuracy(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+func runSeed(args []string) {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	demo := fs.Bool("demo", false, "load the embedded demo dataset")
+	fs.Parse(args)
+
+	if !*demo {
+		fmt.Fprint(os.Stderr, "wby seed: only --demo is supported\n")
+		os.Exit(2)
+	}
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		slog.Error("DATABASE_URL not set")
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	db, err := store.New(ctx, dsn)
+	if err != nil {
+		slog.Error("connect to database", "err", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	summary, err := seed.LoadDemo(ctx, db, time.Now())
+	if err != nil {
+		slog.Error("seed demo dataset", "err", err)
+		os.Exit(1)
+	}
+	slog.Info("demo dataset loaded",
+		"stations", summary.Stations,
+		"observations", summary.Observations,
+		"daily_forecasts", summary.DailyForecasts,
+		"hourly_forecasts", summary.HourlyForecasts,
+	)
+}
+
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	dateFlag := fs.String("date", time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02"), "UTC day to export")
+	fs.Parse(args)
+
+	day, err := time.Parse("2006-01-02", *dateFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "wby export: invalid --date %q\n", *dateFlag)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	sink := export.SinkFromConfig(cfg.Export)
+	if sink == nil {
+		slog.Error("set EXPORT_DIR or EXPORT_S3_BUCKET")
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	db, err := store.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		slog.Error("connect to database", "err", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	key, rows, err := export.New(db, sink).ExportDay(ctx, day)
+	if err != nil {
+		slog.Error("export training data", "err", err)
+		os.Exit(1)
+	}
+	slog.Info("training export written", "key", key, "rows", rows)
+}
+
+func runBackfill(args []string) {
+	fs := flag.NewFlagSet("backfill", flag.ExitOnError)
+	sinceFlag := fs.String("since", "", "fetch observations made since this RFC3339 time")
+	fs.Parse(args)
+
+	since, err := time.Parse(time.RFC3339, *sinceFlag)
+	if err != nil || !since.Before(time.Now()) {
+		fmt.Fprintf(os.Stderr, "wby backfill: --since must be an RFC3339 time in the past, got %q\n", *sinceFlag)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	client, err := newFMIClient(cfg)
+	if err != nil {
+		slog.Error("configure FMI client", "err", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	db, err := store.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		slog.Error("connect to database", "err", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	res, err := fetcher.New(client, db).Backfill(ctx, since)
+	if err != nil {
+		slog.Error("backfill observations", "err", err)
+		os.Exit(1)
+	}
+	slog.Info("observations backfilled",
+		"since", since,
+		"stations", res.Stations,
+		"observations", res.Observations,
+		"failed_windows", res.Errors,
+	)
+	if res.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+func runBias(args []string) {
+	fs := flag.NewFlagSet("bias", flag.ExitOnError)
+	out := fs.String("out", "", "file to write the bias table to")
+	days := fs.Int("days", 3, "UTC days of pairs to learn from, ending yesterday")
+	fs.Parse(args)
+
+	if *out == "" || *days < 1 {
+		fmt.Fprint(os.Stderr, "wby bias: --out and a positive --days are required\n")
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	ctx := context.Background()
+	db, err := store.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		slog.Error("connect to database", "err", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -*days)
+	pairs, err := db.ForecastObservationPairs(ctx, from, to, export.PairMaxDistanceKM)
+	if err != nil {
+		slog.Error("load forecast/observation pairs", "err", err)
+		os.Exit(1)
+	}
+	stations := weather.BuildBiasTable(pairs)
+
+	f, err := os.Create(*out)
+	if err != nil {
+		slog.Error("create bias table", "err", err)
+		os.Exit(1)
+	}
+	if err := weather.WriteBiasTable(f, stations); err != nil {
+		f.Close()
+		slog.Error("write bias table", "err", err)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		slog.Error("write bias table", "err", err)
+		os.Exit(1)
+	}
+	slog.Info("bias table written", "file", *out, "pairs", len(pairs), "stations", len(stations), "from", from, "to", to)
+}
+
+// newFMIClient configures an FMI client the way the server does.
+func newFMIClient(cfg config.Config) (*fmi.Client, error) {
+	client := fmi.NewClient(cfg.FMIBaseURL, cfg.FMIAPIKey, cfg.FMITimeseriesURL)
+	client.SetUserAgent(cfg.FMIUserAgent)
+	client.SetMaxResponseSize(int64(cfg.FMIMaxResponseSize), int64(cfg.FMIMaxUVResponseSize))
+	client.SetRetry(cfg.FMIRetryAttempts, cfg.FMIRetryBaseDelay)
+	client.SetTimeouts(cfg.FMIInteractiveTimeout, cfg.FMIBulkTimeout)
+	if err := client.SetAPIKeyMode(fmi.APIKeyMode(cfg.FMIAPIKeyMode)); err != nil {
+		return nil, err
+	}
+	if err := client.SetObservationFormat(fmi.ObservationFormat(cfg.FMIObservationFormat)); err != nil {
+		return nil, err
+	}
+	filter, err := fmi.ParseObservationFilter(cfg.ObservationBBox, cfg.ObservationPlaces, cfg.ObservationFMISIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.SetObservationFilter(filter); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// runAccuracy compares a day's stored sunshine forecasts with the sunshine
+// FMI's radiation stations measured. The day is the local calendar day the
+// daily forecasts cover.
+func runAccuracy(args []string) {
+	fs := flag.NewFlagSet("accuracy", flag.ExitOnError)
+	dateFlag := fs.String("date", time.Now().AddDate(0, 0, -1).Format("2006-01-02"), "day to check")
+	fs.Parse(args)
+
+	loc, err := time.LoadLocation(weather.DefaultPlaceTimezone)
+	if err != nil {
+		slog.Error("load timezone", "err", err)
+		os.Exit(1)
+	}
+	day, err := time.ParseInLocation("2006-01-02", *dateFlag, loc)
+	if err != nil || !day.AddDate(0, 0, 1).Before(time.Now()) {
+		fmt.Fprintf(os.Stderr, "wby accuracy: --date must be a past YYYY-MM-DD day, got %q\n", *dateFlag)
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	client, err := newFMIClient(cfg)
+	if err != nil {
+		slog.Error("configure FMI client", "err", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	db, err := store.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		slog.Error("connect to database", "err", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	forecasts, err := db.SunshineForecasts(ctx, day)
+	if err != nil {
+		slog.Error("load sunshine forecasts", "err", err)
+		os.Exit(1)
+	}
+	observations, err := client.FetchRadiationObservations(ctx, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		slog.Error("fetch radiation observations", "err", err)
+		os.Exit(1)
+	}
+
+	acc := weather.ValidateSunshine(day, forecasts, observations, export.PairMaxDistanceKM)
+	for _, c := range acc.Comparisons {
+		slog.Info("sunshine compared",
+			"lat", c.Lat, "lon", c.Lon,
+			"distance_km", c.DistanceKM,
+			"forecast_hours", c.ForecastHours,
+			"observed_hours", c.ObservedHours,
+			"fetched_at", c.FetchedAt,
+		)
+	}
+	slog.Info("sunshine accuracy",
+		"date", *dateFlag,
+		"stations", len(acc.Comparisons),
+		"mean_error_hours", acc.MeanError,
+		"mean_abs_error_hours", acc.MeanAbsError,
+	)
+}
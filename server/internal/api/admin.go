@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"wby/internal/fetcher"
+	"wby/internal/logging"
+)
+
+// Refresher runs an observation fetch on demand; *fetcher.Fetcher
+// implements it.
+type Refresher interface {
+	Refresh(ctx context.Context) (fetcher.RefreshResult, error)
+}
+
+// SetRefresher enables POST /v1/admin/refresh. Without one the route
+// answers 503.
+func (h *Handler) SetRefresher(r Refresher) {
+	h.refresher = r
+}
+
+type freshnessWindowJSON struct {
+	CacheTTL        string `json:"cache_ttl"`
+	CacheTTLSeconds int64  `json:"cache_ttl_seconds"`
+	MaxAge          string `json:"max_age,omitempty"`
+	MaxAgeSeconds   int64  `json:"max_age_seconds,omitempty"`
+}
+
+func (h *Handler) getFreshness(w http.ResponseWriter, r *http.Request) {
+	windows := h.service.Freshness().Named()
+	resp := make(map[string]freshnessWindowJSON, len(windows))
+	for name, window := range windows {
+		entry := freshnessWindowJSON{
+			CacheTTL:        window.CacheTTL.String(),
+			CacheTTLSeconds: int64(window.CacheTTL / time.Second),
+		}
+		if window.MaxAge > 0 {
+			entry.MaxAge = window.MaxAge.String()
+			entry.MaxAgeSeconds = int64(window.MaxAge / time.Second)
+		}
+		resp[name] = entry
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]any{"freshness": resp})
+}
+
+type refreshJSON struct {
+	Stations             int  `json:"stations"`
+	Observations         int  `json:"observations"`
+	Errors               int  `json:"errors"`
+	ForecastCacheCleared *int `json:"forecast_cache_cleared,omitempty"`
+}
+
+// postRefresh fetches observations immediately. scope=forecasts also drops
+// the cached forecasts once the fetch has run.
+func (h *Handler) postRefresh(w http.ResponseWriter, r *http.Request) {
+	scope := r.URL.Query().Get("scope")
+	if scope != "" && scope != "observations" && scope != "forecasts" {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid scope parameter")
+		return
+	}
+	if h.refresher == nil {
+		writeError(w, http.StatusServiceUnavailable, codeUnavailable, "observation fetcher disabled")
+		return
+	}
+
+	res, err := h.refresher.Refresh(r.Context())
+	if err != nil {
+		if errors.Is(err, fetcher.ErrRefreshInProgress) {
+			writeError(w, http.StatusConflict, codeConflict, err.Error())
+			return
+		}
+		logging.FromContext(r.Context()).Error("refresh failed", "err", err)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	resp := refreshJSON{Stations: res.Stations, Observations: res.Observations, Errors: res.Errors}
+	if scope == "forecasts" {
+		cleared := h.service.InvalidateForecastCaches()
+		resp.ForecastCacheCleared = &cleared
+	}
+	logging.FromContext(r.Context()).Info("admin refresh",
+		"scope", scope,
+		"stations", resp.Stations,
+		"observations", resp.Observations,
+		"errors", resp.Errors,
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
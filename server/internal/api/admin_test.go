@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wby/internal/fetcher"
+)
+
+func TestGetFreshness_ReportsNamedWindows(t *testing.T) {
+	h := NewHandler(weatherServiceStub{})
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/admin/freshness", nil)
+
+	h.getFreshness(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp struct {
+		Freshness map[string]freshnessWindowJSON `json:"freshness"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	daily, ok := resp.Freshness["daily_forecast"]
+	if !ok {
+		t.Fatalf("expected daily_forecast window, got %v", resp.Freshness)
+	}
+	if daily.CacheTTLSeconds != 600 || daily.MaxAgeSeconds != 3*3600 {
+		t.Fatalf("unexpected daily_forecast window: %+v", daily)
+	}
+	if lb := resp.Freshness["leaderboard"]; lb.MaxAge != "" {
+		t.Fatalf("expected no max_age for leaderboard, got %q", lb.MaxAge)
+	}
+}
+
+type refresherStub struct {
+	res fetcher.RefreshResult
+	err error
+}
+
+func (s refresherStub) Refresh(ctx context.Context) (fetcher.RefreshResult, error) {
+	return s.res, s.err
+}
+
+type invalidatingServiceStub struct {
+	weatherServiceStub
+	invalidated *int
+}
+
+func (s invalidatingServiceStub) InvalidateForecastCaches() int {
+	*s.invalidated++
+	return 7
+}
+
+func TestPostRefresh(t *testing.T) {
+	var invalidated int
+	h := NewHandler(invalidatingServiceStub{invalidated: &invalidated})
+
+	rr := httptest.NewRecorder()
+	h.postRefresh(rr, httptest.NewRequest(http.MethodPost, "/v1/admin/refresh", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503 without a fetcher, got %d", rr.Code)
+	}
+
+	h.SetRefresher(refresherStub{res: fetcher.RefreshResult{Stations: 180, Observations: 1260}})
+	rr = httptest.NewRecorder()
+	h.postRefresh(rr, httptest.NewRequest(http.MethodPost, "/v1/admin/refresh", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp["stations"] != 180.0 || resp["observations"] != 1260.0 || invalidated != 0 {
+		t.Fatalf("unexpected response %v (invalidated %d)", resp, invalidated)
+	}
+	if _, ok := resp["forecast_cache_cleared"]; ok {
+		t.Fatalf("expected no forecast_cache_cleared without scope=forecasts: %v", resp)
+	}
+
+	rr = httptest.NewRecorder()
+	h.postRefresh(rr, httptest.NewRequest(http.MethodPost, "/v1/admin/refresh?scope=forecasts", nil))
+	if rr.Code != http.StatusOK || invalidated != 1 || !strings.Contains(rr.Body.String(), `"forecast_cache_cleared":7`) {
+		t.Fatalf("expected forecast caches cleared, got %d %s", rr.Code, rr.Body)
+	}
+
+	rr = httptest.NewRecorder()
+	h.postRefresh(rr, httptest.NewRequest(http.MethodPost, "/v1/admin/refresh?scope=everything", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for an unknown scope, got %d", rr.Code)
+	}
+
+	h.SetRefresher(refresherStub{err: fetcher.ErrRefreshInProgress})
+	rr = httptest.NewRecorder()
+	h.postRefresh(rr, httptest.NewRequest(http.MethodPost, "/v1/admin/refresh?scope=forecasts", nil))
+	if rr.Code != http.StatusConflict || invalidated != 1 {
+		t.Fatalf("expected status 409 without invalidation, got %d (invalidated %d)", rr.Code, invalidated)
+	}
+}
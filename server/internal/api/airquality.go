@@ -0,0 +1,37 @@
+package api
+
+import (
+	"time"
+
+	"wby/internal/weather"
+)
+
+// airQualityJSON is the latest measurement from the nearest urban air
+// quality station. Concentrations are hourly means in µg/m³; index is the
+// Finnish air quality index from 1 (good) to 5 (very poor).
+type airQualityJSON struct {
+	Station    stationJSON `json:"station"`
+	ObservedAt time.Time   `json:"observed_at"`
+	PM25       *float64    `json:"pm2_5"`
+	PM10       *float64    `json:"pm10"`
+	O3         *float64    `json:"o3"`
+	NO2        *float64    `json:"no2"`
+	Index      int         `json:"index"`
+	Category   string      `json:"category"`
+}
+
+func toAirQualityJSON(r *weather.AirQualityReading) *airQualityJSON {
+	if r == nil {
+		return nil
+	}
+	return &airQualityJSON{
+		Station:    stationJSON{Name: r.Station.Name, DistanceKM: r.DistanceKM, fmisid: r.Station.FMISID},
+		ObservedAt: r.ObservedAt,
+		PM25:       r.PM25,
+		PM10:       r.PM10,
+		O3:         r.O3,
+		NO2:        r.NO2,
+		Index:      r.Index,
+		Category:   r.Category,
+	}
+}
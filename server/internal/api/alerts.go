@@ -0,0 +1,39 @@
+package api
+
+import (
+	"time"
+
+	"wby/internal/weather"
+)
+
+// alertJSON is an active official warning covering the requested point.
+// Severity is the CAP value: Minor, Moderate, Severe or Extreme.
+type alertJSON struct {
+	ID          string    `json:"id"`
+	Event       string    `json:"event"`
+	Severity    string    `json:"severity"`
+	Headline    string    `json:"headline,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Area        string    `json:"area,omitempty"`
+	Onset       time.Time `json:"onset"`
+	Expires     time.Time `json:"expires"`
+}
+
+// toAlertsJSON always returns a non-nil slice so clients get [] rather
+// than null when nothing is in effect.
+func toAlertsJSON(warnings []weather.Warning) []alertJSON {
+	alerts := make([]alertJSON, 0, len(warnings))
+	for _, w := range warnings {
+		alerts = append(alerts, alertJSON{
+			ID:          w.ID,
+			Event:       w.Event,
+			Severity:    w.Severity,
+			Headline:    w.Headline,
+			Description: w.Description,
+			Area:        w.AreaDesc,
+			Onset:       w.Onset,
+			Expires:     w.Expires,
+		})
+	}
+	return alerts
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"time"
+
+	"wby/internal/weather"
+)
+
+// contributorJSON is a station that supplied some of the blended current
+// conditions, with the current block fields it supplied.
+type contributorJSON struct {
+	FMISID     int       `json:"fmisid"`
+	Name       string    `json:"name"`
+	DistanceKM float64   `json:"distance_km"`
+	ObservedAt time.Time `json:"observed_at"`
+	Fields     []string  `json:"fields"`
+}
+
+func toContributorsJSON(contributions []weather.BlendContribution) []contributorJSON {
+	if len(contributions) == 0 {
+		return nil
+	}
+	out := make([]contributorJSON, len(contributions))
+	for i, c := range contributions {
+		out[i] = contributorJSON{
+			FMISID:     c.Station.FMISID,
+			Name:       c.Station.Name,
+			DistanceKM: c.DistanceKM,
+			ObservedAt: c.ObservedAt,
+			Fields:     c.Fields,
+		}
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestGetWeather_BlendIsOptIn(t *testing.T) {
+	observedAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	temp, precip := -3.0, 0.2
+	gauge := weather.Station{FMISID: 1, Name: "Gauge"}
+	aws := weather.Station{FMISID: 2, Name: "AWS"}
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{Current: weather.CurrentWeather{
+			Station:     gauge,
+			DistanceKM:  1,
+			Observation: weather.Observation{ObservedAt: observedAt, Precip1h: &precip},
+		}},
+		blended: &weather.CurrentWeather{
+			Station:     gauge,
+			DistanceKM:  1,
+			Observation: weather.Observation{ObservedAt: observedAt, Precip1h: &precip, Temperature: &temp},
+			Contributors: []weather.BlendContribution{
+				{Station: gauge, DistanceKM: 1, ObservedAt: observedAt, Fields: []string{"precipitation_1h"}},
+				{Station: aws, DistanceKM: 3, ObservedAt: observedAt, Fields: []string{"temperature"}},
+			},
+		},
+	})
+
+	type response struct {
+		Station struct {
+			Name         string            `json:"name"`
+			Contributors []contributorJSON `json:"contributors"`
+		} `json:"station"`
+		Current struct {
+			Temperature *float64 `json:"temperature"`
+		} `json:"current"`
+	}
+	get := func(query string) response {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94"+query, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rr.Code)
+		}
+		var resp response
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	plain := get("")
+	if plain.Current.Temperature != nil || plain.Station.Contributors != nil {
+		t.Fatalf("expected the unblended nearest station without blend, got %+v", plain)
+	}
+
+	blended := get("&blend=true")
+	if blended.Current.Temperature == nil || *blended.Current.Temperature != -3 {
+		t.Fatalf("expected the blended temperature, got %v", blended.Current.Temperature)
+	}
+	if blended.Station.Name != "Gauge" || len(blended.Station.Contributors) != 2 {
+		t.Fatalf("unexpected station block %+v", blended.Station)
+	}
+	if c := blended.Station.Contributors[1]; c.FMISID != 2 || c.DistanceKM != 3 || len(c.Fields) != 1 || c.Fields[0] != "temperature" {
+		t.Fatalf("unexpected contributor %+v", c)
+	}
+}
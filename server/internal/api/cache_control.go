@@ -0,0 +1,56 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"wby/internal/weather"
+)
+
+const (
+	// DefaultObservationInterval is the observation fetch interval assumed
+	// until SetObservationInterval is called.
+	DefaultObservationInterval = 10 * time.Minute
+
+	// minWeatherMaxAge keeps clients from revalidating in a tight loop when
+	// an ingest is overdue.
+	minWeatherMaxAge = 30 * time.Second
+	// forecastMaxAge applies when only hourly and daily forecasts are
+	// requested; FMI refreshes its models a few times a day.
+	forecastMaxAge = 15 * time.Minute
+	// weatherStaleWhileRevalidate lets caches serve the old body while
+	// they fetch the next one.
+	weatherStaleWhileRevalidate = time.Minute
+)
+
+// SetObservationInterval tells the handler how often observations are
+// fetched, so /v1/weather responses expire when the next ingest lands.
+func (h *Handler) SetObservationInterval(d time.Duration) {
+	if d <= 0 {
+		d = DefaultObservationInterval
+	}
+	h.observationInterval = d
+}
+
+// weatherMaxAge is how long a /v1/weather response stays fresh. Responses
+// with current conditions expire at the next expected observation ingest,
+// i.e. the fetch interval minus the observation's age, floored at
+// minWeatherMaxAge.
+func (h *Handler) weatherMaxAge(resp *weatherJSON, sections weather.Section, now time.Time) time.Duration {
+	if sections != 0 && sections&^(weather.SectionHourly|weather.SectionDaily) == 0 {
+		return forecastMaxAge
+	}
+	interval := h.observationInterval
+	if interval <= 0 {
+		interval = DefaultObservationInterval
+	}
+	maxAge := interval
+	if observedAt := resp.Current.ObservedAt; !observedAt.IsZero() && observedAt.Before(now) {
+		maxAge = interval - now.Sub(observedAt)
+	}
+	return max(maxAge, minWeatherMaxAge)
+}
+
+func cacheControl(maxAge, staleWhileRevalidate time.Duration) string {
+	return fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d", int(maxAge.Seconds()), int(staleWhileRevalidate.Seconds()))
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestWeatherMaxAge(t *testing.T) {
+	now := time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)
+	h := NewHandler(weatherServiceStub{})
+	h.SetObservationInterval(10 * time.Minute)
+
+	tests := []struct {
+		name       string
+		observedAt time.Time
+		sections   weather.Section
+		want       time.Duration
+	}{
+		{"fresh observation", now.Add(-2 * time.Minute), 0, 8 * time.Minute},
+		{"ingest due soon", now.Add(-9*time.Minute - 50*time.Second), 0, minWeatherMaxAge},
+		{"overdue ingest", now.Add(-25 * time.Minute), 0, minWeatherMaxAge},
+		{"no observation", time.Time{}, 0, 10 * time.Minute},
+		{"current selected", now.Add(-4 * time.Minute), weather.SectionCurrent | weather.SectionDaily, 6 * time.Minute},
+		{"forecasts only", now.Add(-9 * time.Minute), weather.SectionHourly | weather.SectionDaily, forecastMaxAge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &weatherJSON{Current: currentJSON{ObservedAt: tt.observedAt}}
+			if got := h.weatherMaxAge(resp, tt.sections, now); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetWeather_CacheControl(t *testing.T) {
+	h := NewHandler(weatherServiceStub{weather: &weather.WeatherResponse{
+		Current: weather.CurrentWeather{Observation: weather.Observation{ObservedAt: time.Now().Add(-3 * time.Minute)}},
+	}})
+
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94", nil))
+	// Allow a second of slack for the clock moving during the request.
+	got := rr.Header().Get("Cache-Control")
+	if got != "public, max-age=420, stale-while-revalidate=60" && got != "public, max-age=419, stale-while-revalidate=60" {
+		t.Errorf("unexpected Cache-Control %q", got)
+	}
+}
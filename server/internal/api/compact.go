@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+
+	"wby/internal/weather"
+)
+
+// compactWeatherJSON is the GET /v1/weather/compact body for widgets: flat,
+// unix-second times and values rounded to one decimal, about 300 bytes.
+type compactWeatherJSON struct {
+	Station    string            `json:"station"`
+	ObservedAt int64             `json:"observed_at"`
+	Temp       *float64          `json:"temp"`
+	FeelsLike  *float64          `json:"feels_like"`
+	Symbol     *string           `json:"symbol"`
+	Hourly     []compactHourJSON `json:"hourly"`
+}
+
+type compactHourJSON struct {
+	Time   int64    `json:"time"`
+	Temp   *float64 `json:"temp"`
+	Symbol *string  `json:"symbol"`
+}
+
+func (h *Handler) getCompactWeather(w http.ResponseWriter, r *http.Request) {
+	q, err := parseWeatherQuery(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if !h.resolveWeatherPlace(w, r, &q) {
+		return
+	}
+
+	result, err := h.service.GetCompactWeather(r.Context(), q.lat, q.lon)
+	if err != nil {
+		writeServiceError(w, r, err, "get compact weather failed", "lat", q.lat, "lon", q.lon)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(toCompactWeatherJSON(result, q.units))
+}
+
+func toCompactWeatherJSON(result *weather.WeatherResponse, units string) compactWeatherJSON {
+	temp := func(v *float64) *float64 {
+		if units == unitsImperial {
+			v = convert(v, celsiusToFahrenheit)
+		}
+		return convert(v, func(x float64) float64 { return math.Round(x*10) / 10 })
+	}
+
+	obs := result.Current.Observation
+	resp := compactWeatherJSON{
+		Station:    result.Current.Station.Name,
+		ObservedAt: obs.ObservedAt.Unix(),
+		Temp:       temp(obs.Temperature),
+		FeelsLike:  temp(computeFeelsLike(obs.Temperature, obs.WindSpeed)),
+		Hourly:     make([]compactHourJSON, len(result.Hourly)),
+	}
+	// Stations report no symbol; the forecast for the current hour stands
+	// in for it.
+	if len(result.Hourly) > 0 {
+		resp.Symbol = result.Hourly[0].Symbol
+	}
+	for i, f := range result.Hourly {
+		resp.Hourly[i] = compactHourJSON{Time: f.Time.Unix(), Temp: temp(f.Temperature), Symbol: f.Symbol}
+	}
+	return resp
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// observationAge varies with the wall clock, so golden files pin it.
+var observationAge = regexp.MustCompile(`"observation_age_seconds":\d+`)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestGetWeather_CompactNullsGolden(t *testing.T) {
+	observedAt := time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{
+			Current: weather.CurrentWeather{
+				Station:     weather.Station{FMISID: 100971, Name: "Helsinki Kaisaniemi", Lat: 60.18, Lon: 24.94},
+				DistanceKM:  1.2,
+				Observation: weather.Observation{ObservedAt: observedAt, Temperature: ptr(4.5), WindSpeed: ptr(3.2)},
+			},
+			Forecast: []weather.DailyForecast{{Date: observedAt.Truncate(24 * time.Hour), Source: weather.ForecastSourceEdited, TempHigh: ptr(7.0), TempLow: ptr(1.0), Symbol: ptr("3"), FetchedAt: observedAt}},
+			Hourly:   []weather.HourlyForecast{{Time: observedAt.Add(time.Hour), Temperature: ptr(5.0), FetchedAt: observedAt}},
+			Timezone: "Europe/Helsinki",
+			Provenance: weather.Provenance{
+				GridLat: 60.2, GridLon: 24.9,
+				Forecast: weather.SourceDB, Hourly: weather.SourceCache, UV: weather.SourceUnavailable,
+			},
+		},
+	})
+
+	for _, tt := range []struct {
+		query  string
+		golden string
+	}{
+		{"", "weather.golden.json"},
+		{"&compact_nulls=true", "weather_compact_nulls.golden.json"},
+	} {
+		t.Run(tt.golden, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94"+tt.query, nil))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rr.Code)
+			}
+			var got bytes.Buffer
+			if err := json.Indent(&got, observationAge.ReplaceAll(rr.Body.Bytes(), []byte(`"observation_age_seconds":0`)), "", "  "); err != nil {
+				t.Fatal(err)
+			}
+			got.WriteByte('\n')
+
+			path := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Fatalf("response differs from %s (rerun with -update to accept):\n%s", path, got.Bytes())
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestGetCompactWeather(t *testing.T) {
+	temp, wind := -4.26, 5.0
+	sym := "41"
+	start := time.Date(2026, 1, 15, 13, 0, 0, 0, time.UTC)
+	result := &weather.WeatherResponse{
+		Current: weather.CurrentWeather{
+			Station:     weather.Station{Name: "Helsinki Kaisaniemi"},
+			Observation: weather.Observation{ObservedAt: start.Add(-10 * time.Minute), Temperature: &temp, WindSpeed: &wind},
+		},
+	}
+	for i := range weather.CompactHourlyForecastHours {
+		result.Hourly = append(result.Hourly, weather.HourlyForecast{Time: start.Add(time.Duration(i) * time.Hour), Temperature: &temp, Symbol: &sym})
+	}
+	h := NewHandler(weatherServiceStub{weather: result})
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/weather/compact?lat=60.17&lon=24.94", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if rr.Body.Len() > 400 {
+		t.Errorf("expected a small payload, got %d bytes: %s", rr.Body.Len(), rr.Body)
+	}
+	var resp compactWeatherJSON
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Temp == nil || *resp.Temp != -4.3 || resp.FeelsLike == nil || *resp.FeelsLike >= -4.3 {
+		t.Errorf("unexpected current temps %v %v", resp.Temp, resp.FeelsLike)
+	}
+	if resp.Symbol == nil || *resp.Symbol != "41" || resp.ObservedAt != start.Add(-10*time.Minute).Unix() {
+		t.Errorf("unexpected current %+v", resp)
+	}
+	if len(resp.Hourly) != weather.CompactHourlyForecastHours || resp.Hourly[5].Time != start.Add(5*time.Hour).Unix() {
+		t.Errorf("unexpected hourly %+v", resp.Hourly)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/weather/compact?lat=60.17&lon=24.94&units=imperial", nil))
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if *resp.Temp != 24.3 || *resp.Hourly[0].Temp != 24.3 {
+		t.Errorf("expected °F, got %v and %v", *resp.Temp, *resp.Hourly[0].Temp)
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/weather/compact?lat=abc&lon=24.94", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+const corsMaxAge = "600"
+
+var corsAllowHeaders = strings.Join([]string{
+	"Content-Type",
+	signatureHeaderClientID,
+	signatureHeaderTimestamp,
+	signatureHeaderValue,
+}, ", ")
+
+// NewCORSMiddleware allows browsers on the listed origins to call the API.
+// An origin is either exact ("https://dash.example.com"), "*" for any
+// origin, or a subdomain wildcard ("https://*.example.com"). Preflight
+// requests are answered here, before the signature middleware would reject
+// them as unsigned; browsers never sign preflights.
+func NewCORSMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+	var exact []string
+	var suffixes [][2]string
+	anyOrigin := false
+	for _, o := range allowedOrigins {
+		o = strings.TrimRight(strings.TrimSpace(o), "/")
+		switch {
+		case o == "":
+		case o == "*":
+			anyOrigin = true
+		case strings.Contains(o, "://*."):
+			scheme, host, _ := strings.Cut(o, "://*")
+			suffixes = append(suffixes, [2]string{scheme + "://", host})
+		default:
+			exact = append(exact, o)
+		}
+	}
+	allowed := func(origin string) bool {
+		if anyOrigin {
+			return true
+		}
+		for _, o := range exact {
+			if strings.EqualFold(o, origin) {
+				return true
+			}
+		}
+		lower := strings.ToLower(origin)
+		for _, s := range suffixes {
+			rest, ok := strings.CutPrefix(lower, strings.ToLower(s[0]))
+			if ok && len(rest) > len(s[1]) && strings.HasSuffix(rest, strings.ToLower(s[1])) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		if len(exact) == 0 && len(suffixes) == 0 && !anyOrigin {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Origin")
+			ok := allowed(origin)
+			if ok {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				if ok {
+					w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+					w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+					w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			if ok {
+				w.Header().Set("Access-Control-Expose-Headers", requestIDHeader)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
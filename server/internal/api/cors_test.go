@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSMiddleware_AllowsListedOrigins(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	for _, tc := range []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{"exact match", []string{"https://dash.example.com"}, "https://dash.example.com", true},
+		{"exact mismatch", []string{"https://dash.example.com"}, "https://evil.example.com", false},
+		{"any origin", []string{"*"}, "https://anything.test", true},
+		{"subdomain wildcard", []string{"https://*.example.com"}, "https://dash.example.com", true},
+		{"wildcard needs subdomain", []string{"https://*.example.com"}, "https://example.com", false},
+		{"wildcard checks scheme", []string{"https://*.example.com"}, "http://dash.example.com", false},
+		{"wildcard checks suffix", []string{"https://*.example.com"}, "https://dash.example.com.evil.test", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/weather", nil)
+			req.Header.Set("Origin", tc.origin)
+			rr := httptest.NewRecorder()
+			NewCORSMiddleware(tc.allowed)(next).ServeHTTP(rr, req)
+
+			got := rr.Header().Get("Access-Control-Allow-Origin")
+			if tc.want && got != tc.origin {
+				t.Fatalf("expected Access-Control-Allow-Origin %q, got %q", tc.origin, got)
+			}
+			if !tc.want && got != "" {
+				t.Fatalf("expected no Access-Control-Allow-Origin, got %q", got)
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_AnswersPreflightBeforeSignatureCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/weather", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("preflight must not reach the handler")
+	})
+	signed := NewRequestSignatureMiddleware(map[string]string{"web": "secret"}, nil, 5*time.Minute)(mux)
+	handler := NewCORSMiddleware([]string{"https://dash.example.com"})(signed)
+
+	req := httptest.NewRequest(http.MethodOptions, "/v1/weather", nil)
+	req.Header.Set("Origin", "https://dash.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	req.Header.Set("Access-Control-Request-Headers", "x-client-id, x-timestamp, x-signature")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+		t.Fatalf("unexpected Access-Control-Allow-Origin %q", got)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, X-Client-ID, X-Timestamp, X-Signature" {
+		t.Fatalf("unexpected Access-Control-Allow-Headers %q", got)
+	}
+}
+
+func TestCORSMiddleware_UnsignedRequestsStillRejected(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/weather", func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unsigned request must not reach the handler")
+	})
+	signed := NewRequestSignatureMiddleware(map[string]string{"web": "secret"}, nil, 5*time.Minute)(mux)
+	handler := NewCORSMiddleware([]string{"*"})(signed)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/weather", nil)
+	req.Header.Set("Origin", "https://dash.example.com")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "https://dash.example.com" {
+		t.Fatalf("expected CORS headers on the error so the browser can read it, got %q", got)
+	}
+}
@@ -0,0 +1,360 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+const maxCustomObservationBody = 64 << 10
+
+type customStationJSON struct {
+	StationID  string    `json:"station_id"`
+	Source     string    `json:"source"`
+	DistanceKM float64   `json:"distance_km"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+type customObservationRequestJSON struct {
+	StationID   string    `json:"station_id"`
+	Lat         *float64  `json:"lat"`
+	Lon         *float64  `json:"lon"`
+	ObservedAt  time.Time `json:"observed_at"`
+	Temperature *float64  `json:"temperature"`
+	Humidity    *float64  `json:"humidity"`
+	DewPoint    *float64  `json:"dew_point"`
+	WindSpeed   *float64  `json:"wind_speed"`
+	WindGust    *float64  `json:"wind_gust"`
+	WindDir     *float64  `json:"wind_dir"`
+	Pressure    *float64  `json:"pressure"`
+	Precip1h    *float64  `json:"precip_1h"`
+}
+
+type customObservationResponseJSON struct {
+	StationID  string    `json:"station_id"`
+	Source     string    `json:"source"`
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// Device upload formats accepted by postStationIngest.
+const (
+	formatEcowitt     = "ecowitt"
+	formatWeatherFlow = "weatherflow"
+)
+
+type customStationRequestJSON struct {
+	StationID string   `json:"station_id"`
+	Lat       *float64 `json:"lat"`
+	Lon       *float64 `json:"lon"`
+	Format    string   `json:"format"`
+}
+
+type customStationResponseJSON struct {
+	StationID  string `json:"station_id"`
+	Format     string `json:"format"`
+	Token      string `json:"token"`
+	IngestPath string `json:"ingest_path"`
+}
+
+// postCustomObservation accepts readings in the native JSON format from a
+// user's own weather station, signed like every /v1/ request. Devices that
+// upload in their own format cannot sign and use postStationIngest.
+func (h *Handler) postCustomObservation(w http.ResponseWriter, r *http.Request) {
+	clientID := clientIDFromContext(r.Context())
+	if clientID == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	switch r.URL.Query().Get("format") {
+	case "", "native":
+	case formatEcowitt, formatWeatherFlow:
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "device formats are uploaded to the ingest_path of a station registered at /v1/observations/custom/stations")
+		return
+	default:
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid format parameter")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxCustomObservationBody)
+
+	obs, err := parseNativeObservation(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	obs.ClientID = clientID
+	obs.Source = "native"
+	h.ingestCustomObservation(w, r, obs)
+}
+
+// postCustomStation registers a personal station whose device uploads in
+// its own format and returns the token for its unsigned ingest path. The
+// token is shown only here; registering the station again issues a new
+// one and revokes the old.
+func (h *Handler) postCustomStation(w http.ResponseWriter, r *http.Request) {
+	clientID := clientIDFromContext(r.Context())
+	if clientID == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req customStationRequestJSON
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid JSON body")
+		return
+	}
+	if req.Format != formatEcowitt && req.Format != formatWeatherFlow {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "format must be ecowitt or weatherflow")
+		return
+	}
+	if req.Lat == nil || req.Lon == nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "lat and lon are required")
+		return
+	}
+	st := weather.CustomStation{ClientID: clientID, StationID: req.StationID, Format: req.Format, Lat: *req.Lat, Lon: *req.Lon}
+	if err := validateCustomStation(st.StationID, st.Lat, st.Lon); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	token, err := h.service.RegisterCustomStation(r.Context(), st)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("register custom station failed", "err", err, "client_id", clientID, "station_id", st.StationID)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(customStationResponseJSON{
+		StationID:  st.StationID,
+		Format:     st.Format,
+		Token:      token,
+		IngestPath: "/ingest/" + token,
+	})
+}
+
+// postStationIngest accepts an upload from a registered station's device:
+// the Ecowitt custom-server form upload or a WeatherFlow Tempest obs_st
+// JSON message. Such devices cannot sign requests, so the token in the
+// path authenticates the station, and the station, client and position
+// come from its registration rather than the payload.
+func (h *Handler) postStationIngest(w http.ResponseWriter, r *http.Request) {
+	st, err := h.service.CustomStationForToken(r.Context(), r.PathValue("token"))
+	if err != nil {
+		if errors.Is(err, weather.ErrUnknownStationToken) {
+			writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+			return
+		}
+		logging.FromContext(r.Context()).Error("look up station token failed", "err", err)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxCustomObservationBody)
+
+	var obs weather.CustomObservation
+	switch st.Format {
+	case formatEcowitt:
+		obs, err = parseEcowittObservation(r)
+	case formatWeatherFlow:
+		obs, err = parseWeatherFlowObservation(r)
+	default:
+		err = fmt.Errorf("station has no device format")
+	}
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	obs.ClientID = st.ClientID
+	obs.StationID = st.StationID
+	obs.Lat = st.Lat
+	obs.Lon = st.Lon
+	obs.Source = st.Format
+	h.ingestCustomObservation(w, r, obs)
+}
+
+func (h *Handler) ingestCustomObservation(w http.ResponseWriter, r *http.Request, obs weather.CustomObservation) {
+	if obs.ObservedAt.IsZero() {
+		obs.ObservedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	if err := validateCustomObservation(obs); err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	if err := h.service.IngestCustomObservation(r.Context(), obs); err != nil {
+		logging.FromContext(r.Context()).Error("ingest custom observation failed", "err", err, "client_id", obs.ClientID, "station_id", obs.StationID)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(customObservationResponseJSON{
+		StationID:  obs.StationID,
+		Source:     obs.Source,
+		ObservedAt: obs.ObservedAt,
+	})
+}
+
+func parseNativeObservation(r *http.Request) (weather.CustomObservation, error) {
+	var req customObservationRequestJSON
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return weather.CustomObservation{}, errors.New("invalid JSON body")
+	}
+	if req.Lat == nil || req.Lon == nil {
+		return weather.CustomObservation{}, errors.New("lat and lon are required")
+	}
+	return weather.CustomObservation{
+		StationID:   req.StationID,
+		Lat:         *req.Lat,
+		Lon:         *req.Lon,
+		ObservedAt:  req.ObservedAt.UTC(),
+		Temperature: req.Temperature,
+		Humidity:    req.Humidity,
+		DewPoint:    req.DewPoint,
+		WindSpeed:   req.WindSpeed,
+		WindGust:    req.WindGust,
+		WindDir:     req.WindDir,
+		Pressure:    req.Pressure,
+		Precip1h:    req.Precip1h,
+	}, nil
+}
+
+// parseEcowittObservation reads the imperial form fields an Ecowitt gateway
+// posts in "customized" upload mode and converts them to metric.
+func parseEcowittObservation(r *http.Request) (weather.CustomObservation, error) {
+	if err := r.ParseForm(); err != nil {
+		return weather.CustomObservation{}, errors.New("invalid form body")
+	}
+	form := r.PostForm
+
+	var (
+		obs weather.CustomObservation
+		err error
+	)
+	if ts := form.Get("dateutc"); ts != "" && ts != "now" {
+		observedAt, err := time.Parse("2006-01-02 15:04:05", ts)
+		if err != nil {
+			return weather.CustomObservation{}, errors.New("invalid dateutc")
+		}
+		obs.ObservedAt = observedAt.UTC()
+	}
+
+	if obs.Temperature, err = formFloat(form, "tempf", fahrenheitToCelsius); err != nil {
+		return weather.CustomObservation{}, err
+	}
+	if obs.Humidity, err = formFloat(form, "humidity", nil); err != nil {
+		return weather.CustomObservation{}, err
+	}
+	if obs.WindSpeed, err = formFloat(form, "windspeedmph", mphToMS); err != nil {
+		return weather.CustomObservation{}, err
+	}
+	if obs.WindGust, err = formFloat(form, "windgustmph", mphToMS); err != nil {
+		return weather.CustomObservation{}, err
+	}
+	if obs.WindDir, err = formFloat(form, "winddir", nil); err != nil {
+		return weather.CustomObservation{}, err
+	}
+	if obs.Pressure, err = formFloat(form, "baromrelin", inHgToHPa); err != nil {
+		return weather.CustomObservation{}, err
+	}
+	if obs.Precip1h, err = formFloat(form, "hourlyrainin", inchesToMM); err != nil {
+		return weather.CustomObservation{}, err
+	}
+	return obs, nil
+}
+
+type weatherFlowObservationJSON struct {
+	Type string       `json:"type"`
+	Obs  [][]*float64 `json:"obs"`
+}
+
+// parseWeatherFlowObservation reads a Tempest obs_st message. Only the first
+// observation row is used; its field order is fixed by the WeatherFlow UDP
+// and websocket API. The station reports a missing sensor as null, which
+// stays nil here rather than becoming a zero reading.
+func parseWeatherFlowObservation(r *http.Request) (weather.CustomObservation, error) {
+	var msg weatherFlowObservationJSON
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		return weather.CustomObservation{}, errors.New("invalid JSON body")
+	}
+	if msg.Type != "obs_st" {
+		return weather.CustomObservation{}, errors.New("unsupported WeatherFlow message type")
+	}
+	if len(msg.Obs) == 0 || len(msg.Obs[0]) < 9 {
+		return weather.CustomObservation{}, errors.New("missing WeatherFlow observation")
+	}
+
+	row := msg.Obs[0]
+	if row[0] == nil || *row[0] <= 0 {
+		return weather.CustomObservation{}, errors.New("missing WeatherFlow epoch")
+	}
+	value := func(i int) *float64 {
+		if row[i] == nil {
+			return nil
+		}
+		v := *row[i]
+		return &v
+	}
+	return weather.CustomObservation{
+		ObservedAt:  time.Unix(int64(*row[0]), 0).UTC(),
+		WindSpeed:   value(2),
+		WindGust:    value(3),
+		WindDir:     value(4),
+		Pressure:    value(6),
+		Temperature: value(7),
+		Humidity:    value(8),
+	}, nil
+}
+
+func validateCustomObservation(obs weather.CustomObservation) error {
+	if err := validateCustomStation(obs.StationID, obs.Lat, obs.Lon); err != nil {
+		return err
+	}
+	if obs.ObservedAt.After(time.Now().Add(5 * time.Minute)) {
+		return errors.New("observed_at is in the future")
+	}
+	return nil
+}
+
+func validateCustomStation(stationID string, lat, lon float64) error {
+	if strings.TrimSpace(stationID) == "" {
+		return errors.New("station_id is required")
+	}
+	if len(stationID) > 64 {
+		return errors.New("station_id is too long")
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return errors.New("lat/lon out of range")
+	}
+	return nil
+}
+
+func formFloat(form url.Values, key string, convert func(float64) float64) (*float64, error) {
+	raw := form.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	if convert != nil {
+		v = convert(v)
+	}
+	return &v, nil
+}
+
+func fahrenheitToCelsius(f float64) float64 { return (f - 32) * 5 / 9 }
+func mphToMS(mph float64) float64           { return mph * 0.44704 }
+func inHgToHPa(inHg float64) float64        { return inHg * 33.8639 }
+func inchesToMM(in float64) float64         { return in * 25.4 }
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestParseEcowittObservation_ConvertsToMetric(t *testing.T) {
+	body := "PASSKEY=ABC123&dateutc=2026-04-18+10%3A00%3A00&tempf=50.0&humidity=70&windspeedmph=10&winddir=180&baromrelin=29.92&hourlyrainin=0.1"
+	req := httptest.NewRequest(http.MethodPost, "/ingest/token", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	obs, err := parseEcowittObservation(req)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !obs.ObservedAt.Equal(time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected observed_at %v", obs.ObservedAt)
+	}
+	assertNear(t, "temperature", obs.Temperature, 10.0)
+	assertNear(t, "wind speed", obs.WindSpeed, 4.4704)
+	assertNear(t, "pressure", obs.Pressure, 1013.21)
+	assertNear(t, "precip", obs.Precip1h, 2.54)
+	if obs.WindGust != nil {
+		t.Fatalf("expected missing gust to stay nil")
+	}
+}
+
+func TestParseWeatherFlowObservation_ReadsObsStRow(t *testing.T) {
+	body := `{"serial_number":"ST-00000512","type":"obs_st","obs":[[1776506400,0.1,2.5,4.1,270,3,1008.2,12.5,81,1000,0.5,120,0,0,0,0,2.4,1]]}`
+	req := httptest.NewRequest(http.MethodPost, "/ingest/token", strings.NewReader(body))
+
+	obs, err := parseWeatherFlowObservation(req)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if obs.ObservedAt.Unix() != 1776506400 {
+		t.Fatalf("unexpected observed_at %v", obs.ObservedAt)
+	}
+	assertNear(t, "wind speed", obs.WindSpeed, 2.5)
+	assertNear(t, "wind dir", obs.WindDir, 270)
+	assertNear(t, "pressure", obs.Pressure, 1008.2)
+	assertNear(t, "temperature", obs.Temperature, 12.5)
+	assertNear(t, "humidity", obs.Humidity, 81)
+}
+
+func TestParseEcowittObservation_RejectsNonFiniteValues(t *testing.T) {
+	for _, raw := range []string{"NaN", "Inf", "-Inf"} {
+		body := "dateutc=2026-04-18+10%3A00%3A00&tempf=" + raw
+		req := httptest.NewRequest(http.MethodPost, "/ingest/token", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		if _, err := parseEcowittObservation(req); err == nil || err.Error() != "invalid tempf" {
+			t.Fatalf("tempf=%s: expected invalid tempf, got %v", raw, err)
+		}
+	}
+}
+
+func TestParseWeatherFlowObservation_KeepsNullSensorsMissing(t *testing.T) {
+	body := `{"type":"obs_st","obs":[[1776506400,0.1,null,null,270,3,null,null,81]]}`
+	req := httptest.NewRequest(http.MethodPost, "/ingest/token", strings.NewReader(body))
+
+	obs, err := parseWeatherFlowObservation(req)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if obs.WindSpeed != nil || obs.WindGust != nil || obs.Pressure != nil || obs.Temperature != nil {
+		t.Fatalf("expected null sensors to stay nil, got %+v", obs)
+	}
+	assertNear(t, "humidity", obs.Humidity, 81)
+}
+
+func TestParseWeatherFlowObservation_RejectsMissingEpoch(t *testing.T) {
+	for _, epoch := range []string{"null", "0"} {
+		body := `{"type":"obs_st","obs":[[` + epoch + `,0.1,2.5,4.1,270,3,1008.2,12.5,81]]}`
+		req := httptest.NewRequest(http.MethodPost, "/ingest/token", strings.NewReader(body))
+
+		if _, err := parseWeatherFlowObservation(req); err == nil {
+			t.Fatalf("epoch %s: expected error", epoch)
+		}
+	}
+}
+
+func TestPostCustomObservation_RequiresClientID(t *testing.T) {
+	h := NewHandler(weatherServiceStub{})
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/observations/custom", strings.NewReader(`{}`))
+	h.postCustomObservation(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", rr.Code)
+	}
+}
+
+func TestPostCustomObservation_RejectsDeviceFormats(t *testing.T) {
+	h := NewHandler(weatherServiceStub{})
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/observations/custom?format=ecowitt", strings.NewReader("tempf=50"))
+	h.postCustomObservation(rr, req.WithContext(context.WithValue(req.Context(), clientIDContextKey, "ios-app")))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+}
+
+type stationIngestStub struct {
+	weatherServiceStub
+	station  *weather.CustomStation
+	ingested *weather.CustomObservation
+}
+
+func (s stationIngestStub) CustomStationForToken(ctx context.Context, token string) (*weather.CustomStation, error) {
+	if s.station == nil || token != "good-token" {
+		return nil, weather.ErrUnknownStationToken
+	}
+	return s.station, nil
+}
+
+func (s stationIngestStub) IngestCustomObservation(ctx context.Context, obs weather.CustomObservation) error {
+	*s.ingested = obs
+	return nil
+}
+
+func TestPostStationIngest_UsesRegistration(t *testing.T) {
+	var ingested weather.CustomObservation
+	h := NewHandler(stationIngestStub{
+		station:  &weather.CustomStation{ClientID: "ios-app", StationID: "garden", Format: "ecowitt", Lat: 60.1, Lon: 24.9},
+		ingested: &ingested,
+	})
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	body := "PASSKEY=ABC123&stationtype=GW1000&dateutc=now&tempf=50.0"
+	req := httptest.NewRequest(http.MethodPost, "/ingest/good-token", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ingested.ClientID != "ios-app" || ingested.StationID != "garden" || ingested.Source != "ecowitt" || ingested.Lat != 60.1 {
+		t.Errorf("unexpected ingested observation %+v", ingested)
+	}
+	assertNear(t, "temperature", ingested.Temperature, 10.0)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/ingest/bad-token", strings.NewReader(body)))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401 for an unknown token, got %d", rr.Code)
+	}
+}
+
+func assertNear(t *testing.T, name string, got *float64, want float64) {
+	t.Helper()
+	if got == nil {
+		t.Fatalf("%s: expected %.2f, got nil", name, want)
+	}
+	if math.Abs(*got-want) > 0.01 {
+		t.Fatalf("%s: expected %.2f, got %.4f", name, want, *got)
+	}
+}
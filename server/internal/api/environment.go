@@ -0,0 +1,57 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"wby/internal/weather"
+)
+
+type environmentJSON struct {
+	Warnings   environmentSectionJSON `json:"warnings"`
+	AirQuality environmentSectionJSON `json:"air_quality"`
+	Pollen     environmentSectionJSON `json:"pollen"`
+	UVMax      environmentSectionJSON `json:"uv_max"`
+	FireIndex  environmentSectionJSON `json:"fire_index"`
+}
+
+type environmentSectionJSON struct {
+	Available bool       `json:"available"`
+	Stale     bool       `json:"stale"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	Value     *float64   `json:"value"`
+	Level     string     `json:"level,omitempty"`
+	Summary   string     `json:"summary,omitempty"`
+}
+
+func toEnvironmentJSON(env *weather.Environment) *environmentJSON {
+	section := func(name string) environmentSectionJSON {
+		s := env.Sections[name]
+		return environmentSectionJSON{
+			Available: s.Available,
+			Stale:     s.Stale,
+			UpdatedAt: s.UpdatedAt,
+			Value:     s.Value,
+			Level:     s.Level,
+			Summary:   s.Summary,
+		}
+	}
+	return &environmentJSON{
+		Warnings:   section(weather.EnvironmentWarnings),
+		AirQuality: section(weather.EnvironmentAirQuality),
+		Pollen:     section(weather.EnvironmentPollen),
+		UVMax:      section(weather.EnvironmentUVMax),
+		FireIndex:  section(weather.EnvironmentFireIndex),
+	}
+}
+
+// includes reports whether the comma-separated include parameter names the
+// given optional section.
+func includes(raw, section string) bool {
+	for _, part := range strings.Split(raw, ",") {
+		if strings.TrimSpace(part) == section {
+			return true
+		}
+	}
+	return false
+}
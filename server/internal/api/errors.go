@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+// Error codes carried in every error body. Clients branch on these, so an
+// existing code is never renamed or reused for another condition.
+const (
+	codeInvalidRequest      = "invalid_request"
+	codeInvalidCoordinates  = "invalid_coordinates"
+	codeOutsideCoverage     = "outside_coverage"
+	codeUnknownPlace        = "unknown_place"
+	codeNotFound            = "not_found"
+	codeConflict            = "conflict"
+	codeWarmingUp           = "warming_up"
+	codeUpstreamUnavailable = "upstream_unavailable"
+	codeUpstreamLimited     = "upstream_rate_limited"
+	codeUpstreamRejected    = "upstream_rejected"
+	codeUpstreamMalformed   = "upstream_malformed"
+	codeUnavailable         = "unavailable"
+	codeUnauthorized        = "unauthorized"
+	codeRateLimited         = "rate_limited"
+	codeInternal            = "internal"
+)
+
+// errorJSON is the body of every error response. Code, Message and
+// RequestID sit at the top level rather than in a nested "error" object,
+// because Error has to stay a string for clients written against the old
+// {"error": "..."} body. Error repeats Message and will be dropped in the
+// next release; the other fields stay where they are.
+type errorJSON struct {
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// newErrorJSON builds an error body, picking up the request ID that
+// NewRequestIDMiddleware has already set on the response.
+func newErrorJSON(w http.ResponseWriter, code, msg string) errorJSON {
+	return errorJSON{Error: msg, Code: code, Message: msg, RequestID: w.Header().Get(requestIDHeader)}
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	writeErrorBody(w, status, newErrorJSON(w, code, msg))
+}
+
+func writeErrorBody(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// coordinateError is a missing, malformed or out-of-range lat/lon, reported
+// as invalid_coordinates rather than a generic invalid_request.
+type coordinateError string
+
+func (e coordinateError) Error() string { return string(e) }
+
+// writeBadRequest reports a request validation error as a 400.
+func writeBadRequest(w http.ResponseWriter, err error) {
+	code := codeInvalidRequest
+	var coordErr coordinateError
+	if errors.As(err, &coordErr) {
+		code = codeInvalidCoordinates
+	}
+	writeError(w, http.StatusBadRequest, code, err.Error())
+}
+
+// writeServiceError maps the weather service's sentinel errors to a status
+// and code. Anything else is logged as msg with args and reported as a 500.
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error, msg string, args ...any) {
+	switch {
+	case errors.Is(err, weather.ErrOutOfCoverage):
+		writeError(w, http.StatusNotFound, codeOutsideCoverage, "no weather coverage for this location")
+	case errors.Is(err, weather.ErrNoStations):
+		writeWarmingUp(w)
+	case errors.Is(err, weather.ErrUpstreamRateLimited):
+		logging.FromContext(r.Context()).Warn(msg, append([]any{"err", err}, args...)...)
+		w.Header().Set("Retry-After", upstreamRetryAfter(err))
+		writeError(w, http.StatusServiceUnavailable, codeUpstreamLimited, "upstream weather service rate limited")
+	case errors.Is(err, weather.ErrUpstreamRejected):
+		// A rejected query is a bug here, not an outage; the details say
+		// which query and why.
+		logging.FromContext(r.Context()).Error(msg, append([]any{"err", err}, args...)...)
+		writeError(w, http.StatusBadGateway, codeUpstreamRejected, err.Error())
+	case errors.Is(err, weather.ErrUpstreamMalformed):
+		logging.FromContext(r.Context()).Error(msg, append([]any{"err", err}, args...)...)
+		writeError(w, http.StatusBadGateway, codeUpstreamMalformed, "upstream weather service sent a malformed response")
+	case errors.Is(err, weather.ErrUpstreamUnavailable):
+		logging.FromContext(r.Context()).Warn(msg, append([]any{"err", err}, args...)...)
+		writeError(w, http.StatusServiceUnavailable, codeUpstreamUnavailable, "upstream weather service unavailable")
+	default:
+		logging.FromContext(r.Context()).Error(msg, append([]any{"err", err}, args...)...)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+	}
+}
+
+// warmingUpRetryAfter is the Retry-After, in seconds, sent while warming
+// up; the fetcher's first run starts at boot and takes well under that.
+const warmingUpRetryAfter = "60"
+
+// upstreamRetryAfter is the Retry-After, in seconds, for a rate limited
+// upstream: until the service's backoff ends, or a minute when unknown.
+func upstreamRetryAfter(err error) string {
+	var limited *weather.RateLimitedError
+	if errors.As(err, &limited) {
+		if secs := int(math.Ceil(time.Until(limited.Until).Seconds())); secs > 0 {
+			return strconv.Itoa(secs)
+		}
+	}
+	return "60"
+}
+
+// writeWarmingUp tells the client that the server has no station data yet,
+// so it can retry rather than treat the response as a server failure.
+func writeWarmingUp(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", warmingUpRetryAfter)
+	writeError(w, http.StatusServiceUnavailable, codeWarmingUp, weather.ErrNoStations.Error())
+}
+
+// queryLatLon parses and range-checks the lat and lon query parameters.
+func queryLatLon(q url.Values) (float64, float64, error) {
+	lat, err := strconv.ParseFloat(q.Get("lat"), 64)
+	if err != nil || math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return 0, 0, coordinateError("invalid lat parameter")
+	}
+	lon, err := strconv.ParseFloat(q.Get("lon"), 64)
+	if err != nil || math.IsNaN(lon) || lon < -180 || lon > 180 {
+		return 0, 0, coordinateError("invalid lon parameter")
+	}
+	return lat, lon, nil
+}
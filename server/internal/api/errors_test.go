@@ -0,0 +1,111 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestGetWeather_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"malformed lat", "lat=abc&lon=24.94", nil, http.StatusBadRequest, codeInvalidCoordinates},
+		{"lat out of range", "lat=91&lon=24.94", nil, http.StatusBadRequest, codeInvalidCoordinates},
+		{"nan lon", "lat=60.17&lon=NaN", nil, http.StatusBadRequest, codeInvalidCoordinates},
+		{"bad units", "lat=60.17&lon=24.94&units=kelvin", nil, http.StatusBadRequest, codeInvalidRequest},
+		{"outside coverage", "lat=40&lon=24.94", weather.ErrOutOfCoverage, http.StatusNotFound, codeOutsideCoverage},
+		{"warming up", "lat=60.17&lon=24.94", fmt.Errorf("nearest station: %w", weather.ErrNoStations), http.StatusServiceUnavailable, codeWarmingUp},
+		{"upstream", "lat=60.17&lon=24.94", fmt.Errorf("forecast: %w", weather.ErrUpstreamUnavailable), http.StatusServiceUnavailable, codeUpstreamUnavailable},
+		{"upstream rate limited", "lat=60.17&lon=24.94", &weather.RateLimitedError{Until: time.Now().Add(time.Minute), Err: weather.ErrUpstreamRateLimited}, http.StatusServiceUnavailable, codeUpstreamLimited},
+		{"upstream rejected", "lat=60.17&lon=24.94", fmt.Errorf("fetch forecast: %w", weather.ErrUpstreamRejected), http.StatusBadGateway, codeUpstreamRejected},
+		{"upstream malformed", "lat=60.17&lon=24.94", fmt.Errorf("%w: %w", weather.ErrUpstreamUnavailable, weather.ErrUpstreamMalformed), http.StatusBadGateway, codeUpstreamMalformed},
+		{"internal", "lat=60.17&lon=24.94", errors.New("boom"), http.StatusInternalServerError, codeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(weatherServiceStub{err: tt.err})
+			handler := NewRequestIDMiddleware()(http.HandlerFunc(h.getWeather))
+			req := httptest.NewRequest(http.MethodGet, "/v1/weather?"+tt.query, nil)
+			req.Header.Set(requestIDHeader, "req-123")
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, rr.Code)
+			}
+			var body errorJSON
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Code != tt.wantCode || body.RequestID != "req-123" {
+				t.Fatalf("unexpected body %+v", body)
+			}
+			if body.Message == "" || body.Error != body.Message {
+				t.Fatalf("expected the legacy error string to repeat the message, got %+v", body)
+			}
+		})
+	}
+}
+
+type noNormalsStub struct {
+	weatherServiceStub
+}
+
+func (noNormalsStub) GetClimateNormals(ctx context.Context, lat, lon float64, currentTemp *float64) (*weather.Station, float64, []weather.ClimateNormal, weather.InterpolatedNormal, error) {
+	return nil, 0, nil, weather.InterpolatedNormal{}, nil
+}
+
+func TestGetClimateNormals_NotFoundUsesErrorBody(t *testing.T) {
+	h := NewHandler(noNormalsStub{})
+	handler := NewRequestIDMiddleware()(http.HandlerFunc(h.getClimateNormals))
+	req := httptest.NewRequest(http.MethodGet, "/v1/climate-normals?lat=60.17&lon=24.94", nil)
+	req.Header.Set(requestIDHeader, "req-123")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rr.Code)
+	}
+	var body errorJSON
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Code != codeNotFound || body.Message == "" || body.RequestID != "req-123" {
+		t.Fatalf("unexpected body %+v", body)
+	}
+}
+
+func TestWriteServiceError_UpstreamDetails(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/weather", nil)
+
+	rr := httptest.NewRecorder()
+	limited := &weather.RateLimitedError{Until: time.Now().Add(90 * time.Second), Err: weather.ErrUpstreamRateLimited}
+	writeServiceError(rr, req, fmt.Errorf("%w: %w", weather.ErrUpstreamUnavailable, limited), "forecast failed")
+	if got := rr.Header().Get("Retry-After"); got != "90" {
+		t.Errorf("expected Retry-After 90 for the backoff, got %q", got)
+	}
+
+	rr = httptest.NewRecorder()
+	rejected := fmt.Errorf("fetch forecast: FMI returned 400 InvalidParameterValue: bad latlon: %w", weather.ErrUpstreamRejected)
+	writeServiceError(rr, req, rejected, "forecast failed")
+	var body errorJSON
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body.Message, "InvalidParameterValue: bad latlon") {
+		t.Errorf("expected the rejection details in the message, got %q", body.Message)
+	}
+}
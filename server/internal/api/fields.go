@@ -0,0 +1,176 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// fieldSet is a parsed ?fields= selection: a tree of JSON keys in which a
+// nil subtree selects the whole value.
+type fieldSet map[string]fieldSet
+
+// fieldAliases are short names accepted for top-level response keys.
+var fieldAliases = map[string]string{
+	"hourly": "hourly_forecast",
+	"daily":  "daily_forecast",
+}
+
+// alwaysIncludedFields stay in every selected object so entries can still
+// be placed in time.
+var alwaysIncludedFields = map[string]bool{"observed_at": true, "date": true, "time": true}
+
+// parseFields parses a comma-separated list of dotted JSON paths such as
+// current.temperature,daily.high. It returns nil, meaning everything, for
+// an empty list.
+func parseFields(raw string) fieldSet {
+	var fs fieldSet
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if fs == nil {
+			fs = fieldSet{}
+		}
+		node := fs
+		keys := strings.Split(path, ".")
+		if alias, ok := fieldAliases[keys[0]]; ok {
+			keys[0] = alias
+		}
+		for i, key := range keys {
+			sub, seen := node[key]
+			if i == len(keys)-1 {
+				node[key] = nil // selecting a key selects all of it
+				break
+			}
+			if seen && sub == nil {
+				break // already fully selected
+			}
+			if sub == nil {
+				sub = fieldSet{}
+				node[key] = sub
+			}
+			node = sub
+		}
+	}
+	return fs
+}
+
+// sparse restricts v, a response struct, to the fields in fs and, with
+// dropNulls, leaves out nil pointer fields as if they were all omitempty.
+// Keys keep the struct's order and omitempty is honored, so a full
+// selection marshals exactly like v. Unknown keys select nothing.
+func sparse(v any, fs fieldSet, dropNulls bool) any {
+	if fs == nil && !dropNulls {
+		return v
+	}
+	return fs.filter(reflect.ValueOf(v), dropNulls)
+}
+
+var jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+
+func (fs fieldSet) filter(v reflect.Value, dropNulls bool) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if fs == nil && !dropNulls || v.Type().Implements(jsonMarshalerType) || reflect.PointerTo(v.Type()).Implements(jsonMarshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		var out orderedFields
+		fs.filterStruct(v, dropNulls, &out)
+		return out
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = fs.filter(v.Index(i), dropNulls)
+		}
+		return items
+	}
+	return v.Interface()
+}
+
+func (fs fieldSet) filterStruct(v reflect.Value, dropNulls bool, out *orderedFields) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			fs.filterStruct(v.Field(i), dropNulls, out)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		sub, selected := fs[name]
+		if fs != nil && !selected && !alwaysIncludedFields[name] {
+			continue
+		}
+		fv := v.Field(i)
+		if strings.Contains(opts, "omitempty") && isEmptyJSONValue(fv) {
+			continue
+		}
+		if dropNulls && (fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface) && fv.IsNil() {
+			continue
+		}
+		*out = append(*out, orderedField{key: name, value: sub.filter(fv, dropNulls)})
+	}
+}
+
+// isEmptyJSONValue reports whether encoding/json's omitempty drops v.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+type orderedField struct {
+	key   string
+	value any
+}
+
+// orderedFields marshals as a JSON object with keys in slice order.
+type orderedFields []orderedField
+
+func (o orderedFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
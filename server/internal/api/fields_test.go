@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestSparse_FullSelectionMatchesJSON(t *testing.T) {
+	temp := 1.5
+	resp := &weatherJSON{
+		Units:   unitsMetric,
+		Station: stationJSON{Name: "Helsinki <Kaisaniemi>"},
+		Current: currentJSON{Temperature: &temp, ObservedAt: time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)},
+		Hourly:  []hourlyForecastJSON{{Temperature: &temp}},
+	}
+	fs := parseFields("units,station,current,hourly,daily,timezone,fog_advisory,synoptic_summary,alerts,custom_station,home_sensors,environment,air_quality,marine,road,place,meta")
+
+	want, _ := json.Marshal(resp)
+	got, err := json.Marshal(sparse(resp, fs, false))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	if parseFields(" , ") != nil {
+		t.Fatal("expected an empty list to select everything")
+	}
+	fs := parseFields("current.temperature,daily.high,current,hourly.symbol")
+	if sub, ok := fs["current"]; !ok || sub != nil {
+		t.Errorf("expected current to be fully selected, got %v", fs["current"])
+	}
+	if _, ok := fs["daily_forecast"]["high"]; !ok {
+		t.Errorf("expected daily alias to select daily_forecast.high, got %v", fs)
+	}
+	if _, ok := fs["hourly_forecast"]["symbol"]; !ok {
+		t.Errorf("expected hourly alias to select hourly_forecast.symbol, got %v", fs)
+	}
+}
+
+func TestGetWeather_FieldsFilterResponse(t *testing.T) {
+	temp, high, low := -2.0, 3.0, -4.0
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{
+			Current: weather.CurrentWeather{
+				Observation: weather.Observation{ObservedAt: time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC), Temperature: &temp},
+			},
+			Forecast: []weather.DailyForecast{{Date: time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC), TempHigh: &high, TempLow: &low}},
+		},
+	})
+
+	get := func(query, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9&"+query, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		h.getWeather(rr, req)
+		return rr
+	}
+
+	rr := get("fields=current.temperature,daily.high,bogus,current.bogus", "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	want := `{"current":{"temperature":-2,"observed_at":"2026-04-18T10:00:00Z"},"daily_forecast":[{"date":"2026-04-18","high":3}]}`
+	if got := strings.TrimSpace(rr.Body.String()); got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+
+	etag := rr.Header().Get("ETag")
+	if full := get("", ""); full.Header().Get("ETag") == etag {
+		t.Fatal("expected the full response to have a different ETag")
+	}
+	if rr := get("fields=current.temperature,daily.high,bogus,current.bogus", etag); rr.Code != http.StatusNotModified {
+		t.Fatalf("expected status 304 for matching ETag, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type forecastJSON struct {
+	Units           string               `json:"units"`
+	Hourly          []hourlyForecastJSON `json:"hourly_forecast"`
+	Forecast        []dailyForecastJSON  `json:"daily_forecast"`
+	Timezone        string               `json:"timezone"`
+	SynopticSummary string               `json:"synoptic_summary,omitempty"`
+}
+
+func (h *Handler) getForecast(w http.ResponseWriter, r *http.Request) {
+	lat, lon, err := queryLatLon(r.URL.Query())
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	units, err := parseUnits(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	lang, err := parseLang(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	result, err := h.service.GetForecast(r.Context(), lat, lon, parseHours(r), parseDays(r))
+	if err != nil {
+		writeServiceError(w, r, err, "get forecast failed", "lat", lat, "lon", lon)
+		return
+	}
+
+	resp := forecastJSON{
+		Hourly:          toHourlyForecastJSON(result.Hourly),
+		Forecast:        toDailyForecastJSON(result.Forecast),
+		Timezone:        result.Timezone,
+		SynopticSummary: result.SynopticSummary,
+	}
+	if r.URL.Query().Get("moon") == "false" {
+		dropMoon(resp.Forecast)
+	}
+	resp.applyUnits(units)
+	describeSymbols(resp.Forecast, resp.Hourly, lang)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(resp)
+}
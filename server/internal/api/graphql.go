@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"time"
+
+	"wby/internal/graphql"
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+const maxGraphQLBodyBytes = 64 << 10
+
+var errInternal = errors.New("internal server error")
+
+// maxGraphQLLookups is how many weather or station fields one GraphQL
+// query may select, each under its own alias; every one can cost FMI
+// fetches or a database query.
+const maxGraphQLLookups = 5
+
+// newGraphQLSchema exposes the REST responses as GraphQL types, so clients
+// can select only the fields they need. Resolvers share the REST code
+// paths and therefore the service caches. Object type names are the JSON
+// type names without the suffix, e.g. dailyForecastJSON is DailyForecast.
+func (h *Handler) newGraphQLSchema() *graphql.Schema {
+	typeName := func(t reflect.Type) string { return componentName(t.Name()) }
+	return graphql.NewSchema(typeName,
+		graphql.Field{
+			Name:        "weather",
+			Description: "Current conditions at the nearest station with hourly and daily forecasts, as GET /v1/weather.",
+			Args: []graphql.Arg{
+				{Name: "lat", Type: "Float!", Description: "Latitude in decimal degrees."},
+				{Name: "lon", Type: "Float!", Description: "Longitude in decimal degrees."},
+				{Name: "hours", Type: "Int", Description: "Number of hourly forecast entries."},
+				{Name: "days", Type: "Int", Description: "Number of daily forecast entries, 1-15."},
+				{Name: "units", Type: "String", Description: "metric (default) or imperial."},
+				{Name: "lang", Type: "String", Description: "fi, sv or en; adds symbol_description to forecast entries."},
+				{Name: "blend_custom", Type: "Boolean", Description: "Blend the signing client's nearby personal weather station into current conditions."},
+				{Name: "include_environment", Type: "Boolean", Description: "Add the environment section."},
+			},
+			Type:        reflect.TypeFor[*weatherJSON](),
+			Resolve:     h.resolveWeather,
+			MaxPerQuery: maxGraphQLLookups,
+		},
+		graphql.Field{
+			Name:        "station",
+			Description: "One station and its raw observations, as GET /v1/stations/{fmisid}/observations.",
+			Args: []graphql.Arg{
+				{Name: "fmisid", Type: "Int!", Description: "FMI station ID."},
+				{Name: "from", Type: "String", Description: "RFC3339 start; defaults to 24 hours before to."},
+				{Name: "to", Type: "String", Description: "RFC3339 end; defaults to now."},
+			},
+			Type:        reflect.TypeFor[*stationObservationsJSON](),
+			Resolve:     h.resolveStation,
+			MaxPerQuery: maxGraphQLLookups,
+		},
+		graphql.Field{
+			Name:        "stations",
+			Description: "Known observation stations, as GET /v1/stations.",
+			Args: []graphql.Arg{
+				{Name: "bbox", Type: "String", Description: "Bounding box as minLon,minLat,maxLon,maxLat."},
+			},
+			Type:    reflect.TypeFor[[]stationListJSON](),
+			Resolve: h.resolveStations,
+		},
+	)
+}
+
+func (h *Handler) resolveWeather(ctx context.Context, args map[string]any) (any, error) {
+	q := weatherQuery{
+		lat:   args["lat"].(float64),
+		lon:   args["lon"].(float64),
+		units: unitsMetric,
+	}
+	q.hours, _ = args["hours"].(int)
+	q.days, _ = args["days"].(int)
+	if units, ok := args["units"].(string); ok {
+		if units != unitsMetric && units != unitsImperial {
+			return nil, errors.New("invalid units argument")
+		}
+		q.units = units
+	}
+	if lang, ok := args["lang"].(string); ok {
+		if !weather.ValidLang(lang) {
+			return nil, errors.New("invalid lang argument")
+		}
+		q.lang = lang
+	}
+	q.blendCustom, _ = args["blend_custom"].(bool)
+	q.includeEnvironment, _ = args["include_environment"].(bool)
+
+	resp, err := h.buildWeather(ctx, q)
+	if err != nil {
+		if errors.Is(err, weather.ErrOutOfCoverage) {
+			return nil, errors.New("no weather coverage for this location")
+		}
+		logging.FromContext(ctx).Error("graphql weather failed", "err", err, "lat", q.lat, "lon", q.lon)
+		return nil, errInternal
+	}
+	return resp, nil
+}
+
+func (h *Handler) resolveStation(ctx context.Context, args map[string]any) (any, error) {
+	fmisid := args["fmisid"].(int)
+	rawFrom, _ := args["from"].(string)
+	rawTo, _ := args["to"].(string)
+	from, to, err := observationWindow(rawFrom, rawTo, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	station, observations, err := h.service.GetStationObservations(ctx, fmisid, from, to)
+	if err != nil {
+		if errors.Is(err, weather.ErrStationNotFound) {
+			return nil, errors.New("station not found")
+		}
+		logging.FromContext(ctx).Error("graphql station failed", "err", err, "fmisid", fmisid)
+		return nil, errInternal
+	}
+	resp := toStationObservationsJSON(*station, from, to, observations)
+	return &resp, nil
+}
+
+func (h *Handler) resolveStations(ctx context.Context, args map[string]any) (any, error) {
+	var bbox *weather.BBox
+	if raw, ok := args["bbox"].(string); ok && raw != "" {
+		parsed, err := parseBBox(raw)
+		if err != nil {
+			return nil, err
+		}
+		bbox = &parsed
+	}
+
+	stations, err := h.service.ListStations(ctx, bbox)
+	if err != nil {
+		logging.FromContext(ctx).Error("graphql stations failed", "err", err)
+		return nil, errInternal
+	}
+	resp := make([]stationListJSON, len(stations))
+	for i, st := range stations {
+		resp[i] = toStationListJSON(st)
+	}
+	return resp, nil
+}
+
+// postGraphQL serves queries sent as {"query", "variables",
+// "operationName"} JSON; getGraphQL serves the same as URL parameters.
+// Field errors are reported in the errors array with status 200.
+func (h *Handler) postGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBodyBytes)).Decode(&req); err != nil {
+		writeGraphQL(w, http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: "invalid request body"}}})
+		return
+	}
+	h.serveGraphQL(w, r, req)
+}
+
+func (h *Handler) getGraphQL(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := graphql.Request{Query: q.Get("query"), OperationName: q.Get("operationName")}
+	if raw := q.Get("variables"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+			writeGraphQL(w, http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: "invalid variables parameter"}}})
+			return
+		}
+	}
+	h.serveGraphQL(w, r, req)
+}
+
+func (h *Handler) serveGraphQL(w http.ResponseWriter, r *http.Request, req graphql.Request) {
+	if req.Query == "" {
+		writeGraphQL(w, http.StatusBadRequest, graphql.Response{Errors: []*graphql.Error{{Message: "query is required"}}})
+		return
+	}
+	resp := h.graphql.Execute(r.Context(), req)
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	writeGraphQL(w, status, resp)
+}
+
+func writeGraphQL(w http.ResponseWriter, status int, resp graphql.Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestGraphQL_WeatherReturnsOnlySelectedFields(t *testing.T) {
+	temp := 4.5
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{
+			Current: weather.CurrentWeather{
+				Station:     weather.Station{Name: "Helsinki Kaisaniemi"},
+				Observation: weather.Observation{ObservedAt: time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC), Temperature: &temp},
+			},
+			Timezone: "Europe/Helsinki",
+		},
+	})
+
+	body := `{"query":"query($lat: Float!) { weather(lat: $lat, lon: 24.9) { timezone station { name } current { temperature } } }","variables":{"lat":60.1}}`
+	rr := httptest.NewRecorder()
+	h.postGraphQL(rr, httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(body)))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	want := `{"data":{"weather":{"timezone":"Europe/Helsinki","station":{"name":"Helsinki Kaisaniemi"},"current":{"temperature":4.5}}}}`
+	if got := strings.TrimSpace(rr.Body.String()); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestGraphQL_ServiceErrorIsGraphQLError(t *testing.T) {
+	h := NewHandler(weatherServiceStub{err: errors.New("fmi unavailable")})
+
+	q := url.Values{"query": {`{ weather(lat: 60.1, lon: 24.9) { timezone } }`}}
+	rr := httptest.NewRecorder()
+	h.getGraphQL(rr, httptest.NewRequest(http.MethodGet, "/v1/graphql?"+q.Encode(), nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp struct {
+		Data   map[string]any `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+			Path    []any  `json:"path"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if v, ok := resp.Data["weather"]; !ok || v != nil {
+		t.Fatalf("expected data.weather to be null, got %v", resp.Data)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != "internal server error" || resp.Errors[0].Path[0] != "weather" {
+		t.Fatalf("unexpected errors: %+v", resp.Errors)
+	}
+}
+
+func TestGraphQL_InvalidQueryIsBadRequest(t *testing.T) {
+	h := NewHandler(weatherServiceStub{})
+
+	rr := httptest.NewRecorder()
+	h.postGraphQL(rr, httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(`{"query":"{ weather { timezone } }"}`)))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"errors"`) {
+		t.Fatalf("expected errors in body, got %s", rr.Body.String())
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// gzipMinSize is the smallest body worth compressing; below it the gzip
+// framing costs more than it saves.
+const gzipMinSize = 1024
+
+var gzipWriterPool = sync.Pool{
+	New: func() any { return gzip.NewWriter(nil) },
+}
+
+// NewGzipMiddleware compresses responses of at least minSize bytes for
+// clients that accept gzip. It only looks at the response, so it can wrap
+// the signature middleware without affecting verification. Images and
+// already encoded bodies pass through unchanged, as do the health checks
+// and WebSocket upgrades.
+func NewGzipMiddleware(minSize int) func(http.Handler) http.Handler {
+	if minSize <= 0 {
+		minSize = gzipMinSize
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/health") || r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r.Header.Get("Accept-Encoding")) || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize}
+			defer gw.finish()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.TrimSpace(coding) != "gzip" {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of the body until it knows whether
+// the response is large enough to compress, and holds back the status code
+// until then so Content-Encoding can still be set.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize     int
+	status      int
+	buf         []byte
+	gz          *gzip.Writer
+	passthrough bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(p)
+	case w.passthrough:
+		return w.ResponseWriter.Write(p)
+	}
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.minSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// start commits the headers and flushes the buffer, compressing it when
+// compress is set and the response is suitable.
+func (w *gzipResponseWriter) start(compress bool) error {
+	h := w.Header()
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	if compress && h.Get("Content-Encoding") == "" && !strings.HasPrefix(h.Get("Content-Type"), "image/") {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		w.writeHeader()
+		w.gz = gzipWriterPool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+		_, err := w.gz.Write(w.buf)
+		w.buf = nil
+		return err
+	}
+
+	w.passthrough = true
+	w.writeHeader()
+	_, err := w.ResponseWriter.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *gzipResponseWriter) writeHeader() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// Flush forces the compression decision so streaming handlers see their
+// data reach the client.
+func (w *gzipResponseWriter) Flush() {
+	if w.gz == nil && !w.passthrough {
+		w.start(true)
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *gzipResponseWriter) finish() {
+	if w.gz != nil {
+		w.gz.Close()
+		gzipWriterPool.Put(w.gz)
+		return
+	}
+	if !w.passthrough {
+		w.start(false)
+	}
+}
+
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
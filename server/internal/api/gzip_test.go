@@ -0,0 +1,98 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGzipMiddleware_CompressesLargeResponses(t *testing.T) {
+	body := strings.Repeat(`{"temperature":1.5}`, 200)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/weather", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	rr := httptest.NewRecorder()
+	NewGzipMiddleware(0)(next).ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", got)
+	}
+	if got := rr.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Fatalf("expected Vary: Accept-Encoding, got %q", got)
+	}
+	zr, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatalf("open gzip body: %v", err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	if string(got) != body {
+		t.Fatalf("decompressed body mismatch")
+	}
+}
+
+func TestGzipMiddleware_LeavesSmallResponsesPlain(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "lat and lon are required")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/weather", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	NewGzipMiddleware(0)(next).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+	if got := rr.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("expected no encoding, got %q", got)
+	}
+	var payload map[string]string
+	if err := json.NewDecoder(rr.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode error body: %v", err)
+	}
+	if payload["error"] != "lat and lon are required" {
+		t.Fatalf("unexpected error body: %v", payload)
+	}
+}
+
+func TestGzipMiddleware_SkipsHealthAndClientsWithoutGzip(t *testing.T) {
+	body := strings.Repeat("ok", 1000)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	})
+
+	for _, tc := range []struct {
+		name, path, acceptEncoding string
+	}{
+		{"health", "/health", "gzip"},
+		{"no accept-encoding", "/v1/weather", ""},
+		{"gzip refused", "/v1/weather", "gzip;q=0, br"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rr := httptest.NewRecorder()
+			NewGzipMiddleware(0)(next).ServeHTTP(rr, req)
+
+			if got := rr.Header().Get("Content-Encoding"); got != "" {
+				t.Fatalf("expected no encoding, got %q", got)
+			}
+			if rr.Body.String() != body {
+				t.Fatalf("expected body to pass through unchanged")
+			}
+		})
+	}
+}
@@ -1,54 +1,172 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
-	"errors"
-	"log/slog"
+	"fmt"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"wby/internal/graphql"
+	"wby/internal/logging"
 	"wby/internal/weather"
 )
 
 type WeatherService interface {
-	GetWeather(ctx context.Context, lat, lon float64) (*weather.WeatherResponse, error)
+	GetWeather(ctx context.Context, lat, lon float64, opts weather.WeatherOptions) (*weather.WeatherResponse, error)
+	GetCompactWeather(ctx context.Context, lat, lon float64) (*weather.WeatherResponse, error)
+	GetBlendedCurrentWeather(ctx context.Context, lat, lon float64) (*weather.CurrentWeather, error)
+	GetForecast(ctx context.Context, lat, lon float64, hours, days int) (*weather.ForecastResponse, error)
 	GetTemperatureOverlay(ctx context.Context, req weather.MapOverlayRequest) (*weather.TemperatureOverlay, error)
 	GetTemperatureSamples(ctx context.Context) (*weather.TemperatureSamplesResponse, error)
+	GetRadarImage(ctx context.Context, req weather.RadarRequest) (*weather.RadarImage, error)
+	GetLightning(ctx context.Context, lat, lon, radiusKM float64, window time.Duration) ([]weather.LightningStrike, error)
 	GetClimateNormals(ctx context.Context, lat, lon float64, currentTemp *float64) (*weather.Station, float64, []weather.ClimateNormal, weather.InterpolatedNormal, error)
 	GetLeaderboard(ctx context.Context, lat, lon float64, timeframe string) ([]weather.LeaderboardEntry, error)
+	GetStargazing(ctx context.Context, lat, lon float64) ([]weather.StargazingNight, error)
+	Freshness() weather.Freshness
+	IngestCustomObservation(ctx context.Context, obs weather.CustomObservation) error
+	RegisterCustomStation(ctx context.Context, st weather.CustomStation) (string, error)
+	CustomStationForToken(ctx context.Context, token string) (*weather.CustomStation, error)
+	NearestCustomObservation(ctx context.Context, clientID string, lat, lon float64) (*weather.CustomObservation, float64, error)
+	GetHomeSensors(ctx context.Context, clientID string) ([]weather.HomeSensorReading, error)
+	CreateSubscription(ctx context.Context, sub weather.ForecastSubscription) (weather.ForecastSubscription, error)
+	DeleteSubscription(ctx context.Context, clientID string, id int64) error
+	GetEnvironment(ctx context.Context, lat, lon float64) (*weather.Environment, error)
+	GetRoadWeather(ctx context.Context, lat, lon float64) (*weather.RoadReading, error)
+	ListStations(ctx context.Context, bbox *weather.BBox) ([]weather.Station, error)
+	GetNearbyStations(ctx context.Context, lat, lon float64, n int) ([]weather.NearbyStation, error)
+	SearchPlaces(ctx context.Context, q string) ([]weather.Place, error)
+	ResolvePlace(ctx context.Context, name string) (*weather.Place, []weather.Place, error)
+	GetStationObservations(ctx context.Context, fmisid int, from, to time.Time) (*weather.Station, []weather.Observation, error)
+	GetStationStats(ctx context.Context, fmisid int, period string, from, to time.Time) (*weather.Station, []weather.ObservationStats, error)
+	InvalidateForecastCaches() int
+	WatchHourlyForecast(lat, lon float64) (<-chan struct{}, func())
 }
 
 type Handler struct {
 	service WeatherService
+
+	graphql      *graphql.Schema
+	db           Pinger
+	fetchStatus  FetchStatus
+	maxFetchAge  time.Duration
+	obsFilter    string
+	observations ObservationSubscriber
+	heartbeat    time.Duration
+	wsLimit      chan struct{}
+	wsPing       time.Duration
+	refresher    Refresher
+
+	observationInterval time.Duration
 }
 
 func NewHandler(service WeatherService) *Handler {
-	return &Handler{service: service}
+	h := &Handler{service: service, wsLimit: make(chan struct{}, DefaultWebSocketMaxConnections)}
+	h.graphql = h.newGraphQLSchema()
+	return h
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /v1/weather", h.getWeather)
+	mux.HandleFunc("GET /v1/weather/ws", h.weatherWebSocket)
+	mux.HandleFunc("GET /v1/weather/compact", h.getCompactWeather)
+	mux.HandleFunc("GET /v1/forecast", h.getForecast)
+	mux.HandleFunc("GET /v1/places", h.getPlaces)
+	mux.HandleFunc("GET /v1/symbols", h.getSymbols)
+	mux.HandleFunc("GET /v1/stations", h.getStations)
+	mux.HandleFunc("GET /v1/stations/nearby", h.getNearbyStations)
+	mux.HandleFunc("GET /v1/stations/{fmisid}/observations", h.getStationObservations)
+	mux.HandleFunc("GET /v1/stations/{fmisid}/stats", h.getStationStats)
+	mux.HandleFunc("GET /v1/stations/{fmisid}/stream", h.streamStationObservations)
 	mux.HandleFunc("GET /v1/map/temperature", h.getTemperatureOverlay)
 	mux.HandleFunc("GET /v1/map/temperature/samples", h.getTemperatureSamples)
+	mux.HandleFunc("GET /v1/radar", h.getRadar)
+	mux.HandleFunc("GET /v1/lightning", h.getLightning)
 	mux.HandleFunc("GET /v1/climate-normals", h.getClimateNormals)
 	mux.HandleFunc("GET /v1/leaderboard", h.getLeaderboard)
+	mux.HandleFunc("GET /v1/stargazing", h.getStargazing)
+	mux.HandleFunc("POST /v1/observations/custom", h.postCustomObservation)
+	mux.HandleFunc("POST /v1/observations/custom/stations", h.postCustomStation)
+	mux.HandleFunc("POST /ingest/{token}", h.postStationIngest)
+	mux.HandleFunc("POST /v1/subscriptions", h.postSubscription)
+	mux.HandleFunc("DELETE /v1/subscriptions/{id}", h.deleteSubscription)
+	mux.HandleFunc("GET /v1/admin/freshness", h.getFreshness)
+	mux.HandleFunc("POST /v1/admin/refresh", h.postRefresh)
+	mux.HandleFunc("GET /v1/openapi.json", h.getOpenAPI)
+	mux.HandleFunc("POST /v1/graphql", h.postGraphQL)
+	mux.HandleFunc("GET /v1/graphql", h.getGraphQL)
 	mux.HandleFunc("GET /health", h.health)
+	mux.HandleFunc("GET /health/ready", h.ready)
+	mux.HandleFunc("GET /version", h.getVersion)
 }
 
 type weatherJSON struct {
-	Station  stationJSON          `json:"station"`
-	Current  currentJSON          `json:"current"`
-	Hourly   []hourlyForecastJSON `json:"hourly_forecast"`
-	Forecast []dailyForecastJSON  `json:"daily_forecast"`
-	Timezone string               `json:"timezone"`
+	Units           string               `json:"units"`
+	Station         stationJSON          `json:"station"`
+	Current         currentJSON          `json:"current"`
+	Hourly          []hourlyForecastJSON `json:"hourly_forecast"`
+	Forecast        []dailyForecastJSON  `json:"daily_forecast"`
+	Timezone        string               `json:"timezone"`
+	FogAdvisory     *fogAdvisoryJSON     `json:"fog_advisory"`
+	SynopticSummary string               `json:"synoptic_summary,omitempty"`
+	Alerts          []alertJSON          `json:"alerts"`
+	CustomStation   *customStationJSON   `json:"custom_station,omitempty"`
+	HomeSensors     []homeSensorJSON     `json:"home_sensors,omitempty"`
+	Environment     *environmentJSON     `json:"environment,omitempty"`
+	AirQuality      *airQualityJSON      `json:"air_quality,omitempty"`
+	Marine          *marineJSON          `json:"marine,omitempty"`
+	Road            *roadJSON            `json:"road,omitempty"`
+	Place           *placeJSON           `json:"place,omitempty"`
+	Meta            *metaJSON            `json:"meta,omitempty"`
+
+	// lastModified is when the newest observation or forecast in the
+	// response was taken or fetched, for Last-Modified.
+	lastModified time.Time
+}
+
+type homeSensorJSON struct {
+	Source      string    `json:"source"`
+	StationName string    `json:"station_name"`
+	ModuleName  string    `json:"module_name"`
+	ModuleType  string    `json:"module_type"`
+	ObservedAt  time.Time `json:"observed_at"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	Humidity    *float64  `json:"humidity,omitempty"`
+	Pressure    *float64  `json:"pressure,omitempty"`
+	CO2         *float64  `json:"co2,omitempty"`
+	Noise       *float64  `json:"noise,omitempty"`
+	WindSpeed   *float64  `json:"wind_speed,omitempty"`
+	WindGust    *float64  `json:"wind_gust,omitempty"`
+	WindDir     *float64  `json:"wind_dir,omitempty"`
+	Precip1h    *float64  `json:"precip_1h,omitempty"`
+}
+
+type fogAdvisoryJSON struct {
+	Level              string     `json:"level"`
+	Observed           bool       `json:"observed"`
+	ObservedVisibility *float64   `json:"observed_visibility"`
+	StartsAt           *time.Time `json:"starts_at"`
+	ClearsAt           *time.Time `json:"clears_at"`
+	FogHours           int        `json:"fog_hours"`
 }
 
 type stationJSON struct {
-	Name       string  `json:"name"`
-	DistanceKM float64 `json:"distance_km"`
+	Name       string   `json:"name"`
+	Region     string   `json:"region,omitempty"`
+	DistanceKM float64  `json:"distance_km"`
+	ElevationM *float64 `json:"elevation_m,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	// Contributors is set for blend=true responses.
+	Contributors []contributorJSON `json:"contributors,omitempty"`
+
+	// fmisid is not serialized; live updates subscribe to the station by it.
+	fmisid int
 }
 
 type currentJSON struct {
@@ -66,122 +184,409 @@ type currentJSON struct {
 	Visibility      *float64           `json:"visibility"`
 	CloudCover      *float64           `json:"cloud_cover"`
 	WeatherCode     *float64           `json:"weather_code"`
+	Condition       *string            `json:"condition"`
+	TempAnomaly     *float64           `json:"temp_anomaly"`
 	Extra           map[string]float64 `json:"extra,omitempty"`
 	ObservedAt      time.Time          `json:"observed_at"`
 }
 
 type dailyForecastJSON struct {
-	Date                       string   `json:"date"`
-	High                       *float64 `json:"high"`
-	Low                        *float64 `json:"low"`
-	TempAvg                    *float64 `json:"temperature_avg"`
-	Symbol                     *string  `json:"symbol"`
-	WindSpeed                  *float64 `json:"wind_speed_avg"`
-	WindDir                    *float64 `json:"wind_direction_avg"`
-	Humidity                   *float64 `json:"humidity_avg"`
-	PrecipMM                   *float64 `json:"precipitation_mm"`
-	Precip1hSum                *float64 `json:"precipitation_1h_sum"`
-	DewPointAvg                *float64 `json:"dew_point_avg"`
-	FogIntensityAvg            *float64 `json:"fog_intensity_avg"`
-	FrostProbabilityAvg        *float64 `json:"frost_probability_avg"`
-	SevereFrostProbabilityAvg  *float64 `json:"severe_frost_probability_avg"`
-	GeopHeightAvg              *float64 `json:"geop_height_avg"`
-	PressureAvg                *float64 `json:"pressure_avg"`
-	HighCloudCoverAvg          *float64 `json:"high_cloud_cover_avg"`
-	LowCloudCoverAvg           *float64 `json:"low_cloud_cover_avg"`
-	MediumCloudCoverAvg        *float64 `json:"medium_cloud_cover_avg"`
-	MiddleAndLowCloudCoverAvg  *float64 `json:"middle_and_low_cloud_cover_avg"`
-	TotalCloudCoverAvg         *float64 `json:"total_cloud_cover_avg"`
-	HourlyMaximumGustMax       *float64 `json:"hourly_maximum_gust_max"`
-	HourlyMaximumWindSpeedMax  *float64 `json:"hourly_maximum_wind_speed_max"`
-	PoPAvg                     *float64 `json:"pop_avg"`
-	ProbabilityThunderstormAvg *float64 `json:"probability_thunderstorm_avg"`
-	PotentialPrecipitationForm *float64 `json:"potential_precipitation_form_mode"`
-	PotentialPrecipitationType *float64 `json:"potential_precipitation_type_mode"`
-	PrecipitationForm          *float64 `json:"precipitation_form_mode"`
-	PrecipitationType          *float64 `json:"precipitation_type_mode"`
-	RadiationGlobalAvg         *float64 `json:"radiation_global_avg"`
-	RadiationLWAvg             *float64 `json:"radiation_lw_avg"`
-	WeatherNumberMode          *float64 `json:"weather_number_mode"`
-	WeatherSymbol3Mode         *float64 `json:"weather_symbol3_mode"`
-	WindUMSAvg                 *float64 `json:"wind_ums_avg"`
-	WindVMSAvg                 *float64 `json:"wind_vms_avg"`
-	WindVectorMSAvg            *float64 `json:"wind_vector_ms_avg"`
-	UVIndexAvg                 *float64 `json:"uv_index_avg"`
+	Date                       string     `json:"date"`
+	Source                     string     `json:"source"`
+	High                       *float64   `json:"high"`
+	Low                        *float64   `json:"low"`
+	TempAvg                    *float64   `json:"temperature_avg"`
+	HighRaw                    *float64   `json:"high_raw,omitempty"`
+	LowRaw                     *float64   `json:"low_raw,omitempty"`
+	TempAvgRaw                 *float64   `json:"temperature_avg_raw,omitempty"`
+	DayHigh                    *float64   `json:"day_high"`
+	DayAvg                     *float64   `json:"day_avg"`
+	NightLow                   *float64   `json:"night_low"`
+	NightAvg                   *float64   `json:"night_avg"`
+	Symbol                     *string    `json:"symbol"`
+	SymbolDescription          *string    `json:"symbol_description,omitempty"`
+	Condition                  *string    `json:"condition"`
+	WindSpeed                  *float64   `json:"wind_speed_avg"`
+	WindDir                    *float64   `json:"wind_direction_avg"`
+	Humidity                   *float64   `json:"humidity_avg"`
+	PrecipMM                   *float64   `json:"precipitation_mm"`
+	Precip1hSum                *float64   `json:"precipitation_1h_sum"`
+	PrecipHoursCounted         int        `json:"precipitation_hours_counted"`
+	SnowAccumulationMM         *float64   `json:"snow_accumulation_mm"`
+	DewPointAvg                *float64   `json:"dew_point_avg"`
+	FogIntensityAvg            *float64   `json:"fog_intensity_avg"`
+	FrostProbabilityAvg        *float64   `json:"frost_probability_avg"`
+	SevereFrostProbabilityAvg  *float64   `json:"severe_frost_probability_avg"`
+	GeopHeightAvg              *float64   `json:"geop_height_avg"`
+	PressureAvg                *float64   `json:"pressure_avg"`
+	HighCloudCoverAvg          *float64   `json:"high_cloud_cover_avg"`
+	LowCloudCoverAvg           *float64   `json:"low_cloud_cover_avg"`
+	MediumCloudCoverAvg        *float64   `json:"medium_cloud_cover_avg"`
+	MiddleAndLowCloudCoverAvg  *float64   `json:"middle_and_low_cloud_cover_avg"`
+	TotalCloudCoverAvg         *float64   `json:"total_cloud_cover_avg"`
+	HourlyMaximumGustMax       *float64   `json:"hourly_maximum_gust_max"`
+	HourlyMaximumWindSpeedMax  *float64   `json:"hourly_maximum_wind_speed_max"`
+	PoPAvg                     *float64   `json:"pop_avg"`
+	ProbabilityThunderstormAvg *float64   `json:"probability_thunderstorm_avg"`
+	PotentialPrecipitationForm *float64   `json:"potential_precipitation_form_mode"`
+	PotentialPrecipitationType *float64   `json:"potential_precipitation_type_mode"`
+	PrecipitationForm          *float64   `json:"precipitation_form_mode"`
+	PrecipitationType          *float64   `json:"precipitation_type_mode"`
+	RadiationGlobalAvg         *float64   `json:"radiation_global_avg"`
+	RadiationLWAvg             *float64   `json:"radiation_lw_avg"`
+	WeatherNumberMode          *float64   `json:"weather_number_mode"`
+	WeatherSymbol3Mode         *float64   `json:"weather_symbol3_mode"`
+	WindUMSAvg                 *float64   `json:"wind_ums_avg"`
+	WindVMSAvg                 *float64   `json:"wind_vms_avg"`
+	WindVectorMSAvg            *float64   `json:"wind_vector_ms_avg"`
+	UVIndexMax                 *float64   `json:"uv_index_max"`
+	SunshineHours              *float64   `json:"sunshine_hours"`
+	DayLengthHours             *float64   `json:"day_length_hours"`
+	Sunrise                    *time.Time `json:"sunrise"`
+	Sunset                     *time.Time `json:"sunset"`
+	PolarDay                   bool       `json:"polar_day"`
+	PolarNight                 bool       `json:"polar_night"`
+	MoonPhase                  *float64   `json:"moon_phase,omitempty"`
+	MoonPhaseName              *string    `json:"moon_phase_name,omitempty"`
+	MoonIllumination           *float64   `json:"moon_illumination,omitempty"`
+	NormalTempHigh             *float64   `json:"normal_temp_high"`
+	NormalTempLow              *float64   `json:"normal_temp_low"`
 }
 
 type hourlyForecastJSON struct {
-	Time        time.Time `json:"time"`
-	Temperature *float64  `json:"temperature"`
-	WindSpeed   *float64  `json:"wind_speed"`
-	WindDir     *float64  `json:"wind_direction"`
-	Humidity    *float64  `json:"humidity"`
-	Precip1h    *float64  `json:"precipitation_1h"`
-	Symbol      *string   `json:"symbol"`
-	UVCumulated *float64  `json:"uv_cumulated"`
+	Time              time.Time `json:"time"`
+	Temperature       *float64  `json:"temperature"`
+	TemperatureRaw    *float64  `json:"temperature_raw,omitempty"`
+	FeelsLike         *float64  `json:"feels_like"`
+	WindSpeed         *float64  `json:"wind_speed"`
+	WindDir           *float64  `json:"wind_direction"`
+	WindGust          *float64  `json:"wind_gust"`
+	Humidity          *float64  `json:"humidity"`
+	Pressure          *float64  `json:"pressure"`
+	DewPoint          *float64  `json:"dew_point"`
+	Precip1h          *float64  `json:"precipitation_1h"`
+	PoP               *float64  `json:"precipitation_probability"`
+	Symbol            *string   `json:"symbol"`
+	SymbolDescription *string   `json:"symbol_description,omitempty"`
+	Condition         *string   `json:"condition"`
+	UVCumulated       *float64  `json:"uv_cumulated"`
+	UVIndex           *float64  `json:"uv_index"`
+	CloudCover        *float64  `json:"cloud_cover"`
+	FogIntensity      *float64  `json:"fog_intensity"`
 }
 
 func (h *Handler) getWeather(w http.ResponseWriter, r *http.Request) {
-	lat, err := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	q, err := parseWeatherQuery(r)
 	if err != nil {
-		writeJSONError(w, "invalid lat parameter", http.StatusBadRequest)
+		writeBadRequest(w, err)
 		return
 	}
-	lon, err := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
-	if err != nil {
-		writeJSONError(w, "invalid lon parameter", http.StatusBadRequest)
+	if !h.resolveWeatherPlace(w, r, &q) {
 		return
 	}
 
-	result, err := h.service.GetWeather(r.Context(), lat, lon)
+	resp, err := h.buildWeather(r.Context(), q)
 	if err != nil {
-		if errors.Is(err, weather.ErrOutOfCoverage) {
-			writeJSONError(w, "no weather coverage for this location", http.StatusNotFound)
-			return
+		writeServiceError(w, r, err, "get weather failed", "lat", q.lat, "lon", q.lon)
+		return
+	}
+
+	// The ETag covers the encoded body, so each format and fields
+	// selection validates separately. Protobuf always carries every field.
+	contentType := "application/json"
+	fields := parseFields(r.URL.Query().Get("fields")).withSections(q.sections)
+	compactNulls := r.URL.Query().Get("compact_nulls") == "true"
+	encode := func() ([]byte, error) {
+		if contentType == protobufContentType {
+			return marshalProtobuf(weatherPB(resp))
 		}
-		slog.Error("get weather failed", "err", err, "lat", lat, "lon", lon)
-		writeJSONError(w, "internal server error", http.StatusInternalServerError)
+		var body bytes.Buffer
+		err := json.NewEncoder(&body).Encode(sparse(resp, fields, compactNulls))
+		return body.Bytes(), err
+	}
+	if wantsProtobuf(r.Header.Get("Accept")) {
+		contentType = protobufContentType
+	}
+
+	// meta changes on every request (ages, cache hits), so it is left out
+	// of the ETag to keep revalidation working while the data is unchanged.
+	meta := resp.Meta
+	resp.Meta = nil
+	body, err := encode()
+	if err != nil {
+		logging.FromContext(r.Context()).Error("encode weather failed", "err", err, "content_type", contentType)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(body))
+	w.Header().Set("ETag", etag)
+	if !resp.lastModified.IsZero() {
+		w.Header().Set("Last-Modified", resp.lastModified.UTC().Format(http.TimeFormat))
+	}
+	w.Header().Set("Cache-Control", cacheControl(h.weatherMaxAge(resp, q.sections, time.Now()), weatherStaleWhileRevalidate))
+	w.Header().Add("Vary", "Accept")
+	if notModified(r, etag, resp.lastModified) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
+	resp.Meta = meta
+	if body, err = encode(); err != nil {
+		logging.FromContext(r.Context()).Error("encode weather failed", "err", err, "content_type", contentType)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(body)
+}
+
+// notModified evaluates the request's conditional headers. As in RFC 9110,
+// If-Modified-Since is only considered without If-None-Match, and it is
+// compared at the one-second precision of HTTP dates.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		return match == etag
+	}
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// weatherQuery holds the validated parameters of a weather request.
+type weatherQuery struct {
+	lat, lon           float64
+	place              string
+	resolvedPlace      *weather.Place
+	hours, days        int
+	units, lang        string
+	blend              bool
+	blendCustom        bool
+	sections           weather.Section
+	includeEnvironment bool
+	includeRoad        bool
+	omitMoon           bool
+}
+
+func parseWeatherQuery(r *http.Request) (weatherQuery, error) {
+	// A place name stands in for coordinates only when neither is given;
+	// resolveWeatherPlace fills in lat and lon afterwards.
+	query := r.URL.Query()
+	place := strings.TrimSpace(query.Get("place"))
+	var lat, lon float64
+	if place == "" || query.Has("lat") || query.Has("lon") {
+		place = ""
+		var err error
+		if lat, lon, err = queryLatLon(query); err != nil {
+			return weatherQuery{}, err
+		}
+	}
+	units, err := parseUnits(r)
+	if err != nil {
+		return weatherQuery{}, err
+	}
+	lang, err := parseLang(r)
+	if err != nil {
+		return weatherQuery{}, err
+	}
+	return weatherQuery{
+		lat:                lat,
+		lon:                lon,
+		place:              place,
+		hours:              parseHours(r),
+		days:               parseDays(r),
+		units:              units,
+		lang:               lang,
+		blend:              r.URL.Query().Get("blend") == "true",
+		blendCustom:        r.URL.Query().Get("blend_custom") == "true",
+		sections:           parseSections(r.URL.Query().Get("include")),
+		includeEnvironment: includes(r.URL.Query().Get("include"), "environment"),
+		includeRoad:        includes(r.URL.Query().Get("include"), "road"),
+		omitMoon:           r.URL.Query().Get("moon") == "false",
+	}, nil
+}
+
+// buildWeather assembles the /v1/weather response. It is shared with the
+// GraphQL weather query so both go through the same service caches.
+func (h *Handler) buildWeather(ctx context.Context, q weatherQuery) (*weatherJSON, error) {
+	lat, lon := q.lat, q.lon
+	result, err := h.service.GetWeather(ctx, lat, lon, weather.WeatherOptions{Hours: q.hours, Days: q.days, Sections: q.sections})
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy current conditions so blending never mutates the cached response.
+	current := result.Current
+	wantsCurrent := q.sections.Includes(weather.SectionCurrent)
+	if q.blend && wantsCurrent {
+		blended, err := h.service.GetBlendedCurrentWeather(ctx, lat, lon)
+		if err != nil {
+			logging.FromContext(ctx).Warn("blended observation unavailable", "err", err, "lat", lat, "lon", lon)
+		} else if blended != nil {
+			current = *blended
+		}
+	}
+	obs := current.Observation
+	var customStation *customStationJSON
+	if q.blendCustom && wantsCurrent {
+		if clientID := clientIDFromContext(ctx); clientID != "" {
+			custom, distKM, err := h.service.NearestCustomObservation(ctx, clientID, lat, lon)
+			if err != nil {
+				logging.FromContext(ctx).Warn("custom observation lookup failed", "err", err, "client_id", clientID)
+			} else if custom != nil {
+				obs = weather.BlendCustomObservation(obs, *custom)
+				customStation = &customStationJSON{
+					StationID:  custom.StationID,
+					Source:     custom.Source,
+					DistanceKM: math.Round(distKM*10) / 10,
+					ObservedAt: custom.ObservedAt,
+				}
+			}
+		}
+	}
+
 	resp := weatherJSON{
 		Station: stationJSON{
-			Name:       result.Current.Station.Name,
-			DistanceKM: result.Current.DistanceKM,
+			Name:         current.Station.Name,
+			Region:       current.Station.Region,
+			DistanceKM:   current.DistanceKM,
+			ElevationM:   current.Station.ElevationM,
+			Type:         current.Station.Type,
+			Contributors: toContributorsJSON(current.Contributors),
+			fmisid:       current.Station.FMISID,
 		},
 		Current: currentJSON{
-			Temperature:     result.Current.Observation.Temperature,
-			FeelsLike:       computeFeelsLike(result.Current.Observation.Temperature, result.Current.Observation.WindSpeed),
-			WindSpeed:       result.Current.Observation.WindSpeed,
-			WindGust:        result.Current.Observation.WindGust,
-			WindDir:         result.Current.Observation.WindDir,
-			Humidity:        result.Current.Observation.Humidity,
-			DewPoint:        result.Current.Observation.DewPoint,
-			Pressure:        result.Current.Observation.Pressure,
-			Precip1h:        result.Current.Observation.Precip1h,
-			PrecipIntensity: result.Current.Observation.PrecipIntensity,
-			SnowDepth:       result.Current.Observation.SnowDepth,
-			Visibility:      result.Current.Observation.Visibility,
-			CloudCover:      result.Current.Observation.TotalCloudCover,
-			WeatherCode:     result.Current.Observation.WeatherCode,
-			Extra:           result.Current.Observation.ExtraNumericParams,
-			ObservedAt:      result.Current.Observation.ObservedAt,
+			Temperature:     obs.Temperature,
+			FeelsLike:       computeFeelsLike(obs.Temperature, obs.WindSpeed),
+			WindSpeed:       obs.WindSpeed,
+			WindGust:        obs.WindGust,
+			WindDir:         obs.WindDir,
+			Humidity:        obs.Humidity,
+			DewPoint:        obs.DewPoint,
+			Pressure:        obs.Pressure,
+			Precip1h:        obs.Precip1h,
+			PrecipIntensity: obs.PrecipIntensity,
+			SnowDepth:       obs.SnowDepth,
+			Visibility:      obs.Visibility,
+			CloudCover:      obs.TotalCloudCover,
+			WeatherCode:     obs.WeatherCode,
+			Condition:       observationCondition(obs),
+			TempAnomaly:     current.TempAnomaly,
+			Extra:           obs.ExtraNumericParams,
+			ObservedAt:      obs.ObservedAt,
 		},
-		Timezone: result.Timezone,
+		Timezone:        result.Timezone,
+		SynopticSummary: result.SynopticSummary,
+		Alerts:          toAlertsJSON(result.Warnings),
+		CustomStation:   customStation,
+		AirQuality:      toAirQualityJSON(result.AirQuality),
+		Marine:          toMarineJSON(result.Marine),
+	}
+	if q.resolvedPlace != nil {
+		place := toPlaceJSON(*q.resolvedPlace)
+		resp.Place = &place
+	}
+	if q.includeEnvironment {
+		env, err := h.service.GetEnvironment(ctx, lat, lon)
+		if err != nil {
+			logging.FromContext(ctx).Warn("environment unavailable", "err", err, "lat", lat, "lon", lon)
+		} else {
+			resp.Environment = toEnvironmentJSON(env)
+		}
+	}
+	if q.includeRoad {
+		road, err := h.service.GetRoadWeather(ctx, lat, lon)
+		if err != nil {
+			logging.FromContext(ctx).Warn("road weather unavailable", "err", err, "lat", lat, "lon", lon)
+		} else {
+			resp.Road = toRoadJSON(road)
+		}
+	}
+	if clientID := clientIDFromContext(ctx); clientID != "" && wantsCurrent {
+		sensors, err := h.service.GetHomeSensors(ctx, clientID)
+		if err != nil {
+			logging.FromContext(ctx).Warn("home sensors unavailable", "err", err, "client_id", clientID)
+		}
+		for _, s := range sensors {
+			resp.HomeSensors = append(resp.HomeSensors, homeSensorJSON{
+				Source:      s.Source,
+				StationName: s.StationName,
+				ModuleName:  s.ModuleName,
+				ModuleType:  s.ModuleType,
+				ObservedAt:  s.ObservedAt,
+				Temperature: s.Temperature,
+				Humidity:    s.Humidity,
+				Pressure:    s.Pressure,
+				CO2:         s.CO2,
+				Noise:       s.Noise,
+				WindSpeed:   s.WindSpeed,
+				WindGust:    s.WindGust,
+				WindDir:     s.WindDir,
+				Precip1h:    s.Precip1h,
+			})
+		}
+	}
+	if fog := result.FogAdvisory; fog != nil {
+		resp.FogAdvisory = &fogAdvisoryJSON{
+			Level:              fog.Level,
+			Observed:           fog.Observed,
+			ObservedVisibility: fog.ObservedVisibility,
+			StartsAt:           fog.StartsAt,
+			ClearsAt:           fog.ClearsAt,
+			FogHours:           fog.FogHours,
+		}
 	}
 
-	for _, f := range result.Forecast {
-		resp.Forecast = append(resp.Forecast, dailyForecastJSON{
+	resp.Forecast = toDailyForecastJSON(result.Forecast)
+	resp.Hourly = toHourlyForecastJSON(result.Hourly)
+	if q.omitMoon {
+		dropMoon(resp.Forecast)
+	}
+	resp.applyUnits(q.units)
+	describeSymbols(resp.Forecast, resp.Hourly, q.lang)
+	resp.Meta = toMetaJSON(result, obs, time.Now())
+	resp.lastModified = result.LastModified()
+	if obs.ObservedAt.After(resp.lastModified) {
+		// Blending can serve a newer observation than the nearest
+		// station's.
+		resp.lastModified = obs.ObservedAt
+	}
+	return &resp, nil
+}
+
+func toDailyForecastJSON(forecast []weather.DailyForecast) []dailyForecastJSON {
+	var out []dailyForecastJSON
+	for _, f := range forecast {
+		out = append(out, dailyForecastJSON{
 			Date:                       f.Date.Format("2006-01-02"),
+			Source:                     f.Source,
 			High:                       f.TempHigh,
 			Low:                        f.TempLow,
 			TempAvg:                    f.TempAvg,
+			HighRaw:                    f.TempHighRaw,
+			LowRaw:                     f.TempLowRaw,
+			TempAvgRaw:                 f.TempAvgRaw,
+			DayHigh:                    f.TempDayMax,
+			DayAvg:                     f.TempDayAvg,
+			NightLow:                   f.TempNightMin,
+			NightAvg:                   f.TempNightAvg,
 			Symbol:                     f.Symbol,
+			Condition:                  symbolCondition(f.Symbol),
 			WindSpeed:                  f.WindSpeed,
 			WindDir:                    f.WindDir,
 			Humidity:                   f.HumidityAvg,
 			PrecipMM:                   f.PrecipMM,
 			Precip1hSum:                f.Precip1hSum,
+			PrecipHoursCounted:         f.PrecipHoursCounted,
+			SnowAccumulationMM:         f.SnowAccumulationMM,
 			DewPointAvg:                f.DewPointAvg,
 			FogIntensityAvg:            f.FogIntensityAvg,
 			FrostProbabilityAvg:        f.FrostProbabilityAvg,
@@ -208,25 +613,85 @@ func (h *Handler) getWeather(w http.ResponseWriter, r *http.Request) {
 			WindUMSAvg:                 f.WindUMSAvg,
 			WindVMSAvg:                 f.WindVMSAvg,
 			WindVectorMSAvg:            f.WindVectorMSAvg,
-			UVIndexAvg:                 f.UVIndexAvg,
+			UVIndexMax:                 f.UVIndexMax,
+			SunshineHours:              f.SunshineHours,
+			DayLengthHours:             f.DayLengthHours,
+			Sunrise:                    f.Sunrise,
+			Sunset:                     f.Sunset,
+			PolarDay:                   f.PolarDay,
+			PolarNight:                 f.PolarNight,
+			MoonPhase:                  f.MoonPhase,
+			MoonPhaseName:              moonPhaseName(f.MoonPhase),
+			MoonIllumination:           f.MoonIllumination,
+			NormalTempHigh:             f.NormalTempHigh,
+			NormalTempLow:              f.NormalTempLow,
 		})
 	}
-	for _, hfc := range result.Hourly {
-		resp.Hourly = append(resp.Hourly, hourlyForecastJSON{
-			Time:        hfc.Time,
-			Temperature: hfc.Temperature,
-			WindSpeed:   hfc.WindSpeed,
-			WindDir:     hfc.WindDir,
-			Humidity:    hfc.Humidity,
-			Precip1h:    hfc.Precip1h,
-			Symbol:      hfc.Symbol,
-			UVCumulated: hfc.UVCumulated,
+	return out
+}
+
+func moonPhaseName(phase *float64) *string {
+	if phase == nil {
+		return nil
+	}
+	name := weather.MoonPhaseName(*phase)
+	return &name
+}
+
+// dropMoon removes the moon fields for clients that pass moon=false.
+func dropMoon(daily []dailyForecastJSON) {
+	for i := range daily {
+		daily[i].MoonPhase = nil
+		daily[i].MoonPhaseName = nil
+		daily[i].MoonIllumination = nil
+	}
+}
+
+func toHourlyForecastJSON(hourly []weather.HourlyForecast) []hourlyForecastJSON {
+	var out []hourlyForecastJSON
+	for _, hfc := range hourly {
+		out = append(out, hourlyForecastJSON{
+			Time:           hfc.Time,
+			Temperature:    hfc.Temperature,
+			TemperatureRaw: hfc.TemperatureRaw,
+			FeelsLike:      computeFeelsLike(hfc.Temperature, hfc.WindSpeed),
+			WindSpeed:      hfc.WindSpeed,
+			WindDir:        hfc.WindDir,
+			WindGust:       hfc.WindGust,
+			Humidity:       hfc.Humidity,
+			Pressure:       hfc.Pressure,
+			DewPoint:       hfc.DewPoint,
+			Precip1h:       hfc.Precip1h,
+			PoP:            hfc.PoP,
+			Symbol:         hfc.Symbol,
+			Condition:      symbolCondition(hfc.Symbol),
+			UVCumulated:    hfc.UVCumulated,
+			UVIndex:        hfc.UVIndex,
+			CloudCover:     hfc.CloudCover,
+			FogIntensity:   hfc.FogIntensity,
 		})
 	}
+	return out
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Cache-Control", "public, max-age=300")
-	json.NewEncoder(w).Encode(resp)
+// parseHours reads the optional hours parameter. Invalid values mean the
+// default; the service clamps large ones.
+func parseHours(r *http.Request) int {
+	hours, err := strconv.Atoi(r.URL.Query().Get("hours"))
+	if err != nil || hours <= 0 {
+		return 0
+	}
+	return hours
+}
+
+// parseDays reads the optional days parameter. Invalid values mean the
+// default.
+func parseDays(r *http.Request) int {
+	days, err := strconv.Atoi(r.URL.Query().Get("days"))
+	if err != nil {
+		return 0
+	}
+	return days
 }
 
 func computeFeelsLike(temp, wind *float64) *float64 {
@@ -242,12 +707,6 @@ func computeFeelsLike(temp, wind *float64) *float64 {
 	return &fl
 }
 
-func writeJSONError(w http.ResponseWriter, msg string, status int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": msg})
-}
-
 type climateNormalsJSON struct {
 	Station stationJSON            `json:"station"`
 	Period  string                 `json:"period"`
@@ -272,14 +731,9 @@ type monthlyNormalJSON struct {
 }
 
 func (h *Handler) getClimateNormals(w http.ResponseWriter, r *http.Request) {
-	lat, err := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
-	if err != nil {
-		writeJSONError(w, "invalid lat parameter", http.StatusBadRequest)
-		return
-	}
-	lon, err := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+	lat, lon, err := queryLatLon(r.URL.Query())
 	if err != nil {
-		writeJSONError(w, "invalid lon parameter", http.StatusBadRequest)
+		writeBadRequest(w, err)
 		return
 	}
 
@@ -293,15 +747,13 @@ func (h *Handler) getClimateNormals(w http.ResponseWriter, r *http.Request) {
 
 	station, distKm, normals, today, err := h.service.GetClimateNormals(r.Context(), lat, lon, currentTemp)
 	if err != nil {
-		slog.Error("climate normals", "err", err)
-		writeJSONError(w, "failed to get climate normals", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("climate normals", "err", err)
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to get climate normals")
 		return
 	}
 
 	if station == nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]string{"error": "no climate normals available for this location"})
+		writeError(w, http.StatusNotFound, codeNotFound, "no climate normals available for this location")
 		return
 	}
 
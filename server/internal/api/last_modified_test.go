@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestGetWeather_LastModified(t *testing.T) {
+	observedAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	fetchedAt := time.Date(2026, 1, 10, 12, 3, 7, 500_000_000, time.UTC)
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{
+			Current:  weather.CurrentWeather{Observation: weather.Observation{ObservedAt: observedAt}},
+			Forecast: []weather.DailyForecast{{Date: observedAt, FetchedAt: observedAt.Add(-time.Hour)}},
+			Hourly:   []weather.HourlyForecast{{Time: observedAt, FetchedAt: fetchedAt}},
+		},
+	})
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	do := func(method string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/weather?lat=60.17&lon=24.94", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do(http.MethodGet, nil)
+	// The newest fetch wins, truncated to HTTP date precision.
+	if got, want := rr.Header().Get("Last-Modified"), "Sat, 10 Jan 2026 12:03:07 GMT"; got != want {
+		t.Fatalf("Last-Modified = %q, want %q", got, want)
+	}
+	etag := rr.Header().Get("ETag")
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"same second", map[string]string{"If-Modified-Since": "Sat, 10 Jan 2026 12:03:07 GMT"}, http.StatusNotModified},
+		{"later", map[string]string{"If-Modified-Since": "Sat, 10 Jan 2026 13:00:00 GMT"}, http.StatusNotModified},
+		{"earlier", map[string]string{"If-Modified-Since": "Sat, 10 Jan 2026 12:03:06 GMT"}, http.StatusOK},
+		{"malformed", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+		{"etag takes precedence", map[string]string{"If-None-Match": `"stale"`, "If-Modified-Since": "Sat, 10 Jan 2026 13:00:00 GMT"}, http.StatusOK},
+		{"matching etag", map[string]string{"If-None-Match": etag, "If-Modified-Since": "Sat, 10 Jan 2026 12:00:00 GMT"}, http.StatusNotModified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := do(http.MethodGet, tt.headers); rr.Code != tt.want {
+				t.Fatalf("expected status %d, got %d", tt.want, rr.Code)
+			}
+		})
+	}
+
+	head := do(http.MethodHead, nil)
+	if head.Code != http.StatusOK || head.Body.Len() != 0 {
+		t.Fatalf("expected an empty 200 for HEAD, got %d with %d bytes", head.Code, head.Body.Len())
+	}
+	if head.Header().Get("ETag") != etag || head.Header().Get("Last-Modified") == "" || head.Header().Get("Content-Length") == "" {
+		t.Fatalf("expected HEAD to carry the GET headers, got %v", head.Header())
+	}
+}
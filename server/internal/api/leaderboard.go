@@ -2,10 +2,10 @@ package api
 
 import (
 	"encoding/json"
-	"log/slog"
 	"net/http"
-	"strconv"
 	"time"
+
+	"wby/internal/logging"
 )
 
 type leaderboardJSON struct {
@@ -33,14 +33,9 @@ var supportedTimeframes = map[string]bool{
 }
 
 func (h *Handler) getLeaderboard(w http.ResponseWriter, r *http.Request) {
-	lat, err := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
-	if err != nil {
-		writeJSONError(w, "invalid lat parameter", http.StatusBadRequest)
-		return
-	}
-	lon, err := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+	lat, lon, err := queryLatLon(r.URL.Query())
 	if err != nil {
-		writeJSONError(w, "invalid lon parameter", http.StatusBadRequest)
+		writeBadRequest(w, err)
 		return
 	}
 
@@ -49,14 +44,14 @@ func (h *Handler) getLeaderboard(w http.ResponseWriter, r *http.Request) {
 		timeframe = "now"
 	}
 	if !supportedTimeframes[timeframe] {
-		writeJSONError(w, "unsupported timeframe: "+timeframe, http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "unsupported timeframe: "+timeframe)
 		return
 	}
 
 	entries, err := h.service.GetLeaderboard(r.Context(), lat, lon, timeframe)
 	if err != nil {
-		slog.Error("get leaderboard failed", "err", err, "lat", lat, "lon", lon)
-		writeJSONError(w, "internal server error", http.StatusInternalServerError)
+		logging.FromContext(r.Context()).Error("get leaderboard failed", "err", err, "lat", lat, "lon", lon)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
 		return
 	}
 
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"wby/internal/weather"
+)
+
+const (
+	defaultLightningRadiusKM = 50
+	maxLightningRadiusKM     = 300
+	defaultLightningHours    = 1
+	maxLightningHours        = 24
+)
+
+type lightningJSON struct {
+	RadiusKM float64               `json:"radius_km"`
+	Hours    int                   `json:"hours"`
+	Strikes  []lightningStrikeJSON `json:"strikes"`
+	Storm    *stormJSON            `json:"storm"`
+}
+
+// stormJSON is the estimated track of the storm cell producing the
+// strikes. DirectionDeg is where it is heading, clockwise from north.
+type stormJSON struct {
+	Lat               float64    `json:"lat"`
+	Lon               float64    `json:"lon"`
+	ObservedAt        time.Time  `json:"observed_at"`
+	StrikeCount       int        `json:"strike_count"`
+	SpeedKMH          float64    `json:"speed_kmh"`
+	DirectionDeg      float64    `json:"direction_deg"`
+	DistanceKM        float64    `json:"distance_km"`
+	ClosestApproachKM *float64   `json:"closest_approach_km"`
+	ArrivalAt         *time.Time `json:"arrival_at"`
+}
+
+type lightningStrikeJSON struct {
+	Time         time.Time `json:"time"`
+	Lat          float64   `json:"lat"`
+	Lon          float64   `json:"lon"`
+	DistanceKM   float64   `json:"distance_km"`
+	PeakCurrent  *float64  `json:"peak_current_ka"`
+	Multiplicity *int      `json:"multiplicity"`
+}
+
+func (h *Handler) getLightning(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	lat, lon, err := queryLatLon(q)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	radiusKM := float64(defaultLightningRadiusKM)
+	if raw := q.Get("radius_km"); raw != "" {
+		radiusKM, err = strconv.ParseFloat(raw, 64)
+		if err != nil || radiusKM <= 0 {
+			writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid radius_km parameter")
+			return
+		}
+		radiusKM = min(radiusKM, maxLightningRadiusKM)
+	}
+	hours := defaultLightningHours
+	if raw := q.Get("hours"); raw != "" {
+		hours, err = strconv.Atoi(raw)
+		if err != nil || hours <= 0 {
+			writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid hours parameter")
+			return
+		}
+		hours = min(hours, maxLightningHours)
+	}
+
+	strikes, err := h.service.GetLightning(r.Context(), lat, lon, radiusKM, time.Duration(hours)*time.Hour)
+	if err != nil {
+		writeServiceError(w, r, err, "get lightning failed", "lat", lat, "lon", lon)
+		return
+	}
+
+	resp := lightningJSON{RadiusKM: radiusKM, Hours: hours, Strikes: make([]lightningStrikeJSON, len(strikes))}
+	for i, s := range strikes {
+		resp.Strikes[i] = lightningStrikeJSON{
+			Time:         s.Time,
+			Lat:          s.Lat,
+			Lon:          s.Lon,
+			DistanceKM:   s.DistanceKM,
+			PeakCurrent:  s.PeakCurrent,
+			Multiplicity: s.Multiplicity,
+		}
+	}
+
+	if storm := weather.NowcastStorm(strikes, lat, lon); storm != nil {
+		resp.Storm = &stormJSON{
+			Lat:               storm.Lat,
+			Lon:               storm.Lon,
+			ObservedAt:        storm.ObservedAt,
+			StrikeCount:       storm.StrikeCount,
+			SpeedKMH:          storm.SpeedKMH,
+			DirectionDeg:      storm.DirectionDeg,
+			DistanceKM:        storm.DistanceKM,
+			ClosestApproachKM: storm.ClosestApproachKM,
+			ArrivalAt:         storm.ArrivalAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=60")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+type lightningServiceStub struct {
+	weatherServiceStub
+	strikes []weather.LightningStrike
+	radius  *float64
+	window  *time.Duration
+}
+
+func (s lightningServiceStub) GetLightning(ctx context.Context, lat, lon, radiusKM float64, window time.Duration) ([]weather.LightningStrike, error) {
+	if lon < 19 {
+		return nil, weather.ErrOutOfCoverage
+	}
+	*s.radius, *s.window = radiusKM, window
+	return s.strikes, nil
+}
+
+func TestGetLightning(t *testing.T) {
+	peak := -14.2
+	var radius float64
+	var window time.Duration
+	h := NewHandler(lightningServiceStub{
+		strikes: []weather.LightningStrike{
+			{Time: time.Date(2026, 7, 15, 12, 2, 41, 0, time.UTC), Lat: 61.35, Lon: 23.50, DistanceKM: 17.3},
+			{Time: time.Date(2026, 7, 15, 12, 10, 5, 0, time.UTC), Lat: 61.50, Lon: 23.76, DistanceKM: 0.4, PeakCurrent: &peak},
+		},
+		radius: &radius,
+		window: &window,
+	})
+
+	rr := httptest.NewRecorder()
+	h.getLightning(rr, httptest.NewRequest(http.MethodGet, "/v1/lightning?lat=61.5&lon=23.76&radius_km=1000&hours=3", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body)
+	}
+	if radius != maxLightningRadiusKM || window != 3*time.Hour {
+		t.Errorf("expected radius %d km and 3h window, got %v km and %v", maxLightningRadiusKM, radius, window)
+	}
+
+	var resp lightningJSON
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Strikes) != 2 || resp.Strikes[1].PeakCurrent == nil || *resp.Strikes[1].PeakCurrent != peak {
+		t.Fatalf("unexpected strikes %+v", resp.Strikes)
+	}
+	if resp.Storm != nil {
+		t.Errorf("expected no storm track from two strikes, got %+v", resp.Storm)
+	}
+
+	for query, status := range map[string]int{
+		"lat=61.5":                        http.StatusBadRequest,
+		"lat=61.5&lon=23.76&hours=0":      http.StatusBadRequest,
+		"lat=61.5&lon=23.76&radius_km=-1": http.StatusBadRequest,
+		"lat=61.5&lon=10":                 http.StatusNotFound,
+	} {
+		rr := httptest.NewRecorder()
+		h.getLightning(rr, httptest.NewRequest(http.MethodGet, "/v1/lightning?"+query, nil))
+		if rr.Code != status {
+			t.Errorf("%s: expected status %d, got %d", query, status, rr.Code)
+		}
+	}
+}
@@ -2,11 +2,11 @@ package api
 
 import (
 	"fmt"
-	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+	"wby/internal/logging"
 	"wby/internal/weather"
 )
 
@@ -18,14 +18,14 @@ const (
 func (h *Handler) getTemperatureOverlay(w http.ResponseWriter, r *http.Request) {
 	req, err := parseMapTemperatureRequest(r)
 	if err != nil {
-		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		writeBadRequest(w, err)
 		return
 	}
 
 	overlay, err := h.service.GetTemperatureOverlay(r.Context(), req)
 	if err != nil {
-		slog.Error("get temperature overlay failed", "err", err, "bbox", fmt.Sprintf("%f,%f,%f,%f", req.MinLon, req.MinLat, req.MaxLon, req.MaxLat))
-		writeJSONError(w, "overlay unavailable", http.StatusBadGateway)
+		logging.FromContext(r.Context()).Error("get temperature overlay failed", "err", err, "bbox", fmt.Sprintf("%f,%f,%f,%f", req.MinLon, req.MinLat, req.MaxLon, req.MaxLat))
+		writeError(w, http.StatusBadGateway, codeUpstreamUnavailable, "overlay unavailable")
 		return
 	}
 
@@ -39,19 +39,9 @@ func (h *Handler) getTemperatureOverlay(w http.ResponseWriter, r *http.Request)
 }
 
 func parseMapTemperatureRequest(r *http.Request) (weather.MapOverlayRequest, error) {
-	bboxRaw := strings.TrimSpace(r.URL.Query().Get("bbox"))
-	parts := strings.Split(bboxRaw, ",")
-	if len(parts) != 4 {
-		return weather.MapOverlayRequest{}, fmt.Errorf("invalid bbox parameter")
-	}
-
-	var bbox [4]float64
-	for i, p := range parts {
-		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
-		if err != nil {
-			return weather.MapOverlayRequest{}, fmt.Errorf("invalid bbox parameter")
-		}
-		bbox[i] = v
+	bbox, err := parseBBox(r.URL.Query().Get("bbox"))
+	if err != nil {
+		return weather.MapOverlayRequest{}, err
 	}
 
 	width, err := strconv.Atoi(r.URL.Query().Get("width"))
@@ -65,30 +55,43 @@ func parseMapTemperatureRequest(r *http.Request) (weather.MapOverlayRequest, err
 	width = clamp(width, minOverlayDim, maxOverlayDim)
 	height = clamp(height, minOverlayDim, maxOverlayDim)
 
-	req := weather.MapOverlayRequest{
-		MinLon: bbox[0],
-		MinLat: bbox[1],
-		MaxLon: bbox[2],
-		MaxLat: bbox[3],
+	return weather.MapOverlayRequest{
+		MinLon: bbox.MinLon,
+		MinLat: bbox.MinLat,
+		MaxLon: bbox.MaxLon,
+		MaxLat: bbox.MaxLat,
 		Width:  width,
 		Height: height,
-	}
-	if err := validateBBox(&req); err != nil {
-		return weather.MapOverlayRequest{}, err
-	}
-	return req, nil
+	}, nil
 }
 
-func validateBBox(req *weather.MapOverlayRequest) error {
-	req.MinLon = clampFloat(req.MinLon, -180, 180)
-	req.MaxLon = clampFloat(req.MaxLon, -180, 180)
-	req.MinLat = clampFloat(req.MinLat, -90, 90)
-	req.MaxLat = clampFloat(req.MaxLat, -90, 90)
+// parseBBox parses minLon,minLat,maxLon,maxLat, clamping to valid
+// coordinates.
+func parseBBox(raw string) (weather.BBox, error) {
+	parts := strings.Split(strings.TrimSpace(raw), ",")
+	if len(parts) != 4 {
+		return weather.BBox{}, fmt.Errorf("invalid bbox parameter")
+	}
 
-	if req.MinLon >= req.MaxLon || req.MinLat >= req.MaxLat {
-		return fmt.Errorf("invalid bbox parameter")
+	var v [4]float64
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return weather.BBox{}, fmt.Errorf("invalid bbox parameter")
+		}
+		v[i] = f
+	}
+
+	bbox := weather.BBox{
+		MinLon: clampFloat(v[0], -180, 180),
+		MinLat: clampFloat(v[1], -90, 90),
+		MaxLon: clampFloat(v[2], -180, 180),
+		MaxLat: clampFloat(v[3], -90, 90),
+	}
+	if bbox.MinLon >= bbox.MaxLon || bbox.MinLat >= bbox.MaxLat {
+		return weather.BBox{}, fmt.Errorf("invalid bbox parameter")
 	}
-	return nil
+	return bbox, nil
 }
 
 func clamp(v, min, max int) int {
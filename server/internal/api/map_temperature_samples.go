@@ -4,10 +4,10 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"log/slog"
 	"net/http"
 	"time"
 
+	"wby/internal/logging"
 	"wby/internal/weather"
 )
 
@@ -28,16 +28,16 @@ type temperatureSampleJSON struct {
 func (h *Handler) getTemperatureSamples(w http.ResponseWriter, r *http.Request) {
 	resp, err := h.service.GetTemperatureSamples(r.Context())
 	if err != nil {
-		slog.Error("get temperature samples failed", "err", err)
-		writeJSONError(w, "samples unavailable", http.StatusBadGateway)
+		logging.FromContext(r.Context()).Error("get temperature samples failed", "err", err)
+		writeError(w, http.StatusBadGateway, codeUpstreamUnavailable, "samples unavailable")
 		return
 	}
 
 	payload := buildTemperatureSamplesJSON(resp)
 	body, err := json.Marshal(payload)
 	if err != nil {
-		slog.Error("marshal temperature samples failed", "err", err)
-		writeJSONError(w, "samples unavailable", http.StatusBadGateway)
+		logging.FromContext(r.Context()).Error("marshal temperature samples failed", "err", err)
+		writeError(w, http.StatusBadGateway, codeUpstreamUnavailable, "samples unavailable")
 		return
 	}
 
@@ -75,7 +75,15 @@ type fakeWeatherService struct {
 	err     error
 }
 
-func (f fakeWeatherService) GetWeather(ctx context.Context, lat, lon float64) (*weather.WeatherResponse, error) {
+func (f fakeWeatherService) GetWeather(ctx context.Context, lat, lon float64, opts weather.WeatherOptions) (*weather.WeatherResponse, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetCompactWeather(ctx context.Context, lat, lon float64) (*weather.WeatherResponse, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetBlendedCurrentWeather(ctx context.Context, lat, lon float64) (*weather.CurrentWeather, error) {
 	panic("not used in this test")
 }
 
@@ -94,6 +102,14 @@ func (f fakeWeatherService) GetTemperatureOverlay(ctx context.Context, req weath
 	}, nil
 }
 
+func (f fakeWeatherService) GetLightning(ctx context.Context, lat, lon, radiusKM float64, window time.Duration) ([]weather.LightningStrike, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetRadarImage(ctx context.Context, req weather.RadarRequest) (*weather.RadarImage, error) {
+	panic("not used in this test")
+}
+
 func (f fakeWeatherService) GetTemperatureSamples(ctx context.Context) (*weather.TemperatureSamplesResponse, error) {
 	if f.samples != nil {
 		return f.samples, nil
@@ -108,3 +124,83 @@ func (f fakeWeatherService) GetClimateNormals(ctx context.Context, lat, lon floa
 func (f fakeWeatherService) GetLeaderboard(ctx context.Context, lat, lon float64, timeframe string) ([]weather.LeaderboardEntry, error) {
 	panic("not used in this test")
 }
+
+func (f fakeWeatherService) GetStargazing(ctx context.Context, lat, lon float64) ([]weather.StargazingNight, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) Freshness() weather.Freshness {
+	return weather.DefaultFreshness()
+}
+
+func (f fakeWeatherService) IngestCustomObservation(ctx context.Context, obs weather.CustomObservation) error {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) RegisterCustomStation(ctx context.Context, st weather.CustomStation) (string, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) CustomStationForToken(ctx context.Context, token string) (*weather.CustomStation, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) NearestCustomObservation(ctx context.Context, clientID string, lat, lon float64) (*weather.CustomObservation, float64, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetHomeSensors(ctx context.Context, clientID string) ([]weather.HomeSensorReading, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) CreateSubscription(ctx context.Context, sub weather.ForecastSubscription) (weather.ForecastSubscription, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) DeleteSubscription(ctx context.Context, clientID string, id int64) error {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetEnvironment(ctx context.Context, lat, lon float64) (*weather.Environment, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetRoadWeather(ctx context.Context, lat, lon float64) (*weather.RoadReading, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) ListStations(ctx context.Context, bbox *weather.BBox) ([]weather.Station, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetStationObservations(ctx context.Context, fmisid int, from, to time.Time) (*weather.Station, []weather.Observation, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetNearbyStations(ctx context.Context, lat, lon float64, n int) ([]weather.NearbyStation, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) SearchPlaces(ctx context.Context, q string) ([]weather.Place, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) ResolvePlace(ctx context.Context, name string) (*weather.Place, []weather.Place, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) InvalidateForecastCaches() int {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetStationStats(ctx context.Context, fmisid int, period string, from, to time.Time) (*weather.Station, []weather.ObservationStats, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) GetForecast(ctx context.Context, lat, lon float64, hours, days int) (*weather.ForecastResponse, error) {
+	panic("not used in this test")
+}
+
+func (f fakeWeatherService) WatchHourlyForecast(lat, lon float64) (<-chan struct{}, func()) {
+	panic("not used in this test")
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"time"
+
+	"wby/internal/weather"
+)
+
+// marineJSON is the latest observation from the nearest wave buoy or
+// mareograph. Fields the station does not measure are null.
+type marineJSON struct {
+	Station          stationJSON `json:"station"`
+	ObservedAt       time.Time   `json:"observed_at"`
+	WaveHeight       *float64    `json:"wave_height"`
+	WaveDirection    *float64    `json:"wave_direction"`
+	WavePeriod       *float64    `json:"wave_period"`
+	WaterTemperature *float64    `json:"water_temperature"`
+	SeaLevel         *float64    `json:"sea_level"`
+}
+
+func toMarineJSON(r *weather.MarineReading) *marineJSON {
+	if r == nil {
+		return nil
+	}
+	return &marineJSON{
+		Station:          stationJSON{Name: r.Station.Name, DistanceKM: r.DistanceKM, fmisid: r.Station.FMISID},
+		ObservedAt:       r.ObservedAt,
+		WaveHeight:       r.WaveHeight,
+		WaveDirection:    r.WaveDirection,
+		WavePeriod:       r.WavePeriod,
+		WaterTemperature: r.WaterTemperature,
+		SeaLevel:         r.SeaLevel,
+	}
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"time"
+
+	"wby/internal/weather"
+)
+
+// metaJSON tells clients how old the response's data is and where each
+// section was loaded from, so stale or degraded responses can be spotted
+// without comparing timestamps across sections.
+type metaJSON struct {
+	ObservationAgeSeconds *int64      `json:"observation_age_seconds"`
+	ForecastFetchedAt     *time.Time  `json:"forecast_fetched_at"`
+	HourlyFetchedAt       *time.Time  `json:"hourly_fetched_at"`
+	GridLat               float64     `json:"grid_lat"`
+	GridLon               float64     `json:"grid_lon"`
+	Sources               sourcesJSON `json:"sources"`
+}
+
+// sourcesJSON holds a weather.Source per section: "cache", "db", "fmi" or
+// "unavailable".
+type sourcesJSON struct {
+	Observation string `json:"observation"`
+	Forecast    string `json:"forecast"`
+	Hourly      string `json:"hourly"`
+	UV          string `json:"uv"`
+}
+
+func toMetaJSON(result *weather.WeatherResponse, obs weather.Observation, now time.Time) *metaJSON {
+	meta := &metaJSON{
+		ForecastFetchedAt: oldestFetchedAt(result.Forecast, func(f weather.DailyForecast) time.Time { return f.FetchedAt }),
+		HourlyFetchedAt:   oldestFetchedAt(result.Hourly, func(f weather.HourlyForecast) time.Time { return f.FetchedAt }),
+		GridLat:           result.Provenance.GridLat,
+		GridLon:           result.Provenance.GridLon,
+		Sources: sourcesJSON{
+			// Observations are only ever read from the store; the fetcher
+			// is what talks to FMI.
+			Observation: string(weather.SourceDB),
+			Forecast:    string(result.Provenance.Forecast),
+			Hourly:      string(result.Provenance.Hourly),
+			UV:          string(result.Provenance.UV),
+		},
+	}
+	if !obs.ObservedAt.IsZero() {
+		age := int64(max(now.Sub(obs.ObservedAt), 0) / time.Second)
+		meta.ObservationAgeSeconds = &age
+	}
+	return meta
+}
+
+// oldestFetchedAt returns the earliest non-zero fetch time in entries, which
+// is what the service's freshness checks compare against, or nil if none is
+// known.
+func oldestFetchedAt[T any](entries []T, fetchedAt func(T) time.Time) *time.Time {
+	var oldest time.Time
+	for _, e := range entries {
+		if t := fetchedAt(e); !t.IsZero() && (oldest.IsZero() || t.Before(oldest)) {
+			oldest = t
+		}
+	}
+	if oldest.IsZero() {
+		return nil
+	}
+	oldest = oldest.UTC()
+	return &oldest
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestGetWeather_Meta(t *testing.T) {
+	now := time.Now()
+	older, newer := now.Add(-40*time.Minute), now.Add(-10*time.Minute)
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{
+			Current: weather.CurrentWeather{
+				Observation: weather.Observation{ObservedAt: now.Add(-90 * time.Second)},
+			},
+			Forecast: []weather.DailyForecast{
+				{Date: now, FetchedAt: newer},
+				{Date: now.AddDate(0, 0, 1), FetchedAt: older},
+			},
+			Hourly: []weather.HourlyForecast{{Time: now, FetchedAt: newer}},
+			Provenance: weather.Provenance{
+				GridLat:  60.17,
+				GridLon:  24.94,
+				Forecast: weather.SourceDB,
+				Hourly:   weather.SourceCache,
+				UV:       weather.SourceUnavailable,
+			},
+		},
+	})
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1712&lon=24.9449", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rr := httptest.NewRecorder()
+		h.getWeather(rr, req)
+		return rr
+	}
+
+	rr := get("")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var body struct {
+		Meta struct {
+			ObservationAgeSeconds *int64            `json:"observation_age_seconds"`
+			ForecastFetchedAt     *time.Time        `json:"forecast_fetched_at"`
+			HourlyFetchedAt       *time.Time        `json:"hourly_fetched_at"`
+			GridLat               float64           `json:"grid_lat"`
+			GridLon               float64           `json:"grid_lon"`
+			Sources               map[string]string `json:"sources"`
+		} `json:"meta"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	meta := body.Meta
+	if meta.ObservationAgeSeconds == nil || *meta.ObservationAgeSeconds < 90 || *meta.ObservationAgeSeconds > 100 {
+		t.Errorf("expected an observation age of about 90s, got %v", meta.ObservationAgeSeconds)
+	}
+	if meta.ForecastFetchedAt == nil || !meta.ForecastFetchedAt.Equal(older) {
+		t.Errorf("expected forecast_fetched_at to be the oldest fetch %v, got %v", older, meta.ForecastFetchedAt)
+	}
+	if meta.HourlyFetchedAt == nil || !meta.HourlyFetchedAt.Equal(newer) {
+		t.Errorf("expected hourly_fetched_at %v, got %v", newer, meta.HourlyFetchedAt)
+	}
+	if meta.GridLat != 60.17 || meta.GridLon != 24.94 {
+		t.Errorf("expected grid 60.17,24.94, got %v,%v", meta.GridLat, meta.GridLon)
+	}
+	wantSources := map[string]string{"observation": "db", "forecast": "db", "hourly": "cache", "uv": "unavailable"}
+	for k, v := range wantSources {
+		if meta.Sources[k] != v {
+			t.Errorf("expected sources.%s=%q, got %q", k, v, meta.Sources[k])
+		}
+	}
+
+	// meta is left out of the ETag, so unchanged data still revalidates.
+	if rr := get(rr.Header().Get("ETag")); rr.Code != http.StatusNotModified {
+		t.Fatalf("expected status 304 for matching ETag, got %d", rr.Code)
+	}
+}
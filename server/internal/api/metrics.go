@@ -0,0 +1,67 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"wby/internal/metrics"
+)
+
+// NewMetricsMiddleware counts requests and records their latency by route,
+// method and status. Routes are the mux patterns (e.g. "GET /v1/weather")
+// rather than raw paths, so path parameters do not blow up cardinality;
+// requests no route matches are labelled "unmatched".
+func NewMetricsMiddleware(reg *metrics.Registry, mux *http.ServeMux) func(http.Handler) http.Handler {
+	requests := reg.Counter("wby_http_requests_total", "HTTP requests by route, method and status.", "route", "method", "status")
+	duration := reg.Histogram("wby_http_request_duration_seconds", "HTTP request latency by route, method and status.", metrics.DefaultBuckets, "route", "method", "status")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			route := "unmatched"
+			if _, pattern := mux.Handler(r); pattern != "" {
+				route = pattern
+			}
+
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+
+			if sw.status == 0 {
+				sw.status = http.StatusOK
+			}
+			status := strconv.Itoa(sw.status)
+			requests.Inc(route, r.Method, status)
+			duration.Observe(time.Since(start).Seconds(), route, r.Method, status)
+		})
+	}
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"wby/internal/metrics"
+)
+
+func TestMetricsMiddleware_CountsByRouteAndStatus(t *testing.T) {
+	reg := metrics.NewRegistry()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/stations/{fmisid}/observations", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, codeNotFound, "station not found")
+	})
+	handler := NewMetricsMiddleware(reg, mux)(mux)
+
+	for _, path := range []string{"/v1/stations/100971/observations", "/v1/stations/101004/observations", "/nope"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := reg.Value("wby_http_requests_total", "GET /v1/stations/{fmisid}/observations", "GET", "404"); got != 2 {
+		t.Fatalf("expected 2 requests counted under the route pattern, got %v", got)
+	}
+	if got := reg.Value("wby_http_requests_total", "unmatched", "GET", "404"); got != 1 {
+		t.Fatalf("expected 1 unmatched request, got %v", got)
+	}
+	if got := reg.Value("wby_http_request_duration_seconds", "GET /v1/stations/{fmisid}/observations", "GET", "404"); got != 2 {
+		t.Fatalf("expected 2 latency observations, got %v", got)
+	}
+}
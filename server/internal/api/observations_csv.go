@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+// csvFlushRows is how many rows are written between flushes, so long
+// histories reach the client as they are written.
+const csvFlushRows = 500
+
+// observationCSVColumns are the fixed leading columns, named like the JSON
+// fields. Extra parameters follow as extra.<name>.
+var observationCSVColumns = []string{
+	"observed_at", "temperature", "wind_speed", "wind_gust", "wind_direction",
+	"humidity", "dew_point", "pressure", "precipitation_1h", "precipitation_intensity",
+	"snow_depth", "visibility", "cloud_cover", "weather_code",
+}
+
+// wantsCSV reports whether the client asked for CSV with format=csv or an
+// Accept header naming text/csv. format=json forces JSON.
+func wantsCSV(r *http.Request) (bool, error) {
+	switch r.URL.Query().Get("format") {
+	case "csv":
+		return true, nil
+	case "json":
+		return false, nil
+	case "":
+		return strings.Contains(r.Header.Get("Accept"), "text/csv"), nil
+	default:
+		return false, errors.New("invalid format parameter")
+	}
+}
+
+// writeObservationsCSV streams observations as CSV, one row each, with
+// empty cells for missing values.
+func writeObservationsCSV(w http.ResponseWriter, r *http.Request, station weather.Station, from, to time.Time, observations []weather.Observation) {
+	var extras []string
+	for _, o := range observations {
+		for name := range o.ExtraNumericParams {
+			if !slices.Contains(extras, name) {
+				extras = append(extras, name)
+			}
+		}
+	}
+	slices.Sort(extras)
+
+	filename := fmt.Sprintf("observations_%d_%s_%s.csv", station.FMISID, from.Format("20060102T1504Z"), to.Format("20060102T1504Z"))
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	rc := http.NewResponseController(w)
+	cw := csv.NewWriter(w)
+	header := slices.Clone(observationCSVColumns)
+	for _, name := range extras {
+		header = append(header, "extra."+name)
+	}
+	cw.Write(header)
+
+	row := make([]string, len(header))
+	for i, o := range observations {
+		row[0] = o.ObservedAt.UTC().Format(time.RFC3339)
+		for j, v := range []*float64{
+			o.Temperature, o.WindSpeed, o.WindGust, o.WindDir,
+			o.Humidity, o.DewPoint, o.Pressure, o.Precip1h, o.PrecipIntensity,
+			o.SnowDepth, o.Visibility, o.TotalCloudCover, o.WeatherCode,
+		} {
+			row[1+j] = csvFloat(v)
+		}
+		for j, name := range extras {
+			row[len(observationCSVColumns)+j] = ""
+			if v, ok := o.ExtraNumericParams[name]; ok {
+				row[len(observationCSVColumns)+j] = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+		}
+		cw.Write(row)
+
+		if (i+1)%csvFlushRows == 0 {
+			cw.Flush()
+			if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				logging.FromContext(r.Context()).Warn("flush observations csv failed", "err", err)
+				return
+			}
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		logging.FromContext(r.Context()).Warn("write observations csv failed", "err", err, "fmisid", station.FMISID)
+	}
+}
+
+func csvFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
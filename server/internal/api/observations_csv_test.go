@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestWriteObservationsCSV(t *testing.T) {
+	from := time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)
+	temp, gust := -3.5, 12.25
+	observations := []weather.Observation{
+		{ObservedAt: from.Add(10 * time.Minute), Temperature: &temp, ExtraNumericParams: map[string]float64{"ri_10min": 0.2}},
+		{ObservedAt: from.Add(20 * time.Minute), WindGust: &gust, ExtraNumericParams: map[string]float64{"n_man": 8}},
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/stations/100971/observations", nil)
+	writeObservationsCSV(rr, req, weather.Station{FMISID: 100971}, from, from.Add(24*time.Hour), observations)
+
+	if got := rr.Header().Get("Content-Disposition"); got != `attachment; filename="observations_100971_20260114T1200Z_20260115T1200Z.csv"` {
+		t.Errorf("unexpected Content-Disposition %q", got)
+	}
+	rows, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected header and 2 rows, got %d", len(rows))
+	}
+	header := strings.Join(rows[0], ",")
+	if !strings.HasPrefix(header, "observed_at,temperature,wind_speed,wind_gust,") || !strings.HasSuffix(header, ",weather_code,extra.n_man,extra.ri_10min") {
+		t.Errorf("unexpected header %q", header)
+	}
+	want := []string{
+		"2026-01-14T12:10:00Z,-3.5,,,,,,,,,,,,,,0.2",
+		"2026-01-14T12:20:00Z,,,12.25,,,,,,,,,,,8,",
+	}
+	for i, w := range want {
+		if got := strings.Join(rows[i+1], ","); got != w {
+			t.Errorf("row %d = %q, want %q", i+1, got, w)
+		}
+	}
+}
+
+func TestGetStationObservations_CSV(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(&stationsServiceStub{stations: []weather.Station{{FMISID: 100971}}}).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/stations/100971/observations", nil)
+	req.Header.Set("Accept", "text/csv")
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected CSV for Accept: text/csv, got %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if !strings.HasPrefix(rr.Body.String(), "observed_at,") {
+		t.Fatalf("expected a CSV header, got %q", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/stations/100971/observations?format=csv", nil))
+	if !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected CSV for format=csv, got %q", rr.Header().Get("Content-Type"))
+	}
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/stations/100971/observations?format=xml", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for an unknown format, got %d", rr.Code)
+	}
+}
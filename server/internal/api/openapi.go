@@ -0,0 +1,511 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// The OpenAPI document is generated from the response types in this
+// package, so adding or changing a JSON field updates it automatically.
+// Pointer fields are nullable and omitempty fields are optional; everything
+// else is required. openapi_test.go checks every route in RegisterRoutes
+// is described here.
+
+type apiParam struct {
+	name        string
+	in          string // "query" or "path"
+	typ         string
+	description string
+	required    bool
+	enum        []string
+}
+
+type apiOperation struct {
+	pattern     string
+	summary     string
+	params      []apiParam
+	requestBody any
+	status      int
+	response    any // zero value of the response type; nil for no body
+	contentType string
+}
+
+var (
+	latParam   = apiParam{name: "lat", in: "query", typ: "number", description: "Latitude in decimal degrees.", required: true}
+	lonParam   = apiParam{name: "lon", in: "query", typ: "number", description: "Longitude in decimal degrees.", required: true}
+	hoursParam = apiParam{name: "hours", in: "query", typ: "integer", description: "Number of hourly forecast entries; defaults to 12 and is capped by the server."}
+	daysParam  = apiParam{name: "days", in: "query", typ: "integer", description: "Number of daily forecast entries, 1-15; defaults to 10."}
+	unitsParam = apiParam{name: "units", in: "query", typ: "string", description: "Unit system for the response.", enum: []string{"metric", "imperial"}}
+	moonParam  = apiParam{name: "moon", in: "query", typ: "boolean", description: "false omits moon_phase, moon_phase_name and moon_illumination from daily entries."}
+	langParam  = apiParam{name: "lang", in: "query", typ: "string", description: "Adds a localized symbol_description to forecast entries.", enum: []string{"fi", "sv", "en"}}
+	bboxParam  = apiParam{name: "bbox", in: "query", typ: "string", description: "Bounding box as minLon,minLat,maxLon,maxLat."}
+)
+
+// weatherLocationParams accept a place name in place of lat and lon.
+var weatherLocationParams = []apiParam{
+	{name: "lat", in: "query", typ: "number", description: "Latitude in decimal degrees; required unless place is given."},
+	{name: "lon", in: "query", typ: "number", description: "Longitude in decimal degrees; required unless place is given."},
+	{name: "place", in: "query", typ: "string", description: "Place name resolved server-side when lat and lon are absent. Unknown or ambiguous names return 400 with code unknown_place and suggestions."},
+}
+
+var weatherParams = slices.Concat(weatherLocationParams, []apiParam{
+	hoursParam, daysParam, unitsParam, langParam, moonParam,
+	{name: "compact_nulls", in: "query", typ: "boolean", description: "Leave out keys whose value would be null instead of sending them as null. The default sends every key."},
+	{name: "blend", in: "query", typ: "boolean", description: "Fill each current field from the closest of up to 5 stations within 25 km that reported in the last hour; station.contributors lists which station supplied which fields."},
+	{name: "blend_custom", in: "query", typ: "boolean", description: "Blend the signing client's nearby personal weather station into current conditions."},
+	{name: "include", in: "query", typ: "string", description: "Comma-separated sections. environment and road add optional sections; naming any of current, hourly, daily, alerts, air_quality or marine returns only those core sections and skips loading the rest.", enum: []string{"current", "hourly", "daily", "alerts", "air_quality", "marine", "environment", "road"}},
+})
+
+var apiOperations = []apiOperation{
+	{
+		pattern: "GET /v1/weather",
+		summary: "Current conditions at the nearest station with hourly and daily forecasts. Accept: application/x-protobuf returns the wby.v1.Weather message from internal/api/pb/weather.proto instead of JSON. Before the first station fetch it returns 503 with code warming_up and a Retry-After header.",
+		params: slices.Concat(weatherParams, []apiParam{
+			{name: "fields", in: "query", typ: "string", description: "Comma-separated dotted paths to return, e.g. current.temperature,hourly.symbol,daily.high; hourly and daily alias hourly_forecast and daily_forecast. observed_at, date and time are always kept. Empty returns everything."},
+		}),
+		response: weatherJSON{},
+	},
+	{
+		pattern:  "GET /v1/weather/compact",
+		summary:  "Small payload for widgets: current temperature, feels-like and symbol with the next 6 hours of temperature and symbol. Times are unix seconds and values are rounded to one decimal.",
+		params:   slices.Concat(weatherLocationParams, []apiParam{unitsParam}),
+		response: compactWeatherJSON{},
+	},
+	{
+		pattern: "GET /v1/weather/ws",
+		summary: "WebSocket upgrade. Sends the GET /v1/weather payload as a text message, then again whenever the hourly forecast is refreshed or the nearest station reports a newer observation.",
+		params:  weatherParams,
+		status:  http.StatusSwitchingProtocols,
+	},
+	{
+		pattern:  "GET /v1/forecast",
+		summary:  "Hourly and daily forecast only; works without station observations.",
+		params:   []apiParam{latParam, lonParam, hoursParam, daysParam, unitsParam, langParam, moonParam},
+		response: forecastJSON{},
+	},
+	{
+		pattern:  "GET /v1/stations",
+		summary:  "Known observation stations.",
+		params:   []apiParam{bboxParam},
+		response: stationListJSON{},
+	},
+	{
+		pattern: "GET /v1/places",
+		summary: "Finnish places matching a name: exact matches first, then prefix and fuzzy matches. At most 10 results.",
+		params: []apiParam{
+			{name: "q", in: "query", typ: "string", description: "Place name or the start of one.", required: true},
+		},
+		response: []placeJSON{},
+	},
+	{
+		pattern:  "GET /v1/symbols",
+		summary:  "Every forecast symbol code with its condition slug (the condition field of forecast entries), whether it has separate day and night icons, and a short description. Codes outside this table have condition unknown.",
+		params:   []apiParam{{name: "lang", in: "query", typ: "string", description: "Description language; defaults to en.", enum: []string{"fi", "sv", "en"}}},
+		response: []symbolJSON{},
+	},
+	{
+		pattern: "GET /v1/stations/nearby",
+		summary: "Stations closest to a point, nearest first, with the time of each station's latest observation.",
+		params: []apiParam{
+			latParam,
+			lonParam,
+			{name: "n", in: "query", typ: "integer", description: "Number of stations; defaults to 5, at most 20."},
+		},
+		response: []nearbyStationJSON{},
+	},
+	{
+		pattern: "GET /v1/stations/{fmisid}/observations",
+		summary: "Raw observations of one station, oldest first.",
+		params: []apiParam{
+			{name: "fmisid", in: "path", typ: "integer", description: "FMI station ID.", required: true},
+			{name: "from", in: "query", typ: "string", description: "RFC3339 start; defaults to 24 hours before to."},
+			{name: "to", in: "query", typ: "string", description: "RFC3339 end; defaults to now. At most 7 days after from."},
+			{name: "format", in: "query", typ: "string", description: "csv streams text/csv with one row per observation and extra parameters as extra.<name> columns; also selected by Accept: text/csv.", enum: []string{"json", "csv"}},
+		},
+		response: stationObservationsJSON{},
+	},
+	{
+		pattern: "GET /v1/stations/{fmisid}/stats",
+		summary: "Daily or monthly temperature, precipitation and wind statistics of one station, bucketed in Europe/Helsinki time.",
+		params: []apiParam{
+			{name: "fmisid", in: "path", typ: "integer", description: "FMI station ID.", required: true},
+			{name: "period", in: "query", typ: "string", description: "Bucket size; defaults to day.", enum: []string{"day", "month"}},
+			{name: "from", in: "query", typ: "string", description: "Local date (2006-01-02) or RFC3339 start; defaults to 30 days (day) or 12 months (month) before to."},
+			{name: "to", in: "query", typ: "string", description: "Local date or RFC3339 end, exclusive; defaults to now. At most 366 days (day) or 5 years (month) after from."},
+		},
+		response: stationStatsJSON{},
+	},
+	{
+		pattern: "GET /v1/stations/{fmisid}/stream",
+		summary: "Server-sent events of a station's observations: the latest stored one, then each newer one as it is ingested. Each observation event carries the same JSON as an entry of the observations route.",
+		params: []apiParam{
+			{name: "fmisid", in: "path", typ: "integer", description: "FMI station ID.", required: true},
+		},
+		contentType: "text/event-stream",
+	},
+	{
+		pattern: "GET /v1/map/temperature",
+		summary: "Interpolated temperature overlay image.",
+		params: []apiParam{
+			{name: "bbox", in: "query", typ: "string", description: "Bounding box as minLon,minLat,maxLon,maxLat.", required: true},
+			{name: "width", in: "query", typ: "integer", description: "Image width in pixels.", required: true},
+			{name: "height", in: "query", typ: "integer", description: "Image height in pixels.", required: true},
+		},
+		contentType: "image/png",
+	},
+	{
+		pattern:  "GET /v1/map/temperature/samples",
+		summary:  "Latest station temperatures used for the map overlay.",
+		response: temperatureSamplesJSON{},
+	},
+	{
+		pattern: "GET /v1/lightning",
+		summary: "Recent lightning strikes near a location, oldest first, and the estimated motion of the storm producing them, null when there are too few strikes to track. Strikes are only ingested on days with a forecast thunderstorm risk.",
+		params: []apiParam{latParam, lonParam,
+			{name: "radius_km", in: "query", typ: "number", description: "Search radius in km; default 50, at most 300."},
+			{name: "hours", in: "query", typ: "integer", description: "How many hours back to look; default 1, at most 24."},
+		},
+		response: lightningJSON{},
+	},
+	{
+		pattern: "GET /v1/radar",
+		summary: "FMI precipitation radar (dBZ composite) image. The bbox is widened to whole tiles, given in X-Radar-BBox, and X-Data-Time gives the composite time; upstream failures return 502 with a JSON error.",
+		params: []apiParam{
+			{name: "bbox", in: "query", typ: "string", description: "Bounding box as minLon,minLat,maxLon,maxLat.", required: true},
+			{name: "time", in: "query", typ: "string", description: "RFC3339 time; clamped to the latest composite not after it. Defaults to the latest."},
+			{name: "width", in: "query", typ: "integer", description: "Image width in pixels, 64-1024; default 512."},
+			{name: "height", in: "query", typ: "integer", description: "Image height in pixels, 64-1024; default 512."},
+		},
+		contentType: "image/png",
+	},
+	{
+		pattern: "GET /v1/climate-normals",
+		summary: "1991-2020 climate normals for the nearest station.",
+		params: []apiParam{latParam, lonParam,
+			{name: "current_temp", in: "query", typ: "number", description: "Current temperature to compare against today's normal."},
+		},
+		response: climateNormalsJSON{},
+	},
+	{
+		pattern: "GET /v1/leaderboard",
+		summary: "Warmest, coldest, windiest and wettest stations.",
+		params: []apiParam{latParam, lonParam,
+			{name: "timeframe", in: "query", typ: "string", description: "Aggregation window.", enum: []string{"now"}},
+		},
+		response: leaderboardJSON{},
+	},
+	{
+		pattern:  "GET /v1/stargazing",
+		summary:  "Stargazing conditions for the coming nights.",
+		params:   []apiParam{latParam, lonParam},
+		response: stargazingJSON{},
+	},
+	{
+		pattern: "POST /v1/observations/custom",
+		summary: "Ingest a personal weather station reading for the signing client. Ecowitt and WeatherFlow devices upload to the ingest_path of a registered station instead.",
+		params: []apiParam{
+			{name: "format", in: "query", typ: "string", description: "Payload format; only native is accepted here.", enum: []string{"native"}},
+		},
+		requestBody: customObservationRequestJSON{},
+		status:      http.StatusCreated,
+		response:    customObservationResponseJSON{},
+	},
+	{
+		pattern:     "POST /v1/observations/custom/stations",
+		summary:     "Register a personal station whose device uploads Ecowitt or WeatherFlow payloads and get its ingest token. The token is only returned here; registering the station again replaces it.",
+		requestBody: customStationRequestJSON{},
+		status:      http.StatusCreated,
+		response:    customStationResponseJSON{},
+	},
+	{
+		pattern: "POST /ingest/{token}",
+		summary: "Upload from a registered station's device: an Ecowitt custom-server form post or a WeatherFlow obs_st JSON message, in the format the station was registered with. The token authenticates the station; not signed.",
+		params: []apiParam{
+			{name: "token", in: "path", typ: "string", description: "Ingest token from station registration.", required: true},
+		},
+		status:   http.StatusCreated,
+		response: customObservationResponseJSON{},
+	},
+	{
+		pattern:     "POST /v1/subscriptions",
+		summary:     "Subscribe a webhook to significant forecast changes.",
+		requestBody: subscriptionRequestJSON{},
+		status:      http.StatusCreated,
+		response:    subscriptionJSON{},
+	},
+	{
+		pattern: "DELETE /v1/subscriptions/{id}",
+		summary: "Delete one of the signing client's subscriptions.",
+		params: []apiParam{
+			{name: "id", in: "path", typ: "integer", description: "Subscription ID.", required: true},
+		},
+		status: http.StatusNoContent,
+	},
+	{
+		pattern: "GET /v1/admin/freshness",
+		summary: "Configured cache TTL and maximum age per data type.",
+		response: struct {
+			Freshness map[string]freshnessWindowJSON `json:"freshness"`
+		}{},
+	},
+	{
+		pattern: "POST /v1/admin/refresh",
+		summary: "Fetches observations immediately and reports what was stored; signed with an admin client secret. Returns 409 while a fetch is already running.",
+		params: []apiParam{
+			{name: "scope", in: "query", typ: "string", description: "forecasts also drops the cached daily, hourly and UV forecasts.", enum: []string{"observations", "forecasts"}},
+		},
+		response: refreshJSON{},
+	},
+	{
+		pattern:     "POST /v1/graphql",
+		summary:     "GraphQL queries weather, station and stations over the same data as the REST routes; introspection is enabled.",
+		requestBody: graphqlRequestJSON{},
+		response:    graphqlResponseJSON{},
+	},
+	{
+		pattern: "GET /v1/graphql",
+		summary: "GraphQL query passed as URL parameters.",
+		params: []apiParam{
+			{name: "query", in: "query", typ: "string", description: "GraphQL query document.", required: true},
+			{name: "variables", in: "query", typ: "string", description: "JSON-encoded variables."},
+			{name: "operationName", in: "query", typ: "string", description: "Operation to run when the document has several."},
+		},
+		response: graphqlResponseJSON{},
+	},
+	{
+		pattern: "GET /v1/openapi.json",
+		summary: "This document.",
+		response: struct {
+			OpenAPI string `json:"openapi"`
+		}{},
+	},
+	{
+		pattern: "GET /health",
+		summary: "Liveness check; not signed.",
+		response: struct {
+			Status string `json:"status"`
+		}{},
+	},
+	{
+		pattern:  "GET /health/ready",
+		summary:  "Readiness check of the database and observation fetcher; 503 with the failing checks when not ready. Not signed.",
+		response: readinessJSON{},
+	},
+	{
+		pattern:  "GET /version",
+		summary:  "Version, git commit, build date and Go version of the running build; \"dev\" where not set at link time. Not signed.",
+		response: versionJSON{},
+	},
+}
+
+// graphqlRequestJSON and graphqlResponseJSON describe the GraphQL envelope;
+// the data shape depends on the query.
+type graphqlRequestJSON struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+type graphqlResponseJSON struct {
+	Data   map[string]any `json:"data,omitempty"`
+	Errors []struct {
+		Message string `json:"message"`
+		Path    []any  `json:"path,omitempty"`
+	} `json:"errors,omitempty"`
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  []byte
+)
+
+func (h *Handler) getOpenAPI(w http.ResponseWriter, r *http.Request) {
+	openAPIOnce.Do(func() {
+		openAPIDoc, _ = json.Marshal(buildOpenAPI(apiOperations))
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write(openAPIDoc)
+}
+
+func buildOpenAPI(ops []apiOperation) map[string]any {
+	g := schemaGenerator{components: map[string]any{}}
+	errorRef := g.schema(reflect.TypeFor[errorJSON]())
+
+	paths := map[string]map[string]any{}
+	for _, op := range ops {
+		method, path, _ := strings.Cut(op.pattern, " ")
+		operation := map[string]any{"summary": op.summary}
+
+		var params []map[string]any
+		for _, p := range op.params {
+			schema := map[string]any{"type": p.typ}
+			if len(p.enum) > 0 {
+				schema["enum"] = p.enum
+			}
+			params = append(params, map[string]any{
+				"name":        p.name,
+				"in":          p.in,
+				"description": p.description,
+				"required":    p.required,
+				"schema":      schema,
+			})
+		}
+		if params != nil {
+			operation["parameters"] = params
+		}
+		if op.requestBody != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(op.requestBody))},
+				},
+			}
+		}
+
+		status := op.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := map[string]any{"description": http.StatusText(status)}
+		switch {
+		case op.contentType != "":
+			schema := map[string]any{"type": "string"}
+			if strings.HasPrefix(op.contentType, "image/") {
+				schema["format"] = "binary"
+			}
+			success["content"] = map[string]any{op.contentType: map[string]any{"schema": schema}}
+		case op.response != nil:
+			success["content"] = map[string]any{
+				"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(op.response))},
+			}
+		}
+		operation["responses"] = map[string]any{
+			strconv.Itoa(status): success,
+			"default": map[string]any{
+				"description": "Error. code, message and request_id are top-level fields; error repeats message for older clients until the next release.",
+				"content":     map[string]any{"application/json": map[string]any{"schema": errorRef}},
+			},
+		}
+
+		if paths[path] == nil {
+			paths[path] = map[string]any{}
+		}
+		paths[path][strings.ToLower(method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.1.0",
+		"info": map[string]any{
+			"title":       "wby API",
+			"version":     "1",
+			"description": "Finnish weather from FMI open data. Every /v1 request must be signed with X-Client-ID, X-Timestamp and X-Signature headers; requests other than GET and HEAD also sign the SHA-256 of their body.",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": g.components},
+	}
+}
+
+// schemaGenerator turns Go types into JSON Schema following encoding/json
+// rules. Named structs become shared components referenced by $ref.
+type schemaGenerator struct {
+	components map[string]any
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Pointer {
+		return nullable(g.schema(t.Elem()))
+	}
+	if t == reflect.TypeFor[time.Time]() {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := componentName(t.Name())
+		if _, ok := g.components[name]; !ok {
+			g.components[name] = nil // placeholder for recursive types
+			g.components[name] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]any{}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for f := range t.Fields() {
+			tag := f.Tag.Get("json")
+			if tag == "-" || !f.IsExported() && !f.Anonymous {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				addFields(f.Type)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			properties[name] = g.schema(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	addFields(t)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if required != nil {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// nullable allows null in addition to the given schema.
+func nullable(schema map[string]any) map[string]any {
+	if typ, ok := schema["type"].(string); ok {
+		out := make(map[string]any, len(schema))
+		for k, v := range schema {
+			out[k] = v
+		}
+		out["type"] = []string{typ, "null"}
+		return out
+	}
+	return map[string]any{"oneOf": []any{schema, map[string]any{"type": "null"}}}
+}
+
+// componentName maps Go type names such as dailyForecastJSON to schema
+// names such as DailyForecast.
+func componentName(goName string) string {
+	name := strings.TrimSuffix(goName, "JSON")
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
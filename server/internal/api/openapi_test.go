@@ -0,0 +1,121 @@
+package api
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// registeredPatterns reads the route patterns RegisterRoutes passes to
+// mux.HandleFunc, so a new route cannot be added without documenting it.
+func registeredPatterns(t *testing.T) []string {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "handler.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse handler.go: %v", err)
+	}
+	var patterns []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "RegisterRoutes" {
+			return true
+		}
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				pattern, _ := strconv.Unquote(lit.Value)
+				patterns = append(patterns, pattern)
+			}
+			return true
+		})
+		return false
+	})
+	if len(patterns) == 0 {
+		t.Fatal("found no routes in RegisterRoutes")
+	}
+	return patterns
+}
+
+func TestOpenAPI_DocumentsEveryRegisteredRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(weatherServiceStub{}).RegisterRoutes(mux)
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Fatalf("unexpected openapi version %q", doc.OpenAPI)
+	}
+
+	for _, pattern := range registeredPatterns(t) {
+		method, path, _ := strings.Cut(pattern, " ")
+		if _, ok := doc.Paths[path][strings.ToLower(method)]; !ok {
+			t.Errorf("route %q is not documented", pattern)
+		}
+	}
+	for _, op := range apiOperations {
+		method, path, _ := strings.Cut(op.pattern, " ")
+		path = strings.NewReplacer("{fmisid}", "100971", "{id}", "1").Replace(path)
+		if _, pattern := mux.Handler(httptest.NewRequest(method, path, nil)); pattern != op.pattern {
+			t.Errorf("documented route %q is not registered", op.pattern)
+		}
+	}
+}
+
+func TestOpenAPI_WeatherSchemaMarksNullability(t *testing.T) {
+	doc := buildOpenAPI(apiOperations)
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+	var parsed struct {
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Type any    `json:"type"`
+					Ref  string `json:"$ref"`
+				} `json:"properties"`
+				Required []string `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+
+	weather, ok := parsed.Components.Schemas["Weather"]
+	if !ok {
+		t.Fatal("expected a Weather schema")
+	}
+	if weather.Properties["station"].Ref != "#/components/schemas/Station" {
+		t.Fatalf("expected station to reference the Station schema, got %+v", weather.Properties["station"])
+	}
+	if !slices.Contains(weather.Required, "daily_forecast") || slices.Contains(weather.Required, "synoptic_summary") {
+		t.Fatalf("expected omitempty fields to be optional, got required %v", weather.Required)
+	}
+
+	current := parsed.Components.Schemas["Current"]
+	temp, _ := current.Properties["temperature"].Type.([]any)
+	if len(temp) != 2 || temp[0] != "number" || temp[1] != "null" {
+		t.Fatalf("expected current.temperature to be a nullable number, got %v", current.Properties["temperature"].Type)
+	}
+}
@@ -0,0 +1,5 @@
+// Package pb holds the Protocol Buffers form of API responses, generated
+// from weather.proto by protoc-gen-go.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative weather.proto
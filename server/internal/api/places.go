@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+type placeJSON struct {
+	Name   string  `json:"name"`
+	Region string  `json:"region"`
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	GeoID  *int    `json:"geoid"`
+}
+
+func (h *Handler) getPlaces(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	places, err := h.service.SearchPlaces(r.Context(), q)
+	if err != nil {
+		if errors.Is(err, weather.ErrInvalidPlaceQuery) {
+			writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid q parameter")
+			return
+		}
+		logging.FromContext(r.Context()).Error("search places failed", "err", err, "q", q)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	resp := make([]placeJSON, len(places))
+	for i, p := range places {
+		resp[i] = toPlaceJSON(p)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func toPlaceJSON(p weather.Place) placeJSON {
+	return placeJSON{Name: p.Name, Region: p.Region, Lat: p.Lat, Lon: p.Lon, GeoID: p.GeoID}
+}
+
+// unknownPlaceJSON is the 400 body for a place name that does not resolve
+// to exactly one place: the usual error body plus the closest matches.
+type unknownPlaceJSON struct {
+	errorJSON
+	Suggestions []placeJSON `json:"suggestions"`
+}
+
+// resolveWeatherPlace sets q's coordinates from its place parameter, if
+// any. It writes the error response and returns false when the name is
+// unknown or ambiguous.
+func (h *Handler) resolveWeatherPlace(w http.ResponseWriter, r *http.Request, q *weatherQuery) bool {
+	if q.place == "" {
+		return true
+	}
+	place, suggestions, err := h.service.ResolvePlace(r.Context(), q.place)
+	if err != nil {
+		if errors.Is(err, weather.ErrInvalidPlaceQuery) {
+			writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid place parameter")
+			return false
+		}
+		logging.FromContext(r.Context()).Error("resolve place failed", "err", err, "place", q.place)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return false
+	}
+	if place == nil {
+		body := unknownPlaceJSON{
+			errorJSON:   newErrorJSON(w, codeUnknownPlace, "unknown or ambiguous place"),
+			Suggestions: make([]placeJSON, len(suggestions)),
+		}
+		for i, p := range suggestions {
+			body.Suggestions[i] = toPlaceJSON(p)
+		}
+		writeErrorBody(w, http.StatusBadRequest, body)
+		return false
+	}
+	q.lat, q.lon = place.Lat, place.Lon
+	q.resolvedPlace = place
+	return true
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wby/internal/weather"
+)
+
+type placesServiceStub struct {
+	weatherServiceStub
+	places []weather.Place
+}
+
+func (s placesServiceStub) SearchPlaces(ctx context.Context, q string) ([]weather.Place, error) {
+	if strings.TrimSpace(q) == "" {
+		return nil, weather.ErrInvalidPlaceQuery
+	}
+	return s.places, nil
+}
+
+func (s placesServiceStub) ResolvePlace(ctx context.Context, name string) (*weather.Place, []weather.Place, error) {
+	for _, p := range s.places {
+		if strings.EqualFold(p.Name, name) {
+			return &p, nil, nil
+		}
+	}
+	return nil, s.places, nil
+}
+
+func TestGetPlaces(t *testing.T) {
+	geoid := 634963
+	h := NewHandler(placesServiceStub{places: []weather.Place{
+		{Name: "Tampere", Region: "Pirkanmaa", Lat: 61.4978, Lon: 23.761, GeoID: &geoid},
+		{Name: "Tammisaari", Region: "Uusimaa", Lat: 59.975, Lon: 23.436},
+	}})
+
+	rr := httptest.NewRecorder()
+	h.getPlaces(rr, httptest.NewRequest(http.MethodGet, "/v1/places?q=tam", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp []map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp) != 2 || resp[0]["name"] != "Tampere" || resp[0]["geoid"] != 634963.0 || resp[1]["geoid"] != nil {
+		t.Fatalf("unexpected response %v", resp)
+	}
+
+	rr = httptest.NewRecorder()
+	h.getPlaces(rr, httptest.NewRequest(http.MethodGet, "/v1/places?q=+", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for an empty query, got %d", rr.Code)
+	}
+}
+
+func TestGetWeatherByPlace(t *testing.T) {
+	h := NewHandler(placesServiceStub{
+		weatherServiceStub: weatherServiceStub{weather: &weather.WeatherResponse{}},
+		places: []weather.Place{
+			{Name: "Tampere", Region: "Pirkanmaa", Lat: 61.4978, Lon: 23.761},
+			{Name: "Tammisaari", Region: "Uusimaa", Lat: 59.975, Lon: 23.436},
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?place=tampere", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body)
+	}
+	var resp struct {
+		Place *placeJSON `json:"place"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Place == nil || resp.Place.Name != "Tampere" || resp.Place.Lat != 61.4978 || resp.Place.Lon != 23.761 {
+		t.Fatalf("unexpected place %+v", resp.Place)
+	}
+
+	rr = httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?place=tam", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for an unknown place, got %d", rr.Code)
+	}
+	var unknown unknownPlaceJSON
+	if err := json.Unmarshal(rr.Body.Bytes(), &unknown); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if unknown.Code != "unknown_place" || len(unknown.Suggestions) != 2 || unknown.Suggestions[0].Name != "Tampere" {
+		t.Fatalf("unexpected error response %+v", unknown)
+	}
+
+	// Coordinates win over a place name.
+	rr = httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94&place=tam", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with coordinates, got %d", rr.Code)
+	}
+	if strings.Contains(rr.Body.String(), `"place"`) {
+		t.Fatalf("expected no place block with coordinates: %s", rr.Body)
+	}
+}
@@ -0,0 +1,371 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"wby/internal/api/pb"
+)
+
+// protobufContentType is served to clients whose Accept header names it;
+// JSON stays the default.
+const protobufContentType = "application/x-protobuf"
+
+func wantsProtobuf(accept string) bool {
+	return strings.Contains(accept, protobufContentType)
+}
+
+// marshalProtobuf encodes m with map entries in key order, so the same
+// response always has the same bytes and ETag.
+func marshalProtobuf(m proto.Message) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(m)
+}
+
+// The converters below copy the JSON response types into their
+// weather.proto counterparts field for field.
+
+func timestampPB(t time.Time) *timestamppb.Timestamp {
+	return timestamppb.New(t)
+}
+
+func optionalTimestampPB(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestampPB(*t)
+}
+
+func int64PB(v *int) *int64 {
+	if v == nil {
+		return nil
+	}
+	n := int64(*v)
+	return &n
+}
+
+func weatherPB(v *weatherJSON) *pb.Weather {
+	if v == nil {
+		return nil
+	}
+	m := &pb.Weather{
+		Units:           v.Units,
+		Station:         stationPB(v.Station),
+		Current:         currentPB(v.Current),
+		Timezone:        v.Timezone,
+		FogAdvisory:     fogAdvisoryPB(v.FogAdvisory),
+		SynopticSummary: v.SynopticSummary,
+		CustomStation:   customStationPB(v.CustomStation),
+		Environment:     environmentPB(v.Environment),
+		AirQuality:      airQualityPB(v.AirQuality),
+		Marine:          marinePB(v.Marine),
+		Road:            roadPB(v.Road),
+		Place:           placePB(v.Place),
+		Meta:            metaPB(v.Meta),
+	}
+	for _, x := range v.Hourly {
+		m.HourlyForecast = append(m.HourlyForecast, hourlyForecastPB(x))
+	}
+	for _, x := range v.Forecast {
+		m.DailyForecast = append(m.DailyForecast, dailyForecastPB(x))
+	}
+	for _, x := range v.Alerts {
+		m.Alerts = append(m.Alerts, alertPB(x))
+	}
+	for _, x := range v.HomeSensors {
+		m.HomeSensors = append(m.HomeSensors, homeSensorPB(x))
+	}
+	return m
+}
+
+func stationPB(v stationJSON) *pb.Station {
+	m := &pb.Station{
+		Name:       v.Name,
+		DistanceKm: v.DistanceKM,
+		ElevationM: v.ElevationM,
+		Type:       v.Type,
+		Region:     v.Region,
+	}
+	for _, c := range v.Contributors {
+		m.Contributors = append(m.Contributors, &pb.StationContributor{
+			Fmisid:     int64(c.FMISID),
+			Name:       c.Name,
+			DistanceKm: c.DistanceKM,
+			ObservedAt: timestampPB(c.ObservedAt),
+			Fields:     c.Fields,
+		})
+	}
+	return m
+}
+
+func currentPB(v currentJSON) *pb.Current {
+	return &pb.Current{
+		Temperature:            v.Temperature,
+		FeelsLike:              v.FeelsLike,
+		WindSpeed:              v.WindSpeed,
+		WindGust:               v.WindGust,
+		WindDirection:          v.WindDir,
+		Humidity:               v.Humidity,
+		DewPoint:               v.DewPoint,
+		Pressure:               v.Pressure,
+		Precipitation_1H:       v.Precip1h,
+		PrecipitationIntensity: v.PrecipIntensity,
+		SnowDepth:              v.SnowDepth,
+		Visibility:             v.Visibility,
+		CloudCover:             v.CloudCover,
+		WeatherCode:            v.WeatherCode,
+		Condition:              v.Condition,
+		TempAnomaly:            v.TempAnomaly,
+		Extra:                  v.Extra,
+		ObservedAt:             timestampPB(v.ObservedAt),
+	}
+}
+
+func hourlyForecastPB(v hourlyForecastJSON) *pb.HourlyForecast {
+	return &pb.HourlyForecast{
+		Time:                     timestampPB(v.Time),
+		Temperature:              v.Temperature,
+		TemperatureRaw:           v.TemperatureRaw,
+		WindSpeed:                v.WindSpeed,
+		WindDirection:            v.WindDir,
+		Humidity:                 v.Humidity,
+		Precipitation_1H:         v.Precip1h,
+		Symbol:                   v.Symbol,
+		SymbolDescription:        v.SymbolDescription,
+		Condition:                v.Condition,
+		UvCumulated:              v.UVCumulated,
+		UvIndex:                  v.UVIndex,
+		CloudCover:               v.CloudCover,
+		FogIntensity:             v.FogIntensity,
+		PrecipitationProbability: v.PoP,
+		FeelsLike:                v.FeelsLike,
+		WindGust:                 v.WindGust,
+		Pressure:                 v.Pressure,
+		DewPoint:                 v.DewPoint,
+	}
+}
+
+func dailyForecastPB(v dailyForecastJSON) *pb.DailyForecast {
+	return &pb.DailyForecast{
+		Date:                           v.Date,
+		High:                           v.High,
+		Low:                            v.Low,
+		TemperatureAvg:                 v.TempAvg,
+		HighRaw:                        v.HighRaw,
+		LowRaw:                         v.LowRaw,
+		TemperatureAvgRaw:              v.TempAvgRaw,
+		Symbol:                         v.Symbol,
+		SymbolDescription:              v.SymbolDescription,
+		Condition:                      v.Condition,
+		WindSpeedAvg:                   v.WindSpeed,
+		WindDirectionAvg:               v.WindDir,
+		HumidityAvg:                    v.Humidity,
+		PrecipitationMm:                v.PrecipMM,
+		Precipitation_1HSum:            v.Precip1hSum,
+		PrecipHoursCounted:             int64(v.PrecipHoursCounted),
+		SnowAccumulationMm:             v.SnowAccumulationMM,
+		DewPointAvg:                    v.DewPointAvg,
+		FogIntensityAvg:                v.FogIntensityAvg,
+		FrostProbabilityAvg:            v.FrostProbabilityAvg,
+		SevereFrostProbabilityAvg:      v.SevereFrostProbabilityAvg,
+		GeopHeightAvg:                  v.GeopHeightAvg,
+		PressureAvg:                    v.PressureAvg,
+		HighCloudCoverAvg:              v.HighCloudCoverAvg,
+		LowCloudCoverAvg:               v.LowCloudCoverAvg,
+		MediumCloudCoverAvg:            v.MediumCloudCoverAvg,
+		MiddleAndLowCloudCoverAvg:      v.MiddleAndLowCloudCoverAvg,
+		TotalCloudCoverAvg:             v.TotalCloudCoverAvg,
+		HourlyMaximumGustMax:           v.HourlyMaximumGustMax,
+		HourlyMaximumWindSpeedMax:      v.HourlyMaximumWindSpeedMax,
+		PopAvg:                         v.PoPAvg,
+		ProbabilityThunderstormAvg:     v.ProbabilityThunderstormAvg,
+		PotentialPrecipitationFormMode: v.PotentialPrecipitationForm,
+		PotentialPrecipitationTypeMode: v.PotentialPrecipitationType,
+		PrecipitationFormMode:          v.PrecipitationForm,
+		PrecipitationTypeMode:          v.PrecipitationType,
+		RadiationGlobalAvg:             v.RadiationGlobalAvg,
+		RadiationLwAvg:                 v.RadiationLWAvg,
+		WeatherNumberMode:              v.WeatherNumberMode,
+		WeatherSymbol3Mode:             v.WeatherSymbol3Mode,
+		WindUmsAvg:                     v.WindUMSAvg,
+		WindVmsAvg:                     v.WindVMSAvg,
+		WindVectorMsAvg:                v.WindVectorMSAvg,
+		UvIndexMax:                     v.UVIndexMax,
+		SunshineHours:                  v.SunshineHours,
+		DayLengthHours:                 v.DayLengthHours,
+		Sunrise:                        optionalTimestampPB(v.Sunrise),
+		Sunset:                         optionalTimestampPB(v.Sunset),
+		PolarDay:                       v.PolarDay,
+		PolarNight:                     v.PolarNight,
+		MoonPhase:                      v.MoonPhase,
+		MoonPhaseName:                  v.MoonPhaseName,
+		MoonIllumination:               v.MoonIllumination,
+		NormalTempHigh:                 v.NormalTempHigh,
+		NormalTempLow:                  v.NormalTempLow,
+		DayHigh:                        v.DayHigh,
+		DayAvg:                         v.DayAvg,
+		NightLow:                       v.NightLow,
+		NightAvg:                       v.NightAvg,
+		Source:                         v.Source,
+	}
+}
+
+func fogAdvisoryPB(v *fogAdvisoryJSON) *pb.FogAdvisory {
+	if v == nil {
+		return nil
+	}
+	return &pb.FogAdvisory{
+		Level:              v.Level,
+		Observed:           v.Observed,
+		ObservedVisibility: v.ObservedVisibility,
+		StartsAt:           optionalTimestampPB(v.StartsAt),
+		ClearsAt:           optionalTimestampPB(v.ClearsAt),
+		FogHours:           int64(v.FogHours),
+	}
+}
+
+func alertPB(v alertJSON) *pb.Alert {
+	return &pb.Alert{
+		Id:          v.ID,
+		Event:       v.Event,
+		Severity:    v.Severity,
+		Headline:    v.Headline,
+		Description: v.Description,
+		Area:        v.Area,
+		Onset:       timestampPB(v.Onset),
+		Expires:     timestampPB(v.Expires),
+	}
+}
+
+func customStationPB(v *customStationJSON) *pb.CustomStation {
+	if v == nil {
+		return nil
+	}
+	return &pb.CustomStation{
+		StationId:  v.StationID,
+		Source:     v.Source,
+		DistanceKm: v.DistanceKM,
+		ObservedAt: timestampPB(v.ObservedAt),
+	}
+}
+
+func homeSensorPB(v homeSensorJSON) *pb.HomeSensor {
+	return &pb.HomeSensor{
+		Source:      v.Source,
+		StationName: v.StationName,
+		ModuleName:  v.ModuleName,
+		ModuleType:  v.ModuleType,
+		ObservedAt:  timestampPB(v.ObservedAt),
+		Temperature: v.Temperature,
+		Humidity:    v.Humidity,
+		Pressure:    v.Pressure,
+		Co2:         v.CO2,
+		Noise:       v.Noise,
+		WindSpeed:   v.WindSpeed,
+		WindGust:    v.WindGust,
+		WindDir:     v.WindDir,
+		Precip_1H:   v.Precip1h,
+	}
+}
+
+func environmentPB(v *environmentJSON) *pb.Environment {
+	if v == nil {
+		return nil
+	}
+	return &pb.Environment{
+		Warnings:   environmentSectionPB(v.Warnings),
+		AirQuality: environmentSectionPB(v.AirQuality),
+		Pollen:     environmentSectionPB(v.Pollen),
+		UvMax:      environmentSectionPB(v.UVMax),
+		FireIndex:  environmentSectionPB(v.FireIndex),
+	}
+}
+
+func environmentSectionPB(v environmentSectionJSON) *pb.EnvironmentSection {
+	return &pb.EnvironmentSection{
+		Available: v.Available,
+		Stale:     v.Stale,
+		UpdatedAt: optionalTimestampPB(v.UpdatedAt),
+		Value:     v.Value,
+		Level:     v.Level,
+		Summary:   v.Summary,
+	}
+}
+
+func airQualityPB(v *airQualityJSON) *pb.AirQuality {
+	if v == nil {
+		return nil
+	}
+	return &pb.AirQuality{
+		Station:    stationPB(v.Station),
+		ObservedAt: timestampPB(v.ObservedAt),
+		Pm2_5:      v.PM25,
+		Pm10:       v.PM10,
+		O3:         v.O3,
+		No2:        v.NO2,
+		Index:      int64(v.Index),
+		Category:   v.Category,
+	}
+}
+
+func marinePB(v *marineJSON) *pb.Marine {
+	if v == nil {
+		return nil
+	}
+	return &pb.Marine{
+		Station:          stationPB(v.Station),
+		ObservedAt:       timestampPB(v.ObservedAt),
+		WaveHeight:       v.WaveHeight,
+		WaveDirection:    v.WaveDirection,
+		WavePeriod:       v.WavePeriod,
+		WaterTemperature: v.WaterTemperature,
+		SeaLevel:         v.SeaLevel,
+	}
+}
+
+func roadPB(v *roadJSON) *pb.Road {
+	if v == nil {
+		return nil
+	}
+	return &pb.Road{
+		Station:         stationPB(v.Station),
+		ObservedAt:      timestampPB(v.ObservedAt),
+		RoadTemperature: v.RoadTemperature,
+		AirTemperature:  v.AirTemperature,
+		Condition:       int64PB(v.Condition),
+	}
+}
+
+func placePB(v *placeJSON) *pb.Place {
+	if v == nil {
+		return nil
+	}
+	return &pb.Place{
+		Name:   v.Name,
+		Region: v.Region,
+		Lat:    v.Lat,
+		Lon:    v.Lon,
+		Geoid:  int64PB(v.GeoID),
+	}
+}
+
+func metaPB(v *metaJSON) *pb.Meta {
+	if v == nil {
+		return nil
+	}
+	return &pb.Meta{
+		ObservationAgeSeconds: v.ObservationAgeSeconds,
+		ForecastFetchedAt:     optionalTimestampPB(v.ForecastFetchedAt),
+		HourlyFetchedAt:       optionalTimestampPB(v.HourlyFetchedAt),
+		GridLat:               v.GridLat,
+		GridLon:               v.GridLon,
+		Sources: &pb.Sources{
+			Observation: v.Sources.Observation,
+			Forecast:    v.Sources.Forecast,
+			Hourly:      v.Sources.Hourly,
+			Uv:          v.Sources.UV,
+		},
+	}
+}
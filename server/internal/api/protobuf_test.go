@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"wby/internal/api/pb"
+	"wby/internal/weather"
+)
+
+func TestWeatherPB_RoundTrip(t *testing.T) {
+	temp, zero := -4.5, 0.0
+	symbol := "snow"
+	condition := 6
+	observed := time.Date(2026, 1, 15, 12, 10, 0, 0, time.UTC)
+	sunrise := time.Date(2026, 1, 15, 7, 12, 30, 500, time.UTC)
+	resp := &weatherJSON{
+		Units:   "metric",
+		Station: stationJSON{Name: "Helsinki Kaisaniemi", DistanceKM: 1.25},
+		Current: currentJSON{
+			Temperature: &temp,
+			Precip1h:    &zero,
+			Extra:       map[string]float64{"ri_10min": 0.2, "n_man": 8},
+			ObservedAt:  observed,
+		},
+		Hourly: []hourlyForecastJSON{
+			{Time: observed.Add(time.Hour), Temperature: &temp, Symbol: &symbol},
+			{Time: observed.Add(2 * time.Hour)},
+		},
+		Forecast: []dailyForecastJSON{{Date: "2026-01-15", High: &zero, Sunrise: &sunrise, PolarNight: true}},
+		Timezone: "Europe/Helsinki",
+		Alerts:   []alertJSON{{ID: "a1", Event: "wind", Severity: "moderate", Onset: observed, Expires: observed.Add(6 * time.Hour)}},
+		Road:     &roadJSON{Station: stationJSON{Name: "vt1 Espoo"}, ObservedAt: observed, Condition: &condition},
+		Environment: &environmentJSON{
+			UVMax: environmentSectionJSON{Available: true, Value: &zero, UpdatedAt: &observed},
+		},
+	}
+
+	want := weatherPB(resp)
+	b, err := marshalProtobuf(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got pb.Weather
+	if err := proto.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(&got, want) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", &got, want)
+	}
+
+	if got.Current.Temperature == nil || *got.Current.Temperature != temp || got.Current.WindSpeed != nil {
+		t.Errorf("unexpected current %+v", got.Current)
+	}
+	if got.Current.Precipitation_1H == nil || *got.Current.Precipitation_1H != 0 {
+		t.Errorf("expected a present zero precipitation, got %v", got.Current.Precipitation_1H)
+	}
+	if got.HourlyForecast[1].Temperature != nil || got.Marine != nil || got.Place != nil {
+		t.Errorf("expected nil fields to stay nil: %v", &got)
+	}
+	if ts := got.DailyForecast[0].Sunrise; ts == nil || !ts.AsTime().Equal(sunrise) {
+		t.Errorf("unexpected sunrise %+v", ts)
+	}
+}
+
+func TestGetWeather_Protobuf(t *testing.T) {
+	h := NewHandler(weatherServiceStub{weather: &weather.WeatherResponse{}})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94", nil)
+	req.Header.Set("Accept", "application/x-protobuf")
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/x-protobuf" {
+		t.Fatalf("expected protobuf content type, got %q", ct)
+	}
+	var msg pb.Weather
+	if err := proto.Unmarshal(rr.Body.Bytes(), &msg); err != nil {
+		t.Fatalf("decode protobuf: %v", err)
+	}
+	if msg.Units != "metric" {
+		t.Fatalf("unexpected units %q", msg.Units)
+	}
+	protoETag := rr.Header().Get("ETag")
+
+	rr = httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94", nil))
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" || !strings.HasPrefix(rr.Body.String(), "{") {
+		t.Fatalf("expected JSON by default, got %q", ct)
+	}
+	if rr.Header().Get("ETag") == protoETag {
+		t.Fatal("expected JSON and protobuf bodies to have different ETags")
+	}
+}
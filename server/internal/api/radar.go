@@ -0,0 +1,72 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+const (
+	defaultRadarDim = 512
+	maxRadarDim     = 1024
+)
+
+func (h *Handler) getRadar(w http.ResponseWriter, r *http.Request) {
+	req, err := parseRadarRequest(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	img, err := h.service.GetRadarImage(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, weather.ErrRadarUnavailable) {
+			writeError(w, http.StatusServiceUnavailable, codeUnavailable, "radar unavailable")
+			return
+		}
+		logging.FromContext(r.Context()).Error("get radar image failed", "err", err, "bbox", fmt.Sprintf("%f,%f,%f,%f", req.BBox.MinLon, req.BBox.MinLat, req.BBox.MaxLon, req.BBox.MaxLat))
+		writeError(w, http.StatusBadGateway, codeUpstreamUnavailable, "radar unavailable")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Header().Set("X-Data-Time", img.Time.UTC().Format(time.RFC3339))
+	w.Header().Set("X-Radar-BBox", fmt.Sprintf("%g,%g,%g,%g", img.BBox.MinLon, img.BBox.MinLat, img.BBox.MaxLon, img.BBox.MaxLat))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(img.PNG)
+}
+
+func parseRadarRequest(r *http.Request) (weather.RadarRequest, error) {
+	query := r.URL.Query()
+	bbox, err := parseBBox(query.Get("bbox"))
+	if err != nil {
+		return weather.RadarRequest{}, err
+	}
+
+	req := weather.RadarRequest{BBox: bbox, Width: defaultRadarDim, Height: defaultRadarDim}
+	if raw := query.Get("width"); raw != "" {
+		if req.Width, err = strconv.Atoi(raw); err != nil {
+			return weather.RadarRequest{}, fmt.Errorf("invalid width parameter")
+		}
+	}
+	if raw := query.Get("height"); raw != "" {
+		if req.Height, err = strconv.Atoi(raw); err != nil {
+			return weather.RadarRequest{}, fmt.Errorf("invalid height parameter")
+		}
+	}
+	req.Width = clamp(req.Width, minOverlayDim, maxRadarDim)
+	req.Height = clamp(req.Height, minOverlayDim, maxRadarDim)
+
+	if raw := query.Get("time"); raw != "" {
+		if req.Time, err = time.Parse(time.RFC3339, raw); err != nil {
+			return weather.RadarRequest{}, fmt.Errorf("invalid time parameter")
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+type radarServiceStub struct {
+	weatherServiceStub
+	img *weather.RadarImage
+	err error
+	got *weather.RadarRequest
+}
+
+func (s radarServiceStub) GetRadarImage(ctx context.Context, req weather.RadarRequest) (*weather.RadarImage, error) {
+	if s.got != nil {
+		*s.got = req
+	}
+	return s.img, s.err
+}
+
+func TestGetRadar_OK(t *testing.T) {
+	var got weather.RadarRequest
+	h := NewHandler(radarServiceStub{
+		img: &weather.RadarImage{PNG: []byte{0x89, 0x50, 0x4e, 0x47}, BBox: weather.BBox{MinLon: 24, MinLat: 60, MaxLon: 26, MaxLat: 61}, Time: time.Date(2026, 1, 15, 5, 45, 0, 0, time.UTC)},
+		got: &got,
+	})
+	rr := httptest.NewRecorder()
+	h.getRadar(rr, httptest.NewRequest(http.MethodGet, "/v1/radar?bbox=24.5,60,25.5,60.5&time=2026-01-15T05:47:00Z&width=5000", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "image/png" {
+		t.Fatalf("unexpected content type %s", ct)
+	}
+	if rr.Header().Get("Cache-Control") == "" || rr.Header().Get("X-Data-Time") != "2026-01-15T05:45:00Z" {
+		t.Fatalf("unexpected headers %v", rr.Header())
+	}
+	if bbox := rr.Header().Get("X-Radar-BBox"); bbox != "24,60,26,61" {
+		t.Errorf("X-Radar-BBox = %q", bbox)
+	}
+	if got.Width != maxRadarDim || got.Height != defaultRadarDim {
+		t.Errorf("expected %dx%d, got %dx%d", maxRadarDim, defaultRadarDim, got.Width, got.Height)
+	}
+	if !got.Time.Equal(time.Date(2026, 1, 15, 5, 47, 0, 0, time.UTC)) {
+		t.Errorf("unexpected requested time %v", got.Time)
+	}
+}
+
+func TestGetRadar_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{"missing bbox", "", nil, http.StatusBadRequest},
+		{"inverted bbox", "bbox=25,60,24,61", nil, http.StatusBadRequest},
+		{"bad time", "bbox=24,60,25,61&time=yesterday", nil, http.StatusBadRequest},
+		{"not configured", "bbox=24,60,25,61", weather.ErrRadarUnavailable, http.StatusServiceUnavailable},
+		{"upstream failure", "bbox=24,60,25,61", errors.New("WMS returned 500"), http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(radarServiceStub{err: tt.err})
+			rr := httptest.NewRecorder()
+			h.getRadar(rr, httptest.NewRequest(http.MethodGet, "/v1/radar?"+tt.query, nil))
+
+			if rr.Code != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, rr.Code)
+			}
+			var body struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body.Error == "" {
+				t.Fatalf("expected a JSON error body, got %q", rr.Body.String())
+			}
+		})
+	}
+}
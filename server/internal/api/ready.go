@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const readinessPingTimeout = 2 * time.Second
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// FetchStatus reports observation fetcher progress; *fetcher.Status
+// implements it.
+type FetchStatus interface {
+	StartedAt() time.Time
+	LastSuccess() time.Time
+}
+
+// SetReadiness enables the dependency checks behind /health/ready. The
+// observation check fails once the last successful fetch, or the fetcher's
+// start if none has succeeded yet, is older than maxFetchAge. Either
+// dependency may be nil to skip its check.
+func (h *Handler) SetReadiness(db Pinger, fetch FetchStatus, maxFetchAge time.Duration) {
+	h.db = db
+	h.fetchStatus = fetch
+	h.maxFetchAge = maxFetchAge
+}
+
+// SetObservationFilter reports filter, a description of the stations the
+// fetcher fetches, in the observation check of /health/ready.
+func (h *Handler) SetObservationFilter(filter string) {
+	h.obsFilter = filter
+}
+
+type readinessJSON struct {
+	Status string                        `json:"status"`
+	Checks map[string]readinessCheckJSON `json:"checks"`
+}
+
+type readinessCheckJSON struct {
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	Filter      string     `json:"filter,omitempty"`
+}
+
+func (h *Handler) ready(w http.ResponseWriter, r *http.Request) {
+	resp := readinessJSON{Status: "ready", Checks: map[string]readinessCheckJSON{}}
+	fail := func(name string, check readinessCheckJSON) {
+		check.Status = "fail"
+		resp.Checks[name] = check
+		resp.Status = "not_ready"
+	}
+
+	if h.db != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessPingTimeout)
+		err := h.db.Ping(ctx)
+		cancel()
+		if err != nil {
+			fail("database", readinessCheckJSON{Error: err.Error()})
+		} else {
+			resp.Checks["database"] = readinessCheckJSON{Status: "ok"}
+		}
+	}
+
+	if h.fetchStatus != nil {
+		check := readinessCheckJSON{Status: "ok", Filter: h.obsFilter}
+		since := h.fetchStatus.StartedAt()
+		if last := h.fetchStatus.LastSuccess(); !last.IsZero() {
+			check.LastSuccess = &last
+			since = last
+		}
+		switch {
+		case time.Since(since) <= h.maxFetchAge:
+			resp.Checks["observation_fetch"] = check
+		case check.LastSuccess == nil:
+			check.Error = "no successful observation fetch since start"
+			fail("observation_fetch", check)
+		default:
+			check.Error = "last successful observation fetch is older than " + h.maxFetchAge.String()
+			fail("observation_fetch", check)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if resp.Status != "ready" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type pingerStub struct{ err error }
+
+func (p pingerStub) Ping(ctx context.Context) error { return p.err }
+
+type fetchStatusStub struct {
+	startedAt   time.Time
+	lastSuccess time.Time
+}
+
+func (s fetchStatusStub) StartedAt() time.Time   { return s.startedAt }
+func (s fetchStatusStub) LastSuccess() time.Time { return s.lastSuccess }
+
+func serveReady(t *testing.T, db Pinger, fetch FetchStatus) (int, readinessJSON) {
+	t.Helper()
+	h := NewHandler(weatherServiceStub{})
+	h.SetReadiness(db, fetch, 30*time.Minute)
+	h.SetObservationFilter("places Helsinki")
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	var body readinessJSON
+	if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	return rr.Code, body
+}
+
+func TestReady_OKWhenDependenciesHealthy(t *testing.T) {
+	now := time.Now()
+	code, body := serveReady(t, pingerStub{}, fetchStatusStub{startedAt: now.Add(-time.Hour), lastSuccess: now.Add(-5 * time.Minute)})
+
+	if code != http.StatusOK || body.Status != "ready" {
+		t.Fatalf("expected ready, got %d %+v", code, body)
+	}
+	if body.Checks["observation_fetch"].LastSuccess == nil {
+		t.Fatalf("expected last_success to be reported, got %+v", body.Checks)
+	}
+	if got := body.Checks["observation_fetch"].Filter; got != "places Helsinki" {
+		t.Fatalf("expected the observation filter to be reported, got %q", got)
+	}
+}
+
+func TestReady_FailsWhenDatabaseDown(t *testing.T) {
+	code, body := serveReady(t, pingerStub{err: errors.New("connection refused")}, nil)
+
+	if code != http.StatusServiceUnavailable || body.Status != "not_ready" {
+		t.Fatalf("expected not ready, got %d %+v", code, body)
+	}
+	if got := body.Checks["database"]; got.Status != "fail" || got.Error != "connection refused" {
+		t.Fatalf("unexpected database check %+v", got)
+	}
+}
+
+func TestReady_FailsWhenObservationsStale(t *testing.T) {
+	now := time.Now()
+	for name, status := range map[string]fetchStatusStub{
+		"stale success":         {startedAt: now.Add(-5 * time.Hour), lastSuccess: now.Add(-2 * time.Hour)},
+		"no success after boot": {startedAt: now.Add(-time.Hour)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			code, body := serveReady(t, pingerStub{}, status)
+			if code != http.StatusServiceUnavailable {
+				t.Fatalf("expected 503, got %d", code)
+			}
+			if got := body.Checks["observation_fetch"]; got.Status != "fail" || got.Error == "" {
+				t.Fatalf("unexpected observation check %+v", got)
+			}
+			if got := body.Checks["database"]; got.Status != "ok" {
+				t.Fatalf("expected database ok, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestReady_GracePeriodAfterStart(t *testing.T) {
+	code, body := serveReady(t, pingerStub{}, fetchStatusStub{startedAt: time.Now().Add(-time.Minute)})
+
+	if code != http.StatusOK {
+		t.Fatalf("expected ready during startup grace period, got %d %+v", code, body)
+	}
+}
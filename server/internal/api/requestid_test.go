@@ -0,0 +1,93 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	var seen string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logging.RequestID(r.Context())
+	})
+
+	rr := httptest.NewRecorder()
+	NewRequestIDMiddleware()(next).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/weather", nil))
+
+	id := rr.Header().Get("X-Request-ID")
+	if len(id) != 32 {
+		t.Fatalf("expected a 32 character generated ID, got %q", id)
+	}
+	if seen != id {
+		t.Fatalf("expected context ID %q to match header, got %q", id, seen)
+	}
+}
+
+func TestRequestIDMiddleware_PreservesIncomingID(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/weather", nil)
+	req.Header.Set("X-Request-ID", "client-abc-123")
+	rr := httptest.NewRecorder()
+	NewRequestIDMiddleware()(next).ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("X-Request-ID"); got != "client-abc-123" {
+		t.Fatalf("expected incoming ID to be preserved, got %q", got)
+	}
+}
+
+func TestRequestIDMiddleware_ReplacesInvalidID(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/weather", nil)
+	req.Header.Set("X-Request-ID", strings.Repeat("a", 200))
+	rr := httptest.NewRecorder()
+	NewRequestIDMiddleware()(next).ServeHTTP(rr, req)
+
+	if got := rr.Header().Get("X-Request-ID"); len(got) != 32 {
+		t.Fatalf("expected oversized ID to be replaced, got %q", got)
+	}
+}
+
+type failingWeatherStub struct {
+	weatherServiceStub
+}
+
+func (failingWeatherStub) GetWeather(context.Context, float64, float64, weather.WeatherOptions) (*weather.WeatherResponse, error) {
+	return nil, errors.New("boom")
+}
+
+func TestGetWeather_ErrorLogCarriesRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	mux := http.NewServeMux()
+	NewHandler(failingWeatherStub{}).RegisterRoutes(mux)
+	req := httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94", nil)
+	req.Header.Set("X-Request-ID", "trace-42")
+	rr := httptest.NewRecorder()
+	NewRequestIDMiddleware()(mux).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+	var line map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &line); err != nil {
+		t.Fatalf("decode log line %q: %v", logs.String(), err)
+	}
+	if line["msg"] != "get weather failed" || line["request_id"] != "trace-42" {
+		t.Fatalf("expected error log tagged with request ID, got %v", line)
+	}
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"time"
+
+	"wby/internal/weather"
+)
+
+// roadJSON is the latest observation from the nearest road weather
+// station. Condition is the station's road surface state code.
+type roadJSON struct {
+	Station         stationJSON `json:"station"`
+	ObservedAt      time.Time   `json:"observed_at"`
+	RoadTemperature *float64    `json:"road_temperature"`
+	AirTemperature  *float64    `json:"air_temperature"`
+	Condition       *int        `json:"condition"`
+}
+
+func toRoadJSON(r *weather.RoadReading) *roadJSON {
+	if r == nil {
+		return nil
+	}
+	return &roadJSON{
+		Station:         stationJSON{Name: r.Station.Name, DistanceKM: r.DistanceKM, fmisid: r.Station.FMISID},
+		ObservedAt:      r.ObservedAt,
+		RoadTemperature: r.RoadTemperature,
+		AirTemperature:  r.AirTemperature,
+		Condition:       r.Condition,
+	}
+}
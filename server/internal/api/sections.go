@@ -0,0 +1,81 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+
+	"wby/internal/weather"
+)
+
+// weatherSections maps the include names that select core /v1/weather
+// sections to what the service loads and the top-level keys they fill.
+// environment and road are opt-in extras and are not listed here.
+var weatherSections = map[string]struct {
+	section weather.Section
+	keys    []string
+}{
+	"current":     {weather.SectionCurrent, []string{"station", "current", "fog_advisory", "custom_station", "home_sensors"}},
+	"hourly":      {weather.SectionHourly, []string{"hourly_forecast"}},
+	"daily":       {weather.SectionDaily, []string{"daily_forecast", "synoptic_summary"}},
+	"alerts":      {weather.SectionAlerts, []string{"alerts"}},
+	"air_quality": {weather.SectionAirQuality, []string{"air_quality"}},
+	"marine":      {weather.SectionMarine, []string{"marine"}},
+}
+
+// parseSections returns the core sections named in an include list, or 0,
+// meaning all of them, when it names none.
+func parseSections(raw string) weather.Section {
+	var sections weather.Section
+	for _, part := range strings.Split(raw, ",") {
+		if s, ok := weatherSections[strings.TrimSpace(part)]; ok {
+			sections |= s.section
+		}
+	}
+	return sections
+}
+
+// withSections drops the top-level keys of the sections not in sections
+// from fs, so skipped sections are absent rather than empty.
+func (fs fieldSet) withSections(sections weather.Section) fieldSet {
+	if sections == 0 {
+		return fs
+	}
+	out := fieldSet{}
+	if fs == nil {
+		for _, key := range jsonKeys(reflect.TypeFor[weatherJSON]()) {
+			out[key] = nil
+		}
+	} else {
+		for key, sub := range fs {
+			out[key] = sub
+		}
+	}
+	for _, s := range weatherSections {
+		if !sections.Includes(s.section) {
+			for _, key := range s.keys {
+				delete(out, key)
+			}
+		}
+	}
+	return out
+}
+
+// jsonKeys lists the JSON object keys of struct type t.
+func jsonKeys(t reflect.Type) []string {
+	var keys []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		keys = append(keys, name)
+	}
+	return keys
+}
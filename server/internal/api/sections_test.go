@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+// sectionsStub records the options GetWeather was called with.
+type sectionsStub struct {
+	weatherServiceStub
+	got *weather.WeatherOptions
+}
+
+func (s sectionsStub) GetWeather(ctx context.Context, lat, lon float64, opts weather.WeatherOptions) (*weather.WeatherResponse, error) {
+	*s.got = opts
+	return s.weatherServiceStub.GetWeather(ctx, lat, lon, opts)
+}
+
+func TestGetWeather_IncludeSelectsSections(t *testing.T) {
+	observedAt := time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		query    string
+		sections weather.Section
+		present  []string
+		absent   []string
+	}{
+		{
+			query:    "include=current,alerts",
+			sections: weather.SectionCurrent | weather.SectionAlerts,
+			present:  []string{"units", "station", "current", "alerts", "timezone", "meta"},
+			absent:   []string{"hourly_forecast", "daily_forecast"},
+		},
+		{
+			query:    "include=daily",
+			sections: weather.SectionDaily,
+			present:  []string{"daily_forecast", "timezone"},
+			absent:   []string{"station", "current", "hourly_forecast", "alerts"},
+		},
+		{
+			query:   "include=unknown",
+			present: []string{"station", "current", "hourly_forecast", "daily_forecast", "alerts"},
+		},
+		{
+			query:    "include=hourly&fields=current.temperature,hourly.symbol",
+			sections: weather.SectionHourly,
+			present:  []string{"hourly_forecast"},
+			absent:   []string{"current", "units"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got weather.WeatherOptions
+			h := NewHandler(sectionsStub{
+				weatherServiceStub: weatherServiceStub{weather: &weather.WeatherResponse{
+					Current:  weather.CurrentWeather{Observation: weather.Observation{ObservedAt: observedAt}},
+					Hourly:   []weather.HourlyForecast{{Time: observedAt}},
+					Forecast: []weather.DailyForecast{{Date: observedAt}},
+				}},
+				got: &got,
+			})
+			rr := httptest.NewRecorder()
+			h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94&"+tt.query, nil))
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d", rr.Code)
+			}
+			if got.Sections != tt.sections {
+				t.Errorf("expected sections %b, got %b", tt.sections, got.Sections)
+			}
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range tt.present {
+				if _, ok := body[key]; !ok {
+					t.Errorf("expected %s in %s", key, rr.Body)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := body[key]; ok {
+					t.Errorf("expected no %s in %s", key, rr.Body)
+				}
+			}
+		})
+	}
+}
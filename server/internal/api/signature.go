@@ -1,9 +1,12 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -16,16 +19,29 @@ const (
 	signatureHeaderValue     = "X-Signature"
 )
 
-func NewRequestSignatureMiddleware(clientSecrets map[string]string, maxAge time.Duration) func(http.Handler) http.Handler {
-	secretByClient := make(map[string][]byte, len(clientSecrets))
-	for clientID, secret := range clientSecrets {
-		cleanClientID := strings.TrimSpace(clientID)
-		cleanSecret := strings.TrimSpace(secret)
-		if cleanClientID == "" || cleanSecret == "" {
-			continue
-		}
-		secretByClient[cleanClientID] = []byte(cleanSecret)
-	}
+// maxSignedBodyBytes caps the request bodies the signature middleware
+// buffers to hash. Every signed route has a smaller limit of its own.
+const maxSignedBodyBytes = 1 << 20
+
+type contextKey int
+
+const clientIDContextKey contextKey = iota
+
+// clientIDFromContext returns the client ID the signature middleware
+// authenticated, or "" for unsigned routes.
+func clientIDFromContext(ctx context.Context) string {
+	clientID, _ := ctx.Value(clientIDContextKey).(string)
+	return clientID
+}
+
+// NewRequestSignatureMiddleware verifies signed /v1/ requests. Requests
+// that change state under /v1/admin/ must be signed with one of
+// adminSecrets instead of clientSecrets; read-only admin routes accept
+// either. Requests other than GET and HEAD also sign a hash of their body,
+// so a captured signature cannot be replayed with another body.
+func NewRequestSignatureMiddleware(clientSecrets, adminSecrets map[string]string, maxAge time.Duration) func(http.Handler) http.Handler {
+	secretByClient := cleanSecrets(clientSecrets)
+	secretByAdmin := cleanSecrets(adminSecrets)
 	if maxAge <= 0 {
 		maxAge = 5 * time.Minute
 	}
@@ -43,38 +59,75 @@ func NewRequestSignatureMiddleware(clientSecrets map[string]string, maxAge time.
 			signature = strings.TrimPrefix(signature, "sha256=")
 
 			if clientID == "" || timestamp == "" || signature == "" {
-				writeJSONError(w, "unauthorized", http.StatusUnauthorized)
+				writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 				return
 			}
 
-			secret, ok := secretByClient[clientID]
+			// Admin clients may call every route; regular clients may not
+			// change anything under /v1/admin/.
+			secret, ok := secretByAdmin[clientID]
+			if !ok && !isAdminWrite(r) {
+				secret, ok = secretByClient[clientID]
+			}
 			if !ok {
-				writeJSONError(w, "unauthorized", http.StatusUnauthorized)
+				writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 				return
 			}
 
 			if !isFreshTimestamp(timestamp, maxAge, time.Now()) {
-				writeJSONError(w, "unauthorized", http.StatusUnauthorized)
+				writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 				return
 			}
 
 			signatureBytes, err := hex.DecodeString(signature)
 			if err != nil {
-				writeJSONError(w, "unauthorized", http.StatusUnauthorized)
+				writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 				return
 			}
 
-			expected := buildSignature(secret, r.Method, r.URL.Path, r.URL.RawQuery, timestamp)
+			var bodyHash string
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodyBytes))
+				if err != nil {
+					writeError(w, http.StatusRequestEntityTooLarge, codeInvalidRequest, "request body too large")
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				sum := sha256.Sum256(body)
+				bodyHash = hex.EncodeToString(sum[:])
+			}
+
+			expected := buildSignature(secret, r.Method, r.URL.Path, r.URL.RawQuery, timestamp, bodyHash)
 			if !hmac.Equal(signatureBytes, expected) {
-				writeJSONError(w, "unauthorized", http.StatusUnauthorized)
+				writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIDContextKey, clientID)))
 		})
 	}
 }
 
+func cleanSecrets(secrets map[string]string) map[string][]byte {
+	out := make(map[string][]byte, len(secrets))
+	for clientID, secret := range secrets {
+		cleanClientID := strings.TrimSpace(clientID)
+		cleanSecret := strings.TrimSpace(secret)
+		if cleanClientID == "" || cleanSecret == "" {
+			continue
+		}
+		out[cleanClientID] = []byte(cleanSecret)
+	}
+	return out
+}
+
+func isAdminWrite(r *http.Request) bool {
+	if !strings.HasPrefix(r.URL.Path, "/v1/admin/") {
+		return false
+	}
+	return r.Method != http.MethodGet && r.Method != http.MethodHead
+}
+
 func isFreshTimestamp(ts string, maxAge time.Duration, now time.Time) bool {
 	epochSeconds, err := strconv.ParseInt(ts, 10, 64)
 	if err != nil {
@@ -88,7 +141,10 @@ func isFreshTimestamp(ts string, maxAge time.Duration, now time.Time) bool {
 	return age <= maxAge
 }
 
-func buildSignature(secret []byte, method, path, rawQuery, timestamp string) []byte {
+// buildSignature signs the request line and timestamp, each on its own
+// line, followed for requests with a body by a line holding the hex SHA-256
+// of the body.
+func buildSignature(secret []byte, method, path, rawQuery, timestamp, bodyHash string) []byte {
 	mac := hmac.New(sha256.New, secret)
 	mac.Write([]byte(method))
 	mac.Write([]byte("\n"))
@@ -97,5 +153,9 @@ func buildSignature(secret []byte, method, path, rawQuery, timestamp string) []b
 	mac.Write([]byte(rawQuery))
 	mac.Write([]byte("\n"))
 	mac.Write([]byte(timestamp))
+	if bodyHash != "" {
+		mac.Write([]byte("\n"))
+		mac.Write([]byte(bodyHash))
+	}
 	return mac.Sum(nil)
 }
@@ -4,9 +4,11 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -27,7 +29,7 @@ func TestRequestSignatureMiddleware_AllowsValidSignedRequest(t *testing.T) {
 		w.WriteHeader(http.StatusNoContent)
 	})
 
-	middleware := NewRequestSignatureMiddleware(map[string]string{clientID: secret}, 5*time.Minute)
+	middleware := NewRequestSignatureMiddleware(map[string]string{clientID: secret}, nil, 5*time.Minute)
 	middleware(next).ServeHTTP(rr, req)
 
 	if !nextCalled {
@@ -45,7 +47,7 @@ func TestRequestSignatureMiddleware_RejectsUnsignedRequest(t *testing.T) {
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
 	})
-	middleware := NewRequestSignatureMiddleware(map[string]string{"ios-app": "top-secret"}, 5*time.Minute)
+	middleware := NewRequestSignatureMiddleware(map[string]string{"ios-app": "top-secret"}, nil, 5*time.Minute)
 	middleware(next).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusUnauthorized {
@@ -64,7 +66,7 @@ func TestRequestSignatureMiddleware_RejectsUnknownClient(t *testing.T) {
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
 	})
-	middleware := NewRequestSignatureMiddleware(map[string]string{"ios-app": "top-secret"}, 5*time.Minute)
+	middleware := NewRequestSignatureMiddleware(map[string]string{"ios-app": "top-secret"}, nil, 5*time.Minute)
 	middleware(next).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusUnauthorized {
@@ -85,7 +87,7 @@ func TestRequestSignatureMiddleware_RejectsStaleTimestamp(t *testing.T) {
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
 	})
-	middleware := NewRequestSignatureMiddleware(map[string]string{clientID: secret}, 5*time.Minute)
+	middleware := NewRequestSignatureMiddleware(map[string]string{clientID: secret}, nil, 5*time.Minute)
 	middleware(next).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusUnauthorized {
@@ -102,7 +104,7 @@ func TestRequestSignatureMiddleware_BypassesNonAPIRoutes(t *testing.T) {
 		w.WriteHeader(http.StatusNoContent)
 	})
 
-	middleware := NewRequestSignatureMiddleware(map[string]string{"ios-app": "top-secret"}, 5*time.Minute)
+	middleware := NewRequestSignatureMiddleware(map[string]string{"ios-app": "top-secret"}, nil, 5*time.Minute)
 	middleware(next).ServeHTTP(rr, req)
 
 	if !nextCalled {
@@ -113,6 +115,94 @@ func TestRequestSignatureMiddleware_BypassesNonAPIRoutes(t *testing.T) {
 	}
 }
 
+func TestRequestSignatureMiddleware_AdminWritesNeedAdminSecret(t *testing.T) {
+	secrets := map[string]string{"ios-app": "top-secret"}
+	admins := map[string]string{"ops": "admin-secret"}
+	middleware := NewRequestSignatureMiddleware(secrets, admins, 5*time.Minute)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		method, path, clientID, secret string
+		want                           int
+	}{
+		{http.MethodPost, "/v1/admin/refresh", "ops", "admin-secret", http.StatusNoContent},
+		{http.MethodPost, "/v1/admin/refresh", "ios-app", "top-secret", http.StatusUnauthorized},
+		{http.MethodGet, "/v1/admin/freshness", "ios-app", "top-secret", http.StatusNoContent},
+		{http.MethodGet, "/v1/weather", "ops", "admin-secret", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set("X-Client-ID", tt.clientID)
+		req.Header.Set("X-Timestamp", ts)
+		sig := signForTest(tt.secret, req.Method, req.URL.Path, req.URL.RawQuery, ts)
+		if tt.method != http.MethodGet {
+			sig = signBodyForTest(tt.secret, req.Method, req.URL.Path, req.URL.RawQuery, ts, "")
+		}
+		req.Header.Set("X-Signature", sig)
+
+		rr := httptest.NewRecorder()
+		middleware(next).ServeHTTP(rr, req)
+		if rr.Code != tt.want {
+			t.Errorf("%s %s as %s: expected status %d, got %d", tt.method, tt.path, tt.clientID, tt.want, rr.Code)
+		}
+	}
+}
+
+func TestRequestSignatureMiddleware_SignsBody(t *testing.T) {
+	secret := "top-secret"
+	middleware := NewRequestSignatureMiddleware(map[string]string{"ios-app": secret}, nil, 5*time.Minute)
+	var gotBody string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	signed := `{"lat":60.1,"lon":24.9,"webhook_url":"https://example.com/hook"}`
+	sig := signBodyForTest(secret, http.MethodPost, "/v1/subscriptions", "", ts, signed)
+
+	tests := []struct {
+		name string
+		body string
+		sig  string
+		want int
+	}{
+		{"signed body", signed, sig, http.StatusNoContent},
+		{"replayed with another body", `{"lat":60.1,"lon":24.9,"webhook_url":"https://attacker.example/hook"}`, sig, http.StatusUnauthorized},
+		{"signed without body hash", signed, signForTest(secret, http.MethodPost, "/v1/subscriptions", "", ts), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBody = ""
+			req := httptest.NewRequest(http.MethodPost, "/v1/subscriptions", strings.NewReader(tt.body))
+			req.Header.Set("X-Client-ID", "ios-app")
+			req.Header.Set("X-Timestamp", ts)
+			req.Header.Set("X-Signature", tt.sig)
+
+			rr := httptest.NewRecorder()
+			middleware(next).ServeHTTP(rr, req)
+			if rr.Code != tt.want {
+				t.Fatalf("expected status %d, got %d", tt.want, rr.Code)
+			}
+			if tt.want == http.StatusNoContent && gotBody != tt.body {
+				t.Errorf("handler read body %q, want %q", gotBody, tt.body)
+			}
+		})
+	}
+}
+
+func signBodyForTest(secret, method, path, rawQuery, ts, body string) string {
+	sum := sha256.Sum256([]byte(body))
+	msg := method + "\n" + path + "\n" + rawQuery + "\n" + ts + "\n" + hex.EncodeToString(sum[:])
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(msg))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func signForTest(secret, method, path, rawQuery, ts string) string {
 	msg := method + "\n" + path + "\n" + rawQuery + "\n" + ts
 	mac := hmac.New(sha256.New, []byte(secret))
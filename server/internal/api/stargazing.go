@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type stargazingJSON struct {
+	Nights []stargazingNightJSON `json:"nights"`
+}
+
+type stargazingNightJSON struct {
+	Date             string     `json:"date"`
+	DarkStart        time.Time  `json:"dark_start"`
+	DarkEnd          time.Time  `json:"dark_end"`
+	DarkHours        int        `json:"dark_hours"`
+	CloudCoverAvg    *float64   `json:"cloud_cover_avg"`
+	MoonPhase        float64    `json:"moon_phase"`
+	MoonIllumination float64    `json:"moon_illumination"`
+	Score            *float64   `json:"score"`
+	Rating           string     `json:"rating,omitempty"`
+	BestHour         *time.Time `json:"best_hour"`
+}
+
+func (h *Handler) getStargazing(w http.ResponseWriter, r *http.Request) {
+	lat, lon, err := queryLatLon(r.URL.Query())
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	nights, err := h.service.GetStargazing(r.Context(), lat, lon)
+	if err != nil {
+		writeServiceError(w, r, err, "get stargazing failed", "lat", lat, "lon", lon)
+		return
+	}
+
+	resp := stargazingJSON{Nights: make([]stargazingNightJSON, len(nights))}
+	for i, n := range nights {
+		resp.Nights[i] = stargazingNightJSON{
+			Date:             n.Date.Format("2006-01-02"),
+			DarkStart:        n.DarkStart,
+			DarkEnd:          n.DarkEnd,
+			DarkHours:        n.DarkHours,
+			CloudCoverAvg:    n.CloudCoverAvg,
+			MoonPhase:        n.MoonPhase,
+			MoonIllumination: n.MoonIllumination,
+			Score:            n.Score,
+			Rating:           n.Rating,
+			BestHour:         n.BestHour,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=600")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+type stationListJSON struct {
+	FMISID     int      `json:"fmisid"`
+	Name       string   `json:"name"`
+	Lat        float64  `json:"lat"`
+	Lon        float64  `json:"lon"`
+	WMOCode    string   `json:"wmo_code"`
+	Region     string   `json:"region,omitempty"`
+	ElevationM *float64 `json:"elevation_m"`
+	Type       string   `json:"type,omitempty"`
+}
+
+func (h *Handler) getStations(w http.ResponseWriter, r *http.Request) {
+	var bbox *weather.BBox
+	if raw := r.URL.Query().Get("bbox"); raw != "" {
+		parsed, err := parseBBox(raw)
+		if err != nil {
+			writeBadRequest(w, err)
+			return
+		}
+		bbox = &parsed
+	}
+
+	stations, err := h.service.ListStations(r.Context(), bbox)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("list stations failed", "err", err)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	resp := make([]stationListJSON, len(stations))
+	for i, st := range stations {
+		resp[i] = toStationListJSON(st)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func toStationListJSON(st weather.Station) stationListJSON {
+	return stationListJSON{
+		FMISID:     st.FMISID,
+		Name:       st.Name,
+		Lat:        st.Lat,
+		Lon:        st.Lon,
+		WMOCode:    st.WMOCode,
+		Region:     st.Region,
+		ElevationM: st.ElevationM,
+		Type:       st.Type,
+	}
+}
+
+// nearbyStationJSON is a station near the requested point. LastObservedAt
+// is null for stations that have never reported, and old for dead ones.
+type nearbyStationJSON struct {
+	stationListJSON
+	DistanceKM     float64    `json:"distance_km"`
+	LastObservedAt *time.Time `json:"last_observed_at"`
+}
+
+// defaultNearbyStations is how many stations /v1/stations/nearby returns
+// without n.
+const defaultNearbyStations = 5
+
+func (h *Handler) getNearbyStations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	lat, lon, err := queryLatLon(q)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	n := defaultNearbyStations
+	if raw := q.Get("n"); raw != "" {
+		n, err = strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid n parameter")
+			return
+		}
+		n = min(n, weather.MaxNearbyStations)
+	}
+
+	stations, err := h.service.GetNearbyStations(r.Context(), lat, lon, n)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("get nearby stations failed", "err", err, "lat", lat, "lon", lon)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	resp := make([]nearbyStationJSON, len(stations))
+	for i, st := range stations {
+		resp[i] = nearbyStationJSON{
+			stationListJSON: toStationListJSON(st.Station),
+			DistanceKM:      st.DistanceKM,
+			LastObservedAt:  st.LastObservedAt,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(resp)
+}
+
+type stationObservationsJSON struct {
+	Station      stationListJSON   `json:"station"`
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	Observations []observationJSON `json:"observations"`
+}
+
+type observationJSON struct {
+	ObservedAt      time.Time          `json:"observed_at"`
+	Temperature     *float64           `json:"temperature"`
+	WindSpeed       *float64           `json:"wind_speed"`
+	WindGust        *float64           `json:"wind_gust"`
+	WindDir         *float64           `json:"wind_direction"`
+	Humidity        *float64           `json:"humidity"`
+	DewPoint        *float64           `json:"dew_point"`
+	Pressure        *float64           `json:"pressure"`
+	Precip1h        *float64           `json:"precipitation_1h"`
+	PrecipIntensity *float64           `json:"precipitation_intensity"`
+	SnowDepth       *float64           `json:"snow_depth"`
+	Visibility      *float64           `json:"visibility"`
+	CloudCover      *float64           `json:"cloud_cover"`
+	WeatherCode     *float64           `json:"weather_code"`
+	Extra           map[string]float64 `json:"extra,omitempty"`
+}
+
+func (h *Handler) getStationObservations(w http.ResponseWriter, r *http.Request) {
+	fmisid, err := strconv.Atoi(r.PathValue("fmisid"))
+	if err != nil || fmisid <= 0 {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid fmisid")
+		return
+	}
+	from, to, err := parseObservationWindow(r, time.Now().UTC())
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	asCSV, err := wantsCSV(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	station, observations, err := h.service.GetStationObservations(r.Context(), fmisid, from, to)
+	if err != nil {
+		if errors.Is(err, weather.ErrStationNotFound) {
+			writeError(w, http.StatusNotFound, codeNotFound, "station not found")
+			return
+		}
+		logging.FromContext(r.Context()).Error("get station observations failed", "err", err, "fmisid", fmisid)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.Header().Add("Vary", "Accept")
+	if asCSV {
+		writeObservationsCSV(w, r, *station, from, to, observations)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toStationObservationsJSON(*station, from, to, observations))
+}
+
+func toStationObservationsJSON(station weather.Station, from, to time.Time, observations []weather.Observation) stationObservationsJSON {
+	resp := stationObservationsJSON{
+		Station:      toStationListJSON(station),
+		From:         from,
+		To:           to,
+		Observations: make([]observationJSON, len(observations)),
+	}
+	for i, o := range observations {
+		resp.Observations[i] = toObservationJSON(o)
+	}
+	return resp
+}
+
+func toObservationJSON(o weather.Observation) observationJSON {
+	return observationJSON{
+		ObservedAt:      o.ObservedAt,
+		Temperature:     o.Temperature,
+		WindSpeed:       o.WindSpeed,
+		WindGust:        o.WindGust,
+		WindDir:         o.WindDir,
+		Humidity:        o.Humidity,
+		DewPoint:        o.DewPoint,
+		Pressure:        o.Pressure,
+		Precip1h:        o.Precip1h,
+		PrecipIntensity: o.PrecipIntensity,
+		SnowDepth:       o.SnowDepth,
+		Visibility:      o.Visibility,
+		CloudCover:      o.TotalCloudCover,
+		WeatherCode:     o.WeatherCode,
+		Extra:           o.ExtraNumericParams,
+	}
+}
+
+// parseObservationWindow reads the RFC3339 from/to parameters. Both default
+// so the window covers the 24 hours ending at to, which defaults to now.
+func parseObservationWindow(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	return observationWindow(r.URL.Query().Get("from"), r.URL.Query().Get("to"), now)
+}
+
+func observationWindow(rawFrom, rawTo string, now time.Time) (time.Time, time.Time, error) {
+	to := now
+	if raw := rawTo; raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to parameter")
+		}
+		to = t.UTC()
+	}
+	from := to.Add(-24 * time.Hour)
+	if raw := rawFrom; raw != "" {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from parameter")
+		}
+		from = t.UTC()
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+	if to.Sub(from) > weather.MaxObservationRange {
+		return time.Time{}, time.Time{}, fmt.Errorf("range must not exceed %d days", int(weather.MaxObservationRange.Hours()/24))
+	}
+	return from, to, nil
+}
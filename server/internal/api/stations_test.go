@@ -0,0 +1,228 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+type stationsServiceStub struct {
+	weatherServiceStub
+	stations []weather.Station
+	gotBBox  *weather.BBox
+	gotN     int
+}
+
+func (s *stationsServiceStub) ListStations(ctx context.Context, bbox *weather.BBox) ([]weather.Station, error) {
+	s.gotBBox = bbox
+	return s.stations, nil
+}
+
+func (s *stationsServiceStub) GetStationObservations(ctx context.Context, fmisid int, from, to time.Time) (*weather.Station, []weather.Observation, error) {
+	for _, st := range s.stations {
+		if st.FMISID == fmisid {
+			temp := -3.5
+			return &st, []weather.Observation{{FMISID: fmisid, ObservedAt: to, Temperature: &temp, ExtraNumericParams: map[string]float64{"ri_10min": 0.2}}}, nil
+		}
+	}
+	return nil, nil, weather.ErrStationNotFound
+}
+
+func (s *stationsServiceStub) GetStationStats(ctx context.Context, fmisid int, period string, from, to time.Time) (*weather.Station, []weather.ObservationStats, error) {
+	for _, st := range s.stations {
+		if st.FMISID == fmisid {
+			loc := weather.StatsLocation()
+			low, high := -8.2, -1.4
+			return &st, []weather.ObservationStats{
+				{Start: time.Date(2026, 1, 19, 0, 0, 0, 0, loc), Samples: 144, TempMin: &low, TempMax: &high},
+				{Start: time.Date(2026, 1, 20, 0, 0, 0, 0, loc), Samples: 61},
+			}, nil
+		}
+	}
+	return nil, nil, weather.ErrStationNotFound
+}
+
+func (s *stationsServiceStub) GetNearbyStations(ctx context.Context, lat, lon float64, n int) ([]weather.NearbyStation, error) {
+	s.gotN = n
+	var nearby []weather.NearbyStation
+	for i, st := range s.stations {
+		if i == n {
+			break
+		}
+		nearby = append(nearby, weather.NearbyStation{Station: st, DistanceKM: float64(i) + 0.5})
+	}
+	return nearby, nil
+}
+
+func TestGetStations_EmptyReturnsArray(t *testing.T) {
+	h := NewHandler(&stationsServiceStub{})
+
+	rr := httptest.NewRecorder()
+	h.getStations(rr, httptest.NewRequest(http.MethodGet, "/v1/stations", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if got := strings.TrimSpace(rr.Body.String()); got != "[]" {
+		t.Fatalf("expected [], got %s", got)
+	}
+}
+
+func TestGetStations_PassesBBox(t *testing.T) {
+	elevation := 4.0
+	stub := &stationsServiceStub{stations: []weather.Station{{FMISID: 100971, Name: "Helsinki Kaisaniemi", Lat: 60.18, Lon: 24.94, WMOCode: "2978", Region: "Helsinki", ElevationM: &elevation, Type: weather.StationTypeAWS}}}
+	h := NewHandler(stub)
+
+	rr := httptest.NewRecorder()
+	h.getStations(rr, httptest.NewRequest(http.MethodGet, "/v1/stations?bbox=24.7,60.1,25.2,60.4", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if stub.gotBBox == nil || stub.gotBBox.MinLon != 24.7 || stub.gotBBox.MaxLat != 60.4 {
+		t.Fatalf("unexpected bbox: %+v", stub.gotBBox)
+	}
+	want := `[{"fmisid":100971,"name":"Helsinki Kaisaniemi","lat":60.18,"lon":24.94,"wmo_code":"2978","region":"Helsinki","elevation_m":4,"type":"aws"}]`
+	if got := strings.TrimSpace(rr.Body.String()); got != want {
+		t.Fatalf("unexpected body: %s", got)
+	}
+}
+
+func TestGetStations_RejectsInvalidBBox(t *testing.T) {
+	h := NewHandler(&stationsServiceStub{})
+
+	rr := httptest.NewRecorder()
+	h.getStations(rr, httptest.NewRequest(http.MethodGet, "/v1/stations?bbox=25,60,24,61", nil))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+}
+
+func TestGetStationObservations(t *testing.T) {
+	mux := http.NewServeMux()
+	NewHandler(&stationsServiceStub{stations: []weather.Station{{FMISID: 100971, Name: "Helsinki Kaisaniemi"}}}).RegisterRoutes(mux)
+
+	cases := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"default window", "/v1/stations/100971/observations", http.StatusOK},
+		{"explicit window", "/v1/stations/100971/observations?from=2026-01-14T00:00:00Z&to=2026-01-15T00:00:00Z", http.StatusOK},
+		{"invalid fmisid", "/v1/stations/abc/observations", http.StatusBadRequest},
+		{"invalid timestamp", "/v1/stations/100971/observations?from=yesterday", http.StatusBadRequest},
+		{"reversed window", "/v1/stations/100971/observations?from=2026-01-15T00:00:00Z&to=2026-01-14T00:00:00Z", http.StatusBadRequest},
+		{"window too long", "/v1/stations/100971/observations?from=2026-01-01T00:00:00Z&to=2026-01-15T00:00:00Z", http.StatusBadRequest},
+		{"unknown station", "/v1/stations/1/observations", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if rr.Code != tc.want {
+				t.Fatalf("expected status %d, got %d: %s", tc.want, rr.Code, rr.Body.String())
+			}
+		})
+	}
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/stations/100971/observations?to=2026-01-15T12:00:00Z", nil))
+	var resp struct {
+		From         time.Time `json:"from"`
+		Observations []struct {
+			Temperature *float64           `json:"temperature"`
+			Extra       map[string]float64 `json:"extra"`
+		} `json:"observations"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !resp.From.Equal(time.Date(2026, 1, 14, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("from = %v, want 24h before to", resp.From)
+	}
+	if len(resp.Observations) != 1 || resp.Observations[0].Extra["ri_10min"] != 0.2 {
+		t.Errorf("unexpected observations: %+v", resp.Observations)
+	}
+}
+
+func TestGetStationStats(t *testing.T) {
+	h := NewHandler(&stationsServiceStub{stations: []weather.Station{{FMISID: 100971, Name: "Helsinki Kaisaniemi"}}})
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/stations/100971/stats?from=2026-01-19&to=2026-01-21", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp stationStatsJSON
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Period != "day" || resp.Timezone != "Europe/Helsinki" || len(resp.Stats) != 2 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	// Dates are local: midnight in Helsinki is 22:00 UTC the day before.
+	if resp.Stats[0].Date != "2026-01-19" || !resp.Stats[0].Start.Equal(time.Date(2026, 1, 18, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected first bucket %+v", resp.Stats[0])
+	}
+	if resp.Stats[1].Samples != 61 || resp.Stats[1].TempMin != nil {
+		t.Fatalf("unexpected second bucket %+v", resp.Stats[1])
+	}
+
+	for query, want := range map[string]int{
+		"/v1/stations/100971/stats?period=week":                                http.StatusBadRequest,
+		"/v1/stations/100971/stats?from=2024-01-01&to=2026-01-01":              http.StatusBadRequest,
+		"/v1/stations/100971/stats?period=month&from=2024-01-01&to=2026-01-01": http.StatusOK,
+		"/v1/stations/100971/stats?from=yesterday":                             http.StatusBadRequest,
+		"/v1/stations/1/stats":                                                 http.StatusNotFound,
+	} {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, query, nil))
+		if rr.Code != want {
+			t.Errorf("%s: expected status %d, got %d", query, want, rr.Code)
+		}
+	}
+}
+
+func TestGetNearbyStations(t *testing.T) {
+	stub := &stationsServiceStub{stations: []weather.Station{
+		{FMISID: 100971, Name: "Helsinki Kaisaniemi", Lat: 60.18, Lon: 24.94},
+		{FMISID: 101004, Name: "Helsinki Kumpula", Lat: 60.20, Lon: 24.96},
+	}}
+	h := NewHandler(stub)
+
+	rr := httptest.NewRecorder()
+	h.getNearbyStations(rr, httptest.NewRequest(http.MethodGet, "/v1/stations/nearby?lat=60.17&lon=24.94&n=50", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if stub.gotN != weather.MaxNearbyStations {
+		t.Errorf("expected n to be capped at %d, got %d", weather.MaxNearbyStations, stub.gotN)
+	}
+	var resp []map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp) != 2 || resp[0]["fmisid"] != 100971.0 || resp[0]["distance_km"] != 0.5 {
+		t.Fatalf("unexpected response %v", resp)
+	}
+	if v, ok := resp[0]["last_observed_at"]; !ok || v != nil {
+		t.Fatalf("expected null last_observed_at for a station without observations, got %v", v)
+	}
+
+	for _, query := range []string{"lat=91&lon=24.9", "lat=60.1&lon=abc", "lat=60.1&lon=24.9&n=0"} {
+		rr := httptest.NewRecorder()
+		h.getNearbyStations(rr, httptest.NewRequest(http.MethodGet, "/v1/stations/nearby?"+query, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, rr.Code)
+		}
+	}
+}
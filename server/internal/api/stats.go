@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+type stationStatsJSON struct {
+	Station  stationListJSON `json:"station"`
+	Period   string          `json:"period"`
+	Timezone string          `json:"timezone"`
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Stats    []statsJSON     `json:"stats"`
+}
+
+// statsJSON is one day or month of aggregated observations. Date is the
+// local calendar day (2006-01-02) or month (2006-01).
+type statsJSON struct {
+	Date         string    `json:"date"`
+	Start        time.Time `json:"start"`
+	Samples      int       `json:"samples"`
+	TempMin      *float64  `json:"temp_min"`
+	TempMax      *float64  `json:"temp_max"`
+	TempAvg      *float64  `json:"temp_avg"`
+	PrecipTotal  *float64  `json:"precip_total"`
+	GustMax      *float64  `json:"gust_max"`
+	WindSpeedAvg *float64  `json:"wind_speed_avg"`
+}
+
+func (h *Handler) getStationStats(w http.ResponseWriter, r *http.Request) {
+	fmisid, err := strconv.Atoi(r.PathValue("fmisid"))
+	if err != nil || fmisid <= 0 {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid fmisid")
+		return
+	}
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = weather.StatsPeriodDay
+	}
+	if _, ok := weather.MaxStatsRange[period]; !ok {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid period parameter")
+		return
+	}
+	loc := weather.StatsLocation()
+	from, to, err := statsWindow(r.URL.Query().Get("from"), r.URL.Query().Get("to"), period, time.Now().In(loc))
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	station, stats, err := h.service.GetStationStats(r.Context(), fmisid, period, from, to)
+	if err != nil {
+		if errors.Is(err, weather.ErrStationNotFound) {
+			writeError(w, http.StatusNotFound, codeNotFound, "station not found")
+			return
+		}
+		logging.FromContext(r.Context()).Error("get station stats failed", "err", err, "fmisid", fmisid)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	resp := stationStatsJSON{
+		Station:  toStationListJSON(*station),
+		Period:   period,
+		Timezone: loc.String(),
+		From:     from,
+		To:       to,
+		Stats:    make([]statsJSON, len(stats)),
+	}
+	layout := "2006-01-02"
+	if period == weather.StatsPeriodMonth {
+		layout = "2006-01"
+	}
+	for i, st := range stats {
+		resp.Stats[i] = statsJSON{
+			Date:         st.Start.Format(layout),
+			Start:        st.Start,
+			Samples:      st.Samples,
+			TempMin:      st.TempMin,
+			TempMax:      st.TempMax,
+			TempAvg:      st.TempAvg,
+			PrecipTotal:  st.PrecipTotal,
+			GustMax:      st.GustMax,
+			WindSpeedAvg: st.WindSpeedAvg,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// statsWindow reads the from/to parameters of a stats request, each either
+// a local date (2006-01-02, meaning its midnight) or an RFC3339 time. to
+// defaults to now and from to 30 days (period=day) or 12 months
+// (period=month) before the start of to's day.
+func statsWindow(rawFrom, rawTo, period string, now time.Time) (time.Time, time.Time, error) {
+	loc := now.Location()
+	to := now
+	if rawTo != "" {
+		t, err := parseStatsTime(rawTo, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to parameter")
+		}
+		to = t
+	}
+	day := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc)
+	from := day.AddDate(0, 0, -30)
+	if period == weather.StatsPeriodMonth {
+		from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, -11, 0)
+	}
+	if rawFrom != "" {
+		t, err := parseStatsTime(rawFrom, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from parameter")
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must be before to")
+	}
+	if limit := weather.MaxStatsRange[period]; to.Sub(from) > limit {
+		return time.Time{}, time.Time{}, fmt.Errorf("range must not exceed %d days for period=%s", int(limit.Hours()/24), period)
+	}
+	return from, to, nil
+}
+
+func parseStatsTime(raw string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", raw, loc); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.In(loc), nil
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+const (
+	streamHeartbeatInterval = 30 * time.Second
+	streamBufferSize        = 16
+)
+
+// ObservationSubscriber delivers newly stored observations for a station;
+// *weather.ObservationHub implements it.
+type ObservationSubscriber interface {
+	Subscribe(fmisid, buffer int) (<-chan weather.Observation, func())
+}
+
+// SetObservationStream enables /v1/stations/{fmisid}/stream.
+func (h *Handler) SetObservationStream(sub ObservationSubscriber) {
+	h.observations = sub
+}
+
+// streamStationObservations sends server-sent events for a station: its
+// latest stored observation first, then each newer one as the fetcher
+// stores it. Comment lines every 30 seconds keep idle proxies from closing
+// the connection. The stream ends when the client goes away or the hub
+// closes on shutdown.
+func (h *Handler) streamStationObservations(w http.ResponseWriter, r *http.Request) {
+	fmisid, err := strconv.Atoi(r.PathValue("fmisid"))
+	if err != nil || fmisid <= 0 {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid fmisid")
+		return
+	}
+	if h.observations == nil {
+		writeError(w, http.StatusServiceUnavailable, codeUnavailable, "observation stream unavailable")
+		return
+	}
+
+	// Subscribe before reading the latest observation so nothing stored
+	// in between is missed.
+	events, cancel := h.observations.Subscribe(fmisid, streamBufferSize)
+	defer cancel()
+
+	now := time.Now().UTC()
+	_, recent, err := h.service.GetStationObservations(r.Context(), fmisid, now.Add(-24*time.Hour), now)
+	if err != nil {
+		if errors.Is(err, weather.ErrStationNotFound) {
+			writeError(w, http.StatusNotFound, codeNotFound, "station not found")
+			return
+		}
+		logging.FromContext(r.Context()).Error("get station observations failed", "err", err, "fmisid", fmisid)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The server's write timeout would otherwise cut the stream short.
+	rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	var last time.Time
+	send := func(o weather.Observation) error {
+		if !o.ObservedAt.After(last) {
+			return nil
+		}
+		last = o.ObservedAt
+		data, err := json.Marshal(toObservationJSON(o))
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "id: %s\nevent: observation\ndata: %s\n\n", o.ObservedAt.Format(time.RFC3339), data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if len(recent) > 0 {
+		if err := send(recent[len(recent)-1]); err != nil {
+			return
+		}
+	} else if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.streamHeartbeat())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case o, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := send(o); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *Handler) streamHeartbeat() time.Duration {
+	if h.heartbeat > 0 {
+		return h.heartbeat
+	}
+	return streamHeartbeatInterval
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestStreamStationObservations(t *testing.T) {
+	hub := weather.NewObservationHub()
+	h := NewHandler(&stationsServiceStub{stations: []weather.Station{{FMISID: 100971, Name: "Helsinki Kaisaniemi"}}})
+	h.SetObservationStream(hub)
+	h.heartbeat = 20 * time.Millisecond
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/stations/100971/stream")
+	if err != nil {
+		t.Fatalf("get stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	next := func() string {
+		t.Helper()
+		if !lines.Scan() {
+			t.Fatalf("stream ended early: %v", lines.Err())
+		}
+		return lines.Text()
+	}
+
+	// The stub's latest observation comes first.
+	if line := next(); !strings.HasPrefix(line, "id: ") {
+		t.Fatalf("expected id line, got %q", line)
+	}
+	if line := next(); line != "event: observation" {
+		t.Fatalf("expected observation event, got %q", line)
+	}
+	if line := next(); !strings.Contains(line, `"temperature":-3.5`) {
+		t.Fatalf("expected latest observation data, got %q", line)
+	}
+	next()
+
+	temp := 2.5
+	hub.Publish([]weather.Observation{
+		{FMISID: 101004, ObservedAt: time.Now().Add(time.Hour)},
+		{FMISID: 100971, ObservedAt: time.Now().Add(time.Hour), Temperature: &temp},
+	})
+	var sawHeartbeat, sawPublished bool
+	for !sawPublished {
+		switch line := next(); {
+		case line == ": heartbeat":
+			sawHeartbeat = true
+		case strings.HasPrefix(line, "data: "):
+			if !strings.Contains(line, `"temperature":2.5`) {
+				t.Fatalf("expected published observation, got %q", line)
+			}
+			sawPublished = true
+		}
+	}
+	for !sawHeartbeat {
+		sawHeartbeat = next() == ": heartbeat"
+	}
+
+	hub.Close()
+	for lines.Scan() {
+	}
+}
+
+func TestStreamStationObservations_UnknownStation(t *testing.T) {
+	h := NewHandler(&stationsServiceStub{})
+	h.SetObservationStream(weather.NewObservationHub())
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/stations/1/stream", nil)
+	req.SetPathValue("fmisid", "1")
+	h.streamStationObservations(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404, got %d", rr.Code)
+	}
+}
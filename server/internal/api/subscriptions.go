@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/notifier"
+	"wby/internal/weather"
+)
+
+type subscriptionRequestJSON struct {
+	Lat        *float64 `json:"lat"`
+	Lon        *float64 `json:"lon"`
+	WebhookURL string   `json:"webhook_url"`
+}
+
+type subscriptionJSON struct {
+	ID         int64     `json:"id"`
+	Lat        float64   `json:"lat"`
+	Lon        float64   `json:"lon"`
+	WebhookURL string    `json:"webhook_url"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (h *Handler) postSubscription(w http.ResponseWriter, r *http.Request) {
+	clientID := clientIDFromContext(r.Context())
+	if clientID == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+
+	var req subscriptionRequestJSON
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid JSON body")
+		return
+	}
+	if req.Lat == nil || req.Lon == nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "lat and lon are required")
+		return
+	}
+	if err := notifier.CheckWebhookURL(r.Context(), req.WebhookURL); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "webhook_url must be an https URL on a public host")
+		return
+	}
+
+	sub, err := h.service.CreateSubscription(r.Context(), weather.ForecastSubscription{
+		ClientID:   clientID,
+		Lat:        *req.Lat,
+		Lon:        *req.Lon,
+		WebhookURL: req.WebhookURL,
+	})
+	if err != nil {
+		writeServiceError(w, r, err, "create subscription failed", "client_id", clientID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscriptionJSON{
+		ID:         sub.ID,
+		Lat:        sub.Lat,
+		Lon:        sub.Lon,
+		WebhookURL: sub.WebhookURL,
+		CreatedAt:  sub.CreatedAt,
+	})
+}
+
+func (h *Handler) deleteSubscription(w http.ResponseWriter, r *http.Request) {
+	clientID := clientIDFromContext(r.Context())
+	if clientID == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "unauthorized")
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidRequest, "invalid subscription id")
+		return
+	}
+
+	if err := h.service.DeleteSubscription(r.Context(), clientID, id); err != nil {
+		if errors.Is(err, weather.ErrSubscriptionNotFound) {
+			writeError(w, http.StatusNotFound, codeNotFound, "subscription not found")
+			return
+		}
+		logging.FromContext(r.Context()).Error("delete subscription failed", "err", err, "client_id", clientID, "id", id)
+		writeError(w, http.StatusInternalServerError, codeInternal, "internal server error")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"wby/internal/weather"
+)
+
+// parseLang reads the optional lang parameter; "" means no symbol
+// descriptions.
+func parseLang(r *http.Request) (string, error) {
+	lang := r.URL.Query().Get("lang")
+	if lang != "" && !weather.ValidLang(lang) {
+		return "", fmt.Errorf("invalid lang parameter")
+	}
+	return lang, nil
+}
+
+// symbolCondition is the condition slug for symbol, nil when there is no
+// symbol.
+func symbolCondition(symbol *string) *string {
+	if symbol == nil {
+		return nil
+	}
+	condition := weather.SymbolCondition(*symbol)
+	return &condition
+}
+
+// observationCondition is the condition the observation's wawa code, or
+// failing that its other readings, describe; nil when they describe none.
+func observationCondition(obs weather.Observation) *string {
+	condition := weather.ObservationCondition(obs)
+	if condition == "" {
+		return nil
+	}
+	return &condition
+}
+
+// describeSymbols adds symbol_description in lang to every forecast entry.
+func describeSymbols(daily []dailyForecastJSON, hourly []hourlyForecastJSON, lang string) {
+	if lang == "" {
+		return
+	}
+	describe := func(symbol *string) *string {
+		var text string
+		if symbol != nil {
+			text = weather.SymbolDescription(*symbol, lang)
+		}
+		return &text
+	}
+	for i := range daily {
+		daily[i].SymbolDescription = describe(daily[i].Symbol)
+	}
+	for i := range hourly {
+		hourly[i].SymbolDescription = describe(hourly[i].Symbol)
+	}
+}
+
+type symbolJSON struct {
+	Code        int    `json:"code"`
+	Condition   string `json:"condition"`
+	DayNight    bool   `json:"day_night"`
+	Description string `json:"description"`
+}
+
+// getSymbols serves the symbol table so clients can map forecast symbols
+// and conditions to their own icons. Descriptions default to English.
+func (h *Handler) getSymbols(w http.ResponseWriter, r *http.Request) {
+	lang, err := parseLang(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if lang == "" {
+		lang = weather.LangEnglish
+	}
+
+	symbols := weather.Symbols()
+	resp := make([]symbolJSON, len(symbols))
+	for i, s := range symbols {
+		resp[i] = symbolJSON{Code: s.Code, Condition: s.Condition, DayNight: s.DayNight, Description: s.Description(lang)}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"wby/internal/weather"
+)
+
+func TestGetWeather_LangAddsSymbolDescriptions(t *testing.T) {
+	cloudy, unknown := "3", "999"
+	h := NewHandler(weatherServiceStub{weather: &weather.WeatherResponse{
+		Hourly:   []weather.HourlyForecast{{Symbol: &unknown}},
+		Forecast: []weather.DailyForecast{{Symbol: &cloudy}},
+	}})
+
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9&lang=fi", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var resp struct {
+		Hourly []struct {
+			SymbolDescription *string `json:"symbol_description"`
+		} `json:"hourly_forecast"`
+		Daily []struct {
+			SymbolDescription *string `json:"symbol_description"`
+		} `json:"daily_forecast"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if d := resp.Daily[0].SymbolDescription; d == nil || *d != "pilvistä" {
+		t.Errorf("unexpected daily description: %v", d)
+	}
+	if d := resp.Hourly[0].SymbolDescription; d == nil || *d != "" {
+		t.Errorf("expected empty description for unknown symbol, got %v", d)
+	}
+
+	rr = httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9&lang=de", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unsupported lang, got %d", rr.Code)
+	}
+}
+
+func TestGetWeather_AddsConditions(t *testing.T) {
+	cloudy, unknown := "3", "999"
+	h := NewHandler(weatherServiceStub{weather: &weather.WeatherResponse{
+		Hourly:   []weather.HourlyForecast{{Symbol: &unknown}, {}},
+		Forecast: []weather.DailyForecast{{Symbol: &cloudy}},
+	}})
+
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var resp struct {
+		Hourly []struct {
+			Condition *string `json:"condition"`
+		} `json:"hourly_forecast"`
+		Daily []struct {
+			Condition *string `json:"condition"`
+		} `json:"daily_forecast"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if c := resp.Daily[0].Condition; c == nil || *c != "cloudy" {
+		t.Errorf("unexpected daily condition: %v", c)
+	}
+	if c := resp.Hourly[0].Condition; c == nil || *c != weather.ConditionUnknown {
+		t.Errorf("expected unknown condition for unknown symbol, got %v", c)
+	}
+	if c := resp.Hourly[1].Condition; c != nil {
+		t.Errorf("expected no condition without a symbol, got %q", *c)
+	}
+}
+
+func TestGetWeather_DecodesCurrentCondition(t *testing.T) {
+	for _, tc := range []struct {
+		obs  weather.Observation
+		want *string
+	}{
+		{weather.Observation{WeatherCode: ptr(73.0)}, ptr("heavy_snow")},
+		{weather.Observation{TotalCloudCover: ptr(8.0)}, ptr("cloudy")},
+		{weather.Observation{}, nil},
+	} {
+		h := NewHandler(weatherServiceStub{weather: &weather.WeatherResponse{Current: weather.CurrentWeather{Observation: tc.obs}}})
+		rr := httptest.NewRecorder()
+		h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9", nil))
+
+		var resp struct {
+			Current struct {
+				Condition *string `json:"condition"`
+			} `json:"current"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if got := resp.Current.Condition; (got == nil) != (tc.want == nil) || (got != nil && *got != *tc.want) {
+			t.Errorf("%+v: got condition %v, want %v", tc.obs, got, tc.want)
+		}
+	}
+}
+
+func TestGetSymbols(t *testing.T) {
+	h := NewHandler(weatherServiceStub{})
+
+	rr := httptest.NewRecorder()
+	h.getSymbols(rr, httptest.NewRequest(http.MethodGet, "/v1/symbols?lang=sv", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var symbols []symbolJSON
+	if err := json.Unmarshal(rr.Body.Bytes(), &symbols); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(symbols) != len(weather.Symbols()) {
+		t.Fatalf("expected the full table, got %d symbols", len(symbols))
+	}
+	want := symbolJSON{Code: 1, Condition: "clear", DayNight: true, Description: "klart"}
+	if symbols[0] != want {
+		t.Errorf("expected %+v first, got %+v", want, symbols[0])
+	}
+
+	rr = httptest.NewRecorder()
+	h.getSymbols(rr, httptest.NewRequest(http.MethodGet, "/v1/symbols", nil))
+	if err := json.Unmarshal(rr.Body.Bytes(), &symbols); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if symbols[0].Description != "clear" {
+		t.Errorf("expected English by default, got %q", symbols[0].Description)
+	}
+
+	rr = httptest.NewRecorder()
+	h.getSymbols(rr, httptest.NewRequest(http.MethodGet, "/v1/symbols?lang=de", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unsupported lang, got %d", rr.Code)
+	}
+}
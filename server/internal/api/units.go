@@ -0,0 +1,155 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+const (
+	unitsMetric   = "metric"
+	unitsImperial = "imperial"
+)
+
+// parseUnits reads the optional units parameter, defaulting to metric.
+func parseUnits(r *http.Request) (string, error) {
+	switch units := r.URL.Query().Get("units"); units {
+	case "", unitsMetric:
+		return unitsMetric, nil
+	case unitsImperial:
+		return unitsImperial, nil
+	default:
+		return "", fmt.Errorf("invalid units parameter")
+	}
+}
+
+// Stored and service values are SI (°C, m/s, mm, m, cm, hPa); these convert
+// them for units=imperial.
+func celsiusToFahrenheit(v float64) float64 { return v*9/5 + 32 }
+func kelvinToRankine(v float64) float64     { return v * 9 / 5 }
+func msToMph(v float64) float64             { return v * 2.2369362921 }
+func mmToInches(v float64) float64          { return v / 25.4 }
+func cmToInches(v float64) float64          { return v / 2.54 }
+func metersToMiles(v float64) float64       { return v / 1609.344 }
+func metersToFeet(v float64) float64        { return v / 0.3048 }
+func hPaToInHg(v float64) float64           { return v * 0.0295299831 }
+
+// convert returns a converted copy. The pointers come from service data
+// that may be cached, so they are never written through.
+func convert(v *float64, fn func(float64) float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	out := fn(*v)
+	return &out
+}
+
+func (c *currentJSON) toImperial() {
+	c.Temperature = convert(c.Temperature, celsiusToFahrenheit)
+	c.FeelsLike = convert(c.FeelsLike, celsiusToFahrenheit)
+	c.DewPoint = convert(c.DewPoint, celsiusToFahrenheit)
+	// A temperature difference scales like kelvin to rankine, with no offset.
+	c.TempAnomaly = convert(c.TempAnomaly, kelvinToRankine)
+	c.WindSpeed = convert(c.WindSpeed, msToMph)
+	c.WindGust = convert(c.WindGust, msToMph)
+	c.Pressure = convert(c.Pressure, hPaToInHg)
+	c.Precip1h = convert(c.Precip1h, mmToInches)
+	c.PrecipIntensity = convert(c.PrecipIntensity, mmToInches)
+	c.SnowDepth = convert(c.SnowDepth, cmToInches)
+	c.Visibility = convert(c.Visibility, metersToMiles)
+}
+
+func (d *dailyForecastJSON) toImperial() {
+	d.High = convert(d.High, celsiusToFahrenheit)
+	d.Low = convert(d.Low, celsiusToFahrenheit)
+	d.TempAvg = convert(d.TempAvg, celsiusToFahrenheit)
+	d.HighRaw = convert(d.HighRaw, celsiusToFahrenheit)
+	d.LowRaw = convert(d.LowRaw, celsiusToFahrenheit)
+	d.TempAvgRaw = convert(d.TempAvgRaw, celsiusToFahrenheit)
+	d.DayHigh = convert(d.DayHigh, celsiusToFahrenheit)
+	d.DayAvg = convert(d.DayAvg, celsiusToFahrenheit)
+	d.NightLow = convert(d.NightLow, celsiusToFahrenheit)
+	d.NightAvg = convert(d.NightAvg, celsiusToFahrenheit)
+	d.DewPointAvg = convert(d.DewPointAvg, celsiusToFahrenheit)
+	d.NormalTempHigh = convert(d.NormalTempHigh, celsiusToFahrenheit)
+	d.NormalTempLow = convert(d.NormalTempLow, celsiusToFahrenheit)
+	d.WindSpeed = convert(d.WindSpeed, msToMph)
+	d.HourlyMaximumGustMax = convert(d.HourlyMaximumGustMax, msToMph)
+	d.HourlyMaximumWindSpeedMax = convert(d.HourlyMaximumWindSpeedMax, msToMph)
+	d.WindUMSAvg = convert(d.WindUMSAvg, msToMph)
+	d.WindVMSAvg = convert(d.WindVMSAvg, msToMph)
+	d.WindVectorMSAvg = convert(d.WindVectorMSAvg, msToMph)
+	d.PrecipMM = convert(d.PrecipMM, mmToInches)
+	d.Precip1hSum = convert(d.Precip1hSum, mmToInches)
+	d.SnowAccumulationMM = convert(d.SnowAccumulationMM, mmToInches)
+	d.PressureAvg = convert(d.PressureAvg, hPaToInHg)
+}
+
+func (h *hourlyForecastJSON) toImperial() {
+	h.Temperature = convert(h.Temperature, celsiusToFahrenheit)
+	h.TemperatureRaw = convert(h.TemperatureRaw, celsiusToFahrenheit)
+	h.FeelsLike = convert(h.FeelsLike, celsiusToFahrenheit)
+	h.DewPoint = convert(h.DewPoint, celsiusToFahrenheit)
+	h.WindSpeed = convert(h.WindSpeed, msToMph)
+	h.WindGust = convert(h.WindGust, msToMph)
+	h.Pressure = convert(h.Pressure, hPaToInHg)
+	h.Precip1h = convert(h.Precip1h, mmToInches)
+}
+
+func (m *marineJSON) toImperial() {
+	m.WaveHeight = convert(m.WaveHeight, metersToFeet)
+	m.WaterTemperature = convert(m.WaterTemperature, celsiusToFahrenheit)
+	m.SeaLevel = convert(m.SeaLevel, mmToInches)
+}
+
+func (r *roadJSON) toImperial() {
+	r.RoadTemperature = convert(r.RoadTemperature, celsiusToFahrenheit)
+	r.AirTemperature = convert(r.AirTemperature, celsiusToFahrenheit)
+}
+
+func (s *homeSensorJSON) toImperial() {
+	s.Temperature = convert(s.Temperature, celsiusToFahrenheit)
+	s.Pressure = convert(s.Pressure, hPaToInHg)
+	s.WindSpeed = convert(s.WindSpeed, msToMph)
+	s.WindGust = convert(s.WindGust, msToMph)
+	s.Precip1h = convert(s.Precip1h, mmToInches)
+}
+
+// applyUnits converts a weather response in place for the requested units.
+func (w *weatherJSON) applyUnits(units string) {
+	w.Units = units
+	if units != unitsImperial {
+		return
+	}
+	w.Current.toImperial()
+	for i := range w.Forecast {
+		w.Forecast[i].toImperial()
+	}
+	for i := range w.Hourly {
+		w.Hourly[i].toImperial()
+	}
+	for i := range w.HomeSensors {
+		w.HomeSensors[i].toImperial()
+	}
+	if w.Marine != nil {
+		w.Marine.toImperial()
+	}
+	if w.Road != nil {
+		w.Road.toImperial()
+	}
+	if w.FogAdvisory != nil {
+		w.FogAdvisory.ObservedVisibility = convert(w.FogAdvisory.ObservedVisibility, metersToMiles)
+	}
+}
+
+func (f *forecastJSON) applyUnits(units string) {
+	f.Units = units
+	if units != unitsImperial {
+		return
+	}
+	for i := range f.Forecast {
+		f.Forecast[i].toImperial()
+	}
+	for i := range f.Hourly {
+		f.Hourly[i].toImperial()
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestGetWeather_ImperialUnits(t *testing.T) {
+	temp, wind, pressure, visibility, precip, anomaly := 20.0, 10.0, 1013.25, 1609.344, 25.4, 5.0
+	result := &weather.WeatherResponse{
+		Current: weather.CurrentWeather{
+			Observation: weather.Observation{
+				ObservedAt:  time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC),
+				Temperature: &temp,
+				WindSpeed:   &wind,
+				Pressure:    &pressure,
+				Visibility:  &visibility,
+			},
+			TempAnomaly: &anomaly,
+		},
+		Hourly:   []weather.HourlyForecast{{Temperature: &temp, WindGust: &wind, Pressure: &pressure, Precip1h: &precip}},
+		Forecast: []weather.DailyForecast{{TempHigh: &temp, PrecipMM: &precip, NormalTempHigh: &temp}},
+	}
+	h := NewHandler(weatherServiceStub{weather: result})
+
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9&units=imperial", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+
+	var resp struct {
+		Units   string `json:"units"`
+		Current struct {
+			Temperature *float64 `json:"temperature"`
+			WindSpeed   *float64 `json:"wind_speed"`
+			Pressure    *float64 `json:"pressure"`
+			Visibility  *float64 `json:"visibility"`
+			TempAnomaly *float64 `json:"temp_anomaly"`
+		} `json:"current"`
+		Hourly []struct {
+			Temperature *float64 `json:"temperature"`
+			WindGust    *float64 `json:"wind_gust"`
+			Pressure    *float64 `json:"pressure"`
+			Precip1h    *float64 `json:"precipitation_1h"`
+		} `json:"hourly_forecast"`
+		Daily []struct {
+			High       *float64 `json:"high"`
+			PrecipMM   *float64 `json:"precipitation_mm"`
+			NormalHigh *float64 `json:"normal_temp_high"`
+		} `json:"daily_forecast"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Units != "imperial" {
+		t.Errorf("units = %q", resp.Units)
+	}
+	assertNear(t, "current temperature", resp.Current.Temperature, 68)
+	assertNear(t, "current wind speed", resp.Current.WindSpeed, 22.37)
+	assertNear(t, "current pressure", resp.Current.Pressure, 29.92)
+	assertNear(t, "current visibility", resp.Current.Visibility, 1)
+	assertNear(t, "current temperature anomaly", resp.Current.TempAnomaly, 9)
+	assertNear(t, "hourly temperature", resp.Hourly[0].Temperature, 68)
+	assertNear(t, "hourly gust", resp.Hourly[0].WindGust, 22.37)
+	assertNear(t, "hourly pressure", resp.Hourly[0].Pressure, 29.92)
+	assertNear(t, "hourly precipitation", resp.Hourly[0].Precip1h, 1)
+	assertNear(t, "daily high", resp.Daily[0].High, 68)
+	assertNear(t, "daily precipitation", resp.Daily[0].PrecipMM, 1)
+	assertNear(t, "daily normal high", resp.Daily[0].NormalHigh, 68)
+
+	if temp != 20 || precip != 25.4 || anomaly != 5 {
+		t.Error("service data was modified by the conversion")
+	}
+}
+
+func TestGetWeather_RejectsUnknownUnits(t *testing.T) {
+	h := NewHandler(weatherServiceStub{weather: &weather.WeatherResponse{}})
+
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9&units=kelvin", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", rr.Code)
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestGetVersion_DefaultsToDev(t *testing.T) {
+	h := NewHandler(weatherServiceStub{})
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	var resp map[string]string
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := map[string]string{"version": "dev", "commit": "dev", "build_date": "dev", "go_version": runtime.Version()}
+	for field, value := range want {
+		if resp[field] != value {
+			t.Errorf("expected %s %q, got %q", field, value, resp[field])
+		}
+	}
+
+	etag := rr.Header().Get("ETag")
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	req.Header.Set("If-None-Match", etag)
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	if etag == "" || rr.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for ETag %q, got %d", etag, rr.Code)
+	}
+}
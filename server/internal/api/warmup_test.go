@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"wby/internal/weather"
+)
+
+// emptyStationStore is a store on a fresh deployment: it has no stations.
+type emptyStationStore struct {
+	weather.WeatherStore
+}
+
+func (emptyStationStore) NearestStation(ctx context.Context, lat, lon float64) (weather.Station, float64, error) {
+	return weather.Station{}, 0, fmt.Errorf("nearest station: %w", weather.ErrNoStations)
+}
+
+func TestWeatherEndpoints_WarmingUp(t *testing.T) {
+	h := NewHandler(weather.NewService(emptyStationStore{}, nil, weather.DefaultFreshness()))
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+
+	for _, path := range []string{"/v1/weather", "/v1/weather/compact"} {
+		t.Run(path, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path+"?lat=60.17&lon=24.94", nil))
+
+			if rr.Code != http.StatusServiceUnavailable {
+				t.Fatalf("expected status 503, got %d", rr.Code)
+			}
+			if got := rr.Header().Get("Retry-After"); got != warmingUpRetryAfter {
+				t.Fatalf("expected Retry-After %s, got %q", warmingUpRetryAfter, got)
+			}
+			var body errorJSON
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Code != "warming_up" || body.Error != "no station data available yet" {
+				t.Fatalf("unexpected body %+v", body)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -47,11 +48,22 @@ func TestGetWeather_IncludesTimezoneFromService(t *testing.T) {
 }
 
 type weatherServiceStub struct {
-	weather *weather.WeatherResponse
-	err     error
+	weather       *weather.WeatherResponse
+	err           error
+	hourlyUpdates chan struct{}
+	road          *weather.RoadReading
+	blended       *weather.CurrentWeather
 }
 
-func (s weatherServiceStub) GetWeather(ctx context.Context, lat, lon float64) (*weather.WeatherResponse, error) {
+func (s weatherServiceStub) GetBlendedCurrentWeather(ctx context.Context, lat, lon float64) (*weather.CurrentWeather, error) {
+	return s.blended, nil
+}
+
+func (s weatherServiceStub) GetCompactWeather(ctx context.Context, lat, lon float64) (*weather.WeatherResponse, error) {
+	return s.GetWeather(ctx, lat, lon, weather.WeatherOptions{})
+}
+
+func (s weatherServiceStub) GetWeather(ctx context.Context, lat, lon float64, opts weather.WeatherOptions) (*weather.WeatherResponse, error) {
 	if s.err != nil {
 		return nil, s.err
 	}
@@ -65,6 +77,14 @@ func (s weatherServiceStub) GetTemperatureOverlay(ctx context.Context, req weath
 	panic("not used in this test")
 }
 
+func (s weatherServiceStub) GetLightning(ctx context.Context, lat, lon, radiusKM float64, window time.Duration) ([]weather.LightningStrike, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) GetRadarImage(ctx context.Context, req weather.RadarRequest) (*weather.RadarImage, error) {
+	panic("not used in this test")
+}
+
 func (s weatherServiceStub) GetTemperatureSamples(ctx context.Context) (*weather.TemperatureSamplesResponse, error) {
 	panic("not used in this test")
 }
@@ -76,3 +96,305 @@ func (s weatherServiceStub) GetClimateNormals(ctx context.Context, lat, lon floa
 func (s weatherServiceStub) GetLeaderboard(ctx context.Context, lat, lon float64, timeframe string) ([]weather.LeaderboardEntry, error) {
 	panic("not used in this test")
 }
+
+func (s weatherServiceStub) GetStargazing(ctx context.Context, lat, lon float64) ([]weather.StargazingNight, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) Freshness() weather.Freshness {
+	return weather.DefaultFreshness()
+}
+
+func (s weatherServiceStub) IngestCustomObservation(ctx context.Context, obs weather.CustomObservation) error {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) RegisterCustomStation(ctx context.Context, st weather.CustomStation) (string, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) CustomStationForToken(ctx context.Context, token string) (*weather.CustomStation, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) NearestCustomObservation(ctx context.Context, clientID string, lat, lon float64) (*weather.CustomObservation, float64, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) GetHomeSensors(ctx context.Context, clientID string) ([]weather.HomeSensorReading, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) CreateSubscription(ctx context.Context, sub weather.ForecastSubscription) (weather.ForecastSubscription, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) DeleteSubscription(ctx context.Context, clientID string, id int64) error {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) GetEnvironment(ctx context.Context, lat, lon float64) (*weather.Environment, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) GetRoadWeather(ctx context.Context, lat, lon float64) (*weather.RoadReading, error) {
+	return s.road, nil
+}
+
+func (s weatherServiceStub) ListStations(ctx context.Context, bbox *weather.BBox) ([]weather.Station, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) GetStationObservations(ctx context.Context, fmisid int, from, to time.Time) (*weather.Station, []weather.Observation, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) GetNearbyStations(ctx context.Context, lat, lon float64, n int) ([]weather.NearbyStation, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) SearchPlaces(ctx context.Context, q string) ([]weather.Place, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) ResolvePlace(ctx context.Context, name string) (*weather.Place, []weather.Place, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) InvalidateForecastCaches() int {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) GetStationStats(ctx context.Context, fmisid int, period string, from, to time.Time) (*weather.Station, []weather.ObservationStats, error) {
+	panic("not used in this test")
+}
+
+func (s weatherServiceStub) WatchHourlyForecast(lat, lon float64) (<-chan struct{}, func()) {
+	return s.hourlyUpdates, func() {}
+}
+
+func (s weatherServiceStub) GetForecast(ctx context.Context, lat, lon float64, hours, days int) (*weather.ForecastResponse, error) {
+	panic("not used in this test")
+}
+
+func TestGetWeather_MoonFieldsCanBeSkipped(t *testing.T) {
+	phase, illumination := 0.5, 1.0
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{
+			Forecast: []weather.DailyForecast{{Date: time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC), MoonPhase: &phase, MoonIllumination: &illumination}},
+		},
+	})
+
+	for query, want := range map[string]bool{"": true, "&moon=true": true, "&moon=false": false} {
+		rr := httptest.NewRecorder()
+		h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9"+query, nil))
+
+		var resp struct {
+			Daily []map[string]any `json:"daily_forecast"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		name, ok := resp.Daily[0]["moon_phase_name"]
+		if ok != want {
+			t.Fatalf("query %q: expected moon fields present=%v, got %v", query, want, resp.Daily[0])
+		}
+		if want && (name != weather.MoonFull || resp.Daily[0]["moon_illumination"] != 1.0) {
+			t.Fatalf("query %q: unexpected moon fields %v", query, resp.Daily[0])
+		}
+	}
+}
+
+func TestGetWeather_Alerts(t *testing.T) {
+	onset := time.Date(2026, 1, 15, 6, 0, 0, 0, time.UTC)
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{
+			Warnings: []weather.Warning{{
+				ID: "2.49.0.1.246.0.0.2026.1.15.1", Event: "Wind warning", Severity: "Moderate",
+				Headline: "Wind warning for sea areas", AreaDesc: "Helsinki",
+				Onset: onset, Expires: onset.Add(18 * time.Hour),
+			}},
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9", nil))
+
+	var resp struct {
+		Alerts []alertJSON `json:"alerts"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Alerts) != 1 {
+		t.Fatalf("expected one alert, got %+v", resp.Alerts)
+	}
+	a := resp.Alerts[0]
+	if a.Event != "Wind warning" || a.Severity != "Moderate" || a.Area != "Helsinki" || !a.Expires.Equal(onset.Add(18*time.Hour)) {
+		t.Fatalf("unexpected alert %+v", a)
+	}
+
+	// Without warnings the array is empty rather than null.
+	h = NewHandler(weatherServiceStub{weather: &weather.WeatherResponse{}})
+	rr = httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=24.9", nil))
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if string(raw["alerts"]) != "[]" {
+		t.Fatalf("expected empty alerts array, got %s", raw["alerts"])
+	}
+}
+
+func TestGetWeather_AirQuality(t *testing.T) {
+	pm25, no2 := 5.3, 71.5
+	observed := time.Date(2026, 2, 16, 7, 0, 0, 0, time.UTC)
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{
+			AirQuality: &weather.AirQualityReading{
+				Station:    weather.Station{FMISID: 100723, Name: "Helsinki Mannerheimintie"},
+				DistanceKM: 1.2,
+				AirQuality: weather.AirQuality{FMISID: 100723, ObservedAt: observed, PM25: &pm25, NO2: &no2},
+				Index:      3,
+				Category:   weather.AirQualityFair,
+			},
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94", nil))
+
+	var resp struct {
+		AirQuality map[string]any `json:"air_quality"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	aq := resp.AirQuality
+	if aq["pm2_5"] != 5.3 || aq["no2"] != 71.5 || aq["pm10"] != nil || aq["index"] != 3.0 || aq["category"] != "fair" {
+		t.Fatalf("unexpected air quality %v", aq)
+	}
+	if st, _ := aq["station"].(map[string]any); st["name"] != "Helsinki Mannerheimintie" {
+		t.Fatalf("unexpected air quality station %v", aq["station"])
+	}
+
+	// Without a nearby station the block is omitted.
+	h = NewHandler(weatherServiceStub{weather: &weather.WeatherResponse{}})
+	rr = httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94", nil))
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if _, ok := raw["air_quality"]; ok {
+		t.Fatalf("expected no air_quality block, got %s", raw["air_quality"])
+	}
+}
+
+func TestGetWeather_Marine(t *testing.T) {
+	wave, water := 0.9, 17.3
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{
+			Marine: &weather.MarineReading{
+				Station:    weather.Station{FMISID: 134220, Name: "Helsinki Suomenlahti aaltopoiju"},
+				DistanceKM: 12.4,
+				MarineObservation: weather.MarineObservation{
+					FMISID: 134220, ObservedAt: time.Date(2026, 7, 16, 6, 30, 0, 0, time.UTC),
+					WaveHeight: &wave, WaterTemperature: &water,
+				},
+			},
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=25.0", nil))
+	var resp struct {
+		Marine map[string]any `json:"marine"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	m := resp.Marine
+	if m["wave_height"] != 0.9 || m["water_temperature"] != 17.3 || m["sea_level"] != nil {
+		t.Fatalf("unexpected marine section %v", m)
+	}
+
+	rr = httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.1&lon=25.0&units=imperial", nil))
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if ft, _ := resp.Marine["wave_height"].(float64); math.Abs(ft-2.95) > 0.01 {
+		t.Fatalf("expected wave height in feet, got %v", resp.Marine["wave_height"])
+	}
+
+	// Inland locations omit the section.
+	h = NewHandler(weatherServiceStub{weather: &weather.WeatherResponse{}})
+	rr = httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=61.5&lon=23.8", nil))
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if _, ok := raw["marine"]; ok {
+		t.Fatalf("expected no marine section, got %s", raw["marine"])
+	}
+}
+
+func TestGetWeather_IncludeRoad(t *testing.T) {
+	road, air, cond := -6.1, -8.0, 7
+	h := NewHandler(weatherServiceStub{
+		weather: &weather.WeatherResponse{},
+		road: &weather.RoadReading{
+			Station:    weather.Station{FMISID: 100011, Name: "vt1 Espoo Nupuri"},
+			DistanceKM: 0.4,
+			RoadObservation: weather.RoadObservation{
+				FMISID: 100011, ObservedAt: time.Date(2026, 1, 20, 7, 0, 0, 0, time.UTC),
+				RoadTemperature: &road, AirTemperature: &air, Condition: &cond,
+			},
+		},
+	})
+
+	// Road weather is opt-in.
+	rr := httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.24&lon=24.62", nil))
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if _, ok := raw["road"]; ok {
+		t.Fatalf("expected no road section without include=road, got %s", raw["road"])
+	}
+
+	rr = httptest.NewRecorder()
+	h.getWeather(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.24&lon=24.62&include=road", nil))
+	var resp struct {
+		Road *roadJSON `json:"road"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	r := resp.Road
+	if r == nil || r.Station.Name != "vt1 Espoo Nupuri" || *r.RoadTemperature != road || *r.AirTemperature != air || *r.Condition != cond {
+		t.Fatalf("unexpected road section %+v", r)
+	}
+}
+
+func TestToHourlyForecastJSON_FeelsLike(t *testing.T) {
+	cold, mild, wind := -5.0, 15.0, 5.0
+	hourly := toHourlyForecastJSON([]weather.HourlyForecast{
+		{Temperature: &cold, WindSpeed: &wind},
+		{Temperature: &mild, WindSpeed: &wind},
+		{Temperature: &cold},
+	})
+	if got, want := hourly[0].FeelsLike, computeFeelsLike(&cold, &wind); got == nil || *got != *want || *got >= cold {
+		t.Errorf("expected wind chill below %v, got %v", cold, got)
+	}
+	if got := hourly[1].FeelsLike; got == nil || *got != mild {
+		t.Errorf("expected feels_like to equal the temperature above 10 °C, got %v", got)
+	}
+	if got := hourly[2].FeelsLike; got == nil || *got != cold {
+		t.Errorf("expected feels_like to fall back to the temperature without wind, got %v", got)
+	}
+}
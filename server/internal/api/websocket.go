@@ -0,0 +1,169 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+const (
+	wsWriteWait    = 10 * time.Second
+	wsPingInterval = 30 * time.Second
+	wsReadLimit    = 4 << 10
+
+	// DefaultWebSocketMaxConnections caps concurrent /v1/weather/ws
+	// connections unless SetWebSocketLimit is called.
+	DefaultWebSocketMaxConnections = 500
+)
+
+// SetWebSocketLimit caps concurrent /v1/weather/ws connections; further
+// upgrades get 503 until one closes.
+func (h *Handler) SetWebSocketLimit(n int) {
+	if n <= 0 {
+		n = DefaultWebSocketMaxConnections
+	}
+	h.wsLimit = make(chan struct{}, n)
+}
+
+// weatherWebSocket takes the /v1/weather parameters, sends the weather
+// payload once the connection is upgraded and pushes it again whenever the
+// hourly forecast for the location is refreshed or the nearest station
+// reports a newer observation. Identical payloads are not resent. The
+// server pings every 30 seconds and closes connections that stop
+// answering; client messages are ignored.
+func (h *Handler) weatherWebSocket(w http.ResponseWriter, r *http.Request) {
+	q, err := parseWeatherQuery(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+	if !h.resolveWeatherPlace(w, r, &q) {
+		return
+	}
+	select {
+	case h.wsLimit <- struct{}{}:
+		defer func() { <-h.wsLimit }()
+	default:
+		writeError(w, http.StatusTooManyRequests, codeRateLimited, "too many websocket connections")
+		return
+	}
+
+	// Subscribe before the first payload is built so no update in between
+	// is missed.
+	forecasts, stopForecasts := h.service.WatchHourlyForecast(q.lat, q.lon)
+	defer stopForecasts()
+
+	resp, err := h.buildWeather(r.Context(), q)
+	if err != nil {
+		writeServiceError(w, r, err, "get weather failed", "lat", q.lat, "lon", q.lon)
+		return
+	}
+
+	var observations <-chan weather.Observation
+	if h.observations != nil {
+		var stopObservations func()
+		observations, stopObservations = h.observations.Subscribe(resp.Station.fmisid, 1)
+		defer stopObservations()
+	}
+
+	// The endpoint serves public data and no cookies, so like the REST
+	// routes it accepts any origin.
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: []string{"*"}})
+	if err != nil {
+		logging.FromContext(r.Context()).Debug("websocket upgrade failed", "err", err)
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(wsReadLimit)
+
+	pingInterval := h.wsPing
+	if pingInterval <= 0 {
+		pingInterval = wsPingInterval
+	}
+	// Reading answers pings and delivers pongs; it fails once the client
+	// closes or the connection breaks.
+	readDone := make(chan error, 1)
+	go func() {
+		for {
+			if _, _, err := conn.Read(r.Context()); err != nil {
+				readDone <- err
+				return
+			}
+		}
+	}()
+
+	var last []byte
+	fields := fieldSet(nil).withSections(q.sections)
+	push := func(resp *weatherJSON) error {
+		// meta differs on every build, so only the data decides whether
+		// an update is resent.
+		meta := resp.Meta
+		resp.Meta = nil
+		data, err := json.Marshal(sparse(resp, fields, false))
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(data, last) {
+			return nil
+		}
+		last = data
+		resp.Meta = meta
+		payload, err := json.Marshal(sparse(resp, fields, false))
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), wsWriteWait)
+		defer cancel()
+		return conn.Write(ctx, websocket.MessageText, payload)
+	}
+	refresh := func(ctx context.Context) error {
+		resp, err := h.buildWeather(ctx, q)
+		if err != nil {
+			// Keep the connection; the next update may succeed.
+			logging.FromContext(ctx).Warn("websocket weather refresh failed", "err", err, "lat", q.lat, "lon", q.lon)
+			return nil
+		}
+		return push(resp)
+	}
+
+	if err := push(resp); err != nil {
+		return
+	}
+
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-readDone:
+			return
+		case <-ping.C:
+			// Clients that stop answering are dropped.
+			ctx, cancel := context.WithTimeout(r.Context(), pingInterval)
+			err := conn.Ping(ctx)
+			cancel()
+			if err != nil {
+				return
+			}
+		case <-forecasts:
+			if err := refresh(r.Context()); err != nil {
+				return
+			}
+		case _, ok := <-observations:
+			if !ok {
+				// The hub closes on shutdown.
+				conn.Close(websocket.StatusGoingAway, "server shutting down")
+				return
+			}
+			if err := refresh(r.Context()); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+// liveWeatherStub reports the current value of temp on every call.
+type liveWeatherStub struct {
+	weatherServiceStub
+	temp *atomic.Int64
+}
+
+func (s liveWeatherStub) GetWeather(ctx context.Context, lat, lon float64, opts weather.WeatherOptions) (*weather.WeatherResponse, error) {
+	temp := float64(s.temp.Load())
+	return &weather.WeatherResponse{
+		Current: weather.CurrentWeather{
+			Station:     weather.Station{FMISID: 100971, Name: "Helsinki Kaisaniemi"},
+			Observation: weather.Observation{Temperature: &temp},
+		},
+	}, nil
+}
+
+// dialWebSocket upgrades path on srv and returns a reader of server frames.
+func dialWebSocket(t *testing.T, srv *httptest.Server, path string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	io.WriteString(conn, "GET "+path+" HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected status 101, got %d", resp.StatusCode)
+	}
+	return conn, br
+}
+
+// readFrame skips pings and returns the next frame's opcode and payload.
+func readFrame(t *testing.T, r io.Reader) (byte, []byte) {
+	t.Helper()
+	for {
+		var head [2]byte
+		if _, err := io.ReadFull(r, head[:]); err != nil {
+			t.Fatalf("read frame: %v", err)
+		}
+		n := int(head[1] & 0x7f)
+		if n == 126 {
+			var ext [2]byte
+			io.ReadFull(r, ext[:])
+			n = int(binary.BigEndian.Uint16(ext[:]))
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			t.Fatalf("read payload: %v", err)
+		}
+		if op := head[0] & 0x0f; op != 9 {
+			return op, payload
+		}
+	}
+}
+
+func TestWeatherWebSocket_PushesUpdates(t *testing.T) {
+	temp := &atomic.Int64{}
+	temp.Store(3)
+	forecasts := make(chan struct{}, 1)
+	hub := weather.NewObservationHub()
+	h := NewHandler(liveWeatherStub{weatherServiceStub: weatherServiceStub{hourlyUpdates: forecasts}, temp: temp})
+	h.SetObservationStream(hub)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	_, frames := dialWebSocket(t, srv, "/v1/weather/ws?lat=60.1&lon=24.9")
+
+	if op, payload := readFrame(t, frames); op != 1 || !strings.Contains(string(payload), `"temperature":3`) {
+		t.Fatalf("expected initial payload, got %d %s", op, payload)
+	}
+
+	temp.Store(4)
+	forecasts <- struct{}{}
+	if _, payload := readFrame(t, frames); !strings.Contains(string(payload), `"temperature":4`) {
+		t.Fatalf("expected payload after forecast refresh, got %s", payload)
+	}
+
+	temp.Store(5)
+	hub.Publish([]weather.Observation{{FMISID: 100971, ObservedAt: time.Now()}})
+	if _, payload := readFrame(t, frames); !strings.Contains(string(payload), `"temperature":5`) {
+		t.Fatalf("expected payload after new observation, got %s", payload)
+	}
+
+	hub.Close()
+	if op, payload := readFrame(t, frames); op != 8 || binary.BigEndian.Uint16(payload) != 1001 {
+		t.Fatalf("expected going-away close, got %d %v", op, payload)
+	}
+}
+
+func TestWeatherWebSocket_LimitsConnections(t *testing.T) {
+	temp := &atomic.Int64{}
+	h := NewHandler(liveWeatherStub{temp: temp})
+	h.SetWebSocketLimit(1)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	_, frames := dialWebSocket(t, srv, "/v1/weather/ws?lat=60.1&lon=24.9")
+	readFrame(t, frames)
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/v1/weather/ws?lat=60.1&lon=24.9", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("second upgrade: %v", err)
+	}
+	defer resp.Body.Close()
+	var body errorJSON
+	json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode != http.StatusTooManyRequests || body.Code != codeRateLimited {
+		t.Fatalf("expected 429 rate_limited, got %d %q", resp.StatusCode, body.Code)
+	}
+}
@@ -0,0 +1,473 @@
+// Package app wires the server's subsystems together and manages their
+// lifecycle, so main only loads configuration and waits for a signal.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"wby/internal/api"
+	"wby/internal/config"
+	"wby/internal/export"
+	"wby/internal/fetcher"
+	"wby/internal/fmi"
+	"wby/internal/metrics"
+	"wby/internal/netatmo"
+	"wby/internal/notifier"
+	"wby/internal/store"
+	"wby/internal/weather"
+)
+
+// Subsystem names accepted by Without.
+const (
+	SubsystemHTTP       = "http"
+	SubsystemFetcher    = "fetcher"
+	SubsystemNotifier   = "notifier"
+	SubsystemExporter   = "exporter"
+	SubsystemWarnings   = "warnings"
+	SubsystemLightning  = "lightning"
+	SubsystemAirQuality = "air_quality"
+	SubsystemMarine     = "marine"
+	SubsystemRoad       = "road"
+)
+
+const (
+	observationFetchInterval = 10 * time.Minute
+	warningFetchInterval     = 5 * time.Minute
+	lightningFetchInterval   = 5 * time.Minute
+	airQualityFetchInterval  = 30 * time.Minute
+	marineFetchInterval      = 30 * time.Minute
+	roadFetchInterval        = 10 * time.Minute
+)
+
+// Store is everything the subsystems need from persistence.
+type Store interface {
+	Ping(ctx context.Context) error
+	weather.WeatherStore
+	fetcher.ObservationStore
+	fetcher.WarningStore
+	fetcher.LightningStore
+	fetcher.AirQualityStore
+	fetcher.MarineStore
+	fetcher.RoadStore
+	fetcher.Coordinator
+	export.PairSource
+	netatmo.TokenStore
+}
+
+// FMI is everything the subsystems need from the FMI client.
+type FMI interface {
+	weather.ForecastFetcher
+	fetcher.ObservationSource
+	fetcher.WarningSource
+	fetcher.LightningSource
+	fetcher.AirQualitySource
+	fetcher.MarineSource
+	fetcher.RoadSource
+}
+
+// Subsystem is one independently started and stopped part of the app.
+// Subsystems start in registration order and stop in reverse.
+type Subsystem struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+type Option func(*options)
+
+type options struct {
+	store    Store
+	fmi      FMI
+	disabled map[string]bool
+}
+
+// WithStore uses the given store instead of connecting to DATABASE_URL. The
+// caller owns the store and must close it.
+func WithStore(s Store) Option {
+	return func(o *options) { o.store = s }
+}
+
+// WithFMI uses the given FMI client instead of one built from config.
+func WithFMI(f FMI) Option {
+	return func(o *options) { o.fmi = f }
+}
+
+// Without skips the named subsystems, e.g. to boot only the HTTP API in
+// tests without background workers.
+func Without(names ...string) Option {
+	return func(o *options) {
+		for _, name := range names {
+			o.disabled[name] = true
+		}
+	}
+}
+
+type App struct {
+	Config  config.Config
+	Service *weather.Service
+	Handler http.Handler
+	Metrics *metrics.Registry
+
+	root       *http.ServeMux
+	streams    *weather.ObservationHub
+	subsystems []Subsystem
+	started    []Subsystem
+	closers    []func()
+	errs       chan error
+	addr       net.Addr
+}
+
+// New builds every enabled subsystem without starting any of them.
+func New(ctx context.Context, cfg config.Config, opts ...Option) (*App, error) {
+	o := options{disabled: map[string]bool{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	a := &App{Config: cfg, Metrics: metrics.NewRegistry(), root: http.NewServeMux(), streams: weather.NewObservationHub(), errs: make(chan error, 1)}
+
+	db := o.store
+	if db == nil {
+		pg, err := store.New(ctx, cfg.DatabaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("connect to database: %w", err)
+		}
+		a.closers = append(a.closers, pg.Close)
+		db = pg
+	}
+
+	observationFilter, err := fmi.ParseObservationFilter(cfg.ObservationBBox, cfg.ObservationPlaces, cfg.ObservationFMISIDs)
+	if err != nil {
+		a.Stop(ctx)
+		return nil, err
+	}
+	// Shards split the Finland bbox, so they cannot honour another filter.
+	if cfg.FetchShards > 0 && !observationFilter.IsDefault() {
+		a.Stop(ctx)
+		return nil, fmt.Errorf("FETCH_SHARDS cannot be combined with an observation filter (%s)", observationFilter)
+	}
+	slog.Info("observation filter", "filter", observationFilter.String())
+
+	fmiClient := o.fmi
+	var radar weather.RadarSource
+	var longRange weather.LongRangeForecaster
+	var forecastBatch weather.ForecastBatchFetcher
+	if fmiClient == nil {
+		c := fmi.NewClient(cfg.FMIBaseURL, cfg.FMIAPIKey, cfg.FMITimeseriesURL)
+		c.SetMetrics(a.Metrics)
+		c.SetUserAgent(cfg.FMIUserAgent)
+		if err := c.SetAPIKeyMode(fmi.APIKeyMode(cfg.FMIAPIKeyMode)); err != nil {
+			a.Stop(ctx)
+			return nil, err
+		}
+		c.SetMaxResponseSize(int64(cfg.FMIMaxResponseSize), int64(cfg.FMIMaxUVResponseSize))
+		c.SetRetry(cfg.FMIRetryAttempts, cfg.FMIRetryBaseDelay)
+		c.SetCircuitBreaker(cfg.FMICircuitFailures, cfg.FMICircuitCooldown)
+		c.SetTimeouts(cfg.FMIInteractiveTimeout, cfg.FMIBulkTimeout)
+		c.SetForecastParameters(cfg.FMIForecastParameters)
+		if err := c.SetForecastMinCoverage(cfg.FMIForecastMinCoverage); err != nil {
+			a.Stop(ctx)
+			return nil, err
+		}
+		if err := c.SetForecastModel(fmi.ForecastModel(cfg.FMIForecastModel)); err != nil {
+			a.Stop(ctx)
+			return nil, err
+		}
+		if err := c.SetObservationFormat(fmi.ObservationFormat(cfg.FMIObservationFormat)); err != nil {
+			a.Stop(ctx)
+			return nil, err
+		}
+		if err := c.SetObservationFilter(observationFilter); err != nil {
+			a.Stop(ctx)
+			return nil, err
+		}
+		c.SetObservationChunks(cfg.FMIObservationChunks[0], cfg.FMIObservationChunks[1], cfg.FMIChunkConcurrency)
+		c.SetWarningsURL(cfg.FMIWarningsURL)
+		if cfg.FMIRadarURL != "" {
+			c.SetRadarURL(cfg.FMIRadarURL)
+			radar = c
+		}
+		if cfg.FMILongRangeEnabled {
+			longRange = c
+		}
+		forecastBatch = c
+		fmiClient = c
+	}
+
+	a.Service = weather.NewService(db, fmiClient, cfg.Freshness)
+	a.Service.SetMetrics(a.Metrics)
+	a.Service.SetMaxHourlyForecastHours(cfg.MaxHourlyForecastHours)
+	a.Service.SetMarineMaxDistanceKM(float64(cfg.MarineMaxDistanceKM))
+	if radar != nil {
+		a.Service.SetRadarSource(radar)
+	}
+	if longRange != nil {
+		a.Service.SetLongRangeForecaster(longRange)
+	}
+	if forecastBatch != nil {
+		a.Service.SetForecastBatchFetcher(forecastBatch)
+	}
+	if cfg.NetatmoClientID != "" && len(cfg.NetatmoAccounts) > 0 {
+		nc := netatmo.NewClient(cfg.NetatmoBaseURL, cfg.NetatmoClientID, cfg.NetatmoClientSecret, cfg.NetatmoAccounts)
+		nc.SetTokenStore(db)
+		a.Service.SetHomeSensorProvider(nc)
+		slog.Info("netatmo home sensors enabled", "accounts", len(cfg.NetatmoAccounts))
+	}
+
+	// The environment sections read what the ingestion loops store, which
+	// another replica may be running, so they follow the configuration
+	// rather than whether this instance runs the loop.
+	if cfg.FMIWarningsURL != "" {
+		a.Service.SetEnvironmentProvider(weather.EnvironmentWarnings, a.Service.WarningsEnvironmentProvider())
+	}
+	a.Service.SetEnvironmentProvider(weather.EnvironmentAirQuality, a.Service.AirQualityEnvironmentProvider())
+
+	if cfg.BiasCorrectionEnabled && cfg.BiasCorrectionFile != "" {
+		table, err := loadBiasTable(cfg.BiasCorrectionFile)
+		if err != nil {
+			a.Stop(ctx)
+			return nil, err
+		}
+		a.Service.SetBiasCorrector(table)
+		slog.Info("forecast bias correction enabled", "file", cfg.BiasCorrectionFile)
+	}
+
+	var f *fetcher.Fetcher
+	if !o.disabled[SubsystemFetcher] {
+		f = fetcher.New(fmiClient, db)
+		f.SetMetrics(a.Metrics)
+		f.SetPublisher(a.streams)
+		if cfg.FetchShards > 0 {
+			f.EnableSharding(db, cfg.InstanceID, cfg.FetchShards)
+		}
+	}
+
+	h := api.NewHandler(a.Service)
+	if f != nil {
+		// Readiness fails once three fetch intervals pass without storing
+		// observations.
+		h.SetReadiness(db, f.Status(), 3*observationFetchInterval)
+		h.SetObservationFilter(observationFilter.String())
+		h.SetRefresher(f)
+	} else {
+		h.SetReadiness(db, nil, 0)
+	}
+	h.SetObservationInterval(observationFetchInterval)
+	h.SetObservationStream(a.streams)
+	h.SetWebSocketLimit(cfg.WebSocketMaxConns)
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	// Metrics wrap everything so rejected requests are counted too. Request
+	// IDs come next so even rejected requests can be traced. Compression
+	// only touches the response, so verification sees the request exactly
+	// as the client signed it. CORS sits in front of signature checking
+	// because browsers send preflight requests unsigned.
+	a.root.Handle("/", api.NewMetricsMiddleware(a.Metrics, mux)(
+		api.NewRequestIDMiddleware()(
+			api.NewGzipMiddleware(0)(
+				api.NewCORSMiddleware(cfg.CORSAllowedOrigins)(
+					api.NewRequestSignatureMiddleware(cfg.ClientSecrets, cfg.AdminClientSecrets, cfg.RequestSignatureMaxAge)(mux),
+				),
+			),
+		),
+	))
+	a.Handler = a.root
+
+	if f != nil {
+		a.Register(worker(SubsystemFetcher, func(ctx context.Context) {
+			f.RunObservationLoop(ctx, observationFetchInterval)
+		}))
+	}
+	if cfg.FMIWarningsURL != "" && !o.disabled[SubsystemWarnings] {
+		wf := fetcher.NewWarningFetcher(fmiClient, db)
+		wf.SetMetrics(a.Metrics)
+		a.Register(worker(SubsystemWarnings, func(ctx context.Context) {
+			wf.RunLoop(ctx, warningFetchInterval)
+		}))
+	}
+	if !o.disabled[SubsystemLightning] {
+		lf := fetcher.NewLightningFetcher(fmiClient, db)
+		lf.SetMetrics(a.Metrics)
+		a.Register(worker(SubsystemLightning, func(ctx context.Context) {
+			lf.RunLoop(ctx, lightningFetchInterval)
+		}))
+	}
+	if !o.disabled[SubsystemAirQuality] {
+		af := fetcher.NewAirQualityFetcher(fmiClient, db)
+		af.SetMetrics(a.Metrics)
+		a.Register(worker(SubsystemAirQuality, func(ctx context.Context) {
+			af.RunLoop(ctx, airQualityFetchInterval)
+		}))
+	}
+	if !o.disabled[SubsystemMarine] {
+		mf := fetcher.NewMarineFetcher(fmiClient, db)
+		mf.SetMetrics(a.Metrics)
+		a.Register(worker(SubsystemMarine, func(ctx context.Context) {
+			mf.RunLoop(ctx, marineFetchInterval)
+		}))
+	}
+	if !o.disabled[SubsystemRoad] {
+		rf := fetcher.NewRoadFetcher(fmiClient, db)
+		rf.SetMetrics(a.Metrics)
+		a.Register(worker(SubsystemRoad, func(ctx context.Context) {
+			rf.RunLoop(ctx, roadFetchInterval)
+		}))
+	}
+	if !o.disabled[SubsystemNotifier] {
+		n := notifier.New(a.Service)
+		a.Register(worker(SubsystemNotifier, func(ctx context.Context) {
+			n.RunLoop(ctx, 30*time.Minute)
+		}))
+	}
+	if sink := export.SinkFromConfig(cfg.Export); sink != nil && !o.disabled[SubsystemExporter] {
+		e := export.New(db, sink)
+		a.Register(worker(SubsystemExporter, func(ctx context.Context) {
+			e.RunNightly(ctx, cfg.Export.HourUTC)
+		}))
+	}
+	if !o.disabled[SubsystemHTTP] {
+		a.Register(a.httpSubsystem())
+	}
+
+	return a, nil
+}
+
+// Handle registers h on the HTTP server outside the API middleware, so it
+// needs no request signature and is not counted in request metrics. It is
+// meant for operational endpoints such as /metrics and must be called
+// before Start.
+func (a *App) Handle(pattern string, h http.Handler) {
+	a.root.Handle(pattern, h)
+}
+
+// Register adds a subsystem to be started after those already registered.
+func (a *App) Register(s Subsystem) {
+	a.subsystems = append(a.subsystems, s)
+}
+
+// Start starts every subsystem in order. If one fails, the ones already
+// started are stopped again.
+func (a *App) Start(ctx context.Context) error {
+	for _, s := range a.subsystems {
+		if err := s.Start(ctx); err != nil {
+			stopErr := a.Stop(ctx)
+			return errors.Join(fmt.Errorf("start %s: %w", s.Name, err), stopErr)
+		}
+		slog.Info("subsystem started", "name", s.Name)
+		a.started = append(a.started, s)
+	}
+	return nil
+}
+
+// Stop stops started subsystems in reverse order and releases resources
+// New acquired.
+func (a *App) Stop(ctx context.Context) error {
+	var errs []error
+	for _, s := range slices.Backward(a.started) {
+		if err := s.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", s.Name, err))
+			continue
+		}
+		slog.Info("subsystem stopped", "name", s.Name)
+	}
+	a.started = nil
+	for _, closeFn := range slices.Backward(a.closers) {
+		closeFn()
+	}
+	a.closers = nil
+	return errors.Join(errs...)
+}
+
+// Errors reports fatal errors from running subsystems, such as the HTTP
+// server failing after it started.
+func (a *App) Errors() <-chan error {
+	return a.errs
+}
+
+// Addr is the address the HTTP server listens on once started.
+func (a *App) Addr() net.Addr {
+	return a.addr
+}
+
+func (a *App) httpSubsystem() Subsystem {
+	srv := &http.Server{
+		Addr:         ":" + a.Config.Port,
+		Handler:      a.Handler,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+	// Shutdown waits for open requests and ignores WebSocket connections,
+	// so end observation streams and live weather sockets first.
+	srv.RegisterOnShutdown(a.streams.Close)
+	return Subsystem{
+		Name: SubsystemHTTP,
+		Start: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			a.addr = ln.Addr()
+			slog.Info("server starting", "addr", a.addr.String())
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					select {
+					case a.errs <- fmt.Errorf("http server: %w", err):
+					default:
+					}
+				}
+			}()
+			return nil
+		},
+		Stop: srv.Shutdown,
+	}
+}
+
+// worker adapts a blocking loop into a subsystem. Stop cancels the loop and
+// waits for it to return.
+func worker(name string, run func(ctx context.Context)) Subsystem {
+	var (
+		cancel context.CancelFunc
+		wg     sync.WaitGroup
+	)
+	return Subsystem{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			var workerCtx context.Context
+			workerCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+			wg.Go(func() { run(workerCtx) })
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+			done := make(chan struct{})
+			go func() {
+				wg.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+func loadBiasTable(path string) (*weather.BiasTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open bias table: %w", err)
+	}
+	defer f.Close()
+	return weather.LoadBiasTable(f)
+}
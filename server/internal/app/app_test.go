@@ -0,0 +1,170 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"wby/internal/config"
+	"wby/internal/weather"
+)
+
+// stubStore satisfies Store for boots that never touch persistence.
+type stubStore struct {
+	weather.WeatherStore
+}
+
+func (stubStore) Ping(ctx context.Context) error { return nil }
+
+func (stubStore) NetatmoRefreshToken(ctx context.Context, clientID string, linkedHash []byte) (string, error) {
+	return "", nil
+}
+
+func (stubStore) SaveNetatmoRefreshToken(ctx context.Context, clientID string, linkedHash []byte, refreshToken string) error {
+	return nil
+}
+
+func (stubStore) UpsertStations(ctx context.Context, stations []weather.Station) error { return nil }
+
+func (stubStore) UpsertObservations(ctx context.Context, observations []weather.Observation) error {
+	return nil
+}
+
+func (stubStore) UpsertWarnings(ctx context.Context, warnings []weather.Warning) error { return nil }
+
+func (stubStore) UpsertLightning(ctx context.Context, strikes []weather.LightningStrike) error {
+	return nil
+}
+
+func (stubStore) MaxThunderstormProbability(ctx context.Context, day time.Time) (float64, error) {
+	return 0, nil
+}
+
+func (stubStore) UpsertAirQualityStations(ctx context.Context, stations []weather.Station) error {
+	return nil
+}
+
+func (stubStore) UpsertAirQuality(ctx context.Context, measurements []weather.AirQuality) error {
+	return nil
+}
+
+func (stubStore) UpsertMarineStations(ctx context.Context, stations []weather.Station) error {
+	return nil
+}
+
+func (stubStore) UpsertMarineObservations(ctx context.Context, observations []weather.MarineObservation) error {
+	return nil
+}
+
+func (stubStore) UpsertRoadStations(ctx context.Context, stations []weather.Station) error {
+	return nil
+}
+
+func (stubStore) UpsertRoadObservations(ctx context.Context, observations []weather.RoadObservation) error {
+	return nil
+}
+
+func (stubStore) Heartbeat(ctx context.Context, instanceID string) error { return nil }
+
+func (stubStore) LiveInstances(ctx context.Context, within time.Duration) ([]string, error) {
+	return nil, nil
+}
+
+func (stubStore) ForecastObservationPairs(ctx context.Context, from, to time.Time, maxDistanceKM float64) ([]weather.ForecastObservationPair, error) {
+	return nil, nil
+}
+
+func TestApp_BootsHTTPOnly(t *testing.T) {
+	cfg := config.Config{Port: "0", Freshness: weather.DefaultFreshness()}
+	a, err := New(context.Background(), cfg, WithStore(stubStore{}), Without(SubsystemFetcher, SubsystemLightning, SubsystemAirQuality, SubsystemMarine, SubsystemRoad, SubsystemNotifier))
+	if err != nil {
+		t.Fatalf("new app: %v", err)
+	}
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer a.Stop(context.Background())
+
+	resp, err := http.Get("http://" + a.Addr().String() + "/health")
+	if err != nil {
+		t.Fatalf("get health: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestApp_MetricsServedWithoutSignature(t *testing.T) {
+	cfg := config.Config{Freshness: weather.DefaultFreshness(), ClientSecrets: map[string]string{"ios-app": "secret"}}
+	a, err := New(context.Background(), cfg, WithStore(stubStore{}), Without(SubsystemHTTP, SubsystemFetcher, SubsystemLightning, SubsystemAirQuality, SubsystemMarine, SubsystemRoad, SubsystemNotifier))
+	if err != nil {
+		t.Fatalf("new app: %v", err)
+	}
+	a.Handle("GET /metrics", a.Metrics.Handler())
+
+	rr := httptest.NewRecorder()
+	a.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v1/weather?lat=60.17&lon=24.94", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected unsigned API request to be rejected, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	a.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected metrics to be served, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `wby_http_requests_total{method="GET",route="GET /v1/weather",status="401"} 1`) {
+		t.Fatalf("expected rejected request to be counted, got:\n%s", rr.Body.String())
+	}
+}
+
+func TestApp_StartFailureStopsStartedSubsystems(t *testing.T) {
+	cfg := config.Config{Freshness: weather.DefaultFreshness()}
+	a, err := New(context.Background(), cfg, WithStore(stubStore{}), Without(SubsystemHTTP, SubsystemFetcher, SubsystemLightning, SubsystemAirQuality, SubsystemMarine, SubsystemRoad, SubsystemNotifier))
+	if err != nil {
+		t.Fatalf("new app: %v", err)
+	}
+
+	var stopped []string
+	a.Register(Subsystem{
+		Name:  "first",
+		Start: func(ctx context.Context) error { return nil },
+		Stop:  func(ctx context.Context) error { stopped = append(stopped, "first"); return nil },
+	})
+	a.Register(Subsystem{
+		Name:  "second",
+		Start: func(ctx context.Context) error { return errors.New("boom") },
+		Stop:  func(ctx context.Context) error { stopped = append(stopped, "second"); return nil },
+	})
+
+	if err := a.Start(context.Background()); err == nil {
+		t.Fatal("expected start error")
+	}
+	if len(stopped) != 1 || stopped[0] != "first" {
+		t.Fatalf("expected only the started subsystem to be stopped, got %v", stopped)
+	}
+}
+
+func TestWorker_StopWaitsForLoop(t *testing.T) {
+	exited := make(chan struct{})
+	w := worker("loop", func(ctx context.Context) {
+		<-ctx.Done()
+		close(exited)
+	})
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := w.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	select {
+	case <-exited:
+	default:
+		t.Fatal("expected worker loop to have exited")
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadFreshness_Defaults(t *testing.T) {
+	got := loadFreshness("")
+	if got.DailyForecast.MaxAge != 3*time.Hour || got.HourlyForecast.MaxAge != 90*time.Minute {
+		t.Fatalf("unexpected defaults: %+v", got)
+	}
+}
+
+func TestLoadFreshness_FileThenEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "freshness.json")
+	body := `{"hourly_forecast": {"cache_ttl": "5m", "max_age": "1h"}, "uv": {"cache_ttl": "bogus"}}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FRESHNESS_HOURLY_FORECAST_MAX_AGE", "45m")
+
+	got := loadFreshness(path)
+	if got.HourlyForecast.CacheTTL != 5*time.Minute {
+		t.Fatalf("expected file cache_ttl 5m, got %s", got.HourlyForecast.CacheTTL)
+	}
+	if got.HourlyForecast.MaxAge != 45*time.Minute {
+		t.Fatalf("expected env max_age 45m to win over file, got %s", got.HourlyForecast.MaxAge)
+	}
+	if got.UV.CacheTTL != 10*time.Minute {
+		t.Fatalf("expected invalid uv cache_ttl to keep default, got %s", got.UV.CacheTTL)
+	}
+}
@@ -0,0 +1,85 @@
+// Package export produces training datasets from stored forecasts and
+// observations.
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"wby/internal/weather"
+)
+
+// PairMaxDistanceKM limits pairing to grid cells with a station close
+// enough that its observation is representative of the cell.
+const PairMaxDistanceKM = 10.0
+
+type PairSource interface {
+	ForecastObservationPairs(ctx context.Context, from, to time.Time, maxDistanceKM float64) ([]weather.ForecastObservationPair, error)
+}
+
+// Exporter writes one Parquet file per UTC day of hourly forecasts paired
+// with observations.
+type Exporter struct {
+	source PairSource
+	sink   Sink
+}
+
+func New(source PairSource, sink Sink) *Exporter {
+	return &Exporter{source: source, sink: sink}
+}
+
+// ExportDay exports forecasts valid on the given UTC day and returns the
+// key written and the number of rows.
+func (e *Exporter) ExportDay(ctx context.Context, day time.Time) (string, int, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	pairs, err := e.source.ForecastObservationPairs(ctx, from, to, PairMaxDistanceKM)
+	if err != nil {
+		return "", 0, fmt.Errorf("load pairs: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePairsParquet(&buf, pairs); err != nil {
+		return "", 0, fmt.Errorf("encode parquet: %w", err)
+	}
+	key := fmt.Sprintf("hourly_forecast_pairs/date=%s/pairs.parquet", from.Format("2006-01-02"))
+	if err := e.sink.Put(ctx, key, buf.Bytes()); err != nil {
+		return "", 0, fmt.Errorf("store export: %w", err)
+	}
+	return key, len(pairs), nil
+}
+
+// RunNightly exports the previous UTC day once a day at hourUTC. Hourly
+// forecasts are only retained for three days, so a missed night is lost.
+func (e *Exporter) RunNightly(ctx context.Context, hourUTC int) {
+	slog.Info("training export starting", "hour_utc", hourUTC)
+	for {
+		now := time.Now().UTC()
+		next := time.Date(now.Year(), now.Month(), now.Day(), hourUTC, 0, 0, 0, time.UTC)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+
+		timer := time.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			slog.Info("training export stopped")
+			return
+		case <-timer.C:
+		}
+
+		day := next.AddDate(0, 0, -1)
+		start := time.Now()
+		key, rows, err := e.ExportDay(ctx, day)
+		if err != nil {
+			slog.Error("training export failed", "err", err, "day", day.Format("2006-01-02"))
+			continue
+		}
+		slog.Info("training export written", "key", key, "rows", rows, "duration", time.Since(start))
+	}
+}
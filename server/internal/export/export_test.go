@@ -0,0 +1,168 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"wby/internal/weather"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+type fakePairSource struct {
+	from, to time.Time
+	pairs    []weather.ForecastObservationPair
+}
+
+func (f *fakePairSource) ForecastObservationPairs(ctx context.Context, from, to time.Time, maxDistanceKM float64) ([]weather.ForecastObservationPair, error) {
+	f.from, f.to = from, to
+	return f.pairs, nil
+}
+
+type memorySink map[string][]byte
+
+func (m memorySink) Put(ctx context.Context, key string, data []byte) error {
+	m[key] = data
+	return nil
+}
+
+func TestWritePairsParquetFraming(t *testing.T) {
+	pairs := []weather.ForecastObservationPair{
+		{GridLat: 60.17, GridLon: 24.94, ForecastTime: time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC), FMISID: 100971, ForecastTemperature: ptr(-3.2), ObservedTemperature: ptr(-2.8)},
+		{GridLat: 60.17, GridLon: 24.94, ForecastTime: time.Date(2026, 1, 15, 13, 0, 0, 0, time.UTC), FMISID: 100971, ForecastTemperature: ptr(-3.0)},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePairsParquet(&buf, pairs); err != nil {
+		t.Fatalf("WritePairsParquet: %v", err)
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := binary.LittleEndian.Uint32(data[len(data)-8 : len(data)-4])
+	if int(footerLen) >= len(data)-12 {
+		t.Fatalf("footer length %d exceeds file size %d", footerLen, len(data))
+	}
+	footer := data[len(data)-8-int(footerLen) : len(data)-8]
+	for _, name := range []string{"forecast_time", "observed_temperature", "fmisid"} {
+		if !bytes.Contains(footer, []byte(name)) {
+			t.Errorf("footer missing column %q", name)
+		}
+	}
+}
+
+func TestWritePairsParquetRoundTrip(t *testing.T) {
+	fetched := time.Date(2026, 1, 15, 6, 0, 0, 0, time.UTC)
+	pairs := []weather.ForecastObservationPair{
+		{GridLat: 60.17, GridLon: 24.94, ForecastTime: fetched.Add(6 * time.Hour), FetchedAt: fetched, FMISID: 100971, StationDistanceKM: 1.5, ForecastTemperature: ptr(-3.2), ObservedTemperature: ptr(-2.8)},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePairsParquet(&buf, pairs); err != nil {
+		t.Fatalf("WritePairsParquet: %v", err)
+	}
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	schema := file.Schema()
+	if got, ok := schema.Lookup("forecast_time"); !ok || !got.Node.Type().LogicalType().Timestamp.IsAdjustedToUTC {
+		t.Errorf("forecast_time is not a UTC timestamp")
+	}
+	if got, ok := schema.Lookup("observed_wind_speed"); !ok || !got.Node.Optional() {
+		t.Errorf("observed_wind_speed is not optional")
+	}
+
+	rows, err := parquet.Read[pairRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+	row := rows[0]
+	if row.LeadHours != 6 || row.FMISID != 100971 || !row.ForecastTime.Equal(pairs[0].ForecastTime) {
+		t.Errorf("unexpected row %+v", row)
+	}
+	if row.ObservedTemperature == nil || *row.ObservedTemperature != -2.8 || row.ObservedWindSpeed != nil {
+		t.Errorf("expected observed temperature -2.8 and no wind speed, got %v %v", row.ObservedTemperature, row.ObservedWindSpeed)
+	}
+}
+
+func TestExportDayWritesPartitionedKey(t *testing.T) {
+	source := &fakePairSource{pairs: []weather.ForecastObservationPair{{FMISID: 1}}}
+	sink := memorySink{}
+
+	key, rows, err := New(source, sink).ExportDay(context.Background(), time.Date(2026, 1, 15, 18, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ExportDay: %v", err)
+	}
+	if key != "hourly_forecast_pairs/date=2026-01-15/pairs.parquet" {
+		t.Errorf("key = %q", key)
+	}
+	if rows != 1 {
+		t.Errorf("rows = %d, want 1", rows)
+	}
+	if _, ok := sink[key]; !ok {
+		t.Error("sink did not receive the export")
+	}
+	if !source.from.Equal(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)) || source.to.Sub(source.from) != 24*time.Hour {
+		t.Errorf("queried %v to %v, want the whole UTC day", source.from, source.to)
+	}
+}
+
+func TestLocalSinkPut(t *testing.T) {
+	dir := t.TempDir()
+	if err := (LocalSink{Dir: dir}).Put(context.Background(), "a/b/c.parquet", []byte("data")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "a", "b", "c.parquet"))
+	if err != nil {
+		t.Fatalf("read export: %v", err)
+	}
+	if string(got) != "data" {
+		t.Errorf("content = %q", got)
+	}
+}
+
+func TestS3SinkPutSignsPathStyleRequest(t *testing.T) {
+	var gotPath, gotAuth, gotHash string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		gotHash = r.Header.Get("X-Amz-Content-Sha256")
+	}))
+	defer srv.Close()
+
+	sink := S3Sink{
+		Bucket:          "training",
+		Prefix:          "wby/",
+		Region:          "eu-north-1",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	}
+	if err := sink.Put(context.Background(), "date=2026-01-15/pairs.parquet", []byte("data")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if gotPath != "/training/wby/date=2026-01-15/pairs.parquet" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(gotAuth, "/eu-north-1/s3/aws4_request") {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if gotHash != sha256Hex([]byte("data")) {
+		t.Errorf("payload hash = %q", gotHash)
+	}
+}
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"wby/internal/weather"
+)
+
+// pairRow is one row of the training export. Pointer columns are optional
+// and hold nulls where the forecast or observation lacked the value.
+type pairRow struct {
+	GridLat             float64   `parquet:"grid_lat"`
+	GridLon             float64   `parquet:"grid_lon"`
+	ForecastTime        time.Time `parquet:"forecast_time,timestamp(microsecond)"`
+	FetchedAt           time.Time `parquet:"fetched_at,timestamp(microsecond)"`
+	LeadHours           float64   `parquet:"lead_hours"`
+	FMISID              int32     `parquet:"fmisid"`
+	StationDistanceKM   float64   `parquet:"station_distance_km"`
+	ForecastTemperature *float64  `parquet:"forecast_temperature"`
+	ForecastWindSpeed   *float64  `parquet:"forecast_wind_speed"`
+	ForecastHumidity    *float64  `parquet:"forecast_humidity"`
+	ForecastPrecip1h    *float64  `parquet:"forecast_precip_1h"`
+	ObservedTemperature *float64  `parquet:"observed_temperature"`
+	ObservedWindSpeed   *float64  `parquet:"observed_wind_speed"`
+	ObservedHumidity    *float64  `parquet:"observed_humidity"`
+	ObservedPrecip1h    *float64  `parquet:"observed_precip_1h"`
+}
+
+// WritePairsParquet encodes the pairs with one column per field. Times are
+// TIMESTAMP_MICROS in UTC; lead_hours is the forecast horizon at fetch time.
+func WritePairsParquet(out io.Writer, pairs []weather.ForecastObservationPair) error {
+	rows := make([]pairRow, len(pairs))
+	for i, p := range pairs {
+		rows[i] = pairRow{
+			GridLat:             p.GridLat,
+			GridLon:             p.GridLon,
+			ForecastTime:        p.ForecastTime,
+			FetchedAt:           p.FetchedAt,
+			LeadHours:           p.ForecastTime.Sub(p.FetchedAt).Hours(),
+			FMISID:              int32(p.FMISID),
+			StationDistanceKM:   p.StationDistanceKM,
+			ForecastTemperature: p.ForecastTemperature,
+			ForecastWindSpeed:   p.ForecastWindSpeed,
+			ForecastHumidity:    p.ForecastHumidity,
+			ForecastPrecip1h:    p.ForecastPrecip1h,
+			ObservedTemperature: p.ObservedTemperature,
+			ObservedWindSpeed:   p.ObservedWindSpeed,
+			ObservedHumidity:    p.ObservedHumidity,
+			ObservedPrecip1h:    p.ObservedPrecip1h,
+		}
+	}
+	return parquet.Write(out, rows, parquet.CreatedBy("wby export", "", ""))
+}
@@ -0,0 +1,164 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"wby/internal/config"
+)
+
+// Sink stores a finished export file under a slash-separated key.
+type Sink interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// LocalSink writes exports below Dir.
+type LocalSink struct {
+	Dir string
+}
+
+func (s LocalSink) Put(ctx context.Context, key string, data []byte) error {
+	path := filepath.Join(s.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create export dir: %w", err)
+	}
+	// Write then rename so readers never see a partial file.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write export: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rename export: %w", err)
+	}
+	return nil
+}
+
+// S3Sink uploads exports with a SigV4-signed PutObject. Endpoint is only
+// needed for S3-compatible stores such as MinIO and switches to path-style
+// addressing.
+type S3Sink struct {
+	Bucket          string
+	Prefix          string
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	HTTPClient      *http.Client
+}
+
+func (s S3Sink) Put(ctx context.Context, key string, data []byte) error {
+	objectKey := strings.TrimPrefix(strings.TrimSuffix(s.Prefix, "/")+"/"+key, "/")
+
+	var target *url.URL
+	var err error
+	if s.Endpoint != "" {
+		target, err = url.Parse(strings.TrimRight(s.Endpoint, "/") + "/" + s.Bucket + "/" + objectKey)
+	} else {
+		target, err = url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.Bucket, s.Region, objectKey))
+	}
+	if err != nil {
+		return fmt.Errorf("build S3 URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create S3 request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.apache.parquet")
+	s.sign(req, data, time.Now().UTC())
+
+	client := s.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 2 * time.Minute}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("put S3 object: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("put S3 object: status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// sign adds AWS Signature Version 4 headers for a single-chunk payload.
+func (s S3Sink) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\n" +
+		"host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// SinkFromConfig returns the configured sink, preferring S3, or nil when
+// exports are disabled.
+func SinkFromConfig(cfg config.Export) Sink {
+	switch {
+	case cfg.S3Bucket != "":
+		return S3Sink{
+			Bucket:          cfg.S3Bucket,
+			Prefix:          cfg.S3Prefix,
+			Region:          cfg.S3Region,
+			Endpoint:        cfg.S3Endpoint,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		}
+	case cfg.Dir != "":
+		return LocalSink{Dir: cfg.Dir}
+	default:
+		return nil
+	}
+}
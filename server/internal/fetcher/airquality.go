@@ -0,0 +1,32 @@
+package fetcher
+
+import (
+	"context"
+
+	"wby/internal/fmi"
+	"wby/internal/weather"
+)
+
+type AirQualitySource interface {
+	FetchAirQuality(ctx context.Context) (*fmi.AirQualityResult, error)
+}
+
+type AirQualityStore interface {
+	UpsertAirQualityStations(ctx context.Context, stations []weather.Station) error
+	UpsertAirQuality(ctx context.Context, measurements []weather.AirQuality) error
+}
+
+// AirQualityFetcher periodically stores the latest hourly measurements from
+// FMI's urban air quality stations.
+type AirQualityFetcher = StationFetcher[weather.AirQuality]
+
+func NewAirQualityFetcher(source AirQualitySource, store AirQualityStore) *AirQualityFetcher {
+	fetch := func(ctx context.Context) ([]weather.Station, []weather.AirQuality, error) {
+		result, err := source.FetchAirQuality(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Stations, result.Measurements, nil
+	}
+	return newStationFetcher("air quality", fetch, store.UpsertAirQualityStations, store.UpsertAirQuality)
+}
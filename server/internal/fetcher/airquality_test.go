@@ -0,0 +1,54 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wby/internal/fmi"
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+type stubAirQualitySource struct {
+	result *fmi.AirQualityResult
+}
+
+func (s stubAirQualitySource) FetchAirQuality(ctx context.Context) (*fmi.AirQualityResult, error) {
+	return s.result, nil
+}
+
+type recordingAirQualityStore struct {
+	stations     []weather.Station
+	measurements []weather.AirQuality
+}
+
+func (s *recordingAirQualityStore) UpsertAirQualityStations(ctx context.Context, stations []weather.Station) error {
+	s.stations = append(s.stations, stations...)
+	return nil
+}
+
+func (s *recordingAirQualityStore) UpsertAirQuality(ctx context.Context, measurements []weather.AirQuality) error {
+	s.measurements = append(s.measurements, measurements...)
+	return nil
+}
+
+func TestAirQualityFetcherRunOnce(t *testing.T) {
+	reg := metrics.NewRegistry()
+	pm25 := 5.3
+	result := &fmi.AirQualityResult{
+		Stations:     []weather.Station{{FMISID: 100742, Name: "Helsinki Kallio 2"}},
+		Measurements: []weather.AirQuality{{FMISID: 100742, ObservedAt: time.Now(), PM25: &pm25}},
+	}
+
+	store := &recordingAirQualityStore{}
+	f := NewAirQualityFetcher(stubAirQualitySource{result: result}, store)
+	f.SetMetrics(reg)
+	f.runOnce(context.Background())
+	if len(store.stations) != 1 || len(store.measurements) != 1 {
+		t.Fatalf("expected stations and measurements to be stored, got %d and %d", len(store.stations), len(store.measurements))
+	}
+	if got := reg.Value("wby_air_quality_fetch_runs_total", "ok"); got != 1 {
+		t.Errorf("expected 1 ok run, got %v", got)
+	}
+}
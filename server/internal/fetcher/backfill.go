@@ -0,0 +1,53 @@
+package fetcher
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"wby/internal/fmi"
+)
+
+// Backfill fetches and stores the observations made since since, one
+// fmi.MaxObservationRange window at a time, to fill the gaps left while
+// the fetcher was down. Each window is stored as soon as it is fetched, so
+// an interrupted backfill keeps its progress. A failed window is logged and
+// counted in Errors and the walk carries on; only ctx ending stops it.
+// Unlike the loop it publishes nothing, since the observations are old.
+func (f *Fetcher) Backfill(ctx context.Context, since time.Time) (RefreshResult, error) {
+	var res RefreshResult
+	stations := map[int]bool{}
+	now := time.Now()
+	for start := since; start.Before(now); start = start.Add(fmi.MaxObservationRange) {
+		end := start.Add(fmi.MaxObservationRange)
+		if end.After(now) {
+			end = now
+		}
+		result, err := f.fmi.FetchObservationsRange(ctx, start, end)
+		if err != nil {
+			if ctx.Err() != nil {
+				return res, ctx.Err()
+			}
+			slog.Error("failed to backfill observations", "err", err, "start", start, "end", end)
+			res.Errors++
+			continue
+		}
+		if err := f.store.UpsertStations(ctx, result.Stations); err != nil {
+			slog.Error("failed to upsert backfilled stations", "err", err, "start", start, "end", end)
+			res.Errors++
+			continue
+		}
+		if err := f.store.UpsertObservations(ctx, result.Observations); err != nil {
+			slog.Error("failed to upsert backfilled observations", "err", err, "start", start, "end", end)
+			res.Errors++
+			continue
+		}
+		for _, st := range result.Stations {
+			stations[st.FMISID] = true
+		}
+		res.Stations = len(stations)
+		res.Observations += len(result.Observations)
+		slog.Info("observations backfilled", "start", start, "end", end, "stations", len(result.Stations), "observations", len(result.Observations))
+	}
+	return res, nil
+}
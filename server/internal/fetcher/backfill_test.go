@@ -0,0 +1,94 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"wby/internal/fmi"
+	"wby/internal/weather"
+)
+
+// rangeSource serves one observation per window and fails the windows
+// starting at failAt.
+type rangeSource struct {
+	stubSource
+	windows [][2]time.Time
+	failAt  time.Time
+}
+
+func (s *rangeSource) FetchObservationsRange(ctx context.Context, start, end time.Time) (*fmi.ObservationResult, error) {
+	s.windows = append(s.windows, [2]time.Time{start, end})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if start.Equal(s.failAt) {
+		return nil, errors.New("boom")
+	}
+	return &fmi.ObservationResult{
+		Stations:     []weather.Station{{FMISID: 100971}},
+		Observations: []weather.Observation{{FMISID: 100971, ObservedAt: start}},
+	}, nil
+}
+
+type countingObservationStore struct {
+	stubObservationStore
+	observations int
+}
+
+func (s *countingObservationStore) UpsertObservations(ctx context.Context, observations []weather.Observation) error {
+	s.observations += len(observations)
+	return nil
+}
+
+func TestBackfill_WalksRangeInWindows(t *testing.T) {
+	since := time.Now().Add(-20 * 24 * time.Hour)
+	src := &rangeSource{failAt: since.Add(fmi.MaxObservationRange)}
+	store := &countingObservationStore{}
+	pub := &recordingPublisher{}
+	f := New(src, store)
+	f.SetPublisher(pub)
+
+	res, err := f.Backfill(context.Background(), since)
+	if err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	if len(src.windows) != 3 {
+		t.Fatalf("expected 3 windows, got %v", src.windows)
+	}
+	for i, w := range src.windows {
+		if w[1].Sub(w[0]) > fmi.MaxObservationRange {
+			t.Errorf("window %d spans %s", i, w[1].Sub(w[0]))
+		}
+		if i > 0 && !w[0].Equal(src.windows[i-1][1]) {
+			t.Errorf("window %d starts at %s, not where window %d ended", i, w[0], i-1)
+		}
+	}
+	if last := src.windows[2][1]; time.Since(last) > time.Minute {
+		t.Errorf("expected the last window to end now, got %s", last)
+	}
+	if res != (RefreshResult{Stations: 1, Observations: 2, Errors: 1}) {
+		t.Fatalf("unexpected result %+v", res)
+	}
+	if store.observations != 2 {
+		t.Errorf("expected 2 stored observations, got %d", store.observations)
+	}
+	if len(pub.published) != 0 {
+		t.Errorf("expected backfilled observations not to be published, got %+v", pub.published)
+	}
+}
+
+func TestBackfill_StopsWhenContextEnds(t *testing.T) {
+	src := &rangeSource{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := New(src, stubObservationStore{}).Backfill(ctx, time.Now().Add(-20*24*time.Hour))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(src.windows) != 1 {
+		t.Fatalf("expected the walk to stop after one window, got %d", len(src.windows))
+	}
+}
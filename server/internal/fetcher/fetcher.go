@@ -2,26 +2,146 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"wby/internal/fmi"
-	"wby/internal/store"
+	"wby/internal/metrics"
+	"wby/internal/weather"
 )
 
+// ObservationSource fetches observations from FMI. FetchObservations may
+// return observations along with an error when only part of its area
+// could be fetched.
+type ObservationSource interface {
+	FetchObservations(ctx context.Context) (*fmi.ObservationResult, error)
+	FetchObservationsInBBox(ctx context.Context, minLon, minLat, maxLon, maxLat float64) (*fmi.ObservationResult, error)
+	FetchObservationsRange(ctx context.Context, start, end time.Time) (*fmi.ObservationResult, error)
+}
+
+type ObservationStore interface {
+	UpsertStations(ctx context.Context, stations []weather.Station) error
+	UpsertObservations(ctx context.Context, observations []weather.Observation) error
+}
+
+// ObservationPublisher receives observations after they are stored;
+// *weather.ObservationHub implements it.
+type ObservationPublisher interface {
+	Publish(observations []weather.Observation)
+}
+
 type Fetcher struct {
-	fmi   *fmi.Client
-	store *store.Store
+	fmi   ObservationSource
+	store ObservationStore
+
+	// Set by EnableSharding; nil means this replica fetches everything.
+	coordinator Coordinator
+	instanceID  string
+	regions     []Region
+
+	runs      *metrics.CounterVec
+	status    *Status
+	publisher ObservationPublisher
+	refresh   chan chan RefreshResult
+}
+
+// RefreshResult counts what one observation fetch stored. Errors counts
+// fetches that failed, one per region for sharded replicas.
+type RefreshResult struct {
+	Stations     int
+	Observations int
+	Errors       int
+}
+
+// ErrRefreshInProgress is returned by Refresh while the loop is busy
+// fetching or has not been started.
+var ErrRefreshInProgress = errors.New("observation fetch already in progress")
+
+// Status publishes when the fetcher last stored observations, for
+// readiness checks. It is safe for concurrent use.
+type Status struct {
+	startedAt   time.Time
+	lastSuccess atomic.Int64
+}
+
+func newStatus(now time.Time) *Status {
+	return &Status{startedAt: now}
+}
+
+// StartedAt is when the fetcher was created.
+func (s *Status) StartedAt() time.Time {
+	return s.startedAt
+}
+
+// LastSuccess is when observations were last stored, or the zero time if
+// no fetch has succeeded yet.
+func (s *Status) LastSuccess() time.Time {
+	ns := s.lastSuccess.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns).UTC()
+}
+
+func (s *Status) recordSuccess(t time.Time) {
+	s.lastSuccess.Store(t.UnixNano())
+}
+
+func New(fmiClient ObservationSource, store ObservationStore) *Fetcher {
+	return &Fetcher{fmi: fmiClient, store: store, status: newStatus(time.Now()), refresh: make(chan chan RefreshResult)}
+}
+
+// Refresh makes RunObservationLoop fetch observations now instead of
+// waiting for the next tick, and returns what was stored. It does not
+// queue: when the loop is not idle it returns ErrRefreshInProgress.
+func (f *Fetcher) Refresh(ctx context.Context) (RefreshResult, error) {
+	done := make(chan RefreshResult, 1)
+	select {
+	case f.refresh <- done:
+	default:
+		return RefreshResult{}, ErrRefreshInProgress
+	}
+	select {
+	case res := <-done:
+		return res, nil
+	case <-ctx.Done():
+		return RefreshResult{}, ctx.Err()
+	}
+}
+
+// Status reports the fetcher's progress.
+func (f *Fetcher) Status() *Status {
+	return f.status
 }
 
-func New(fmiClient *fmi.Client, store *store.Store) *Fetcher {
-	return &Fetcher{fmi: fmiClient, store: store}
+// EnableSharding splits observation fetching into shards regions shared
+// between every replica that heartbeats through coord, so each replica only
+// fetches the regions assigned to it.
+func (f *Fetcher) EnableSharding(coord Coordinator, instanceID string, shards int) {
+	f.coordinator = coord
+	f.instanceID = instanceID
+	f.regions = SplitRegions(shards)
+}
+
+// SetMetrics counts observation fetches in reg by result: ok, empty,
+// fetch_error, query_rejected, response_too_large, store_error or coordination_error. Sharded replicas count
+// one result per owned region.
+func (f *Fetcher) SetMetrics(reg *metrics.Registry) {
+	f.runs = reg.Counter("wby_observation_fetch_runs_total", "Observation fetcher runs by result.", "result")
+}
+
+// SetPublisher passes every successfully stored batch of observations to
+// p, e.g. to stream them to clients.
+func (f *Fetcher) SetPublisher(p ObservationPublisher) {
+	f.publisher = p
 }
 
 func (f *Fetcher) RunObservationLoop(ctx context.Context, interval time.Duration) {
-	slog.Info("observation fetcher starting", "interval", interval)
+	slog.Info("observation fetcher starting", "interval", interval, "shards", len(f.regions), "instance", f.instanceID)
 
-	f.fetchObservations(ctx)
+	f.runOnce(ctx, interval)
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -32,36 +152,115 @@ func (f *Fetcher) RunObservationLoop(ctx context.Context, interval time.Duration
 			slog.Info("observation fetcher stopped")
 			return
 		case <-ticker.C:
-			f.fetchObservations(ctx)
+			f.runOnce(ctx, interval)
+		case done := <-f.refresh:
+			slog.Info("observation refresh requested")
+			done <- f.runOnce(ctx, interval)
+			ticker.Reset(interval)
 		}
 	}
 }
 
-func (f *Fetcher) fetchObservations(ctx context.Context) {
-	start := time.Now()
-	result, err := f.fmi.FetchObservations(ctx)
+func (f *Fetcher) runOnce(ctx context.Context, interval time.Duration) RefreshResult {
+	var res RefreshResult
+	record := func(result *fmi.ObservationResult, outcome string) {
+		f.runs.Inc(outcome)
+		switch outcome {
+		case "ok":
+			res.Stations += len(result.Stations)
+			res.Observations += len(result.Observations)
+		case "empty":
+		default:
+			res.Errors++
+		}
+	}
+
+	if f.coordinator == nil {
+		start := time.Now()
+		result, err := f.fmi.FetchObservations(ctx)
+		if err != nil && result == nil {
+			record(nil, fetchFailure(err))
+			return res
+		}
+		if err != nil {
+			slog.Warn("part of the observation fetch failed, storing the rest", "err", err)
+		}
+		record(result, f.storeObservations(ctx, result, start, "all"))
+		return res
+	}
+
+	if err := f.coordinator.Heartbeat(ctx, f.instanceID); err != nil {
+		slog.Error("fetcher heartbeat failed", "err", err)
+		record(nil, "coordination_error")
+		return res
+	}
+	// A replica that missed three ticks is considered gone and its regions
+	// move to the survivors.
+	live, err := f.coordinator.LiveInstances(ctx, 3*interval)
 	if err != nil {
-		slog.Error("failed to fetch observations from FMI", "err", err)
-		return
+		slog.Error("failed to list live fetcher instances", "err", err)
+		record(nil, "coordination_error")
+		return res
 	}
+	owned := AssignRegions(f.regions, live, f.instanceID)
+	slog.Info("fetcher regions assigned", "instance", f.instanceID, "live_instances", len(live), "owned", len(owned), "total", len(f.regions))
+
+	for _, r := range owned {
+		start := time.Now()
+		result, err := f.fmi.FetchObservationsInBBox(ctx, r.MinLon, r.MinLat, r.MaxLon, r.MaxLat)
+		if err != nil {
+			record(nil, fetchFailure(err, "region", r.Name))
+			continue
+		}
+		record(result, f.storeObservations(ctx, result, start, r.Name))
+	}
+	return res
+}
+
+// fetchFailure logs a failed observation fetch and returns its run result:
+// query_rejected when FMI refused the query, which retrying won't fix,
+// response_too_large when the response passed the client's size cap, and
+// fetch_error otherwise.
+func fetchFailure(err error, args ...any) string {
+	var apiErr *fmi.APIError
+	if errors.As(err, &apiErr) && apiErr.QueryRejected() {
+		slog.Error("FMI rejected the observation query", append([]any{"err", err, "code", apiErr.Code}, args...)...)
+		return "query_rejected"
+	}
+	if errors.Is(err, fmi.ErrResponseTooLarge) {
+		slog.Error("FMI observation response exceeded the size limit", append([]any{"err", err}, args...)...)
+		return "response_too_large"
+	}
+	slog.Error("failed to fetch observations from FMI", append([]any{"err", err}, args...)...)
+	return "fetch_error"
+}
+
+// storeObservations persists one fetch and returns its run result.
+func (f *Fetcher) storeObservations(ctx context.Context, result *fmi.ObservationResult, start time.Time, region string) string {
 	if len(result.Stations) == 0 {
-		slog.Warn("observation fetch returned no stations")
-		return
+		slog.Warn("observation fetch returned no stations", "region", region)
+		return "empty"
 	}
 
 	if err := f.store.UpsertStations(ctx, result.Stations); err != nil {
 		slog.Error("failed to upsert stations", "err", err)
-		return
+		return "store_error"
 	}
 
 	if err := f.store.UpsertObservations(ctx, result.Observations); err != nil {
 		slog.Error("failed to upsert observations", "err", err)
-		return
+		return "store_error"
+	}
+	f.status.recordSuccess(time.Now())
+	if f.publisher != nil {
+		f.publisher.Publish(result.Observations)
 	}
 
 	slog.Info("observations fetched",
+		"region", region,
 		"stations", len(result.Stations),
 		"observations", len(result.Observations),
 		"duration", time.Since(start),
 	)
+	return "ok"
 }
@@ -0,0 +1,127 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"wby/internal/fmi"
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+type stubSource struct {
+	result *fmi.ObservationResult
+	err    error
+}
+
+func (s stubSource) FetchObservations(ctx context.Context) (*fmi.ObservationResult, error) {
+	return s.result, s.err
+}
+
+func (s stubSource) FetchObservationsInBBox(ctx context.Context, minLon, minLat, maxLon, maxLat float64) (*fmi.ObservationResult, error) {
+	return s.result, s.err
+}
+
+func (s stubSource) FetchObservationsRange(ctx context.Context, start, end time.Time) (*fmi.ObservationResult, error) {
+	return s.result, s.err
+}
+
+type stubObservationStore struct{}
+
+func (stubObservationStore) UpsertStations(ctx context.Context, stations []weather.Station) error {
+	return nil
+}
+
+func (stubObservationStore) UpsertObservations(ctx context.Context, observations []weather.Observation) error {
+	return nil
+}
+
+func TestRunOnce_CountsResults(t *testing.T) {
+	reg := metrics.NewRegistry()
+	ok := &fmi.ObservationResult{Stations: []weather.Station{{FMISID: 100971}}}
+
+	rejected := &fmi.APIError{Code: "OperationParsingFailed", HTTPStatus: 400}
+	tooLarge := fmt.Errorf("fetch observations: %w", fmi.ErrResponseTooLarge)
+	for _, src := range []stubSource{{result: ok}, {result: ok}, {result: &fmi.ObservationResult{}}, {err: errors.New("boom")}, {err: rejected}, {err: tooLarge}} {
+		f := New(src, stubObservationStore{})
+		f.SetMetrics(reg)
+		f.runOnce(context.Background(), time.Minute)
+	}
+
+	for result, want := range map[string]float64{"ok": 2, "empty": 1, "fetch_error": 1, "query_rejected": 1, "response_too_large": 1, "store_error": 0} {
+		if got := reg.Value("wby_observation_fetch_runs_total", result); got != want {
+			t.Errorf("expected %v %s runs, got %v", want, result, got)
+		}
+	}
+}
+
+func TestRunOnce_StoresPartialResults(t *testing.T) {
+	reg := metrics.NewRegistry()
+	partial := &fmi.ObservationResult{Stations: []weather.Station{{FMISID: 100971}}}
+	f := New(stubSource{result: partial, err: errors.New("chunk 27.5,65,32,71: timeout")}, stubObservationStore{})
+	f.SetMetrics(reg)
+	f.runOnce(context.Background(), time.Minute)
+
+	if got := reg.Value("wby_observation_fetch_runs_total", "ok"); got != 1 {
+		t.Fatalf("expected the fetched part to be stored, got %v ok runs", got)
+	}
+}
+
+type recordingPublisher struct {
+	published [][]weather.Observation
+}
+
+func (p *recordingPublisher) Publish(observations []weather.Observation) {
+	p.published = append(p.published, observations)
+}
+
+func TestRunOnce_PublishesStoredObservations(t *testing.T) {
+	observations := []weather.Observation{{FMISID: 100971, ObservedAt: time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)}}
+	pub := &recordingPublisher{}
+
+	f := New(stubSource{result: &fmi.ObservationResult{Stations: []weather.Station{{FMISID: 100971}}, Observations: observations}}, stubObservationStore{})
+	f.SetPublisher(pub)
+	f.runOnce(context.Background(), time.Minute)
+
+	f = New(stubSource{err: errors.New("boom")}, stubObservationStore{})
+	f.SetPublisher(pub)
+	f.runOnce(context.Background(), time.Minute)
+
+	if len(pub.published) != 1 || len(pub.published[0]) != 1 || pub.published[0][0].FMISID != 100971 {
+		t.Fatalf("expected one published batch, got %+v", pub.published)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	result := &fmi.ObservationResult{
+		Stations:     []weather.Station{{FMISID: 100971}, {FMISID: 101004}},
+		Observations: []weather.Observation{{FMISID: 100971}, {FMISID: 101004}, {FMISID: 101004}},
+	}
+	f := New(stubSource{result: result}, stubObservationStore{})
+
+	if _, err := f.Refresh(context.Background()); !errors.Is(err, ErrRefreshInProgress) {
+		t.Fatalf("expected ErrRefreshInProgress before the loop runs, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go f.RunObservationLoop(ctx, time.Hour)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		res, err := f.Refresh(ctx)
+		if err == nil {
+			if res != (RefreshResult{Stations: 2, Observations: 3}) {
+				t.Fatalf("unexpected refresh result %+v", res)
+			}
+			return
+		}
+		if !errors.Is(err, ErrRefreshInProgress) || time.Now().After(deadline) {
+			t.Fatalf("refresh failed: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,111 @@
+package fetcher
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+const (
+	// ThunderstormThreshold is the daily thunderstorm probability (percent)
+	// anywhere in Finland above which lightning is fetched. It is a daily
+	// average of hourly probabilities, so even stormy days rarely exceed 30.
+	ThunderstormThreshold = 10.0
+
+	// lightningOverlap refetches the tail of the previous window, as FMI
+	// publishes strikes with a delay of a few minutes.
+	lightningOverlap = 10 * time.Minute
+	// lightningBackfill is how far back the first fetch after a quiet
+	// period reaches.
+	lightningBackfill = time.Hour
+)
+
+// lightningBBox covers Finland and its surrounding sea areas.
+var lightningBBox = weather.BBox{MinLon: 19, MinLat: 59, MaxLon: 32, MaxLat: 71}
+
+type LightningSource interface {
+	FetchLightning(ctx context.Context, bbox weather.BBox, since time.Time) ([]weather.LightningStrike, error)
+}
+
+type LightningStore interface {
+	UpsertLightning(ctx context.Context, strikes []weather.LightningStrike) error
+	MaxThunderstormProbability(ctx context.Context, day time.Time) (float64, error)
+}
+
+// LightningFetcher stores lightning strikes, but only while the stored
+// forecasts give a high thunderstorm probability for today, so quiet days
+// cost no FMI requests.
+type LightningFetcher struct {
+	source LightningSource
+	store  LightningStore
+	runs   *metrics.CounterVec
+
+	// since is where the next fetch starts; zero after a quiet period.
+	since time.Time
+}
+
+func NewLightningFetcher(source LightningSource, store LightningStore) *LightningFetcher {
+	return &LightningFetcher{source: source, store: store}
+}
+
+// SetMetrics counts lightning fetcher runs in reg by result: ok, quiet,
+// gate_error, fetch_error or store_error.
+func (f *LightningFetcher) SetMetrics(reg *metrics.Registry) {
+	f.runs = reg.Counter("wby_lightning_fetch_runs_total", "Lightning fetcher runs by result.", "result")
+}
+
+func (f *LightningFetcher) RunLoop(ctx context.Context, interval time.Duration) {
+	slog.Info("lightning fetcher starting", "interval", interval, "threshold", ThunderstormThreshold)
+
+	f.runOnce(ctx, time.Now())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("lightning fetcher stopped")
+			return
+		case now := <-ticker.C:
+			f.runOnce(ctx, now)
+		}
+	}
+}
+
+func (f *LightningFetcher) runOnce(ctx context.Context, now time.Time) {
+	now = now.UTC()
+	p, err := f.store.MaxThunderstormProbability(ctx, now.Truncate(24*time.Hour))
+	if err != nil {
+		slog.Error("failed to read thunderstorm probability", "err", err)
+		f.runs.Inc("gate_error")
+		return
+	}
+	if p < ThunderstormThreshold {
+		f.since = time.Time{}
+		f.runs.Inc("quiet")
+		return
+	}
+
+	since := f.since
+	if since.IsZero() {
+		since = now.Add(-lightningBackfill)
+	}
+	strikes, err := f.source.FetchLightning(ctx, lightningBBox, since)
+	if err != nil {
+		slog.Error("failed to fetch lightning from FMI", "err", err)
+		f.runs.Inc("fetch_error")
+		return
+	}
+	if err := f.store.UpsertLightning(ctx, strikes); err != nil {
+		slog.Error("failed to store lightning", "err", err)
+		f.runs.Inc("store_error")
+		return
+	}
+	f.since = now.Add(-lightningOverlap)
+	slog.Info("stored lightning strikes", "count", len(strikes), "thunderstorm_probability", p)
+	f.runs.Inc("ok")
+}
@@ -0,0 +1,60 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+type recordingLightningSource struct {
+	since []time.Time
+}
+
+func (s *recordingLightningSource) FetchLightning(ctx context.Context, bbox weather.BBox, since time.Time) ([]weather.LightningStrike, error) {
+	s.since = append(s.since, since)
+	return []weather.LightningStrike{{Time: since, Lat: 61.5, Lon: 23.8}}, nil
+}
+
+type stubLightningStore struct {
+	probability *float64
+	stored      int
+}
+
+func (s *stubLightningStore) UpsertLightning(ctx context.Context, strikes []weather.LightningStrike) error {
+	s.stored += len(strikes)
+	return nil
+}
+
+func (s *stubLightningStore) MaxThunderstormProbability(ctx context.Context, day time.Time) (float64, error) {
+	return *s.probability, nil
+}
+
+func TestLightningFetcher_OnlyFetchesWhenThunderstormsLikely(t *testing.T) {
+	probability := 2.0
+	src := &recordingLightningSource{}
+	store := &stubLightningStore{probability: &probability}
+	now := time.Date(2026, 7, 15, 12, 0, 0, 0, time.UTC)
+
+	f := NewLightningFetcher(src, store)
+	f.runOnce(context.Background(), now)
+	if len(src.since) != 0 {
+		t.Fatalf("expected no fetch on a quiet day, got %d", len(src.since))
+	}
+
+	probability = 25
+	f.runOnce(context.Background(), now)
+	f.runOnce(context.Background(), now.Add(5*time.Minute))
+
+	if len(src.since) != 2 || store.stored != 2 {
+		t.Fatalf("expected two fetches and stores, got %d fetches and %d strikes", len(src.since), store.stored)
+	}
+	if want := time.Date(2026, 7, 15, 11, 0, 0, 0, time.UTC); !src.since[0].Equal(want) {
+		t.Errorf("first fetch since %v, want %v", src.since[0], want)
+	}
+	// The next window overlaps the previous one to catch late strikes.
+	if want := time.Date(2026, 7, 15, 11, 50, 0, 0, time.UTC); !src.since[1].Equal(want) {
+		t.Errorf("second fetch since %v, want %v", src.since[1], want)
+	}
+}
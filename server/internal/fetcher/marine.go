@@ -0,0 +1,32 @@
+package fetcher
+
+import (
+	"context"
+
+	"wby/internal/fmi"
+	"wby/internal/weather"
+)
+
+type MarineSource interface {
+	FetchMarine(ctx context.Context) (*fmi.MarineResult, error)
+}
+
+type MarineStore interface {
+	UpsertMarineStations(ctx context.Context, stations []weather.Station) error
+	UpsertMarineObservations(ctx context.Context, observations []weather.MarineObservation) error
+}
+
+// MarineFetcher periodically stores the latest wave buoy and mareograph
+// observations.
+type MarineFetcher = StationFetcher[weather.MarineObservation]
+
+func NewMarineFetcher(source MarineSource, store MarineStore) *MarineFetcher {
+	fetch := func(ctx context.Context) ([]weather.Station, []weather.MarineObservation, error) {
+		result, err := source.FetchMarine(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Stations, result.Observations, nil
+	}
+	return newStationFetcher("marine", fetch, store.UpsertMarineStations, store.UpsertMarineObservations)
+}
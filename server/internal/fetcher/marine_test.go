@@ -0,0 +1,54 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wby/internal/fmi"
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+type stubMarineSource struct {
+	result *fmi.MarineResult
+}
+
+func (s stubMarineSource) FetchMarine(ctx context.Context) (*fmi.MarineResult, error) {
+	return s.result, nil
+}
+
+type recordingMarineStore struct {
+	stations     []weather.Station
+	observations []weather.MarineObservation
+}
+
+func (s *recordingMarineStore) UpsertMarineStations(ctx context.Context, stations []weather.Station) error {
+	s.stations = append(s.stations, stations...)
+	return nil
+}
+
+func (s *recordingMarineStore) UpsertMarineObservations(ctx context.Context, observations []weather.MarineObservation) error {
+	s.observations = append(s.observations, observations...)
+	return nil
+}
+
+func TestMarineFetcherRunOnce(t *testing.T) {
+	reg := metrics.NewRegistry()
+	wave := 0.9
+	result := &fmi.MarineResult{
+		Stations:     []weather.Station{{FMISID: 134220, Name: "Helsinki Suomenlahti aaltopoiju"}},
+		Observations: []weather.MarineObservation{{FMISID: 134220, ObservedAt: time.Now(), WaveHeight: &wave}},
+	}
+
+	store := &recordingMarineStore{}
+	f := NewMarineFetcher(stubMarineSource{result: result}, store)
+	f.SetMetrics(reg)
+	f.runOnce(context.Background())
+	if len(store.stations) != 1 || len(store.observations) != 1 {
+		t.Fatalf("expected stations and observations to be stored, got %d and %d", len(store.stations), len(store.observations))
+	}
+	if got := reg.Value("wby_marine_fetch_runs_total", "ok"); got != 1 {
+		t.Errorf("expected 1 ok run, got %v", got)
+	}
+}
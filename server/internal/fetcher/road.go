@@ -0,0 +1,32 @@
+package fetcher
+
+import (
+	"context"
+
+	"wby/internal/fmi"
+	"wby/internal/weather"
+)
+
+type RoadSource interface {
+	FetchRoadObservations(ctx context.Context) (*fmi.RoadResult, error)
+}
+
+type RoadStore interface {
+	UpsertRoadStations(ctx context.Context, stations []weather.Station) error
+	UpsertRoadObservations(ctx context.Context, observations []weather.RoadObservation) error
+}
+
+// RoadFetcher periodically stores the latest road weather station
+// observations.
+type RoadFetcher = StationFetcher[weather.RoadObservation]
+
+func NewRoadFetcher(source RoadSource, store RoadStore) *RoadFetcher {
+	fetch := func(ctx context.Context) ([]weather.Station, []weather.RoadObservation, error) {
+		result, err := source.FetchRoadObservations(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return result.Stations, result.Observations, nil
+	}
+	return newStationFetcher("road", fetch, store.UpsertRoadStations, store.UpsertRoadObservations)
+}
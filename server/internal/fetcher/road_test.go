@@ -0,0 +1,54 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wby/internal/fmi"
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+type stubRoadSource struct {
+	result *fmi.RoadResult
+}
+
+func (s stubRoadSource) FetchRoadObservations(ctx context.Context) (*fmi.RoadResult, error) {
+	return s.result, nil
+}
+
+type recordingRoadStore struct {
+	stations     []weather.Station
+	observations []weather.RoadObservation
+}
+
+func (s *recordingRoadStore) UpsertRoadStations(ctx context.Context, stations []weather.Station) error {
+	s.stations = append(s.stations, stations...)
+	return nil
+}
+
+func (s *recordingRoadStore) UpsertRoadObservations(ctx context.Context, observations []weather.RoadObservation) error {
+	s.observations = append(s.observations, observations...)
+	return nil
+}
+
+func TestRoadFetcherRunOnce(t *testing.T) {
+	reg := metrics.NewRegistry()
+	road := -6.1
+	result := &fmi.RoadResult{
+		Stations:     []weather.Station{{FMISID: 100011, Name: "vt1 Espoo Nupuri"}},
+		Observations: []weather.RoadObservation{{FMISID: 100011, ObservedAt: time.Now(), RoadTemperature: &road}},
+	}
+
+	store := &recordingRoadStore{}
+	f := NewRoadFetcher(stubRoadSource{result: result}, store)
+	f.SetMetrics(reg)
+	f.runOnce(context.Background())
+	if len(store.stations) != 1 || len(store.observations) != 1 {
+		t.Fatalf("expected stations and observations to be stored, got %d and %d", len(store.stations), len(store.observations))
+	}
+	if got := reg.Value("wby_road_fetch_runs_total", "ok"); got != 1 {
+		t.Errorf("expected 1 ok run, got %v", got)
+	}
+}
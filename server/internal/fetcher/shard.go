@@ -0,0 +1,88 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"time"
+)
+
+// Observation coverage bbox; matches the Finland bounds in internal/fmi.
+const (
+	coverageMinLon = 19.0
+	coverageMinLat = 59.0
+	coverageMaxLon = 32.0
+	coverageMaxLat = 71.0
+)
+
+// Region is one shard of background fetch work.
+type Region struct {
+	Name   string
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+// SplitRegions divides the coverage area into n latitude bands. Stations
+// are denser in the south, but equal bands keep shard boundaries stable
+// across releases, which matters more than perfect balance.
+func SplitRegions(n int) []Region {
+	if n < 1 {
+		n = 1
+	}
+	step := (coverageMaxLat - coverageMinLat) / float64(n)
+	regions := make([]Region, n)
+	for i := range regions {
+		regions[i] = Region{
+			Name:   fmt.Sprintf("band-%d-of-%d", i+1, n),
+			MinLon: coverageMinLon,
+			MinLat: coverageMinLat + float64(i)*step,
+			MaxLon: coverageMaxLon,
+			MaxLat: coverageMinLat + float64(i+1)*step,
+		}
+	}
+	regions[n-1].MaxLat = coverageMaxLat
+	return regions
+}
+
+// Coordinator tracks which replicas are alive, via the database.
+type Coordinator interface {
+	Heartbeat(ctx context.Context, instanceID string) error
+	LiveInstances(ctx context.Context, within time.Duration) ([]string, error)
+}
+
+// AssignRegions returns the regions owned by self using rendezvous hashing:
+// each region goes to the live instance with the highest hash of
+// (region, instance). Every replica computes the same assignment from the
+// same membership list, and a replica joining or leaving only moves the
+// regions it wins or held.
+func AssignRegions(regions []Region, instances []string, self string) []Region {
+	if !slices.Contains(instances, self) {
+		instances = append(slices.Clone(instances), self)
+	}
+	var owned []Region
+	for _, r := range regions {
+		var winner string
+		var best uint64
+		for _, inst := range instances {
+			h := rendezvousHash(r.Name, inst)
+			if winner == "" || h > best || (h == best && inst < winner) {
+				winner, best = inst, h
+			}
+		}
+		if winner == self {
+			owned = append(owned, r)
+		}
+	}
+	return owned
+}
+
+func rendezvousHash(region, instance string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(region))
+	h.Write([]byte{0})
+	h.Write([]byte(instance))
+	return h.Sum64()
+}
@@ -0,0 +1,62 @@
+package fetcher
+
+import "testing"
+
+func TestSplitRegions_CoversAreaWithoutGaps(t *testing.T) {
+	regions := SplitRegions(4)
+	if len(regions) != 4 {
+		t.Fatalf("expected 4 regions, got %d", len(regions))
+	}
+	if regions[0].MinLat != coverageMinLat || regions[3].MaxLat != coverageMaxLat {
+		t.Fatalf("expected bands to span the coverage area, got %+v", regions)
+	}
+	for i := 1; i < len(regions); i++ {
+		if regions[i].MinLat != regions[i-1].MaxLat {
+			t.Fatalf("gap between band %d and %d", i-1, i)
+		}
+	}
+}
+
+func TestAssignRegions_PartitionsAcrossInstances(t *testing.T) {
+	regions := SplitRegions(16)
+	instances := []string{"replica-a", "replica-b", "replica-c"}
+
+	owner := map[string]string{}
+	for _, inst := range instances {
+		for _, r := range AssignRegions(regions, instances, inst) {
+			if prev, ok := owner[r.Name]; ok {
+				t.Fatalf("region %s assigned to both %s and %s", r.Name, prev, inst)
+			}
+			owner[r.Name] = inst
+		}
+	}
+	if len(owner) != len(regions) {
+		t.Fatalf("expected every region to be owned, got %d of %d", len(owner), len(regions))
+	}
+}
+
+func TestAssignRegions_LeavingInstanceOnlyMovesItsRegions(t *testing.T) {
+	regions := SplitRegions(16)
+	before := []string{"replica-a", "replica-b", "replica-c"}
+	after := []string{"replica-a", "replica-b"}
+
+	for _, inst := range after {
+		kept := map[string]bool{}
+		for _, r := range AssignRegions(regions, after, inst) {
+			kept[r.Name] = true
+		}
+		for _, r := range AssignRegions(regions, before, inst) {
+			if !kept[r.Name] {
+				t.Fatalf("%s lost region %s when another replica left", inst, r.Name)
+			}
+		}
+	}
+}
+
+func TestAssignRegions_SelfMissingFromMembership(t *testing.T) {
+	regions := SplitRegions(8)
+	owned := AssignRegions(regions, nil, "only-replica")
+	if len(owned) != len(regions) {
+		t.Fatalf("expected a lone replica to own all %d regions, got %d", len(regions), len(owned))
+	}
+}
@@ -0,0 +1,80 @@
+package fetcher
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+// StationFetcher periodically stores the stations of one of FMI's separate
+// observation networks, such as road weather or air quality stations, and
+// their latest readings. Readings are only stored once their stations are.
+type StationFetcher[T any] struct {
+	network        string
+	fetch          func(ctx context.Context) ([]weather.Station, []T, error)
+	upsertStations func(ctx context.Context, stations []weather.Station) error
+	upsertReadings func(ctx context.Context, readings []T) error
+	runs           *metrics.CounterVec
+}
+
+func newStationFetcher[T any](
+	network string,
+	fetch func(ctx context.Context) ([]weather.Station, []T, error),
+	upsertStations func(ctx context.Context, stations []weather.Station) error,
+	upsertReadings func(ctx context.Context, readings []T) error,
+) *StationFetcher[T] {
+	return &StationFetcher[T]{network: network, fetch: fetch, upsertStations: upsertStations, upsertReadings: upsertReadings}
+}
+
+// SetMetrics counts fetches in reg by result: ok, fetch_error or
+// store_error. The counter is named after the network, e.g.
+// wby_road_fetch_runs_total.
+func (f *StationFetcher[T]) SetMetrics(reg *metrics.Registry) {
+	name := "wby_" + strings.ReplaceAll(f.network, " ", "_") + "_fetch_runs_total"
+	help := strings.ToUpper(f.network[:1]) + f.network[1:] + " fetcher runs by result."
+	f.runs = reg.Counter(name, help, "result")
+}
+
+func (f *StationFetcher[T]) RunLoop(ctx context.Context, interval time.Duration) {
+	slog.Info("station fetcher starting", "network", f.network, "interval", interval)
+
+	f.runOnce(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("station fetcher stopped", "network", f.network)
+			return
+		case <-ticker.C:
+			f.runOnce(ctx)
+		}
+	}
+}
+
+func (f *StationFetcher[T]) runOnce(ctx context.Context) {
+	stations, readings, err := f.fetch(ctx)
+	if err != nil {
+		slog.Error("failed to fetch station readings", "network", f.network, "err", err)
+		f.runs.Inc("fetch_error")
+		return
+	}
+	if err := f.upsertStations(ctx, stations); err != nil {
+		slog.Error("failed to store stations", "network", f.network, "err", err)
+		f.runs.Inc("store_error")
+		return
+	}
+	if err := f.upsertReadings(ctx, readings); err != nil {
+		slog.Error("failed to store station readings", "network", f.network, "err", err)
+		f.runs.Inc("store_error")
+		return
+	}
+	slog.Info("stored station readings", "network", f.network, "stations", len(stations), "readings", len(readings))
+	f.runs.Inc("ok")
+}
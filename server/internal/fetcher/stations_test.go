@@ -0,0 +1,54 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+func TestStationFetcherRunOnce(t *testing.T) {
+	reg := metrics.NewRegistry()
+	stations := []weather.Station{{FMISID: 100011, Name: "vt1 Espoo Nupuri"}}
+	readings := []int{1, 2}
+	var fetchErr, storeErr error
+	var storedStations, storedReadings int
+	f := newStationFetcher("test network",
+		func(context.Context) ([]weather.Station, []int, error) {
+			return stations, readings, fetchErr
+		},
+		func(_ context.Context, s []weather.Station) error {
+			storedStations += len(s)
+			return storeErr
+		},
+		func(_ context.Context, r []int) error {
+			storedReadings += len(r)
+			return storeErr
+		},
+	)
+	f.SetMetrics(reg)
+
+	f.runOnce(context.Background())
+	if storedStations != 1 || storedReadings != 2 {
+		t.Fatalf("expected stations and readings to be stored, got %d and %d", storedStations, storedReadings)
+	}
+
+	fetchErr = errors.New("boom")
+	f.runOnce(context.Background())
+
+	// Readings are not stored when their stations could not be.
+	fetchErr, storeErr = nil, errors.New("db down")
+	storedReadings = 0
+	f.runOnce(context.Background())
+	if storedReadings != 0 {
+		t.Fatalf("expected no readings after a station store failure, got %d", storedReadings)
+	}
+
+	for result, want := range map[string]float64{"ok": 1, "fetch_error": 1, "store_error": 1} {
+		if got := reg.Value("wby_test_network_fetch_runs_total", result); got != want {
+			t.Errorf("expected %v %s runs, got %v", want, result, got)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package fetcher
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+type WarningSource interface {
+	FetchWarnings(ctx context.Context) ([]weather.Warning, error)
+}
+
+type WarningStore interface {
+	UpsertWarnings(ctx context.Context, warnings []weather.Warning) error
+}
+
+// WarningFetcher keeps the stored weather warnings in sync with the CAP
+// feed. Each run replaces the stored set, so cancelled and expired
+// warnings disappear on the next refresh.
+type WarningFetcher struct {
+	source WarningSource
+	store  WarningStore
+	runs   *metrics.CounterVec
+}
+
+func NewWarningFetcher(source WarningSource, store WarningStore) *WarningFetcher {
+	return &WarningFetcher{source: source, store: store}
+}
+
+// SetMetrics counts warning fetches in reg by result: ok, fetch_error or
+// store_error.
+func (f *WarningFetcher) SetMetrics(reg *metrics.Registry) {
+	f.runs = reg.Counter("wby_warning_fetch_runs_total", "Warning fetcher runs by result.", "result")
+}
+
+func (f *WarningFetcher) RunLoop(ctx context.Context, interval time.Duration) {
+	slog.Info("warning fetcher starting", "interval", interval)
+
+	f.runOnce(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("warning fetcher stopped")
+			return
+		case <-ticker.C:
+			f.runOnce(ctx)
+		}
+	}
+}
+
+func (f *WarningFetcher) runOnce(ctx context.Context) {
+	warnings, err := f.source.FetchWarnings(ctx)
+	if err != nil {
+		// Keep the stored warnings; they still expire on their own.
+		slog.Error("failed to fetch warnings", "err", err)
+		f.runs.Inc("fetch_error")
+		return
+	}
+	if err := f.store.UpsertWarnings(ctx, warnings); err != nil {
+		slog.Error("failed to store warnings", "err", err)
+		f.runs.Inc("store_error")
+		return
+	}
+	slog.Info("stored warnings", "count", len(warnings))
+	f.runs.Inc("ok")
+}
@@ -0,0 +1,60 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+type stubWarningSource struct {
+	warnings []weather.Warning
+	err      error
+}
+
+func (s stubWarningSource) FetchWarnings(ctx context.Context) ([]weather.Warning, error) {
+	return s.warnings, s.err
+}
+
+type recordingWarningStore struct {
+	calls [][]weather.Warning
+	err   error
+}
+
+func (s *recordingWarningStore) UpsertWarnings(ctx context.Context, warnings []weather.Warning) error {
+	s.calls = append(s.calls, warnings)
+	return s.err
+}
+
+func TestWarningFetcherRunOnce(t *testing.T) {
+	reg := metrics.NewRegistry()
+	warnings := []weather.Warning{{ID: "a"}, {ID: "b"}}
+
+	store := &recordingWarningStore{}
+	f := NewWarningFetcher(stubWarningSource{warnings: warnings}, store)
+	f.SetMetrics(reg)
+	f.runOnce(context.Background())
+	if len(store.calls) != 1 || len(store.calls[0]) != 2 {
+		t.Fatalf("expected the feed to be stored once, got %v", store.calls)
+	}
+
+	// A failed fetch must not wipe the stored warnings.
+	f = NewWarningFetcher(stubWarningSource{err: errors.New("boom")}, store)
+	f.SetMetrics(reg)
+	f.runOnce(context.Background())
+	if len(store.calls) != 1 {
+		t.Fatalf("expected no store call after a failed fetch, got %d", len(store.calls))
+	}
+
+	f = NewWarningFetcher(stubWarningSource{}, &recordingWarningStore{err: errors.New("db down")})
+	f.SetMetrics(reg)
+	f.runOnce(context.Background())
+
+	for result, want := range map[string]float64{"ok": 1, "fetch_error": 1, "store_error": 1} {
+		if got := reg.Value("wby_warning_fetch_runs_total", result); got != want {
+			t.Errorf("expected %v %s runs, got %v", want, result, got)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package fmi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+const (
+	// DefaultCircuitFailures is how many FMI calls in a row must fail before
+	// the circuit breaker opens.
+	DefaultCircuitFailures = 5
+	// DefaultCircuitCooldown is how long the breaker stays open before it
+	// lets a probe request through.
+	DefaultCircuitCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without contacting FMI while the circuit
+// breaker is open. It matches weather.ErrUpstreamUnavailable, so callers
+// fall back to stored data as they would for any outage.
+var ErrCircuitOpen error = circuitOpenError{}
+
+type circuitOpenError struct{}
+
+func (circuitOpenError) Error() string { return "fmi: circuit breaker open" }
+
+func (circuitOpenError) Is(target error) bool { return target == weather.ErrUpstreamUnavailable }
+
+type circuitState string
+
+const (
+	circuitClosed   circuitState = "closed"
+	circuitOpen     circuitState = "open"
+	circuitHalfOpen circuitState = "half_open"
+)
+
+// breaker is a circuit breaker shared by every FMI call a Client makes.
+// After threshold consecutive failures it rejects calls for cooldown, then
+// lets a single probe through: its success closes the circuit again and
+// its failure restarts the cooldown.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+
+	transitions *metrics.CounterVec
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown, now: time.Now, state: circuitClosed}
+}
+
+// allow reports whether a call may go ahead, returning ErrCircuitOpen when
+// it may not. Every allowed call must be followed by done.
+func (b *breaker) allow(ctx context.Context) error {
+	if b.threshold < 1 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.transition(ctx, circuitHalfOpen)
+	case circuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+	default:
+		return nil
+	}
+	b.probing = true
+	return nil
+}
+
+// done records the outcome of an allowed call. A caller giving up says
+// nothing about FMI's health and neither trips nor closes the circuit; an
+// abandoned probe just lets the next call probe instead.
+func (b *breaker) done(ctx context.Context, err error) {
+	if b.threshold < 1 {
+		return
+	}
+	failed := err != nil && retryable(ctx, err)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	switch {
+	case failed:
+		b.failures++
+		if b.state == circuitHalfOpen || (b.state == circuitClosed && b.failures >= b.threshold) {
+			b.openedAt = b.now()
+			b.transition(ctx, circuitOpen, "failures", b.failures, "err", err)
+		}
+	case err == nil || ctx.Err() == nil:
+		b.failures = 0
+		if b.state != circuitClosed {
+			b.transition(ctx, circuitClosed)
+		}
+	}
+}
+
+func (b *breaker) transition(ctx context.Context, to circuitState, args ...any) {
+	from := b.state
+	b.state = to
+	b.transitions.Inc(string(to))
+	log := logging.FromContext(ctx)
+	args = append([]any{"from", from, "to", to}, args...)
+	if to == circuitOpen {
+		log.Warn("FMI circuit breaker opened", append(args, "cooldown", b.cooldown)...)
+		return
+	}
+	log.Info("FMI circuit breaker state changed", args...)
+}
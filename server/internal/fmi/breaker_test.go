@@ -0,0 +1,128 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if code := int(status.Load()); code != http.StatusOK {
+			http.Error(w, "unavailable", code)
+			return
+		}
+		w.Write(observations)
+	}))
+	defer srv.Close()
+
+	reg := metrics.NewRegistry()
+	c := NewClient(srv.URL, "", "")
+	c.SetMetrics(reg)
+	c.SetRetry(1, 0)
+	c.SetCircuitBreaker(2, time.Minute)
+	now := time.Now()
+	c.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := c.FetchObservations(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected the upstream error, got %v", err)
+		}
+	}
+	_, err = c.FetchForecast(ctx, 60.17, 24.94, 3)
+	if !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, weather.ErrUpstreamUnavailable) {
+		t.Fatalf("expected ErrCircuitOpen after 2 failures, got %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("expected no request while open, got %d requests", got)
+	}
+
+	// A failed probe after the cooldown reopens the circuit.
+	now = now.Add(time.Minute)
+	if _, err := c.FetchObservations(ctx); errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("expected a probe after the cooldown")
+	}
+	if _, err := c.FetchObservations(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the failed probe to reopen the circuit, got %v", err)
+	}
+
+	// A successful probe closes it.
+	now = now.Add(time.Minute)
+	status.Store(http.StatusOK)
+	if _, err := c.FetchObservations(ctx); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if _, err := c.FetchObservations(ctx); err != nil {
+		t.Fatalf("expected the circuit closed, got %v", err)
+	}
+
+	for state, want := range map[circuitState]float64{circuitOpen: 2, circuitHalfOpen: 2, circuitClosed: 1} {
+		if got := reg.Value("wby_fmi_circuit_transitions_total", string(state)); got != want {
+			t.Errorf("expected %v transitions to %s, got %v", want, state, got)
+		}
+	}
+}
+
+func TestBreaker_IgnoresHealthyFailures(t *testing.T) {
+	ctx := context.Background()
+	b := newBreaker(1, time.Minute)
+
+	// FMI answered: rejected queries and malformed bodies don't count.
+	for _, err := range []error{
+		&APIError{Code: "OperationParsingFailed", HTTPStatus: http.StatusBadRequest},
+		&decodeError{err: errors.New("EOF")},
+	} {
+		if err := b.allow(ctx); err != nil {
+			t.Fatal(err)
+		}
+		b.done(ctx, err)
+	}
+	// Nor does the caller giving up.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := b.allow(canceled); err != nil {
+		t.Fatal(err)
+	}
+	b.done(canceled, context.Canceled)
+
+	if err := b.allow(ctx); err != nil {
+		t.Fatalf("expected the circuit closed, got %v", err)
+	}
+	b.done(ctx, &StatusError{StatusCode: http.StatusBadGateway})
+	if err := b.allow(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected a 502 to open the circuit, got %v", err)
+	}
+}
+
+func TestClient_CircuitBreakerDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetRetry(1, 0)
+	c.SetCircuitBreaker(0, time.Minute)
+	for range DefaultCircuitFailures + 1 {
+		if _, err := c.FetchObservations(context.Background()); errors.Is(err, ErrCircuitOpen) {
+			t.Fatal("expected the breaker disabled")
+		}
+	}
+}
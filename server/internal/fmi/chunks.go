@@ -0,0 +1,125 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"wby/internal/weather"
+)
+
+// DefaultObservationChunkConcurrency is how many observation chunks are
+// fetched at once.
+const DefaultObservationChunkConcurrency = 4
+
+// SetObservationChunks splits FetchObservations of a bbox filter into a
+// grid of cols by rows chunks, fetched concurrency at a time, so a slow or
+// failed part of the country doesn't cost the whole fetch. 1x1, the
+// default, fetches the bbox in one request; sizes below 1 count as 1, and
+// a concurrency below 1 is DefaultObservationChunkConcurrency.
+func (c *Client) SetObservationChunks(cols, rows, concurrency int) {
+	c.chunkCols, c.chunkRows = max(cols, 1), max(rows, 1)
+	c.chunkConcurrency = concurrency
+	if concurrency < 1 {
+		c.chunkConcurrency = DefaultObservationChunkConcurrency
+	}
+}
+
+// observationChunks splits b into a cols by rows grid, west to east within
+// each row, south to north.
+func observationChunks(b weather.BBox, cols, rows int) []weather.BBox {
+	lonStep := (b.MaxLon - b.MinLon) / float64(cols)
+	latStep := (b.MaxLat - b.MinLat) / float64(rows)
+	chunks := make([]weather.BBox, 0, cols*rows)
+	for row := range rows {
+		for col := range cols {
+			chunk := weather.BBox{
+				MinLon: b.MinLon + float64(col)*lonStep,
+				MinLat: b.MinLat + float64(row)*latStep,
+				MaxLon: b.MinLon + float64(col+1)*lonStep,
+				MaxLat: b.MinLat + float64(row+1)*latStep,
+			}
+			// Close the grid on the exact bounds rather than a sum of
+			// rounded steps.
+			if col == cols-1 {
+				chunk.MaxLon = b.MaxLon
+			}
+			if row == rows-1 {
+				chunk.MaxLat = b.MaxLat
+			}
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks
+}
+
+// fetchObservationChunks fetches b in chunks and merges them. When some
+// chunks fail it returns the others' observations with the failures
+// joined; only when all fail is the result nil.
+func (c *Client) fetchObservationChunks(ctx context.Context, b weather.BBox) (*ObservationResult, error) {
+	chunks := observationChunks(b, c.chunkCols, c.chunkRows)
+	results := make([]*ObservationResult, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, c.chunkConcurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			result, err := c.fetchLatestObservations(ctx, ObservationFilter{BBox: &chunk})
+			if err != nil {
+				errs[i] = fmt.Errorf("chunk %s: %w", bboxParam(chunk), err)
+				return
+			}
+			results[i] = result
+		})
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	if !slices.ContainsFunc(results, func(r *ObservationResult) bool { return r != nil }) {
+		return nil, err
+	}
+	return mergeObservationResults(results), err
+}
+
+// mergeObservationResults merges results, skipping nil ones. A station on
+// a chunk boundary is fetched by both chunks; its station and observations
+// are kept once.
+func mergeObservationResults(results []*ObservationResult) *ObservationResult {
+	type key struct {
+		fmisid int
+		t      time.Time
+	}
+	merged := &ObservationResult{}
+	stations := map[int]bool{}
+	observations := map[key]bool{}
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		for _, s := range r.Stations {
+			if !stations[s.FMISID] {
+				stations[s.FMISID] = true
+				merged.Stations = append(merged.Stations, s)
+			}
+		}
+		for _, o := range r.Observations {
+			k := key{o.FMISID, o.ObservedAt.UTC()}
+			if !observations[k] {
+				observations[k] = true
+				merged.Observations = append(merged.Observations, o)
+			}
+		}
+	}
+	slices.SortFunc(merged.Stations, func(a, b weather.Station) int {
+		return a.FMISID - b.FMISID
+	})
+	slices.SortStableFunc(merged.Observations, func(a, b weather.Observation) int {
+		return a.ObservedAt.Compare(b.ObservedAt)
+	})
+	return merged
+}
@@ -0,0 +1,108 @@
+package fmi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestObservationChunks_CoverBBox(t *testing.T) {
+	chunks := observationChunks(FinlandBBox, 3, 2)
+	if len(chunks) != 6 {
+		t.Fatalf("expected 6 chunks, got %d", len(chunks))
+	}
+	if first, last := chunks[0], chunks[5]; first.MinLon != 19 || first.MinLat != 59 || last.MaxLon != 32 || last.MaxLat != 71 {
+		t.Fatalf("expected the chunks to span the bbox, got %+v..%+v", first, last)
+	}
+	for i, c := range chunks {
+		if col := i % 3; col > 0 && c.MinLon != chunks[i-1].MaxLon {
+			t.Errorf("chunk %d leaves a gap after its western neighbour: %+v", i, c)
+		}
+		if i >= 3 && c.MinLat != chunks[i-3].MaxLat {
+			t.Errorf("chunk %d leaves a gap above its southern neighbour: %+v", i, c)
+		}
+	}
+}
+
+func TestClient_FetchObservationsInChunks(t *testing.T) {
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var bboxes []string
+	var inFlight, maxInFlight atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		bbox := r.URL.Query().Get("bbox")
+		mu.Lock()
+		bboxes = append(bboxes, bbox)
+		mu.Unlock()
+		// The north-east chunk times out; every other chunk sees the
+		// same station, as if it sat on their shared corner.
+		if bbox == "25.5,65,32,71" {
+			http.Error(w, "timeout", http.StatusGatewayTimeout)
+			return
+		}
+		w.Write(observations)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetRetry(1, time.Millisecond)
+	c.SetObservationChunks(2, 2, 2)
+	result, err := c.FetchObservations(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "chunk 25.5,65,32,71") {
+		t.Fatalf("expected the failed chunk to be reported, got %v", err)
+	}
+	want, err := ParseObservations(observations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil || len(result.Stations) != 1 || len(result.Observations) != len(want.Observations) {
+		t.Fatalf("expected the other chunks' observations deduplicated, got %+v", result)
+	}
+	if len(bboxes) != 4 {
+		t.Fatalf("expected 4 chunk requests, got %v", bboxes)
+	}
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("expected at most 2 chunks in flight, got %d", got)
+	}
+}
+
+func TestMergeObservationResults(t *testing.T) {
+	t0 := time.Date(2026, 2, 16, 6, 0, 0, 0, time.UTC)
+	temp := 1.5
+	a := &ObservationResult{
+		Stations:     []weather.Station{{FMISID: 2}, {FMISID: 1}},
+		Observations: []weather.Observation{{FMISID: 1, ObservedAt: t0.Add(10 * time.Minute), Temperature: &temp}, {FMISID: 2, ObservedAt: t0, Temperature: &temp}},
+	}
+	b := &ObservationResult{
+		Stations:     []weather.Station{{FMISID: 2}},
+		Observations: []weather.Observation{{FMISID: 2, ObservedAt: t0.In(time.FixedZone("EET", 2*3600)), Temperature: &temp}},
+	}
+	merged := mergeObservationResults([]*ObservationResult{a, nil, b})
+	if len(merged.Stations) != 2 || merged.Stations[0].FMISID != 1 {
+		t.Fatalf("expected 2 stations by FMISID, got %+v", merged.Stations)
+	}
+	if len(merged.Observations) != 2 || !merged.Observations[0].ObservedAt.Equal(t0) {
+		t.Fatalf("expected 2 observations by time, got %+v", merged.Observations)
+	}
+}
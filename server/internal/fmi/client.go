@@ -1,180 +1,786 @@
 package fmi
 
 import (
+	"compress/gzip"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log/slog"
+	"maps"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"wby/internal/logging"
+	"wby/internal/version"
 	"wby/internal/weather"
 )
 
 type Client struct {
 	baseURL       string
 	apiKey        string
+	apiKeyMode    APIKeyMode
+	userAgent     string
 	timeseriesURL string
+	warningsURL   string
+	radarURL      string
 	httpClient    *http.Client
+
+	// interactiveTimeout and bulkTimeout bound each call; see SetTimeouts.
+	interactiveTimeout time.Duration
+	bulkTimeout        time.Duration
+
+	// metrics receives every call's CallStats; see SetMetricsSink.
+	metrics Metrics
+
+	// maxResponseSize and maxUVResponseSize cap a response body after
+	// decompression.
+	maxResponseSize   int64
+	maxUVResponseSize int64
+
+	retryAttempts  int
+	retryBaseDelay time.Duration
+	breaker        *breaker
+
+	observationFormat   ObservationFormat
+	observationFilter   ObservationFilter
+	chunkCols           int
+	chunkRows           int
+	chunkConcurrency    int
+	forecastModel       ForecastModel
+	forecastParameters  []string
+	forecastMinCoverage float64
+
+	// stations are those seen in observation responses, by FMISID, for
+	// attributing simple format observations, which have no station IDs.
+	stationsMu sync.Mutex
+	stations   map[int]weather.Station
+
+	// now is the clock hourly forecasts are windowed and filtered by.
+	now func() time.Time
+}
+
+// ForecastModel selects the FMI model point forecasts come from.
+//
+// A deployment serves a single model: stored forecasts are keyed by grid
+// point only, so switching models serves the previous model's rows until
+// they go stale and are refetched.
+type ForecastModel string
+
+const (
+	// ModelEdited is the meteorologist-edited Scandinavia forecast, ten
+	// days ahead.
+	ModelEdited ForecastModel = "edited"
+	// ModelHarmonie is the MEPS/Harmonie surface model, updated more often
+	// but only about two and a half days ahead.
+	ModelHarmonie ForecastModel = "harmonie"
+)
+
+// forecastStoredQueries maps each ForecastModel to its stored query.
+var forecastStoredQueries = map[ForecastModel]string{
+	ModelEdited:   "fmi::forecast::edited::weather::scandinavia::point::timevaluepair",
+	ModelHarmonie: "fmi::forecast::harmonie::surface::point::timevaluepair",
+}
+
+// ObservationFormat selects the stored query variant observations are
+// fetched in.
+type ObservationFormat string
+
+const (
+	// FormatTimeValuePair repeats the station metadata for every
+	// parameter, so responses are large but self-describing.
+	FormatTimeValuePair ObservationFormat = "timevaluepair"
+	// FormatMultiPointCoverage lists each station once and the values as a
+	// table in the order of observationParameters.
+	FormatMultiPointCoverage ObservationFormat = "multipointcoverage"
+)
+
+// APIKeyMode selects how the API key is sent to the timeseries API, the
+// only FMI endpoint family that takes one.
+type APIKeyMode string
+
+const (
+	// APIKeyPath sends the key as a path segment, .../fmi-apikey/<key>/...,
+	// as FMI documents it.
+	APIKeyPath APIKeyMode = "path"
+	// APIKeyHeader sends the key in the fmi-apikey header, which keeps it
+	// out of URLs and so out of proxy access logs.
+	APIKeyHeader APIKeyMode = "header"
+)
+
+// apiKeyHeader is the header APIKeyHeader sends the key in.
+const apiKeyHeader = "fmi-apikey"
+
+// DefaultUserAgent identifies this build to FMI, e.g. "wby/v1.4.0".
+func DefaultUserAgent() string {
+	return "wby/" + version.Get().Version
+}
+
+// SetForecastParameters limits FetchForecast and FetchHourlyForecast to
+// the named parameters, e.g. "temperature,windspeedms". Empty requests the
+// stored query's default set. Aggregates of parameters left out are nil.
+func (c *Client) SetForecastParameters(params []string) {
+	c.forecastParameters = params
+}
+
+// forecastQuery builds the point forecast request for the given window,
+// with a latlon for each of points.
+func (c *Client) forecastQuery(start, end string, points ...LatLon) url.Values {
+	params := url.Values{
+		"service":        {"WFS"},
+		"version":        {"2.0.0"},
+		"request":        {"getFeature"},
+		"storedquery_id": {forecastStoredQueries[c.forecastModel]},
+		"timestep":       {"60"},
+		"starttime":      {start},
+		"endtime":        {end},
+	}
+	for _, p := range points {
+		params.Add("latlon", fmt.Sprintf("%f,%f", p.Lat, p.Lon))
+	}
+	if len(c.forecastParameters) > 0 {
+		params.Set("parameters", strings.Join(c.forecastParameters, ","))
+	}
+	return params
+}
+
+// observationParameters are requested explicitly in multipointcoverage
+// format, whose value columns are only identified by this order.
+var observationParameters = []string{
+	"t2m", "ws_10min", "wg_10min", "wd_10min", "rh", "td", "r_1h",
+	"ri_10min", "snow_aws", "p_sea", "vis", "n_man", "wawa",
 }
 
-const forecastDays = 11
 const hourlyForecastHours = 12
 
+const (
+	// DefaultMaxResponseSize caps a WFS or timeseries response after
+	// decompression. WFS compresses about tenfold, so a few MB of gzip
+	// could otherwise expand without bound.
+	DefaultMaxResponseSize = 64 << 20
+	// DefaultMaxUVResponseSize caps a UV forecast, a few KB of JSON.
+	DefaultMaxUVResponseSize = 1 << 20
+)
+
+// maxErrorBodySize caps the part of a non-200 response read for its error,
+// enough for an ExceptionReport; a StatusError quotes maxErrorQuoteSize of
+// it.
+const (
+	maxErrorBodySize  = 64 << 10
+	maxErrorQuoteSize = 512
+)
+
+const (
+	// DefaultRetryAttempts is how many times a stored query is attempted
+	// before its error is returned.
+	DefaultRetryAttempts = 3
+	// DefaultRetryBaseDelay is the wait before the first retry; each
+	// further retry doubles it.
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+)
+
 func NewClient(baseURL, apiKey, timeseriesURL string) *Client {
 	return &Client{
 		baseURL:       baseURL,
 		apiKey:        apiKey,
+		apiKeyMode:    APIKeyPath,
+		userAgent:     DefaultUserAgent(),
 		timeseriesURL: timeseriesURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		// Calls are bounded by interactiveTimeout or bulkTimeout rather
+		// than one timeout for every request.
+		httpClient:          &http.Client{},
+		interactiveTimeout:  DefaultInteractiveTimeout,
+		bulkTimeout:         DefaultBulkTimeout,
+		maxResponseSize:     DefaultMaxResponseSize,
+		maxUVResponseSize:   DefaultMaxUVResponseSize,
+		retryAttempts:       DefaultRetryAttempts,
+		retryBaseDelay:      DefaultRetryBaseDelay,
+		breaker:             newBreaker(DefaultCircuitFailures, DefaultCircuitCooldown),
+		observationFormat:   FormatTimeValuePair,
+		observationFilter:   DefaultObservationFilter(),
+		chunkCols:           1,
+		chunkRows:           1,
+		chunkConcurrency:    DefaultObservationChunkConcurrency,
+		forecastModel:       ModelEdited,
+		forecastMinCoverage: DefaultForecastMinCoverage,
+		now:                 time.Now,
+	}
+}
+
+// SetForecastModel selects the model FetchForecast and FetchHourlyForecast
+// query; empty means ModelEdited. Unknown models are rejected.
+func (c *Client) SetForecastModel(m ForecastModel) error {
+	if m == "" {
+		m = ModelEdited
+	}
+	if _, ok := forecastStoredQueries[m]; !ok {
+		return fmt.Errorf("unknown forecast model %q", m)
+	}
+	c.forecastModel = m
+	return nil
+}
+
+// SetForecastMinCoverage sets the share of a day's hours, from 0 to 1, that
+// must carry a value for a daily forecast aggregate to be computed. Days
+// below it get a nil aggregate instead of one skewed by the few hours left.
+func (c *Client) SetForecastMinCoverage(ratio float64) error {
+	if ratio < 0 || ratio > 1 {
+		return fmt.Errorf("forecast minimum coverage %g outside 0-1", ratio)
+	}
+	c.forecastMinCoverage = ratio
+	return nil
+}
+
+// SetObservationFormat selects the format FetchObservations and
+// FetchObservationsInBBox request; empty means FormatTimeValuePair.
+// Unknown formats are rejected.
+func (c *Client) SetObservationFormat(f ObservationFormat) error {
+	switch f {
+	case "":
+		c.observationFormat = FormatTimeValuePair
+		return nil
+	case FormatTimeValuePair, FormatMultiPointCoverage:
+		c.observationFormat = f
+		return nil
+	}
+	return fmt.Errorf("unknown observation format %q", f)
+}
+
+// SetAPIKeyMode selects how the API key is sent; empty means APIKeyPath.
+// Unknown modes are rejected.
+func (c *Client) SetAPIKeyMode(m APIKeyMode) error {
+	switch m {
+	case "":
+		c.apiKeyMode = APIKeyPath
+		return nil
+	case APIKeyPath, APIKeyHeader:
+		c.apiKeyMode = m
+		return nil
+	}
+	return fmt.Errorf("unknown API key mode %q", m)
+}
+
+// SetUserAgent sets the User-Agent sent with every FMI request; empty
+// keeps DefaultUserAgent.
+func (c *Client) SetUserAgent(ua string) {
+	if ua != "" {
+		c.userAgent = ua
+	}
+}
+
+// SetMaxResponseSize caps response bodies after decompression, WFS and
+// timeseries observations at wfs bytes and UV forecasts at uv bytes;
+// reading past a cap fails with ErrResponseTooLarge. Sizes below 1 keep
+// the defaults.
+func (c *Client) SetMaxResponseSize(wfs, uv int64) {
+	if wfs > 0 {
+		c.maxResponseSize = wfs
+	}
+	if uv > 0 {
+		c.maxUVResponseSize = uv
+	}
+}
+
+// SetObservationFilter selects the stations FetchObservations and
+// FetchObservationsRange fetch; the default is every station in Finland.
+// A filter without exactly one of its fields set is rejected.
+func (c *Client) SetObservationFilter(f ObservationFilter) error {
+	if err := f.validate(); err != nil {
+		return err
 	}
+	c.observationFilter = f
+	return nil
+}
+
+// SetRetry sets how many times a stored query is attempted and the backoff
+// before the first retry. Attempts below 1 disable retries.
+func (c *Client) SetRetry(attempts int, baseDelay time.Duration) {
+	c.retryAttempts = max(attempts, 1)
+	c.retryBaseDelay = baseDelay
 }
 
+// SetCircuitBreaker sets how many FMI calls in a row must fail before
+// further calls fail fast with ErrCircuitOpen, and for how long. Failures
+// below 1 disable the breaker.
+func (c *Client) SetCircuitBreaker(failures int, cooldown time.Duration) {
+	c.breaker.threshold = failures
+	c.breaker.cooldown = cooldown
+}
+
+// MaxObservationRange is the longest time range FMI serves in one
+// observation query.
+const MaxObservationRange = 168 * time.Hour
+
+// observationTimestep is the spacing of fetched observations.
+const observationTimestep = 10 * time.Minute
+
+// FetchObservations fetches the latest observations for the stations
+// selected by the observation filter (see SetObservationFilter). FMI
+// returns empty results without one.
+//
+// A bbox is fetched in chunks when SetObservationChunks asks for more than
+// one; if only some fail, the rest are returned along with an error.
 func (c *Client) FetchObservations(ctx context.Context) (*ObservationResult, error) {
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
+	if f := c.observationFilter; f.BBox != nil && c.chunkCols*c.chunkRows > 1 {
+		return c.fetchObservationChunks(ctx, *f.BBox)
+	}
+	return c.fetchLatestObservations(ctx, c.observationFilter)
+}
+
+// FetchObservationsInBBox fetches the latest observations for stations
+// inside the given bounding box, regardless of the observation filter.
+func (c *Client) FetchObservationsInBBox(ctx context.Context, minLon, minLat, maxLon, maxLat float64) (*ObservationResult, error) {
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
+	return c.fetchLatestObservations(ctx, ObservationFilter{BBox: &weather.BBox{MinLon: minLon, MinLat: minLat, MaxLon: maxLon, MaxLat: maxLat}})
+}
+
+// fetchLatestObservations fetches the latest observations for f's
+// stations. With an API key they come from the timeseries API, falling
+// back to WFS when that fails.
+func (c *Client) fetchLatestObservations(ctx context.Context, f ObservationFilter) (*ObservationResult, error) {
+	if c.apiKey != "" {
+		result, err := c.fetchObservationsTimeseries(ctx, f)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		logging.FromContext(ctx).Warn("timeseries observations failed, falling back to WFS", "err", err)
+	}
+	return c.fetchObservations(ctx, observationParams(f))
+}
+
+// FetchObservationsRange fetches the observations of the stations selected
+// by the observation filter from start to end, both inclusive, e.g. to fill a gap left while the fetcher
+// was down. Ranges longer than MaxObservationRange are fetched in
+// consecutive chunks and merged, each with the bulk timeout.
+func (c *Client) FetchObservationsRange(ctx context.Context, start, end time.Time) (*ObservationResult, error) {
+	start, end = start.UTC().Truncate(observationTimestep), end.UTC()
+	if end.Before(start) {
+		return nil, fmt.Errorf("fetch observations: range ends at %s before it starts at %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+
+	merged := &ObservationResult{}
+	seen := map[int]bool{}
+	for chunkStart := start; !chunkStart.After(end); chunkStart = chunkStart.Add(MaxObservationRange) {
+		// Chunks stop a step short of the next one so no time is fetched twice.
+		chunkEnd := chunkStart.Add(MaxObservationRange - observationTimestep)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+		params := observationParams(c.observationFilter)
+		params.Set("starttime", chunkStart.Format(time.RFC3339))
+		params.Set("endtime", chunkEnd.Format(time.RFC3339))
+		chunkCtx, cancel := c.bulkContext(ctx)
+		result, err := c.fetchObservations(chunkCtx, params)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("%s to %s: %w", chunkStart.Format(time.RFC3339), chunkEnd.Format(time.RFC3339), err)
+		}
+		for _, st := range result.Stations {
+			if !seen[st.FMISID] {
+				seen[st.FMISID] = true
+				merged.Stations = append(merged.Stations, st)
+			}
+		}
+		merged.Observations = append(merged.Observations, result.Observations...)
+	}
+	return merged, nil
+}
+
+func observationParams(f ObservationFilter) url.Values {
 	params := url.Values{
-		"service":        {"WFS"},
-		"version":        {"2.0.0"},
-		"request":        {"getFeature"},
-		"storedquery_id": {"fmi::observations::weather::timevaluepair"},
-		"timestep":       {"10"},
-		"maxlocations":   {"200"},
-		// FMI currently returns empty results without an explicit area filter.
-		// This bbox covers Finland where the app data is sourced.
-		"bbox": {"19,59,32,71"},
+		"service":      {"WFS"},
+		"version":      {"2.0.0"},
+		"request":      {"getFeature"},
+		"timestep":     {strconv.Itoa(int(observationTimestep / time.Minute))},
+		"maxlocations": {"200"},
+	}
+	f.wfsParams(params)
+	return params
+}
+
+func (c *Client) fetchObservations(ctx context.Context, params url.Values) (*ObservationResult, error) {
+	params.Set("storedquery_id", "fmi::observations::weather::"+string(c.observationFormat))
+	parse := ParseObservationsReader
+	if c.observationFormat == FormatMultiPointCoverage {
+		params.Set("parameters", strings.Join(observationParameters, ","))
+		parse = func(r io.Reader) (*ObservationResult, error) {
+			return ParseObservationsMultipointReader(r, observationParameters)
+		}
 	}
 
-	data, err := c.fetch(ctx, params)
+	var result *ObservationResult
+	var size int64
+	err := c.fetchReader(ctx, params, func(body io.Reader) (err error) {
+		counted := &countingReader{r: body}
+		result, err = parse(counted)
+		size = counted.n
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("fetch observations: %w", err)
 	}
-	return ParseObservations(data)
+	if len(result.Observations) > 0 {
+		c.rememberStations(result.Stations)
+		return result, nil
+	}
+	// A sizeable response that parsed to nothing suggests the format
+	// changed under us; the simple format is the least likely to.
+	if size < minSimpleFallbackSize {
+		return result, nil
+	}
+	logging.FromContext(ctx).Warn("FMI observations parsed empty, retrying in the simple format", "format", c.observationFormat, "bytes", size)
+	return c.fetchObservationsSimple(ctx, params)
+}
+
+// fetchObservationsSimple is fetchObservations in the simple format. Only
+// stations seen in an earlier response can be attributed, so it returns
+// nothing until one has been parsed.
+func (c *Client) fetchObservationsSimple(ctx context.Context, params url.Values) (*ObservationResult, error) {
+	params = maps.Clone(params)
+	params.Set("storedquery_id", simpleObservationsQuery)
+	params.Set("parameters", strings.Join(observationParameters, ","))
+	stations := c.knownStations()
+
+	var result *ObservationResult
+	err := c.fetchReader(ctx, params, func(body io.Reader) (err error) {
+		result, err = ParseObservationsSimpleReader(body, stations)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch simple observations: %w", err)
+	}
+	return result, nil
+}
+
+// rememberStations records stations for fetchObservationsSimple.
+func (c *Client) rememberStations(stations []weather.Station) {
+	c.stationsMu.Lock()
+	defer c.stationsMu.Unlock()
+	if c.stations == nil {
+		c.stations = make(map[int]weather.Station)
+	}
+	for _, s := range stations {
+		c.stations[s.FMISID] = s
+	}
+}
+
+func (c *Client) knownStations() []weather.Station {
+	c.stationsMu.Lock()
+	defer c.stationsMu.Unlock()
+	return slices.Collect(maps.Values(c.stations))
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
 }
 
-func (c *Client) FetchForecast(ctx context.Context, lat, lon float64) (weather.ForecastData, error) {
-	start, end := forecastTimeWindowUTC(forecastDays)
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
 
+// FetchAirQuality fetches the last few hours of hourly PM2.5, PM10, ozone
+// and NO2 means from the urban air quality stations in Finland.
+func (c *Client) FetchAirQuality(ctx context.Context) (*AirQualityResult, error) {
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
 	params := url.Values{
 		"service":        {"WFS"},
 		"version":        {"2.0.0"},
 		"request":        {"getFeature"},
-		"storedquery_id": {"fmi::forecast::edited::weather::scandinavia::point::timevaluepair"},
-		"latlon":         {fmt.Sprintf("%f,%f", lat, lon)},
+		"storedquery_id": {"urban::observations::airquality::hourly::timevaluepair"},
+		"parameters":     {"PM25_PT1H_avg,PM10_PT1H_avg,O3_PT1H_avg,NO2_PT1H_avg"},
+		"bbox":           {"19,59,32,71"},
+		"starttime":      {time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Hour).Format(time.RFC3339)},
 		"timestep":       {"60"},
-		"starttime":      {start},
-		"endtime":        {end},
 	}
 
-	data, err := c.fetch(ctx, params)
+	var result *AirQualityResult
+	err := c.fetchDecode(ctx, params, func(data []byte) (err error) {
+		result, err = ParseAirQuality(data)
+		return err
+	})
 	if err != nil {
-		return weather.ForecastData{}, fmt.Errorf("fetch forecast: %w", err)
+		return nil, fmt.Errorf("fetch air quality: %w", err)
 	}
-	return ParseForecast(data, lat, lon)
+	return result, nil
 }
 
-func (c *Client) FetchHourlyForecast(ctx context.Context, lat, lon float64, limit int) ([]weather.HourlyForecast, error) {
-	hours := limit
-	if hours <= 0 {
-		hours = hourlyForecastHours
+// marineQueries are the stored queries for wave buoys and mareographs and
+// the parameters requested from each.
+var marineQueries = []struct {
+	id         string
+	parameters string
+}{
+	{"fmi::observations::wave::timevaluepair", "WaveHs,ModalWDi,WTP,TWATER"},
+	{"fmi::observations::mareograph::timevaluepair", "WATLEV"},
+}
+
+// FetchMarine fetches the last few hours of wave buoy and mareograph
+// observations. The two station networks are fetched separately and
+// merged.
+func (c *Client) FetchMarine(ctx context.Context) (*MarineResult, error) {
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
+	start := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Hour).Format(time.RFC3339)
+	merged := &MarineResult{}
+	for _, q := range marineQueries {
+		params := url.Values{
+			"service":        {"WFS"},
+			"version":        {"2.0.0"},
+			"request":        {"getFeature"},
+			"storedquery_id": {q.id},
+			"parameters":     {q.parameters},
+			"bbox":           {"19,59,32,71"},
+			"starttime":      {start},
+		}
+
+		var result *MarineResult
+		err := c.fetchDecode(ctx, params, func(data []byte) (err error) {
+			result, err = ParseMarine(data)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch marine observations (%s): %w", q.id, err)
+		}
+		merged.Stations = append(merged.Stations, result.Stations...)
+		merged.Observations = append(merged.Observations, result.Observations...)
 	}
-	start, end := forecastHoursWindowUTC(hours)
+	return merged, nil
+}
 
+// FetchRoadObservations fetches the last hour of road surface temperature,
+// air temperature and road condition from the road weather stations.
+func (c *Client) FetchRoadObservations(ctx context.Context) (*RoadResult, error) {
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
 	params := url.Values{
 		"service":        {"WFS"},
 		"version":        {"2.0.0"},
 		"request":        {"getFeature"},
-		"storedquery_id": {"fmi::forecast::edited::weather::scandinavia::point::timevaluepair"},
-		"latlon":         {fmt.Sprintf("%f,%f", lat, lon)},
-		"timestep":       {"60"},
-		"starttime":      {start},
-		"endtime":        {end},
+		"storedquery_id": {"livi::observations::road::default::timevaluepair"},
+		"parameters":     {"TROAD1,TA,RSCOND1"},
+		"bbox":           {"19,59,32,71"},
+		"starttime":      {time.Now().UTC().Add(-time.Hour).Truncate(time.Minute).Format(time.RFC3339)},
 	}
 
-	data, err := c.fetch(ctx, params)
+	var result *RoadResult
+	err := c.fetchDecode(ctx, params, func(data []byte) (err error) {
+		result, err = ParseRoadObservations(data)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("fetch hourly forecast: %w", err)
+		return nil, fmt.Errorf("fetch road observations: %w", err)
 	}
-	return ParseHourlyForecast(data, hours)
+	return result, nil
 }
 
-func (c *Client) FetchUVForecast(ctx context.Context, lat, lon float64) ([]weather.UVDataPoint, error) {
-	if c.apiKey == "" {
-		return nil, nil
+// FetchForecast fetches hourly data for today and the following days-1
+// days and aggregates it into daily forecasts.
+func (c *Client) FetchForecast(ctx context.Context, lat, lon float64, days int) (weather.ForecastData, error) {
+	ctx, cancel := c.interactiveContext(ctx)
+	defer cancel()
+	start, end := forecastTimeWindowUTC(c.now(), days, weather.PlaceLocation(weather.DefaultPlaceTimezone))
+
+	var result weather.ForecastData
+	var summary ForecastParseSummary
+	err := c.fetchDecode(ctx, c.forecastQuery(start, end, LatLon{Lat: lat, Lon: lon}), func(data []byte) (err error) {
+		result, summary, err = ParseForecast(data, lat, lon, c.forecastMinCoverage)
+		return err
+	})
+	if err != nil {
+		return weather.ForecastData{}, fmt.Errorf("fetch forecast: %w", err)
 	}
+	logForecastSummary(ctx, summary, lat, lon)
+	setForecastSource(result.Forecasts, string(c.forecastModel))
+	return result, nil
+}
 
-	startTime := time.Now().UTC().Truncate(time.Hour).Format(time.RFC3339)
-	reqURL := fmt.Sprintf(
-		"%s/fmi-apikey/%s/timeseries?param=epochtime,uvCumulated&producer=uv&format=json&latlon=%f,%f&timesteps=30&starttime=%s",
-		c.timeseriesURL, c.apiKey, lat, lon, startTime,
-	)
+// MaxForecastBatch is how many points FetchForecastBatch asks FMI for in
+// one request; larger batches take several.
+const MaxForecastBatch = 20
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("build UV request: %w", err)
+// FetchForecastBatch is FetchForecast for several points, asking FMI for
+// up to MaxForecastBatch of them per request rather than making a request
+// for each. The results are in the order of points; a point FMI returned
+// no forecast for gets an empty ForecastData. As a batch is slower than
+// one point, it has the bulk timeout.
+func (c *Client) FetchForecastBatch(ctx context.Context, points []LatLon, days int) ([]weather.ForecastData, error) {
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
+	start, end := forecastTimeWindowUTC(c.now(), days, weather.PlaceLocation(weather.DefaultPlaceTimezone))
+
+	results := make([]weather.ForecastData, 0, len(points))
+	for batch := range slices.Chunk(points, MaxForecastBatch) {
+		var forecasts []weather.ForecastData
+		var summaries []ForecastParseSummary
+		err := c.fetchDecode(ctx, c.forecastQuery(start, end, batch...), func(data []byte) (err error) {
+			forecasts, summaries, err = ParseForecastBatch(data, batch, c.forecastMinCoverage)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch forecast batch: %w", err)
+		}
+		for i, p := range batch {
+			logForecastSummary(ctx, summaries[i], p.Lat, p.Lon)
+			setForecastSource(forecasts[i].Forecasts, string(c.forecastModel))
+		}
+		results = append(results, forecasts...)
 	}
+	return results, nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("fetch UV forecast: %w", err)
+// logForecastSummary reports the forecast values ParseForecast could not
+// use. NaN hours are routine, e.g. parameters the model does not compute,
+// so only unparsable values, which suggest a format change, are warnings.
+func logForecastSummary(ctx context.Context, summary ForecastParseSummary, lat, lon float64) {
+	log := logging.FromContext(ctx)
+	switch {
+	case summary.Unparsable > 0:
+		log.Warn("FMI forecast had unparsable values", "lat", lat, "lon", lon, "summary", summary)
+	case summary.Missing > 0 || summary.Dropped > 0 || len(summary.UnknownParams) > 0:
+		log.Debug("FMI forecast had missing values", "lat", lat, "lon", lon, "summary", summary)
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("UV API returned %d: %s", resp.StatusCode, string(body))
+// ecmwfStoredQuery is the ECMWF point forecast, which reaches about 15
+// days ahead.
+const ecmwfStoredQuery = "ecmwf::forecast::surface::point::timevaluepair"
+
+// FetchForecastECMWF fetches days of ECMWF daily forecasts for the point,
+// for extending the configured model past its horizon. It always requests
+// ECMWF's default parameters, as its names differ from the edited
+// forecast's.
+func (c *Client) FetchForecastECMWF(ctx context.Context, lat, lon float64, days int) (weather.ForecastData, error) {
+	ctx, cancel := c.interactiveContext(ctx)
+	defer cancel()
+	start, end := forecastTimeWindowUTC(c.now(), days, weather.PlaceLocation(weather.DefaultPlaceTimezone))
+	params := url.Values{
+		"service":        {"WFS"},
+		"version":        {"2.0.0"},
+		"request":        {"getFeature"},
+		"storedquery_id": {ecmwfStoredQuery},
+		"latlon":         {fmt.Sprintf("%f,%f", lat, lon)},
+		"timestep":       {"60"},
+		"starttime":      {start},
+		"endtime":        {end},
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	var result weather.ForecastData
+	var summary ForecastParseSummary
+	err := c.fetchDecode(ctx, params, func(data []byte) (err error) {
+		result, summary, err = ParseForecast(data, lat, lon, c.forecastMinCoverage)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("read UV response: %w", err)
+		return weather.ForecastData{}, fmt.Errorf("fetch ECMWF forecast: %w", err)
+	}
+	logForecastSummary(ctx, summary, lat, lon)
+	estimateSymbols(result.Forecasts)
+	setForecastSource(result.Forecasts, weather.ForecastSourceECMWF)
+	return result, nil
+}
+
+func setForecastSource(forecasts []weather.DailyForecast, source string) {
+	for i := range forecasts {
+		forecasts[i].Source = source
+	}
+}
+
+func (c *Client) FetchHourlyForecast(ctx context.Context, lat, lon float64, limit int) ([]weather.HourlyForecast, error) {
+	ctx, cancel := c.interactiveContext(ctx)
+	defer cancel()
+	hours := limit
+	if hours <= 0 {
+		hours = hourlyForecastHours
 	}
+	now := c.now()
+	start, end := forecastHoursWindowUTC(now, hours)
 
-	var raw []struct {
-		EpochTime   int64    `json:"epochtime"`
-		UVCumulated *float64 `json:"uvCumulated"`
+	var hourly []weather.HourlyForecast
+	err := c.fetchDecode(ctx, c.forecastQuery(start, end, LatLon{Lat: lat, Lon: lon}), func(data []byte) (err error) {
+		hourly, err = ParseHourlyForecast(data, hours, now)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch hourly forecast: %w", err)
 	}
-	if err := json.Unmarshal(body, &raw); err != nil {
-		slog.Warn("failed to parse UV response", "err", err, "body", string(body))
+	return hourly, nil
+}
+
+// FetchUVForecast fetches the cumulated UV forecast for the next 30 hours
+// from the timeseries API, or nothing without an API key. It is retried
+// like a stored query; an error object in place of data is an *APIError.
+func (c *Client) FetchUVForecast(ctx context.Context, lat, lon float64) ([]weather.UVDataPoint, error) {
+	if c.apiKey == "" {
 		return nil, nil
 	}
+	ctx, cancel := c.interactiveContext(ctx)
+	defer cancel()
+	params := url.Values{
+		"param":     {"epochtime,uvCumulated"},
+		"producer":  {"uv"},
+		"format":    {"json"},
+		"latlon":    {fmt.Sprintf("%f,%f", lat, lon)},
+		"timesteps": {"30"},
+		"starttime": {c.now().UTC().Truncate(time.Hour).Format(time.RFC3339)},
+	}
 
 	var points []weather.UVDataPoint
-	for _, r := range raw {
-		if r.UVCumulated == nil {
-			continue
-		}
-		points = append(points, weather.UVDataPoint{
-			Time:        time.Unix(r.EpochTime, 0).UTC(),
-			UVCumulated: *r.UVCumulated,
-		})
+	err := c.fetchURL(ctx, "timeseries::uv", c.timeseriesRequestURL(params), c.maxUVResponseSize, func(body io.Reader) (err error) {
+		points, err = parseUVForecast(body)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch UV forecast: %w", err)
 	}
 	return points, nil
 }
 
-func forecastTimeWindowUTC(days int) (start, end string) {
+// forecastTimeWindowUTC covers the rest of today and the next days-1 days
+// in loc, ending with the last hour of the final local day so that day is
+// complete. Local midnights make DST days 23 or 25 hours long.
+func forecastTimeWindowUTC(now time.Time, days int, loc *time.Location) (start, end string) {
 	if days < 1 {
 		days = 1
 	}
-	startTime := time.Now().UTC().Truncate(time.Hour)
-	// Inclusive window: today + next (days-1) days.
-	endTime := startTime.AddDate(0, 0, days-1)
+	startTime := now.UTC().Truncate(time.Hour)
+	local := now.In(loc)
+	nextMidnight := time.Date(local.Year(), local.Month(), local.Day()+days, 0, 0, 0, 0, loc)
+	// Inclusive window: the last timestep is 23:00 local on the final day.
+	endTime := nextMidnight.Add(-time.Hour).UTC()
 	return startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)
 }
 
-func forecastHoursWindowUTC(hours int) (start, end string) {
+// forecastHoursWindowUTC covers the current hour and the next hours,
+// one more than asked for in case FMI has no value for the current hour.
+func forecastHoursWindowUTC(now time.Time, hours int) (start, end string) {
 	if hours < 1 {
 		hours = 1
 	}
-	startTime := time.Now().UTC().Truncate(time.Hour)
-	// Inclusive window: current hour + next (hours-1) hours.
-	endTime := startTime.Add(time.Duration(hours-1) * time.Hour)
+	startTime := now.UTC().Truncate(time.Hour)
+	// Inclusive window: current hour + next hours hours.
+	endTime := startTime.Add(time.Duration(hours) * time.Hour)
 	return startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)
 }
 
 func (c *Client) FetchClimateNormals(ctx context.Context, fmisids string) ([]byte, error) {
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
 	params := url.Values{
 		"service":        {"WFS"},
 		"version":        {"2.0.0"},
@@ -186,24 +792,280 @@ func (c *Client) FetchClimateNormals(ctx context.Context, fmisids string) ([]byt
 	return c.fetch(ctx, params)
 }
 
+// fetch runs a stored query, retrying network errors, 429 and 5xx
+// responses with exponential backoff. A retry that would outlive the
+// context's deadline is not attempted. While the circuit breaker is open
+// it fails with ErrCircuitOpen without a request.
 func (c *Client) fetch(ctx context.Context, params url.Values) ([]byte, error) {
-	reqURL := c.baseURL + "?" + params.Encode()
+	var data []byte
+	err := c.fetchReader(ctx, params, func(body io.Reader) (err error) {
+		data, err = io.ReadAll(body)
+		return err
+	})
+	return data, err
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+// fetchDecode is fetch for callers that decode the whole body at once;
+// decode runs within the call, so its time and failure are the call's.
+func (c *Client) fetchDecode(ctx context.Context, params url.Values, decode func([]byte) error) error {
+	return c.fetchReader(ctx, params, func(body io.Reader) error {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		return decode(data)
+	})
+}
+
+// fetchReader is fetch for responses decoded straight from the HTTP body.
+// read may be called once per attempt; an error it returns is retried only
+// when reading the body failed, not when the body could not be decoded.
+func (c *Client) fetchReader(ctx context.Context, params url.Values, read func(io.Reader) error) error {
+	return c.fetchURL(ctx, params.Get("storedquery_id"), c.baseURL+"?"+params.Encode(), c.maxResponseSize, read)
+}
+
+// fetchURL is fetchReader for a request URL built by the caller, reported
+// in metrics and logs as query, whose body may be at most limit bytes.
+func (c *Client) fetchURL(ctx context.Context, query, reqURL string, limit int64, read func(io.Reader) error) (err error) {
+	if err := c.breaker.allow(ctx); err != nil {
+		return err
+	}
+	defer func() { c.breaker.done(ctx, err) }()
+	stats := CallStats{Query: query}
+	defer func(start time.Time) { c.observeFetch(stats, start, err) }(time.Now())
+
+	for attempt := 1; ; attempt++ {
+		stats.Retries = attempt - 1
+		err := c.fetchOnce(ctx, reqURL, limit, read, &stats)
+		if err == nil || attempt >= c.retryAttempts || !retryable(ctx, err) {
+			return err
+		}
+		delay := backoff(c.retryBaseDelay, attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			return err
+		}
+		logging.FromContext(ctx).Debug("retrying FMI request", "query", query, "attempt", attempt, "delay", delay, "err", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// fetchOnce makes one attempt at reqURL, recording its status, body size
+// and parse time in stats.
+func (c *Client) fetchOnce(ctx context.Context, reqURL string, limit int64, read func(io.Reader) error, stats *CallStats) error {
+	stats.Status, stats.Bytes, stats.ParseDuration = 0, 0, 0
+	req, err := c.newRequest(ctx, reqURL)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
+	// Setting Accept-Encoding turns off net/http's transparent
+	// decompression, so gzip responses are decoded here.
+	req.Header.Set("Accept-Encoding", "gzip")
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return asNetworkError(c.redactError(err))
 	}
 	defer resp.Body.Close()
+	stats.Status = resp.StatusCode
 
+	var r io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("decompress response: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("FMI returned %d: %s", resp.StatusCode, string(body))
+		raw, _ := io.ReadAll(io.LimitReader(r, maxErrorBodySize))
+		stats.Bytes = int64(len(raw))
+		// Error pages may echo the request URL.
+		body := []byte(c.redact(string(raw)))
+		if apiErr := parseExceptionReport(body, resp.StatusCode); apiErr != nil {
+			return apiErr
+		}
+		if apiErr := parseTimeseriesError(body, resp.StatusCode); apiErr != nil {
+			return apiErr
+		}
+		return &StatusError{StatusCode: resp.StatusCode, Body: truncate(body, maxErrorQuoteSize)}
+	}
+
+	body := &bodyReader{r: newCappedReader(r, limit)}
+	start := time.Now()
+	err = read(body)
+	stats.Bytes = body.n
+	stats.ParseDuration = max(time.Since(start)-body.wait, 0)
+	if err != nil {
+		if errors.Is(body.err, ErrResponseTooLarge) {
+			return &decodeError{err: body.err}
+		}
+		if body.err != nil {
+			return asNetworkError(body.err)
+		}
+		return &decodeError{err: err}
+	}
+	return nil
+}
+
+// newRequest builds a GET request for reqURL with the client's User-Agent,
+// adding the API key when reqURL is on the timeseries API.
+func (c *Client) newRequest(ctx context.Context, reqURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, c.redactError(err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.apiKey == "" || c.timeseriesURL == "" || !strings.HasPrefix(reqURL, c.timeseriesURL) {
+		return req, nil
+	}
+	switch c.apiKeyMode {
+	case APIKeyHeader:
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	default:
+		base, err := url.Parse(c.timeseriesURL)
+		if err != nil {
+			return nil, fmt.Errorf("parse timeseries URL: %w", err)
+		}
+		base.Path = strings.TrimSuffix(base.Path, "/")
+		req.URL.Path = base.Path + "/fmi-apikey/" + c.apiKey + strings.TrimPrefix(req.URL.Path, base.Path)
+		req.URL.RawPath = ""
+	}
+	return req, nil
+}
+
+// redact replaces the API key in s, so URLs and the errors quoting them
+// can be logged.
+func (c *Client) redact(s string) string {
+	if c.apiKey == "" {
+		return s
 	}
+	return strings.ReplaceAll(s, c.apiKey, "REDACTED")
+}
 
-	return io.ReadAll(resp.Body)
+// redactError redacts the URL of a *url.Error, which is how net/http
+// reports failed requests.
+func (c *Client) redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = c.redact(urlErr.URL)
+	}
+	return err
+}
+
+// ErrResponseTooLarge is returned, wrapped, when a response body is larger
+// than its cap (see SetMaxResponseSize). It is not retried.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// cappedReader fails with ErrResponseTooLarge once more than limit bytes
+// are read, rather than truncating the body quietly.
+type cappedReader struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+}
+
+func newCappedReader(r io.Reader, limit int64) *cappedReader {
+	return &cappedReader{r: io.LimitReader(r, limit+1), limit: limit, remaining: limit}
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if c.remaining < 0 {
+		return n, fmt.Errorf("%w: over %d bytes", ErrResponseTooLarge, c.limit)
+	}
+	return n, err
+}
+
+// bodyReader remembers the first error reading the response body, so a
+// connection reset can be told apart from a malformed document. It also
+// counts the bytes read and the time spent waiting for them, which is not
+// parse time.
+type bodyReader struct {
+	r    io.Reader
+	err  error
+	n    int64
+	wait time.Duration
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := b.r.Read(p)
+	b.wait += time.Since(start)
+	b.n += int64(n)
+	if err != nil && err != io.EOF && b.err == nil {
+		b.err = err
+	}
+	return n, err
+}
+
+// decodeError is a response FMI sent in full that could not be decoded;
+// fetching it again won't help.
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string { return e.err.Error() }
+
+func (e *decodeError) Unwrap() error { return e.err }
+
+func (e *decodeError) Is(target error) bool { return target == ErrParse }
+
+// StatusError is a non-200 response from FMI without an ExceptionReport,
+// such as a proxy's error page.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("FMI returned %d: %s", e.StatusCode, e.Body)
+}
+
+// Is matches the category of the status code; see ErrRateLimited.
+func (e *StatusError) Is(target error) bool {
+	return target != nil && target == statusCategory(e.StatusCode)
+}
+
+// retryable reports whether err is worth another attempt: a network
+// error, 429 or 5xx, and not the caller giving up.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var decodeErr *decodeError
+	if errors.As(err, &decodeErr) {
+		return false
+	}
+	code := 0
+	var status *StatusError
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &status):
+		code = status.StatusCode
+	case errors.As(err, &apiErr):
+		code = apiErr.HTTPStatus
+	default:
+		return true
+	}
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// backoff is the wait before retry number attempt: base doubled for each
+// earlier retry, with the upper half randomized so clients that failed
+// together don't retry in lockstep.
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base << (attempt - 1)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
 }
@@ -0,0 +1,591 @@
+package fmi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"wby/internal/metrics"
+	"wby/internal/weather"
+)
+
+func TestClient_RecordsFetchMetrics(t *testing.T) {
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(observations)
+	}))
+	defer srv.Close()
+
+	reg := metrics.NewRegistry()
+	c := NewClient(srv.URL, "", "")
+	c.SetMetrics(reg)
+	c.SetRetry(DefaultRetryAttempts, time.Millisecond)
+
+	if _, err := c.FetchObservations(context.Background()); err != nil {
+		t.Fatalf("fetch observations: %v", err)
+	}
+	fail = true
+	if _, err := c.FetchObservations(context.Background()); err == nil {
+		t.Fatal("expected error from failing upstream")
+	}
+
+	const query = "fmi::observations::weather::timevaluepair"
+	if got := reg.Value("wby_fmi_fetch_duration_seconds", query, "ok"); got != 1 {
+		t.Errorf("expected 1 successful fetch, got %v", got)
+	}
+	if got := reg.Value("wby_fmi_fetch_duration_seconds", query, "error"); got != 1 {
+		t.Errorf("expected 1 failed fetch, got %v", got)
+	}
+	if got := reg.Value("wby_fmi_fetch_errors_total", query); got != 1 {
+		t.Errorf("expected 1 fetch error, got %v", got)
+	}
+}
+
+func TestClient_RetriesTransientFailures(t *testing.T) {
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		w.Write(observations)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetRetry(3, time.Millisecond)
+	result, err := c.FetchObservations(context.Background())
+	if err != nil {
+		t.Fatalf("fetch observations: %v", err)
+	}
+	if len(result.Observations) == 0 {
+		t.Error("expected observations from the third attempt")
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("expected 3 requests, got %d", got)
+	}
+}
+
+func TestClient_RetryPolicy(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   int
+		attempts int
+		want     int32
+	}{
+		{"bad request is not retried", http.StatusBadRequest, 3, 1},
+		{"not found is not retried", http.StatusNotFound, 3, 1},
+		{"too many requests is retried", http.StatusTooManyRequests, 3, 3},
+		{"server error is retried", http.StatusInternalServerError, 3, 3},
+		{"attempts are configurable", http.StatusServiceUnavailable, 2, 2},
+		{"retries can be disabled", http.StatusServiceUnavailable, 0, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				http.Error(w, "failed", tc.status)
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.URL, "", "")
+			c.SetRetry(tc.attempts, time.Millisecond)
+			_, err := c.FetchObservations(context.Background())
+			var status *StatusError
+			if !errors.As(err, &status) || status.StatusCode != tc.status {
+				t.Fatalf("expected StatusError %d, got %v", tc.status, err)
+			}
+			if got := requests.Load(); got != tc.want {
+				t.Errorf("expected %d requests, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_DoesNotRetryMalformedObservations(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0"><wfs:member>`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetRetry(3, time.Millisecond)
+	if _, err := c.FetchObservations(context.Background()); err == nil {
+		t.Fatal("expected error for a truncated document")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestClient_DecompressesGzipResponses(t *testing.T) {
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed := gzipped(t, observations)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			t.Errorf("expected gzip to be accepted, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed)
+	}))
+	defer srv.Close()
+
+	result, err := NewClient(srv.URL, "", "").FetchObservations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ParseObservations(observations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Observations) == 0 || !reflect.DeepEqual(result, want) {
+		t.Fatalf("expected the decompressed fixture, got %d observations", len(result.Observations))
+	}
+}
+
+func TestClient_RejectsDecompressionBomb(t *testing.T) {
+	bomb := gzipped(t, make([]byte, 8<<20))
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(bomb)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetMaxResponseSize(1<<20, 0)
+	c.SetRetry(3, time.Millisecond)
+	_, err := c.FetchObservations(context.Background())
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected a too large response, got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected an oversized response not to be retried, got %d requests", got)
+	}
+}
+
+func TestClient_CapsResponseSize(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("producer") == "uv" {
+			w.Write([]byte(`[` + strings.Repeat(`{"epochtime":1781517600,"uvCumulated":2.5},`, 100) + `{}]`))
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "secret", srv.URL)
+	c.SetMaxResponseSize(0, 1024)
+	c.SetRetry(1, time.Millisecond)
+	if _, err := c.FetchUVForecast(context.Background(), 60.17, 24.94); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected the UV cap to apply, got %v", err)
+	}
+
+	body = bytes.Repeat([]byte("<html>bad gateway</html>"), 1000)
+	_, err := c.FetchObservationsRange(context.Background(), time.Now().Add(-time.Hour), time.Now())
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a StatusError, got %v", err)
+	}
+	if len(status.Body) > maxErrorQuoteSize {
+		t.Errorf("expected the error body to be truncated, got %d bytes", len(status.Body))
+	}
+}
+
+func TestClient_FetchObservationsRangeChunks(t *testing.T) {
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var windows [][2]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		windows = append(windows, [2]string{r.URL.Query().Get("starttime"), r.URL.Query().Get("endtime")})
+		w.Write(observations)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	start := time.Date(2026, 10, 3, 12, 4, 0, 0, time.UTC)
+	result, err := c.FetchObservationsRange(context.Background(), start, start.Add(10*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][2]string{
+		{"2026-10-03T12:00:00Z", "2026-10-10T11:50:00Z"},
+		{"2026-10-10T12:00:00Z", "2026-10-13T12:04:00Z"},
+	}
+	if !reflect.DeepEqual(windows, want) {
+		t.Fatalf("expected windows %v, got %v", want, windows)
+	}
+	single, err := ParseObservations(observations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Stations) != len(single.Stations) {
+		t.Errorf("expected %d distinct stations, got %d", len(single.Stations), len(result.Stations))
+	}
+	if len(result.Observations) != 2*len(single.Observations) {
+		t.Errorf("expected the observations of both windows, got %d", len(result.Observations))
+	}
+
+	if _, err := c.FetchObservationsRange(context.Background(), start, start.Add(-time.Hour)); err == nil {
+		t.Fatal("expected an error for a range that ends before it starts")
+	}
+}
+
+func TestClient_RetryRespectsDeadline(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetRetry(3, time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := c.FetchObservations(ctx); err == nil {
+		t.Fatal("expected error from failing upstream")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected fetch to give up before the deadline, took %v", elapsed)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 request, got %d", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base := 100 * time.Millisecond
+	for attempt, upper := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		for range 20 {
+			d := backoff(base, attempt+1)
+			if d < upper/2 || d > upper {
+				t.Fatalf("attempt %d: backoff %v outside [%v, %v]", attempt+1, d, upper/2, upper)
+			}
+		}
+	}
+}
+
+// filterForecastMembers keeps the members of the forecast fixture whose
+// parameter is in params, like FMI answering a parameters= query.
+func filterForecastMembers(doc string, params []string) string {
+	start := strings.Index(doc, "<wfs:member>")
+	end := strings.LastIndex(doc, "</wfs:member>") + len("</wfs:member>")
+	var kept strings.Builder
+	for _, m := range strings.SplitAfter(doc[start:end], "</wfs:member>") {
+		for _, p := range params {
+			if strings.Contains(m, "param="+p+"&") {
+				kept.WriteString(m)
+				break
+			}
+		}
+	}
+	return doc[:start] + kept.String() + doc[end:]
+}
+
+func TestClient_FetchForecastWithParameters(t *testing.T) {
+	fixture, err := os.ReadFile("fixtures/forecast.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requested string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Query().Get("parameters")
+		w.Write([]byte(filterForecastMembers(string(fixture), strings.Split(requested, ","))))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	// SmartSymbol is requested but missing from the response.
+	c.SetForecastParameters([]string{"Temperature", "WindSpeedMS", "SmartSymbol"})
+	data, err := c.FetchForecast(context.Background(), 60.17, 24.94, 2)
+	if err != nil {
+		t.Fatalf("fetch forecast: %v", err)
+	}
+	if requested != "Temperature,WindSpeedMS,SmartSymbol" {
+		t.Errorf("expected the configured parameters in the query, got %q", requested)
+	}
+	if len(data.Forecasts) == 0 {
+		t.Fatal("expected daily forecast entries")
+	}
+
+	required := []string{"TempHigh", "TempLow", "TempAvg", "WindSpeed"}
+	// The day/night split depends on the hours left today, and day length
+	// is computed without FMI data.
+	allowed := map[string]bool{
+		"TempDayMax": true, "TempDayAvg": true, "TempNightMin": true, "TempNightAvg": true,
+		"DayLengthHours": true,
+	}
+	day := reflect.ValueOf(data.Forecasts[0])
+	for _, name := range required {
+		allowed[name] = true
+		if day.FieldByName(name).IsNil() {
+			t.Errorf("expected %s to be set", name)
+		}
+	}
+	for i := range day.NumField() {
+		field, name := day.Field(i), day.Type().Field(i).Name
+		if field.Kind() == reflect.Pointer && !allowed[name] && !field.IsNil() {
+			t.Errorf("expected %s to be nil without its parameter", name)
+		}
+	}
+}
+
+func TestClient_ForecastModelSelectsStoredQuery(t *testing.T) {
+	var storedQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		storedQuery = r.URL.Query().Get("storedquery_id")
+		w.Write([]byte(`<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0"/>`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	for _, tc := range []struct {
+		model ForecastModel
+		want  string
+	}{
+		{"", "fmi::forecast::edited::weather::scandinavia::point::timevaluepair"},
+		{ModelHarmonie, "fmi::forecast::harmonie::surface::point::timevaluepair"},
+		{ModelEdited, "fmi::forecast::edited::weather::scandinavia::point::timevaluepair"},
+	} {
+		if err := c.SetForecastModel(tc.model); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.FetchHourlyForecast(context.Background(), 60.17, 24.94, 12); err != nil {
+			t.Fatalf("fetch hourly forecast: %v", err)
+		}
+		if storedQuery != tc.want {
+			t.Errorf("model %q: expected %s, got %s", tc.model, tc.want, storedQuery)
+		}
+	}
+	if err := c.SetForecastModel("gfs"); err == nil {
+		t.Error("expected error for an unknown model")
+	}
+}
+
+func TestClient_FetchForecastECMWF(t *testing.T) {
+	fixtures := map[string][]byte{}
+	for query, file := range map[string]string{
+		"ecmwf::forecast::surface::point::timevaluepair":                    "testdata/forecast_ecmwf.xml",
+		"fmi::forecast::edited::weather::scandinavia::point::timevaluepair": "fixtures/forecast.xml",
+	} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixtures[query] = data
+	}
+	var params url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params = r.URL.Query()
+		fixture, ok := fixtures[params.Get("storedquery_id")]
+		if !ok {
+			t.Errorf("unexpected stored query %s", params.Get("storedquery_id"))
+		}
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	// The configured parameters are for the edited model and not sent.
+	c.SetForecastParameters([]string{"Temperature"})
+	data, err := c.FetchForecastECMWF(context.Background(), 60.17, 24.94, 15)
+	if err != nil {
+		t.Fatalf("fetch ECMWF forecast: %v", err)
+	}
+	if params.Has("parameters") {
+		t.Errorf("edited model parameters sent to ECMWF: %s", params.Get("parameters"))
+	}
+	if len(data.Forecasts) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(data.Forecasts))
+	}
+	// Rain, snow on three-hour steps, then a clear day on six-hour steps.
+	want := []struct {
+		symbol   string
+		precipMM float64
+		snow     bool
+	}{
+		{"32", 4, false},
+		{"51", 1.6, true},
+		{"1", 0, false},
+	}
+	for i, w := range want {
+		f := data.Forecasts[i]
+		if f.Source != weather.ForecastSourceECMWF {
+			t.Errorf("day %d: expected source ecmwf, got %q", i, f.Source)
+		}
+		if f.Symbol == nil || *f.Symbol != w.symbol {
+			t.Errorf("day %d: expected symbol %s, got %v", i, w.symbol, f.Symbol)
+		}
+		if f.PrecipMM == nil || math.Abs(*f.PrecipMM-w.precipMM) > 0.01 {
+			t.Errorf("day %d: expected precipitation %.1f, got %v", i, w.precipMM, f.PrecipMM)
+		}
+		if f.SnowAccumulationMM == nil || (*f.SnowAccumulationMM > 0) != w.snow {
+			t.Errorf("day %d: expected snow %v, got %v", i, w.snow, f.SnowAccumulationMM)
+		}
+		if f.HourlyMaximumGustMax == nil {
+			t.Errorf("day %d: expected WindGust mapped to the hourly maximum gust", i)
+		}
+	}
+
+	data, err = c.FetchForecast(context.Background(), 60.17, 24.94, 10)
+	if err != nil {
+		t.Fatalf("fetch forecast: %v", err)
+	}
+	if len(data.Forecasts) == 0 || data.Forecasts[0].Source != weather.ForecastSourceEdited {
+		t.Errorf("expected days marked edited, got %+v", data.Forecasts)
+	}
+}
+
+func TestForecastTimeWindowUTC_EndsWithFinalLocalDay(t *testing.T) {
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name       string
+		now        time.Time
+		days       int
+		start, end string
+	}{
+		// 23:30 local is 21:30 UTC in winter; a UTC window would have
+		// stopped at 21:00 UTC on the final day.
+		{"winter", time.Date(2026, 1, 10, 23, 30, 0, 0, helsinki), 3, "2026-01-10T21:00:00Z", "2026-01-12T21:00:00Z"},
+		{"single day", time.Date(2026, 6, 15, 9, 0, 0, 0, helsinki), 1, "2026-06-15T06:00:00Z", "2026-06-15T20:00:00Z"},
+		// The window crosses the spring DST change, so the final day
+		// ends at 23:00 EEST.
+		{"spring forward", time.Date(2026, 3, 28, 12, 0, 0, 0, helsinki), 2, "2026-03-28T10:00:00Z", "2026-03-29T20:00:00Z"},
+		{"fall back", time.Date(2026, 10, 24, 12, 0, 0, 0, helsinki), 2, "2026-10-24T09:00:00Z", "2026-10-25T21:00:00Z"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			start, end := forecastTimeWindowUTC(c.now, c.days, helsinki)
+			if start != c.start || end != c.end {
+				t.Fatalf("got %s..%s, want %s..%s", start, end, c.start, c.end)
+			}
+		})
+	}
+}
+
+func TestClient_FetchForecastCoversFinalDay(t *testing.T) {
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// FMI answers with every hour of the requested window, both ends
+	// included.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, err := time.Parse(time.RFC3339, r.URL.Query().Get("starttime"))
+		if err != nil {
+			t.Error(err)
+		}
+		end, err := time.Parse(time.RFC3339, r.URL.Query().Get("endtime"))
+		if err != nil {
+			t.Error(err)
+		}
+		w.Write(hourlyForecastXML("Europe/Helsinki", "Temperature", start, end.Add(time.Hour), 2))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.now = func() time.Time { return time.Date(2026, 2, 16, 14, 0, 0, 0, helsinki) }
+	result, err := c.FetchForecast(context.Background(), 60.17, 24.94, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Forecasts) != 3 {
+		t.Fatalf("expected 3 days, got %d", len(result.Forecasts))
+	}
+	last := result.Forecasts[2].Date
+	if last.Format(time.DateOnly) != "2026-02-18" {
+		t.Fatalf("expected the last day to be 2026-02-18, got %s", last.Format(time.DateOnly))
+	}
+	var hours int
+	for _, h := range result.Hourly {
+		if h.Time.In(helsinki).Format(time.DateOnly) == "2026-02-18" {
+			hours++
+		}
+	}
+	if hours != 24 {
+		t.Errorf("expected 24 hourly samples on the last day, got %d", hours)
+	}
+}
+
+func TestClient_FetchHourlyForecastSkipsPastHours(t *testing.T) {
+	fixture, err := os.ReadFile("fixtures/forecast.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var start, end string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start, end = r.URL.Query().Get("starttime"), r.URL.Query().Get("endtime")
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	now := time.Date(2026, 2, 16, 9, 40, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	hourly, err := c.FetchHourlyForecast(context.Background(), 60.17, 24.94, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One hour of margin past the 12 asked for.
+	if start != "2026-02-16T09:00:00Z" || end != "2026-02-16T21:00:00Z" {
+		t.Errorf("expected 09:00..21:00, got %s..%s", start, end)
+	}
+	// The fixture starts at 08:00, before the current hour.
+	if len(hourly) != 12 || !hourly[0].Time.Equal(now.Truncate(time.Hour)) {
+		t.Fatalf("expected 12 hours from 09:00, got %d from %v", len(hourly), hourly[0].Time)
+	}
+}
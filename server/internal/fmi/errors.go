@@ -0,0 +1,62 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"wby/internal/weather"
+)
+
+// Every error the client returns for a failed FMI call matches, with
+// errors.Is, one of these categories. They are the weather package's
+// sentinels, so the weather service can tell them apart without importing
+// this package.
+var (
+	// ErrRateLimited is a 429.
+	ErrRateLimited = weather.ErrUpstreamRateLimited
+	// ErrUpstreamUnavailable is a 5xx, a network error or a timeout, or
+	// ErrCircuitOpen.
+	ErrUpstreamUnavailable = weather.ErrUpstreamUnavailable
+	// ErrBadRequest is any other 4xx, with or without an ExceptionReport:
+	// FMI refused the query, so sending it again won't help.
+	ErrBadRequest = weather.ErrUpstreamRejected
+	// ErrParse is a response that arrived but could not be decoded,
+	// including one over its size cap.
+	ErrParse = weather.ErrUpstreamMalformed
+)
+
+// statusCategory is the category of an FMI response with status code, or
+// nil for a success.
+func statusCategory(code int) error {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code >= 500:
+		return ErrUpstreamUnavailable
+	case code >= 400:
+		return ErrBadRequest
+	}
+	return nil
+}
+
+// networkError is a request that got no complete response: the connection
+// failed or dropped, or the client's deadline passed.
+type networkError struct {
+	err error
+}
+
+func (e *networkError) Error() string { return e.err.Error() }
+
+func (e *networkError) Unwrap() error { return e.err }
+
+func (e *networkError) Is(target error) bool { return target == ErrUpstreamUnavailable }
+
+// asNetworkError wraps err as a networkError unless the caller canceled
+// the request, which says nothing about FMI.
+func asNetworkError(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	return &networkError{err: err}
+}
@@ -0,0 +1,67 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestClient_ClassifiesErrors(t *testing.T) {
+	exception, err := os.ReadFile("testdata/exception_report.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	categories := []error{ErrRateLimited, ErrUpstreamUnavailable, ErrBadRequest, ErrParse}
+	cases := []struct {
+		name   string
+		status int
+		body   []byte
+		want   error
+	}{
+		{"too many requests", http.StatusTooManyRequests, []byte("slow down"), ErrRateLimited},
+		{"server error", http.StatusBadGateway, []byte("<html>Bad Gateway</html>"), ErrUpstreamUnavailable},
+		{"exception report", http.StatusBadRequest, exception, ErrBadRequest},
+		{"proxy 4xx", http.StatusForbidden, []byte("forbidden"), ErrBadRequest},
+		{"truncated forecast", http.StatusOK, []byte(`<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0"><wfs:member>`), ErrParse},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write(tc.body)
+			}))
+			defer srv.Close()
+
+			c := NewClient(srv.URL, "", "")
+			c.SetRetry(0, time.Millisecond)
+			_, err := c.FetchForecast(context.Background(), 60.17, 24.94, 3)
+			for _, category := range categories {
+				if got := errors.Is(err, category); got != (category == tc.want) {
+					t.Errorf("errors.Is(%v, %v) = %t", err, category, got)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_NetworkErrorIsUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetRetry(0, time.Millisecond)
+	_, err := c.FetchHourlyForecast(context.Background(), 60.17, 24.94, 12)
+	if !errors.Is(err, ErrUpstreamUnavailable) || errors.Is(err, ErrBadRequest) {
+		t.Fatalf("expected a refused connection to be an outage, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.FetchHourlyForecast(ctx, 60.17, 24.94, 12); errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("expected a canceled request not to count as an outage, got %v", err)
+	}
+}
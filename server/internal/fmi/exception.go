@@ -0,0 +1,62 @@
+package fmi
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is an OWS ExceptionReport FMI sent instead of data, typically
+// with HTTP 400 for an unknown stored query or an invalid parameter.
+type APIError struct {
+	// Code is the exceptionCode of the first exception, e.g.
+	// "OperationParsingFailed" or "InvalidParameterValue".
+	Code string
+	// Texts holds the ExceptionText lines of every exception in order.
+	Texts      []string
+	HTTPStatus int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("FMI returned %d %s: %s", e.HTTPStatus, e.Code, strings.Join(e.Texts, "; "))
+}
+
+// QueryRejected reports whether FMI refused the query itself, so retrying
+// it unchanged cannot succeed, rather than failing to answer it.
+func (e *APIError) QueryRejected() bool {
+	return e.HTTPStatus >= 400 && e.HTTPStatus < 500 && e.HTTPStatus != http.StatusTooManyRequests
+}
+
+// Is lets the weather service, which cannot import this package, detect
+// rejected queries with errors.Is(err, weather.ErrUpstreamRejected), and
+// matches the other categories of the status code likewise.
+func (e *APIError) Is(target error) bool {
+	return target != nil && target == statusCategory(e.HTTPStatus)
+}
+
+type exceptionReport struct {
+	XMLName    xml.Name `xml:"ExceptionReport"`
+	Exceptions []struct {
+		Code  string   `xml:"exceptionCode,attr"`
+		Texts []string `xml:"ExceptionText"`
+	} `xml:"Exception"`
+}
+
+// parseExceptionReport decodes an ows:ExceptionReport body. It returns nil
+// when data is not one, as for an HTML error page from a proxy.
+func parseExceptionReport(data []byte, status int) *APIError {
+	var report exceptionReport
+	if err := xml.Unmarshal(data, &report); err != nil || len(report.Exceptions) == 0 {
+		return nil
+	}
+	apiErr := &APIError{Code: report.Exceptions[0].Code, HTTPStatus: status}
+	for _, ex := range report.Exceptions {
+		for _, text := range ex.Texts {
+			if text = strings.TrimSpace(text); text != "" {
+				apiErr.Texts = append(apiErr.Texts, text)
+			}
+		}
+	}
+	return apiErr
+}
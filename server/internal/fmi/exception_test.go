@@ -0,0 +1,67 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestParseExceptionReport(t *testing.T) {
+	data, err := os.ReadFile("testdata/exception_report.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiErr := parseExceptionReport(data, http.StatusBadRequest)
+	if apiErr == nil {
+		t.Fatal("expected an exception report")
+	}
+	if apiErr.Code != "OperationParsingFailed" {
+		t.Errorf("expected code OperationParsingFailed, got %q", apiErr.Code)
+	}
+	if len(apiErr.Texts) != 3 || apiErr.Texts[2] != "Stored query 'fmi::observations::weather::timevaluepairs' is not available" {
+		t.Errorf("unexpected texts %q", apiErr.Texts)
+	}
+
+	if parseExceptionReport([]byte("<html><body>Bad Gateway</body></html>"), http.StatusBadGateway) != nil {
+		t.Error("expected nil for a body that is not an exception report")
+	}
+}
+
+func TestClient_ReturnsAPIErrorForExceptionReport(t *testing.T) {
+	report, err := os.ReadFile("testdata/exception_report.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(report)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetRetry(3, time.Millisecond)
+	_, err = c.FetchObservations(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.HTTPStatus != http.StatusBadRequest || !apiErr.QueryRejected() {
+		t.Errorf("expected a rejected query with status 400, got %+v", apiErr)
+	}
+	if !errors.Is(err, weather.ErrUpstreamRejected) {
+		t.Error("expected errors.Is to match weather.ErrUpstreamRejected")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected a rejected query not to be retried, got %d requests", got)
+	}
+}
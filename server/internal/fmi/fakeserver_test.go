@@ -0,0 +1,206 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"wby/internal/fmi/fmitest"
+)
+
+// fakeServerNow is when the fake server tests run, the first hour of the
+// forecast fixture.
+var fakeServerNow = time.Date(2026, 2, 16, 8, 10, 0, 0, time.UTC)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func assertParams(t *testing.T, got url.Values, want url.Values) {
+	t.Helper()
+	if !maps.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("sent %s\nwant %s", got.Encode(), want.Encode())
+	}
+}
+
+func TestClient_FetchForecastFromFakeServer(t *testing.T) {
+	const query = "fmi::forecast::edited::weather::scandinavia::point::timevaluepair"
+	srv := fmitest.NewServer(t)
+	srv.Serve(query, readFixture(t, "fixtures/forecast.xml"))
+
+	c := NewClient(srv.WFSURL(), "", "")
+	c.now = func() time.Time { return fakeServerNow }
+	result, err := c.FetchForecast(context.Background(), 60.17, 24.94, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Forecasts) == 0 {
+		t.Fatal("expected forecasts")
+	}
+	// Three Helsinki days end at 23:00 local, UTC+2 in February.
+	assertParams(t, srv.LastRequest(query).Params, url.Values{
+		"service":        {"WFS"},
+		"version":        {"2.0.0"},
+		"request":        {"getFeature"},
+		"storedquery_id": {query},
+		"latlon":         {"60.170000,24.940000"},
+		"timestep":       {"60"},
+		"starttime":      {"2026-02-16T08:00:00Z"},
+		"endtime":        {"2026-02-18T21:00:00Z"},
+	})
+}
+
+func TestClient_FetchObservationsFromFakeServer(t *testing.T) {
+	const query = "fmi::observations::weather::timevaluepair"
+	srv := fmitest.NewServer(t)
+	srv.Serve(query, readFixture(t, "fixtures/observations.xml"))
+
+	c := NewClient(srv.WFSURL(), "", "")
+	result, err := c.FetchObservations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Observations) == 0 {
+		t.Fatal("expected observations")
+	}
+	assertParams(t, srv.LastRequest(query).Params, url.Values{
+		"service":        {"WFS"},
+		"version":        {"2.0.0"},
+		"request":        {"getFeature"},
+		"storedquery_id": {query},
+		"timestep":       {"10"},
+		"maxlocations":   {"200"},
+		"bbox":           {"19,59,32,71"},
+	})
+}
+
+func TestClient_FetchUVForecastFromFakeServer(t *testing.T) {
+	const query = "timeseries::uv"
+	want := url.Values{
+		"param":     {"epochtime,uvCumulated"},
+		"producer":  {"uv"},
+		"format":    {"json"},
+		"latlon":    {"60.170000,24.940000"},
+		"timesteps": {"30"},
+		"starttime": {"2026-02-16T08:00:00Z"},
+	}
+	for _, mode := range []APIKeyMode{APIKeyPath, APIKeyHeader} {
+		t.Run(string(mode), func(t *testing.T) {
+			srv := fmitest.NewServer(t)
+			srv.Serve(query, readFixture(t, "fixtures/uv.json"))
+
+			c := NewClient(srv.WFSURL(), "secret", srv.TimeseriesURL())
+			c.now = func() time.Time { return fakeServerNow }
+			if err := c.SetAPIKeyMode(mode); err != nil {
+				t.Fatal(err)
+			}
+			points, err := c.FetchUVForecast(context.Background(), 60.17, 24.94)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(points) == 0 {
+				t.Fatal("expected UV points")
+			}
+			req := srv.LastRequest(query)
+			if req.APIKey != "secret" {
+				t.Errorf("expected the API key to be sent, got %q", req.APIKey)
+			}
+			assertParams(t, req.Params, want)
+		})
+	}
+}
+
+func TestClient_FakeServerErrors(t *testing.T) {
+	const query = "fmi::forecast::edited::weather::scandinavia::point::timevaluepair"
+	tests := []struct {
+		name     string
+		status   int
+		body     []byte
+		want     error
+		requests int
+	}{
+		{"outage is retried", http.StatusServiceUnavailable, []byte("unavailable"), ErrUpstreamUnavailable, DefaultRetryAttempts},
+		{"exception report is not", http.StatusBadRequest, readFixture(t, "testdata/exception_report.xml"), ErrBadRequest, 1},
+		{"truncated document", http.StatusOK, []byte(`<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0"><wfs:member>`), ErrParse, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := fmitest.NewServer(t)
+			srv.Fail(query, tt.status, tt.body)
+
+			c := NewClient(srv.WFSURL(), "", "")
+			c.SetRetry(DefaultRetryAttempts, time.Millisecond)
+			c.now = func() time.Time { return fakeServerNow }
+			_, err := c.FetchForecast(context.Background(), 60.17, 24.94, 3)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			var apiErr *APIError
+			if tt.want == ErrBadRequest && (!errors.As(err, &apiErr) || apiErr.Code != "OperationParsingFailed") {
+				t.Errorf("expected the exception report as an *APIError, got %v", err)
+			}
+			if got := len(srv.Requests()); got != tt.requests {
+				t.Errorf("expected %d requests, got %d", tt.requests, got)
+			}
+		})
+	}
+}
+
+func TestClient_FetchForecastBatchFromFakeServer(t *testing.T) {
+	const query = "fmi::forecast::edited::weather::scandinavia::point::timevaluepair"
+	srv := fmitest.NewServer(t)
+	from := time.Date(2026, 2, 16, 8, 0, 0, 0, time.UTC)
+	srv.Serve(query, batchForecastXML(from, from.Add(12*time.Hour), map[string]float64{
+		"60.17000 24.94000": -3,
+		"61.49800 23.76000": -8,
+	}))
+
+	c := NewClient(srv.WFSURL(), "", "")
+	c.now = func() time.Time { return fakeServerNow }
+	points := []LatLon{{Lat: 60.17, Lon: 24.94}, {Lat: 61.498, Lon: 23.76}}
+	results, err := c.FetchForecastBatch(context.Background(), points, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []float64{-3, -8} {
+		f := results[i].Forecasts
+		if len(f) != 1 || f[0].TempAvg == nil || *f[0].TempAvg != want || f[0].Source != string(ModelEdited) {
+			t.Errorf("point %v: expected an edited forecast averaging %g, got %+v", points[i], want, f)
+		}
+	}
+	assertParams(t, srv.LastRequest(query).Params, url.Values{
+		"service":        {"WFS"},
+		"version":        {"2.0.0"},
+		"request":        {"getFeature"},
+		"storedquery_id": {query},
+		"latlon":         {"60.170000,24.940000", "61.498000,23.760000"},
+		"timestep":       {"60"},
+		"starttime":      {"2026-02-16T08:00:00Z"},
+		"endtime":        {"2026-02-16T21:00:00Z"},
+	})
+
+	// Points past MaxForecastBatch take another request.
+	many := slices.Repeat(points, MaxForecastBatch/2+1)
+	results, err = c.FetchForecastBatch(context.Background(), many, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(many) || results[len(many)-1].Forecasts == nil {
+		t.Fatalf("expected a forecast for each of %d points, got %d", len(many), len(results))
+	}
+	requests := srv.Requests()
+	if len(requests) != 3 || len(requests[1].Params["latlon"]) != MaxForecastBatch || len(requests[2].Params["latlon"]) != 2 {
+		t.Errorf("expected batches of %d and 2 points after the first request, got %d requests", MaxForecastBatch, len(requests))
+	}
+}
@@ -0,0 +1,139 @@
+package fmi
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"wby/internal/weather"
+)
+
+// FinlandBBox covers Finland, where observations are fetched from unless
+// an ObservationFilter says otherwise.
+var FinlandBBox = weather.BBox{MinLon: 19, MinLat: 59, MaxLon: 32, MaxLat: 71}
+
+// ObservationFilter selects the stations observations are fetched for:
+// those inside BBox, at Places, or with FMISIDs. Exactly one is set.
+type ObservationFilter struct {
+	BBox    *weather.BBox
+	Places  []string
+	FMISIDs []int
+}
+
+// DefaultObservationFilter fetches every station in Finland.
+func DefaultObservationFilter() ObservationFilter {
+	bbox := FinlandBBox
+	return ObservationFilter{BBox: &bbox}
+}
+
+// ParseObservationFilter builds a filter from a "minLon,minLat,maxLon,maxLat"
+// bbox, place names or FMISIDs, of which at most one may be given; none
+// is DefaultObservationFilter.
+func ParseObservationFilter(bbox string, places, fmisids []string) (ObservationFilter, error) {
+	var f ObservationFilter
+	if bbox != "" {
+		fields := strings.Split(bbox, ",")
+		if len(fields) != 4 {
+			return f, fmt.Errorf("observation bbox %q: want minLon,minLat,maxLon,maxLat", bbox)
+		}
+		var v [4]float64
+		for i, field := range fields {
+			n, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return f, fmt.Errorf("observation bbox %q: %w", bbox, err)
+			}
+			v[i] = n
+		}
+		f.BBox = &weather.BBox{MinLon: v[0], MinLat: v[1], MaxLon: v[2], MaxLat: v[3]}
+	}
+	f.Places = places
+	for _, raw := range fmisids {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return f, fmt.Errorf("observation FMISID %q is not a station ID", raw)
+		}
+		f.FMISIDs = append(f.FMISIDs, id)
+	}
+	if f.BBox == nil && len(f.Places) == 0 && len(f.FMISIDs) == 0 {
+		return DefaultObservationFilter(), nil
+	}
+	return f, f.validate()
+}
+
+func (f ObservationFilter) validate() error {
+	set := 0
+	if f.BBox != nil {
+		set++
+		if f.BBox.MinLon >= f.BBox.MaxLon || f.BBox.MinLat >= f.BBox.MaxLat {
+			return fmt.Errorf("observation bbox %s is empty", f)
+		}
+	}
+	if len(f.Places) > 0 {
+		set++
+	}
+	if len(f.FMISIDs) > 0 {
+		set++
+	}
+	if set != 1 {
+		return errors.New("set exactly one of an observation bbox, places or FMISIDs")
+	}
+	return nil
+}
+
+// IsDefault reports whether f is DefaultObservationFilter.
+func (f ObservationFilter) IsDefault() bool {
+	return f.BBox != nil && *f.BBox == FinlandBBox && len(f.Places) == 0 && len(f.FMISIDs) == 0
+}
+
+// String describes f for logs, e.g. "places Helsinki,Espoo".
+func (f ObservationFilter) String() string {
+	switch {
+	case f.BBox != nil:
+		return "bbox " + bboxParam(*f.BBox)
+	case len(f.Places) > 0:
+		return "places " + strings.Join(f.Places, ",")
+	default:
+		return "fmisids " + joinInts(f.FMISIDs)
+	}
+}
+
+// wfsParams sets the WFS stored query parameters selecting f's stations;
+// places and FMISIDs are repeated parameters.
+func (f ObservationFilter) wfsParams(params url.Values) {
+	switch {
+	case f.BBox != nil:
+		params.Set("bbox", bboxParam(*f.BBox))
+	case len(f.Places) > 0:
+		params["place"] = f.Places
+	default:
+		for _, id := range f.FMISIDs {
+			params.Add("fmisid", strconv.Itoa(id))
+		}
+	}
+}
+
+// timeseriesParams is wfsParams for the timeseries API, which takes lists.
+func (f ObservationFilter) timeseriesParams(params url.Values) {
+	switch {
+	case f.BBox != nil:
+		params.Set("bbox", bboxParam(*f.BBox))
+	case len(f.Places) > 0:
+		params.Set("places", strings.Join(f.Places, ","))
+	default:
+		params.Set("fmisid", joinInts(f.FMISIDs))
+	}
+}
+
+func bboxParam(b weather.BBox) string {
+	return fmt.Sprintf("%g,%g,%g,%g", b.MinLon, b.MinLat, b.MaxLon, b.MaxLat)
+}
+
+func joinInts(ids []int) string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.Itoa(id)
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,102 @@
+package fmi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseObservationFilter(t *testing.T) {
+	cases := []struct {
+		name            string
+		bbox            string
+		places, fmisids []string
+		want            string
+		wantErr         bool
+	}{
+		{name: "default", want: "bbox 19,59,32,71"},
+		{name: "bbox", bbox: "24.5, 60.1, 25.3, 60.4", want: "bbox 24.5,60.1,25.3,60.4"},
+		{name: "places", places: []string{"Helsinki", "Espoo"}, want: "places Helsinki,Espoo"},
+		{name: "fmisids", fmisids: []string{"100971", "101004"}, want: "fmisids 100971,101004"},
+		{name: "short bbox", bbox: "24,60,25", wantErr: true},
+		{name: "empty bbox", bbox: "25,60,24,61", wantErr: true},
+		{name: "bad fmisid", fmisids: []string{"kumpula"}, wantErr: true},
+		{name: "two filters", bbox: "24,60,25,61", places: []string{"Helsinki"}, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := ParseObservationFilter(tc.bbox, tc.places, tc.fmisids)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if err == nil && f.String() != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, f)
+			}
+		})
+	}
+	if !DefaultObservationFilter().IsDefault() {
+		t.Fatal("expected the default filter to report so")
+	}
+}
+
+func TestClient_FetchObservationsWithFilter(t *testing.T) {
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write(observations)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	if err := c.SetObservationFilter(ObservationFilter{FMISIDs: []int{100971, 101004}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FetchObservations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(query["fmisid"], []string{"100971", "101004"}) || query.Has("bbox") {
+		t.Fatalf("expected repeated fmisid parameters and no bbox, got %v", query)
+	}
+
+	if err := c.SetObservationFilter(ObservationFilter{Places: []string{"Helsinki", "Espoo"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FetchObservations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(query["place"], []string{"Helsinki", "Espoo"}) {
+		t.Fatalf("expected repeated place parameters, got %v", query)
+	}
+
+	if err := c.SetObservationFilter(ObservationFilter{}); err == nil {
+		t.Fatal("expected a filter without a selection to be rejected")
+	}
+}
+
+func TestClient_FetchObservationsTimeseriesWithFilter(t *testing.T) {
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("", "secret", srv.URL)
+	if err := c.SetObservationFilter(ObservationFilter{Places: []string{"Helsinki", "Espoo"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FetchObservations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("places") != "Helsinki,Espoo" || query.Has("bbox") {
+		t.Fatalf("expected a places list and no bbox, got %v", query)
+	}
+}
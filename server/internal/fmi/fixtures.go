@@ -0,0 +1,152 @@
+package fmi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"wby/internal/weather"
+)
+
+// fixtureURL is the base URL of a FixtureClient; its requests never leave
+// the process.
+const fixtureURL = "http://fixtures.invalid/wfs"
+
+// emptyFeatureCollection answers the stored queries there is no fixture
+// for, so background fetches store nothing instead of failing.
+const emptyFeatureCollection = `<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" numberMatched="0" numberReturned="0"/>`
+
+// FixtureClient is a Client that serves recorded responses instead of
+// calling FMI, for running the server offline and as a
+// deterministic upstream in integration tests. Observations are shifted so
+// the latest is from the current ten minutes; forecasts and UV by whole
+// days, keeping their daily cycle, so they cover the current hour. Stored
+// queries without a fixture return no features.
+type FixtureClient struct {
+	*Client
+	files fs.FS
+}
+
+// NewFixtureClient returns a FixtureClient serving the observations.xml,
+// forecast.xml and uv.json in files, such as fixtures.FS. The recordings
+// are not embedded here, so binaries that never serve them stay small.
+func NewFixtureClient(files fs.FS) *FixtureClient {
+	c := NewClient(fixtureURL, "", "")
+	c.httpClient = &http.Client{Transport: fixtureTransport{files: files, now: func() time.Time { return c.now() }}}
+	c.SetRetry(0, 0)
+	c.SetCircuitBreaker(0, 0)
+	return &FixtureClient{Client: c, files: files}
+}
+
+// FetchUVForecast returns the UV fixture; the Client would need an API
+// key to ask for it.
+func (f *FixtureClient) FetchUVForecast(ctx context.Context, lat, lon float64) ([]weather.UVDataPoint, error) {
+	data, err := fs.ReadFile(f.files, "uv.json")
+	if err != nil {
+		return nil, err
+	}
+	points, err := parseUVForecast(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("UV fixture: %w", err)
+	}
+	if len(points) == 0 {
+		return nil, nil
+	}
+	shift := wholeDaysUntil(points[0].Time, f.now())
+	for i := range points {
+		points[i].Time = points[i].Time.Add(shift)
+	}
+	return points, nil
+}
+
+// fixtureTransport answers WFS requests from the fixture files.
+type fixtureTransport struct {
+	files fs.FS
+	now   func() time.Time
+}
+
+func (t fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := t.fixture(req.URL.Query().Get("storedquery_id"))
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/xml"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func (t fixtureTransport) fixture(query string) ([]byte, error) {
+	switch {
+	case query == "fmi::observations::weather::timevaluepair":
+		data, err := fs.ReadFile(t.files, "observations.xml")
+		if err != nil {
+			return nil, err
+		}
+		times := fixtureTimes(data)
+		if len(times) == 0 {
+			return data, nil
+		}
+		latest := slices.MaxFunc(times, time.Time.Compare)
+		return shiftTimes(data, t.now().UTC().Truncate(observationTimestep).Sub(latest)), nil
+	case strings.Contains(query, "::forecast::"):
+		data, err := fs.ReadFile(t.files, "forecast.xml")
+		if err != nil {
+			return nil, err
+		}
+		times := fixtureTimes(data)
+		if len(times) == 0 {
+			return data, nil
+		}
+		earliest := slices.MinFunc(times, time.Time.Compare)
+		return shiftTimes(data, wholeDaysUntil(earliest, t.now())), nil
+	}
+	return []byte(emptyFeatureCollection), nil
+}
+
+var (
+	fixtureTimeRE = regexp.MustCompile(`<wml2:time>([^<]+)</wml2:time>`)
+	timestampRE   = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z`)
+)
+
+// fixtureTimes returns the times of a fixture's time/value pairs.
+func fixtureTimes(data []byte) []time.Time {
+	var times []time.Time
+	for _, m := range fixtureTimeRE.FindAllSubmatch(data, -1) {
+		if t, err := time.Parse(time.RFC3339, string(m[1])); err == nil {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+// shiftTimes moves every UTC timestamp in data by d.
+func shiftTimes(data []byte, d time.Duration) []byte {
+	return timestampRE.ReplaceAllFunc(data, func(ts []byte) []byte {
+		t, err := time.Parse(time.RFC3339, string(ts))
+		if err != nil {
+			return ts
+		}
+		return []byte(t.Add(d).Format(time.RFC3339))
+	})
+}
+
+// wholeDaysUntil is the shift, in whole days, that moves start to the last
+// time of day it has at or before the current hour of now.
+func wholeDaysUntil(start, now time.Time) time.Duration {
+	diff := now.UTC().Truncate(time.Hour).Sub(start)
+	days := diff / (24 * time.Hour)
+	if diff < 0 && diff%(24*time.Hour) != 0 {
+		days--
+	}
+	return days * 24 * time.Hour
+}
@@ -0,0 +1,11 @@
+// Package fixtures holds FMI responses recorded for fmi.FixtureClient:
+// observations, the edited forecast and the UV forecast. It is a package of
+// its own so that only binaries built to serve fixtures embed them.
+package fixtures
+
+import "embed"
+
+// FS holds observations.xml, forecast.xml and uv.json.
+//
+//go:embed observations.xml forecast.xml uv.json
+var FS embed.FS
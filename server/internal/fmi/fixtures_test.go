@@ -0,0 +1,85 @@
+package fmi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wby/internal/fmi/fixtures"
+)
+
+func TestFixtureClient_ShiftsFixturesToNow(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 34, 0, 0, time.UTC)
+	c := NewFixtureClient(fixtures.FS)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	obs, err := c.FetchObservations(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(obs.Stations) == 0 || len(obs.Observations) == 0 {
+		t.Fatal("expected the observation fixture")
+	}
+	latest := obs.Observations[0].ObservedAt
+	for _, o := range obs.Observations {
+		if o.ObservedAt.After(latest) {
+			latest = o.ObservedAt
+		}
+	}
+	if want := time.Date(2026, 10, 16, 12, 30, 0, 0, time.UTC); !latest.Equal(want) {
+		t.Errorf("expected the latest observation at %s, got %s", want, latest)
+	}
+
+	hourly, err := c.FetchHourlyForecast(ctx, 60.17, 24.94, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hourly) != 12 || !hourly[0].Time.Equal(now.Truncate(time.Hour)) {
+		t.Fatalf("expected 12 hours from %s, got %d from %v", now.Truncate(time.Hour), len(hourly), hourly)
+	}
+
+	forecast, err := c.FetchForecast(ctx, 60.17, 24.94, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forecast.Forecasts) == 0 || forecast.Forecasts[0].Date.Format(time.DateOnly) != "2026-10-16" {
+		t.Fatalf("expected daily forecasts from today, got %+v", forecast.Forecasts)
+	}
+
+	uv, err := c.FetchUVForecast(ctx, 60.17, 24.94)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uv) == 0 || uv[0].Time.After(now) || !uv[len(uv)-1].Time.After(now.Add(24*time.Hour)) {
+		t.Fatalf("expected UV covering the next day, got %v", uv)
+	}
+}
+
+func TestFixtureClient_EmptyWithoutFixture(t *testing.T) {
+	aq, err := NewFixtureClient(fixtures.FS).FetchAirQuality(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aq.Stations) != 0 {
+		t.Fatalf("expected no air quality stations, got %d", len(aq.Stations))
+	}
+}
+
+func TestWholeDaysUntil(t *testing.T) {
+	start := time.Date(2026, 2, 16, 8, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		now  time.Time
+		want time.Duration
+	}{
+		{time.Date(2026, 2, 16, 8, 30, 0, 0, time.UTC), 0},
+		{time.Date(2026, 2, 20, 7, 59, 0, 0, time.UTC), 3 * 24 * time.Hour},
+		{time.Date(2026, 2, 20, 8, 0, 0, 0, time.UTC), 4 * 24 * time.Hour},
+		{time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC), -24 * time.Hour},
+		{time.Date(2026, 2, 15, 7, 0, 0, 0, time.UTC), -48 * time.Hour},
+	} {
+		if got := wholeDaysUntil(start, c.now); got != c.want {
+			t.Errorf("wholeDaysUntil(%s) = %s, want %s", c.now, got, c.want)
+		}
+	}
+}
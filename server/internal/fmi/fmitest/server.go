@@ -0,0 +1,225 @@
+// Package fmitest is a fake FMI open data API for testing the fmi client
+// end to end: a WFS endpoint and a timeseries API that check the
+// parameters they are called with and serve the responses a test sets.
+package fmitest
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Request is a request the Server received.
+type Request struct {
+	// Query is the stored query id of a WFS request, or "timeseries::"
+	// followed by the producer of a timeseries request.
+	Query string
+	// APIKey is the key in a timeseries request's /fmi-apikey/ path
+	// prefix or fmi-apikey header.
+	APIKey string
+	Params url.Values
+}
+
+// Response is what the Server answers a query with.
+type Response struct {
+	// Status defaults to 200.
+	Status int
+	Body   []byte
+	// Header is added to the response.
+	Header http.Header
+}
+
+// Server is a fake FMI API. Requests with missing or malformed parameters
+// fail the test and get an exception report, as do queries without a
+// response.
+type Server struct {
+	t   testing.TB
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	responses map[string]Response
+	requests  []Request
+}
+
+// NewServer starts a Server that is closed when the test ends.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{t: t, responses: map[string]Response{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /wfs", s.serveWFS)
+	mux.HandleFunc("GET /timeseries", s.serveTimeseries)
+	mux.HandleFunc("GET /fmi-apikey/{key}/timeseries", s.serveTimeseries)
+	s.srv = httptest.NewServer(mux)
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+// WFSURL is the WFS endpoint, the fmi client's base URL.
+func (s *Server) WFSURL() string { return s.srv.URL + "/wfs" }
+
+// TimeseriesURL is the timeseries API root, the fmi client's timeseries
+// URL.
+func (s *Server) TimeseriesURL() string { return s.srv.URL }
+
+// Serve answers query, a stored query id or "timeseries::<producer>",
+// with a 200 and body.
+func (s *Server) Serve(query string, body []byte) {
+	s.Respond(query, Response{Body: body})
+}
+
+// Fail answers query with status and body, e.g. an exception report.
+func (s *Server) Fail(query string, status int, body []byte) {
+	s.Respond(query, Response{Status: status, Body: body})
+}
+
+// Respond answers query with r.
+func (s *Server) Respond(query string, r Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[query] = r
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest returns the latest request for query, failing the test if
+// there was none.
+func (s *Server) LastRequest(query string) Request {
+	s.t.Helper()
+	requests := s.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Query == query {
+			return requests[i]
+		}
+	}
+	s.t.Fatalf("fmitest: no request for %s", query)
+	return Request{}
+}
+
+func (s *Server) serveWFS(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	req := Request{Query: params.Get("storedquery_id"), Params: params}
+	s.serve(w, req, validateWFS(params))
+}
+
+func (s *Server) serveTimeseries(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	req := Request{Query: "timeseries::" + params.Get("producer"), APIKey: r.PathValue("key"), Params: params}
+	if key := r.Header.Get("fmi-apikey"); key != "" {
+		req.APIKey = key
+	}
+	s.serve(w, req, validateTimeseries(params))
+}
+
+func (s *Server) serve(w http.ResponseWriter, req Request, problems []string) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	resp, ok := s.responses[req.Query]
+	s.mu.Unlock()
+
+	if len(problems) > 0 {
+		s.t.Errorf("fmitest: invalid %s request %s: %s", req.Query, req.Params.Encode(), strings.Join(problems, "; "))
+		writeException(w, "InvalidParameterValue", problems)
+		return
+	}
+	if !ok {
+		s.t.Errorf("fmitest: no response for %s", req.Query)
+		writeException(w, "OperationParsingFailed", []string{fmt.Sprintf("Stored query '%s' is not available", req.Query)})
+		return
+	}
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	if resp.Status != 0 {
+		w.WriteHeader(resp.Status)
+	}
+	w.Write(resp.Body)
+}
+
+// writeException answers with a 400 and an OWS exception report, the way
+// FMI rejects a request.
+func writeException(w http.ResponseWriter, code string, texts []string) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	b.WriteString(`<ExceptionReport xmlns="http://www.opengis.net/ows/1.1" version="2.0.0" xml:lang="eng">` + "\n")
+	fmt.Fprintf(&b, "  <Exception exceptionCode=%q>\n", code)
+	for _, text := range texts {
+		fmt.Fprintf(&b, "    <ExceptionText>%s</ExceptionText>\n", html.EscapeString(text))
+	}
+	b.WriteString("  </Exception>\n</ExceptionReport>\n")
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write([]byte(b.String()))
+}
+
+var (
+	latlonRE = regexp.MustCompile(`^-?\d+(\.\d+)?,-?\d+(\.\d+)?$`)
+	bboxRE   = regexp.MustCompile(`^-?\d+(\.\d+)?(,-?\d+(\.\d+)?){3}$`)
+)
+
+// validateWFS returns what is wrong with a stored query request.
+func validateWFS(params url.Values) []string {
+	var problems []string
+	for _, p := range [][2]string{{"service", "WFS"}, {"version", "2.0.0"}, {"request", "getFeature"}} {
+		if got := params.Get(p[0]); got != p[1] {
+			problems = append(problems, fmt.Sprintf("%s is %q, want %q", p[0], got, p[1]))
+		}
+	}
+	if params.Get("storedquery_id") == "" {
+		problems = append(problems, "no storedquery_id")
+	}
+	return append(problems, validateCommon(params, "timestep")...)
+}
+
+// validateTimeseries returns what is wrong with a timeseries request.
+func validateTimeseries(params url.Values) []string {
+	var problems []string
+	for _, name := range []string{"producer", "param", "format"} {
+		if params.Get(name) == "" {
+			problems = append(problems, "no "+name)
+		}
+	}
+	return append(problems, validateCommon(params, "timestep", "timesteps")...)
+}
+
+// validateCommon checks the location, time and step parameters both APIs
+// share; steps are the names of the positive integer parameters. latlon
+// may be repeated, for a forecast at several points.
+func validateCommon(params url.Values, steps ...string) []string {
+	var problems []string
+	for _, v := range params["latlon"] {
+		if !latlonRE.MatchString(v) {
+			problems = append(problems, fmt.Sprintf("latlon %q is not lat,lon", v))
+		}
+	}
+	if v, ok := params["bbox"]; ok && (len(v) != 1 || !bboxRE.MatchString(v[0])) {
+		problems = append(problems, fmt.Sprintf("bbox %q is not minlon,minlat,maxlon,maxlat", v))
+	}
+	for _, name := range steps {
+		if v, ok := params[name]; ok {
+			if n, err := strconv.Atoi(params.Get(name)); err != nil || n < 1 || len(v) != 1 {
+				problems = append(problems, fmt.Sprintf("%s %q is not a positive integer", name, v))
+			}
+		}
+	}
+	for _, name := range []string{"starttime", "endtime"} {
+		if v, ok := params[name]; ok {
+			if _, err := time.Parse(time.RFC3339, params.Get(name)); err != nil || len(v) != 1 {
+				problems = append(problems, fmt.Sprintf("%s %q is not an RFC 3339 time", name, v))
+			}
+		}
+	}
+	return problems
+}
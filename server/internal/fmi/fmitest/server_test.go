@@ -0,0 +1,39 @@
+package fmitest
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	wfs := func(extra string) url.Values {
+		v, err := url.ParseQuery("service=WFS&version=2.0.0&request=getFeature&storedquery_id=q" + extra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	tests := []struct {
+		name     string
+		problems []string
+		want     int
+	}{
+		{"valid point query", validateWFS(wfs("&latlon=60.170000,24.940000&timestep=60&starttime=2026-02-16T08:00:00Z")), 0},
+		{"valid bbox query", validateWFS(wfs("&bbox=19,59,32,71&timestep=10")), 0},
+		{"space separated latlon", validateWFS(wfs("&latlon=60.17+24.94")), 1},
+		{"several points", validateWFS(wfs("&latlon=60.17,24.94&latlon=61.5,23.76")), 0},
+		{"one bad point of several", validateWFS(wfs("&latlon=60.17,24.94&latlon=61.5")), 1},
+		{"timestep with a unit", validateWFS(wfs("&timestep=1h")), 1},
+		{"local time", validateWFS(wfs("&starttime=2026-02-16T08:00:00")), 1},
+		{"no stored query", validateWFS(url.Values{"service": {"WFS"}, "version": {"2.0.0"}, "request": {"getFeature"}}), 1},
+		{"WFS 1.1", validateWFS(url.Values{"service": {"WFS"}, "version": {"1.1.0"}, "request": {"getFeature"}, "storedquery_id": {"q"}}), 1},
+		{"valid timeseries", validateTimeseries(url.Values{"producer": {"uv"}, "param": {"uvCumulated"}, "format": {"json"}, "timesteps": {"30"}}), 0},
+		{"timeseries without format", validateTimeseries(url.Values{"producer": {"uv"}, "param": {"uvCumulated"}}), 1},
+		{"zero timesteps", validateTimeseries(url.Values{"producer": {"uv"}, "param": {"uvCumulated"}, "format": {"json"}, "timesteps": {"0"}}), 1},
+	}
+	for _, tt := range tests {
+		if len(tt.problems) != tt.want {
+			t.Errorf("%s: expected %d problems, got %q", tt.name, tt.want, tt.problems)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package fmi
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"wby/internal/weather"
+)
+
+// FetchLightning returns the lightning strikes located inside bbox since
+// the given time, oldest first.
+func (c *Client) FetchLightning(ctx context.Context, bbox weather.BBox, since time.Time) ([]weather.LightningStrike, error) {
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
+	params := url.Values{
+		"service":        {"WFS"},
+		"version":        {"2.0.0"},
+		"request":        {"getFeature"},
+		"storedquery_id": {"fmi::observations::lightning::simple"},
+		"bbox":           {fmt.Sprintf("%g,%g,%g,%g", bbox.MinLon, bbox.MinLat, bbox.MaxLon, bbox.MaxLat)},
+		"starttime":      {since.UTC().Format(time.RFC3339)},
+		"endtime":        {time.Now().UTC().Format(time.RFC3339)},
+	}
+
+	var strikes []weather.LightningStrike
+	err := c.fetchDecode(ctx, params, func(data []byte) (err error) {
+		strikes, err = ParseLightning(data)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch lightning: %w", err)
+	}
+	return strikes, nil
+}
+
+type bsWfsElement struct {
+	Pos   string `xml:"Location>Point>pos"`
+	Time  string `xml:"Time"`
+	Name  string `xml:"ParameterName"`
+	Value string `xml:"ParameterValue"`
+}
+
+// ParseLightning parses the simple-feature (BsWfs) lightning response, in
+// which every strike is repeated once per parameter, into strikes sorted by
+// time.
+func ParseLightning(data []byte) ([]weather.LightningStrike, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	index := map[string]int{}
+	var strikes []weather.LightningStrike
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse lightning: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "BsWfsElement" {
+			continue
+		}
+		var el bsWfsElement
+		if err := dec.DecodeElement(&el, &start); err != nil {
+			return nil, fmt.Errorf("parse lightning element: %w", err)
+		}
+
+		key := strings.TrimSpace(el.Time) + " " + strings.TrimSpace(el.Pos)
+		i, seen := index[key]
+		if !seen {
+			strike, err := newLightningStrike(el)
+			if err != nil {
+				return nil, err
+			}
+			i = len(strikes)
+			index[key] = i
+			strikes = append(strikes, strike)
+		}
+
+		v, err := strconv.ParseFloat(strings.TrimSpace(el.Value), 64)
+		if err != nil || math.IsNaN(v) {
+			continue
+		}
+		switch el.Name {
+		case "peak_current":
+			strikes[i].PeakCurrent = &v
+		case "multiplicity":
+			n := int(v)
+			strikes[i].Multiplicity = &n
+		}
+	}
+	slices.SortStableFunc(strikes, func(a, b weather.LightningStrike) int { return a.Time.Compare(b.Time) })
+	return strikes, nil
+}
+
+func newLightningStrike(el bsWfsElement) (weather.LightningStrike, error) {
+	t, lat, lon, err := el.point()
+	if err != nil {
+		return weather.LightningStrike{}, fmt.Errorf("strike: %w", err)
+	}
+	return weather.LightningStrike{Time: t, Lat: lat, Lon: lon}, nil
+}
+
+// point parses the element's time and position.
+func (el bsWfsElement) point() (time.Time, float64, float64, error) {
+	t, err := time.Parse(time.RFC3339, strings.TrimSpace(el.Time))
+	if err != nil {
+		return time.Time{}, 0, 0, fmt.Errorf("parse time %q: %w", el.Time, err)
+	}
+	fields := strings.Fields(el.Pos)
+	if len(fields) != 2 {
+		return time.Time{}, 0, 0, fmt.Errorf("invalid position %q", el.Pos)
+	}
+	lat, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return time.Time{}, 0, 0, fmt.Errorf("invalid position %q", el.Pos)
+	}
+	lon, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return time.Time{}, 0, 0, fmt.Errorf("invalid position %q", el.Pos)
+	}
+	return t.UTC(), lat, lon, nil
+}
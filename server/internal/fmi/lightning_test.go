@@ -0,0 +1,54 @@
+package fmi
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseLightning(t *testing.T) {
+	data, err := os.ReadFile("testdata/lightning.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strikes, err := ParseLightning(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strikes) != 2 {
+		t.Fatalf("expected 2 strikes, got %d", len(strikes))
+	}
+
+	// Sorted by time, so the later-listed strike comes first.
+	first, second := strikes[0], strikes[1]
+	if want := time.Date(2026, 7, 15, 12, 2, 41, 0, time.UTC); !first.Time.Equal(want) {
+		t.Errorf("first strike at %v, want %v", first.Time, want)
+	}
+	if first.PeakCurrent != nil {
+		t.Errorf("expected NaN peak current to be nil, got %v", *first.PeakCurrent)
+	}
+	if first.Multiplicity == nil || *first.Multiplicity != 1 {
+		t.Errorf("unexpected multiplicity %v", first.Multiplicity)
+	}
+
+	if second.Lat != 61.4981 || second.Lon != 23.7610 {
+		t.Errorf("unexpected position %f, %f", second.Lat, second.Lon)
+	}
+	if second.PeakCurrent == nil || *second.PeakCurrent != -14.2 {
+		t.Errorf("unexpected peak current %v", second.PeakCurrent)
+	}
+	if second.Multiplicity == nil || *second.Multiplicity != 2 {
+		t.Errorf("unexpected multiplicity %v", second.Multiplicity)
+	}
+}
+
+func TestParseLightning_Empty(t *testing.T) {
+	strikes, err := ParseLightning([]byte(`<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" numberReturned="0"/>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strikes) != 0 {
+		t.Fatalf("expected no strikes, got %d", len(strikes))
+	}
+}
@@ -0,0 +1,110 @@
+package fmi
+
+import (
+	"strconv"
+	"time"
+
+	"wby/internal/metrics"
+)
+
+// Metrics receives the CallStats of every FMI call the Client makes. It is
+// called synchronously, possibly from several goroutines at once.
+type Metrics interface {
+	ObserveCall(CallStats)
+}
+
+// CallStats describes one FMI call, retries included.
+type CallStats struct {
+	// Query is the stored query, or the timeseries, WMS or CAP request,
+	// that was called.
+	Query string
+	// Duration is the whole call, from the first attempt to the end of the
+	// last, retry delays included.
+	Duration time.Duration
+	// Status is the HTTP status of the last attempt; 0 when no response
+	// arrived.
+	Status int
+	// Bytes is the size of the last attempt's response body after
+	// decompression, as far as it was read.
+	Bytes int64
+	// Retries is how many attempts were made after the first.
+	Retries int
+	// ParseDuration is the time spent decoding the last response, not
+	// counting waits for the network; zero when no body was decoded.
+	ParseDuration time.Duration
+	// Err is the call's error, nil on success.
+	Err error
+}
+
+// SetMetrics records the duration, response status and size, retries and
+// parse time of every FMI call in reg, labelled by stored query (or
+// "timeseries::uv", "cap::warnings" and "wms::radar::*" for the UV,
+// warnings and radar endpoints), along with the circuit breaker's state
+// changes. See SetMetricsSink for recording calls elsewhere.
+func (c *Client) SetMetrics(reg *metrics.Registry) {
+	c.metrics = newRegistryMetrics(reg)
+	c.breaker.transitions = reg.Counter("wby_fmi_circuit_transitions_total", "FMI circuit breaker state changes, by the state entered.", "state")
+}
+
+// SetMetricsSink sends the CallStats of every call to m; nil stops
+// recording them.
+func (c *Client) SetMetricsSink(m Metrics) {
+	c.metrics = m
+}
+
+// observeFetch completes stats for a call that started at start and ended
+// with err, and records it.
+func (c *Client) observeFetch(stats CallStats, start time.Time, err error) {
+	if c.metrics == nil {
+		return
+	}
+	stats.Duration = time.Since(start)
+	stats.Err = err
+	c.metrics.ObserveCall(stats)
+}
+
+// responseSizeBuckets are the wby_fmi_response_bytes buckets, from a small
+// point query to a country-wide observation bbox.
+var responseSizeBuckets = []float64{1 << 10, 8 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20, 16 << 20, 64 << 20}
+
+// registryMetrics records calls in a metrics.Registry.
+type registryMetrics struct {
+	duration  *metrics.HistogramVec
+	errors    *metrics.CounterVec
+	responses *metrics.CounterVec
+	size      *metrics.HistogramVec
+	retries   *metrics.CounterVec
+	parse     *metrics.HistogramVec
+}
+
+func newRegistryMetrics(reg *metrics.Registry) *registryMetrics {
+	return &registryMetrics{
+		duration:  reg.Histogram("wby_fmi_fetch_duration_seconds", "Duration of FMI requests.", metrics.DefaultBuckets, "query", "result"),
+		errors:    reg.Counter("wby_fmi_fetch_errors_total", "Failed FMI requests.", "query"),
+		responses: reg.Counter("wby_fmi_responses_total", "FMI requests by the HTTP status of their last attempt, \"none\" when no response arrived.", "query", "status"),
+		size:      reg.Histogram("wby_fmi_response_bytes", "Size of FMI response bodies after decompression.", responseSizeBuckets, "query"),
+		retries:   reg.Counter("wby_fmi_fetch_retries_total", "FMI request attempts after the first.", "query"),
+		parse:     reg.Histogram("wby_fmi_parse_duration_seconds", "Time spent decoding FMI responses, not counting network waits.", metrics.DefaultBuckets, "query"),
+	}
+}
+
+func (m *registryMetrics) ObserveCall(s CallStats) {
+	result := "ok"
+	if s.Err != nil {
+		result = "error"
+		m.errors.Inc(s.Query)
+	}
+	m.duration.Observe(s.Duration.Seconds(), s.Query, result)
+	status := "none"
+	if s.Status != 0 {
+		status = strconv.Itoa(s.Status)
+		m.size.Observe(float64(s.Bytes), s.Query)
+	}
+	m.responses.Inc(s.Query, status)
+	if s.Retries > 0 {
+		m.retries.Add(float64(s.Retries), s.Query)
+	}
+	if s.ParseDuration > 0 {
+		m.parse.Observe(s.ParseDuration.Seconds(), s.Query)
+	}
+}
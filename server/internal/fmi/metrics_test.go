@@ -0,0 +1,118 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"wby/internal/metrics"
+)
+
+// recordingMetrics is a Metrics sink that keeps every call.
+type recordingMetrics struct {
+	mu    sync.Mutex
+	calls []CallStats
+}
+
+func (m *recordingMetrics) ObserveCall(s CallStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, s)
+}
+
+func TestClient_ReportsCallStats(t *testing.T) {
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(observations)
+	}))
+	defer srv.Close()
+
+	sink := &recordingMetrics{}
+	c := NewClient(srv.URL, "", "")
+	c.SetMetricsSink(sink)
+	c.SetRetry(3, time.Millisecond)
+
+	if _, err := c.FetchObservations(context.Background()); err != nil {
+		t.Fatalf("fetch observations: %v", err)
+	}
+	fail = true
+	if _, err := c.FetchObservations(context.Background()); err == nil {
+		t.Fatal("expected error from failing upstream")
+	}
+
+	if len(sink.calls) != 2 {
+		t.Fatalf("expected 2 calls, got %+v", sink.calls)
+	}
+	const query = "fmi::observations::weather::timevaluepair"
+	ok, failed := sink.calls[0], sink.calls[1]
+	if ok.Query != query || ok.Status != http.StatusOK || ok.Bytes != int64(len(observations)) || ok.Retries != 0 || ok.Err != nil {
+		t.Errorf("unexpected stats for the successful call: %+v", ok)
+	}
+	if ok.ParseDuration <= 0 || ok.Duration < ok.ParseDuration {
+		t.Errorf("expected a parse duration within the call's %s, got %s", ok.Duration, ok.ParseDuration)
+	}
+	if failed.Query != query || failed.Status != http.StatusServiceUnavailable || failed.Retries != 2 || failed.ParseDuration != 0 {
+		t.Errorf("unexpected stats for the failed call: %+v", failed)
+	}
+	if !errors.Is(failed.Err, ErrUpstreamUnavailable) {
+		t.Errorf("expected the failed call's error to be an outage, got %v", failed.Err)
+	}
+}
+
+func TestClient_ReportsParseFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0"><wfs:member>`))
+	}))
+	defer srv.Close()
+
+	sink := &recordingMetrics{}
+	c := NewClient(srv.URL, "", "")
+	c.SetMetricsSink(sink)
+	if _, err := c.FetchForecast(context.Background(), 60.17, 24.94, 3); !errors.Is(err, ErrParse) {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+	if len(sink.calls) != 1 || !errors.Is(sink.calls[0].Err, ErrParse) || sink.calls[0].Status != http.StatusOK {
+		t.Fatalf("expected the parse failure to be the call's, got %+v", sink.calls)
+	}
+}
+
+func TestRegistryMetrics_RecordsCalls(t *testing.T) {
+	reg := metrics.NewRegistry()
+	m := newRegistryMetrics(reg)
+	m.ObserveCall(CallStats{Query: "q", Duration: time.Second, Status: http.StatusOK, Bytes: 2048, ParseDuration: time.Millisecond})
+	m.ObserveCall(CallStats{Query: "q", Duration: time.Second, Status: http.StatusServiceUnavailable, Retries: 2, Err: ErrUpstreamUnavailable})
+	m.ObserveCall(CallStats{Query: "q", Duration: time.Second, Err: ErrUpstreamUnavailable})
+
+	for _, tc := range []struct {
+		name   string
+		labels []string
+		want   float64
+	}{
+		{"wby_fmi_fetch_duration_seconds", []string{"q", "ok"}, 1},
+		{"wby_fmi_fetch_duration_seconds", []string{"q", "error"}, 2},
+		{"wby_fmi_fetch_errors_total", []string{"q"}, 2},
+		{"wby_fmi_responses_total", []string{"q", "200"}, 1},
+		{"wby_fmi_responses_total", []string{"q", "503"}, 1},
+		{"wby_fmi_responses_total", []string{"q", "none"}, 1},
+		{"wby_fmi_response_bytes", []string{"q"}, 2},
+		{"wby_fmi_fetch_retries_total", []string{"q"}, 2},
+		{"wby_fmi_parse_duration_seconds", []string{"q"}, 1},
+	} {
+		if got := reg.Value(tc.name, tc.labels...); got != tc.want {
+			t.Errorf("%s%v = %v, want %v", tc.name, tc.labels, got, tc.want)
+		}
+	}
+}
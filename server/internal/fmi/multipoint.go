@@ -0,0 +1,118 @@
+package fmi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gridSeries is the omso:GridSeriesObservation of a multipointcoverage
+// response: every station and parameter in one member, with station
+// metadata listed once instead of once per parameter.
+type gridSeries struct {
+	FeatureOfInterest featureOfInterest `xml:"featureOfInterest"`
+	Result            coverageResult    `xml:"result"`
+}
+
+type coverageResult struct {
+	Coverage multiPointCoverage `xml:"MultiPointCoverage"`
+}
+
+type multiPointCoverage struct {
+	// Positions lists "lat lon epochseconds" for each row of Tuples.
+	Positions string `xml:"domainSet>SimpleMultiPoint>positions"`
+	// Tuples holds one row of values per position, one column per
+	// requested parameter.
+	Tuples string `xml:"rangeSet>DataBlock>doubleOrNilReasonTupleList"`
+}
+
+// ParseObservationsMultipoint parses an FMI multipointcoverage observation
+// response. params names the tuple columns in the order they were
+// requested.
+func ParseObservationsMultipoint(data []byte, params []string) (*ObservationResult, error) {
+	return ParseObservationsMultipointReader(bytes.NewReader(data), params)
+}
+
+// ParseObservationsMultipointReader is ParseObservationsMultipoint reading
+// from r.
+func ParseObservationsMultipointReader(r io.Reader, params []string) (*ObservationResult, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("parse multipointcoverage: no parameters")
+	}
+	columns := make([]string, len(params))
+	for i, p := range params {
+		columns[i] = strings.ToLower(p)
+	}
+
+	b := newObservationBuilder()
+	var parseErr error
+	err := decodeMembers(r, func(m member) {
+		if parseErr == nil {
+			parseErr = addGridSeries(b, m.Grid, columns)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return b.result(), nil
+}
+
+// addGridSeries folds one GridSeriesObservation into b. Rows are matched
+// to stations by position, through each location's representative point.
+func addGridSeries(b *observationBuilder, g gridSeries, columns []string) error {
+	foi := g.FeatureOfInterest.Feature
+	points := make(map[string]gmlPoint)
+	for _, pt := range append(foi.Shape.MultiPoint.PointMember, foi.Shape.MultiPoint.Points...) {
+		points[pt.ID] = pt
+	}
+
+	stationsAt := make(map[string]int)
+	for _, lm := range foi.SampledFeature.LocationCollection.Members {
+		pt, ok := points[strings.TrimPrefix(lm.Location.RepresentativePoint.Href, "#")]
+		if !ok {
+			continue
+		}
+		feature := spatialFeature{Shape: shape{Point: pt}}
+		feature.SampledFeature.LocationCollection.Members = []locationMember{lm}
+		station := stationInfo(feature)
+		b.addStation(station)
+		stationsAt[positionKey(strings.Fields(pt.Pos))] = station.FMISID
+	}
+
+	positions := strings.Fields(g.Result.Coverage.Positions)
+	values := strings.Fields(g.Result.Coverage.Tuples)
+	if len(positions)%3 != 0 || len(values)%len(columns) != 0 || len(positions)/3 != len(values)/len(columns) {
+		return fmt.Errorf("parse multipointcoverage: %d positions do not match %d values of %d parameters", len(positions)/3, len(values), len(columns))
+	}
+	for row := range len(positions) / 3 {
+		pos := positions[row*3 : row*3+3]
+		fmisid, ok := stationsAt[positionKey(pos)]
+		if !ok {
+			continue
+		}
+		epoch, err := strconv.ParseInt(pos[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		t := time.Unix(epoch, 0).UTC()
+		for i, param := range columns {
+			b.set(fmisid, t, param, parseFloat(values[row*len(columns)+i]))
+		}
+	}
+	return nil
+}
+
+// positionKey identifies a station by the lat and lon fields of a gml:pos
+// or positions row, as FMI prints both with the same precision.
+func positionKey(fields []string) string {
+	if len(fields) < 2 {
+		return ""
+	}
+	return fields[0] + " " + fields[1]
+}
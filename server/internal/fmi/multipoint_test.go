@@ -0,0 +1,141 @@
+package fmi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"wby/internal/weather"
+)
+
+func TestParseObservationsMultipoint_MatchesTimeValuePair(t *testing.T) {
+	tvp, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mpc, err := os.ReadFile("testdata/observations_multipointcoverage.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := ParseObservations(tvp)
+	if err != nil {
+		t.Fatalf("parse timevaluepair: %v", err)
+	}
+	got, err := ParseObservationsMultipoint(mpc, observationParameters)
+	if err != nil {
+		t.Fatalf("parse multipointcoverage: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Stations, want.Stations) {
+		t.Errorf("stations differ:\n got %+v\nwant %+v", got.Stations, want.Stations)
+	}
+	if len(got.Observations) != len(want.Observations) || len(got.Observations) == 0 {
+		t.Fatalf("expected %d observations, got %d", len(want.Observations), len(got.Observations))
+	}
+	for i := range want.Observations {
+		if !reflect.DeepEqual(got.Observations[i], want.Observations[i]) {
+			t.Fatalf("observation %d differs:\n got %+v\nwant %+v", i, got.Observations[i], want.Observations[i])
+		}
+	}
+}
+
+const twoStationCoverage = `<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" xmlns:omso="http://inspire.ec.europa.eu/schemas/omso/3.0" xmlns:om="http://www.opengis.net/om/2.0" xmlns:sams="http://www.opengis.net/samplingSpatial/2.0" xmlns:sam="http://www.opengis.net/sampling/2.0" xmlns:target="http://xml.fmi.fi/namespace/om/atmosphericfeatures/1.1" xmlns:gml="http://www.opengis.net/gml/3.2" xmlns:gmlcov="http://www.opengis.net/gmlcov/1.0" xmlns:xlink="http://www.w3.org/1999/xlink">
+<wfs:member><omso:GridSeriesObservation>
+<om:featureOfInterest><sams:SF_SpatialSamplingFeature>
+<sam:sampledFeature><target:LocationCollection>
+<target:member><target:Location>
+<gml:identifier>100971</gml:identifier>
+<gml:name codeSpace="http://xml.fmi.fi/namespace/locationcode/name">Helsinki Kaisaniemi</gml:name>
+<target:representativePoint xlink:href="#point-100971"/>
+</target:Location></target:member>
+<target:member><target:Location>
+<gml:identifier>101004</gml:identifier>
+<gml:name codeSpace="http://xml.fmi.fi/namespace/locationcode/name">Helsinki Kumpula</gml:name>
+<target:representativePoint xlink:href="#point-101004"/>
+</target:Location></target:member>
+</target:LocationCollection></sam:sampledFeature>
+<sams:shape><gml:MultiPoint>
+<gml:pointMember><gml:Point gml:id="point-100971"><gml:pos>60.17523 24.94459 </gml:pos></gml:Point></gml:pointMember>
+<gml:pointMember><gml:Point gml:id="point-101004"><gml:pos>60.20307 24.96131 </gml:pos></gml:Point></gml:pointMember>
+</gml:MultiPoint></sams:shape>
+</sams:SF_SpatialSamplingFeature></om:featureOfInterest>
+<om:result><gmlcov:MultiPointCoverage>
+<gml:domainSet><gmlcov:SimpleMultiPoint><gmlcov:positions>
+60.17523 24.94459 1771221000
+60.17523 24.94459 1771221600
+60.20307 24.96131 1771221000
+60.20307 24.96131 1771221600
+</gmlcov:positions></gmlcov:SimpleMultiPoint></gml:domainSet>
+<gml:rangeSet><gml:DataBlock><gml:doubleOrNilReasonTupleList>
+-8.1 3.2
+-8.3 NaN
+-7.9 4.0
+NaN NaN
+</gml:doubleOrNilReasonTupleList></gml:DataBlock></gml:rangeSet>
+</gmlcov:MultiPointCoverage></om:result>
+</omso:GridSeriesObservation></wfs:member>
+</wfs:FeatureCollection>`
+
+func TestParseObservationsMultipoint_MatchesRowsToStations(t *testing.T) {
+	result, err := ParseObservationsMultipoint([]byte(twoStationCoverage), []string{"t2m", "ws_10min"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Stations) != 2 || result.Stations[0].Name != "Helsinki Kaisaniemi" || result.Stations[1].Lat != 60.20307 {
+		t.Fatalf("unexpected stations %+v", result.Stations)
+	}
+	// The all-NaN row has no values and is dropped.
+	if len(result.Observations) != 3 {
+		t.Fatalf("expected 3 observations, got %d", len(result.Observations))
+	}
+	i := slices.IndexFunc(result.Observations, func(o weather.Observation) bool {
+		return o.FMISID == 101004
+	})
+	kumpula := result.Observations[i]
+	if kumpula.Temperature == nil || *kumpula.Temperature != -7.9 || kumpula.WindSpeed == nil || *kumpula.WindSpeed != 4.0 {
+		t.Errorf("unexpected Kumpula observation %+v", kumpula)
+	}
+}
+
+func TestParseObservationsMultipoint_RejectsMismatchedColumns(t *testing.T) {
+	if _, err := ParseObservationsMultipoint([]byte(twoStationCoverage), []string{"t2m", "ws_10min", "rh"}); err == nil {
+		t.Fatal("expected error when the values don't fill the requested columns")
+	}
+}
+
+func TestClient_FetchesMultipointCoverage(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/observations_multipointcoverage.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("storedquery_id") != "fmi::observations::weather::multipointcoverage" || q.Get("parameters") != strings.Join(observationParameters, ",") {
+			http.Error(w, "unexpected query", http.StatusBadRequest)
+			return
+		}
+		w.Write(fixture)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	if err := c.SetObservationFormat(FormatMultiPointCoverage); err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.FetchObservations(context.Background())
+	if err != nil {
+		t.Fatalf("fetch observations: %v", err)
+	}
+	if len(result.Stations) != 1 || len(result.Observations) == 0 {
+		t.Errorf("expected observations for one station, got %+v", result)
+	}
+	if err := c.SetObservationFormat("simple"); err == nil {
+		t.Error("expected error for an unknown format")
+	}
+}
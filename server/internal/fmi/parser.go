@@ -1,8 +1,11 @@
 package fmi
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io"
+	"log/slog"
 	"math"
 	"slices"
 	"strconv"
@@ -34,6 +37,7 @@ type featureCollection struct {
 
 type member struct {
 	Observation pointTimeSeries `xml:"PointTimeSeriesObservation"`
+	Grid        gridSeries      `xml:"GridSeriesObservation"`
 }
 
 type pointTimeSeries struct {
@@ -68,9 +72,17 @@ type locationMember struct {
 }
 
 type location struct {
-	Identifier string    `xml:"identifier"`
-	Names      []gmlName `xml:"name"`
-	Timezone   string    `xml:"timezone"`
+	Identifier          string    `xml:"identifier"`
+	Names               []gmlName `xml:"name"`
+	Regions             []gmlName `xml:"region"`
+	Timezone            string    `xml:"timezone"`
+	Elevation           string    `xml:"elevation"`
+	StationGroups       []string  `xml:"stationGroup"`
+	RepresentativePoint xlinkRef  `xml:"representativePoint"`
+}
+
+type xlinkRef struct {
+	Href string `xml:"http://www.w3.org/1999/xlink href,attr"`
 }
 
 type gmlName struct {
@@ -84,12 +96,16 @@ type shape struct {
 }
 
 type gmlPoint struct {
+	ID   string `xml:"http://www.opengis.net/gml/3.2 id,attr"`
 	Name string `xml:"name"`
 	Pos  string `xml:"pos"`
 }
 
 type multiPoint struct {
 	Points []gmlPoint `xml:"pointMembers>Point"`
+	// PointMember holds the points of multipointcoverage responses, which
+	// wrap each one in its own gml:pointMember.
+	PointMember []gmlPoint `xml:"pointMember>Point"`
 }
 
 type tsResult struct {
@@ -117,13 +133,271 @@ type ObservationResult struct {
 
 // ParseObservations parses an FMI WFS observation response.
 func ParseObservations(data []byte) (*ObservationResult, error) {
+	return ParseObservationsReader(bytes.NewReader(data))
+}
+
+// ParseObservationsReader parses an FMI WFS observation response from r.
+// Members are decoded one at a time and folded into the result, so a
+// bbox-wide response never sits in memory as a whole document tree.
+func ParseObservationsReader(r io.Reader) (*ObservationResult, error) {
+	b := newObservationBuilder()
+	err := decodeMembers(r, func(m member) {
+		param := strings.ToLower(extractParam(m.Observation.ObservedProperty.Href))
+		station := extractStationInfo(m.Observation)
+		b.addStation(station)
+
+		for _, pt := range m.Observation.Result.TimeSeries.Points {
+			t, err := time.Parse(time.RFC3339, pt.TVP.Time)
+			if err != nil {
+				continue
+			}
+			b.set(station.FMISID, t, param, parseFloat(pt.TVP.Value))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.result(), nil
+}
+
+// observationBuilder folds parameter values from either response format
+// into one observation per station and time.
+type observationBuilder struct {
+	stations     map[int]*weather.Station
+	observations map[observationKey]*weather.Observation
+}
+
+type observationKey struct {
+	fmisid int
+	t      time.Time
+}
+
+func newObservationBuilder() *observationBuilder {
+	return &observationBuilder{
+		stations:     make(map[int]*weather.Station),
+		observations: make(map[observationKey]*weather.Observation),
+	}
+}
+
+// addStation records s unless its FMISID was already seen.
+func (b *observationBuilder) addStation(s weather.Station) {
+	if _, ok := b.stations[s.FMISID]; !ok {
+		b.stations[s.FMISID] = &s
+	}
+}
+
+// set stores val as param, lowercased, of the observation at fmisid and t.
+// A parameter repeated in another member keeps the last value sent; a
+// missing value does not erase one already set.
+func (b *observationBuilder) set(fmisid int, t time.Time, param string, val *float64) {
+	t = t.UTC()
+	key := observationKey{fmisid: fmisid, t: t}
+	obs, ok := b.observations[key]
+	if !ok {
+		obs = &weather.Observation{FMISID: fmisid, ObservedAt: t}
+		b.observations[key] = obs
+	}
+	if val == nil {
+		return
+	}
+
+	switch param {
+	case "temperature", "t2m":
+		obs.Temperature = val
+	case "windspeedms", "ws_10min":
+		obs.WindSpeed = val
+	case "windgust", "gustspeed", "maximumwind", "wg_10min":
+		obs.WindGust = val
+	case "winddirection", "wd_10min":
+		obs.WindDir = val
+	case "humidity", "rh":
+		obs.Humidity = val
+	case "dewpoint", "td":
+		obs.DewPoint = val
+	case "pressure", "p_sea":
+		obs.Pressure = val
+	case "precipitation1h", "precipitationamount", "r_1h":
+		obs.Precip1h = val
+	case "precipitationintensity", "ri_10min":
+		obs.PrecipIntensity = val
+	case "snowdepth", "snow_aws":
+		obs.SnowDepth = val
+	case "visibility", "vis":
+		obs.Visibility = val
+	case "totalcloudcover", "cloudcover", "n_man":
+		obs.TotalCloudCover = val
+	case "weather", "weathercode", "wawa":
+		obs.WeatherCode = val
+	default:
+		if obs.ExtraNumericParams == nil {
+			obs.ExtraNumericParams = make(map[string]float64)
+		}
+		obs.ExtraNumericParams[param] = *val
+	}
+}
+
+// result returns the stations and the observations with at least one
+// value, sorted for deterministic output: stations by FMISID,
+// observations by time.
+func (b *observationBuilder) result() *ObservationResult {
+	result := &ObservationResult{}
+	if len(b.stations) == 0 {
+		return result
+	}
+	for _, s := range b.stations {
+		result.Stations = append(result.Stations, *s)
+	}
+	for _, o := range b.observations {
+		if !hasAnyValue(o) {
+			continue
+		}
+		result.Observations = append(result.Observations, *o)
+	}
+
+	slices.SortFunc(result.Stations, func(a, b weather.Station) int {
+		return a.FMISID - b.FMISID
+	})
+	slices.SortFunc(result.Observations, func(a, b weather.Observation) int {
+		return a.ObservedAt.Compare(b.ObservedAt)
+	})
+	return result
+}
+
+// decodeMembers streams the wfs:member elements of a FeatureCollection
+// from r to fn, holding only one member in memory at a time.
+func decodeMembers(r io.Reader, fn func(member)) error {
+	dec := xml.NewDecoder(r)
+	root := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			if !root {
+				return fmt.Errorf("unmarshal WFS: %w", io.EOF)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unmarshal WFS: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !root {
+			if start.Name.Local != "FeatureCollection" {
+				return fmt.Errorf("unmarshal WFS: expected element type <FeatureCollection> but have <%s>", start.Name.Local)
+			}
+			root = true
+			continue
+		}
+		if start.Name.Local != "member" {
+			continue
+		}
+		var m member
+		if err := dec.DecodeElement(&m, &start); err != nil {
+			return fmt.Errorf("unmarshal WFS member: %w", err)
+		}
+		fn(m)
+	}
+}
+
+// AirQualityResult holds parsed air quality data from FMI.
+type AirQualityResult struct {
+	Stations     []weather.Station
+	Measurements []weather.AirQuality
+}
+
+// ParseAirQuality parses an FMI WFS urban air quality response. It reads
+// the same time-value-pair documents as ParseObservations, with pollutant
+// parameters instead of weather ones.
+func ParseAirQuality(data []byte) (*AirQualityResult, error) {
 	var fc featureCollection
 	if err := xml.Unmarshal(data, &fc); err != nil {
-		return nil, fmt.Errorf("unmarshal WFS: %w", err)
+		return nil, fmt.Errorf("unmarshal WFS air quality: %w", err)
 	}
 
-	if len(fc.Members) == 0 {
-		return &ObservationResult{}, nil
+	stationMap := make(map[int]*weather.Station)
+	type aqKey struct {
+		fmisid int
+		t      time.Time
+	}
+	aqMap := make(map[aqKey]*weather.AirQuality)
+
+	for _, m := range fc.Members {
+		param := strings.ToLower(extractParam(m.Observation.ObservedProperty.Href))
+		station := extractStationInfo(m.Observation)
+		fmisid := station.FMISID
+
+		if _, ok := stationMap[fmisid]; !ok {
+			stationMap[fmisid] = &station
+		}
+
+		for _, pt := range m.Observation.Result.TimeSeries.Points {
+			t, err := time.Parse(time.RFC3339, pt.TVP.Time)
+			if err != nil {
+				continue
+			}
+			val := parseFloat(pt.TVP.Value)
+			if val == nil {
+				continue
+			}
+
+			key := aqKey{fmisid: fmisid, t: t}
+			aq, ok := aqMap[key]
+			if !ok {
+				aq = &weather.AirQuality{FMISID: fmisid, ObservedAt: t}
+				aqMap[key] = aq
+			}
+
+			switch param {
+			case "pm25_pt1h_avg", "pm25":
+				aq.PM25 = val
+			case "pm10_pt1h_avg", "pm10":
+				aq.PM10 = val
+			case "o3_pt1h_avg", "o3":
+				aq.O3 = val
+			case "no2_pt1h_avg", "no2":
+				aq.NO2 = val
+			}
+		}
+	}
+
+	result := &AirQualityResult{}
+	for _, s := range stationMap {
+		result.Stations = append(result.Stations, *s)
+	}
+	for _, aq := range aqMap {
+		if aq.PM25 == nil && aq.PM10 == nil && aq.O3 == nil && aq.NO2 == nil {
+			continue
+		}
+		result.Measurements = append(result.Measurements, *aq)
+	}
+
+	slices.SortFunc(result.Stations, func(a, b weather.Station) int {
+		return a.FMISID - b.FMISID
+	})
+	slices.SortFunc(result.Measurements, func(a, b weather.AirQuality) int {
+		if c := a.ObservedAt.Compare(b.ObservedAt); c != 0 {
+			return c
+		}
+		return a.FMISID - b.FMISID
+	})
+
+	return result, nil
+}
+
+// MarineResult holds parsed wave buoy and mareograph data from FMI.
+type MarineResult struct {
+	Stations     []weather.Station
+	Observations []weather.MarineObservation
+}
+
+// ParseMarine parses an FMI WFS wave or mareograph observation response.
+// Both use the time-value-pair layout of ParseObservations.
+func ParseMarine(data []byte) (*MarineResult, error) {
+	var fc featureCollection
+	if err := xml.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("unmarshal WFS marine observations: %w", err)
 	}
 
 	stationMap := make(map[int]*weather.Station)
@@ -131,21 +405,103 @@ func ParseObservations(data []byte) (*ObservationResult, error) {
 		fmisid int
 		t      time.Time
 	}
-	obsMap := make(map[obsKey]*weather.Observation)
+	obsMap := make(map[obsKey]*weather.MarineObservation)
 
 	for _, m := range fc.Members {
 		param := strings.ToLower(extractParam(m.Observation.ObservedProperty.Href))
-		fmisid, name, lat, lon, wmo := extractStationInfo(m.Observation)
+		station := extractStationInfo(m.Observation)
+		fmisid := station.FMISID
 
 		if _, ok := stationMap[fmisid]; !ok {
-			stationMap[fmisid] = &weather.Station{
-				FMISID:  fmisid,
-				Name:    name,
-				Lat:     lat,
-				Lon:     lon,
-				WMOCode: wmo,
+			stationMap[fmisid] = &station
+		}
+
+		for _, pt := range m.Observation.Result.TimeSeries.Points {
+			t, err := time.Parse(time.RFC3339, pt.TVP.Time)
+			if err != nil {
+				continue
+			}
+			val := parseFloat(pt.TVP.Value)
+			if val == nil {
+				continue
+			}
+
+			key := obsKey{fmisid: fmisid, t: t}
+			obs, ok := obsMap[key]
+			if !ok {
+				obs = &weather.MarineObservation{FMISID: fmisid, ObservedAt: t}
+				obsMap[key] = obs
+			}
+
+			switch param {
+			case "wavehs":
+				obs.WaveHeight = val
+			case "modalwdi":
+				obs.WaveDirection = val
+			case "wtp":
+				obs.WavePeriod = val
+			case "twater":
+				obs.WaterTemperature = val
+			case "watlev":
+				obs.SeaLevel = val
 			}
 		}
+	}
+
+	result := &MarineResult{}
+	for _, s := range stationMap {
+		result.Stations = append(result.Stations, *s)
+	}
+	for _, obs := range obsMap {
+		if obs.WaveHeight == nil && obs.WaveDirection == nil && obs.WavePeriod == nil &&
+			obs.WaterTemperature == nil && obs.SeaLevel == nil {
+			continue
+		}
+		result.Observations = append(result.Observations, *obs)
+	}
+
+	slices.SortFunc(result.Stations, func(a, b weather.Station) int {
+		return a.FMISID - b.FMISID
+	})
+	slices.SortFunc(result.Observations, func(a, b weather.MarineObservation) int {
+		if c := a.ObservedAt.Compare(b.ObservedAt); c != 0 {
+			return c
+		}
+		return a.FMISID - b.FMISID
+	})
+
+	return result, nil
+}
+
+// RoadResult holds parsed road weather station data from FMI.
+type RoadResult struct {
+	Stations     []weather.Station
+	Observations []weather.RoadObservation
+}
+
+// ParseRoadObservations parses an FMI WFS road weather observation
+// response, which uses the time-value-pair layout of ParseObservations.
+func ParseRoadObservations(data []byte) (*RoadResult, error) {
+	var fc featureCollection
+	if err := xml.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("unmarshal WFS road observations: %w", err)
+	}
+
+	stationMap := make(map[int]*weather.Station)
+	type obsKey struct {
+		fmisid int
+		t      time.Time
+	}
+	obsMap := make(map[obsKey]*weather.RoadObservation)
+
+	for _, m := range fc.Members {
+		param := strings.ToLower(extractParam(m.Observation.ObservedProperty.Href))
+		station := extractStationInfo(m.Observation)
+		fmisid := station.FMISID
+
+		if _, ok := stationMap[fmisid]; !ok {
+			stationMap[fmisid] = &station
+		}
 
 		for _, pt := range m.Observation.Result.TimeSeries.Points {
 			t, err := time.Parse(time.RFC3339, pt.TVP.Time)
@@ -153,135 +509,322 @@ func ParseObservations(data []byte) (*ObservationResult, error) {
 				continue
 			}
 			val := parseFloat(pt.TVP.Value)
+			if val == nil {
+				continue
+			}
 
 			key := obsKey{fmisid: fmisid, t: t}
 			obs, ok := obsMap[key]
 			if !ok {
-				obs = &weather.Observation{FMISID: fmisid, ObservedAt: t}
+				obs = &weather.RoadObservation{FMISID: fmisid, ObservedAt: t}
 				obsMap[key] = obs
 			}
 
 			switch param {
-			case "temperature", "t2m":
-				obs.Temperature = val
-			case "windspeedms", "ws_10min":
-				obs.WindSpeed = val
-			case "windgust", "gustspeed", "maximumwind", "wg_10min":
-				obs.WindGust = val
-			case "winddirection", "wd_10min":
-				obs.WindDir = val
-			case "humidity", "rh":
-				obs.Humidity = val
-			case "dewpoint", "td":
-				obs.DewPoint = val
-			case "pressure", "p_sea":
-				obs.Pressure = val
-			case "precipitation1h", "precipitationamount", "r_1h":
-				obs.Precip1h = val
-			case "precipitationintensity", "ri_10min":
-				obs.PrecipIntensity = val
-			case "snowdepth", "snow_aws":
-				obs.SnowDepth = val
-			case "visibility", "vis":
-				obs.Visibility = val
-			case "totalcloudcover", "cloudcover", "n_man":
-				obs.TotalCloudCover = val
-			case "weather", "weathercode", "wawa":
-				obs.WeatherCode = val
-			default:
-				if val != nil {
-					if obs.ExtraNumericParams == nil {
-						obs.ExtraNumericParams = make(map[string]float64)
-					}
-					obs.ExtraNumericParams[param] = *val
-				}
+			case "troad1":
+				obs.RoadTemperature = val
+			case "ta":
+				obs.AirTemperature = val
+			case "rscond1":
+				code := int(*val)
+				obs.Condition = &code
 			}
 		}
 	}
 
-	result := &ObservationResult{}
+	result := &RoadResult{}
 	for _, s := range stationMap {
 		result.Stations = append(result.Stations, *s)
 	}
-	for _, o := range obsMap {
-		if !hasAnyValue(o) {
+	for _, obs := range obsMap {
+		if obs.RoadTemperature == nil && obs.AirTemperature == nil && obs.Condition == nil {
 			continue
 		}
-		result.Observations = append(result.Observations, *o)
+		result.Observations = append(result.Observations, *obs)
 	}
 
-	// Sort for deterministic output: stations by FMISID, observations by time.
 	slices.SortFunc(result.Stations, func(a, b weather.Station) int {
 		return a.FMISID - b.FMISID
 	})
-	slices.SortFunc(result.Observations, func(a, b weather.Observation) int {
-		return a.ObservedAt.Compare(b.ObservedAt)
+	slices.SortFunc(result.Observations, func(a, b weather.RoadObservation) int {
+		if c := a.ObservedAt.Compare(b.ObservedAt); c != 0 {
+			return c
+		}
+		return a.FMISID - b.FMISID
 	})
 
 	return result, nil
 }
 
+// dayStartHour and dayEndHour bound the local daytime used for a daily
+// forecast's day/night temperature split.
+const (
+	dayStartHour = 6
+	dayEndHour   = 18
+)
+
+// DefaultForecastMinCoverage is the share of a day's reported hours that
+// must carry a value for ParseForecast to aggregate a parameter.
+const DefaultForecastMinCoverage = 0.5
+
+// conditionalForecastParams are only defined while something happens, such
+// as the form of falling precipitation, so FMI's NaN for the other hours
+// is not missing data and does not count against coverage.
+var conditionalForecastParams = map[string]bool{
+	"precipitationform": true,
+	"precipitationtype": true,
+}
+
+// ForecastParseSummary counts the forecast values ParseForecast could not
+// use, for callers to log.
+type ForecastParseSummary struct {
+	// Missing counts values FMI sent as NaN.
+	Missing int
+	// Unparsable counts values and timestamps that were not valid at all.
+	Unparsable int
+	// UnknownParams lists the parameters no daily column uses, sorted.
+	UnknownParams []string
+	// Dropped counts daily aggregates left nil because fewer than the
+	// minimum coverage of their hours had a value, including days on which
+	// FMI sent only NaN for a parameter.
+	Dropped int
+	// Duplicates counts values for a parameter and time already seen in an
+	// earlier member; the last value sent is kept.
+	Duplicates int
+}
+
+// LogValue groups the summary's counts for slog.
+func (s ForecastParseSummary) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("missing", s.Missing),
+		slog.Int("unparsable", s.Unparsable),
+		slog.Any("unknown_params", s.UnknownParams),
+		slog.Int("dropped", s.Dropped),
+		slog.Int("duplicates", s.Duplicates),
+	)
+}
+
 // ParseForecast parses an FMI WFS forecast response and aggregates hourly
-// values into daily forecast columns.
-func ParseForecast(data []byte, gridLat, gridLon float64) (weather.ForecastData, error) {
+// values into daily forecast columns. A day's column is left nil when less
+// than minCoverage of the hours reported for it carry a value, rather than
+// aggregating whatever few hours are left. The hours themselves are
+// returned as well, as Hourly, so one response serves both.
+func ParseForecast(data []byte, gridLat, gridLon, minCoverage float64) (weather.ForecastData, ForecastParseSummary, error) {
+	var fc featureCollection
+	if err := xml.Unmarshal(data, &fc); err != nil {
+		return weather.ForecastData{}, ForecastParseSummary{}, fmt.Errorf("unmarshal WFS forecast: %w", err)
+	}
+	result, summary := forecastData(fc.Members, gridLat, gridLon, minCoverage)
+	return result, summary, nil
+}
+
+// LatLon is a point to fetch a forecast for.
+type LatLon = weather.LatLon
+
+// forecastPosTolerance is how far, in degrees, the position of a location
+// in a batch response may be from the point it answers. FMI echoes the
+// requested coordinates rounded to five decimals.
+const forecastPosTolerance = 0.001
+
+// ParseForecastBatch is ParseForecast for a response covering several
+// points, as FetchForecastBatch requests. Members are split by the
+// position in their featureOfInterest, each going to the requested point
+// it matches; the results and summaries are in the order of points, and a
+// point the response has no location for gets an empty ForecastData.
+// Members at other positions are ignored. With a single point every member
+// is that point's, as in ParseForecast.
+func ParseForecastBatch(data []byte, points []LatLon, minCoverage float64) ([]weather.ForecastData, []ForecastParseSummary, error) {
 	var fc featureCollection
 	if err := xml.Unmarshal(data, &fc); err != nil {
-		return weather.ForecastData{}, fmt.Errorf("unmarshal WFS forecast: %w", err)
+		return nil, nil, fmt.Errorf("unmarshal WFS forecast: %w", err)
+	}
+	byPoint := make([][]member, len(points))
+	for _, m := range fc.Members {
+		i := 0
+		if len(points) > 1 {
+			if i = matchPoint(points, m.Observation.FeatureOfInterest.Feature.Shape); i < 0 {
+				continue
+			}
+		}
+		byPoint[i] = append(byPoint[i], m)
+	}
+	results := make([]weather.ForecastData, len(points))
+	summaries := make([]ForecastParseSummary, len(points))
+	for i, members := range byPoint {
+		if len(members) > 0 {
+			results[i], summaries[i] = forecastData(members, points[i].Lat, points[i].Lon, minCoverage)
+		}
+	}
+	return results, summaries, nil
+}
+
+// matchPoint returns the index of the point within forecastPosTolerance
+// of s's position, or -1.
+func matchPoint(points []LatLon, s shape) int {
+	pos := s.Point.Pos
+	if len(s.MultiPoint.Points) > 0 {
+		pos = s.MultiPoint.Points[0].Pos
+	}
+	if strings.TrimSpace(pos) == "" {
+		return -1
 	}
+	lat, lon := parsePos(pos)
+	for i, p := range points {
+		if math.Abs(p.Lat-lat) <= forecastPosTolerance && math.Abs(p.Lon-lon) <= forecastPosTolerance {
+			return i
+		}
+	}
+	return -1
+}
 
+// forecastData aggregates the members of one location into daily
+// forecasts, as ParseForecast describes.
+func forecastData(members []member, gridLat, gridLon, minCoverage float64) (weather.ForecastData, ForecastParseSummary) {
+	var summary ForecastParseSummary
+
+	// val is nil for hours FMI reported as missing; they still count
+	// towards the day's coverage.
 	type hourlyEntry struct {
 		t   time.Time
-		val float64
+		val *float64
 	}
 	params := make(map[string][]hourlyEntry)
+	// seen indexes params by parameter and time, so a parameter FMI repeats
+	// in another member replaces its hours instead of counting them twice.
+	seen := make(map[string]map[int64]int)
 	var timezone string
 
-	for _, m := range fc.Members {
+	for _, m := range members {
 		if timezone == "" {
 			timezone = extractLocationTimezone(m.Observation)
 		}
-		param := strings.ToLower(extractParam(m.Observation.ObservedProperty.Href))
+		param := forecastParam(m.Observation.ObservedProperty.Href)
 		for _, pt := range m.Observation.Result.TimeSeries.Points {
 			t, err := time.Parse(time.RFC3339, pt.TVP.Time)
 			if err != nil {
+				summary.Unparsable++
 				continue
 			}
 			val := parseFloat(pt.TVP.Value)
-			if val == nil {
+			if val == nil && !isMissingValue(pt.TVP.Value) {
+				summary.Unparsable++
+				continue
+			}
+			if i, ok := seen[param][t.Unix()]; ok {
+				summary.Duplicates++
+				if val != nil {
+					params[param][i].val = val
+				}
 				continue
 			}
-			params[param] = append(params[param], hourlyEntry{t: t, val: *val})
+			if val == nil {
+				summary.Missing++
+			}
+			if seen[param] == nil {
+				seen[param] = make(map[int64]int)
+			}
+			seen[param][t.Unix()] = len(params[param])
+			params[param] = append(params[param], hourlyEntry{t: t, val: val})
 		}
 	}
 
 	type dayBucket struct {
 		values map[string][]float64
+		hours  map[string]int
+		times  []time.Time
 	}
 	days := make(map[string]*dayBucket)
 	dayOrder := []string{}
+	// Days are the point's local calendar days; in UTC a Finnish day would
+	// run 02:00-02:00 and highs and lows could land on the wrong date.
+	loc := weather.PlaceLocation(timezone)
+	localDate := func(t time.Time) string { return t.In(loc).Format("2006-01-02") }
 
-	addValue := func(dateKey, param string, value float64) {
+	addValue := func(dateKey, param string, value *float64) {
 		b, ok := days[dateKey]
 		if !ok {
-			b = &dayBucket{values: make(map[string][]float64)}
+			b = &dayBucket{values: make(map[string][]float64), hours: make(map[string]int)}
 			days[dateKey] = b
 			dayOrder = append(dayOrder, dateKey)
 		}
-		b.values[param] = append(b.values[param], value)
+		b.hours[param]++
+		if value != nil {
+			b.values[param] = append(b.values[param], *value)
+		}
 	}
 
 	for param, entries := range params {
 		for _, e := range entries {
-			addValue(e.t.Format("2006-01-02"), param, e.val)
+			addValue(localDate(e.t), param, e.val)
+		}
+	}
+	// Temperatures are also split into the local day (06:00-18:00) and the
+	// night hours either side of it, within the same calendar day.
+	for _, e := range params["temperature"] {
+		if h := e.t.In(loc).Hour(); h >= dayStartHour && h < dayEndHour {
+			addValue(localDate(e.t), "temperature:day", e.val)
+		} else {
+			addValue(localDate(e.t), "temperature:night", e.val)
+		}
+	}
+
+	byTime := func(param string) map[time.Time]float64 {
+		values := make(map[time.Time]float64)
+		for _, e := range params[param] {
+			if e.val != nil {
+				values[e.t] = *e.val
+			}
+		}
+		return values
+	}
+	cloudByTime := byTime("totalcloudcover")
+	radiationByTime := byTime("radiationglobal")
+
+	// Snow is estimated hour by hour, so a day that turns from snow to
+	// rain only counts its snowy hours. The potential form stands in for
+	// hours without a precipitation form.
+	tempByTime := byTime("temperature")
+	formByTime := byTime("precipitationform")
+	potentialFormByTime := byTime("potentialprecipitationform")
+	snowByDate := make(map[string]float64)
+	for _, e := range params["precipitation1h"] {
+		if e.val == nil {
+			continue
+		}
+		snowByDate[localDate(e.t)] += snowDepthMM(*e.val, hourValue(e.t, formByTime, potentialFormByTime), hourValue(e.t, tempByTime))
+	}
+	seenTimes := make(map[time.Time]bool)
+	for _, entries := range params {
+		for _, e := range entries {
+			if e.val == nil || seenTimes[e.t] {
+				continue
+			}
+			seenTimes[e.t] = true
+			if b, ok := days[localDate(e.t)]; ok {
+				b.times = append(b.times, e.t)
+			}
 		}
 	}
 
 	now := time.Now()
+	used := make(map[string]bool)
 	var forecasts []weather.DailyForecast
 	for _, dk := range dayOrder {
 		b := days[dk]
 		date, _ := time.Parse("2006-01-02", dk)
-		vals := func(param string) []float64 { return b.values[param] }
+		dropped := make(map[string]bool)
+		vals := func(param string) []float64 {
+			used[param] = true
+			values := b.values[param]
+			if b.hours[param] == 0 || conditionalForecastParams[param] || float64(len(values)) >= minCoverage*float64(b.hours[param]) {
+				return values
+			}
+			if !dropped[param] {
+				dropped[param] = true
+				summary.Dropped++
+			}
+			return nil
+		}
 
 		f := weather.DailyForecast{
 			GridLat:   gridLat,
@@ -304,11 +847,20 @@ func ParseForecast(data []byte, gridLat, gridLon float64) (weather.ForecastData,
 			f.TempLow = &lo
 		}
 		f.TempAvg = avgPtr(tempVals)
+		f.TempDayMax = maxPtr(vals("temperature:day"))
+		f.TempDayAvg = avgPtr(vals("temperature:day"))
+		f.TempNightMin = minPtr(vals("temperature:night"))
+		f.TempNightAvg = avgPtr(vals("temperature:night"))
 		f.WindSpeed = avgPtr(vals("windspeedms"))
 		f.WindDir = circularMeanDegreesPtr(vals("winddirection"))
 		f.HumidityAvg = avgPtr(vals("humidity"))
 		f.PrecipMM = sumPtr(vals("precipitation1h"))
 		f.Precip1hSum = f.PrecipMM
+		f.PrecipHoursCounted = len(vals("precipitation1h"))
+		if f.PrecipMM != nil {
+			snow := math.Round(snowByDate[dk]*10) / 10
+			f.SnowAccumulationMM = &snow
+		}
 		f.Symbol = modeRoundedStringPtr(vals("weathersymbol3"))
 
 		f.DewPointAvg = avgPtr(vals("dewpoint"))
@@ -338,34 +890,81 @@ func ParseForecast(data []byte, gridLat, gridLon float64) (weather.ForecastData,
 		f.WindVMSAvg = avgPtr(vals("windvms"))
 		f.WindVectorMSAvg = avgPtr(vals("windvectorms"))
 
+		sunshineHours := make([]weather.SunshineHour, 0, len(b.times))
+		for _, t := range b.times {
+			h := weather.SunshineHour{Time: t}
+			if v, ok := cloudByTime[t]; ok {
+				h.CloudCover = &v
+			}
+			if v, ok := radiationByTime[t]; ok {
+				h.RadiationGlobal = &v
+			}
+			sunshineHours = append(sunshineHours, h)
+		}
+		f.SunshineHours = weather.EstimateSunshineHours(gridLat, gridLon, date, sunshineHours)
+		dayLength := math.Round(weather.ComputeSunTimes(gridLat, gridLon, date).DayLength.Hours()*100) / 100
+		f.DayLengthHours = &dayLength
+
 		forecasts = append(forecasts, f)
 	}
+	for param := range params {
+		if !used[param] {
+			summary.UnknownParams = append(summary.UnknownParams, param)
+		}
+	}
+	slices.Sort(summary.UnknownParams)
 	return weather.ForecastData{
 		Forecasts: forecasts,
+		Hourly:    hourlyForecasts(featureCollection{Members: members}, 0, time.Time{}),
 		Timezone:  timezone,
-	}, nil
+	}, summary
+}
+
+// hourValue is the value at t in the first of byTime that has one.
+func hourValue(t time.Time, byTime ...map[time.Time]float64) *float64 {
+	for _, values := range byTime {
+		if v, ok := values[t]; ok {
+			return &v
+		}
+	}
+	return nil
 }
 
 // ParseHourlyForecast parses hourly time/value pairs for temperature and weather symbol.
-func ParseHourlyForecast(data []byte, limit int) ([]weather.HourlyForecast, error) {
+// Hours before the one containing now are dropped before the first limit
+// hours are kept, so a response starting in the past still yields limit
+// useful hours.
+func ParseHourlyForecast(data []byte, limit int, now time.Time) ([]weather.HourlyForecast, error) {
 	var fc featureCollection
 	if err := xml.Unmarshal(data, &fc); err != nil {
 		return nil, fmt.Errorf("unmarshal WFS hourly forecast: %w", err)
 	}
+	return hourlyForecasts(fc, limit, now), nil
+}
 
+// hourlyForecasts collects the hours of a forecast response from the one
+// containing now, the first limit of them when limit is positive. A zero
+// now keeps every hour.
+func hourlyForecasts(fc featureCollection, limit int, now time.Time) []weather.HourlyForecast {
 	type hourlyPoint struct {
-		t       time.Time
-		temp    *float64
-		wind    *float64
-		windDir *float64
-		rh      *float64
-		precip  *float64
-		sym     *string
+		t        time.Time
+		temp     *float64
+		wind     *float64
+		windDir  *float64
+		gust     *float64
+		rh       *float64
+		pressure *float64
+		dewPoint *float64
+		precip   *float64
+		pop      *float64
+		cloud    *float64
+		fog      *float64
+		sym      *string
 	}
 	byTime := make(map[time.Time]*hourlyPoint)
 
 	for _, m := range fc.Members {
-		param := strings.ToLower(extractParam(m.Observation.ObservedProperty.Href))
+		param := forecastParam(m.Observation.ObservedProperty.Href)
 		for _, pt := range m.Observation.Result.TimeSeries.Points {
 			t, err := time.Parse(time.RFC3339, pt.TVP.Time)
 			if err != nil {
@@ -376,6 +975,9 @@ func ParseHourlyForecast(data []byte, limit int) ([]weather.HourlyForecast, erro
 				continue
 			}
 
+			// Keyed in UTC so an hour is one entry however its offset
+			// was written; a repeated parameter keeps the last value.
+			t = t.UTC()
 			p, ok := byTime[t]
 			if !ok {
 				p = &hourlyPoint{t: t}
@@ -389,10 +991,23 @@ func ParseHourlyForecast(data []byte, limit int) ([]weather.HourlyForecast, erro
 				p.wind = val
 			case "winddirection":
 				p.windDir = val
+			case "hourlymaximumgust":
+				p.gust = val
 			case "humidity":
 				p.rh = val
+			case "pressure":
+				p.pressure = val
+			case "dewpoint":
+				p.dewPoint = val
 			case "precipitation1h":
 				p.precip = val
+			case "pop":
+				pop := max(0, min(*val, 100))
+				p.pop = &pop
+			case "totalcloudcover":
+				p.cloud = val
+			case "fogintensity":
+				p.fog = val
 			case "weathersymbol3":
 				s := strconv.Itoa(int(math.Round(*val)))
 				p.sym = &s
@@ -400,9 +1015,14 @@ func ParseHourlyForecast(data []byte, limit int) ([]weather.HourlyForecast, erro
 		}
 	}
 
+	currentHour := now.Truncate(time.Hour)
 	var items []hourlyPoint
 	for _, p := range byTime {
-		if p.temp == nil && p.wind == nil && p.windDir == nil && p.rh == nil && p.precip == nil && p.sym == nil {
+		if p.t.Before(currentHour) {
+			continue
+		}
+		if p.temp == nil && p.wind == nil && p.windDir == nil && p.gust == nil && p.rh == nil && p.pressure == nil && p.dewPoint == nil &&
+			p.precip == nil && p.pop == nil && p.cloud == nil && p.fog == nil && p.sym == nil {
 			continue
 		}
 		items = append(items, *p)
@@ -418,16 +1038,22 @@ func ParseHourlyForecast(data []byte, limit int) ([]weather.HourlyForecast, erro
 	result := make([]weather.HourlyForecast, 0, len(items))
 	for _, p := range items {
 		result = append(result, weather.HourlyForecast{
-			Time:        p.t,
-			Temperature: p.temp,
-			WindSpeed:   p.wind,
-			WindDir:     p.windDir,
-			Humidity:    p.rh,
-			Precip1h:    p.precip,
-			Symbol:      p.sym,
+			Time:         p.t,
+			Temperature:  p.temp,
+			WindSpeed:    p.wind,
+			WindDir:      p.windDir,
+			WindGust:     p.gust,
+			Humidity:     p.rh,
+			Pressure:     p.pressure,
+			DewPoint:     p.dewPoint,
+			Precip1h:     p.precip,
+			PoP:          p.pop,
+			Symbol:       p.sym,
+			CloudCover:   p.cloud,
+			FogIntensity: p.fog,
 		})
 	}
-	return result, nil
+	return result
 }
 
 // ParseClimateNormals parses an FMI WFS response containing 30-year climate
@@ -453,7 +1079,7 @@ func ParseClimateNormals(data []byte) ([]weather.ClimateNormal, error) {
 
 	for _, m := range fc.Members {
 		param := extractParam(m.Observation.ObservedProperty.Href)
-		fmisid, _, _, _, _ := extractStationInfo(m.Observation)
+		fmisid := extractStationInfo(m.Observation).FMISID
 
 		for _, pt := range m.Observation.Result.TimeSeries.Points {
 			t, err := time.Parse(time.RFC3339, pt.TVP.Time)
@@ -526,6 +1152,14 @@ func sumPtr(values []float64) *float64 {
 	return &sum
 }
 
+func minPtr(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	v := slices.Min(values)
+	return &v
+}
+
 func maxPtr(values []float64) *float64 {
 	if len(values) == 0 {
 		return nil
@@ -587,6 +1221,90 @@ func circularMeanDegreesPtr(values []float64) *float64 {
 	return &mean
 }
 
+// forecastParamAliases maps Harmonie parameter names to the edited
+// forecast's, so both models fill the same DailyForecast and
+// HourlyForecast fields.
+var forecastParamAliases = map[string]string{
+	"windgust":    "hourlymaximumgust",
+	"maximumwind": "hourlymaximumwindspeed",
+}
+
+// ecmwfParamAliases maps the ECMWF surface forecast's parameter names to
+// the edited forecast's. ECMWF reports precipitation over each time step,
+// which widens to three and six hours further out, so summing it per day
+// still gives the daily amount. None of these names occur in the other
+// models' responses.
+var ecmwfParamAliases = map[string]string{
+	"precipitationamount": "precipitation1h",
+}
+
+// forecastParam returns the lowercased forecast parameter named in href,
+// with model-specific names mapped by forecastParamAliases and
+// ecmwfParamAliases.
+func forecastParam(href string) string {
+	param := strings.ToLower(extractParam(href))
+	if alias, ok := forecastParamAliases[param]; ok {
+		return alias
+	}
+	if alias, ok := ecmwfParamAliases[param]; ok {
+		return alias
+	}
+	return param
+}
+
+// Daily precipitation, in mm, from which estimateSymbols reports light,
+// moderate and heavy precipitation.
+const (
+	estimatedSymbolMinPrecipMM      = 0.3
+	estimatedSymbolModeratePrecipMM = 3.0
+	estimatedSymbolHeavyPrecipMM    = 10.0
+)
+
+// estimateSymbols fills Symbol for days the model sent no WeatherSymbol3
+// for, as ECMWF does not, from the day's precipitation, mean temperature
+// and cloud cover. Only the non-shower WeatherSymbol3 codes are used, as a
+// daily total cannot tell showers from steady rain.
+func estimateSymbols(forecasts []weather.DailyForecast) {
+	for i := range forecasts {
+		f := &forecasts[i]
+		if f.Symbol != nil {
+			continue
+		}
+		var code int
+		switch {
+		case f.PrecipMM != nil && *f.PrecipMM >= estimatedSymbolMinPrecipMM && f.TempAvg != nil:
+			// Rain, sleet or snow (31, 81 or 51), plus one for moderate
+			// and two for heavy precipitation.
+			switch {
+			case *f.TempAvg >= 2:
+				code = 31
+			case *f.TempAvg > -1:
+				code = 81
+			default:
+				code = 51
+			}
+			if *f.PrecipMM >= estimatedSymbolHeavyPrecipMM {
+				code += 2
+			} else if *f.PrecipMM >= estimatedSymbolModeratePrecipMM {
+				code++
+			}
+		case f.TotalCloudCoverAvg != nil:
+			switch {
+			case *f.TotalCloudCoverAvg < 20:
+				code = 1
+			case *f.TotalCloudCoverAvg < 80:
+				code = 2
+			default:
+				code = 3
+			}
+		default:
+			continue
+		}
+		symbol := strconv.Itoa(code)
+		f.Symbol = &symbol
+	}
+}
+
 func extractParam(href string) string {
 	for _, part := range strings.Split(href, "&") {
 		if strings.HasPrefix(part, "param=") {
@@ -600,28 +1318,43 @@ func extractParam(href string) string {
 	return ""
 }
 
-func extractStationInfo(pts pointTimeSeries) (fmisid int, name string, lat, lon float64, wmo string) {
-	foi := pts.FeatureOfInterest.Feature
+func extractStationInfo(pts pointTimeSeries) weather.Station {
+	return stationInfo(pts.FeatureOfInterest.Feature)
+}
+
+func stationInfo(foi spatialFeature) weather.Station {
+	var (
+		fmisid            int
+		name, wmo, region string
+		elevationM        *float64
+		groups            []string
+	)
 	for _, lm := range foi.SampledFeature.LocationCollection.Members {
 		loc := lm.Location
 		fmisid, _ = strconv.Atoi(loc.Identifier)
+		if elevationM == nil {
+			elevationM = parseFloat(strings.TrimSpace(loc.Elevation))
+		}
+		groups = append(groups, loc.StationGroups...)
 		var fallbackName string
-		for _, n := range loc.Names {
+		for _, n := range slices.Concat(loc.Names, loc.Regions) {
 			value := strings.TrimSpace(n.Value)
 			if value == "" {
 				continue
 			}
-			switch {
-			case isLocationNameCodeSpace(n.CodeSpace):
+			switch field := locationField(n.CodeSpace); {
+			case field == locationName:
 				if name == "" || isLikelyCodeValue(name) {
 					name = value
 				}
-			case isLocationWMOCodeSpace(n.CodeSpace):
+			case field == locationWMO:
 				wmo = value
 				if fallbackName == "" {
 					fallbackName = value
 				}
-			case fallbackName == "":
+			case field == locationRegion:
+				region = value
+			case field == locationOther && fallbackName == "":
 				fallbackName = value
 			}
 		}
@@ -646,8 +1379,41 @@ func extractStationInfo(pts pointTimeSeries) (fmisid int, name string, lat, lon
 	if name == "" {
 		name = strconv.Itoa(fmisid)
 	}
-	lat, lon = parsePos(pos)
-	return
+	lat, lon := parsePos(pos)
+	return weather.Station{
+		FMISID:     fmisid,
+		Name:       name,
+		Lat:        lat,
+		Lon:        lon,
+		WMOCode:    wmo,
+		Region:     region,
+		ElevationM: elevationM,
+		Type:       stationType(groups),
+	}
+}
+
+// stationTypes maps FMI station group codes to weather.Station types, in
+// order of preference: a station in several groups gets the first match.
+var stationTypes = []struct {
+	groups []string
+	typ    string
+}{
+	{[]string{"aws", "synop"}, weather.StationTypeAWS},
+	{[]string{"mareo", "mareograph"}, weather.StationTypeMareograph},
+	{[]string{"prec", "precipitation"}, weather.StationTypePrecipitation},
+}
+
+// stationType returns the type for a station's FMI groups, or "" when none
+// is recognised.
+func stationType(groups []string) string {
+	for _, t := range stationTypes {
+		for _, g := range groups {
+			if slices.Contains(t.groups, strings.ToLower(strings.TrimSpace(g))) {
+				return t.typ
+			}
+		}
+	}
+	return ""
 }
 
 func extractLocationTimezone(pts pointTimeSeries) string {
@@ -660,12 +1426,38 @@ func extractLocationTimezone(pts pointTimeSeries) string {
 	return ""
 }
 
-func isLocationNameCodeSpace(codeSpace string) bool {
-	return strings.HasSuffix(strings.ToLower(strings.TrimSpace(codeSpace)), "/locationcode/name")
+// locationNameField is the Station field a Location name fills, by the
+// name's codeSpace.
+type locationNameField int
+
+const (
+	locationOther locationNameField = iota
+	locationName
+	locationWMO
+	locationRegion
+	// locationCountry names are recognised so they are never taken for a
+	// station name, but not kept: every station is in Finland.
+	locationCountry
+)
+
+// locationCodeSpaces maps the trailing path of FMI codeSpace URIs, such as
+// http://xml.fmi.fi/namespace/locationcode/name, to the field they fill.
+// FMI's region is the municipality the station is in.
+var locationCodeSpaces = map[string]locationNameField{
+	"locationcode/name": locationName,
+	"locationcode/wmo":  locationWMO,
+	"location/region":   locationRegion,
+	"location/country":  locationCountry,
 }
 
-func isLocationWMOCodeSpace(codeSpace string) bool {
-	return strings.HasSuffix(strings.ToLower(strings.TrimSpace(codeSpace)), "/locationcode/wmo")
+func locationField(codeSpace string) locationNameField {
+	codeSpace = strings.ToLower(strings.TrimSpace(codeSpace))
+	for suffix, field := range locationCodeSpaces {
+		if strings.HasSuffix(codeSpace, "/"+suffix) {
+			return field
+		}
+	}
+	return locationOther
 }
 
 func isLikelyCodeValue(v string) bool {
@@ -690,6 +1482,11 @@ func parsePos(pos string) (float64, float64) {
 	return lat, lon
 }
 
+// isMissingValue reports whether s is FMI's marker for a missing value.
+func isMissingValue(s string) bool {
+	return strings.EqualFold(strings.TrimSpace(s), "NaN")
+}
+
 func parseFloat(s string) *float64 {
 	v, err := strconv.ParseFloat(s, 64)
 	if err != nil || math.IsNaN(v) {
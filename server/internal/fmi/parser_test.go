@@ -1,15 +1,24 @@
 package fmi
 
 import (
+	"fmt"
+	"io"
+	"maps"
 	"math"
 	"os"
+	"reflect"
+	"runtime"
+	"runtime/metrics"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"wby/internal/weather"
 )
 
 func TestParseObservations(t *testing.T) {
-	data, err := os.ReadFile("testdata/observations.xml")
+	data, err := os.ReadFile("fixtures/observations.xml")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -42,6 +51,18 @@ func TestParseObservations(t *testing.T) {
 	}
 }
 
+func TestParseObservationsReader_RejectsOtherDocuments(t *testing.T) {
+	for _, body := range []string{"", "<ExceptionReport></ExceptionReport>"} {
+		if _, err := ParseObservationsReader(strings.NewReader(body)); err == nil {
+			t.Errorf("expected error for %q", body)
+		}
+	}
+	result, err := ParseObservationsReader(strings.NewReader(`<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0"/>`))
+	if err != nil || len(result.Stations) != 0 {
+		t.Fatalf("expected an empty result, got %+v, %v", result, err)
+	}
+}
+
 func TestCircularMeanDegreesPtrWrapAround(t *testing.T) {
 	got := circularMeanDegreesPtr([]float64{350, 10})
 	if got == nil {
@@ -59,7 +80,7 @@ func TestCircularMeanDegreesPtrEmpty(t *testing.T) {
 }
 
 func TestParseObservationsStationNamePreference(t *testing.T) {
-	data, err := os.ReadFile("testdata/observations.xml")
+	data, err := os.ReadFile("fixtures/observations.xml")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -81,19 +102,116 @@ func TestParseObservationsStationNamePreference(t *testing.T) {
 		if st.WMOCode != "2978" {
 			t.Fatalf("unexpected WMO code for FMISID 100971: %q", st.WMOCode)
 		}
+		if st.Region != "Helsinki" {
+			t.Fatalf("unexpected region for FMISID 100971: %q", st.Region)
+		}
 	}
 	if !found {
 		t.Fatal("expected station FMISID 100971 in fixture")
 	}
 }
 
+func TestParseObservationsStationElevationAndType(t *testing.T) {
+	data, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fixture predates elevation and group parsing; add them to the
+	// first Location block only.
+	region := `<target:region codeSpace="http://xml.fmi.fi/namespace/location/region">Helsinki</target:region>`
+	data = []byte(strings.Replace(string(data), region, region+
+		`<target:elevation uom="m"> 4 </target:elevation>`+
+		`<target:stationGroup>PREC</target:stationGroup><target:stationGroup>AWS</target:stationGroup>`, 1))
+
+	result, err := ParseObservations(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, st := range result.Stations {
+		if st.FMISID != 100971 {
+			continue
+		}
+		if st.ElevationM == nil || *st.ElevationM != 4 {
+			t.Fatalf("expected elevation 4 m, got %v", st.ElevationM)
+		}
+		if st.Type != weather.StationTypeAWS {
+			t.Fatalf("expected type %q, got %q", weather.StationTypeAWS, st.Type)
+		}
+		return
+	}
+	t.Fatal("expected station FMISID 100971 in fixture")
+}
+
+func TestParseObservationsStationRegions(t *testing.T) {
+	data, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ParseObservations(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Stations) == 0 {
+		t.Fatal("expected stations in fixture")
+	}
+	for _, st := range result.Stations {
+		if st.Region == "" {
+			t.Errorf("station %d (%s): missing region", st.FMISID, st.Name)
+		}
+		if st.Region == st.Name {
+			t.Errorf("station %d: region %q was taken as the name", st.FMISID, st.Region)
+		}
+	}
+}
+
+func TestLocationField(t *testing.T) {
+	cases := []struct {
+		codeSpace string
+		want      locationNameField
+	}{
+		{"http://xml.fmi.fi/namespace/locationcode/name", locationName},
+		{" HTTP://XML.FMI.FI/namespace/LocationCode/Name ", locationName},
+		{"http://xml.fmi.fi/namespace/locationcode/wmo", locationWMO},
+		{"http://xml.fmi.fi/namespace/location/region", locationRegion},
+		{"http://xml.fmi.fi/namespace/location/country", locationCountry},
+		{"http://xml.fmi.fi/namespace/locationcode/geoid", locationOther},
+		{"http://xml.fmi.fi/namespace/locationcode/fmisid", locationOther},
+		{"http://example.com/notlocationcode/name", locationOther},
+		{"", locationOther},
+	}
+	for _, c := range cases {
+		if got := locationField(c.codeSpace); got != c.want {
+			t.Errorf("locationField(%q) = %d, want %d", c.codeSpace, got, c.want)
+		}
+	}
+}
+
+func TestStationType(t *testing.T) {
+	cases := []struct {
+		groups []string
+		want   string
+	}{
+		{nil, ""},
+		{[]string{"AWS"}, weather.StationTypeAWS},
+		{[]string{"prec"}, weather.StationTypePrecipitation},
+		{[]string{"PREC", "MAREO"}, weather.StationTypeMareograph},
+		{[]string{"BUOY"}, ""},
+	}
+	for _, c := range cases {
+		if got := stationType(c.groups); got != c.want {
+			t.Errorf("stationType(%v) = %q, want %q", c.groups, got, c.want)
+		}
+	}
+}
+
 func TestParseForecast(t *testing.T) {
-	data, err := os.ReadFile("testdata/forecast.xml")
+	data, err := os.ReadFile("fixtures/forecast.xml")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	result, err := ParseForecast(data, 60.17, 24.94)
+	result, _, err := ParseForecast(data, 60.17, 24.94, DefaultForecastMinCoverage)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -125,15 +243,20 @@ func TestParseForecast(t *testing.T) {
 	if day.HumidityAvg == nil {
 		t.Error("expected humidity_avg to be set")
 	}
+	if day.SunshineHours == nil {
+		t.Error("expected sunshine_hours to be set")
+	} else if day.DayLengthHours == nil || *day.SunshineHours > *day.DayLengthHours {
+		t.Errorf("expected sunshine_hours within day length, got %v of %v", *day.SunshineHours, day.DayLengthHours)
+	}
 }
 
 func TestParseHourlyForecast(t *testing.T) {
-	data, err := os.ReadFile("testdata/forecast.xml")
+	data, err := os.ReadFile("fixtures/forecast.xml")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	result, err := ParseHourlyForecast(data, 12)
+	result, err := ParseHourlyForecast(data, 12, forecastFixtureStart)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -155,6 +278,27 @@ func TestParseHourlyForecast(t *testing.T) {
 	if result[0].Humidity == nil {
 		t.Error("expected hourly humidity to be set")
 	}
+	if result[0].CloudCover == nil {
+		t.Error("expected hourly cloud_cover to be set")
+	}
+	if result[0].WindGust == nil {
+		t.Error("expected hourly wind_gust to be set")
+	}
+	if result[0].Pressure == nil {
+		t.Error("expected hourly pressure to be set")
+	}
+	// The fixture has a dew point for every hour, -9.92 in the first.
+	for _, h := range result {
+		if h.DewPoint == nil {
+			t.Fatalf("expected hourly dew_point to be set at %s", h.Time)
+		}
+	}
+	if got := *result[0].DewPoint; got != -9.92 {
+		t.Errorf("expected the first hour's dew point -9.92, got %v", got)
+	}
+	if result[0].PoP == nil || *result[0].PoP < 0 || *result[0].PoP > 100 {
+		t.Errorf("expected hourly PoP within 0-100, got %v", result[0].PoP)
+	}
 	for i := 1; i < len(result); i++ {
 		if result[i].Time.Before(result[i-1].Time) {
 			t.Fatalf("hourly forecast not sorted: %s before %s", result[i].Time, result[i-1].Time)
@@ -162,6 +306,50 @@ func TestParseHourlyForecast(t *testing.T) {
 	}
 }
 
+// forecastFixtureStart is the first hour in fixtures/forecast.xml.
+var forecastFixtureStart = time.Date(2026, 2, 16, 8, 0, 0, 0, time.UTC)
+
+func TestParseForecast_ReturnsHourlySeries(t *testing.T) {
+	data, err := os.ReadFile("fixtures/forecast.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, _, err := ParseForecast(data, 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hourly, err := ParseHourlyForecast(data, 0, forecastFixtureStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hourly) == 0 || !reflect.DeepEqual(result.Hourly, hourly) {
+		t.Fatalf("expected the %d hours of the response, got %d", len(hourly), len(result.Hourly))
+	}
+	if !result.Hourly[0].Time.Equal(forecastFixtureStart) {
+		t.Errorf("expected the series to start at %s, got %s", forecastFixtureStart, result.Hourly[0].Time)
+	}
+}
+
+func TestParseHourlyForecast_SkipsPastHours(t *testing.T) {
+	data, err := os.ReadFile("fixtures/forecast.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two and a half hours after the response starts, the first two hours
+	// have passed and the current one is partly elapsed.
+	now := forecastFixtureStart.Add(150 * time.Minute)
+	result, err := ParseHourlyForecast(data, 12, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 12 {
+		t.Fatalf("expected 12 entries, got %d", len(result))
+	}
+	if want := now.Truncate(time.Hour); !result[0].Time.Equal(want) {
+		t.Errorf("expected the first entry at the current hour %s, got %s", want, result[0].Time)
+	}
+}
+
 func TestParseClimateNormals(t *testing.T) {
 	data, err := os.ReadFile("testdata/climate_normals.xml")
 	if err != nil {
@@ -217,13 +405,244 @@ func TestParseClimateNormals(t *testing.T) {
 	}
 }
 
+// hourlyForecastXML is a minimal forecast response with one parameter
+// reported every hour in [from, to).
+func hourlyForecastXML(timezone, param string, from, to time.Time, value float64) []byte {
+	return hourlySeriesXML(timezone, param, from, to, func(time.Time) float64 { return value })
+}
+
+// hourlySeriesXML is hourlyForecastXML with a value computed for each hour.
+func hourlySeriesXML(timezone, param string, from, to time.Time, value func(time.Time) float64) []byte {
+	var b strings.Builder
+	b.WriteString(`<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" xmlns:om="http://www.opengis.net/om/2.0" xmlns:omso="http://inspire.ec.europa.eu/schemas/omso/3.0" xmlns:sams="http://www.opengis.net/samplingSpatial/2.0" xmlns:sam="http://www.opengis.net/sampling/2.0" xmlns:wml2="http://www.opengis.net/waterml/2.0" xmlns:target="http://xml.fmi.fi/namespace/om/atmosphericfeatures/1.1" xmlns:xlink="http://www.w3.org/1999/xlink">`)
+	b.WriteString(`<wfs:member><omso:PointTimeSeriesObservation>`)
+	fmt.Fprintf(&b, `<om:observedProperty xlink:href="https://opendata.fmi.fi/meta?observableProperty=forecast&amp;param=%s&amp;language=eng"/>`, param)
+	fmt.Fprintf(&b, `<om:featureOfInterest><sams:SF_SpatialSamplingFeature><sam:sampledFeature><target:LocationCollection><target:member><target:Location><target:timezone>%s</target:timezone></target:Location></target:member></target:LocationCollection></sam:sampledFeature></sams:SF_SpatialSamplingFeature></om:featureOfInterest>`, timezone)
+	b.WriteString(`<om:result><wml2:MeasurementTimeseries>`)
+	for t := from; t.Before(to); t = t.Add(time.Hour) {
+		fmt.Fprintf(&b, `<wml2:point><wml2:MeasurementTVP><wml2:time>%s</wml2:time><wml2:value>%g</wml2:value></wml2:MeasurementTVP></wml2:point>`, t.UTC().Format(time.RFC3339), value(t))
+	}
+	b.WriteString(`</wml2:MeasurementTimeseries></om:result></omso:PointTimeSeriesObservation></wfs:member></wfs:FeatureCollection>`)
+	return []byte(b.String())
+}
+
+func TestParseHourlyForecast_ClampsPoP(t *testing.T) {
+	from := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	for _, c := range []struct {
+		value, want float64
+	}{{-5, 0}, {70, 70}, {100.4, 100}} {
+		result, err := ParseHourlyForecast(hourlyForecastXML("Europe/Helsinki", "PoP", from, from.Add(time.Hour), c.value), 0, from)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result) != 1 || result[0].PoP == nil || *result[0].PoP != c.want {
+			t.Errorf("PoP %g: got %+v, want %g", c.value, result, c.want)
+		}
+	}
+}
+
+func TestParseForecast_CollapsesDuplicateMembers(t *testing.T) {
+	data, err := os.ReadFile("testdata/forecast_duplicates.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, summary, err := ParseForecast(data, 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Forecasts) != 1 {
+		t.Fatalf("expected one day, got %d", len(result.Forecasts))
+	}
+	f := result.Forecasts[0]
+	if f.PrecipMM == nil || *f.PrecipMM != 3 || f.PrecipHoursCounted != 24 {
+		t.Errorf("expected 3 mm over 24 hours, got %v over %d", f.PrecipMM, f.PrecipHoursCounted)
+	}
+	// The repeated 13:00 value replaces the first; the repeated NaN at
+	// 14:00 does not erase it.
+	if f.TempHigh == nil || *f.TempHigh != 30 || f.TempAvg == nil || *f.TempAvg != 16.25 {
+		t.Errorf("expected high 30 and avg 16.25, got %v and %v", f.TempHigh, f.TempAvg)
+	}
+	if summary.Duplicates != 28 || summary.Missing != 0 {
+		t.Errorf("expected 28 duplicates and no missing values, got %+v", summary)
+	}
+
+	if len(result.Hourly) != 24 {
+		t.Fatalf("expected 24 hours, got %d", len(result.Hourly))
+	}
+	for _, h := range result.Hourly {
+		want := map[int]float64{13: 30, 14: 18.5}[h.Time.Hour()]
+		if h.Time.Day() == 15 && want != 0 && (h.Temperature == nil || *h.Temperature != want) {
+			t.Errorf("%s: expected %g, got %v", h.Time.Format(time.RFC3339), want, h.Temperature)
+		}
+	}
+}
+
+func TestObservationBuilder_RepeatedValues(t *testing.T) {
+	at := time.Date(2026, 2, 15, 20, 0, 0, 0, time.UTC)
+	v := func(f float64) *float64 { return &f }
+
+	b := newObservationBuilder()
+	b.addStation(weather.Station{FMISID: 100971})
+	b.set(100971, at, "t2m", v(-8.1))
+	// The same instant written with an offset, then missing.
+	b.set(100971, at.In(time.FixedZone("EET", 2*3600)), "t2m", v(-8.3))
+	b.set(100971, at, "t2m", nil)
+
+	result := b.result()
+	if len(result.Observations) != 1 {
+		t.Fatalf("expected one observation, got %d", len(result.Observations))
+	}
+	if got := result.Observations[0].Temperature; got == nil || *got != -8.3 {
+		t.Fatalf("expected the last value -8.3, got %v", got)
+	}
+}
+
+func TestParseForecast_MissingValuePolicy(t *testing.T) {
+	data, err := os.ReadFile("testdata/forecast_nan.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, summary, err := ParseForecast(data, 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Forecasts) != 1 {
+		t.Fatalf("expected one day, got %d", len(result.Forecasts))
+	}
+	f := result.Forecasts[0]
+	for name, c := range map[string]struct {
+		got  *float64
+		want float64
+	}{
+		// Four NaN hours leave 20 of 24, and NaN every other hour is
+		// exactly the default half.
+		"temp high":    {f.TempHigh, 21.5},
+		"temp low":     {f.TempLow, 10},
+		"temp avg":     {f.TempAvg, 16.45},
+		"humidity avg": {f.HumidityAvg, 80},
+		// The corrupt value is skipped, not counted against coverage.
+		"pressure avg": {f.PressureAvg, 1012},
+		// Precipitation form is NaN whenever it is dry.
+		"precip form": {f.PrecipitationFormMode, 1},
+	} {
+		if c.got == nil || math.Abs(*c.got-c.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %g", name, c.got, c.want)
+		}
+	}
+	// All NaN, and a single value among NaNs, are both below coverage.
+	if f.RadiationGlobalAvg != nil || f.RadiationLWAvg != nil {
+		t.Errorf("expected no radiation averages, got %v and %v", f.RadiationGlobalAvg, f.RadiationLWAvg)
+	}
+
+	want := ForecastParseSummary{Missing: 84, Unparsable: 1, UnknownParams: []string{"visibility"}, Dropped: 2}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("summary = %+v, want %+v", summary, want)
+	}
+
+	// Without a minimum the lone longwave value is averaged as before.
+	result, summary, err = ParseForecast(data, 60.17, 24.94, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Forecasts[0].RadiationLWAvg; got == nil || *got != 310 {
+		t.Errorf("expected radiation LW 310 without a minimum coverage, got %v", got)
+	}
+	if summary.Dropped != 0 {
+		t.Errorf("expected nothing dropped without a minimum coverage, got %d", summary.Dropped)
+	}
+
+	// Requiring every hour drops the temperatures and humidity too.
+	result, _, err = ParseForecast(data, 60.17, 24.94, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := result.Forecasts[0]; f.TempHigh != nil || f.HumidityAvg != nil || f.PressureAvg == nil {
+		t.Errorf("expected only pressure to survive full coverage, got temp %v humidity %v pressure %v", f.TempHigh, f.HumidityAvg, f.PressureAvg)
+	}
+}
+
+func TestParseForecast_MapsHarmonieParameters(t *testing.T) {
+	from := time.Date(2026, 6, 15, 9, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+
+	daily, _, err := ParseForecast(hourlyForecastXML("Europe/Helsinki", "WindGust", from, to, 14.5), 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily.Forecasts) != 1 || daily.Forecasts[0].HourlyMaximumGustMax == nil || *daily.Forecasts[0].HourlyMaximumGustMax != 14.5 {
+		t.Errorf("expected WindGust as hourly_maximum_gust_max, got %+v", daily.Forecasts)
+	}
+
+	daily, _, err = ParseForecast(hourlyForecastXML("Europe/Helsinki", "MaximumWind", from, to, 9), 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(daily.Forecasts) != 1 || daily.Forecasts[0].HourlyMaximumWindSpeedMax == nil || *daily.Forecasts[0].HourlyMaximumWindSpeedMax != 9 {
+		t.Errorf("expected MaximumWind as hourly_maximum_wind_speed_max, got %+v", daily.Forecasts)
+	}
+
+	hourly, err := ParseHourlyForecast(hourlyForecastXML("Europe/Helsinki", "WindGust", from, to, 14.5), 0, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hourly) != 3 || hourly[0].WindGust == nil || *hourly[0].WindGust != 14.5 {
+		t.Errorf("expected WindGust as the hourly wind_gust, got %+v", hourly)
+	}
+}
+
+func TestParseForecast_BucketsByLocalDayAcrossDST(t *testing.T) {
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name  string
+		day   time.Time
+		hours float64
+	}{
+		{"spring forward", time.Date(2026, 3, 29, 0, 0, 0, 0, helsinki), 23},
+		{"ordinary day", time.Date(2026, 6, 15, 0, 0, 0, 0, helsinki), 24},
+		{"fall back", time.Date(2026, 10, 25, 0, 0, 0, 0, helsinki), 25},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// One hourly millimetre from the local midnight before the day
+			// to the one after it, so each day's sum is its length in hours.
+			from := c.day.AddDate(0, 0, -1)
+			to := c.day.AddDate(0, 0, 2)
+			result, _, err := ParseForecast(hourlyForecastXML("Europe/Helsinki", "Precipitation1h", from, to, 1), 60.17, 24.94, DefaultForecastMinCoverage)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Forecasts) != 3 {
+				t.Fatalf("expected 3 daily buckets, got %d", len(result.Forecasts))
+			}
+			var found bool
+			for _, f := range result.Forecasts {
+				if f.Date.Format("2006-01-02") != c.day.Format("2006-01-02") {
+					continue
+				}
+				found = true
+				if f.PrecipMM == nil || *f.PrecipMM != c.hours {
+					t.Fatalf("expected %v hourly values on %s, got %v", c.hours, c.day.Format("2006-01-02"), f.PrecipMM)
+				}
+			}
+			if !found {
+				t.Fatalf("no bucket for %s", c.day.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
 func TestParseForecastDefaultParamsFixture(t *testing.T) {
-	data, err := os.ReadFile("testdata/forecast.xml")
+	data, err := os.ReadFile("fixtures/forecast.xml")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	result, err := ParseForecast(data, 60.17, 24.94)
+	result, _, err := ParseForecast(data, 60.17, 24.94, DefaultForecastMinCoverage)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -271,3 +690,403 @@ func TestParseForecastDefaultParamsFixture(t *testing.T) {
 		t.Error("expected wind_vector_ms_avg")
 	}
 }
+
+func TestParseAirQuality(t *testing.T) {
+	data, err := os.ReadFile("testdata/airquality.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ParseAirQuality(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Stations) != 2 {
+		t.Fatalf("stations = %d, want 2", len(result.Stations))
+	}
+	if result.Stations[0].FMISID != 100723 || result.Stations[0].Name != "Helsinki Mannerheimintie" {
+		t.Errorf("first station = %+v", result.Stations[0])
+	}
+
+	// The all-NaN hour at Mannerheimintie is dropped.
+	if len(result.Measurements) != 3 {
+		t.Fatalf("measurements = %d, want 3", len(result.Measurements))
+	}
+
+	latest := result.Measurements[2]
+	if latest.FMISID != 100742 || latest.ObservedAt.Hour() != 7 {
+		t.Fatalf("latest measurement = %d at %v", latest.FMISID, latest.ObservedAt)
+	}
+	if latest.PM25 == nil || *latest.PM25 != 5.3 {
+		t.Errorf("PM25 = %v, want 5.3", latest.PM25)
+	}
+	if latest.PM10 == nil || *latest.PM10 != 12.4 {
+		t.Errorf("PM10 = %v, want 12.4", latest.PM10)
+	}
+	if latest.O3 != nil {
+		t.Errorf("O3 = %v, want nil for NaN", *latest.O3)
+	}
+	if latest.NO2 == nil || *latest.NO2 != 22.9 {
+		t.Errorf("NO2 = %v, want 22.9", latest.NO2)
+	}
+}
+
+func TestParseMarine(t *testing.T) {
+	data, err := os.ReadFile("testdata/marine.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ParseMarine(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Stations) != 2 {
+		t.Fatalf("stations = %d, want 2", len(result.Stations))
+	}
+	if result.Stations[0].FMISID != 132310 || result.Stations[0].Name != "Helsinki Kaivopuisto" {
+		t.Errorf("first station = %+v", result.Stations[0])
+	}
+
+	// The mareograph's NaN-only half hour is dropped.
+	if len(result.Observations) != 3 {
+		t.Fatalf("observations = %d, want 3", len(result.Observations))
+	}
+
+	buoy := result.Observations[2]
+	if buoy.FMISID != 134220 || buoy.ObservedAt.Minute() != 30 {
+		t.Fatalf("latest buoy observation = %d at %v", buoy.FMISID, buoy.ObservedAt)
+	}
+	if buoy.WaveHeight == nil || *buoy.WaveHeight != 0.9 {
+		t.Errorf("WaveHeight = %v, want 0.9", buoy.WaveHeight)
+	}
+	if buoy.WaveDirection == nil || *buoy.WaveDirection != 230 {
+		t.Errorf("WaveDirection = %v, want 230", buoy.WaveDirection)
+	}
+	if buoy.WavePeriod != nil {
+		t.Errorf("WavePeriod = %v, want nil for NaN", *buoy.WavePeriod)
+	}
+	if buoy.WaterTemperature == nil || *buoy.WaterTemperature != 17.3 {
+		t.Errorf("WaterTemperature = %v, want 17.3", buoy.WaterTemperature)
+	}
+
+	mareograph := result.Observations[1]
+	if mareograph.FMISID != 132310 || mareograph.SeaLevel == nil || *mareograph.SeaLevel != -124 {
+		t.Errorf("unexpected mareograph observation %+v", mareograph)
+	}
+	if mareograph.WaveHeight != nil {
+		t.Errorf("mareograph should not report waves, got %v", *mareograph.WaveHeight)
+	}
+}
+
+func TestParseRoadObservations(t *testing.T) {
+	data, err := os.ReadFile("testdata/road.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ParseRoadObservations(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Stations) != 2 {
+		t.Fatalf("stations = %d, want 2", len(result.Stations))
+	}
+	// The all-NaN measurement at Jorvas is dropped.
+	if len(result.Observations) != 3 {
+		t.Fatalf("observations = %d, want 3", len(result.Observations))
+	}
+
+	latest := result.Observations[1]
+	if latest.FMISID != 100011 || latest.ObservedAt.Minute() != 0 {
+		t.Fatalf("unexpected observation order: %d at %v", latest.FMISID, latest.ObservedAt)
+	}
+	if latest.RoadTemperature == nil || *latest.RoadTemperature != -6.1 {
+		t.Errorf("RoadTemperature = %v, want -6.1", latest.RoadTemperature)
+	}
+	if latest.AirTemperature == nil || *latest.AirTemperature != -8 {
+		t.Errorf("AirTemperature = %v, want -8", latest.AirTemperature)
+	}
+	if latest.Condition == nil || *latest.Condition != 7 {
+		t.Errorf("Condition = %v, want 7", latest.Condition)
+	}
+
+	jorvas := result.Observations[2]
+	if jorvas.FMISID != 100234 || jorvas.AirTemperature != nil || jorvas.Condition != nil {
+		t.Errorf("unexpected Jorvas observation %+v", jorvas)
+	}
+}
+
+func TestParseForecast_SplitsDayAndNightTemperatures(t *testing.T) {
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	localHour := func(t time.Time) float64 { return float64(t.In(helsinki).Hour()) }
+
+	// On the spring-forward day there is no 03:00, so the night has 11
+	// hours and the day still runs 06:00-17:00.
+	day := time.Date(2026, 3, 29, 0, 0, 0, 0, helsinki)
+	result, _, err := ParseForecast(hourlySeriesXML("Europe/Helsinki", "Temperature", day, day.AddDate(0, 0, 1), localHour), 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Forecasts) != 1 {
+		t.Fatalf("expected one day, got %d", len(result.Forecasts))
+	}
+	f := result.Forecasts[0]
+	for name, c := range map[string]struct {
+		got  *float64
+		want float64
+	}{
+		"day max":   {f.TempDayMax, 17},
+		"day avg":   {f.TempDayAvg, 11.5},
+		"night min": {f.TempNightMin, 0},
+		"night avg": {f.TempNightAvg, 135.0 / 11},
+	} {
+		if c.got == nil || math.Abs(*c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", name, c.got, c.want)
+		}
+	}
+
+	// Fetched in the evening, today has no daytime hours left.
+	evening := time.Date(2026, 6, 15, 19, 0, 0, 0, helsinki)
+	result, _, err = ParseForecast(hourlySeriesXML("Europe/Helsinki", "Temperature", evening, evening.Add(5*time.Hour), localHour), 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f = result.Forecasts[0]
+	if f.TempDayMax != nil || f.TempDayAvg != nil {
+		t.Errorf("expected no day temperatures, got max %v avg %v", f.TempDayMax, f.TempDayAvg)
+	}
+	if f.TempNightMin == nil || *f.TempNightMin != 19 {
+		t.Errorf("expected night low 19, got %v", f.TempNightMin)
+	}
+}
+
+func TestParseForecast_CountsPrecipitationHours(t *testing.T) {
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fetched at 19:00, today has 5 hours left and tomorrow is complete.
+	evening := time.Date(2026, 6, 15, 19, 0, 0, 0, helsinki)
+	midnight := time.Date(2026, 6, 17, 0, 0, 0, 0, helsinki)
+	result, _, err := ParseForecast(hourlyForecastXML("Europe/Helsinki", "Precipitation1h", evening, midnight, 0.5), 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Forecasts) != 2 {
+		t.Fatalf("expected two days, got %d", len(result.Forecasts))
+	}
+	for i, want := range []struct {
+		hours int
+		sum   float64
+	}{{5, 2.5}, {24, 12}} {
+		f := result.Forecasts[i]
+		if f.PrecipHoursCounted != want.hours || f.PrecipMM == nil || *f.PrecipMM != want.sum {
+			t.Errorf("day %d: expected %gmm over %d hours, got %v over %d", i, want.sum, want.hours, f.PrecipMM, f.PrecipHoursCounted)
+		}
+	}
+
+	// Without precipitation values nothing was counted, unlike a dry day.
+	result, _, err = ParseForecast(hourlyForecastXML("Europe/Helsinki", "Temperature", evening, midnight, 10), 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := result.Forecasts[1]; f.PrecipHoursCounted != 0 || f.PrecipMM != nil {
+		t.Errorf("expected no precipitation hours, got %v over %d", f.PrecipMM, f.PrecipHoursCounted)
+	}
+}
+
+// largeObservationsReader streams the observation fixture with its
+// members repeated n times, approximating a full bbox response without
+// holding it in memory, like an HTTP body.
+func largeObservationsReader(b *testing.B, n int) func() io.Reader {
+	b.Helper()
+	data, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		b.Fatal(err)
+	}
+	doc := string(data)
+	start := strings.Index(doc, "<wfs:member>")
+	end := strings.LastIndex(doc, "</wfs:member>") + len("</wfs:member>")
+	return func() io.Reader {
+		parts := []io.Reader{strings.NewReader(doc[:start])}
+		for range n {
+			parts = append(parts, strings.NewReader(doc[start:end]))
+		}
+		return io.MultiReader(append(parts, strings.NewReader(doc[end:]))...)
+	}
+}
+
+// reportPeakHeap samples the live heap while the benchmark runs and
+// reports its maximum as peak-heap-B.
+func reportPeakHeap(b *testing.B) (stop func()) {
+	b.Helper()
+	runtime.GC()
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	var peak uint64
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(100 * time.Microsecond)
+		defer ticker.Stop()
+		for {
+			metrics.Read(sample)
+			peak = max(peak, sample[0].Value.Uint64())
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+		b.ReportMetric(float64(peak), "peak-heap-B")
+	}
+}
+
+func BenchmarkParseObservations(b *testing.B) {
+	data, err := io.ReadAll(largeObservationsReader(b, 50)())
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	stop := reportPeakHeap(b)
+	for b.Loop() {
+		if _, err := ParseObservations(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+	stop()
+}
+
+func BenchmarkParseObservationsReader(b *testing.B) {
+	body := largeObservationsReader(b, 50)
+	b.ReportAllocs()
+	stop := reportPeakHeap(b)
+	for b.Loop() {
+		if _, err := ParseObservationsReader(body()); err != nil {
+			b.Fatal(err)
+		}
+	}
+	stop()
+}
+
+// forecastSeriesXML is hourlySeriesXML with a member for each parameter.
+func forecastSeriesXML(timezone string, from, to time.Time, series map[string]func(time.Time) float64) []byte {
+	var members strings.Builder
+	var doc string
+	for param, value := range series {
+		doc = string(hourlySeriesXML(timezone, param, from, to, value))
+		start := strings.Index(doc, "<wfs:member>")
+		members.WriteString(doc[start : strings.LastIndex(doc, "</wfs:member>")+len("</wfs:member>")])
+	}
+	start := strings.Index(doc, "<wfs:member>")
+	return []byte(doc[:start] + members.String() + "</wfs:FeatureCollection>")
+}
+
+func TestParseForecast_SplitsSnowFromRain(t *testing.T) {
+	helsinki, err := time.LoadLocation("Europe/Helsinki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Snow at -2 °C turns to rain at +2 °C at noon.
+	day := time.Date(2026, 1, 10, 0, 0, 0, 0, helsinki)
+	afternoon := func(t time.Time) bool { return t.In(helsinki).Hour() >= 12 }
+	data := forecastSeriesXML("Europe/Helsinki", day, day.AddDate(0, 0, 1), map[string]func(time.Time) float64{
+		"Precipitation1h": func(time.Time) float64 { return 1 },
+		"PrecipitationForm": func(t time.Time) float64 {
+			if afternoon(t) {
+				return 1
+			}
+			return 3
+		},
+		"Temperature": func(t time.Time) float64 {
+			if afternoon(t) {
+				return 2
+			}
+			return -2
+		},
+	})
+	result, _, err := ParseForecast(data, 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := result.Forecasts[0]
+	if f.PrecipMM == nil || *f.PrecipMM != 24 {
+		t.Fatalf("expected 24mm of precipitation, got %v", f.PrecipMM)
+	}
+	// Only the 12 snowy hours count, at 10mm of snow per mm of water.
+	if f.SnowAccumulationMM == nil || *f.SnowAccumulationMM != 120 {
+		t.Errorf("expected 120mm of snow, got %v", f.SnowAccumulationMM)
+	}
+
+	// Without precipitation data there is no estimate, unlike a day
+	// without snow.
+	result, _, err = ParseForecast(hourlyForecastXML("Europe/Helsinki", "Temperature", day, day.AddDate(0, 0, 1), -5), 60.17, 24.94, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := result.Forecasts[0]; f.SnowAccumulationMM != nil {
+		t.Errorf("expected no snow estimate, got %v", *f.SnowAccumulationMM)
+	}
+}
+
+// batchForecastXML is a forecast with an hourly Temperature member at each
+// position, "lat lon", holding that position's temperature.
+func batchForecastXML(from, to time.Time, temps map[string]float64) []byte {
+	var b strings.Builder
+	b.WriteString(`<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" xmlns:om="http://www.opengis.net/om/2.0" xmlns:omso="http://inspire.ec.europa.eu/schemas/omso/3.0" xmlns:sams="http://www.opengis.net/samplingSpatial/2.0" xmlns:sam="http://www.opengis.net/sampling/2.0" xmlns:wml2="http://www.opengis.net/waterml/2.0" xmlns:gml="http://www.opengis.net/gml/3.2" xmlns:target="http://xml.fmi.fi/namespace/om/atmosphericfeatures/1.1" xmlns:xlink="http://www.w3.org/1999/xlink">`)
+	for _, pos := range slices.Sorted(maps.Keys(temps)) {
+		b.WriteString(`<wfs:member><omso:PointTimeSeriesObservation>`)
+		b.WriteString(`<om:observedProperty xlink:href="https://opendata.fmi.fi/meta?observableProperty=forecast&amp;param=Temperature&amp;language=eng"/>`)
+		b.WriteString(`<om:featureOfInterest><sams:SF_SpatialSamplingFeature><sam:sampledFeature><target:LocationCollection><target:member><target:Location><target:timezone>Europe/Helsinki</target:timezone></target:Location></target:member></target:LocationCollection></sam:sampledFeature>`)
+		fmt.Fprintf(&b, `<sams:shape><gml:MultiPoint><gml:pointMembers><gml:Point><gml:pos>%s </gml:pos></gml:Point></gml:pointMembers></gml:MultiPoint></sams:shape>`, pos)
+		b.WriteString(`</sams:SF_SpatialSamplingFeature></om:featureOfInterest><om:result><wml2:MeasurementTimeseries>`)
+		for t := from; t.Before(to); t = t.Add(time.Hour) {
+			fmt.Fprintf(&b, `<wml2:point><wml2:MeasurementTVP><wml2:time>%s</wml2:time><wml2:value>%g</wml2:value></wml2:MeasurementTVP></wml2:point>`, t.UTC().Format(time.RFC3339), temps[pos])
+		}
+		b.WriteString(`</wml2:MeasurementTimeseries></om:result></omso:PointTimeSeriesObservation></wfs:member>`)
+	}
+	b.WriteString(`</wfs:FeatureCollection>`)
+	return []byte(b.String())
+}
+
+func TestParseForecastBatch_SplitsMembersByPosition(t *testing.T) {
+	from := time.Date(2026, 2, 15, 22, 0, 0, 0, time.UTC)
+	data := batchForecastXML(from, from.Add(24*time.Hour), map[string]float64{
+		"60.17000 24.94000": -3,
+		"61.49800 23.76000": -8,
+		"65.01000 25.47000": -15,
+	})
+	points := []LatLon{{Lat: 61.498, Lon: 23.76}, {Lat: 60.17, Lon: 24.94}, {Lat: 67.37, Lon: 26.63}}
+	results, summaries, err := ParseForecastBatch(data, points, DefaultForecastMinCoverage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || len(summaries) != 3 {
+		t.Fatalf("expected a result per point, got %d results and %d summaries", len(results), len(summaries))
+	}
+	for i, want := range []float64{-8, -3} {
+		f := results[i].Forecasts
+		if len(f) != 1 || f[0].TempAvg == nil || *f[0].TempAvg != want || f[0].GridLat != points[i].Lat || f[0].GridLon != points[i].Lon {
+			t.Errorf("point %v: expected one day averaging %g, got %+v", points[i], want, f)
+		}
+		if results[i].Timezone != "Europe/Helsinki" || len(results[i].Hourly) != 24 {
+			t.Errorf("point %v: expected 24 hours in Europe/Helsinki, got %d in %q", points[i], len(results[i].Hourly), results[i].Timezone)
+		}
+	}
+	// Oulu was not asked for, and Sodankylä is missing from the response.
+	if results[2].Forecasts != nil {
+		t.Errorf("expected no forecast for a point without a location, got %+v", results[2].Forecasts)
+	}
+}
@@ -0,0 +1,266 @@
+package fmi
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"wby/internal/weather"
+)
+
+const (
+	// radarLayer is FMI's Finland-wide radar reflectivity (dBZ) composite.
+	radarLayer = "Radar:suomi_dbz_eureffin"
+	// maxRadarImageBytes caps a GetMap response; a 2048x2048 composite is
+	// well under this.
+	maxRadarImageBytes = 8 << 20
+	// maxRadarCapabilitiesBytes caps a GetCapabilities response.
+	maxRadarCapabilitiesBytes = 16 << 20
+	// maxRadarTimes bounds how many timestamps one time dimension interval
+	// may expand to, a week of 5-minute composites with room to spare.
+	maxRadarTimes = 5000
+)
+
+// SetRadarURL sets the WMS endpoint RadarTimes and FetchRadarImage use.
+func (c *Client) SetRadarURL(u string) {
+	c.radarURL = u
+}
+
+// RadarTimes returns the timestamps the radar composite is available for,
+// read from the time dimension of the WMS capabilities.
+func (c *Client) RadarTimes(ctx context.Context) (_ []time.Time, err error) {
+	ctx, cancel := c.interactiveContext(ctx)
+	defer cancel()
+	stats := CallStats{Query: "wms::radar::capabilities"}
+	defer func(start time.Time) { c.observeFetch(stats, start, err) }(time.Now())
+
+	params := url.Values{
+		"service": {"WMS"},
+		"version": {"1.3.0"},
+		"request": {"GetCapabilities"},
+	}
+	data, _, err := c.wmsGet(ctx, params, maxRadarCapabilitiesBytes, &stats)
+	if err != nil {
+		return nil, fmt.Errorf("fetch radar capabilities: %w", err)
+	}
+	defer func(start time.Time) { stats.ParseDuration = time.Since(start) }(time.Now())
+	return ParseRadarTimes(data, radarLayer)
+}
+
+// FetchRadarImage renders the radar composite for req as a transparent PNG.
+func (c *Client) FetchRadarImage(ctx context.Context, req weather.RadarRequest) (_ []byte, err error) {
+	ctx, cancel := c.interactiveContext(ctx)
+	defer cancel()
+	stats := CallStats{Query: "wms::radar::map"}
+	defer func(start time.Time) { c.observeFetch(stats, start, err) }(time.Now())
+
+	// WMS 1.3.0 uses lat,lon axis order for EPSG:4326.
+	params := url.Values{
+		"service":     {"WMS"},
+		"version":     {"1.3.0"},
+		"request":     {"GetMap"},
+		"layers":      {radarLayer},
+		"styles":      {""},
+		"crs":         {"EPSG:4326"},
+		"bbox":        {fmt.Sprintf("%g,%g,%g,%g", req.BBox.MinLat, req.BBox.MinLon, req.BBox.MaxLat, req.BBox.MaxLon)},
+		"width":       {strconv.Itoa(req.Width)},
+		"height":      {strconv.Itoa(req.Height)},
+		"format":      {"image/png"},
+		"transparent": {"true"},
+		"time":        {req.Time.UTC().Format(time.RFC3339)},
+	}
+	data, header, err := c.wmsGet(ctx, params, maxRadarImageBytes, &stats)
+	if err != nil {
+		return nil, fmt.Errorf("fetch radar image: %w", err)
+	}
+	// WMS servers report errors as a 200 with an XML exception document.
+	if ct := header.Get("Content-Type"); !strings.HasPrefix(ct, "image/png") {
+		return nil, fmt.Errorf("radar WMS returned %s: %s", ct, truncate(data, 512))
+	}
+	return data, nil
+}
+
+// wmsGet requests params from the radar WMS, recording the response's
+// status and size in stats.
+func (c *Client) wmsGet(ctx context.Context, params url.Values, limit int64, stats *CallStats) ([]byte, http.Header, error) {
+	if c.radarURL == "" {
+		return nil, nil, errors.New("radar WMS URL not configured")
+	}
+	req, err := c.newRequest(ctx, c.radarURL+"?"+params.Encode())
+	if err != nil {
+		return nil, nil, fmt.Errorf("build request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	stats.Status = resp.StatusCode
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		stats.Bytes = int64(len(body))
+		return nil, nil, fmt.Errorf("WMS returned %d: %s", resp.StatusCode, string(body))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	stats.Bytes = int64(len(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, nil, fmt.Errorf("%w: over %d bytes", ErrResponseTooLarge, limit)
+	}
+	return data, resp.Header, nil
+}
+
+func truncate(data []byte, n int) string {
+	if len(data) > n {
+		data = data[:n]
+	}
+	return string(data)
+}
+
+type wmsLayer struct {
+	Name       string         `xml:"Name"`
+	Dimensions []wmsDimension `xml:"Dimension"`
+	Layers     []wmsLayer     `xml:"Layer"`
+}
+
+type wmsDimension struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ParseRadarTimes returns the sorted time dimension values of the named
+// layer in a WMS 1.3.0 capabilities document.
+func ParseRadarTimes(data []byte, layer string) ([]time.Time, error) {
+	var caps struct {
+		Layers []wmsLayer `xml:"Capability>Layer"`
+	}
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&caps); err != nil {
+		return nil, fmt.Errorf("parse WMS capabilities: %w", err)
+	}
+	l := findWMSLayer(caps.Layers, layer)
+	if l == nil {
+		return nil, fmt.Errorf("layer %s not in WMS capabilities", layer)
+	}
+	for _, d := range l.Dimensions {
+		if d.Name == "time" {
+			return parseTimeDimension(d.Value)
+		}
+	}
+	return nil, fmt.Errorf("layer %s has no time dimension", layer)
+}
+
+func findWMSLayer(layers []wmsLayer, name string) *wmsLayer {
+	// Namespaced endpoints may list the layer without its workspace prefix.
+	_, short, _ := strings.Cut(name, ":")
+	for i := range layers {
+		if layers[i].Name == name || layers[i].Name == short {
+			return &layers[i]
+		}
+		if l := findWMSLayer(layers[i].Layers, name); l != nil {
+			return l
+		}
+	}
+	return nil
+}
+
+// parseTimeDimension expands a WMS time dimension, a comma-separated list
+// of instants and start/end/period intervals, into sorted instants.
+func parseTimeDimension(raw string) ([]time.Time, error) {
+	var times []time.Time
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fields := strings.Split(part, "/")
+		switch len(fields) {
+		case 1:
+			t, err := time.Parse(time.RFC3339, part)
+			if err != nil {
+				return nil, fmt.Errorf("parse time %q: %w", part, err)
+			}
+			times = append(times, t.UTC())
+		case 3:
+			start, err := time.Parse(time.RFC3339, fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("parse interval start %q: %w", fields[0], err)
+			}
+			end, err := time.Parse(time.RFC3339, fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("parse interval end %q: %w", fields[1], err)
+			}
+			step, err := parseISODuration(fields[2])
+			if err != nil {
+				return nil, err
+			}
+			for t := start.UTC(); !t.After(end); t = t.Add(step) {
+				if len(times) >= maxRadarTimes {
+					return nil, fmt.Errorf("time dimension has more than %d values", maxRadarTimes)
+				}
+				times = append(times, t)
+			}
+		default:
+			return nil, fmt.Errorf("invalid time dimension value %q", part)
+		}
+	}
+	if len(times) == 0 {
+		return nil, errors.New("empty time dimension")
+	}
+	return times, nil
+}
+
+// parseISODuration parses the day and time parts of an ISO 8601 duration,
+// e.g. PT5M or P1DT12H. Years, months and weeks are not supported.
+func parseISODuration(raw string) (time.Duration, error) {
+	rest, ok := strings.CutPrefix(raw, "P")
+	if !ok || rest == "" {
+		return 0, fmt.Errorf("invalid duration %q", raw)
+	}
+	var d time.Duration
+	inTime := false
+	for rest != "" {
+		if rest[0] == 'T' {
+			inTime = true
+			rest = rest[1:]
+			continue
+		}
+		i := strings.IndexFunc(rest, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", raw)
+		}
+		n, err := strconv.ParseFloat(rest[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", raw)
+		}
+		var unit time.Duration
+		switch {
+		case rest[i] == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case rest[i] == 'H' && inTime:
+			unit = time.Hour
+		case rest[i] == 'M' && inTime:
+			unit = time.Minute
+		case rest[i] == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("unsupported duration %q", raw)
+		}
+		d += time.Duration(n * float64(unit))
+		rest = rest[i+1:]
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", raw)
+	}
+	return d, nil
+}
@@ -0,0 +1,101 @@
+package fmi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestParseRadarTimes(t *testing.T) {
+	data, err := os.ReadFile("testdata/radar_capabilities.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	times, err := ParseRadarTimes(data, radarLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 05:00 to 05:30 every 5 minutes, plus 05:45.
+	if len(times) != 8 {
+		t.Fatalf("expected 8 times, got %d: %v", len(times), times)
+	}
+	if want := time.Date(2026, 1, 15, 5, 0, 0, 0, time.UTC); !times[0].Equal(want) {
+		t.Errorf("first time = %v, want %v", times[0], want)
+	}
+	if want := time.Date(2026, 1, 15, 5, 45, 0, 0, time.UTC); !times[7].Equal(want) {
+		t.Errorf("last time = %v, want %v", times[7], want)
+	}
+
+	if _, err := ParseRadarTimes(data, "Radar:missing"); err == nil {
+		t.Error("expected an error for an unknown layer")
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want time.Duration
+	}{
+		{"PT5M", 5 * time.Minute},
+		{"PT1H30M", 90 * time.Minute},
+		{"P1DT12H", 36 * time.Hour},
+		{"PT0.5S", 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		got, err := parseISODuration(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("parseISODuration(%q) = %v, %v; want %v", tt.raw, got, err, tt.want)
+		}
+	}
+	for _, raw := range []string{"", "P", "5M", "P1M", "PT", "PT0M", "PTXM"} {
+		if _, err := parseISODuration(raw); err == nil {
+			t.Errorf("parseISODuration(%q): expected error", raw)
+		}
+	}
+}
+
+func TestFetchRadarImage(t *testing.T) {
+	png := []byte{0x89, 0x50, 0x4e, 0x47}
+	var gotQuery map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		if r.URL.Query().Get("time") == "2026-01-15T05:45:00Z" {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(png)
+			return
+		}
+		// GeoServer reports errors with status 200.
+		w.Header().Set("Content-Type", "application/vnd.ogc.se_xml")
+		w.Write([]byte(`<ServiceExceptionReport><ServiceException>Invalid time</ServiceException></ServiceExceptionReport>`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetRadarURL(srv.URL)
+	req := weather.RadarRequest{
+		BBox:  weather.BBox{MinLon: 24.5, MinLat: 60.0, MaxLon: 25.5, MaxLat: 60.5},
+		Width: 256, Height: 256,
+		Time: time.Date(2026, 1, 15, 5, 45, 0, 0, time.UTC),
+	}
+	got, err := c.FetchRadarImage(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(png) {
+		t.Errorf("unexpected image bytes %v", got)
+	}
+	if bbox := gotQuery["bbox"]; len(bbox) != 1 || bbox[0] != "60,24.5,60.5,25.5" {
+		t.Errorf("expected lat,lon axis order, got bbox %v", bbox)
+	}
+
+	req.Time = req.Time.Add(time.Minute)
+	if _, err := c.FetchRadarImage(context.Background(), req); err == nil {
+		t.Error("expected an error for a WMS exception document")
+	}
+}
@@ -0,0 +1,127 @@
+package fmi
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"wby/internal/weather"
+)
+
+// radiationTimestep is the spacing of the GLOB_1MIN samples requested; they
+// are averaged into hourly values.
+const radiationTimestep = 10 * time.Minute
+
+// FetchRadiationObservations returns the hourly mean global radiation
+// measured by the radiation stations in Finland between start and end.
+func (c *Client) FetchRadiationObservations(ctx context.Context, start, end time.Time) ([]weather.RadiationObservation, error) {
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
+	params := url.Values{
+		"service":        {"WFS"},
+		"version":        {"2.0.0"},
+		"request":        {"getFeature"},
+		"storedquery_id": {"fmi::observations::radiation::simple"},
+		"parameters":     {"GLOB_1MIN"},
+		"bbox":           {fmt.Sprintf("%g,%g,%g,%g", FinlandBBox.MinLon, FinlandBBox.MinLat, FinlandBBox.MaxLon, FinlandBBox.MaxLat)},
+		"timestep":       {strconv.Itoa(int(radiationTimestep / time.Minute))},
+		"starttime":      {start.UTC().Format(time.RFC3339)},
+		"endtime":        {end.UTC().Format(time.RFC3339)},
+	}
+
+	var observations []weather.RadiationObservation
+	err := c.fetchDecode(ctx, params, func(data []byte) (err error) {
+		observations, err = ParseRadiation(data)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch radiation observations: %w", err)
+	}
+	return observations, nil
+}
+
+// ParseRadiation parses a simple-feature (BsWfs) radiation response into
+// hourly means per station, sorted by station position and time. Samples
+// are assigned to the nearest whole hour, the way hourly forecast values
+// are stamped.
+func ParseRadiation(data []byte) ([]weather.RadiationObservation, error) {
+	type key struct {
+		pos  string
+		hour time.Time
+	}
+	type sum struct {
+		lat, lon float64
+		total    float64
+		n        int
+	}
+	sums := make(map[key]*sum)
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse radiation: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "BsWfsElement" {
+			continue
+		}
+		var el bsWfsElement
+		if err := dec.DecodeElement(&el, &start); err != nil {
+			return nil, fmt.Errorf("parse radiation element: %w", err)
+		}
+		if !strings.EqualFold(el.Name, "GLOB_1MIN") {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(el.Value), 64)
+		if err != nil || math.IsNaN(v) {
+			continue
+		}
+		t, lat, lon, err := el.point()
+		if err != nil {
+			return nil, fmt.Errorf("parse radiation: %w", err)
+		}
+
+		k := key{pos: strings.TrimSpace(el.Pos), hour: t.Round(time.Hour)}
+		acc := sums[k]
+		if acc == nil {
+			acc = &sum{lat: lat, lon: lon}
+			sums[k] = acc
+		}
+		acc.total += v
+		acc.n++
+	}
+
+	observations := make([]weather.RadiationObservation, 0, len(sums))
+	for k, acc := range sums {
+		observations = append(observations, weather.RadiationObservation{
+			Lat:             acc.lat,
+			Lon:             acc.lon,
+			Time:            k.hour,
+			RadiationGlobal: acc.total / float64(acc.n),
+		})
+	}
+	slices.SortFunc(observations, func(a, b weather.RadiationObservation) int {
+		if c := cmp.Compare(a.Lat, b.Lat); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.Lon, b.Lon); c != 0 {
+			return c
+		}
+		return a.Time.Compare(b.Time)
+	})
+	return observations, nil
+}
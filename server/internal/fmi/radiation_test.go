@@ -0,0 +1,42 @@
+package fmi
+
+import (
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseRadiation(t *testing.T) {
+	data, err := os.ReadFile("testdata/radiation.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	observations, err := ParseRadiation(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(observations) != 3 {
+		t.Fatalf("expected 3 hourly values, got %d: %+v", len(observations), observations)
+	}
+
+	// Samples from 09:50 to 10:20 average into 10:00, skipping the NaN;
+	// 10:40 rounds to 11:00.
+	first, second, other := observations[0], observations[1], observations[2]
+	if first.Lat != 60.20307 || first.Lon != 24.96131 {
+		t.Errorf("unexpected position %f, %f", first.Lat, first.Lon)
+	}
+	if want := time.Date(2026, 6, 20, 10, 0, 0, 0, time.UTC); !first.Time.Equal(want) {
+		t.Errorf("first hour %v, want %v", first.Time, want)
+	}
+	if math.Abs(first.RadiationGlobal-640) > 1e-9 {
+		t.Errorf("expected mean 640 W/m², got %v", first.RadiationGlobal)
+	}
+	if second.Time.Hour() != 11 || second.RadiationGlobal != 120 {
+		t.Errorf("unexpected second hour %+v", second)
+	}
+	if other.Lat != 61.84 || other.RadiationGlobal != 88.5 {
+		t.Errorf("unexpected second station %+v", other)
+	}
+}
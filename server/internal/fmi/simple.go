@@ -0,0 +1,93 @@
+package fmi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"wby/internal/weather"
+)
+
+// simpleObservationsQuery is the BsWfs ("simple feature") variant of the
+// observation stored query, one element per station, time and parameter.
+const simpleObservationsQuery = "fmi::observations::weather::simple"
+
+// minSimpleFallbackSize is the smallest response that is assumed to hold
+// observations: an empty FeatureCollection is its namespace declarations,
+// well under this.
+const minSimpleFallbackSize = 4 << 10
+
+// ParseObservationsSimple parses a BsWfs observation response. Its elements
+// carry a position but no station ID, so they are attributed to stations
+// by position; elements at positions not in stations are skipped.
+func ParseObservationsSimple(data []byte, stations []weather.Station) (*ObservationResult, error) {
+	return ParseObservationsSimpleReader(bytes.NewReader(data), stations)
+}
+
+// ParseObservationsSimpleReader is ParseObservationsSimple reading from r.
+func ParseObservationsSimpleReader(r io.Reader, stations []weather.Station) (*ObservationResult, error) {
+	stationsAt := make(map[string]weather.Station, len(stations))
+	for _, s := range stations {
+		stationsAt[coordinateKey(s.Lat, s.Lon)] = s
+	}
+
+	b := newObservationBuilder()
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse simple observations: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "BsWfsElement" {
+			continue
+		}
+		var el bsWfsElement
+		if err := dec.DecodeElement(&el, &start); err != nil {
+			return nil, fmt.Errorf("parse simple observations element: %w", err)
+		}
+
+		station, ok := stationsAt[simplePositionKey(el.Pos)]
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(el.Time))
+		if err != nil {
+			continue
+		}
+		b.addStation(station)
+		b.set(station.FMISID, t, strings.ToLower(strings.TrimSpace(el.Name)), parseFloat(strings.TrimSpace(el.Value)))
+	}
+	return b.result(), nil
+}
+
+// simplePositionKey is coordinateKey for a gml:pos of "lat lon".
+func simplePositionKey(pos string) string {
+	fields := strings.Fields(pos)
+	if len(fields) < 2 {
+		return ""
+	}
+	lat, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return ""
+	}
+	lon, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return ""
+	}
+	return coordinateKey(lat, lon)
+}
+
+// coordinateKey identifies a station by its coordinates, rounded so a
+// position printed with fewer decimals still matches.
+func coordinateKey(lat, lon float64) string {
+	return fmt.Sprintf("%.4f %.4f", lat, lon)
+}
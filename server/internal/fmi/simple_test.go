@@ -0,0 +1,105 @@
+package fmi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseObservationsSimple_MatchesTimeValuePair(t *testing.T) {
+	tvp, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	simple, err := os.ReadFile("testdata/observations_simple.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ParseObservations(tvp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseObservationsSimple(simple, want.Stations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Stations, want.Stations) {
+		t.Errorf("stations differ:\n got %+v\nwant %+v", got.Stations, want.Stations)
+	}
+	// The fixture holds the first six times of observations.xml.
+	if len(got.Observations) != 6 {
+		t.Fatalf("expected 6 observations, got %d", len(got.Observations))
+	}
+	for i, obs := range got.Observations {
+		if !reflect.DeepEqual(obs, want.Observations[i]) {
+			t.Fatalf("observation %d differs:\n got %+v\nwant %+v", i, obs, want.Observations[i])
+		}
+	}
+
+	unknown, err := ParseObservationsSimple(simple, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unknown.Stations) != 0 || len(unknown.Observations) != 0 {
+		t.Fatalf("expected elements of unknown stations to be skipped, got %+v", unknown)
+	}
+}
+
+func TestClient_FetchObservationsFallsBackToSimple(t *testing.T) {
+	tvp, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	simple, err := os.ReadFile("testdata/observations_simple.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Members FMI renamed: a full response the timevaluepair parser finds
+	// nothing in.
+	renamed := strings.ReplaceAll(string(tvp), "PointTimeSeriesObservation", "PointTimeSeriesObservation2")
+
+	var queries []string
+	body := string(tvp)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("storedquery_id")
+		queries = append(queries, query)
+		if query == simpleObservationsQuery {
+			w.Write(simple)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	if _, err := c.FetchObservations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	body = renamed
+	result, err := c.FetchObservations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Stations) != 1 || len(result.Observations) != 6 {
+		t.Fatalf("expected the simple format's observations, got %d stations and %d observations", len(result.Stations), len(result.Observations))
+	}
+	want := []string{"fmi::observations::weather::timevaluepair", "fmi::observations::weather::timevaluepair", simpleObservationsQuery}
+	if !reflect.DeepEqual(queries, want) {
+		t.Fatalf("expected queries %v, got %v", want, queries)
+	}
+
+	// An empty collection is not a format change.
+	queries = nil
+	body = `<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" numberMatched="0" numberReturned="0"></wfs:FeatureCollection>`
+	if _, err := c.FetchObservations(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 1 {
+		t.Fatalf("expected no fallback for an empty response, got %v", queries)
+	}
+}
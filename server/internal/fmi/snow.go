@@ -0,0 +1,55 @@
+package fmi
+
+import "math"
+
+// FMI precipitation form codes, as sent for PrecipitationForm and
+// PotentialPrecipitationForm.
+const (
+	precipFormSleet = 2
+	precipFormSnow  = 3
+)
+
+// snowDepthMM estimates the fresh snow, in mm of depth, from liquid mm of
+// precipitation falling in form at temp °C. Either may be nil: without a
+// form, the precipitation is taken as snow at or below freezing.
+func snowDepthMM(liquid float64, form, temp *float64) float64 {
+	if liquid <= 0 {
+		return 0
+	}
+	return liquid * snowShare(form, temp) * snowRatio(temp)
+}
+
+// snowShare is the part of the precipitation that falls as snow: all of it
+// for snow, half for sleet and none for rain, drizzle or hail.
+func snowShare(form, temp *float64) float64 {
+	if form == nil {
+		if temp != nil && *temp <= 0 {
+			return 1
+		}
+		return 0
+	}
+	switch int(math.Round(*form)) {
+	case precipFormSnow:
+		return 1
+	case precipFormSleet:
+		return 0.5
+	}
+	return 0
+}
+
+// snowRatio is how many mm of snow a mm of water makes at temp °C: wet
+// snow near freezing packs densely, cold powder is fluffier. Without a
+// temperature it is the common 10:1.
+func snowRatio(temp *float64) float64 {
+	switch {
+	case temp == nil:
+		return 10
+	case *temp > 0:
+		return 7
+	case *temp > -5:
+		return 10
+	case *temp > -10:
+		return 15
+	}
+	return 20
+}
@@ -0,0 +1,33 @@
+package fmi
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSnowDepthMM(t *testing.T) {
+	ptr := func(v float64) *float64 { return &v }
+	for _, c := range []struct {
+		name       string
+		liquid     float64
+		form, temp *float64
+		want       float64
+	}{
+		{"wet snow", 2, ptr(precipFormSnow), ptr(0.5), 14},
+		{"snow", 2, ptr(precipFormSnow), ptr(-3), 20},
+		{"cold snow", 2, ptr(precipFormSnow), ptr(-7), 30},
+		{"powder", 2, ptr(precipFormSnow), ptr(-15), 40},
+		{"sleet", 2, ptr(precipFormSleet), ptr(0.5), 7},
+		{"rain", 2, ptr(1), ptr(-1), 0},
+		{"freezing rain", 2, ptr(5), ptr(-1), 0},
+		{"no form below freezing", 2, nil, ptr(-1), 20},
+		{"no form above freezing", 2, nil, ptr(1), 0},
+		{"no form or temperature", 2, nil, nil, 0},
+		{"snow without temperature", 2, ptr(precipFormSnow), nil, 20},
+		{"dry", 0, ptr(precipFormSnow), ptr(-3), 0},
+	} {
+		if got := snowDepthMM(c.liquid, c.form, c.temp); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("%s: got %g, want %g", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package fmi
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// DefaultInteractiveTimeout bounds a call that blocks a user request:
+	// forecasts, UV and radar.
+	DefaultInteractiveTimeout = 8 * time.Second
+	// DefaultBulkTimeout bounds a background call such as the observation
+	// fetch, which downloads far more.
+	DefaultBulkTimeout = 60 * time.Second
+)
+
+// SetTimeouts bounds each call, retries included, at interactive for calls
+// that block a user request and bulk for background fetches. Timeouts below
+// 1 keep the defaults.
+func (c *Client) SetTimeouts(interactive, bulk time.Duration) {
+	if interactive > 0 {
+		c.interactiveTimeout = interactive
+	}
+	if bulk > 0 {
+		c.bulkTimeout = bulk
+	}
+}
+
+// interactiveContext bounds ctx for a call that blocks a user request. A
+// shorter deadline already on ctx wins.
+func (c *Client) interactiveContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.interactiveTimeout)
+}
+
+// bulkContext bounds ctx for a background call.
+func (c *Client) bulkContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.bulkTimeout)
+}
@@ -0,0 +1,61 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_TimeoutsByCallType(t *testing.T) {
+	forecast, err := os.ReadFile("fixtures/forecast.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		if strings.Contains(r.URL.Query().Get("storedquery_id"), "forecast") {
+			w.Write(forecast)
+			return
+		}
+		w.Write(observations)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	c.SetRetry(0, time.Millisecond)
+	c.SetTimeouts(20*time.Millisecond, 5*time.Second)
+
+	_, err = c.FetchForecast(context.Background(), 60.17, 24.94, 3)
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("expected the slow forecast to time out as an outage, got %v", err)
+	}
+
+	result, err := c.FetchObservations(context.Background())
+	if err != nil {
+		t.Fatalf("expected the slow observation fetch to finish within the bulk timeout, got %v", err)
+	}
+	if len(result.Observations) == 0 {
+		t.Fatal("expected observations")
+	}
+}
+
+func TestClient_SetTimeoutsKeepsDefaults(t *testing.T) {
+	c := NewClient("", "", "")
+	c.SetTimeouts(0, -time.Second)
+	if c.interactiveTimeout != DefaultInteractiveTimeout || c.bulkTimeout != DefaultBulkTimeout {
+		t.Fatalf("expected the defaults, got %s and %s", c.interactiveTimeout, c.bulkTimeout)
+	}
+}
@@ -0,0 +1,281 @@
+package fmi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+func TestParseObservationsTimeseries_MatchesTimeValuePair(t *testing.T) {
+	tvp, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, err := os.ReadFile("testdata/observations_timeseries.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ts)*10 > len(tvp) {
+		t.Errorf("expected the JSON to be an order of magnitude smaller, got %d bytes against %d", len(ts), len(tvp))
+	}
+
+	want, err := ParseObservations(tvp)
+	if err != nil {
+		t.Fatalf("parse timevaluepair: %v", err)
+	}
+	got, err := ParseObservationsTimeseries(ts, observationParameters)
+	if err != nil {
+		t.Fatalf("parse timeseries: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Stations, want.Stations) {
+		t.Errorf("stations differ:\n got %+v\nwant %+v", got.Stations, want.Stations)
+	}
+	if len(got.Observations) != len(want.Observations) || len(got.Observations) == 0 {
+		t.Fatalf("expected %d observations, got %d", len(want.Observations), len(got.Observations))
+	}
+	for i := range want.Observations {
+		if !reflect.DeepEqual(got.Observations[i], want.Observations[i]) {
+			t.Fatalf("observation %d differs:\n got %+v\nwant %+v", i, got.Observations[i], want.Observations[i])
+		}
+	}
+}
+
+func TestParseObservationsTimeseries_MissingValues(t *testing.T) {
+	result, err := ParseObservationsTimeseries([]byte(`[
+{"fmisid":101004,"wmo":null,"stationname":"Helsinki Kumpula","region":"Helsinki","latitude":60.20307,"longitude":24.96131,"epochtime":1771221000,"t2m":"-7.9","ws_10min":"nan"},
+{"fmisid":101004,"wmo":null,"stationname":"Helsinki Kumpula","region":"Helsinki","latitude":60.20307,"longitude":24.96131,"epochtime":1771221600,"t2m":null,"ws_10min":null}
+]`), []string{"t2m", "ws_10min"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Stations) != 1 || result.Stations[0].WMOCode != "" || result.Stations[0].Name != "Helsinki Kumpula" {
+		t.Fatalf("unexpected stations %+v", result.Stations)
+	}
+	if len(result.Observations) != 1 {
+		t.Fatalf("expected the all-null row to be dropped, got %d observations", len(result.Observations))
+	}
+	obs := result.Observations[0]
+	if obs.Temperature == nil || *obs.Temperature != -7.9 || obs.WindSpeed != nil {
+		t.Fatalf("expected a quoted temperature and no wind, got %v/%v", obs.Temperature, obs.WindSpeed)
+	}
+
+	if _, err := ParseObservationsTimeseries([]byte(`{"error":"bad request"}`), observationParameters); err == nil {
+		t.Fatal("expected an error for a response that is not an array")
+	}
+}
+
+func TestClient_FetchObservationsFromTimeseries(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/observations_timeseries.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var query string
+	wfs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected WFS request")
+	}))
+	defer wfs.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fmi-apikey/secret/timeseries" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		query = r.URL.RawQuery
+		w.Write(fixture)
+	}))
+	defer ts.Close()
+
+	c := NewClient(wfs.URL, "secret", ts.URL)
+	c.now = func() time.Time { return time.Date(2026, 2, 16, 6, 5, 0, 0, time.UTC) }
+	result, err := c.FetchObservations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Stations) != 1 || result.Stations[0].Name != "Helsinki Kaisaniemi" || result.Stations[0].Lat == 0 {
+		t.Fatalf("expected a named station with coordinates, got %+v", result.Stations)
+	}
+	if len(result.Observations) == 0 {
+		t.Fatal("expected observations")
+	}
+	for _, want := range []string{
+		"param=fmisid%2Cwmo%2Cstationname%2Cregion%2Clatitude%2Clongitude%2Cepochtime%2Ct2m%2C",
+		"bbox=19%2C59%2C32%2C71",
+		"starttime=2026-02-16T05%3A00%3A00Z",
+		"producer=observations_fmi",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("expected %q in query %s", want, query)
+		}
+	}
+}
+
+func TestClient_FetchObservationsFallsBackToWFS(t *testing.T) {
+	observations, err := os.ReadFile("fixtures/observations.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wfs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(observations)
+	}))
+	defer wfs.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	c := NewClient(wfs.URL, "secret", ts.URL)
+	c.SetRetry(1, time.Millisecond)
+	result, err := c.FetchObservations(context.Background())
+	if err != nil {
+		t.Fatalf("expected the WFS fallback to succeed, got %v", err)
+	}
+	if len(result.Observations) == 0 {
+		t.Fatal("expected observations from WFS")
+	}
+}
+
+func TestParseUVForecast(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		points   int
+		apiError bool
+		wantErr  bool
+	}{
+		{name: "array", body: `[{"epochtime":1781517600,"uvCumulated":2.5},{"epochtime":1781521200,"uvCumulated":null}]`, points: 1},
+		{name: "empty array", body: `[]`},
+		{name: "error object", body: `{"error":"Unknown parameter 'uvCumulated'"}`, apiError: true, wantErr: true},
+		{name: "empty body", body: " \n", wantErr: true},
+		{name: "malformed", body: `[{"epochtime":1781517600,`, wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			points, err := parseUVForecast(strings.NewReader(tc.body))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			var apiErr *APIError
+			if errors.As(err, &apiErr) != tc.apiError {
+				t.Fatalf("expected an APIError %v, got %v", tc.apiError, err)
+			}
+			if len(points) != tc.points {
+				t.Fatalf("expected %d points, got %d", tc.points, len(points))
+			}
+		})
+	}
+}
+
+func TestClient_FetchUVForecast(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[{"epochtime":1781517600,"uvCumulated":2.5}]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("", "secret", srv.URL)
+	c.SetRetry(3, time.Millisecond)
+	points, err := c.FetchUVForecast(context.Background(), 60.17, 24.94)
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if len(points) != 1 || points[0].UVCumulated != 2.5 || requests.Load() != 2 {
+		t.Fatalf("expected one point after 2 requests, got %+v after %d", points, requests.Load())
+	}
+}
+
+func TestClient_FetchUVForecastRejected(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"Unknown producer 'uv'"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("", "secret", srv.URL)
+	c.SetRetry(3, time.Millisecond)
+	_, err := c.FetchUVForecast(context.Background(), 60.17, 24.94)
+	if !errors.Is(err, weather.ErrUpstreamRejected) {
+		t.Fatalf("expected a rejected query, got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected a rejected query not to be retried, got %d requests", got)
+	}
+}
+
+func TestClient_SendsUserAgent(t *testing.T) {
+	var agents []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents = append(agents, r.UserAgent())
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("", "secret", srv.URL)
+	if _, err := c.FetchUVForecast(context.Background(), 60.17, 24.94); err != nil {
+		t.Fatal(err)
+	}
+	c.SetUserAgent("wby-staging/1.0")
+	if _, err := c.FetchUVForecast(context.Background(), 60.17, 24.94); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"wby/dev", "wby-staging/1.0"}; !reflect.DeepEqual(agents, want) {
+		t.Fatalf("expected User-Agents %q, got %q", want, agents)
+	}
+}
+
+func TestClient_APIKeyHeader(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/proxy/timeseries" || r.Header.Get("fmi-apikey") != "secret" {
+			t.Errorf("expected the key in a header, got %s with %q", r.URL.Path, r.Header.Get("fmi-apikey"))
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("", "secret", srv.URL+"/proxy")
+	if err := c.SetAPIKeyMode(APIKeyHeader); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FetchUVForecast(context.Background(), 60.17, 24.94); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetAPIKeyMode("cookie"); err == nil {
+		t.Fatal("expected an unknown mode to be rejected")
+	}
+}
+
+func TestClient_RedactsAPIKey(t *testing.T) {
+	echo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no route for "+r.URL.Path, http.StatusBadGateway)
+	}))
+	defer echo.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	for name, base := range map[string]string{"error page": echo.URL, "unreachable": down.URL} {
+		t.Run(name, func(t *testing.T) {
+			c := NewClient("", "s3cr3t-key", base)
+			c.SetRetry(1, time.Millisecond)
+			_, err := c.FetchUVForecast(context.Background(), 60.17, 24.94)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if strings.Contains(err.Error(), "s3cr3t-key") || !strings.Contains(err.Error(), "REDACTED") {
+				t.Fatalf("expected the key to be redacted, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package fmi
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"wby/internal/weather"
+)
+
+// SetWarningsURL sets the CAP feed FetchWarnings reads; empty disables it.
+func (c *Client) SetWarningsURL(u string) {
+	c.warningsURL = u
+}
+
+// FetchWarnings returns the warnings currently published in the CAP feed.
+// It returns nil without a request when no feed is configured.
+func (c *Client) FetchWarnings(ctx context.Context) (_ []weather.Warning, err error) {
+	if c.warningsURL == "" {
+		return nil, nil
+	}
+	ctx, cancel := c.bulkContext(ctx)
+	defer cancel()
+	stats := CallStats{Query: "cap::warnings"}
+	defer func(start time.Time) { c.observeFetch(stats, start, err) }(time.Now())
+
+	req, err := c.newRequest(ctx, c.warningsURL)
+	if err != nil {
+		return nil, fmt.Errorf("build warnings request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch warnings: %w", err)
+	}
+	defer resp.Body.Close()
+	stats.Status = resp.StatusCode
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorQuoteSize))
+		stats.Bytes = int64(len(body))
+		return nil, fmt.Errorf("warnings feed returned %d: %s", resp.StatusCode, string(body))
+	}
+	data, err := io.ReadAll(newCappedReader(resp.Body, c.maxResponseSize))
+	stats.Bytes = int64(len(data))
+	if err != nil {
+		return nil, fmt.Errorf("read warnings: %w", err)
+	}
+	defer func(start time.Time) { stats.ParseDuration = time.Since(start) }(time.Now())
+	return ParseWarnings(data)
+}
+
+type capAlert struct {
+	Identifier string    `xml:"identifier"`
+	Status     string    `xml:"status"`
+	MsgType    string    `xml:"msgType"`
+	Infos      []capInfo `xml:"info"`
+}
+
+type capInfo struct {
+	Language    string    `xml:"language"`
+	Event       string    `xml:"event"`
+	Severity    string    `xml:"severity"`
+	Onset       string    `xml:"onset"`
+	Effective   string    `xml:"effective"`
+	Expires     string    `xml:"expires"`
+	Headline    string    `xml:"headline"`
+	Description string    `xml:"description"`
+	Areas       []capArea `xml:"area"`
+}
+
+type capArea struct {
+	AreaDesc string   `xml:"areaDesc"`
+	Polygons []string `xml:"polygon"`
+}
+
+// ParseWarnings extracts the CAP alerts in data, either a bare <alert> or
+// an Atom feed with alerts embedded in its entries. Each alert uses its
+// English info block when there is one. Alerts that are not actual, are
+// cancellations, or lack a polygon or expiry are skipped, as they cannot be
+// matched to a point or a time.
+func ParseWarnings(data []byte) ([]weather.Warning, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var warnings []weather.Warning
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return warnings, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse warnings: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "alert" {
+			continue
+		}
+		var alert capAlert
+		if err := dec.DecodeElement(&alert, &start); err != nil {
+			return nil, fmt.Errorf("parse CAP alert: %w", err)
+		}
+		if w, ok := alert.warning(); ok {
+			warnings = append(warnings, w)
+		}
+	}
+}
+
+func (a capAlert) warning() (weather.Warning, bool) {
+	if a.Status != "Actual" || a.MsgType == "Cancel" || len(a.Infos) == 0 {
+		return weather.Warning{}, false
+	}
+	info := a.Infos[0]
+	for _, candidate := range a.Infos {
+		if strings.HasPrefix(candidate.Language, "en") {
+			info = candidate
+			break
+		}
+	}
+
+	expires, err := time.Parse(time.RFC3339, strings.TrimSpace(info.Expires))
+	if err != nil {
+		return weather.Warning{}, false
+	}
+	onsetRaw := info.Onset
+	if onsetRaw == "" {
+		onsetRaw = info.Effective
+	}
+	onset, err := time.Parse(time.RFC3339, strings.TrimSpace(onsetRaw))
+	if err != nil {
+		return weather.Warning{}, false
+	}
+
+	w := weather.Warning{
+		ID:          strings.TrimSpace(a.Identifier),
+		Event:       strings.TrimSpace(info.Event),
+		Severity:    strings.TrimSpace(info.Severity),
+		Headline:    strings.TrimSpace(info.Headline),
+		Description: strings.TrimSpace(info.Description),
+		Onset:       onset.UTC(),
+		Expires:     expires.UTC(),
+	}
+	var areaNames []string
+	for _, area := range info.Areas {
+		var found bool
+		for _, raw := range area.Polygons {
+			if ring := parseCAPPolygon(raw); ring != nil {
+				w.Polygons = append(w.Polygons, ring)
+				found = true
+			}
+		}
+		if found && area.AreaDesc != "" {
+			areaNames = append(areaNames, strings.TrimSpace(area.AreaDesc))
+		}
+	}
+	w.AreaDesc = strings.Join(areaNames, ", ")
+	if w.ID == "" || len(w.Polygons) == 0 {
+		return weather.Warning{}, false
+	}
+	return w, true
+}
+
+// parseCAPPolygon parses a CAP polygon, space-separated "lat,lon" pairs
+// with the first pair repeated at the end. It returns nil for anything
+// that is not a closed ring of at least four points.
+func parseCAPPolygon(raw string) [][2]float64 {
+	var ring [][2]float64
+	for _, pair := range strings.Fields(raw) {
+		latRaw, lonRaw, ok := strings.Cut(pair, ",")
+		if !ok {
+			return nil
+		}
+		lat, err := strconv.ParseFloat(latRaw, 64)
+		if err != nil {
+			return nil
+		}
+		lon, err := strconv.ParseFloat(lonRaw, 64)
+		if err != nil {
+			return nil
+		}
+		ring = append(ring, [2]float64{lat, lon})
+	}
+	if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+		return nil
+	}
+	return ring
+}
@@ -0,0 +1,107 @@
+package fmi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseWarnings(t *testing.T) {
+	data, err := os.ReadFile("testdata/warnings.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	warnings, err := ParseWarnings(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The cancellation and the exercise are skipped.
+	if len(warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %d", len(warnings))
+	}
+
+	wind := warnings[0]
+	if wind.ID != "2.49.0.1.246.0.0.2026.1.15.1" {
+		t.Errorf("unexpected id %q", wind.ID)
+	}
+	if wind.Event != "Wind warning" || wind.Headline != "Wind warning for sea areas" {
+		t.Errorf("expected the English info block, got event %q headline %q", wind.Event, wind.Headline)
+	}
+	if wind.Severity != "Moderate" {
+		t.Errorf("unexpected severity %q", wind.Severity)
+	}
+	if wind.Description != "Mean wind speed 15-18 m/s." {
+		t.Errorf("unexpected description %q", wind.Description)
+	}
+	if want := time.Date(2026, 1, 15, 6, 0, 0, 0, time.UTC); !wind.Onset.Equal(want) {
+		t.Errorf("onset = %v, want %v", wind.Onset, want)
+	}
+	if want := time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC); !wind.Expires.Equal(want) {
+		t.Errorf("expires = %v, want %v", wind.Expires, want)
+	}
+	if wind.AreaDesc != "Helsinki, Espoo" {
+		t.Errorf("unexpected area %q", wind.AreaDesc)
+	}
+	if len(wind.Polygons) != 2 || len(wind.Polygons[0]) != 5 {
+		t.Fatalf("expected two 5-point rings, got %v", wind.Polygons)
+	}
+	if wind.Polygons[0][1] != [2]float64{60.30, 24.80} {
+		t.Errorf("unexpected polygon point %v", wind.Polygons[0][1])
+	}
+
+	// Without onset, the effective time is used.
+	traffic := warnings[1]
+	if want := time.Date(2026, 1, 15, 6, 0, 0, 0, time.UTC); !traffic.Onset.Equal(want) {
+		t.Errorf("onset = %v, want %v", traffic.Onset, want)
+	}
+}
+
+func TestParseCAPPolygon(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want int
+	}{
+		{"closed ring", "60,24 61,24 61,25 60,24", 4},
+		{"open ring", "60,24 61,24 61,25 60,25", 0},
+		{"too short", "60,24 61,24 60,24", 0},
+		{"malformed pair", "60,24 61 61,25 60,24", 0},
+		{"empty", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCAPPolygon(tt.raw); len(got) != tt.want {
+				t.Errorf("got %d points, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchWarnings(t *testing.T) {
+	data, err := os.ReadFile("testdata/warnings.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "", "")
+	if got, err := c.FetchWarnings(context.Background()); err != nil || got != nil {
+		t.Fatalf("expected no request without a feed URL, got %v, %v", got, err)
+	}
+
+	c.SetWarningsURL(srv.URL)
+	got, err := c.FetchWarnings(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 warnings, got %d", len(got))
+	}
+}
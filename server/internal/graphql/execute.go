@@ -0,0 +1,504 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response is a GraphQL result. Data is absent when the request failed
+// before execution, e.g. on a syntax or validation error.
+type Response struct {
+	Data   any      `json:"data,omitempty"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+type Error struct {
+	Message   string     `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+	Path      []any      `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Limits on a query's shape, checked before anything is resolved. Depth
+// counts nested selection sets; the standard introspection query needs
+// about a dozen. Fields are counted with every fragment spread expanded.
+const (
+	maxQueryDepth  = 20
+	maxQueryFields = 2000
+)
+
+type execution struct {
+	ctx       context.Context
+	schema    *Schema
+	variables map[string]any
+	errors    []*Error
+	// fragmentShapes remembers the shape of each fragment, so a fragment
+	// spread many times is only walked once.
+	fragmentShapes map[string]queryShape
+}
+
+// queryShape is the size of a selection set: how many fields it expands
+// to, at most maxQueryFields+1, and how deeply its selections nest.
+type queryShape struct {
+	fields, depth int
+}
+
+func (s queryShape) add(o queryShape) queryShape {
+	return queryShape{fields: min(s.fields+o.fields, maxQueryFields+1), depth: max(s.depth, o.depth)}
+}
+
+// Execute runs a query. Resolver errors become GraphQL errors on the field
+// that failed, which resolves to null; they never fail the whole request.
+func (s *Schema) Execute(ctx context.Context, req Request) Response {
+	doc, err := parser.ParseQuery(&ast.Source{Input: req.Query})
+	if err != nil {
+		return Response{Errors: []*Error{asError(err)}}
+	}
+	if errs := validator.Validate(s.schema, doc); len(errs) > 0 {
+		return Response{Errors: fromList(errs)}
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return Response{Errors: []*Error{asError(err)}}
+	}
+
+	e := &execution{ctx: ctx, schema: s, fragmentShapes: map[string]queryShape{}}
+	if e.variables, err = coerceVariables(s.schema, op, req.Variables); err != nil {
+		return Response{Errors: []*Error{asError(err)}}
+	}
+	shape := e.shape(op.SelectionSet)
+	switch {
+	case shape.depth > maxQueryDepth:
+		return Response{Errors: []*Error{{Message: fmt.Sprintf("query is nested more than %d levels deep", maxQueryDepth)}}}
+	case shape.fields > maxQueryFields:
+		return Response{Errors: []*Error{{Message: fmt.Sprintf("query selects more than %d fields", maxQueryFields)}}}
+	}
+
+	groups := e.collect(op.SelectionSet)
+	if err := s.checkRootFields(groups); err != nil {
+		return Response{Errors: []*Error{err}}
+	}
+	data := &orderedMap{}
+	for _, group := range groups {
+		key := group[0].Alias
+		data.set(key, e.resolveRoot(ctx, group, []any{key}))
+	}
+	if err := ctx.Err(); err != nil {
+		return Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	return Response{Data: data, Errors: e.errors}
+}
+
+// checkRootFields enforces the root fields' MaxPerQuery.
+func (s *Schema) checkRootFields(groups [][]*ast.Field) *Error {
+	counts := map[string]int{}
+	for _, group := range groups {
+		f := group[0]
+		counts[f.Name]++
+		if root, ok := s.roots[f.Name]; ok && root.MaxPerQuery > 0 && counts[f.Name] > root.MaxPerQuery {
+			return &Error{Message: fmt.Sprintf("a query may select %q at most %d times", f.Name, root.MaxPerQuery), Locations: locations(f.Position)}
+		}
+	}
+	return nil
+}
+
+func asError(err error) *Error {
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) {
+		return fromGQLError(gqlErr)
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return &Error{Message: err.Error()}
+}
+
+func fromList(errs gqlerror.List) []*Error {
+	out := make([]*Error, len(errs))
+	for i, err := range errs {
+		out[i] = fromGQLError(err)
+	}
+	return out
+}
+
+// fromGQLError converts a gqlparser error. Its path, set on variable
+// errors, is folded into the message, as it does not point into data.
+func fromGQLError(err *gqlerror.Error) *Error {
+	e := &Error{Message: err.Message}
+	if len(err.Path) > 0 {
+		e.Message = fmt.Sprintf("%s %s", err.Path, err.Message)
+	}
+	for _, loc := range err.Locations {
+		e.Locations = append(e.Locations, Location{Line: loc.Line, Column: loc.Column})
+	}
+	return e
+}
+
+func locations(pos *ast.Position) []Location {
+	if pos == nil {
+		return nil
+	}
+	return []Location{{Line: pos.Line, Column: pos.Column}}
+}
+
+func selectOperation(doc *ast.QueryDocument, name string) (*ast.OperationDefinition, error) {
+	op := doc.Operations.ForName(name)
+	switch {
+	case op == nil && name != "":
+		return nil, &Error{Message: fmt.Sprintf("unknown operation %q", name)}
+	case op == nil:
+		return nil, &Error{Message: "operationName is required when the document has several operations"}
+	case op.Operation != ast.Query:
+		return nil, &Error{Message: fmt.Sprintf("%s operations are not supported", op.Operation), Locations: locations(op.Position)}
+	}
+	return op, nil
+}
+
+// coerceVariables validates the provided variables with gqlparser, then
+// converts them to the Go types resolvers receive.
+func coerceVariables(schema *ast.Schema, op *ast.OperationDefinition, provided map[string]any) (map[string]any, error) {
+	vars, err := validator.VariableValues(schema, op, provided)
+	if err != nil {
+		return nil, err
+	}
+	for _, def := range op.VariableDefinitions {
+		raw, ok := vars[def.Variable]
+		if !ok {
+			continue
+		}
+		v, err := coerceValue(raw, def.Type)
+		if err != nil {
+			return nil, &Error{Message: fmt.Sprintf("variable $%s: %v", def.Variable, err), Locations: locations(def.Position)}
+		}
+		vars[def.Variable] = v
+	}
+	return vars, nil
+}
+
+// coerceValue converts a literal or decoded JSON value to the Go type
+// resolvers receive for t: int, float64, string, bool or []any.
+func coerceValue(v any, t *ast.Type) (any, error) {
+	if v == nil {
+		if t.NonNull {
+			return nil, fmt.Errorf("expected %s, found null", t)
+		}
+		return nil, nil
+	}
+	if t.Elem != nil {
+		list, ok := v.([]any)
+		if !ok {
+			list = []any{v}
+		}
+		out := make([]any, len(list))
+		for i, item := range list {
+			c, err := coerceValue(item, t.Elem)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = c
+		}
+		return out, nil
+	}
+
+	switch t.NamedType {
+	case "Int":
+		switch n := v.(type) {
+		case int:
+			return n, nil
+		case int64:
+			return int(n), nil
+		case float64:
+			if n == math.Trunc(n) && math.Abs(n) <= math.MaxInt32 {
+				return int(n), nil
+			}
+		}
+	case "Float":
+		switch n := v.(type) {
+		case int:
+			return float64(n), nil
+		case int64:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		}
+	case "String":
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+	case "ID":
+		switch id := v.(type) {
+		case string:
+			return id, nil
+		case int, int64:
+			return fmt.Sprint(id), nil
+		case float64:
+			if id == math.Trunc(id) {
+				return fmt.Sprint(int64(id)), nil
+			}
+		}
+	case "Boolean":
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown type %s", t.NamedType)
+	}
+	return nil, fmt.Errorf("expected %s, found %v", t, describeValue(v))
+}
+
+func describeValue(v any) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(v)
+}
+
+// shape measures a validated selection set before anything is resolved.
+// Each fragment is measured once, however often it is spread.
+func (e *execution) shape(sels ast.SelectionSet) queryShape {
+	var shape queryShape
+	for _, sel := range sels {
+		switch sel := sel.(type) {
+		case *ast.FragmentSpread:
+			fragShape, ok := e.fragmentShapes[sel.Name]
+			if !ok {
+				fragShape = e.shape(sel.Definition.SelectionSet)
+				e.fragmentShapes[sel.Name] = fragShape
+			}
+			shape = shape.add(fragShape)
+		case *ast.InlineFragment:
+			shape = shape.add(e.shape(sel.SelectionSet))
+		case *ast.Field:
+			self := queryShape{fields: 1, depth: 1}
+			if len(sel.SelectionSet) > 0 {
+				sub := e.shape(sel.SelectionSet)
+				self = queryShape{fields: min(1+sub.fields, maxQueryFields+1), depth: 1 + sub.depth}
+			}
+			shape = shape.add(self)
+		}
+	}
+	return shape
+}
+
+// collect flattens fragments and applies @skip/@include, grouping fields
+// by response key in selection order. It stops early once the request's
+// context is done.
+func (e *execution) collect(sels ast.SelectionSet) [][]*ast.Field {
+	var groups [][]*ast.Field
+	index := map[string]int{}
+	var walk func(sels ast.SelectionSet)
+	walk = func(sels ast.SelectionSet) {
+		for _, sel := range sels {
+			if e.ctx.Err() != nil {
+				return
+			}
+			switch sel := sel.(type) {
+			case *ast.FragmentSpread:
+				if e.included(sel.Directives) {
+					walk(sel.Definition.SelectionSet)
+				}
+			case *ast.InlineFragment:
+				if e.included(sel.Directives) {
+					walk(sel.SelectionSet)
+				}
+			case *ast.Field:
+				if !e.included(sel.Directives) {
+					continue
+				}
+				if i, ok := index[sel.Alias]; ok {
+					groups[i] = append(groups[i], sel)
+					continue
+				}
+				index[sel.Alias] = len(groups)
+				groups = append(groups, []*ast.Field{sel})
+			}
+		}
+	}
+	walk(sels)
+	return groups
+}
+
+func (e *execution) included(dirs ast.DirectiveList) bool {
+	if d := dirs.ForName("skip"); d != nil {
+		if skip, _ := d.ArgumentMap(e.variables)["if"].(bool); skip {
+			return false
+		}
+	}
+	if d := dirs.ForName("include"); d != nil {
+		if include, _ := d.ArgumentMap(e.variables)["if"].(bool); !include {
+			return false
+		}
+	}
+	return true
+}
+
+// subSelections merges the selection sets of fields sharing a response key.
+func subSelections(group []*ast.Field) ast.SelectionSet {
+	var sels ast.SelectionSet
+	for _, f := range group {
+		sels = append(sels, f.SelectionSet...)
+	}
+	return sels
+}
+
+func (e *execution) resolveRoot(ctx context.Context, group []*ast.Field, path []any) any {
+	f := group[0]
+	switch f.Name {
+	case "__typename":
+		return e.schema.schema.Query.Name
+	case "__schema":
+		return e.complete(e.schema.intro, f.Definition.Type, subSelections(group), path)
+	}
+
+	args, err := e.arguments(f)
+	if err != nil {
+		e.fieldError(err, f, path)
+		return nil
+	}
+	if f.Name == "__type" {
+		var t *introType
+		types := e.schema.intro.Types
+		if i := slices.IndexFunc(types, func(t *introType) bool { return *t.Name == args["name"] }); i >= 0 {
+			t = types[i]
+		}
+		return e.complete(t, f.Definition.Type, subSelections(group), path)
+	}
+
+	result, err := e.schema.roots[f.Name].Resolve(ctx, args)
+	if err != nil {
+		e.fieldError(err, f, path)
+		return nil
+	}
+	return e.complete(result, f.Definition.Type, subSelections(group), path)
+}
+
+func (e *execution) arguments(f *ast.Field) (map[string]any, error) {
+	args := f.ArgumentMap(e.variables)
+	for name, raw := range args {
+		v, err := coerceValue(raw, f.Definition.Arguments.ForName(name).Type)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", name, err)
+		}
+		args[name] = v
+	}
+	return args, nil
+}
+
+func (e *execution) fieldError(err error, f *ast.Field, path []any) {
+	e.errors = append(e.errors, &Error{
+		Message:   err.Error(),
+		Locations: locations(f.Position),
+		Path:      append([]any(nil), path...),
+	})
+}
+
+// complete serializes a resolved Go value according to its GraphQL type
+// and the requested selection.
+func (e *execution) complete(v any, t *ast.Type, sels ast.SelectionSet, path []any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+
+	if t.Elem != nil {
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		out := make([]any, rv.Len())
+		for i := range out {
+			out[i] = e.complete(rv.Index(i).Interface(), t.Elem, sels, append(path, i))
+		}
+		return out
+	}
+	indexes, isObject := e.schema.objects[t.NamedType]
+	if !isObject {
+		if tm, ok := rv.Interface().(time.Time); ok {
+			return tm.Format(time.RFC3339Nano)
+		}
+		return rv.Interface()
+	}
+
+	out := &orderedMap{}
+	for _, group := range e.collect(sels) {
+		f := group[0]
+		if f.Name == "__typename" {
+			out.set(f.Alias, t.NamedType)
+			continue
+		}
+		index, ok := indexes[f.Name]
+		if !ok {
+			out.set(f.Alias, nil)
+			continue
+		}
+		fv, err := rv.FieldByIndexErr(index)
+		if err != nil {
+			out.set(f.Alias, nil)
+			continue
+		}
+		out.set(f.Alias, e.complete(fv.Interface(), f.Definition.Type, subSelections(group), append(path, f.Alias)))
+	}
+	return out
+}
+
+// orderedMap is a JSON object that keeps the order fields were selected
+// in, as GraphQL responses must.
+type orderedMap struct {
+	keys   []string
+	values []any
+}
+
+func (m *orderedMap) set(key string, v any) {
+	m.keys = append(m.keys, key)
+	m.values = append(m.values, v)
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,171 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type point struct {
+	Name  string   `json:"name"`
+	Value *float64 `json:"value"`
+	Tags  []string `json:"tags,omitempty"`
+	Next  *point   `json:"next,omitempty"`
+}
+
+func testSchema() *Schema {
+	v := 1.5
+	return NewSchema(nil,
+		Field{
+			Name: "point",
+			Args: []Arg{{Name: "name", Type: "String!"}, {Name: "scale", Type: "Float"}},
+			Type: reflect.TypeFor[*point](),
+			Resolve: func(_ context.Context, args map[string]any) (any, error) {
+				value := v
+				if scale, ok := args["scale"].(float64); ok {
+					value *= scale
+				}
+				return &point{Name: args["name"].(string), Value: &value, Next: &point{Name: "next"}}, nil
+			},
+			MaxPerQuery: 2,
+		},
+		Field{
+			Name: "broken",
+			Type: reflect.TypeFor[*point](),
+			Resolve: func(context.Context, map[string]any) (any, error) {
+				return nil, errors.New("backend unavailable")
+			},
+		},
+	)
+}
+
+func run(t *testing.T, req Request) string {
+	t.Helper()
+	b, err := json.Marshal(testSchema().Execute(context.Background(), req))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(b)
+}
+
+func TestExecute_SelectsRequestedFieldsInOrder(t *testing.T) {
+	got := run(t, Request{Query: `{ point(name: "a", scale: 2) { value name renamed: name next { name } } }`})
+	want := `{"data":{"point":{"value":3,"name":"a","renamed":"a","next":{"name":"next"}}}}`
+	if got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestExecute_VariablesFragmentsAndDirectives(t *testing.T) {
+	got := run(t, Request{
+		Query: `query Q($n: String!, $withNext: Boolean!) {
+			point(name: $n) { ...parts next @include(if: $withNext) { name } }
+		}
+		fragment parts on point { name tags }`,
+		OperationName: "Q",
+		Variables:     map[string]any{"n": "b", "withNext": false},
+	})
+	want := `{"data":{"point":{"name":"b","tags":null}}}`
+	if got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestExecute_ResolverErrorIsFieldError(t *testing.T) {
+	got := run(t, Request{Query: `{ broken { name } point(name: "c") { name } }`})
+	want := `{"data":{"broken":null,"point":{"name":"c"}},"errors":[{"message":"backend unavailable","locations":[{"line":1,"column":3}],"path":["broken"]}]}`
+	if got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestExecute_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"syntax", `{ point(name: "a") { name }`, "Expected Name, found <EOF>"},
+		{"unknown field", `{ point(name: "a") { colour } }`, `Cannot query field "colour"`},
+		{"missing argument", `{ point { name } }`, `"name"`},
+		{"missing selection", `{ point(name: "a") }`, "must have a selection"},
+		{"mutation", `mutation { point(name: "a") { name } }`, "mutation"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testSchema().Execute(context.Background(), Request{Query: tt.query})
+			if resp.Data != nil {
+				t.Fatalf("expected no data, got %v", resp.Data)
+			}
+			if len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.want) {
+				t.Fatalf("errors = %v, want message containing %q", resp.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecute_Introspection(t *testing.T) {
+	got := run(t, Request{Query: `{
+		__schema { queryType { name } }
+		__type(name: "point") { kind fields { name type { kind name ofType { name } } } }
+	}`})
+	for _, want := range []string{
+		`"queryType":{"name":"Query"}`,
+		`{"name":"name","type":{"kind":"NON_NULL","name":null,"ofType":{"name":"String"}}}`,
+		`{"name":"value","type":{"kind":"SCALAR","name":"Float","ofType":null}}`,
+		`{"name":"next","type":{"kind":"OBJECT","name":"point","ofType":null}}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("introspection missing %s in %s", want, got)
+		}
+	}
+}
+
+func TestExecute_RejectsExpensiveQueries(t *testing.T) {
+	// Each fragment spreads the next twice, doubling the fields at every
+	// level: 2^30 fields if expanded.
+	var bomb strings.Builder
+	bomb.WriteString(`{ ...F0 } `)
+	for i := range 30 {
+		fmt.Fprintf(&bomb, "fragment F%d on Query { ...F%d ...F%d } ", i, i+1, i+1)
+	}
+	bomb.WriteString(`fragment F30 on Query { point(name: "a") { name } }`)
+
+	deep := `{ point(name: "a") { ` + strings.Repeat("next { ", maxQueryDepth) + "name" + strings.Repeat(" }", maxQueryDepth) + " } }"
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"fragment bomb", bomb.String(), "more than 2000 fields"},
+		{"deep nesting", deep, "more than 20 levels"},
+		{"aliased root fields", `{ a: point(name: "a") { name } b: point(name: "b") { name } c: point(name: "c") { name } }`, `"point" at most 2 times`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			resp := testSchema().Execute(context.Background(), Request{Query: tt.query})
+			if resp.Data != nil || len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.want) {
+				t.Fatalf("expected an error containing %q, got %v", tt.want, resp.Errors)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("rejecting the query took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestExecute_StopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp := testSchema().Execute(ctx, Request{Query: `{ point(name: "a") { name } }`})
+	if resp.Data != nil || len(resp.Errors) == 0 {
+		t.Fatalf("expected a canceled query to fail, got %+v", resp)
+	}
+}
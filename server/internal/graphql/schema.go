@@ -0,0 +1,346 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Field is a root query field. Its result type is described by a Go type
+// whose json-tagged fields become the GraphQL object's fields, following
+// encoding/json naming: pointers, slices, maps and omitempty fields are
+// nullable, everything else is non-null.
+type Field struct {
+	Name        string
+	Description string
+	Args        []Arg
+	Type        reflect.Type
+	Resolve     func(ctx context.Context, args map[string]any) (any, error)
+	// MaxPerQuery limits how many times, under different aliases, one
+	// query may select the field; 0 is no limit. It bounds the work a
+	// query can fan out to expensive resolvers.
+	MaxPerQuery int
+}
+
+// Arg is an argument of a root field. Type is written in GraphQL syntax,
+// e.g. "Float!" or "[String!]", and may only use the built-in scalars.
+type Arg struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Schema is a query-only schema. Parsing, validation and the introspection
+// types come from gqlparser; execution maps Go values onto it.
+type Schema struct {
+	schema *ast.Schema
+	roots  map[string]Field
+	// objects maps each object type's fields to Go struct field indexes.
+	objects map[string]map[string][]int
+	intro   *introSchema
+}
+
+// NewSchema builds a query-only schema from root fields. typeName names
+// the object type for a Go struct type; nil uses the Go type name. It
+// panics on Go types it cannot map, as these are programming errors.
+func NewSchema(typeName func(reflect.Type) string, fields ...Field) *Schema {
+	if typeName == nil {
+		typeName = func(t reflect.Type) string { return t.Name() }
+	}
+	s := &Schema{roots: map[string]Field{}, objects: map[string]map[string][]int{}}
+	b := &schemaBuilder{schema: s, typeName: typeName}
+	b.defs = append(b.defs, describe("", "An arbitrary JSON value.")+"scalar JSON\n")
+
+	var query strings.Builder
+	query.WriteString(describe("", "The root query type. Timestamps are RFC 3339 strings."))
+	query.WriteString("type Query {\n")
+	for _, f := range fields {
+		query.WriteString(describe("  ", f.Description))
+		query.WriteString("  " + f.Name)
+		if len(f.Args) > 0 {
+			query.WriteString("(\n")
+			for _, a := range f.Args {
+				query.WriteString(describe("    ", a.Description))
+				fmt.Fprintf(&query, "    %s: %s\n", a.Name, a.Type)
+			}
+			query.WriteString("  )")
+		}
+		fmt.Fprintf(&query, ": %s\n", b.typeOf(f.Type, false))
+		s.roots[f.Name] = f
+	}
+	query.WriteString("}\n")
+	b.defs = append(b.defs, query.String())
+
+	// The introspection types are declared by gqlparser's prelude; only
+	// their Go field indexes are needed.
+	b.typeOf(reflect.TypeFor[introSchema](), false)
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: strings.Join(b.defs, "\n")})
+	if err != nil {
+		panic(fmt.Sprintf("graphql: invalid schema: %v", err))
+	}
+	s.schema = schema
+	s.buildIntrospection()
+	return s
+}
+
+// describe renders a description in SDL, indented by indent.
+func describe(indent, description string) string {
+	if description == "" {
+		return ""
+	}
+	// A JSON string is also a valid GraphQL string.
+	quoted, _ := json.Marshal(description)
+	return indent + string(quoted) + "\n"
+}
+
+// schemaBuilder renders Go types as SDL type definitions.
+type schemaBuilder struct {
+	schema   *Schema
+	typeName func(reflect.Type) string
+	defs     []string
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// typeOf returns the GraphQL type reference for t, defining the object
+// types it needs.
+func (b *schemaBuilder) typeOf(t reflect.Type, nonNull bool) string {
+	if t.Kind() == reflect.Pointer {
+		return b.typeOf(t.Elem(), false)
+	}
+
+	var ref string
+	switch t.Kind() {
+	case reflect.String:
+		ref = "String"
+	case reflect.Bool:
+		ref = "Boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		ref = "Int"
+	case reflect.Float32, reflect.Float64:
+		ref = "Float"
+	case reflect.Map, reflect.Interface:
+		ref = "JSON"
+	case reflect.Slice, reflect.Array:
+		elem := t.Elem()
+		ref = "[" + b.typeOf(elem, elem.Kind() != reflect.Pointer && elem.Kind() != reflect.Interface) + "]"
+	case reflect.Struct:
+		if t == timeType {
+			ref = "String"
+			break
+		}
+		ref = b.objectType(t)
+	default:
+		panic(fmt.Sprintf("graphql: unsupported Go type %s", t))
+	}
+	if nonNull {
+		return ref + "!"
+	}
+	return ref
+}
+
+func (b *schemaBuilder) objectType(t reflect.Type) string {
+	name, builtIn := introTypeNames[t]
+	if !builtIn {
+		name = b.typeName(t)
+	}
+	if name == "" {
+		panic(fmt.Sprintf("graphql: struct type %s needs a name", t))
+	}
+	if _, ok := b.schema.objects[name]; ok {
+		return name
+	}
+	indexes := map[string][]int{}
+	b.schema.objects[name] = indexes // before the fields, so recursive types terminate
+
+	var sdl strings.Builder
+	fmt.Fprintf(&sdl, "type %s {\n", name)
+	var addFields func(t reflect.Type, index []int)
+	addFields = func(t reflect.Type, index []int) {
+		for i := range t.NumField() {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" || !f.IsExported() && !f.Anonymous {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			fieldIndex := append(append([]int(nil), index...), i)
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				addFields(f.Type, fieldIndex)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			nonNull := !strings.Contains(opts, "omitempty")
+			fmt.Fprintf(&sdl, "  %s: %s\n", name, b.typeOf(f.Type, nonNull))
+			indexes[name] = fieldIndex
+		}
+	}
+	addFields(t, nil)
+	sdl.WriteString("}\n")
+	if !builtIn {
+		b.defs = append(b.defs, sdl.String())
+	}
+	return name
+}
+
+// Introspection results are plain Go values served like any other
+// resolver result, built once from the loaded schema.
+
+type introSchema struct {
+	Description      *string          `json:"description"`
+	Types            []*introType     `json:"types"`
+	QueryType        *introType       `json:"queryType"`
+	MutationType     *introType       `json:"mutationType"`
+	SubscriptionType *introType       `json:"subscriptionType"`
+	Directives       []introDirective `json:"directives"`
+}
+
+type introType struct {
+	Kind           string            `json:"kind"`
+	Name           *string           `json:"name"`
+	Description    *string           `json:"description"`
+	SpecifiedByURL *string           `json:"specifiedByURL"`
+	Fields         []introField      `json:"fields"`
+	Interfaces     []*introType      `json:"interfaces"`
+	PossibleTypes  []*introType      `json:"possibleTypes"`
+	EnumValues     []introEnumValue  `json:"enumValues"`
+	InputFields    []introInputValue `json:"inputFields"`
+	OfType         *introType        `json:"ofType"`
+	IsOneOf        *bool             `json:"isOneOf"`
+}
+
+type introField struct {
+	Name              string            `json:"name"`
+	Description       *string           `json:"description"`
+	Args              []introInputValue `json:"args"`
+	Type              *introType        `json:"type"`
+	IsDeprecated      bool              `json:"isDeprecated"`
+	DeprecationReason *string           `json:"deprecationReason"`
+}
+
+type introInputValue struct {
+	Name              string     `json:"name"`
+	Description       *string    `json:"description"`
+	Type              *introType `json:"type"`
+	DefaultValue      *string    `json:"defaultValue"`
+	IsDeprecated      bool       `json:"isDeprecated"`
+	DeprecationReason *string    `json:"deprecationReason"`
+}
+
+type introEnumValue struct {
+	Name              string  `json:"name"`
+	Description       *string `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type introDirective struct {
+	Name         string            `json:"name"`
+	Description  *string           `json:"description"`
+	Locations    []string          `json:"locations"`
+	Args         []introInputValue `json:"args"`
+	IsRepeatable bool              `json:"isRepeatable"`
+}
+
+var introTypeNames = map[reflect.Type]string{
+	reflect.TypeFor[introSchema]():     "__Schema",
+	reflect.TypeFor[introType]():       "__Type",
+	reflect.TypeFor[introField]():      "__Field",
+	reflect.TypeFor[introInputValue](): "__InputValue",
+	reflect.TypeFor[introEnumValue]():  "__EnumValue",
+	reflect.TypeFor[introDirective]():  "__Directive",
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func (s *Schema) buildIntrospection() {
+	names := make([]string, 0, len(s.schema.Types))
+	for name := range s.schema.Types {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// Named types first, so references can point at them.
+	types := map[string]*introType{}
+	for _, name := range names {
+		def := s.schema.Types[name]
+		types[name] = &introType{Kind: string(def.Kind), Name: optional(name), Description: optional(def.Description)}
+	}
+	var ref func(t *ast.Type) *introType
+	ref = func(t *ast.Type) *introType {
+		switch {
+		case t.NonNull:
+			inner := *t
+			inner.NonNull = false
+			return &introType{Kind: "NON_NULL", OfType: ref(&inner)}
+		case t.Elem != nil:
+			return &introType{Kind: "LIST", OfType: ref(t.Elem)}
+		}
+		return types[t.NamedType]
+	}
+	args := func(defs ast.ArgumentDefinitionList) []introInputValue {
+		out := []introInputValue{}
+		for _, a := range defs {
+			arg := introInputValue{Name: a.Name, Description: optional(a.Description), Type: ref(a.Type)}
+			if a.DefaultValue != nil {
+				arg.DefaultValue = optional(a.DefaultValue.String())
+			}
+			out = append(out, arg)
+		}
+		return out
+	}
+
+	for _, name := range names {
+		def, t := s.schema.Types[name], types[name]
+		switch def.Kind {
+		case ast.Object:
+			t.Fields = []introField{}
+			t.Interfaces = []*introType{}
+			for _, f := range def.Fields {
+				if strings.HasPrefix(f.Name, "__") {
+					continue
+				}
+				t.Fields = append(t.Fields, introField{Name: f.Name, Description: optional(f.Description), Args: args(f.Arguments), Type: ref(f.Type)})
+			}
+		case ast.Enum:
+			t.EnumValues = []introEnumValue{}
+			for _, v := range def.EnumValues {
+				t.EnumValues = append(t.EnumValues, introEnumValue{Name: v.Name, Description: optional(v.Description)})
+			}
+		}
+	}
+
+	s.intro = &introSchema{QueryType: types[s.schema.Query.Name]}
+	for _, name := range names {
+		s.intro.Types = append(s.intro.Types, types[name])
+	}
+	directives := make([]string, 0, len(s.schema.Directives))
+	for name := range s.schema.Directives {
+		directives = append(directives, name)
+	}
+	slices.Sort(directives)
+	for _, name := range directives {
+		d := s.schema.Directives[name]
+		dir := introDirective{Name: d.Name, Description: optional(d.Description), Args: args(d.Arguments), IsRepeatable: d.IsRepeatable}
+		for _, loc := range d.Locations {
+			dir.Locations = append(dir.Locations, string(loc))
+		}
+		s.intro.Directives = append(s.intro.Directives, dir)
+	}
+}
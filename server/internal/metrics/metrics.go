@@ -0,0 +1,144 @@
+// Package metrics wraps the Prometheus client in the small API the server
+// uses: labelled counters and histograms looked up by name, served from
+// one registry.
+//
+// Vectors are nil-safe, so code can record into an uninstrumented (nil)
+// vector and tests only wire up a Registry when they assert on metrics.
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// DefaultBuckets are latency buckets in seconds, the Prometheus client
+// defaults.
+var DefaultBuckets = prometheus.DefBuckets
+
+type Registry struct {
+	reg *prometheus.Registry
+
+	mu         sync.Mutex
+	counters   map[string]*CounterVec
+	histograms map[string]*HistogramVec
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		reg:        prometheus.NewRegistry(),
+		counters:   map[string]*CounterVec{},
+		histograms: map[string]*HistogramVec{},
+	}
+}
+
+// Counter returns the counter vector called name, creating it on first
+// use. Registering the same name twice returns the same vector, so several
+// components can share one.
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.counters[name]; ok {
+		return c
+	}
+	c := &CounterVec{vec: prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)}
+	r.reg.MustRegister(c.vec)
+	r.counters[name] = c
+	return c
+}
+
+// Histogram returns the histogram vector called name, creating it on
+// first use with the given bucket upper bounds.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if h, ok := r.histograms[name]; ok {
+		return h
+	}
+	h := &HistogramVec{vec: prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)}
+	r.reg.MustRegister(h.vec)
+	r.histograms[name] = h
+	return h
+}
+
+// Value returns a counter's value, or a histogram's observation count, for
+// the given label values. It is meant for tests.
+func (r *Registry) Value(name string, labelValues ...string) float64 {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	c, isCounter := r.counters[name]
+	h, isHistogram := r.histograms[name]
+	r.mu.Unlock()
+	switch {
+	case isCounter:
+		return c.Value(labelValues...)
+	case isHistogram:
+		return float64(h.Count(labelValues...))
+	}
+	return 0
+}
+
+// Handler serves every registered metric in the Prometheus exposition
+// format.
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.reg, promhttp.HandlerOpts{})
+}
+
+type CounterVec struct {
+	vec *prometheus.CounterVec
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if c == nil {
+		return
+	}
+	c.vec.WithLabelValues(labelValues...).Add(delta)
+}
+
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	if c == nil {
+		return 0
+	}
+	var m dto.Metric
+	if err := c.vec.WithLabelValues(labelValues...).Write(&m); err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
+
+type HistogramVec struct {
+	vec *prometheus.HistogramVec
+}
+
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	if h == nil {
+		return
+	}
+	h.vec.WithLabelValues(labelValues...).Observe(v)
+}
+
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	if h == nil {
+		return 0
+	}
+	var m dto.Metric
+	if err := h.vec.WithLabelValues(labelValues...).(prometheus.Metric).Write(&m); err != nil {
+		return 0
+	}
+	return m.GetHistogram().GetSampleCount()
+}
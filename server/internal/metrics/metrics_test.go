@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WritesTextFormat(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.Counter("wby_requests_total", "Requests served.", "route", "status")
+	requests.Inc("GET /v1/weather", "200")
+	requests.Inc("GET /v1/weather", "200")
+	requests.Inc(`GET /odd"route`, "500")
+	latency := reg.Histogram("wby_latency_seconds", "Request latency.", []float64{0.1, 1}, "route")
+	latency.Observe(0.05, "GET /v1/weather")
+	latency.Observe(0.5, "GET /v1/weather")
+
+	rr := httptest.NewRecorder()
+	reg.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rr.Body)
+
+	want := `# HELP wby_latency_seconds Request latency.
+# TYPE wby_latency_seconds histogram
+wby_latency_seconds_bucket{route="GET /v1/weather",le="0.1"} 1
+wby_latency_seconds_bucket{route="GET /v1/weather",le="1"} 2
+wby_latency_seconds_bucket{route="GET /v1/weather",le="+Inf"} 2
+wby_latency_seconds_sum{route="GET /v1/weather"} 0.55
+wby_latency_seconds_count{route="GET /v1/weather"} 2
+# HELP wby_requests_total Requests served.
+# TYPE wby_requests_total counter
+wby_requests_total{route="GET /odd\"route",status="500"} 1
+wby_requests_total{route="GET /v1/weather",status="200"} 2
+`
+	if string(body) != want {
+		t.Fatalf("unexpected exposition:\n%s\nwant:\n%s", body, want)
+	}
+	if !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected content type %q", rr.Header().Get("Content-Type"))
+	}
+}
+
+func TestRegistry_SameNameReturnsSameVector(t *testing.T) {
+	reg := NewRegistry()
+	reg.Counter("wby_hits_total", "Hits.", "cache").Inc("forecast")
+	reg.Counter("wby_hits_total", "Hits.", "cache").Inc("forecast")
+
+	if got := reg.Value("wby_hits_total", "forecast"); got != 2 {
+		t.Fatalf("expected shared counter value 2, got %v", got)
+	}
+}
+
+func TestNilVectorsAreNoOps(t *testing.T) {
+	var reg *Registry
+	reg.Counter("wby_hits_total", "Hits.", "cache").Inc("forecast")
+	reg.Histogram("wby_latency_seconds", "Latency.", DefaultBuckets).Observe(1)
+
+	if got := reg.Value("wby_hits_total", "forecast"); got != 0 {
+		t.Fatalf("expected nil registry to report 0, got %v", got)
+	}
+}
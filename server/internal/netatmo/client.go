@@ -0,0 +1,294 @@
+// Package netatmo pulls home weather station readings from the Netatmo
+// Connect API for API clients that have linked a Netatmo account.
+package netatmo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"wby/internal/logging"
+	"wby/internal/weather"
+)
+
+const source = "netatmo"
+
+// maxErrorBody caps how much of an upstream error response is kept in an
+// error, since errors end up in logs.
+const maxErrorBody = 200
+
+// maxStationsDataBody caps a getstationsdata response. A household account
+// returns a few kilobytes; anything near the cap is not one.
+const maxStationsDataBody = 1 << 20
+
+// TokenStore keeps the refresh tokens Netatmo rotates, so a restart does
+// not fall back to a configured token Netatmo has already replaced.
+type TokenStore interface {
+	// NetatmoRefreshToken returns the latest refresh token for the API
+	// client's account, or "" when none is stored. linkedHash identifies
+	// the configured token the stored one descends from, so linking the
+	// account again with a new token supersedes the stored chain.
+	NetatmoRefreshToken(ctx context.Context, clientID string, linkedHash []byte) (string, error)
+	SaveNetatmoRefreshToken(ctx context.Context, clientID string, linkedHash []byte, refreshToken string) error
+}
+
+type Client struct {
+	baseURL      string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+	tokens       TokenStore
+
+	// accounts is fixed at construction; each account guards its tokens.
+	accounts map[string]*account
+}
+
+type account struct {
+	mu           sync.Mutex
+	linkedHash   []byte
+	loaded       bool
+	refreshToken string
+	accessToken  string
+	expiresAt    time.Time
+}
+
+// NewClient creates a client for the Netatmo app identified by clientID and
+// clientSecret. refreshTokens maps wby API client IDs to the Netatmo refresh
+// token of the account they linked.
+func NewClient(baseURL, clientID, clientSecret string, refreshTokens map[string]string) *Client {
+	accounts := make(map[string]*account, len(refreshTokens))
+	for apiClientID, token := range refreshTokens {
+		hash := sha256.Sum256([]byte(token))
+		accounts[apiClientID] = &account{refreshToken: token, linkedHash: hash[:]}
+	}
+	return &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		accounts: accounts,
+	}
+}
+
+// SetTokenStore persists rotated refresh tokens in ts and resumes from the
+// stored ones. Without a store they are kept in memory only.
+func (c *Client) SetTokenStore(ts TokenStore) {
+	c.tokens = ts
+}
+
+// HomeSensors implements weather.HomeSensorProvider.
+func (c *Client) HomeSensors(ctx context.Context, clientID string) ([]weather.HomeSensorReading, error) {
+	token, ok, err := c.accessToken(ctx, clientID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/getstationsdata", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get stations data: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxStationsDataBody+1))
+	if err != nil {
+		return nil, fmt.Errorf("read stations data: %w", err)
+	}
+	if len(body) > maxStationsDataBody {
+		return nil, fmt.Errorf("read stations data: response exceeds %d bytes", maxStationsDataBody)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get stations data: status %d: %s", resp.StatusCode, truncateBody(body))
+	}
+	return ParseStationsData(body)
+}
+
+// accessToken returns a valid access token for the API client, refreshing it
+// when it is about to expire. Only requests for the same account wait on a
+// refresh. Netatmo rotates refresh tokens, so the new one replaces the
+// configured token and is saved to the token store when there is one.
+func (c *Client) accessToken(ctx context.Context, clientID string) (string, bool, error) {
+	acct, ok := c.accounts[clientID]
+	if !ok {
+		return "", false, nil
+	}
+	acct.mu.Lock()
+	defer acct.mu.Unlock()
+
+	if acct.accessToken != "" && time.Now().Add(time.Minute).Before(acct.expiresAt) {
+		return acct.accessToken, true, nil
+	}
+	if c.tokens != nil && !acct.loaded {
+		stored, err := c.tokens.NetatmoRefreshToken(ctx, clientID, acct.linkedHash)
+		if err != nil {
+			return "", false, fmt.Errorf("load refresh token: %w", err)
+		}
+		if stored != "" {
+			acct.refreshToken = stored
+		}
+		acct.loaded = true
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {acct.refreshToken},
+		"client_id":     {c.clientID},
+		"client_secret": {c.clientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/oauth2/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", false, fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("refresh token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// Only the OAuth error code is kept; the rest of the body is the
+		// token endpoint's to word and may echo the request.
+		var oauthErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, maxErrorBody)).Decode(&oauthErr) != nil || oauthErr.Error == "" {
+			return "", false, fmt.Errorf("refresh token: status %d", resp.StatusCode)
+		}
+		return "", false, fmt.Errorf("refresh token: status %d: %s", resp.StatusCode, truncateBody([]byte(oauthErr.Error)))
+	}
+
+	var tok struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", false, fmt.Errorf("decode token: %w", err)
+	}
+	acct.accessToken = tok.AccessToken
+	acct.expiresAt = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	if tok.RefreshToken != "" && tok.RefreshToken != acct.refreshToken {
+		acct.refreshToken = tok.RefreshToken
+		if c.tokens != nil {
+			// The access token is good either way; a lost refresh token
+			// only matters after a restart.
+			if err := c.tokens.SaveNetatmoRefreshToken(ctx, clientID, acct.linkedHash, tok.RefreshToken); err != nil {
+				logging.FromContext(ctx).Warn("netatmo refresh token not saved", "client_id", clientID, "err", err)
+			}
+		}
+	}
+	return acct.accessToken, true, nil
+}
+
+// truncateBody returns at most maxErrorBody bytes of an upstream response
+// for an error message.
+func truncateBody(body []byte) string {
+	if len(body) > maxErrorBody {
+		return string(body[:maxErrorBody]) + "..."
+	}
+	return string(body)
+}
+
+type stationsDataResponse struct {
+	Body struct {
+		Devices []struct {
+			StationName   string        `json:"station_name"`
+			ModuleName    string        `json:"module_name"`
+			Type          string        `json:"type"`
+			DashboardData dashboardData `json:"dashboard_data"`
+			Modules       []struct {
+				ModuleName    string        `json:"module_name"`
+				Type          string        `json:"type"`
+				DashboardData dashboardData `json:"dashboard_data"`
+			} `json:"modules"`
+		} `json:"devices"`
+	} `json:"body"`
+}
+
+type dashboardData struct {
+	TimeUTC      int64    `json:"time_utc"`
+	Temperature  *float64 `json:"Temperature"`
+	Humidity     *float64 `json:"Humidity"`
+	Pressure     *float64 `json:"Pressure"`
+	CO2          *float64 `json:"CO2"`
+	Noise        *float64 `json:"Noise"`
+	WindStrength *float64 `json:"WindStrength"`
+	GustStrength *float64 `json:"GustStrength"`
+	WindAngle    *float64 `json:"WindAngle"`
+	SumRain1     *float64 `json:"sum_rain_1"`
+}
+
+// moduleTypes maps Netatmo hardware type codes to reading kinds.
+var moduleTypes = map[string]string{
+	"NAMain":    "indoor",
+	"NAModule4": "indoor",
+	"NAModule1": "outdoor",
+	"NAModule2": "wind",
+	"NAModule3": "rain",
+}
+
+// ParseStationsData converts a getstationsdata response into readings, one
+// per module that has reported data. Netatmo reports wind in km/h; it is
+// converted to m/s to match FMI observations.
+func ParseStationsData(data []byte) ([]weather.HomeSensorReading, error) {
+	var resp stationsDataResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("decode stations data: %w", err)
+	}
+
+	var readings []weather.HomeSensorReading
+	add := func(stationName, moduleName, moduleType string, d dashboardData) {
+		if d.TimeUTC == 0 {
+			return
+		}
+		kind, ok := moduleTypes[moduleType]
+		if !ok {
+			return
+		}
+		readings = append(readings, weather.HomeSensorReading{
+			Source:      source,
+			StationName: stationName,
+			ModuleName:  moduleName,
+			ModuleType:  kind,
+			ObservedAt:  time.Unix(d.TimeUTC, 0).UTC(),
+			Temperature: d.Temperature,
+			Humidity:    d.Humidity,
+			Pressure:    d.Pressure,
+			CO2:         d.CO2,
+			Noise:       d.Noise,
+			WindSpeed:   kmhToMS(d.WindStrength),
+			WindGust:    kmhToMS(d.GustStrength),
+			WindDir:     d.WindAngle,
+			Precip1h:    d.SumRain1,
+		})
+	}
+	for _, dev := range resp.Body.Devices {
+		add(dev.StationName, dev.ModuleName, dev.Type, dev.DashboardData)
+		for _, m := range dev.Modules {
+			add(dev.StationName, m.ModuleName, m.Type, m.DashboardData)
+		}
+	}
+	return readings, nil
+}
+
+func kmhToMS(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	ms := *v / 3.6
+	return &ms
+}
@@ -0,0 +1,119 @@
+package netatmo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParseStationsData_ReturnsLabeledModules(t *testing.T) {
+	data := []byte(`{"body":{"devices":[{
+		"station_name":"Home","module_name":"Living room","type":"NAMain",
+		"dashboard_data":{"time_utc":1776506400,"Temperature":21.4,"Humidity":38,"CO2":612,"Noise":35,"Pressure":1012.3},
+		"modules":[
+			{"module_name":"Balcony","type":"NAModule1","dashboard_data":{"time_utc":1776506350,"Temperature":6.2,"Humidity":81}},
+			{"module_name":"Anemometer","type":"NAModule2","dashboard_data":{"time_utc":1776506350,"WindStrength":18,"GustStrength":36,"WindAngle":250}},
+			{"module_name":"Offline","type":"NAModule3","dashboard_data":{}}
+		]}]}}`)
+
+	readings, err := ParseStationsData(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(readings) != 3 {
+		t.Fatalf("expected 3 reporting modules, got %d", len(readings))
+	}
+	for _, r := range readings {
+		if r.Source != "netatmo" {
+			t.Fatalf("expected netatmo source, got %q", r.Source)
+		}
+	}
+	if readings[0].ModuleType != "indoor" || *readings[0].CO2 != 612 {
+		t.Fatalf("unexpected indoor reading %+v", readings[0])
+	}
+	if readings[1].ModuleType != "outdoor" || *readings[1].Temperature != 6.2 {
+		t.Fatalf("unexpected outdoor reading %+v", readings[1])
+	}
+	wind := readings[2]
+	if wind.ModuleType != "wind" || math.Abs(*wind.WindSpeed-5) > 1e-9 || math.Abs(*wind.WindGust-10) > 1e-9 {
+		t.Fatalf("expected wind converted to m/s, got %+v", wind)
+	}
+}
+
+type memoryTokenStore map[string]string
+
+func (m memoryTokenStore) NetatmoRefreshToken(_ context.Context, clientID string, linkedHash []byte) (string, error) {
+	return m[clientID+fmt.Sprintf("%x", linkedHash)], nil
+}
+
+func (m memoryTokenStore) SaveNetatmoRefreshToken(_ context.Context, clientID string, linkedHash []byte, refreshToken string) error {
+	m[clientID+fmt.Sprintf("%x", linkedHash)] = refreshToken
+	return nil
+}
+
+func TestClient_PersistsRotatedRefreshToken(t *testing.T) {
+	// Netatmo accepts each refresh token once and answers with the next.
+	var issued atomic.Int32
+	valid := "linked"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth2/token":
+			if r.FormValue("refresh_token") != valid {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"invalid_grant","echo":"`+r.FormValue("client_secret")+`"}`)
+				return
+			}
+			valid = fmt.Sprintf("rotated-%d", issued.Add(1))
+			fmt.Fprintf(w, `{"access_token":"access","refresh_token":%q,"expires_in":10800}`, valid)
+		case "/api/getstationsdata":
+			fmt.Fprint(w, `{"body":{"devices":[]}}`)
+		}
+	}))
+	defer srv.Close()
+
+	tokens := memoryTokenStore{}
+	accounts := map[string]string{"app": "linked"}
+	c := NewClient(srv.URL, "id", "secret", accounts)
+	c.SetTokenStore(tokens)
+	if _, err := c.HomeSensors(context.Background(), "app"); err != nil {
+		t.Fatalf("first client: %v", err)
+	}
+
+	// A restarted client resumes from the stored token, as the configured
+	// one is spent.
+	restarted := NewClient(srv.URL, "id", "secret", accounts)
+	restarted.SetTokenStore(tokens)
+	if _, err := restarted.HomeSensors(context.Background(), "app"); err != nil {
+		t.Fatalf("restarted client: %v", err)
+	}
+
+	_, err := NewClient(srv.URL, "id", "secret", accounts).HomeSensors(context.Background(), "app")
+	if err == nil {
+		t.Fatal("expected the spent configured token to fail without a store")
+	}
+	if !strings.Contains(err.Error(), "invalid_grant") || strings.Contains(err.Error(), "secret") {
+		t.Fatalf("expected only the OAuth error code, got %v", err)
+	}
+}
+
+func TestClient_RejectsOversizedStationsData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth2/token":
+			fmt.Fprint(w, `{"access_token":"access","expires_in":10800}`)
+		case "/api/getstationsdata":
+			fmt.Fprint(w, `{"body":{"devices":[],"padding":"`+strings.Repeat("x", maxStationsDataBody)+`"}}`)
+		}
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL, "id", "secret", map[string]string{"app": "linked"}).HomeSensors(context.Background(), "app")
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Fatalf("expected oversized response error, got %v", err)
+	}
+}
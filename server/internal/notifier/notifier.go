@@ -0,0 +1,142 @@
+// Package notifier pushes forecast subscription updates to device webhooks.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"wby/internal/weather"
+)
+
+type SubscriptionService interface {
+	ListSubscriptions(ctx context.Context) ([]weather.ForecastSubscription, error)
+	PrefetchForecasts(ctx context.Context, points []weather.LatLon)
+	CheckSubscription(ctx context.Context, sub weather.ForecastSubscription) ([]weather.ForecastChange, []weather.DailyForecast, error)
+	SaveSubscriptionSnapshot(ctx context.Context, id int64, snapshot []weather.DailyForecast) error
+}
+
+type Notifier struct {
+	service    SubscriptionService
+	httpClient *http.Client
+}
+
+func New(service SubscriptionService) *Notifier {
+	return &Notifier{
+		service:    service,
+		httpClient: newWebhookClient(),
+	}
+}
+
+type webhookPayload struct {
+	SubscriptionID int64               `json:"subscription_id"`
+	Lat            float64             `json:"lat"`
+	Lon            float64             `json:"lon"`
+	Changes        []webhookChangeJSON `json:"changes"`
+}
+
+type webhookChangeJSON struct {
+	Date     string   `json:"date"`
+	Kind     string   `json:"kind"`
+	Previous *float64 `json:"previous"`
+	Current  *float64 `json:"current"`
+}
+
+func (n *Notifier) RunLoop(ctx context.Context, interval time.Duration) {
+	slog.Info("subscription notifier starting", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("subscription notifier stopped")
+			return
+		case <-ticker.C:
+			n.checkAll(ctx)
+		}
+	}
+}
+
+func (n *Notifier) checkAll(ctx context.Context) {
+	subs, err := n.service.ListSubscriptions(ctx)
+	if err != nil {
+		slog.Error("failed to list subscriptions", "err", err)
+		return
+	}
+
+	// Fetch the forecasts the checks need a batch of points at a time
+	// rather than one request per subscription.
+	points := make([]weather.LatLon, len(subs))
+	for i, sub := range subs {
+		points[i] = weather.LatLon{Lat: sub.Lat, Lon: sub.Lon}
+	}
+	n.service.PrefetchForecasts(ctx, points)
+
+	pushed := 0
+	for _, sub := range subs {
+		changes, snapshot, err := n.service.CheckSubscription(ctx, sub)
+		if err != nil {
+			slog.Warn("subscription check failed", "err", err, "subscription_id", sub.ID)
+			continue
+		}
+		if snapshot == nil {
+			continue
+		}
+		if len(changes) > 0 {
+			// The old snapshot stays until a push succeeds, so the next
+			// check finds the same changes and retries.
+			if err := n.push(ctx, sub, changes); err != nil {
+				slog.Warn("subscription push failed", "err", err, "subscription_id", sub.ID)
+				continue
+			}
+			pushed++
+		}
+		if err := n.service.SaveSubscriptionSnapshot(ctx, sub.ID, snapshot); err != nil {
+			slog.Warn("subscription snapshot update failed", "err", err, "subscription_id", sub.ID)
+		}
+	}
+	slog.Info("subscriptions checked", "subscriptions", len(subs), "pushed", pushed)
+}
+
+func (n *Notifier) push(ctx context.Context, sub weather.ForecastSubscription, changes []weather.ForecastChange) error {
+	payload := webhookPayload{
+		SubscriptionID: sub.ID,
+		Lat:            sub.Lat,
+		Lon:            sub.Lon,
+		Changes:        make([]webhookChangeJSON, len(changes)),
+	}
+	for i, c := range changes {
+		payload.Changes[i] = webhookChangeJSON{
+			Date:     c.Date.Format("2006-01-02"),
+			Kind:     c.Kind,
+			Previous: c.Previous,
+			Current:  c.Current,
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post webhook: status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+type fakeService struct {
+	subs       []weather.ForecastSubscription
+	changes    []weather.ForecastChange
+	saved      map[int64][]weather.DailyForecast
+	prefetched []weather.LatLon
+}
+
+func (f *fakeService) ListSubscriptions(context.Context) ([]weather.ForecastSubscription, error) {
+	return f.subs, nil
+}
+
+func (f *fakeService) PrefetchForecasts(_ context.Context, points []weather.LatLon) {
+	f.prefetched = append(f.prefetched, points...)
+}
+
+func (f *fakeService) CheckSubscription(context.Context, weather.ForecastSubscription) ([]weather.ForecastChange, []weather.DailyForecast, error) {
+	return f.changes, []weather.DailyForecast{{Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}}, nil
+}
+
+func (f *fakeService) SaveSubscriptionSnapshot(_ context.Context, id int64, snapshot []weather.DailyForecast) error {
+	f.saved[id] = snapshot
+	return nil
+}
+
+func TestCheckAll_SavesSnapshotOnlyAfterSuccessfulPush(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	high := 20.0
+	svc := &fakeService{
+		subs:    []weather.ForecastSubscription{{ID: 1, WebhookURL: srv.URL}},
+		changes: []weather.ForecastChange{{Kind: "temperature_high", Current: &high}},
+		saved:   map[int64][]weather.DailyForecast{},
+	}
+	n := New(svc)
+	// The test server is on loopback, which the webhook dialer refuses.
+	n.httpClient = srv.Client()
+
+	n.checkAll(context.Background())
+	if _, ok := svc.saved[1]; ok {
+		t.Fatal("snapshot saved although the push failed")
+	}
+
+	status = http.StatusNoContent
+	n.checkAll(context.Background())
+	if _, ok := svc.saved[1]; !ok {
+		t.Fatal("snapshot not saved after a successful push")
+	}
+}
+
+func TestCheckAll_SavesBaselineWithoutPush(t *testing.T) {
+	svc := &fakeService{
+		subs:  []weather.ForecastSubscription{{ID: 1, WebhookURL: "https://unreachable.invalid/hook"}},
+		saved: map[int64][]weather.DailyForecast{},
+	}
+	New(svc).checkAll(context.Background())
+	if _, ok := svc.saved[1]; !ok {
+		t.Fatal("baseline snapshot not saved")
+	}
+}
+
+func TestCheckAll_PrefetchesEveryLocation(t *testing.T) {
+	svc := &fakeService{
+		subs: []weather.ForecastSubscription{
+			{ID: 1, Lat: 60.17, Lon: 24.94},
+			{ID: 2, Lat: 65.01, Lon: 25.47},
+		},
+		saved: map[int64][]weather.DailyForecast{},
+	}
+	New(svc).checkAll(context.Background())
+	want := []weather.LatLon{{Lat: 60.17, Lon: 24.94}, {Lat: 65.01, Lon: 25.47}}
+	if !slices.Equal(svc.prefetched, want) {
+		t.Fatalf("expected %v prefetched, got %v", want, svc.prefetched)
+	}
+}
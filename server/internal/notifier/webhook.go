@@ -0,0 +1,88 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrWebhookNotAllowed is returned for webhook URLs the server must not
+// call, such as addresses on its own network.
+var ErrWebhookNotAllowed = errors.New("webhook URL not allowed")
+
+// sharedAddressSpace is carrier-grade NAT space (RFC 6598), which is no
+// more public than RFC 1918 space but not covered by netip's IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// publicAddr reports whether addr is a globally routable unicast address.
+// Loopback, private, link-local (which includes cloud metadata endpoints
+// such as 169.254.169.254), multicast and unspecified addresses are not.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// CheckWebhookURL rejects webhook URLs that are not https or whose host is,
+// or resolves to, a non-public address. The notifier's dialer checks the
+// address again when it connects, as DNS can change in between.
+func CheckWebhookURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("%w: must be an https URL", ErrWebhookNotAllowed)
+	}
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !publicAddr(addr) {
+			return fmt.Errorf("%w: %s is not a public address", ErrWebhookNotAllowed, host)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve %s", ErrWebhookNotAllowed, host)
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return fmt.Errorf("%w: %s resolves to a non-public address", ErrWebhookNotAllowed, host)
+		}
+	}
+	return nil
+}
+
+// newWebhookClient returns an HTTP client that refuses to connect to
+// non-public addresses after DNS resolution, so a webhook host cannot be
+// repointed at the server's own network once the subscription exists.
+// Proxies are not used, since the check must see the final address.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrWebhookNotAllowed, address)
+			}
+			if !publicAddr(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s is not a public address", ErrWebhookNotAllowed, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+		// A redirect could lead anywhere, including somewhere the dialer
+		// allows but the subscription never named.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
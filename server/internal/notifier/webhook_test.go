@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckWebhookURL(t *testing.T) {
+	cases := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://93.184.216.34/hook", true},
+		{"http://93.184.216.34/hook", false},
+		{"https://127.0.0.1/hook", false},
+		{"https://[::1]/hook", false},
+		{"https://10.1.2.3/hook", false},
+		{"https://192.168.0.10:8443/hook", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://100.64.0.1/hook", false},
+		{"https://[fd00:ec2::254]/hook", false},
+		{"https://[::ffff:127.0.0.1]/hook", false},
+		{"https://0.0.0.0/hook", false},
+		{"https://localhost/hook", false},
+		{"not a url", false},
+	}
+	for _, tc := range cases {
+		err := CheckWebhookURL(context.Background(), tc.url)
+		if tc.allowed && err != nil {
+			t.Errorf("CheckWebhookURL(%q) = %v, want nil", tc.url, err)
+		}
+		if !tc.allowed && !errors.Is(err, ErrWebhookNotAllowed) {
+			t.Errorf("CheckWebhookURL(%q) = %v, want ErrWebhookNotAllowed", tc.url, err)
+		}
+	}
+}
+
+func TestWebhookClient_RefusesLoopback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook client connected to a loopback server")
+	}))
+	defer srv.Close()
+
+	_, err := newWebhookClient().Post(srv.URL, "application/json", nil)
+	if !errors.Is(err, ErrWebhookNotAllowed) {
+		t.Fatalf("err = %v, want ErrWebhookNotAllowed", err)
+	}
+}
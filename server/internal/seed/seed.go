@@ -0,0 +1,221 @@
+// Package seed loads the demo dataset embedded in the binary so a fresh
+// database can serve a populated UI without FMI access.
+package seed
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"wby/internal/weather"
+)
+
+//go:embed demo/*.json
+var demoFS embed.FS
+
+// The demo files describe a single day; every timestamp is shifted
+// relative to this date so the data always looks current when loaded.
+var demoBaseDate = time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+type Loader interface {
+	UpsertStations(ctx context.Context, stations []weather.Station) error
+	UpsertObservations(ctx context.Context, observations []weather.Observation) error
+	UpsertForecasts(ctx context.Context, forecasts []weather.DailyForecast) error
+	UpsertHourlyForecasts(ctx context.Context, gridLat, gridLon float64, hourly []weather.HourlyForecast) error
+}
+
+type Summary struct {
+	Stations        int
+	Observations    int
+	DailyForecasts  int
+	HourlyForecasts int
+}
+
+type demoStation struct {
+	FMISID  int     `json:"fmisid"`
+	Name    string  `json:"name"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	WMOCode string  `json:"wmo_code"`
+}
+
+type demoObservation struct {
+	FMISID          int       `json:"fmisid"`
+	ObservedAt      time.Time `json:"observed_at"`
+	Temperature     *float64  `json:"temperature"`
+	WindSpeed       *float64  `json:"wind_speed"`
+	WindGust        *float64  `json:"wind_gust"`
+	WindDir         *float64  `json:"wind_dir"`
+	Humidity        *float64  `json:"humidity"`
+	DewPoint        *float64  `json:"dew_point"`
+	Pressure        *float64  `json:"pressure"`
+	Precip1h        *float64  `json:"precip_1h"`
+	Visibility      *float64  `json:"visibility"`
+	TotalCloudCover *float64  `json:"total_cloud_cover"`
+	WeatherCode     *float64  `json:"weather_code"`
+}
+
+type demoDailyForecast struct {
+	GridLat            float64  `json:"grid_lat"`
+	GridLon            float64  `json:"grid_lon"`
+	Date               string   `json:"date"`
+	TempHigh           *float64 `json:"temp_high"`
+	TempLow            *float64 `json:"temp_low"`
+	TempAvg            *float64 `json:"temp_avg"`
+	WindSpeed          *float64 `json:"wind_speed"`
+	WindDir            *float64 `json:"wind_dir"`
+	HumidityAvg        *float64 `json:"humidity_avg"`
+	PrecipMM           *float64 `json:"precip_mm"`
+	Symbol             *string  `json:"symbol"`
+	PoPAvg             *float64 `json:"pop_avg"`
+	TotalCloudCoverAvg *float64 `json:"total_cloud_cover_avg"`
+	PressureAvg        *float64 `json:"pressure_avg"`
+}
+
+type demoHourlyForecast struct {
+	GridLat     float64   `json:"grid_lat"`
+	GridLon     float64   `json:"grid_lon"`
+	Time        time.Time `json:"time"`
+	Temperature *float64  `json:"temperature"`
+	WindSpeed   *float64  `json:"wind_speed"`
+	WindDir     *float64  `json:"wind_dir"`
+	Humidity    *float64  `json:"humidity"`
+	Precip1h    *float64  `json:"precip_1h"`
+	Symbol      *string   `json:"symbol"`
+	CloudCover  *float64  `json:"cloud_cover"`
+}
+
+// LoadDemo upserts the embedded demo dataset. Observations end at the
+// current hour, hourly forecasts start at it, and daily forecasts start
+// today. Forecasts are stamped as fetched now, so they are served from
+// Postgres until the configured freshness MaxAge passes.
+func LoadDemo(ctx context.Context, db Loader, now time.Time) (Summary, error) {
+	var summary Summary
+	now = now.UTC()
+	currentHour := now.Truncate(time.Hour)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var stations []demoStation
+	if err := readDemoFile("demo/stations.json", &stations); err != nil {
+		return summary, err
+	}
+	domainStations := make([]weather.Station, len(stations))
+	for i, s := range stations {
+		domainStations[i] = weather.Station{FMISID: s.FMISID, Name: s.Name, Lat: s.Lat, Lon: s.Lon, WMOCode: s.WMOCode}
+	}
+	if err := db.UpsertStations(ctx, domainStations); err != nil {
+		return summary, fmt.Errorf("seed stations: %w", err)
+	}
+	summary.Stations = len(domainStations)
+
+	var observations []demoObservation
+	if err := readDemoFile("demo/observations.json", &observations); err != nil {
+		return summary, err
+	}
+	obsShift := currentHour.Sub(demoBaseDate.Add(23 * time.Hour))
+	domainObs := make([]weather.Observation, len(observations))
+	for i, o := range observations {
+		domainObs[i] = weather.Observation{
+			FMISID:          o.FMISID,
+			ObservedAt:      o.ObservedAt.Add(obsShift),
+			Temperature:     o.Temperature,
+			WindSpeed:       o.WindSpeed,
+			WindGust:        o.WindGust,
+			WindDir:         o.WindDir,
+			Humidity:        o.Humidity,
+			DewPoint:        o.DewPoint,
+			Pressure:        o.Pressure,
+			Precip1h:        o.Precip1h,
+			Visibility:      o.Visibility,
+			TotalCloudCover: o.TotalCloudCover,
+			WeatherCode:     o.WeatherCode,
+		}
+	}
+	if err := db.UpsertObservations(ctx, domainObs); err != nil {
+		return summary, fmt.Errorf("seed observations: %w", err)
+	}
+	summary.Observations = len(domainObs)
+
+	var daily []demoDailyForecast
+	if err := readDemoFile("demo/daily_forecasts.json", &daily); err != nil {
+		return summary, err
+	}
+	dayShift := int(today.Sub(demoBaseDate).Hours() / 24)
+	domainDaily := make([]weather.DailyForecast, 0, len(daily))
+	for _, f := range daily {
+		date, err := time.Parse("2006-01-02", f.Date)
+		if err != nil {
+			return summary, fmt.Errorf("parse demo forecast date %q: %w", f.Date, err)
+		}
+		domainDaily = append(domainDaily, weather.DailyForecast{
+			GridLat:            f.GridLat,
+			GridLon:            f.GridLon,
+			Date:               date.AddDate(0, 0, dayShift),
+			FetchedAt:          now,
+			SchemaVersion:      weather.DailyForecastSchemaVersion,
+			TempHigh:           f.TempHigh,
+			TempLow:            f.TempLow,
+			TempAvg:            f.TempAvg,
+			WindSpeed:          f.WindSpeed,
+			WindDir:            f.WindDir,
+			HumidityAvg:        f.HumidityAvg,
+			PrecipMM:           f.PrecipMM,
+			Symbol:             f.Symbol,
+			PoPAvg:             f.PoPAvg,
+			TotalCloudCoverAvg: f.TotalCloudCoverAvg,
+			PressureAvg:        f.PressureAvg,
+		})
+	}
+	if err := db.UpsertForecasts(ctx, domainDaily); err != nil {
+		return summary, fmt.Errorf("seed daily forecasts: %w", err)
+	}
+	summary.DailyForecasts = len(domainDaily)
+
+	var hourly []demoHourlyForecast
+	if err := readDemoFile("demo/hourly_forecasts.json", &hourly); err != nil {
+		return summary, err
+	}
+	hourlyShift := currentHour.Sub(demoBaseDate)
+	type gridPoint struct{ lat, lon float64 }
+	byGrid := make(map[gridPoint][]weather.HourlyForecast)
+	var gridOrder []gridPoint
+	for _, h := range hourly {
+		gp := gridPoint{h.GridLat, h.GridLon}
+		if _, ok := byGrid[gp]; !ok {
+			gridOrder = append(gridOrder, gp)
+		}
+		byGrid[gp] = append(byGrid[gp], weather.HourlyForecast{
+			Time:          h.Time.Add(hourlyShift),
+			FetchedAt:     now,
+			SchemaVersion: weather.HourlyForecastSchemaVersion,
+			Temperature:   h.Temperature,
+			WindSpeed:     h.WindSpeed,
+			WindDir:       h.WindDir,
+			Humidity:      h.Humidity,
+			Precip1h:      h.Precip1h,
+			Symbol:        h.Symbol,
+			CloudCover:    h.CloudCover,
+		})
+	}
+	for _, gp := range gridOrder {
+		if err := db.UpsertHourlyForecasts(ctx, gp.lat, gp.lon, byGrid[gp]); err != nil {
+			return summary, fmt.Errorf("seed hourly forecasts: %w", err)
+		}
+		summary.HourlyForecasts += len(byGrid[gp])
+	}
+
+	return summary, nil
+}
+
+func readDemoFile(name string, v any) error {
+	data, err := demoFS.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %s: %w", name, err)
+	}
+	return nil
+}
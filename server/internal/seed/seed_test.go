@@ -0,0 +1,67 @@
+package seed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wby/internal/weather"
+)
+
+type recordingLoader struct {
+	stations     []weather.Station
+	observations []weather.Observation
+	daily        []weather.DailyForecast
+	hourly       []weather.HourlyForecast
+}
+
+func (l *recordingLoader) UpsertStations(ctx context.Context, stations []weather.Station) error {
+	l.stations = append(l.stations, stations...)
+	return nil
+}
+
+func (l *recordingLoader) UpsertObservations(ctx context.Context, observations []weather.Observation) error {
+	l.observations = append(l.observations, observations...)
+	return nil
+}
+
+func (l *recordingLoader) UpsertForecasts(ctx context.Context, forecasts []weather.DailyForecast) error {
+	l.daily = append(l.daily, forecasts...)
+	return nil
+}
+
+func (l *recordingLoader) UpsertHourlyForecasts(ctx context.Context, gridLat, gridLon float64, hourly []weather.HourlyForecast) error {
+	l.hourly = append(l.hourly, hourly...)
+	return nil
+}
+
+func TestLoadDemo_ShiftsDataToNow(t *testing.T) {
+	now := time.Date(2026, 10, 15, 14, 37, 0, 0, time.UTC)
+	loader := &recordingLoader{}
+
+	summary, err := LoadDemo(context.Background(), loader, now)
+	if err != nil {
+		t.Fatalf("load demo: %v", err)
+	}
+	if summary.Stations == 0 || summary.Observations == 0 || summary.DailyForecasts == 0 || summary.HourlyForecasts == 0 {
+		t.Fatalf("expected every dataset to be populated, got %+v", summary)
+	}
+
+	var latest time.Time
+	for _, o := range loader.observations {
+		if o.ObservedAt.After(latest) {
+			latest = o.ObservedAt
+		}
+	}
+	if want := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC); !latest.Equal(want) {
+		t.Fatalf("expected latest observation at %v, got %v", want, latest)
+	}
+
+	today := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	if !loader.daily[0].Date.Equal(today) {
+		t.Fatalf("expected daily forecasts to start today, got %v", loader.daily[0].Date)
+	}
+	if !loader.hourly[0].Time.Equal(now.Truncate(time.Hour)) {
+		t.Fatalf("expected hourly forecasts to start at the current hour, got %v", loader.hourly[0].Time)
+	}
+}
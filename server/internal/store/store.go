@@ -3,7 +3,10 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -30,14 +33,24 @@ func (s *Store) Close() {
 	s.pool.Close()
 }
 
+// Ping checks that the database is reachable.
+func (s *Store) Ping(ctx context.Context) error {
+	return s.pool.Ping(ctx)
+}
+
 func (s *Store) UpsertStations(ctx context.Context, stations []weather.Station) error {
 	batch := &pgx.Batch{}
 	for _, st := range stations {
+		// Not every query reports elevation, region or station groups, so
+		// missing values keep what an earlier fetch stored.
 		batch.Queue(
-			`INSERT INTO stations (fmisid, name, geom, wmo_code)
-			 VALUES ($1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, $5)
-			 ON CONFLICT (fmisid) DO UPDATE SET name = $2, geom = ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, wmo_code = $5`,
-			st.FMISID, st.Name, st.Lon, st.Lat, st.WMOCode,
+			`INSERT INTO stations (fmisid, name, geom, wmo_code, elevation_m, station_type, region)
+			 VALUES ($1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, $5, $6, NULLIF($7, ''), NULLIF($8, ''))
+			 ON CONFLICT (fmisid) DO UPDATE SET name = $2, geom = ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, wmo_code = $5,
+			   elevation_m = COALESCE($6, stations.elevation_m),
+			   station_type = COALESCE(NULLIF($7, ''), stations.station_type),
+			   region = COALESCE(NULLIF($8, ''), stations.region)`,
+			st.FMISID, st.Name, st.Lon, st.Lat, st.WMOCode, st.ElevationM, st.Type, st.Region,
 		)
 	}
 	br := s.pool.SendBatch(ctx, batch)
@@ -51,20 +64,73 @@ func (s *Store) UpsertStations(ctx context.Context, stations []weather.Station)
 }
 
 func (s *Store) NearestStation(ctx context.Context, lat, lon float64) (weather.Station, float64, error) {
+	stations, err := s.NearestStations(ctx, lat, lon, 1)
+	if err != nil {
+		return weather.Station{}, 0, err
+	}
+	if len(stations) == 0 {
+		// The query orders every station by distance, so no rows means
+		// an empty table.
+		return weather.Station{}, 0, fmt.Errorf("nearest station: %w", weather.ErrNoStations)
+	}
+	return stations[0].Station, stations[0].DistanceKM, nil
+}
+
+// NearestStations returns up to n stations closest to the point, nearest
+// first, each with the time of its latest observation.
+func (s *Store) NearestStations(ctx context.Context, lat, lon float64, n int) ([]weather.NearbyStation, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT s.fmisid, s.name, ST_Y(s.geom::geometry), ST_X(s.geom::geometry), COALESCE(s.wmo_code, ''),
+		        s.elevation_m, COALESCE(s.station_type, ''), COALESCE(s.region, ''),
+		        ST_Distance(s.geom, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography),
+		        latest.observed_at
+		 FROM stations s
+		 LEFT JOIN LATERAL (
+		   SELECT observed_at FROM observations o
+		   WHERE o.fmisid = s.fmisid
+		   ORDER BY observed_at DESC
+		   LIMIT 1
+		 ) latest ON true
+		 ORDER BY s.geom <-> ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography
+		 LIMIT $3`,
+		lon, lat, n,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("nearest stations: %w", err)
+	}
+	defer rows.Close()
+
+	var result []weather.NearbyStation
+	for rows.Next() {
+		var st weather.NearbyStation
+		var distMeters float64
+		if err := rows.Scan(&st.FMISID, &st.Name, &st.Lat, &st.Lon, &st.WMOCode, &st.ElevationM, &st.Type, &st.Region, &distMeters, &st.LastObservedAt); err != nil {
+			return nil, fmt.Errorf("scan nearest station: %w", err)
+		}
+		st.DistanceKM = distMeters / 1000.0
+		result = append(result, st)
+	}
+	return result, rows.Err()
+}
+
+// GetStation returns the station with the given FMISID, or nil if there is
+// none.
+func (s *Store) GetStation(ctx context.Context, fmisid int) (*weather.Station, error) {
 	var st weather.Station
-	var distMeters float64
 	err := s.pool.QueryRow(ctx,
-		`SELECT fmisid, name, ST_Y(geom::geometry), ST_X(geom::geometry), wmo_code,
-		        ST_Distance(geom, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography)
+		`SELECT fmisid, name, ST_Y(geom::geometry), ST_X(geom::geometry), COALESCE(wmo_code, ''),
+		        elevation_m, COALESCE(station_type, ''), COALESCE(region, '')
 		 FROM stations
-		 ORDER BY geom <-> ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography
-		 LIMIT 1`,
-		lon, lat,
-	).Scan(&st.FMISID, &st.Name, &st.Lat, &st.Lon, &st.WMOCode, &distMeters)
+		 WHERE fmisid = $1`,
+		fmisid,
+	).Scan(&st.FMISID, &st.Name, &st.Lat, &st.Lon, &st.WMOCode, &st.ElevationM, &st.Type, &st.Region)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
 	if err != nil {
-		return st, 0, fmt.Errorf("nearest station: %w", err)
+		return nil, fmt.Errorf("get station: %w", err)
 	}
-	return st, distMeters / 1000.0, nil
+	return &st, nil
 }
 
 func (s *Store) NearestStationWithClimateNormals(ctx context.Context, lat, lon float64, period string) (weather.Station, float64, error) {
@@ -72,19 +138,55 @@ func (s *Store) NearestStationWithClimateNormals(ctx context.Context, lat, lon f
 	var distMeters float64
 	err := s.pool.QueryRow(ctx,
 		`SELECT s.fmisid, s.name, ST_Y(s.geom::geometry), ST_X(s.geom::geometry), s.wmo_code,
+		        s.elevation_m, COALESCE(s.station_type, ''), COALESCE(s.region, ''),
 		        ST_Distance(s.geom, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography)
 		 FROM stations s
 		 WHERE EXISTS (SELECT 1 FROM climate_normals cn WHERE cn.fmisid = s.fmisid AND cn.period = $3)
 		 ORDER BY s.geom <-> ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography
 		 LIMIT 1`,
 		lon, lat, period,
-	).Scan(&st.FMISID, &st.Name, &st.Lat, &st.Lon, &st.WMOCode, &distMeters)
+	).Scan(&st.FMISID, &st.Name, &st.Lat, &st.Lon, &st.WMOCode, &st.ElevationM, &st.Type, &st.Region, &distMeters)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = weather.ErrNoClimateNormals
+	}
 	if err != nil {
 		return st, 0, fmt.Errorf("nearest station with climate normals: %w", err)
 	}
 	return st, distMeters / 1000.0, nil
 }
 
+// ListStations returns all stations ordered by FMISID, limited to bbox when
+// it is non-nil.
+func (s *Store) ListStations(ctx context.Context, bbox *weather.BBox) ([]weather.Station, error) {
+	query := `SELECT fmisid, name, ST_Y(geom::geometry), ST_X(geom::geometry), COALESCE(wmo_code, ''),
+		        elevation_m, COALESCE(station_type, ''), COALESCE(region, '')
+		 FROM stations`
+	var args []any
+	if bbox != nil {
+		query += `
+		 WHERE geom && ST_MakeEnvelope($1, $2, $3, $4, 4326)::geography`
+		args = append(args, bbox.MinLon, bbox.MinLat, bbox.MaxLon, bbox.MaxLat)
+	}
+	query += `
+		 ORDER BY fmisid`
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list stations: %w", err)
+	}
+	defer rows.Close()
+
+	var stations []weather.Station
+	for rows.Next() {
+		var st weather.Station
+		if err := rows.Scan(&st.FMISID, &st.Name, &st.Lat, &st.Lon, &st.WMOCode, &st.ElevationM, &st.Type, &st.Region); err != nil {
+			return nil, fmt.Errorf("scan station: %w", err)
+		}
+		stations = append(stations, st)
+	}
+	return stations, rows.Err()
+}
+
 func (s *Store) UpsertObservations(ctx context.Context, observations []weather.Observation) error {
 	batch := &pgx.Batch{}
 	for _, o := range observations {
@@ -135,6 +237,258 @@ func (s *Store) LatestObservation(ctx context.Context, fmisid int) (weather.Obse
 	return o, nil
 }
 
+// LatestObservationsNear returns the latest observation of each of the n
+// stations closest to the point that have reported since since, nearest
+// first.
+func (s *Store) LatestObservationsNear(ctx context.Context, lat, lon float64, n int, since time.Time) ([]weather.StationObservation, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT s.fmisid, s.name, ST_Y(s.geom::geometry), ST_X(s.geom::geometry), COALESCE(s.wmo_code, ''),
+		        s.elevation_m, COALESCE(s.station_type, ''), COALESCE(s.region, ''),
+		        ST_Distance(s.geom, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography),
+		        o.observed_at, o.temperature, o.wind_speed, o.wind_gust, o.wind_dir, o.humidity, o.dew_point,
+		        o.pressure, o.precip_1h, o.precip_intensity, o.snow_depth, o.visibility, o.total_cloud_cover, o.weather_code, o.extra
+		 FROM stations s
+		 JOIN LATERAL (
+		   SELECT * FROM observations o
+		   WHERE o.fmisid = s.fmisid AND o.observed_at >= $4
+		   ORDER BY observed_at DESC
+		   LIMIT 1
+		 ) o ON true
+		 ORDER BY s.geom <-> ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography
+		 LIMIT $3`,
+		lon, lat, n, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("latest observations near: %w", err)
+	}
+	defer rows.Close()
+
+	var result []weather.StationObservation
+	for rows.Next() {
+		var so weather.StationObservation
+		st, o := &so.Station, &so.Observation
+		var distMeters float64
+		var extraRaw []byte
+		if err := rows.Scan(
+			&st.FMISID, &st.Name, &st.Lat, &st.Lon, &st.WMOCode, &st.ElevationM, &st.Type, &st.Region, &distMeters,
+			&o.ObservedAt, &o.Temperature, &o.WindSpeed, &o.WindGust, &o.WindDir, &o.Humidity, &o.DewPoint,
+			&o.Pressure, &o.Precip1h, &o.PrecipIntensity, &o.SnowDepth, &o.Visibility, &o.TotalCloudCover, &o.WeatherCode, &extraRaw,
+		); err != nil {
+			return nil, fmt.Errorf("scan latest observation near: %w", err)
+		}
+		o.FMISID = st.FMISID
+		o.ExtraNumericParams = decodeNumericExtras(extraRaw)
+		so.DistanceKM = distMeters / 1000.0
+		result = append(result, so)
+	}
+	return result, rows.Err()
+}
+
+// ObservationStats aggregates a station's observations in [from, to) by
+// day or month (period) in loc. Observations come every 10 minutes with a
+// trailing one-hour precipitation sum, so only the on-the-hour samples are
+// added up for the precipitation total.
+func (s *Store) ObservationStats(ctx context.Context, fmisid int, period string, loc *time.Location, from, to time.Time) ([]weather.ObservationStats, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT date_trunc($2, observed_at AT TIME ZONE $3) AS bucket,
+		        COUNT(*), MIN(temperature), MAX(temperature), AVG(temperature),
+		        SUM(precip_1h) FILTER (WHERE EXTRACT(MINUTE FROM observed_at) = 0),
+		        MAX(wind_gust), AVG(wind_speed)
+		 FROM observations
+		 WHERE fmisid = $1 AND observed_at >= $4 AND observed_at < $5
+		 GROUP BY bucket
+		 ORDER BY bucket`,
+		fmisid, period, loc.String(), from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("observation stats: %w", err)
+	}
+	defer rows.Close()
+
+	var result []weather.ObservationStats
+	for rows.Next() {
+		var st weather.ObservationStats
+		var bucket time.Time
+		if err := rows.Scan(&bucket, &st.Samples, &st.TempMin, &st.TempMax, &st.TempAvg,
+			&st.PrecipTotal, &st.GustMax, &st.WindSpeedAvg); err != nil {
+			return nil, fmt.Errorf("scan observation stats: %w", err)
+		}
+		// The bucket is a local wall-clock time without a zone.
+		st.Start = time.Date(bucket.Year(), bucket.Month(), bucket.Day(), 0, 0, 0, 0, loc)
+		result = append(result, st)
+	}
+	return result, rows.Err()
+}
+
+// ObservationsRange returns a station's observations with observed_at in
+// [from, to], oldest first.
+func (s *Store) ObservationsRange(ctx context.Context, fmisid int, from, to time.Time) ([]weather.Observation, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT fmisid, observed_at, temperature, wind_speed, wind_gust, wind_dir, humidity, dew_point,
+		        pressure, precip_1h, precip_intensity, snow_depth, visibility, total_cloud_cover, weather_code, extra
+		 FROM observations
+		 WHERE fmisid = $1 AND observed_at BETWEEN $2 AND $3
+		 ORDER BY observed_at`,
+		fmisid, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("observations range: %w", err)
+	}
+	defer rows.Close()
+
+	var result []weather.Observation
+	for rows.Next() {
+		var o weather.Observation
+		var extraRaw []byte
+		if err := rows.Scan(
+			&o.FMISID, &o.ObservedAt, &o.Temperature, &o.WindSpeed, &o.WindGust, &o.WindDir, &o.Humidity, &o.DewPoint,
+			&o.Pressure, &o.Precip1h, &o.PrecipIntensity, &o.SnowDepth, &o.Visibility, &o.TotalCloudCover, &o.WeatherCode, &extraRaw,
+		); err != nil {
+			return nil, fmt.Errorf("scan observation: %w", err)
+		}
+		o.ExtraNumericParams = decodeNumericExtras(extraRaw)
+		result = append(result, o)
+	}
+	return result, rows.Err()
+}
+
+func (s *Store) UpsertCustomObservation(ctx context.Context, o weather.CustomObservation) error {
+	batch := &pgx.Batch{}
+	batch.Queue(
+		`INSERT INTO custom_stations (client_id, station_id, geom, source)
+		 VALUES ($1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, $5)
+		 ON CONFLICT (client_id, station_id) DO UPDATE SET
+		   geom = ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, source = $5`,
+		o.ClientID, o.StationID, o.Lon, o.Lat, o.Source,
+	)
+	batch.Queue(
+		`INSERT INTO custom_observations (
+			client_id, station_id, observed_at, temperature, humidity, dew_point,
+			wind_speed, wind_gust, wind_dir, pressure, precip_1h
+		)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		 ON CONFLICT (client_id, station_id, observed_at) DO UPDATE SET
+		   temperature = $4, humidity = $5, dew_point = $6, wind_speed = $7, wind_gust = $8,
+		   wind_dir = $9, pressure = $10, precip_1h = $11`,
+		o.ClientID, o.StationID, o.ObservedAt, o.Temperature, o.Humidity, o.DewPoint,
+		o.WindSpeed, o.WindGust, o.WindDir, o.Pressure, o.Precip1h,
+	)
+	br := s.pool.SendBatch(ctx, batch)
+	defer br.Close()
+	for range 2 {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("upsert custom observation: %w", err)
+		}
+	}
+	return nil
+}
+
+// RegisterCustomStation creates or moves the personal station and sets its
+// ingest token hash, replacing any earlier token.
+func (s *Store) RegisterCustomStation(ctx context.Context, st weather.CustomStation, tokenHash []byte) error {
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO custom_stations (client_id, station_id, geom, source, ingest_format, token_hash)
+		 VALUES ($1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, $5, $5, $6)
+		 ON CONFLICT (client_id, station_id) DO UPDATE SET
+		   geom = ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, source = $5, ingest_format = $5, token_hash = $6`,
+		st.ClientID, st.StationID, st.Lon, st.Lat, st.Format, tokenHash,
+	)
+	if err != nil {
+		return fmt.Errorf("register custom station: %w", err)
+	}
+	return nil
+}
+
+// CustomStationByToken returns the personal station registered with the
+// token hash, or nil.
+func (s *Store) CustomStationByToken(ctx context.Context, tokenHash []byte) (*weather.CustomStation, error) {
+	var st weather.CustomStation
+	err := s.pool.QueryRow(ctx,
+		`SELECT client_id, station_id, ingest_format, ST_Y(geom::geometry), ST_X(geom::geometry)
+		 FROM custom_stations
+		 WHERE token_hash = $1`,
+		tokenHash,
+	).Scan(&st.ClientID, &st.StationID, &st.Format, &st.Lat, &st.Lon)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("custom station by token: %w", err)
+	}
+	return &st, nil
+}
+
+// NetatmoRefreshToken returns the refresh token last saved for the API
+// client's Netatmo account linked with linkedHash, or "".
+func (s *Store) NetatmoRefreshToken(ctx context.Context, clientID string, linkedHash []byte) (string, error) {
+	var token string
+	err := s.pool.QueryRow(ctx,
+		`SELECT refresh_token FROM netatmo_tokens
+		 WHERE client_id = $1 AND linked_token_hash = $2`,
+		clientID, linkedHash,
+	).Scan(&token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("netatmo refresh token: %w", err)
+	}
+	return token, nil
+}
+
+// SaveNetatmoRefreshToken stores the rotated refresh token for the API
+// client's Netatmo account, replacing any from an earlier link.
+func (s *Store) SaveNetatmoRefreshToken(ctx context.Context, clientID string, linkedHash []byte, refreshToken string) error {
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO netatmo_tokens (client_id, linked_token_hash, refresh_token, updated_at)
+		 VALUES ($1, $2, $3, now())
+		 ON CONFLICT (client_id) DO UPDATE SET
+		   linked_token_hash = EXCLUDED.linked_token_hash, refresh_token = EXCLUDED.refresh_token, updated_at = now()`,
+		clientID, linkedHash, refreshToken,
+	)
+	if err != nil {
+		return fmt.Errorf("save netatmo refresh token: %w", err)
+	}
+	return nil
+}
+
+// NearestCustomObservation returns the latest reading from the client's
+// closest personal station that has reported within maxAge, or nil.
+func (s *Store) NearestCustomObservation(ctx context.Context, clientID string, lat, lon float64, maxAge time.Duration) (*weather.CustomObservation, float64, error) {
+	var o weather.CustomObservation
+	var distMeters float64
+	err := s.pool.QueryRow(ctx,
+		`SELECT cs.client_id, cs.station_id, cs.source, ST_Y(cs.geom::geometry), ST_X(cs.geom::geometry),
+		        o.observed_at, o.temperature, o.humidity, o.dew_point, o.wind_speed, o.wind_gust,
+		        o.wind_dir, o.pressure, o.precip_1h,
+		        ST_Distance(cs.geom, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography)
+		 FROM custom_stations cs
+		 JOIN LATERAL (
+		   SELECT * FROM custom_observations co
+		   WHERE co.client_id = cs.client_id AND co.station_id = cs.station_id
+		     AND co.observed_at >= $4
+		   ORDER BY co.observed_at DESC
+		   LIMIT 1
+		 ) o ON TRUE
+		 WHERE cs.client_id = $1
+		 ORDER BY cs.geom <-> ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography
+		 LIMIT 1`,
+		clientID, lon, lat, time.Now().Add(-maxAge),
+	).Scan(
+		&o.ClientID, &o.StationID, &o.Source, &o.Lat, &o.Lon,
+		&o.ObservedAt, &o.Temperature, &o.Humidity, &o.DewPoint, &o.WindSpeed, &o.WindGust,
+		&o.WindDir, &o.Pressure, &o.Precip1h,
+		&distMeters,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("nearest custom observation: %w", err)
+	}
+	return &o, distMeters / 1000.0, nil
+}
+
 func (s *Store) GetLatestTemperatureSamplesInBBox(ctx context.Context, minLon, minLat, maxLon, maxLat float64, limit int) ([]weather.TemperatureSample, error) {
 	if limit <= 0 {
 		limit = 300
@@ -222,6 +576,9 @@ func decodeNumericExtras(raw []byte) map[string]float64 {
 	return result
 }
 
+// UpsertForecasts stores daily forecasts by grid point and date. A
+// long-range ECMWF day never replaces one from a shorter-range model, so
+// stitched fetches and later edited-only fetches don't clobber each other.
 func (s *Store) UpsertForecasts(ctx context.Context, forecasts []weather.DailyForecast) error {
 	batch := &pgx.Batch{}
 	for _, f := range forecasts {
@@ -234,9 +591,10 @@ func (s *Store) UpsertForecasts(ctx context.Context, forecasts []weather.DailyFo
 				hourly_maximum_gust_max, hourly_maximum_wind_speed_max, pop_avg, probability_thunderstorm_avg,
 				potential_precipitation_form_mode, potential_precipitation_type_mode, precipitation_form_mode, precipitation_type_mode,
 				radiation_global_avg, radiation_lw_avg, weather_number_mode, weather_symbol3_mode, wind_ums_avg, wind_vms_avg, wind_vector_ms_avg,
-				uv_index_avg
+				uv_index_max, sunshine_hours, day_length_hours, schema_version,
+				temp_day_max, temp_day_avg, temp_night_min, temp_night_avg, source, precip_hours_counted, snow_accumulation_mm
 			)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46, $47, $48, $49, $50)
 			 ON CONFLICT (grid_lat, grid_lon, forecast_for) DO UPDATE SET
 			   fetched_at = $4, temp_high = $5, temp_low = $6, temp_avg = $7, wind_speed = $8, wind_direction = $9,
 			   humidity_avg = $10, precip_mm = $11, precipitation_1h_sum = $12, symbol = $13, dew_point_avg = $14,
@@ -246,7 +604,10 @@ func (s *Store) UpsertForecasts(ctx context.Context, forecasts []weather.DailyFo
 			   probability_thunderstorm_avg = $28, potential_precipitation_form_mode = $29, potential_precipitation_type_mode = $30,
 			   precipitation_form_mode = $31, precipitation_type_mode = $32, radiation_global_avg = $33, radiation_lw_avg = $34,
 			   weather_number_mode = $35, weather_symbol3_mode = $36, wind_ums_avg = $37, wind_vms_avg = $38, wind_vector_ms_avg = $39,
-			   uv_index_avg = $40`,
+			   uv_index_max = $40, sunshine_hours = $41, day_length_hours = $42, schema_version = $43,
+			   temp_day_max = $44, temp_day_avg = $45, temp_night_min = $46, temp_night_avg = $47, source = $48,
+			   precip_hours_counted = $49, snow_accumulation_mm = $50
+			 WHERE forecasts.source = 'ecmwf' OR EXCLUDED.source <> 'ecmwf'`,
 			f.GridLat, f.GridLon, f.Date, f.FetchedAt, f.TempHigh, f.TempLow,
 			f.TempAvg, f.WindSpeed, f.WindDir, f.HumidityAvg, f.PrecipMM, f.Precip1hSum, f.Symbol,
 			f.DewPointAvg, f.FogIntensityAvg, f.FrostProbabilityAvg, f.SevereFrostProbabilityAvg, f.GeopHeightAvg, f.PressureAvg,
@@ -254,7 +615,8 @@ func (s *Store) UpsertForecasts(ctx context.Context, forecasts []weather.DailyFo
 			f.HourlyMaximumGustMax, f.HourlyMaximumWindSpeedMax, f.PoPAvg, f.ProbabilityThunderstormAvg,
 			f.PotentialPrecipitationFormMode, f.PotentialPrecipitationTypeMode, f.PrecipitationFormMode, f.PrecipitationTypeMode,
 			f.RadiationGlobalAvg, f.RadiationLWAvg, f.WeatherNumberMode, f.WeatherSymbol3Mode, f.WindUMSAvg, f.WindVMSAvg, f.WindVectorMSAvg,
-			f.UVIndexAvg,
+			f.UVIndexMax, f.SunshineHours, f.DayLengthHours, f.SchemaVersion,
+			f.TempDayMax, f.TempDayAvg, f.TempNightMin, f.TempNightAvg, forecastSource(f.Source), f.PrecipHoursCounted, f.SnowAccumulationMM,
 		)
 	}
 	br := s.pool.SendBatch(ctx, batch)
@@ -267,6 +629,15 @@ func (s *Store) UpsertForecasts(ctx context.Context, forecasts []weather.DailyFo
 	return nil
 }
 
+// forecastSource defaults rows without a model to the edited forecast,
+// matching the column default.
+func forecastSource(source string) string {
+	if source == "" {
+		return weather.ForecastSourceEdited
+	}
+	return source
+}
+
 func (s *Store) GetForecasts(ctx context.Context, gridLat, gridLon float64) ([]weather.DailyForecast, error) {
 	rows, err := s.pool.Query(ctx,
 		`SELECT grid_lat, grid_lon, forecast_for, fetched_at, temp_high, temp_low,
@@ -276,12 +647,13 @@ func (s *Store) GetForecasts(ctx context.Context, gridLat, gridLon float64) ([]w
 		        hourly_maximum_gust_max, hourly_maximum_wind_speed_max, pop_avg, probability_thunderstorm_avg,
 		        potential_precipitation_form_mode, potential_precipitation_type_mode, precipitation_form_mode, precipitation_type_mode,
 		        radiation_global_avg, radiation_lw_avg, weather_number_mode, weather_symbol3_mode, wind_ums_avg, wind_vms_avg, wind_vector_ms_avg,
-		        uv_index_avg
+		        uv_index_max, sunshine_hours, day_length_hours, schema_version,
+		        temp_day_max, temp_day_avg, temp_night_min, temp_night_avg, source, precip_hours_counted, snow_accumulation_mm
 		 FROM forecasts
 		 WHERE grid_lat = $1 AND grid_lon = $2 AND forecast_for >= CURRENT_DATE
 		 ORDER BY forecast_for
-		 LIMIT 11`,
-		gridLat, gridLon,
+		 LIMIT $3`,
+		gridLat, gridLon, weather.MaxForecastDays,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("get forecasts: %w", err)
@@ -299,7 +671,8 @@ func (s *Store) GetForecasts(ctx context.Context, gridLat, gridLon float64) ([]w
 			&f.HourlyMaximumGustMax, &f.HourlyMaximumWindSpeedMax, &f.PoPAvg, &f.ProbabilityThunderstormAvg,
 			&f.PotentialPrecipitationFormMode, &f.PotentialPrecipitationTypeMode, &f.PrecipitationFormMode, &f.PrecipitationTypeMode,
 			&f.RadiationGlobalAvg, &f.RadiationLWAvg, &f.WeatherNumberMode, &f.WeatherSymbol3Mode, &f.WindUMSAvg, &f.WindVMSAvg, &f.WindVectorMSAvg,
-			&f.UVIndexAvg,
+			&f.UVIndexMax, &f.SunshineHours, &f.DayLengthHours, &f.SchemaVersion,
+			&f.TempDayMax, &f.TempDayAvg, &f.TempNightMin, &f.TempNightAvg, &f.Source, &f.PrecipHoursCounted, &f.SnowAccumulationMM,
 		); err != nil {
 			return nil, err
 		}
@@ -319,14 +692,20 @@ func (s *Store) UpsertHourlyForecasts(ctx context.Context, gridLat, gridLon floa
 		batch.Queue(
 			`INSERT INTO hourly_forecasts (
 				grid_lat, grid_lon, forecast_time, fetched_at,
-				temperature, wind_speed, wind_direction, humidity, precipitation_1h, symbol, uv_cumulated
+				temperature, wind_speed, wind_direction, humidity, precipitation_1h, symbol,
+				uv_cumulated, cloud_cover, fog_intensity, schema_version, pop,
+				wind_gust, pressure, dew_point, uv_index
 			)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
 			 ON CONFLICT (grid_lat, grid_lon, forecast_time) DO UPDATE SET
 			   fetched_at = $4, temperature = $5, wind_speed = $6, wind_direction = $7,
-			   humidity = $8, precipitation_1h = $9, symbol = $10, uv_cumulated = $11`,
+			   humidity = $8, precipitation_1h = $9, symbol = $10, uv_cumulated = $11, cloud_cover = $12,
+			   fog_intensity = $13, schema_version = $14, pop = $15,
+			   wind_gust = $16, pressure = $17, dew_point = $18, uv_index = $19`,
 			gridLat, gridLon, h.Time, fetchedAt,
-			h.Temperature, h.WindSpeed, h.WindDir, h.Humidity, h.Precip1h, h.Symbol, h.UVCumulated,
+			h.Temperature, h.WindSpeed, h.WindDir, h.Humidity, h.Precip1h, h.Symbol,
+			h.UVCumulated, h.CloudCover, h.FogIntensity, h.SchemaVersion, h.PoP,
+			h.WindGust, h.Pressure, h.DewPoint, h.UVIndex,
 		)
 	}
 	br := s.pool.SendBatch(ctx, batch)
@@ -348,7 +727,9 @@ func (s *Store) GetHourlyForecasts(ctx context.Context, gridLat, gridLon float64
 		limit = 12
 	}
 	rows, err := s.pool.Query(ctx,
-		`SELECT forecast_time, fetched_at, temperature, wind_speed, wind_direction, humidity, precipitation_1h, symbol, uv_cumulated
+		`SELECT forecast_time, fetched_at, temperature, wind_speed, wind_direction, humidity, precipitation_1h, symbol,
+		        uv_cumulated, cloud_cover, fog_intensity, schema_version, pop,
+		        wind_gust, pressure, dew_point, uv_index
 		 FROM hourly_forecasts
 		 WHERE grid_lat = $1 AND grid_lon = $2 AND forecast_time >= date_trunc('hour', NOW())
 		 ORDER BY forecast_time
@@ -364,7 +745,9 @@ func (s *Store) GetHourlyForecasts(ctx context.Context, gridLat, gridLon float64
 	for rows.Next() {
 		var h weather.HourlyForecast
 		if err := rows.Scan(
-			&h.Time, &h.FetchedAt, &h.Temperature, &h.WindSpeed, &h.WindDir, &h.Humidity, &h.Precip1h, &h.Symbol, &h.UVCumulated,
+			&h.Time, &h.FetchedAt, &h.Temperature, &h.WindSpeed, &h.WindDir, &h.Humidity, &h.Precip1h, &h.Symbol,
+			&h.UVCumulated, &h.CloudCover, &h.FogIntensity, &h.SchemaVersion, &h.PoP,
+			&h.WindGust, &h.Pressure, &h.DewPoint, &h.UVIndex,
 		); err != nil {
 			return nil, err
 		}
@@ -536,3 +919,673 @@ func (s *Store) GetClimateNormals(ctx context.Context, fmisid int, period string
 	}
 	return normals, rows.Err()
 }
+
+// subscriptionSnapshotDay is the subset of a daily forecast the diff engine
+// compares, stored as JSONB on the subscription row.
+type subscriptionSnapshotDay struct {
+	Date     string   `json:"date"`
+	TempHigh *float64 `json:"temp_high,omitempty"`
+	TempLow  *float64 `json:"temp_low,omitempty"`
+	PrecipMM *float64 `json:"precip_mm,omitempty"`
+	PoPAvg   *float64 `json:"pop_avg,omitempty"`
+}
+
+func (s *Store) CreateSubscription(ctx context.Context, sub weather.ForecastSubscription) (weather.ForecastSubscription, error) {
+	err := s.pool.QueryRow(ctx,
+		`INSERT INTO forecast_subscriptions (client_id, lat, lon, webhook_url)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`,
+		sub.ClientID, sub.Lat, sub.Lon, sub.WebhookURL,
+	).Scan(&sub.ID, &sub.CreatedAt)
+	if err != nil {
+		return sub, fmt.Errorf("insert subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *Store) DeleteSubscription(ctx context.Context, clientID string, id int64) (bool, error) {
+	tag, err := s.pool.Exec(ctx,
+		`DELETE FROM forecast_subscriptions WHERE id = $1 AND client_id = $2`,
+		id, clientID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("delete subscription: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (s *Store) ListSubscriptions(ctx context.Context) ([]weather.ForecastSubscription, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, client_id, lat, lon, webhook_url, snapshot, created_at
+		 FROM forecast_subscriptions
+		 ORDER BY id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []weather.ForecastSubscription
+	for rows.Next() {
+		var sub weather.ForecastSubscription
+		var snapshotRaw []byte
+		if err := rows.Scan(&sub.ID, &sub.ClientID, &sub.Lat, &sub.Lon, &sub.WebhookURL, &snapshotRaw, &sub.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan subscription: %w", err)
+		}
+		sub.Snapshot = decodeSubscriptionSnapshot(snapshotRaw)
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (s *Store) UpdateSubscriptionSnapshot(ctx context.Context, id int64, snapshot []weather.DailyForecast) error {
+	_, err := s.pool.Exec(ctx,
+		`UPDATE forecast_subscriptions SET snapshot = $2 WHERE id = $1`,
+		id, encodeSubscriptionSnapshot(snapshot),
+	)
+	if err != nil {
+		return fmt.Errorf("update subscription snapshot: %w", err)
+	}
+	return nil
+}
+
+func encodeSubscriptionSnapshot(forecasts []weather.DailyForecast) []byte {
+	days := make([]subscriptionSnapshotDay, len(forecasts))
+	for i, f := range forecasts {
+		days[i] = subscriptionSnapshotDay{
+			Date:     f.Date.Format("2006-01-02"),
+			TempHigh: f.TempHigh,
+			TempLow:  f.TempLow,
+			PrecipMM: f.PrecipMM,
+			PoPAvg:   f.PoPAvg,
+		}
+	}
+	b, err := json.Marshal(days)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func decodeSubscriptionSnapshot(raw []byte) []weather.DailyForecast {
+	if len(raw) == 0 {
+		return nil
+	}
+	var days []subscriptionSnapshotDay
+	if err := json.Unmarshal(raw, &days); err != nil {
+		return nil
+	}
+	forecasts := make([]weather.DailyForecast, 0, len(days))
+	for _, d := range days {
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			continue
+		}
+		forecasts = append(forecasts, weather.DailyForecast{
+			Date:     date,
+			TempHigh: d.TempHigh,
+			TempLow:  d.TempLow,
+			PrecipMM: d.PrecipMM,
+			PoPAvg:   d.PoPAvg,
+		})
+	}
+	return forecasts
+}
+
+func (s *Store) Heartbeat(ctx context.Context, instanceID string) error {
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO fetcher_instances (instance_id, seen_at)
+		 VALUES ($1, NOW())
+		 ON CONFLICT (instance_id) DO UPDATE SET seen_at = NOW()`,
+		instanceID,
+	)
+	if err != nil {
+		return fmt.Errorf("fetcher heartbeat: %w", err)
+	}
+	return nil
+}
+
+// LiveInstances returns the fetcher instances that heartbeated within the
+// window, sorted by ID, and prunes long-dead ones.
+func (s *Store) LiveInstances(ctx context.Context, within time.Duration) ([]string, error) {
+	cutoff := time.Now().Add(-within)
+	if _, err := s.pool.Exec(ctx,
+		`DELETE FROM fetcher_instances WHERE seen_at < $1`,
+		cutoff.Add(-24*time.Hour),
+	); err != nil {
+		return nil, fmt.Errorf("prune fetcher instances: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT instance_id FROM fetcher_instances WHERE seen_at >= $1 ORDER BY instance_id`,
+		cutoff,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("live fetcher instances: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan fetcher instance: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// UpsertWarnings replaces the stored warnings with the current feed: the
+// given warnings are inserted or updated, and any warning that has expired
+// or is no longer in the feed is deleted.
+func (s *Store) UpsertWarnings(ctx context.Context, warnings []weather.Warning) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin warnings upsert: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ids := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		// CAP polygons may self-intersect; ST_MakeValid repairs them and
+		// ST_CollectionExtract keeps only the polygonal parts.
+		_, err := tx.Exec(ctx,
+			`INSERT INTO warnings (id, event, severity, headline, description, area_desc, onset, expires, geom, updated_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8,
+			         ST_Multi(ST_CollectionExtract(ST_MakeValid(ST_GeomFromText($9, 4326)), 3)), NOW())
+			 ON CONFLICT (id) DO UPDATE SET
+				event = EXCLUDED.event,
+				severity = EXCLUDED.severity,
+				headline = EXCLUDED.headline,
+				description = EXCLUDED.description,
+				area_desc = EXCLUDED.area_desc,
+				onset = EXCLUDED.onset,
+				expires = EXCLUDED.expires,
+				geom = EXCLUDED.geom,
+				updated_at = NOW()`,
+			w.ID, w.Event, w.Severity, w.Headline, w.Description, w.AreaDesc, w.Onset, w.Expires, multiPolygonWKT(w.Polygons),
+		)
+		if err != nil {
+			return fmt.Errorf("upsert warning %s: %w", w.ID, err)
+		}
+		ids = append(ids, w.ID)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM warnings WHERE expires <= NOW() OR NOT (id = ANY($1))`,
+		ids,
+	); err != nil {
+		return fmt.Errorf("prune warnings: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// WarningsForPoint returns the warnings whose area contains the point and
+// that have not expired at the given time, by onset.
+func (s *Store) WarningsForPoint(ctx context.Context, lat, lon float64, at time.Time) ([]weather.Warning, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT id, event, severity, headline, description, area_desc, onset, expires
+		 FROM warnings
+		 WHERE ST_Intersects(geom, ST_SetSRID(ST_MakePoint($1, $2), 4326))
+		   AND expires > $3
+		 ORDER BY onset, id`,
+		lon, lat, at,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("warnings for point: %w", err)
+	}
+	defer rows.Close()
+
+	var warnings []weather.Warning
+	for rows.Next() {
+		var w weather.Warning
+		if err := rows.Scan(&w.ID, &w.Event, &w.Severity, &w.Headline, &w.Description, &w.AreaDesc, &w.Onset, &w.Expires); err != nil {
+			return nil, fmt.Errorf("scan warning: %w", err)
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings, rows.Err()
+}
+
+// lightningRetention is how long strikes are kept; older ones are pruned on
+// every upsert.
+const lightningRetention = 7 * 24 * time.Hour
+
+// UpsertLightning stores strikes, ignoring ones already stored, and prunes
+// strikes older than lightningRetention.
+func (s *Store) UpsertLightning(ctx context.Context, strikes []weather.LightningStrike) error {
+	batch := &pgx.Batch{}
+	for _, l := range strikes {
+		batch.Queue(
+			`INSERT INTO lightning_strikes (struck_at, lat, lon, geom, peak_current, multiplicity)
+			 VALUES ($1, $2, $3, ST_SetSRID(ST_MakePoint($3, $2), 4326)::geography, $4, $5)
+			 ON CONFLICT (struck_at, lat, lon) DO UPDATE SET
+			   peak_current = EXCLUDED.peak_current, multiplicity = EXCLUDED.multiplicity`,
+			l.Time, l.Lat, l.Lon, l.PeakCurrent, l.Multiplicity,
+		)
+	}
+	batch.Queue(`DELETE FROM lightning_strikes WHERE struck_at < $1`, time.Now().Add(-lightningRetention))
+	br := s.pool.SendBatch(ctx, batch)
+	defer br.Close()
+	for range strikes {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("upsert lightning strike: %w", err)
+		}
+	}
+	if _, err := br.Exec(); err != nil {
+		return fmt.Errorf("prune lightning strikes: %w", err)
+	}
+	return nil
+}
+
+// LightningNear returns the strikes within radiusKM of the point since the
+// given time, oldest first.
+func (s *Store) LightningNear(ctx context.Context, lat, lon, radiusKM float64, since time.Time) ([]weather.LightningStrike, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT struck_at, lat, lon, peak_current, multiplicity,
+		        ST_Distance(geom, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography)
+		 FROM lightning_strikes
+		 WHERE ST_DWithin(geom, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3)
+		   AND struck_at >= $4
+		 ORDER BY struck_at`,
+		lon, lat, radiusKM*1000, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("lightning near: %w", err)
+	}
+	defer rows.Close()
+
+	var strikes []weather.LightningStrike
+	for rows.Next() {
+		var l weather.LightningStrike
+		var distMeters float64
+		if err := rows.Scan(&l.Time, &l.Lat, &l.Lon, &l.PeakCurrent, &l.Multiplicity, &distMeters); err != nil {
+			return nil, fmt.Errorf("scan lightning strike: %w", err)
+		}
+		l.DistanceKM = distMeters / 1000.0
+		strikes = append(strikes, l)
+	}
+	return strikes, rows.Err()
+}
+
+// MaxThunderstormProbability returns the highest daily thunderstorm
+// probability (percent) among the stored forecasts for day, or 0 when there
+// are none.
+func (s *Store) MaxThunderstormProbability(ctx context.Context, day time.Time) (float64, error) {
+	var p float64
+	err := s.pool.QueryRow(ctx,
+		`SELECT COALESCE(MAX(probability_thunderstorm_avg), 0)
+		 FROM forecasts
+		 WHERE forecast_for = $1`,
+		day,
+	).Scan(&p)
+	if err != nil {
+		return 0, fmt.Errorf("max thunderstorm probability: %w", err)
+	}
+	return p, nil
+}
+
+// upsertNetworkStations stores stations in the table of one of FMI's
+// separate networks: air quality, marine or road stations. Those tables
+// hold just a name and position and are kept out of stations, so
+// NearestStation never resolves to a sensor without the usual weather
+// observations.
+func (s *Store) upsertNetworkStations(ctx context.Context, table string, stations []weather.Station) error {
+	batch := &pgx.Batch{}
+	for _, st := range stations {
+		batch.Queue(
+			`INSERT INTO `+table+` (fmisid, name, geom)
+			 VALUES ($1, $2, ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography)
+			 ON CONFLICT (fmisid) DO UPDATE SET name = $2, geom = ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography`,
+			st.FMISID, st.Name, st.Lon, st.Lat,
+		)
+	}
+	br := s.pool.SendBatch(ctx, batch)
+	defer br.Close()
+	for range stations {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("upsert %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// nearestNetworkStation returns the station in one of the separate network
+// tables closest to the point and its distance in km, or nil if the table
+// is empty.
+func (s *Store) nearestNetworkStation(ctx context.Context, table string, lat, lon float64) (*weather.Station, float64, error) {
+	var st weather.Station
+	var distMeters float64
+	err := s.pool.QueryRow(ctx,
+		`SELECT fmisid, name, ST_Y(geom::geometry), ST_X(geom::geometry),
+		        ST_Distance(geom, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography)
+		 FROM `+table+`
+		 ORDER BY geom <-> ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography
+		 LIMIT 1`,
+		lon, lat,
+	).Scan(&st.FMISID, &st.Name, &st.Lat, &st.Lon, &distMeters)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("nearest %s: %w", table, err)
+	}
+	return &st, distMeters / 1000.0, nil
+}
+
+// UpsertAirQualityStations stores the urban air quality stations.
+func (s *Store) UpsertAirQualityStations(ctx context.Context, stations []weather.Station) error {
+	return s.upsertNetworkStations(ctx, "air_quality_stations", stations)
+}
+
+// airQualityRetention is how long air quality measurements are kept.
+const airQualityRetention = 7 * 24 * time.Hour
+
+// UpsertAirQuality stores hourly air quality measurements and prunes those
+// older than airQualityRetention. Their stations must already be stored.
+func (s *Store) UpsertAirQuality(ctx context.Context, measurements []weather.AirQuality) error {
+	batch := &pgx.Batch{}
+	for _, aq := range measurements {
+		batch.Queue(
+			`INSERT INTO air_quality (fmisid, observed_at, pm25, pm10, o3, no2)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 ON CONFLICT (fmisid, observed_at) DO UPDATE SET
+			   pm25 = $3, pm10 = $4, o3 = $5, no2 = $6`,
+			aq.FMISID, aq.ObservedAt, aq.PM25, aq.PM10, aq.O3, aq.NO2,
+		)
+	}
+	batch.Queue(`DELETE FROM air_quality WHERE observed_at < $1`, time.Now().Add(-airQualityRetention))
+	br := s.pool.SendBatch(ctx, batch)
+	defer br.Close()
+	for range measurements {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("upsert air quality: %w", err)
+		}
+	}
+	if _, err := br.Exec(); err != nil {
+		return fmt.Errorf("prune air quality: %w", err)
+	}
+	return nil
+}
+
+// NearestAirQualityStation returns the air quality station closest to the point and its
+// distance in km, or nil if there are none.
+func (s *Store) NearestAirQualityStation(ctx context.Context, lat, lon float64) (*weather.Station, float64, error) {
+	return s.nearestNetworkStation(ctx, "air_quality_stations", lat, lon)
+}
+
+// LatestAirQuality returns the newest measurement from the station, or nil
+// if it has none.
+func (s *Store) LatestAirQuality(ctx context.Context, fmisid int) (*weather.AirQuality, error) {
+	var aq weather.AirQuality
+	err := s.pool.QueryRow(ctx,
+		`SELECT fmisid, observed_at, pm25, pm10, o3, no2
+		 FROM air_quality
+		 WHERE fmisid = $1
+		 ORDER BY observed_at DESC
+		 LIMIT 1`,
+		fmisid,
+	).Scan(&aq.FMISID, &aq.ObservedAt, &aq.PM25, &aq.PM10, &aq.O3, &aq.NO2)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("latest air quality: %w", err)
+	}
+	return &aq, nil
+}
+
+// UpsertMarineStations stores the wave buoys and mareographs.
+func (s *Store) UpsertMarineStations(ctx context.Context, stations []weather.Station) error {
+	return s.upsertNetworkStations(ctx, "marine_stations", stations)
+}
+
+// marineRetention is how long marine observations are kept.
+const marineRetention = 7 * 24 * time.Hour
+
+// UpsertMarineObservations stores marine observations and prunes those
+// older than marineRetention. Their stations must already be stored.
+func (s *Store) UpsertMarineObservations(ctx context.Context, observations []weather.MarineObservation) error {
+	batch := &pgx.Batch{}
+	for _, o := range observations {
+		batch.Queue(
+			`INSERT INTO marine_observations (fmisid, observed_at, wave_height, wave_direction, wave_period, water_temperature, sea_level)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7)
+			 ON CONFLICT (fmisid, observed_at) DO UPDATE SET
+			   wave_height = $3, wave_direction = $4, wave_period = $5, water_temperature = $6, sea_level = $7`,
+			o.FMISID, o.ObservedAt, o.WaveHeight, o.WaveDirection, o.WavePeriod, o.WaterTemperature, o.SeaLevel,
+		)
+	}
+	batch.Queue(`DELETE FROM marine_observations WHERE observed_at < $1`, time.Now().Add(-marineRetention))
+	br := s.pool.SendBatch(ctx, batch)
+	defer br.Close()
+	for range observations {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("upsert marine observation: %w", err)
+		}
+	}
+	if _, err := br.Exec(); err != nil {
+		return fmt.Errorf("prune marine observations: %w", err)
+	}
+	return nil
+}
+
+// NearestMarineStation returns the marine station closest to the point and its
+// distance in km, or nil if there are none.
+func (s *Store) NearestMarineStation(ctx context.Context, lat, lon float64) (*weather.Station, float64, error) {
+	return s.nearestNetworkStation(ctx, "marine_stations", lat, lon)
+}
+
+// LatestMarineObservation returns the newest observation from the station,
+// or nil if it has none.
+func (s *Store) LatestMarineObservation(ctx context.Context, fmisid int) (*weather.MarineObservation, error) {
+	var o weather.MarineObservation
+	err := s.pool.QueryRow(ctx,
+		`SELECT fmisid, observed_at, wave_height, wave_direction, wave_period, water_temperature, sea_level
+		 FROM marine_observations
+		 WHERE fmisid = $1
+		 ORDER BY observed_at DESC
+		 LIMIT 1`,
+		fmisid,
+	).Scan(&o.FMISID, &o.ObservedAt, &o.WaveHeight, &o.WaveDirection, &o.WavePeriod, &o.WaterTemperature, &o.SeaLevel)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("latest marine observation: %w", err)
+	}
+	return &o, nil
+}
+
+// UpsertRoadStations stores the road weather stations.
+func (s *Store) UpsertRoadStations(ctx context.Context, stations []weather.Station) error {
+	return s.upsertNetworkStations(ctx, "road_stations", stations)
+}
+
+// roadRetention is how long road observations are kept. Only the latest
+// one is served, and road stations report every few minutes.
+const roadRetention = 2 * 24 * time.Hour
+
+// UpsertRoadObservations stores road observations and prunes those older
+// than roadRetention. Their stations must already be stored.
+func (s *Store) UpsertRoadObservations(ctx context.Context, observations []weather.RoadObservation) error {
+	batch := &pgx.Batch{}
+	for _, o := range observations {
+		batch.Queue(
+			`INSERT INTO road_observations (fmisid, observed_at, road_temperature, air_temperature, condition)
+			 VALUES ($1, $2, $3, $4, $5)
+			 ON CONFLICT (fmisid, observed_at) DO UPDATE SET
+			   road_temperature = $3, air_temperature = $4, condition = $5`,
+			o.FMISID, o.ObservedAt, o.RoadTemperature, o.AirTemperature, o.Condition,
+		)
+	}
+	batch.Queue(`DELETE FROM road_observations WHERE observed_at < $1`, time.Now().Add(-roadRetention))
+	br := s.pool.SendBatch(ctx, batch)
+	defer br.Close()
+	for range observations {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("upsert road observation: %w", err)
+		}
+	}
+	if _, err := br.Exec(); err != nil {
+		return fmt.Errorf("prune road observations: %w", err)
+	}
+	return nil
+}
+
+// NearestRoadStation returns the road weather station closest to the point and its
+// distance in km, or nil if there are none.
+func (s *Store) NearestRoadStation(ctx context.Context, lat, lon float64) (*weather.Station, float64, error) {
+	return s.nearestNetworkStation(ctx, "road_stations", lat, lon)
+}
+
+// LatestRoadObservation returns the newest observation from the road
+// station, or nil if it has none.
+func (s *Store) LatestRoadObservation(ctx context.Context, fmisid int) (*weather.RoadObservation, error) {
+	var o weather.RoadObservation
+	err := s.pool.QueryRow(ctx,
+		`SELECT fmisid, observed_at, road_temperature, air_temperature, condition
+		 FROM road_observations
+		 WHERE fmisid = $1
+		 ORDER BY observed_at DESC
+		 LIMIT 1`,
+		fmisid,
+	).Scan(&o.FMISID, &o.ObservedAt, &o.RoadTemperature, &o.AirTemperature, &o.Condition)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("latest road observation: %w", err)
+	}
+	return &o, nil
+}
+
+// SearchPlaces returns up to limit places whose name matches q, ranking
+// exact matches first, then prefix matches, then by trigram similarity.
+// Matching is case-insensitive.
+func (s *Store) SearchPlaces(ctx context.Context, q string, limit int) ([]weather.Place, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT name, region, lat, lon, geoid
+		 FROM places
+		 WHERE lower(name) LIKE $2 || '%' OR lower(name) % $1
+		 ORDER BY lower(name) = $1 DESC,
+		          lower(name) LIKE $2 || '%' DESC,
+		          similarity(lower(name), $1) DESC,
+		          name
+		 LIMIT $3`,
+		strings.ToLower(q), escapeLike(strings.ToLower(q)), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search places: %w", err)
+	}
+	defer rows.Close()
+
+	var result []weather.Place
+	for rows.Next() {
+		var p weather.Place
+		if err := rows.Scan(&p.Name, &p.Region, &p.Lat, &p.Lon, &p.GeoID); err != nil {
+			return nil, fmt.Errorf("scan place: %w", err)
+		}
+		result = append(result, p)
+	}
+	return result, rows.Err()
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// multiPolygonWKT renders (lat, lon) rings as a WKT MULTIPOLYGON, which
+// uses lon lat order.
+func multiPolygonWKT(rings [][][2]float64) string {
+	var b strings.Builder
+	b.WriteString("MULTIPOLYGON(")
+	for i, ring := range rings {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString("((")
+		for j, p := range ring {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.FormatFloat(p[1], 'f', -1, 64))
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(p[0], 'f', -1, 64))
+		}
+		b.WriteString("))")
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// ForecastObservationPairs joins hourly forecasts valid in [from, to) with
+// the observation at the same time from the nearest station within
+// maxDistanceKM of the grid cell. Cells without such a station are skipped.
+func (s *Store) ForecastObservationPairs(ctx context.Context, from, to time.Time, maxDistanceKM float64) ([]weather.ForecastObservationPair, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT hf.grid_lat, hf.grid_lon, hf.forecast_time, hf.fetched_at,
+		        st.fmisid, st.distance_m / 1000.0,
+		        hf.temperature, hf.wind_speed, hf.humidity, hf.precipitation_1h,
+		        o.temperature, o.wind_speed, o.humidity, o.precip_1h
+		 FROM hourly_forecasts hf
+		 JOIN LATERAL (
+		   SELECT s.fmisid,
+		          ST_Distance(s.geom, ST_SetSRID(ST_MakePoint(hf.grid_lon, hf.grid_lat), 4326)::geography) AS distance_m
+		   FROM stations s
+		   ORDER BY s.geom <-> ST_SetSRID(ST_MakePoint(hf.grid_lon, hf.grid_lat), 4326)::geography
+		   LIMIT 1
+		 ) st ON st.distance_m <= $3 * 1000.0
+		 JOIN observations o ON o.fmisid = st.fmisid AND o.observed_at = hf.forecast_time
+		 WHERE hf.forecast_time >= $1 AND hf.forecast_time < $2
+		 ORDER BY hf.grid_lat, hf.grid_lon, hf.forecast_time`,
+		from, to, maxDistanceKM,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("forecast observation pairs: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []weather.ForecastObservationPair
+	for rows.Next() {
+		var p weather.ForecastObservationPair
+		if err := rows.Scan(
+			&p.GridLat, &p.GridLon, &p.ForecastTime, &p.FetchedAt,
+			&p.FMISID, &p.StationDistanceKM,
+			&p.ForecastTemperature, &p.ForecastWindSpeed, &p.ForecastHumidity, &p.ForecastPrecip1h,
+			&p.ObservedTemperature, &p.ObservedWindSpeed, &p.ObservedHumidity, &p.ObservedPrecip1h,
+		); err != nil {
+			return nil, fmt.Errorf("scan forecast observation pair: %w", err)
+		}
+		pairs = append(pairs, p)
+	}
+	return pairs, rows.Err()
+}
+
+// SunshineForecasts returns the stored sunshine estimates of every grid
+// cell's daily forecast for the given date.
+func (s *Store) SunshineForecasts(ctx context.Context, date time.Time) ([]weather.SunshineForecast, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT grid_lat, grid_lon, fetched_at, sunshine_hours
+		 FROM forecasts
+		 WHERE forecast_for = $1 AND sunshine_hours IS NOT NULL
+		 ORDER BY grid_lat, grid_lon`,
+		date.Format("2006-01-02"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sunshine forecasts: %w", err)
+	}
+	defer rows.Close()
+
+	var forecasts []weather.SunshineForecast
+	for rows.Next() {
+		var f weather.SunshineForecast
+		if err := rows.Scan(&f.GridLat, &f.GridLon, &f.FetchedAt, &f.SunshineHours); err != nil {
+			return nil, fmt.Errorf("scan sunshine forecast: %w", err)
+		}
+		forecasts = append(forecasts, f)
+	}
+	return forecasts, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"wby/internal/weather"
 )
@@ -46,3 +47,312 @@ func TestUpsertStations(t *testing.T) {
 		t.Errorf("expected distance < 1km, got %f", dist)
 	}
 }
+
+func TestMultiPolygonWKT(t *testing.T) {
+	rings := [][][2]float64{
+		{{60, 24}, {61, 24}, {61, 25.5}, {60, 24}},
+		{{65, 25}, {66, 25}, {66, 26}, {65, 25}},
+	}
+	want := "MULTIPOLYGON(((24 60,24 61,25.5 61,24 60)),((25 65,25 66,26 66,25 65)))"
+	if got := multiPolygonWKT(rings); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestWarningsForPoint(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	warnings := []weather.Warning{
+		{
+			ID: "test-active", Event: "Wind warning", Severity: "Moderate",
+			Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour),
+			Polygons: [][][2]float64{{{60, 24}, {61, 24}, {61, 25}, {60, 25}, {60, 24}}},
+		},
+		{
+			ID: "test-elsewhere", Event: "Forest fire warning", Severity: "Severe",
+			Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour),
+			Polygons: [][][2]float64{{{67, 24}, {68, 24}, {68, 25}, {67, 25}, {67, 24}}},
+		},
+	}
+	if err := s.UpsertWarnings(ctx, warnings); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.UpsertWarnings(context.Background(), nil) })
+
+	got, err := s.WarningsForPoint(ctx, 60.5, 24.5, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != "test-active" {
+		t.Fatalf("expected only test-active, got %+v", got)
+	}
+
+	got, err = s.WarningsForPoint(ctx, 60.5, 24.5, now.Add(2*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected no warnings after expiry, got %+v", got)
+	}
+}
+
+func TestLightningNear(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	peak := -14.2
+	strikes := []weather.LightningStrike{
+		{Time: now.Add(-10 * time.Minute), Lat: 61.50, Lon: 23.76, PeakCurrent: &peak},
+		{Time: now.Add(-20 * time.Minute), Lat: 61.45, Lon: 23.70},
+		{Time: now.Add(-5 * time.Minute), Lat: 65.00, Lon: 25.50},
+	}
+	if err := s.UpsertLightning(ctx, strikes); err != nil {
+		t.Fatal(err)
+	}
+	// Upserting again must not duplicate strikes.
+	if err := s.UpsertLightning(ctx, strikes); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.LightningNear(ctx, 61.5, 23.76, 20, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 nearby strikes, got %d", len(got))
+	}
+	if !got[0].Time.Before(got[1].Time) {
+		t.Errorf("expected strikes sorted by time, got %v then %v", got[0].Time, got[1].Time)
+	}
+	if got[1].PeakCurrent == nil || *got[1].PeakCurrent != peak || got[1].DistanceKM > 0.1 {
+		t.Errorf("unexpected strike %+v", got[1])
+	}
+}
+
+func TestAirQuality(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	if err := s.UpsertAirQualityStations(ctx, []weather.Station{
+		{FMISID: 100742, Name: "Helsinki Kallio 2", Lat: 60.18739, Lon: 24.95065},
+		{FMISID: 100662, Name: "Oulu keskusta", Lat: 65.01236, Lon: 25.47136},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Hour)
+	older, newer := 4.1, 5.3
+	if err := s.UpsertAirQuality(ctx, []weather.AirQuality{
+		{FMISID: 100742, ObservedAt: now.Add(-time.Hour), PM25: &older},
+		{FMISID: 100742, ObservedAt: now, PM25: &newer},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	st, dist, err := s.NearestAirQualityStation(ctx, 60.17, 24.94)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st == nil || st.FMISID != 100742 || dist > 5 {
+		t.Fatalf("unexpected nearest station %+v at %.1f km", st, dist)
+	}
+
+	aq, err := s.LatestAirQuality(ctx, 100742)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aq == nil || !aq.ObservedAt.Equal(now) || aq.PM25 == nil || *aq.PM25 != newer {
+		t.Errorf("unexpected latest air quality %+v", aq)
+	}
+
+	aq, err = s.LatestAirQuality(ctx, 100662)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aq != nil {
+		t.Errorf("expected no air quality for a station without measurements, got %+v", aq)
+	}
+}
+
+func TestMarineObservations(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	if err := s.UpsertMarineStations(ctx, []weather.Station{
+		{FMISID: 134220, Name: "Helsinki Suomenlahti aaltopoiju", Lat: 59.965, Lon: 25.235},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	wave := 0.9
+	if err := s.UpsertMarineObservations(ctx, []weather.MarineObservation{
+		{FMISID: 134220, ObservedAt: now, WaveHeight: &wave},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	st, dist, err := s.NearestMarineStation(ctx, 60.0, 25.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st == nil || st.FMISID != 134220 || dist > 10 {
+		t.Fatalf("unexpected nearest marine station %+v at %.1f km", st, dist)
+	}
+
+	o, err := s.LatestMarineObservation(ctx, 134220)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o == nil || o.WaveHeight == nil || *o.WaveHeight != wave || o.SeaLevel != nil {
+		t.Errorf("unexpected latest marine observation %+v", o)
+	}
+}
+
+func TestRoadObservations(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	if err := s.UpsertRoadStations(ctx, []weather.Station{
+		{FMISID: 100011, Name: "vt1 Espoo Nupuri", Lat: 60.2369, Lon: 24.6214},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	road, cond := -6.1, 7
+	if err := s.UpsertRoadObservations(ctx, []weather.RoadObservation{
+		{FMISID: 100011, ObservedAt: now, RoadTemperature: &road, Condition: &cond},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Road stations must not be picked up as regular weather stations.
+	if st, _, err := s.NearestStation(ctx, 60.2369, 24.6214); err == nil && st.FMISID == 100011 {
+		t.Fatal("expected NearestStation to ignore road stations")
+	}
+
+	st, dist, err := s.NearestRoadStation(ctx, 60.24, 24.62)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st == nil || st.FMISID != 100011 || dist > 1 {
+		t.Fatalf("unexpected nearest road station %+v at %.1f km", st, dist)
+	}
+
+	o, err := s.LatestRoadObservation(ctx, 100011)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o == nil || o.RoadTemperature == nil || *o.RoadTemperature != road || o.Condition == nil || *o.Condition != cond {
+		t.Errorf("unexpected latest road observation %+v", o)
+	}
+}
+
+func TestObservationStats(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	if err := s.UpsertStations(ctx, []weather.Station{{FMISID: 999101, Name: "Stats Test", Lat: 60.2, Lon: 24.9}}); err != nil {
+		t.Fatal(err)
+	}
+	loc := weather.StatsLocation()
+	day := time.Date(2026, 1, 20, 0, 0, 0, 0, loc)
+	temps := []float64{-8, -2, -5}
+	precip := []float64{0.4, 9.9, 0.6}
+	obs := []weather.Observation{
+		// 00:00 local is still the previous day in UTC.
+		{FMISID: 999101, ObservedAt: day, Temperature: &temps[0], Precip1h: &precip[0]},
+		// Off-hour samples carry a trailing hourly sum and are not added up.
+		{FMISID: 999101, ObservedAt: day.Add(10 * time.Minute), Temperature: &temps[1], Precip1h: &precip[1]},
+		{FMISID: 999101, ObservedAt: day.Add(23 * time.Hour), Temperature: &temps[2], Precip1h: &precip[2]},
+	}
+	if err := s.UpsertObservations(ctx, obs); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := s.ObservationStats(ctx, 999101, weather.StatsPeriodDay, loc, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected one day, got %d", len(stats))
+	}
+	st := stats[0]
+	if !st.Start.Equal(day) || st.Samples != 3 || *st.TempMin != -8 || *st.TempMax != -2 {
+		t.Errorf("unexpected stats %+v", st)
+	}
+	if st.PrecipTotal == nil || *st.PrecipTotal != 1.0 {
+		t.Errorf("expected 1.0 mm from on-the-hour samples, got %v", st.PrecipTotal)
+	}
+}
+
+func TestNearestStations(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	if err := s.UpsertStations(ctx, []weather.Station{
+		{FMISID: 999201, Name: "Nearby A", Lat: 69.50, Lon: 27.00},
+		{FMISID: 999202, Name: "Nearby B", Lat: 69.60, Lon: 27.00},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	temp := -20.0
+	observed := time.Now().UTC().Truncate(time.Minute)
+	if err := s.UpsertObservations(ctx, []weather.Observation{{FMISID: 999202, ObservedAt: observed, Temperature: &temp}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.NearestStations(ctx, 69.49, 27.0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].FMISID != 999201 || got[1].FMISID != 999202 {
+		t.Fatalf("unexpected nearest stations %+v", got)
+	}
+	if got[0].DistanceKM >= got[1].DistanceKM {
+		t.Errorf("expected stations ordered by distance, got %v then %v", got[0].DistanceKM, got[1].DistanceKM)
+	}
+	if got[0].LastObservedAt != nil || got[1].LastObservedAt == nil || !got[1].LastObservedAt.Equal(observed) {
+		t.Errorf("unexpected latest observation times %v, %v", got[0].LastObservedAt, got[1].LastObservedAt)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	if got := escapeLike(`50%_off\`); got != `50\%\_off\\` {
+		t.Errorf("escapeLike = %q", got)
+	}
+}
+
+func TestSearchPlaces(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	got, err := s.SearchPlaces(ctx, "kuo", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].Name != "Kuopio" {
+		t.Fatalf("expected Kuopio first for a prefix search, got %+v", got)
+	}
+
+	// A typo still finds the place through trigram similarity.
+	got, err = s.SearchPlaces(ctx, "tampre", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].Name != "Tampere" {
+		t.Fatalf("expected a fuzzy match on Tampere, got %+v", got)
+	}
+
+	got, err = s.SearchPlaces(ctx, "HELSINKI", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) == 0 || got[0].Name != "Helsinki" || got[0].GeoID == nil || *got[0].GeoID != 658225 {
+		t.Fatalf("expected an exact match on Helsinki, got %+v", got)
+	}
+}
@@ -0,0 +1,40 @@
+// Package version identifies the running build. The variables are set at
+// link time, e.g.
+//
+//	go build -ldflags "-X wby/internal/version.Version=v1.4.0 -X wby/internal/version.Commit=$(git rev-parse HEAD) -X wby/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// and are "dev" when they were not.
+package version
+
+import "runtime"
+
+var (
+	Version = "dev"
+	Commit  = "dev"
+	Date    = "dev"
+)
+
+// Info describes the running build.
+type Info struct {
+	Version   string
+	Commit    string
+	BuildDate string
+	GoVersion string
+}
+
+// Get returns the build information, with "dev" for anything set empty.
+func Get() Info {
+	return Info{
+		Version:   orDev(Version),
+		Commit:    orDev(Commit),
+		BuildDate: orDev(Date),
+		GoVersion: runtime.Version(),
+	}
+}
+
+func orDev(s string) string {
+	if s == "" {
+		return "dev"
+	}
+	return s
+}
@@ -0,0 +1,148 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"wby/internal/logging"
+)
+
+// AirQuality is one hourly air quality measurement from an FMI urban air
+// quality station. Concentrations are hourly means in µg/m³.
+type AirQuality struct {
+	FMISID     int
+	ObservedAt time.Time
+	PM25       *float64
+	PM10       *float64
+	O3         *float64
+	NO2        *float64
+}
+
+// AirQualityReading is the latest air quality near a location.
+type AirQualityReading struct {
+	Station    Station
+	DistanceKM float64
+	AirQuality
+	Index    int // 1 (good) to 5 (very poor); 0 when no pollutant was measured
+	Category string
+}
+
+// Air quality categories of the Finnish air quality index, in index order.
+const (
+	AirQualityGood         = "good"
+	AirQualitySatisfactory = "satisfactory"
+	AirQualityFair         = "fair"
+	AirQualityPoor         = "poor"
+	AirQualityVeryPoor     = "very_poor"
+)
+
+var airQualityCategories = []string{"", AirQualityGood, AirQualitySatisfactory, AirQualityFair, AirQualityPoor, AirQualityVeryPoor}
+
+// Upper bounds (µg/m³, hourly mean) of index levels 1 to 4 for each
+// pollutant; anything above the last bound is level 5.
+var (
+	pm25IndexBounds = [4]float64{10, 25, 50, 75}
+	pm10IndexBounds = [4]float64{20, 50, 100, 200}
+	o3IndexBounds   = [4]float64{60, 100, 140, 180}
+	no2IndexBounds  = [4]float64{40, 70, 150, 200}
+)
+
+const (
+	// airQualityMaxDistanceKM bounds how far the nearest station may be;
+	// urban measurements say little about air elsewhere.
+	airQualityMaxDistanceKM = 50
+	// airQualityMaxAge bounds how old the latest measurement may be.
+	airQualityMaxAge = 3 * time.Hour
+)
+
+// AirQualityIndex returns the Finnish air quality index, the worst level
+// of the measured pollutants, and its category. It returns 0 and "" when
+// nothing was measured.
+func AirQualityIndex(aq AirQuality) (int, string) {
+	index := 0
+	for _, p := range []struct {
+		value  *float64
+		bounds [4]float64
+	}{
+		{aq.PM25, pm25IndexBounds},
+		{aq.PM10, pm10IndexBounds},
+		{aq.O3, o3IndexBounds},
+		{aq.NO2, no2IndexBounds},
+	} {
+		if p.value == nil {
+			continue
+		}
+		level := 5
+		for i, bound := range p.bounds {
+			if *p.value <= bound {
+				level = i + 1
+				break
+			}
+		}
+		index = max(index, level)
+	}
+	return index, airQualityCategories[index]
+}
+
+// nearestAirQuality returns the latest measurement of the nearest air
+// quality station, or nil when there is none close enough or recent
+// enough. Air quality is supplementary, so store failures are logged and
+// the weather response is served without it.
+func (s *Service) nearestAirQuality(ctx context.Context, lat, lon float64, now time.Time) *AirQualityReading {
+	reading, err := s.lookupAirQuality(ctx, lat, lon, now)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to load air quality", "err", err, "lat", lat, "lon", lon)
+		return nil
+	}
+	return reading
+}
+
+// lookupAirQuality is nearestAirQuality reporting store failures.
+func (s *Service) lookupAirQuality(ctx context.Context, lat, lon float64, now time.Time) (*AirQualityReading, error) {
+	station, distKM, err := s.store.NearestAirQualityStation(ctx, lat, lon)
+	if err != nil {
+		return nil, fmt.Errorf("find air quality station: %w", err)
+	}
+	if station == nil || distKM > airQualityMaxDistanceKM {
+		return nil, nil
+	}
+	aq, err := s.store.LatestAirQuality(ctx, station.FMISID)
+	if err != nil {
+		return nil, fmt.Errorf("latest air quality at %d: %w", station.FMISID, err)
+	}
+	if aq == nil || now.Sub(aq.ObservedAt) > airQualityMaxAge {
+		return nil, nil
+	}
+	reading := &AirQualityReading{Station: *station, DistanceKM: distKM, AirQuality: *aq}
+	reading.Index, reading.Category = AirQualityIndex(*aq)
+	return reading, nil
+}
+
+// AirQualityEnvironmentProvider reports the air quality index of the
+// nearest urban air quality station, for registering as the air_quality
+// environment section. Level is the index category and Summary the
+// station name. The section is unavailable where no station is close
+// enough or has measured recently.
+func (s *Service) AirQualityEnvironmentProvider() EnvironmentProvider {
+	return environmentProviderFunc(s.airQualityProvider)
+}
+
+func (s *Service) airQualityProvider(ctx context.Context, lat, lon float64) (EnvironmentSection, error) {
+	reading, err := s.lookupAirQuality(ctx, lat, lon, time.Now())
+	if err != nil {
+		return EnvironmentSection{}, err
+	}
+	if reading == nil || reading.Index == 0 {
+		return EnvironmentSection{}, nil
+	}
+	index := float64(reading.Index)
+	observedAt := reading.ObservedAt.UTC()
+	return EnvironmentSection{
+		Available: true,
+		UpdatedAt: &observedAt,
+		Value:     &index,
+		Level:     reading.Category,
+		Summary:   reading.Station.Name,
+	}, nil
+}
@@ -0,0 +1,27 @@
+package weather
+
+import "testing"
+
+func TestAirQualityIndex(t *testing.T) {
+	tests := []struct {
+		name         string
+		aq           AirQuality
+		wantIndex    int
+		wantCategory string
+	}{
+		{"nothing measured", AirQuality{}, 0, ""},
+		{"clean air", AirQuality{PM25: ptr(4), NO2: ptr(12)}, 1, AirQualityGood},
+		{"bound is inclusive", AirQuality{PM10: ptr(50)}, 2, AirQualitySatisfactory},
+		{"worst pollutant wins", AirQuality{PM25: ptr(5), NO2: ptr(71.5)}, 3, AirQualityFair},
+		{"ozone poor", AirQuality{O3: ptr(150)}, 4, AirQualityPoor},
+		{"above every bound", AirQuality{PM25: ptr(80)}, 5, AirQualityVeryPoor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, category := AirQualityIndex(tt.aq)
+			if index != tt.wantIndex || category != tt.wantCategory {
+				t.Errorf("AirQualityIndex() = %d, %q, want %d, %q", index, category, tt.wantIndex, tt.wantCategory)
+			}
+		})
+	}
+}
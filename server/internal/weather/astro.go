@@ -0,0 +1,137 @@
+package weather
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// sunriseZenithDeg is the solar zenith angle at sunrise/sunset, accounting for
+// atmospheric refraction and the apparent radius of the solar disc.
+const sunriseZenithDeg = 90.833
+
+// SunTimes describes the solar day for a location and calendar date.
+// Sunrise and Sunset are nil when the sun does not cross the horizon,
+// in which case PolarDay or PolarNight is set instead.
+type SunTimes struct {
+	Sunrise    *time.Time
+	Sunset     *time.Time
+	DayLength  time.Duration
+	PolarDay   bool
+	PolarNight bool
+}
+
+// ComputeSunTimes returns sunrise, sunset and day length for the given
+// location and date using the NOAA solar calculator equations. Only the
+// year, month and day of date are used.
+func ComputeSunTimes(lat, lon float64, date time.Time) SunTimes {
+	noonUTC := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, time.UTC)
+	decl, eqTime := solarDeclinationAndEquationOfTime(noonUTC)
+
+	latRad := degToRad(lat)
+	declRad := degToRad(decl)
+	cosHA := math.Cos(degToRad(sunriseZenithDeg))/(math.Cos(latRad)*math.Cos(declRad)) - math.Tan(latRad)*math.Tan(declRad)
+
+	if cosHA > 1 {
+		return SunTimes{PolarNight: true}
+	}
+	if cosHA < -1 {
+		return SunTimes{DayLength: 24 * time.Hour, PolarDay: true}
+	}
+
+	haDeg := radToDeg(math.Acos(cosHA))
+	midnight := noonUTC.Add(-12 * time.Hour)
+	solarNoonMin := 720 - 4*lon - eqTime
+	sunrise := midnight.Add(minutesToDuration(solarNoonMin - 4*haDeg))
+	sunset := midnight.Add(minutesToDuration(solarNoonMin + 4*haDeg))
+
+	return SunTimes{
+		Sunrise:   &sunrise,
+		Sunset:    &sunset,
+		DayLength: minutesToDuration(8 * haDeg),
+	}
+}
+
+// withSunTimes returns a copy of forecasts with sunrise, sunset and the
+// polar flags for lat/lon, rounded to the minute. They depend on the exact
+// location rather than the forecast grid cell, so they are not stored.
+func withSunTimes(lat, lon float64, forecasts []DailyForecast) []DailyForecast {
+	out := slices.Clone(forecasts)
+	for i := range out {
+		sun := ComputeSunTimes(lat, lon, out[i].Date)
+		out[i].Sunrise = roundToMinute(sun.Sunrise)
+		out[i].Sunset = roundToMinute(sun.Sunset)
+		out[i].PolarDay = sun.PolarDay
+		out[i].PolarNight = sun.PolarNight
+	}
+	return out
+}
+
+func roundToMinute(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	rounded := t.Round(time.Minute)
+	return &rounded
+}
+
+// SolarElevation returns the geometric elevation of the sun above the horizon
+// in degrees for the given location and instant.
+func SolarElevation(lat, lon float64, t time.Time) float64 {
+	t = t.UTC()
+	decl, eqTime := solarDeclinationAndEquationOfTime(t)
+
+	minutes := float64(t.Hour()*60+t.Minute()) + float64(t.Second())/60
+	trueSolarTime := math.Mod(minutes+eqTime+4*lon, 1440)
+	if trueSolarTime < 0 {
+		trueSolarTime += 1440
+	}
+	hourAngle := trueSolarTime/4 - 180
+
+	latRad := degToRad(lat)
+	declRad := degToRad(decl)
+	cosZenith := math.Sin(latRad)*math.Sin(declRad) + math.Cos(latRad)*math.Cos(declRad)*math.Cos(degToRad(hourAngle))
+	cosZenith = math.Max(-1, math.Min(1, cosZenith))
+	return 90 - radToDeg(math.Acos(cosZenith))
+}
+
+// solarDeclinationAndEquationOfTime returns the solar declination in degrees
+// and the equation of time in minutes for the given instant.
+func solarDeclinationAndEquationOfTime(t time.Time) (float64, float64) {
+	julianDay := float64(t.Unix())/86400 + 2440587.5
+	jc := (julianDay - 2451545) / 36525
+
+	meanLong := math.Mod(280.46646+jc*(36000.76983+jc*0.0003032), 360)
+	meanAnom := 357.52911 + jc*(35999.05029-0.0001537*jc)
+	eccent := 0.016708634 - jc*(0.000042037+0.0000001267*jc)
+
+	meanAnomRad := degToRad(meanAnom)
+	center := math.Sin(meanAnomRad)*(1.914602-jc*(0.004817+0.000014*jc)) +
+		math.Sin(2*meanAnomRad)*(0.019993-0.000101*jc) +
+		math.Sin(3*meanAnomRad)*0.000289
+	omega := degToRad(125.04 - 1934.136*jc)
+	appLong := meanLong + center - 0.00569 - 0.00478*math.Sin(omega)
+
+	meanObliq := 23 + (26+(21.448-jc*(46.815+jc*(0.00059-jc*0.001813)))/60)/60
+	obliqCorr := degToRad(meanObliq + 0.00256*math.Cos(omega))
+
+	decl := radToDeg(math.Asin(math.Sin(obliqCorr) * math.Sin(degToRad(appLong))))
+
+	y := math.Pow(math.Tan(obliqCorr/2), 2)
+	meanLongRad := degToRad(meanLong)
+	eqTime := 4 * radToDeg(y*math.Sin(2*meanLongRad)-
+		2*eccent*math.Sin(meanAnomRad)+
+		4*eccent*y*math.Sin(meanAnomRad)*math.Cos(2*meanLongRad)-
+		0.5*y*y*math.Sin(4*meanLongRad)-
+		1.25*eccent*eccent*math.Sin(2*meanAnomRad))
+
+	return decl, eqTime
+}
+
+func degToRad(d float64) float64 { return d * math.Pi / 180 }
+
+func radToDeg(r float64) float64 { return r * 180 / math.Pi }
+
+func minutesToDuration(m float64) time.Duration {
+	return time.Duration(m * float64(time.Minute))
+}
@@ -0,0 +1,79 @@
+package weather
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestComputeSunTimes_HelsinkiMidsummer(t *testing.T) {
+	got := ComputeSunTimes(60.17, 24.94, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC))
+
+	if got.PolarDay || got.PolarNight {
+		t.Fatalf("unexpected polar flags: %+v", got)
+	}
+	if got.Sunrise == nil || got.Sunset == nil {
+		t.Fatal("expected sunrise and sunset")
+	}
+	// Helsinki midsummer: sunrise ~00:54 UTC, sunset ~19:50 UTC, ~18h56m of daylight.
+	if hours := got.DayLength.Hours(); math.Abs(hours-18.9) > 0.2 {
+		t.Fatalf("expected day length near 18.9h, got %.2f", hours)
+	}
+	wantSunrise := time.Date(2026, 6, 21, 0, 54, 0, 0, time.UTC)
+	if d := got.Sunrise.Sub(wantSunrise); d < -5*time.Minute || d > 5*time.Minute {
+		t.Fatalf("expected sunrise near %s, got %s", wantSunrise, got.Sunrise)
+	}
+}
+
+func TestSolarElevation_NoonVsMidnight(t *testing.T) {
+	noon := SolarElevation(60.17, 24.94, time.Date(2026, 6, 21, 10, 20, 0, 0, time.UTC))
+	midnight := SolarElevation(60.17, 24.94, time.Date(2026, 6, 21, 22, 20, 0, 0, time.UTC))
+
+	// Max elevation at 60.17°N on the solstice is 90 - 60.17 + 23.44 ≈ 53.3°.
+	if math.Abs(noon-53.3) > 0.5 {
+		t.Fatalf("expected noon elevation near 53.3°, got %.2f", noon)
+	}
+	if midnight >= 0 {
+		t.Fatalf("expected sun below horizon at local midnight, got %.2f", midnight)
+	}
+}
+
+func TestComputeSunTimes_UtsjokiPolarDayAndNight(t *testing.T) {
+	const lat, lon = 69.91, 27.03
+
+	june := ComputeSunTimes(lat, lon, time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC))
+	if !june.PolarDay || june.PolarNight || june.Sunrise != nil || june.Sunset != nil {
+		t.Fatalf("expected polar day without sunrise/sunset in June, got %+v", june)
+	}
+	if june.DayLength != 24*time.Hour {
+		t.Fatalf("expected 24h day length in June, got %s", june.DayLength)
+	}
+
+	december := ComputeSunTimes(lat, lon, time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC))
+	if !december.PolarNight || december.PolarDay || december.Sunrise != nil || december.Sunset != nil {
+		t.Fatalf("expected polar night without sunrise/sunset in December, got %+v", december)
+	}
+	if december.DayLength != 0 {
+		t.Fatalf("expected zero day length in December, got %s", december.DayLength)
+	}
+}
+
+func TestWithSunTimes(t *testing.T) {
+	forecasts := []DailyForecast{
+		{Date: time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC)},
+		{Date: time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC)},
+	}
+
+	helsinki := withSunTimes(60.17, 24.94, forecasts)
+	if helsinki[0].Sunrise == nil || helsinki[0].Sunrise.Second() != 0 || helsinki[0].PolarDay {
+		t.Fatalf("expected a whole-minute Helsinki sunrise, got %+v", helsinki[0])
+	}
+	if forecasts[0].Sunrise != nil {
+		t.Fatal("withSunTimes must not modify its input")
+	}
+
+	utsjoki := withSunTimes(69.91, 27.03, forecasts)
+	if !utsjoki[0].PolarDay || utsjoki[0].Sunrise != nil || !utsjoki[1].PolarNight || utsjoki[1].Sunset != nil {
+		t.Fatalf("expected polar day then polar night in Utsjoki, got %+v", utsjoki)
+	}
+}
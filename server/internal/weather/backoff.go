@@ -0,0 +1,95 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrUpstreamRateLimited matches FMI errors that refuse a call for being
+// over the rate limit. The service stops calling FMI for a while when it
+// sees one; see RateLimitedError.
+var ErrUpstreamRateLimited = errors.New("upstream weather service rate limited")
+
+// ErrUpstreamMalformed matches FMI responses that arrived in full but could
+// not be parsed, such as a truncated or reshaped document.
+var ErrUpstreamMalformed = errors.New("upstream weather service sent a malformed response")
+
+const (
+	minRateLimitBackoff = time.Minute
+	maxRateLimitBackoff = 15 * time.Minute
+)
+
+// RateLimitedError is returned while the service is backing off after FMI
+// rate limited it, without calling FMI. It matches ErrUpstreamRateLimited.
+type RateLimitedError struct {
+	// Until is when the service calls FMI again.
+	Until time.Time
+	// Err is the rate limit error that started the backoff.
+	Err error
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("backing off FMI until %s: %v", e.Until.UTC().Format(time.RFC3339), e.Err)
+}
+
+func (e *RateLimitedError) Unwrap() error { return e.Err }
+
+func (e *RateLimitedError) Is(target error) bool { return target == ErrUpstreamRateLimited }
+
+// rateLimitBackoff pauses every FMI call from the service once one is rate
+// limited. Each rate limit in a row doubles the pause, from
+// minRateLimitBackoff up to maxRateLimitBackoff; a successful call resets it.
+type rateLimitBackoff struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	delay time.Duration
+	until time.Time
+	cause error
+}
+
+func newRateLimitBackoff() *rateLimitBackoff {
+	return &rateLimitBackoff{now: time.Now}
+}
+
+// wait returns a *RateLimitedError while backing off, and nil when FMI may
+// be called.
+func (b *rateLimitBackoff) wait() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.now().Before(b.until) {
+		return &RateLimitedError{Until: b.until, Err: b.cause}
+	}
+	return nil
+}
+
+// observe records the outcome of an FMI call. A rate limit starts or
+// lengthens the backoff and is returned as a *RateLimitedError; other
+// errors are returned as they are and leave the backoff alone.
+func (b *rateLimitBackoff) observe(err error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case err == nil:
+		b.delay, b.until, b.cause = 0, time.Time{}, nil
+		return nil
+	case !errors.Is(err, ErrUpstreamRateLimited):
+		return err
+	}
+	b.delay = min(max(b.delay*2, minRateLimitBackoff), maxRateLimitBackoff)
+	b.until = b.now().Add(b.delay)
+	b.cause = err
+	return &RateLimitedError{Until: b.until, Err: err}
+}
+
+// callFMI runs fetch unless the service is backing off from FMI, and
+// records its outcome.
+func callFMI[T any](b *rateLimitBackoff, fetch func() (T, error)) (T, error) {
+	if err := b.wait(); err != nil {
+		var zero T
+		return zero, err
+	}
+	v, err := fetch()
+	return v, b.observe(err)
+}
@@ -0,0 +1,111 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGetForecast_ReactsToErrorCategory(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	stale := make([]DailyForecast, 10)
+	for i := range stale {
+		stale[i] = DailyForecast{Date: today.AddDate(0, 0, i), FetchedAt: time.Now().Add(-12 * time.Hour), TempAvg: ptr(1), SchemaVersion: DailyForecastSchemaVersion}
+	}
+	tests := []struct {
+		name      string
+		category  error
+		wantStale bool
+	}{
+		{"unavailable", ErrUpstreamUnavailable, true},
+		{"rate limited", ErrUpstreamRateLimited, true},
+		{"malformed", ErrUpstreamMalformed, true},
+		{"rejected", ErrUpstreamRejected, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := failingForecastFetcher{err: fmt.Errorf("fetch forecast: %w", tt.category)}
+
+			svc := NewService(storedForecastStore{days: stale}, fetcher, DefaultFreshness())
+			forecasts, _, source, err := svc.getForecast(context.Background(), 60.2, 24.9, 5)
+			if tt.wantStale && (err != nil || len(forecasts) != 5 || source != SourceDB) {
+				t.Fatalf("expected 5 stale days from the db, got %d from %s, err %v", len(forecasts), source, err)
+			}
+			if !tt.wantStale && !errors.Is(err, tt.category) {
+				t.Fatalf("expected the %s error instead of stale days, got %v", tt.name, err)
+			}
+
+			svc = NewService(emptyStore{}, fetcher, DefaultFreshness())
+			if _, _, _, err := svc.getForecast(context.Background(), 60.2, 24.9, 5); !errors.Is(err, tt.category) {
+				t.Fatalf("expected the error to match %v, got %v", tt.category, err)
+			}
+		})
+	}
+}
+
+// rateLimitedFetcher fails every daily forecast fetch with a rate limit
+// until ok is set, counting calls.
+type rateLimitedFetcher struct {
+	stubForecastFetcher
+	calls int
+	ok    bool
+}
+
+func (f *rateLimitedFetcher) FetchForecast(ctx context.Context, lat, lon float64, days int) (ForecastData, error) {
+	f.calls++
+	if f.ok {
+		return f.stubForecastFetcher.FetchForecast(ctx, lat, lon, days)
+	}
+	return ForecastData{}, fmt.Errorf("fetch forecast: %w", ErrUpstreamRateLimited)
+}
+
+func TestGetForecast_BacksOffAfterRateLimit(t *testing.T) {
+	now := time.Date(2026, 2, 16, 12, 0, 0, 0, time.UTC)
+	fetcher := &rateLimitedFetcher{}
+	svc := NewService(emptyStore{}, fetcher, DefaultFreshness())
+	svc.fmiBackoff.now = func() time.Time { return now }
+
+	_, _, _, err := svc.getForecast(context.Background(), 60.2, 24.9, 5)
+	var limited *RateLimitedError
+	if !errors.As(err, &limited) || !limited.Until.Equal(now.Add(minRateLimitBackoff)) {
+		t.Fatalf("expected a backoff until %s, got %v", now.Add(minRateLimitBackoff), err)
+	}
+
+	// Other grid points wait out the backoff too.
+	if _, _, _, err := svc.getForecast(context.Background(), 61.5, 23.8, 5); !errors.Is(err, ErrUpstreamRateLimited) {
+		t.Fatalf("expected a rate limit error while backing off, got %v", err)
+	}
+	if fetcher.calls != 1 {
+		t.Fatalf("expected FMI to be called once while backing off, got %d calls", fetcher.calls)
+	}
+
+	now = now.Add(minRateLimitBackoff)
+	_, _, _, err = svc.getForecast(context.Background(), 60.2, 24.9, 5)
+	if !errors.As(err, &limited) || !limited.Until.Equal(now.Add(2*minRateLimitBackoff)) {
+		t.Fatalf("expected the second rate limit to double the backoff, got %v", err)
+	}
+
+	now = now.Add(2 * minRateLimitBackoff)
+	fetcher.ok = true
+	if _, _, _, err := svc.getForecast(context.Background(), 60.2, 24.9, 5); err != nil {
+		t.Fatal(err)
+	}
+	if svc.fmiBackoff.delay != 0 {
+		t.Fatalf("expected a success to reset the backoff, got %s", svc.fmiBackoff.delay)
+	}
+}
+
+func TestRateLimitBackoff_IsCapped(t *testing.T) {
+	b := newRateLimitBackoff()
+	for range 10 {
+		b.observe(ErrUpstreamRateLimited)
+	}
+	if b.delay != maxRateLimitBackoff {
+		t.Fatalf("expected the backoff to stop at %s, got %s", maxRateLimitBackoff, b.delay)
+	}
+	if err := errors.New("timeout"); b.observe(err) != err || b.delay != maxRateLimitBackoff {
+		t.Fatal("expected other errors to pass through and leave the backoff alone")
+	}
+}
@@ -0,0 +1,202 @@
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// BiasCorrector supplies the mean temperature error of raw FMI forecasts
+// for a station at a given lead time. Corrected values are raw minus bias.
+type BiasCorrector interface {
+	TemperatureBias(fmisid int, lead time.Duration) (float64, bool)
+}
+
+// BiasEntry is the mean forecast-minus-observed temperature for forecasts
+// at least LeadHours ahead.
+type BiasEntry struct {
+	LeadHours int     `json:"lead_hours"`
+	Bias      float64 `json:"bias"`
+}
+
+// BiasTable is a per-station, per-lead-time bias table, typically learned
+// offline from the forecast/observation pairs written by the training export.
+type BiasTable struct {
+	stations map[int][]BiasEntry
+}
+
+// NewBiasTable builds a table from entries keyed by FMISID.
+func NewBiasTable(stations map[int][]BiasEntry) *BiasTable {
+	t := &BiasTable{stations: make(map[int][]BiasEntry, len(stations))}
+	for fmisid, entries := range stations {
+		sorted := slices.Clone(entries)
+		slices.SortFunc(sorted, func(a, b BiasEntry) int { return a.LeadHours - b.LeadHours })
+		t.stations[fmisid] = sorted
+	}
+	return t
+}
+
+// LoadBiasTable reads a table in the form
+// {"stations": {"100971": [{"lead_hours": 0, "bias": 0.4}, ...]}}.
+func LoadBiasTable(r io.Reader) (*BiasTable, error) {
+	var doc struct {
+		Stations map[string][]BiasEntry `json:"stations"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode bias table: %w", err)
+	}
+	stations := make(map[int][]BiasEntry, len(doc.Stations))
+	for key, entries := range doc.Stations {
+		fmisid, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("bias table station %q: %w", key, err)
+		}
+		stations[fmisid] = entries
+	}
+	return NewBiasTable(stations), nil
+}
+
+// biasLeadBuckets are the lead times, in hours, BuildBiasTable learns a
+// bias for; each bucket covers the leads up to the next one.
+var biasLeadBuckets = []int{0, 6, 12, 24, 48}
+
+// minBiasSamples is how many pairs a station's lead bucket needs before
+// BuildBiasTable trusts its mean error.
+const minBiasSamples = 12
+
+// BuildBiasTable learns the mean forecast-minus-observed temperature per
+// station and lead bucket from the pairs the training export reads.
+// Buckets with fewer than minBiasSamples pairs are left out, so stations
+// with too little data get no correction.
+func BuildBiasTable(pairs []ForecastObservationPair) map[int][]BiasEntry {
+	type sum struct {
+		total float64
+		n     int
+	}
+	sums := make(map[int]map[int]*sum)
+	for _, p := range pairs {
+		if p.ForecastTemperature == nil || p.ObservedTemperature == nil {
+			continue
+		}
+		hours := int(p.ForecastTime.Sub(p.FetchedAt) / time.Hour)
+		if hours < 0 {
+			continue
+		}
+		bucket := biasLeadBuckets[0]
+		for _, b := range biasLeadBuckets {
+			if b <= hours {
+				bucket = b
+			}
+		}
+		if sums[p.FMISID] == nil {
+			sums[p.FMISID] = make(map[int]*sum)
+		}
+		acc := sums[p.FMISID][bucket]
+		if acc == nil {
+			acc = &sum{}
+			sums[p.FMISID][bucket] = acc
+		}
+		acc.total += *p.ForecastTemperature - *p.ObservedTemperature
+		acc.n++
+	}
+
+	stations := make(map[int][]BiasEntry, len(sums))
+	for fmisid, buckets := range sums {
+		var entries []BiasEntry
+		for _, b := range biasLeadBuckets {
+			if acc := buckets[b]; acc != nil && acc.n >= minBiasSamples {
+				entries = append(entries, BiasEntry{LeadHours: b, Bias: math.Round(acc.total/float64(acc.n)*100) / 100})
+			}
+		}
+		if len(entries) > 0 {
+			stations[fmisid] = entries
+		}
+	}
+	return stations
+}
+
+// WriteBiasTable writes stations in the form LoadBiasTable reads.
+func WriteBiasTable(w io.Writer, stations map[int][]BiasEntry) error {
+	doc := struct {
+		Stations map[string][]BiasEntry `json:"stations"`
+	}{Stations: make(map[string][]BiasEntry, len(stations))}
+	for fmisid, entries := range stations {
+		doc.Stations[strconv.Itoa(fmisid)] = entries
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode bias table: %w", err)
+	}
+	return nil
+}
+
+// TemperatureBias returns the entry with the largest LeadHours not after
+// lead. Leads shorter than every entry use the first one.
+func (t *BiasTable) TemperatureBias(fmisid int, lead time.Duration) (float64, bool) {
+	entries := t.stations[fmisid]
+	if len(entries) == 0 {
+		return 0, false
+	}
+	hours := int(lead / time.Hour)
+	bias := entries[0].Bias
+	for _, e := range entries[1:] {
+		if e.LeadHours > hours {
+			break
+		}
+		bias = e.Bias
+	}
+	return bias, true
+}
+
+// SetBiasCorrector enables bias correction of served temperatures. Pass nil
+// to serve raw FMI values.
+func (s *Service) SetBiasCorrector(c BiasCorrector) {
+	s.biasCorrector = c
+}
+
+// applyBiasCorrection returns corrected copies of the forecasts with the raw
+// FMI temperatures in the *Raw fields, which stay nil for stations without
+// a bias entry. The inputs are shared with the caches and the
+// store, so they are never modified.
+func (s *Service) applyBiasCorrection(fmisid int, hourly []HourlyForecast, daily []DailyForecast) ([]HourlyForecast, []DailyForecast) {
+	if s.biasCorrector == nil {
+		return hourly, daily
+	}
+	correct := func(raw *float64, lead time.Duration) (*float64, *float64) {
+		if raw == nil {
+			return nil, nil
+		}
+		bias, ok := s.biasCorrector.TemperatureBias(fmisid, max(lead, 0))
+		if !ok {
+			return raw, nil
+		}
+		corrected := *raw - bias
+		return &corrected, raw
+	}
+
+	correctedHourly := slices.Clone(hourly)
+	for i := range correctedHourly {
+		h := &correctedHourly[i]
+		h.Temperature, h.TemperatureRaw = correct(h.Temperature, h.Time.Sub(h.FetchedAt))
+	}
+
+	correctedDaily := slices.Clone(daily)
+	for i := range correctedDaily {
+		d := &correctedDaily[i]
+		// Daily values aggregate the whole day; midday stands in for its lead.
+		lead := d.Date.Add(12 * time.Hour).Sub(d.FetchedAt)
+		d.TempHigh, d.TempHighRaw = correct(d.TempHigh, lead)
+		d.TempLow, d.TempLowRaw = correct(d.TempLow, lead)
+		d.TempAvg, d.TempAvgRaw = correct(d.TempAvg, lead)
+		d.TempDayMax, _ = correct(d.TempDayMax, lead)
+		d.TempDayAvg, _ = correct(d.TempDayAvg, lead)
+		d.TempNightMin, _ = correct(d.TempNightMin, lead)
+		d.TempNightAvg, _ = correct(d.TempNightAvg, lead)
+	}
+	return correctedHourly, correctedDaily
+}
@@ -0,0 +1,112 @@
+package weather
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBiasTable_PicksLeadBucket(t *testing.T) {
+	table, err := LoadBiasTable(strings.NewReader(`{"stations": {"100971": [
+		{"lead_hours": 24, "bias": 1.0},
+		{"lead_hours": 0, "bias": 0.2},
+		{"lead_hours": 72, "bias": 1.8}
+	]}}`))
+	if err != nil {
+		t.Fatalf("LoadBiasTable: %v", err)
+	}
+
+	cases := []struct {
+		lead time.Duration
+		want float64
+	}{
+		{0, 0.2},
+		{23 * time.Hour, 0.2},
+		{24 * time.Hour, 1.0},
+		{100 * time.Hour, 1.8},
+	}
+	for _, tc := range cases {
+		got, ok := table.TemperatureBias(100971, tc.lead)
+		if !ok || got != tc.want {
+			t.Errorf("lead %v: got %v (ok=%v), want %v", tc.lead, got, ok, tc.want)
+		}
+	}
+	if _, ok := table.TemperatureBias(1, 0); ok {
+		t.Error("expected no bias for unknown station")
+	}
+}
+
+func TestApplyBiasCorrection_KeepsRawAndDoesNotMutateInput(t *testing.T) {
+	s := NewService(nil, nil, DefaultFreshness())
+	s.SetBiasCorrector(NewBiasTable(map[int][]BiasEntry{100971: {{LeadHours: 0, Bias: 1.5}}}))
+
+	fetched := time.Date(2026, 1, 15, 6, 0, 0, 0, time.UTC)
+	hourly := []HourlyForecast{{Time: fetched.Add(time.Hour), FetchedAt: fetched, Temperature: ptr(-4)}}
+	daily := []DailyForecast{{Date: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), FetchedAt: fetched, TempHigh: ptr(-1), TempLow: nil}}
+
+	gotHourly, gotDaily := s.applyBiasCorrection(100971, hourly, daily)
+
+	if *gotHourly[0].Temperature != -5.5 || *gotHourly[0].TemperatureRaw != -4 {
+		t.Errorf("hourly corrected=%v raw=%v", *gotHourly[0].Temperature, *gotHourly[0].TemperatureRaw)
+	}
+	if *gotDaily[0].TempHigh != -2.5 || *gotDaily[0].TempHighRaw != -1 {
+		t.Errorf("daily high corrected=%v raw=%v", *gotDaily[0].TempHigh, *gotDaily[0].TempHighRaw)
+	}
+	if gotDaily[0].TempLow != nil || gotDaily[0].TempLowRaw != nil {
+		t.Error("expected missing low to stay missing")
+	}
+	if *hourly[0].Temperature != -4 || hourly[0].TemperatureRaw != nil || *daily[0].TempHigh != -1 {
+		t.Error("input forecasts were modified")
+	}
+
+	s.SetBiasCorrector(nil)
+	gotHourly, _ = s.applyBiasCorrection(100971, hourly, daily)
+	if *gotHourly[0].Temperature != -4 || gotHourly[0].TemperatureRaw != nil {
+		t.Error("expected raw values with correction disabled")
+	}
+}
+
+func TestBuildBiasTable_MeansErrorPerLeadBucket(t *testing.T) {
+	fetched := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	var pairs []ForecastObservationPair
+	add := func(fmisid, n int, lead time.Duration, forecast, observed float64) {
+		for range n {
+			pairs = append(pairs, ForecastObservationPair{
+				FMISID: fmisid, FetchedAt: fetched, ForecastTime: fetched.Add(lead),
+				ForecastTemperature: ptr(forecast), ObservedTemperature: ptr(observed),
+			})
+		}
+	}
+	add(100971, minBiasSamples, 2*time.Hour, 5, 4.5)
+	add(100971, minBiasSamples, 30*time.Hour, 5, 3.5)
+	add(100971, minBiasSamples, 50*time.Hour, 5, 6)
+	// Too few pairs for a bucket, and a station, of their own.
+	add(100971, minBiasSamples-1, 8*time.Hour, 9, 0)
+	add(101004, minBiasSamples-1, 2*time.Hour, 9, 0)
+
+	var buf bytes.Buffer
+	if err := WriteBiasTable(&buf, BuildBiasTable(pairs)); err != nil {
+		t.Fatal(err)
+	}
+	table, err := LoadBiasTable(&buf)
+	if err != nil {
+		t.Fatalf("LoadBiasTable: %v", err)
+	}
+	for _, tc := range []struct {
+		lead time.Duration
+		want float64
+	}{
+		{0, 0.5},
+		{8 * time.Hour, 0.5},
+		{30 * time.Hour, 1.5},
+		{60 * time.Hour, -1},
+	} {
+		if got, ok := table.TemperatureBias(100971, tc.lead); !ok || got != tc.want {
+			t.Errorf("lead %v: got %v (ok=%v), want %v", tc.lead, got, ok, tc.want)
+		}
+	}
+	if _, ok := table.TemperatureBias(101004, 0); ok {
+		t.Error("expected no bias for a station with too few pairs")
+	}
+}
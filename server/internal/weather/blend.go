@@ -0,0 +1,124 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// BlendStationCount is how many nearby stations a blended observation
+	// draws on.
+	BlendStationCount = 5
+	// Stations further away than this, or whose latest observation is
+	// older than the max age, are not blended into current conditions.
+	blendMaxDistanceKM = 25.0
+	blendMaxAge        = time.Hour
+)
+
+// StationObservation is a station's latest observation and its distance
+// from a requested point.
+type StationObservation struct {
+	Station     Station
+	DistanceKM  float64
+	Observation Observation
+}
+
+// BlendContribution is a station that supplied fields to a blended
+// observation. Fields are named as in the API's current block.
+type BlendContribution struct {
+	Station    Station
+	DistanceKM float64
+	ObservedAt time.Time
+	Fields     []string
+}
+
+// blendFields are the observation fields BlendObservations fills from the
+// closest station that reports them.
+var blendFields = []struct {
+	name  string
+	field func(*Observation) **float64
+}{
+	{"temperature", func(o *Observation) **float64 { return &o.Temperature }},
+	{"wind_speed", func(o *Observation) **float64 { return &o.WindSpeed }},
+	{"wind_gust", func(o *Observation) **float64 { return &o.WindGust }},
+	{"wind_direction", func(o *Observation) **float64 { return &o.WindDir }},
+	{"humidity", func(o *Observation) **float64 { return &o.Humidity }},
+	{"dew_point", func(o *Observation) **float64 { return &o.DewPoint }},
+	{"pressure", func(o *Observation) **float64 { return &o.Pressure }},
+	{"precipitation_1h", func(o *Observation) **float64 { return &o.Precip1h }},
+	{"precipitation_intensity", func(o *Observation) **float64 { return &o.PrecipIntensity }},
+	{"snow_depth", func(o *Observation) **float64 { return &o.SnowDepth }},
+	{"visibility", func(o *Observation) **float64 { return &o.Visibility }},
+	{"cloud_cover", func(o *Observation) **float64 { return &o.TotalCloudCover }},
+	{"weather_code", func(o *Observation) **float64 { return &o.WeatherCode }},
+}
+
+// BlendObservations builds one observation from stations ordered nearest
+// first, taking each field from the closest station that reports it. The
+// time, station and extra parameters are the nearest station's. It returns
+// the stations that supplied at least one field, in the same order.
+func BlendObservations(stations []StationObservation) (Observation, []BlendContribution) {
+	if len(stations) == 0 {
+		return Observation{}, nil
+	}
+	blended := stations[0].Observation
+	fieldsBy := make([][]string, len(stations))
+	for _, f := range blendFields {
+		dst := f.field(&blended)
+		*dst = nil
+		for i := range stations {
+			if v := *f.field(&stations[i].Observation); v != nil {
+				*dst = v
+				fieldsBy[i] = append(fieldsBy[i], f.name)
+				break
+			}
+		}
+	}
+
+	var contributions []BlendContribution
+	for i, so := range stations {
+		if len(fieldsBy[i]) == 0 {
+			continue
+		}
+		contributions = append(contributions, BlendContribution{
+			Station:    so.Station,
+			DistanceKM: so.DistanceKM,
+			ObservedAt: so.Observation.ObservedAt,
+			Fields:     fieldsBy[i],
+		})
+	}
+	return blended, contributions
+}
+
+// GetBlendedCurrentWeather returns current conditions blended from up to
+// BlendStationCount nearby stations that reported within the last hour, so
+// a precipitation-only gauge next door doesn't leave temperature empty. It
+// returns nil when no such station is in range.
+func (s *Service) GetBlendedCurrentWeather(ctx context.Context, lat, lon float64) (*CurrentWeather, error) {
+	if lon < finlandMinLon || lon > finlandMaxLon || lat < finlandMinLat || lat > finlandMaxLat {
+		return nil, ErrOutOfCoverage
+	}
+
+	nearby, err := s.store.LatestObservationsNear(ctx, lat, lon, BlendStationCount, time.Now().Add(-blendMaxAge))
+	if err != nil {
+		return nil, fmt.Errorf("latest observations near: %w", err)
+	}
+	inRange := nearby[:0:0]
+	for _, so := range nearby {
+		if so.DistanceKM <= blendMaxDistanceKM {
+			inRange = append(inRange, so)
+		}
+	}
+	if len(inRange) == 0 {
+		return nil, nil
+	}
+
+	obs, contributions := BlendObservations(inRange)
+	return &CurrentWeather{
+		Station:      inRange[0].Station,
+		DistanceKM:   inRange[0].DistanceKM,
+		Observation:  obs,
+		Contributors: contributions,
+	}, nil
+}
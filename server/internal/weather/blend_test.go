@@ -0,0 +1,80 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBlendObservations_TakesEachFieldFromClosestReporter(t *testing.T) {
+	now := time.Now()
+	gauge := StationObservation{
+		Station:     Station{FMISID: 1, Name: "Gauge", Type: StationTypePrecipitation},
+		DistanceKM:  1,
+		Observation: Observation{FMISID: 1, ObservedAt: now, Precip1h: ptr(0.4), ExtraNumericParams: map[string]float64{"x": 1}},
+	}
+	aws := StationObservation{
+		Station:     Station{FMISID: 2, Name: "AWS", Type: StationTypeAWS},
+		DistanceKM:  3,
+		Observation: Observation{FMISID: 2, ObservedAt: now.Add(-10 * time.Minute), Temperature: ptr(-3), WindSpeed: ptr(4), Precip1h: ptr(0.1)},
+	}
+	unused := StationObservation{
+		Station:     Station{FMISID: 3, Name: "Far"},
+		DistanceKM:  8,
+		Observation: Observation{FMISID: 3, Temperature: ptr(-5)},
+	}
+
+	obs, contributions := BlendObservations([]StationObservation{gauge, aws, unused})
+	if obs.FMISID != 1 || !obs.ObservedAt.Equal(now) || obs.ExtraNumericParams["x"] != 1 {
+		t.Fatalf("expected the nearest station's identity and extras, got %+v", obs)
+	}
+	if *obs.Precip1h != 0.4 || *obs.Temperature != -3 || *obs.WindSpeed != 4 || obs.Humidity != nil {
+		t.Fatalf("unexpected blended values: %+v", obs)
+	}
+	want := []BlendContribution{
+		{Station: gauge.Station, DistanceKM: 1, ObservedAt: now, Fields: []string{"precipitation_1h"}},
+		{Station: aws.Station, DistanceKM: 3, ObservedAt: aws.Observation.ObservedAt, Fields: []string{"temperature", "wind_speed"}},
+	}
+	if !reflect.DeepEqual(contributions, want) {
+		t.Fatalf("got contributions %+v\nwant %+v", contributions, want)
+	}
+	if gauge.Observation.Temperature != nil {
+		t.Fatal("input observation was modified")
+	}
+}
+
+// blendStore serves fixed nearby observations.
+type blendStore struct {
+	emptyStore
+	nearby []StationObservation
+}
+
+func (s blendStore) LatestObservationsNear(ctx context.Context, lat, lon float64, n int, since time.Time) ([]StationObservation, error) {
+	return s.nearby, nil
+}
+
+func TestGetBlendedCurrentWeather(t *testing.T) {
+	store := blendStore{nearby: []StationObservation{
+		{Station: Station{FMISID: 1}, DistanceKM: 2, Observation: Observation{Precip1h: ptr(0)}},
+		{Station: Station{FMISID: 2}, DistanceKM: 40, Observation: Observation{Temperature: ptr(1)}},
+	}}
+	s := NewService(store, stubForecastFetcher{}, DefaultFreshness())
+
+	current, err := s.GetBlendedCurrentWeather(context.Background(), 60.17, 24.94)
+	if err != nil {
+		t.Fatalf("GetBlendedCurrentWeather: %v", err)
+	}
+	if current.Station.FMISID != 1 || current.Observation.Temperature != nil || len(current.Contributors) != 1 {
+		t.Fatalf("expected stations beyond range to be ignored, got %+v", current)
+	}
+
+	s = NewService(blendStore{}, stubForecastFetcher{}, DefaultFreshness())
+	if current, err := s.GetBlendedCurrentWeather(context.Background(), 60.17, 24.94); err != nil || current != nil {
+		t.Fatalf("expected nil without nearby stations, got %+v, %v", current, err)
+	}
+	if _, err := s.GetBlendedCurrentWeather(context.Background(), 40, 24.94); !errors.Is(err, ErrOutOfCoverage) {
+		t.Fatalf("expected ErrOutOfCoverage, got %v", err)
+	}
+}
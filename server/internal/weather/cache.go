@@ -3,6 +3,8 @@ package weather
 import (
 	"sync"
 	"time"
+
+	"wby/internal/metrics"
 )
 
 type cacheEntry[V any] struct {
@@ -11,9 +13,13 @@ type cacheEntry[V any] struct {
 }
 
 type Cache[V any] struct {
-	mu  sync.RWMutex
-	ttl time.Duration
-	m   map[string]cacheEntry[V]
+	mu         sync.RWMutex
+	ttl        time.Duration
+	m          map[string]cacheEntry[V]
+	maxEntries int
+
+	name    string
+	lookups *metrics.CounterVec
 }
 
 func NewCache[V any](ttl time.Duration) *Cache[V] {
@@ -23,19 +29,67 @@ func NewCache[V any](ttl time.Duration) *Cache[V] {
 	}
 }
 
+// NewBoundedCache is NewCache holding at most maxEntries entries, for
+// caches keyed by client input. When full, Set drops expired entries and,
+// if none had expired, the one closest to expiring.
+func NewBoundedCache[V any](ttl time.Duration, maxEntries int) *Cache[V] {
+	c := NewCache[V](ttl)
+	c.maxEntries = maxEntries
+	return c
+}
+
+// instrument counts hits and misses of Get in lookups, labelled with name.
+func (c *Cache[V]) instrument(name string, lookups *metrics.CounterVec) {
+	c.name = name
+	c.lookups = lookups
+}
+
 func (c *Cache[V]) Get(key string) (V, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	entry, ok := c.m[key]
 	if !ok || time.Now().After(entry.expiresAt) {
+		c.lookups.Inc(c.name, "miss")
 		var zero V
 		return zero, false
 	}
+	c.lookups.Inc(c.name, "hit")
 	return entry.value, true
 }
 
 func (c *Cache[V]) Set(key string, value V) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if _, ok := c.m[key]; !ok && c.maxEntries > 0 && len(c.m) >= c.maxEntries {
+		c.evict()
+	}
 	c.m[key] = cacheEntry[V]{value: value, expiresAt: time.Now().Add(c.ttl)}
 }
+
+// Clear drops every entry and returns how many there were.
+func (c *Cache[V]) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.m)
+	clear(c.m)
+	return n
+}
+
+// evict makes room for one entry. The caller holds c.mu.
+func (c *Cache[V]) evict() {
+	now := time.Now()
+	var soonest string
+	var soonestAt time.Time
+	for key, entry := range c.m {
+		if now.After(entry.expiresAt) {
+			delete(c.m, key)
+			continue
+		}
+		if soonestAt.IsZero() || entry.expiresAt.Before(soonestAt) {
+			soonest, soonestAt = key, entry.expiresAt
+		}
+	}
+	if len(c.m) >= c.maxEntries {
+		delete(c.m, soonest)
+	}
+}
@@ -3,6 +3,8 @@ package weather
 import (
 	"testing"
 	"time"
+
+	"wby/internal/metrics"
 )
 
 func TestCache_SetAndGet(t *testing.T) {
@@ -29,3 +31,66 @@ func TestCache_Expiry(t *testing.T) {
 		t.Fatal("expected cache miss after TTL")
 	}
 }
+
+func TestCache_CountsHitsAndMisses(t *testing.T) {
+	reg := metrics.NewRegistry()
+	c := NewCache[string](time.Minute)
+	c.instrument("forecast", reg.Counter("wby_cache_lookups_total", "", "cache", "result"))
+
+	c.Get("key1")
+	c.Set("key1", "value1")
+	c.Get("key1")
+	c.Get("key1")
+
+	if got := reg.Value("wby_cache_lookups_total", "forecast", "miss"); got != 1 {
+		t.Errorf("expected 1 miss, got %v", got)
+	}
+	if got := reg.Value("wby_cache_lookups_total", "forecast", "hit"); got != 2 {
+		t.Errorf("expected 2 hits, got %v", got)
+	}
+}
+
+func TestCache_Clear(t *testing.T) {
+	c := NewCache[string](time.Minute)
+	c.Set("a", "1")
+	c.Set("b", "2")
+
+	if n := c.Clear(); n != 2 {
+		t.Fatalf("expected 2 cleared entries, got %d", n)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected a miss after Clear")
+	}
+}
+
+func TestBoundedCache_EvictsWhenFull(t *testing.T) {
+	c := NewBoundedCache[string](time.Minute, 2)
+	c.Set("a", "1")
+	time.Sleep(time.Millisecond)
+	c.Set("b", "2")
+	c.Set("a", "1")
+	c.Set("c", "3")
+
+	if len(c.m) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(c.m))
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected the entry closest to expiring to be evicted")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("expected the new entry to be cached")
+	}
+}
+
+func TestBoundedCache_SweepsExpired(t *testing.T) {
+	c := NewBoundedCache[string](20*time.Millisecond, 3)
+	c.Set("a", "1")
+	c.Set("b", "2")
+	c.Set("c", "3")
+	time.Sleep(40 * time.Millisecond)
+	c.Set("d", "4")
+
+	if len(c.m) != 1 {
+		t.Fatalf("expected expired entries swept, got %d entries", len(c.m))
+	}
+}
@@ -0,0 +1,112 @@
+package weather
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownStationToken is returned for an ingest token no personal
+// station was registered with.
+var ErrUnknownStationToken = errors.New("unknown station token")
+
+const (
+	// Personal stations further away than this, or older than the max age,
+	// are not blended into current conditions.
+	customObservationMaxDistanceKM = 5.0
+	customObservationMaxAge        = 30 * time.Minute
+)
+
+// CustomStation is a personal weather station registered for token
+// ingestion. Format is the payload format its device uploads, "ecowitt" or
+// "weatherflow".
+type CustomStation struct {
+	ClientID  string
+	StationID string
+	Format    string
+	Lat       float64
+	Lon       float64
+}
+
+// RegisterCustomStation registers st for ingestion by devices that cannot
+// sign requests, such as an Ecowitt gateway's custom-server upload, and
+// returns the ingest token for its upload URL. Only a hash of the token is
+// stored, so it cannot be shown again; registering the station again
+// replaces it.
+func (s *Service) RegisterCustomStation(ctx context.Context, st CustomStation) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate station token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+	if err := s.store.RegisterCustomStation(ctx, st, stationTokenHash(token)); err != nil {
+		return "", fmt.Errorf("register custom station: %w", err)
+	}
+	return token, nil
+}
+
+// CustomStationForToken returns the station an ingest token was issued
+// for, or ErrUnknownStationToken.
+func (s *Service) CustomStationForToken(ctx context.Context, token string) (*CustomStation, error) {
+	st, err := s.store.CustomStationByToken(ctx, stationTokenHash(token))
+	if err != nil {
+		return nil, fmt.Errorf("custom station by token: %w", err)
+	}
+	if st == nil {
+		return nil, ErrUnknownStationToken
+	}
+	return st, nil
+}
+
+func stationTokenHash(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+func (s *Service) IngestCustomObservation(ctx context.Context, obs CustomObservation) error {
+	if err := s.store.UpsertCustomObservation(ctx, obs); err != nil {
+		return fmt.Errorf("store custom observation: %w", err)
+	}
+	return nil
+}
+
+// NearestCustomObservation returns the client's closest personal station
+// reading within blending range, or nil when there is none.
+func (s *Service) NearestCustomObservation(ctx context.Context, clientID string, lat, lon float64) (*CustomObservation, float64, error) {
+	obs, distKM, err := s.store.NearestCustomObservation(ctx, clientID, lat, lon, customObservationMaxAge)
+	if err != nil {
+		return nil, 0, fmt.Errorf("nearest custom observation: %w", err)
+	}
+	if obs == nil || distKM > customObservationMaxDistanceKM {
+		return nil, 0, nil
+	}
+	return obs, distKM, nil
+}
+
+// BlendCustomObservation overwrites official observation fields with the
+// values a personal station reports. Fields the personal station doesn't
+// report keep their official values.
+func BlendCustomObservation(official Observation, custom CustomObservation) Observation {
+	blended := official
+	overlay := func(dst **float64, src *float64) {
+		if src != nil {
+			*dst = src
+		}
+	}
+	overlay(&blended.Temperature, custom.Temperature)
+	overlay(&blended.Humidity, custom.Humidity)
+	overlay(&blended.DewPoint, custom.DewPoint)
+	overlay(&blended.WindSpeed, custom.WindSpeed)
+	overlay(&blended.WindGust, custom.WindGust)
+	overlay(&blended.WindDir, custom.WindDir)
+	overlay(&blended.Pressure, custom.Pressure)
+	overlay(&blended.Precip1h, custom.Precip1h)
+	if custom.ObservedAt.After(blended.ObservedAt) {
+		blended.ObservedAt = custom.ObservedAt
+	}
+	return blended
+}
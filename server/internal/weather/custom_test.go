@@ -0,0 +1,33 @@
+package weather
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBlendCustomObservation_PrefersPersonalStationValues(t *testing.T) {
+	official := Observation{
+		ObservedAt:  time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC),
+		Temperature: ptr(8.0),
+		Humidity:    ptr(60),
+		Visibility:  ptr(20000),
+	}
+	custom := CustomObservation{
+		ObservedAt:  time.Date(2026, 4, 18, 10, 5, 0, 0, time.UTC),
+		Temperature: ptr(9.5),
+	}
+
+	blended := BlendCustomObservation(official, custom)
+	if *blended.Temperature != 9.5 {
+		t.Fatalf("expected personal station temperature, got %v", *blended.Temperature)
+	}
+	if *blended.Humidity != 60 || *blended.Visibility != 20000 {
+		t.Fatalf("expected unreported fields to keep official values")
+	}
+	if !blended.ObservedAt.Equal(custom.ObservedAt) {
+		t.Fatalf("expected observed_at to advance to the newer reading, got %v", blended.ObservedAt)
+	}
+	if *official.Temperature != 8.0 {
+		t.Fatalf("official observation was mutated")
+	}
+}
@@ -0,0 +1,198 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"wby/internal/logging"
+)
+
+// Environment section names, in response order.
+const (
+	EnvironmentWarnings   = "warnings"
+	EnvironmentAirQuality = "air_quality"
+	EnvironmentPollen     = "pollen"
+	EnvironmentUVMax      = "uv_max"
+	EnvironmentFireIndex  = "fire_index"
+)
+
+var environmentSections = []string{
+	EnvironmentWarnings,
+	EnvironmentAirQuality,
+	EnvironmentPollen,
+	EnvironmentUVMax,
+	EnvironmentFireIndex,
+}
+
+// EnvironmentSection is one auxiliary data source in the environment block.
+// Available is false when no provider is configured or it has never
+// returned data. Stale is set when the provider failed and the last known
+// value is served instead.
+type EnvironmentSection struct {
+	Available bool
+	Stale     bool
+	UpdatedAt *time.Time
+	Value     *float64
+	Level     string
+	Summary   string
+}
+
+// Environment aggregates the auxiliary sections for a location, keyed by
+// section name.
+type Environment struct {
+	Sections map[string]EnvironmentSection
+}
+
+// EnvironmentProvider fetches one environment section for a grid point.
+type EnvironmentProvider interface {
+	FetchEnvironment(ctx context.Context, lat, lon float64) (EnvironmentSection, error)
+}
+
+type environmentProviderFunc func(ctx context.Context, lat, lon float64) (EnvironmentSection, error)
+
+func (f environmentProviderFunc) FetchEnvironment(ctx context.Context, lat, lon float64) (EnvironmentSection, error) {
+	return f(ctx, lat, lon)
+}
+
+// SetEnvironmentProvider registers the provider for a section, replacing any
+// earlier one.
+func (s *Service) SetEnvironmentProvider(section string, p EnvironmentProvider) {
+	s.environmentMu.Lock()
+	defer s.environmentMu.Unlock()
+	s.environmentProviders[section] = p
+}
+
+// GetEnvironment fetches every configured section concurrently. Each section
+// is cached independently, so one slow or failing source never blocks or
+// invalidates the others.
+func (s *Service) GetEnvironment(ctx context.Context, lat, lon float64) (*Environment, error) {
+	if lon < finlandMinLon || lon > finlandMaxLon || lat < finlandMinLat || lat > finlandMaxLat {
+		return nil, ErrOutOfCoverage
+	}
+	gridLat, gridLon := snapToGrid(lat, lon)
+
+	s.environmentMu.RLock()
+	providers := make(map[string]EnvironmentProvider, len(s.environmentProviders))
+	for name, p := range s.environmentProviders {
+		providers[name] = p
+	}
+	s.environmentMu.RUnlock()
+
+	env := &Environment{Sections: make(map[string]EnvironmentSection, len(environmentSections))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range environmentSections {
+		p, ok := providers[name]
+		if !ok {
+			env.Sections[name] = EnvironmentSection{}
+			continue
+		}
+		wg.Go(func() {
+			section := s.environmentSection(ctx, name, p, gridLat, gridLon)
+			mu.Lock()
+			env.Sections[name] = section
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return env, nil
+}
+
+func (s *Service) environmentSection(ctx context.Context, name string, p EnvironmentProvider, gridLat, gridLon float64) EnvironmentSection {
+	cacheKey := fmt.Sprintf("%s:%.2f,%.2f", name, gridLat, gridLon)
+	if cached, ok := s.environmentCache.Get(cacheKey); ok {
+		return cached
+	}
+
+	section, err := p.FetchEnvironment(ctx, gridLat, gridLon)
+	if err != nil {
+		logging.FromContext(ctx).Warn("environment section unavailable", "err", err, "section", name, "lat", gridLat, "lon", gridLon)
+		if last, ok := s.environmentLastKnown.Get(cacheKey); ok {
+			last.Stale = true
+			return last
+		}
+		return EnvironmentSection{}
+	}
+	if section.UpdatedAt == nil {
+		now := time.Now().UTC()
+		section.UpdatedAt = &now
+	}
+	s.environmentCache.Set(cacheKey, section)
+	s.environmentLastKnown.Set(cacheKey, section)
+	return section
+}
+
+// WarningsEnvironmentProvider reports the FMI warnings in effect at the
+// point from the stored CAP alerts, for registering as the warnings section
+// when warnings are ingested. Value is the number of warnings, and Level
+// and Summary the lowercase CAP severity and headline of the most severe
+// one, or "none" when there are none.
+func (s *Service) WarningsEnvironmentProvider() EnvironmentProvider {
+	return environmentProviderFunc(s.warningsProvider)
+}
+
+// capSeverities ranks CAP severities, least severe first.
+var capSeverities = []string{"Minor", "Moderate", "Severe", "Extreme"}
+
+func (s *Service) warningsProvider(ctx context.Context, lat, lon float64) (EnvironmentSection, error) {
+	warnings, err := s.warningsAt(ctx, lat, lon, time.Now())
+	if err != nil {
+		return EnvironmentSection{}, fmt.Errorf("load warnings: %w", err)
+	}
+	count := float64(len(warnings))
+	section := EnvironmentSection{Available: true, Value: &count, Level: "none"}
+	// Below the -1 of a severity outside the CAP list, so the first
+	// warning always counts.
+	worst := -2
+	for _, w := range warnings {
+		if rank := slices.Index(capSeverities, w.Severity); rank > worst {
+			worst = rank
+			section.Level = strings.ToLower(w.Severity)
+			section.Summary = w.Headline
+		}
+	}
+	return section, nil
+}
+
+// uvMaxProvider reports today's peak UV index from the FMI UV forecast the
+// service already fetches for the weather response.
+func (s *Service) uvMaxProvider(ctx context.Context, lat, lon float64) (EnvironmentSection, error) {
+	points, _ := s.getUVData(ctx, lat, lon)
+	if len(points) == 0 {
+		return EnvironmentSection{}, fmt.Errorf("no UV forecast")
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	var peak *float64
+	for hour, index := range uvIndexes(points) {
+		if time.Unix(hour, 0).UTC().Format("2006-01-02") != today {
+			continue
+		}
+		if peak == nil || index > *peak {
+			peak = &index
+		}
+	}
+	if peak == nil {
+		return EnvironmentSection{}, fmt.Errorf("no UV forecast for today")
+	}
+	return EnvironmentSection{Available: true, Value: peak, Level: uvLevel(*peak)}, nil
+}
+
+// uvLevel maps a UV index to the WHO exposure category.
+func uvLevel(uv float64) string {
+	switch {
+	case uv < 3:
+		return "low"
+	case uv < 6:
+		return "moderate"
+	case uv < 8:
+		return "high"
+	case uv < 11:
+		return "very_high"
+	default:
+		return "extreme"
+	}
+}
@@ -0,0 +1,113 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubEnvironmentProvider struct {
+	section EnvironmentSection
+	err     error
+}
+
+func (p *stubEnvironmentProvider) FetchEnvironment(ctx context.Context, lat, lon float64) (EnvironmentSection, error) {
+	return p.section, p.err
+}
+
+func TestGetEnvironment_FallsBackToStaleSectionOnFailure(t *testing.T) {
+	freshness := DefaultFreshness()
+	freshness.Environment = FreshnessWindow{CacheTTL: time.Nanosecond, MaxAge: time.Hour}
+	s := NewService(nil, nil, freshness)
+
+	pollen := &stubEnvironmentProvider{section: EnvironmentSection{Available: true, Value: ptr(3), Level: "moderate"}}
+	s.SetEnvironmentProvider(EnvironmentPollen, pollen)
+	s.SetEnvironmentProvider(EnvironmentUVMax, &stubEnvironmentProvider{err: errors.New("down")})
+
+	if _, err := s.GetEnvironment(context.Background(), 60.17, 24.94); err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	pollen.err = errors.New("upstream timeout")
+
+	env, err := s.GetEnvironment(context.Background(), 60.17, 24.94)
+	if err != nil {
+		t.Fatalf("second fetch: %v", err)
+	}
+	got := env.Sections[EnvironmentPollen]
+	if !got.Available || !got.Stale || *got.Value != 3 {
+		t.Fatalf("expected stale last known pollen section, got %+v", got)
+	}
+	if uv := env.Sections[EnvironmentUVMax]; uv.Available || uv.Stale {
+		t.Fatalf("expected unavailable UV section, got %+v", uv)
+	}
+	if w := env.Sections[EnvironmentWarnings]; w.Available {
+		t.Fatalf("expected warnings to be unavailable without a provider")
+	}
+}
+
+func TestGetEnvironment_RejectsOutOfCoverage(t *testing.T) {
+	s := NewService(nil, nil, DefaultFreshness())
+	if _, err := s.GetEnvironment(context.Background(), 48.85, 2.35); !errors.Is(err, ErrOutOfCoverage) {
+		t.Fatalf("expected ErrOutOfCoverage, got %v", err)
+	}
+}
+
+func TestWarningsEnvironmentProvider_ReportsMostSevereActiveWarning(t *testing.T) {
+	now := time.Now()
+	s := NewService(warningStore{warnings: []Warning{
+		{Severity: "Moderate", Headline: "Wind warning", Expires: now.Add(time.Hour)},
+		{Severity: "Severe", Headline: "Forest fire warning", Expires: now.Add(time.Hour)},
+		{Severity: "Extreme", Headline: "Expired storm warning", Expires: now.Add(-time.Minute)},
+	}}, stubForecastFetcher{}, DefaultFreshness())
+	s.SetEnvironmentProvider(EnvironmentWarnings, s.WarningsEnvironmentProvider())
+
+	env, err := s.GetEnvironment(context.Background(), 60.17, 24.94)
+	if err != nil {
+		t.Fatalf("GetEnvironment: %v", err)
+	}
+	got := env.Sections[EnvironmentWarnings]
+	if !got.Available || got.Value == nil || *got.Value != 2 || got.Level != "severe" || got.Summary != "Forest fire warning" {
+		t.Fatalf("unexpected warnings section %+v", got)
+	}
+}
+
+type airQualityStore struct {
+	emptyStore
+	station *Station
+	distKM  float64
+	aq      *AirQuality
+}
+
+func (s airQualityStore) NearestAirQualityStation(ctx context.Context, lat, lon float64) (*Station, float64, error) {
+	return s.station, s.distKM, nil
+}
+
+func (s airQualityStore) LatestAirQuality(ctx context.Context, fmisid int) (*AirQuality, error) {
+	return s.aq, nil
+}
+
+func TestAirQualityEnvironmentProvider(t *testing.T) {
+	observedAt := time.Now().Add(-30 * time.Minute).Truncate(time.Second)
+	store := airQualityStore{
+		station: &Station{FMISID: 100742, Name: "Helsinki Kallio 2"},
+		distKM:  2.1,
+		aq:      &AirQuality{FMISID: 100742, ObservedAt: observedAt, PM25: ptr(30), NO2: ptr(12)},
+	}
+	s := NewService(store, stubForecastFetcher{}, DefaultFreshness())
+
+	got, err := s.airQualityProvider(context.Background(), 60.18, 24.95)
+	if err != nil {
+		t.Fatalf("airQualityProvider: %v", err)
+	}
+	if !got.Available || *got.Value != 3 || got.Level != AirQualityFair || got.Summary != "Helsinki Kallio 2" || !got.UpdatedAt.Equal(observedAt) {
+		t.Fatalf("unexpected air quality section %+v", got)
+	}
+
+	store.distKM = 80
+	s = NewService(store, stubForecastFetcher{}, DefaultFreshness())
+	if got, err := s.airQualityProvider(context.Background(), 60.18, 24.95); err != nil || got.Available {
+		t.Fatalf("expected no section for a distant station, got %+v, %v", got, err)
+	}
+}
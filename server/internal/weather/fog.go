@@ -0,0 +1,83 @@
+package weather
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// fogVisibilityThreshold is the WMO definition of fog: visibility below 1 km.
+	fogVisibilityThreshold = 1000.0
+	// denseFogVisibilityThreshold matches the visibility at which FMI issues
+	// dense fog traffic warnings.
+	denseFogVisibilityThreshold = 200.0
+)
+
+// FogAdvisory summarizes observed and forecast fog for the hourly window.
+type FogAdvisory struct {
+	Level              string
+	Observed           bool
+	ObservedVisibility *float64
+	StartsAt           *time.Time
+	ClearsAt           *time.Time
+	FogHours           int
+}
+
+// BuildFogAdvisory combines the latest observed visibility with the hourly
+// FogIntensity forecast (0 none, 1 moderate, 2 dense). It returns nil when
+// neither source indicates fog. ClearsAt is the first fog-free forecast hour
+// after fog begins and stays nil if fog persists through the window.
+func BuildFogAdvisory(obs Observation, hourly []HourlyForecast) *FogAdvisory {
+	observed := obs.Visibility != nil && *obs.Visibility < fogVisibilityThreshold
+
+	var (
+		advisory     FogAdvisory
+		maxIntensity int
+		inFog        = observed
+	)
+	if observed {
+		advisory.Observed = true
+		advisory.ObservedVisibility = obs.Visibility
+		startsAt := obs.ObservedAt
+		advisory.StartsAt = &startsAt
+		if *obs.Visibility < denseFogVisibilityThreshold {
+			maxIntensity = 2
+		} else {
+			maxIntensity = 1
+		}
+	}
+
+	for _, h := range hourly {
+		intensity := 0
+		if h.FogIntensity != nil {
+			intensity = int(math.Round(*h.FogIntensity))
+		}
+		if intensity >= 1 {
+			advisory.FogHours++
+			if intensity > maxIntensity {
+				maxIntensity = intensity
+			}
+			if advisory.StartsAt == nil {
+				startsAt := h.Time
+				advisory.StartsAt = &startsAt
+			}
+			inFog = true
+			continue
+		}
+		if inFog && advisory.ClearsAt == nil {
+			clearsAt := h.Time
+			advisory.ClearsAt = &clearsAt
+		}
+		inFog = false
+	}
+
+	if !observed && advisory.FogHours == 0 {
+		return nil
+	}
+	if maxIntensity >= 2 {
+		advisory.Level = "dense"
+	} else {
+		advisory.Level = "moderate"
+	}
+	return &advisory
+}
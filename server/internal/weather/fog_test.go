@@ -0,0 +1,78 @@
+package weather
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildFogAdvisory_NoFog(t *testing.T) {
+	start := time.Date(2026, 10, 15, 4, 0, 0, 0, time.UTC)
+	hourly := []HourlyForecast{
+		{Time: start, FogIntensity: ptr(0)},
+		{Time: start.Add(time.Hour), FogIntensity: ptr(0)},
+	}
+	obs := Observation{ObservedAt: start, Visibility: ptr(25000)}
+
+	if got := BuildFogAdvisory(obs, hourly); got != nil {
+		t.Fatalf("expected no advisory, got %+v", got)
+	}
+}
+
+func TestBuildFogAdvisory_ForecastFogWithClearing(t *testing.T) {
+	start := time.Date(2026, 10, 15, 3, 0, 0, 0, time.UTC)
+	hourly := []HourlyForecast{
+		{Time: start, FogIntensity: ptr(0)},
+		{Time: start.Add(1 * time.Hour), FogIntensity: ptr(1)},
+		{Time: start.Add(2 * time.Hour), FogIntensity: ptr(2)},
+		{Time: start.Add(3 * time.Hour), FogIntensity: ptr(1)},
+		{Time: start.Add(4 * time.Hour), FogIntensity: ptr(0)},
+		{Time: start.Add(5 * time.Hour)},
+	}
+	obs := Observation{ObservedAt: start, Visibility: ptr(8000)}
+
+	got := BuildFogAdvisory(obs, hourly)
+	if got == nil {
+		t.Fatal("expected advisory")
+	}
+	if got.Observed {
+		t.Fatal("expected forecast-only advisory")
+	}
+	if got.Level != "dense" {
+		t.Fatalf("expected dense level, got %q", got.Level)
+	}
+	if got.FogHours != 3 {
+		t.Fatalf("expected 3 fog hours, got %d", got.FogHours)
+	}
+	if got.StartsAt == nil || !got.StartsAt.Equal(start.Add(time.Hour)) {
+		t.Fatalf("unexpected start: %v", got.StartsAt)
+	}
+	if got.ClearsAt == nil || !got.ClearsAt.Equal(start.Add(4*time.Hour)) {
+		t.Fatalf("unexpected clearing time: %v", got.ClearsAt)
+	}
+}
+
+func TestBuildFogAdvisory_ObservedFogPersists(t *testing.T) {
+	start := time.Date(2026, 10, 15, 5, 0, 0, 0, time.UTC)
+	hourly := []HourlyForecast{
+		{Time: start.Add(time.Hour), FogIntensity: ptr(1)},
+		{Time: start.Add(2 * time.Hour), FogIntensity: ptr(1)},
+	}
+	obs := Observation{ObservedAt: start, Visibility: ptr(600)}
+
+	got := BuildFogAdvisory(obs, hourly)
+	if got == nil {
+		t.Fatal("expected advisory")
+	}
+	if !got.Observed || got.ObservedVisibility == nil || *got.ObservedVisibility != 600 {
+		t.Fatalf("expected observed fog at 600 m, got %+v", got)
+	}
+	if got.Level != "moderate" {
+		t.Fatalf("expected moderate level, got %q", got.Level)
+	}
+	if got.StartsAt == nil || !got.StartsAt.Equal(start) {
+		t.Fatalf("expected start at observation time, got %v", got.StartsAt)
+	}
+	if got.ClearsAt != nil {
+		t.Fatalf("expected no clearing within window, got %v", got.ClearsAt)
+	}
+}
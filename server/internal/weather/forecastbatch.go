@@ -0,0 +1,67 @@
+package weather
+
+import (
+	"context"
+
+	"wby/internal/logging"
+)
+
+// LatLon is a point to fetch a forecast for.
+type LatLon struct {
+	Lat, Lon float64
+}
+
+// ForecastBatchFetcher fetches daily forecasts for several points in fewer
+// upstream requests than one per point; *fmi.Client implements it. Results
+// are in the order of points, with an empty ForecastData for a point the
+// upstream returned nothing for.
+type ForecastBatchFetcher interface {
+	FetchForecastBatch(ctx context.Context, points []LatLon, days int) ([]ForecastData, error)
+}
+
+// SetForecastBatchFetcher enables PrefetchForecasts.
+func (s *Service) SetForecastBatchFetcher(f ForecastBatchFetcher) {
+	s.forecastBatch = f
+}
+
+// PrefetchForecasts fetches the default forecast window, in batches, for
+// the grid points of points that have no fresh cached or stored forecast,
+// so that reading them one by one afterwards does not cost an FMI request
+// each. Without a batch fetcher it does nothing. A failed batch is logged
+// and its points are left for the reads to fetch.
+func (s *Service) PrefetchForecasts(ctx context.Context, points []LatLon) {
+	if s.forecastBatch == nil {
+		return
+	}
+	var missing []LatLon
+	seen := make(map[string]bool)
+	for _, p := range points {
+		gridLat, gridLon := snapToGrid(p.Lat, p.Lon)
+		key := gridKey(gridLat, gridLon)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, _, _, ok := s.lookupForecast(ctx, key, gridLat, gridLon, DefaultForecastDays); !ok {
+			missing = append(missing, LatLon{Lat: gridLat, Lon: gridLon})
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	results, err := callFMI(s.fmiBackoff, func() ([]ForecastData, error) {
+		return s.forecastBatch.FetchForecastBatch(ctx, missing, DefaultForecastDays)
+	})
+	if err != nil {
+		logging.FromContext(ctx).Warn("forecast prefetch failed", "err", err, "points", len(missing))
+		return
+	}
+	for i, data := range results {
+		if len(data.Forecasts) == 0 {
+			continue
+		}
+		p := missing[i]
+		s.saveForecast(ctx, gridKey(p.Lat, p.Lon), p.Lat, p.Lon, DefaultForecastDays, data)
+	}
+}
@@ -0,0 +1,52 @@
+package weather
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type recordingBatchFetcher struct {
+	batches [][]LatLon
+}
+
+func (f *recordingBatchFetcher) FetchForecastBatch(ctx context.Context, points []LatLon, days int) ([]ForecastData, error) {
+	f.batches = append(f.batches, points)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	results := make([]ForecastData, len(points))
+	for i, p := range points {
+		forecasts := make([]DailyForecast, days)
+		for d := range forecasts {
+			forecasts[d] = DailyForecast{GridLat: p.Lat, GridLon: p.Lon, Date: today.AddDate(0, 0, d), FetchedAt: time.Now(), TempAvg: ptr(1)}
+		}
+		results[i] = ForecastData{Forecasts: forecasts}
+	}
+	return results, nil
+}
+
+func TestPrefetchForecasts_BatchesMissingGridPoints(t *testing.T) {
+	fetcher := &countingForecastFetcher{}
+	batch := &recordingBatchFetcher{}
+	s := NewService(storedForecastStore{}, fetcher, DefaultFreshness())
+	s.SetForecastBatchFetcher(batch)
+
+	// The first two share a grid point.
+	s.PrefetchForecasts(context.Background(), []LatLon{{Lat: 60.171, Lon: 24.941}, {Lat: 60.169, Lon: 24.939}, {Lat: 65.01, Lon: 25.47}})
+	if len(batch.batches) != 1 || len(batch.batches[0]) != 2 {
+		t.Fatalf("expected one batch of 2 grid points, got %v", batch.batches)
+	}
+
+	resp, err := s.GetForecast(context.Background(), 65.01, 25.47, 0, 0)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	if len(resp.Forecast) != DefaultForecastDays || fetcher.calls != 0 {
+		t.Fatalf("expected the prefetched days without a fetch, got %d days and %d fetches", len(resp.Forecast), fetcher.calls)
+	}
+
+	// Cached points are not fetched again.
+	s.PrefetchForecasts(context.Background(), []LatLon{{Lat: 60.17, Lon: 24.94}})
+	if len(batch.batches) != 1 {
+		t.Fatalf("expected no second batch, got %v", batch.batches)
+	}
+}
@@ -0,0 +1,67 @@
+package weather
+
+import "time"
+
+const (
+	// forecastDiffTempThreshold is how far (°C) a daily high or low must be
+	// revised before it counts as a material change.
+	forecastDiffTempThreshold = 2.0
+	// Precipitation counts as expected at this daily total (mm) or this
+	// average probability (%).
+	forecastDiffPrecipMM  = 0.5
+	forecastDiffPrecipPoP = 50.0
+)
+
+// ForecastChange is one material revision between two forecasts for a day.
+// Kind is "temperature_high", "temperature_low" or "precipitation".
+type ForecastChange struct {
+	Date     time.Time
+	Kind     string
+	Previous *float64
+	Current  *float64
+}
+
+// DiffForecasts compares two daily forecasts for the same location and
+// returns only the revisions worth waking a device for: a high or low moving
+// by more than forecastDiffTempThreshold, or precipitation newly expected on
+// a day that was previously dry. Days missing from either forecast are
+// skipped.
+func DiffForecasts(previous, current []DailyForecast) []ForecastChange {
+	prevByDate := make(map[string]DailyForecast, len(previous))
+	for _, f := range previous {
+		prevByDate[f.Date.Format("2006-01-02")] = f
+	}
+
+	var changes []ForecastChange
+	for _, cur := range current {
+		prev, ok := prevByDate[cur.Date.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		if tempRevised(prev.TempHigh, cur.TempHigh) {
+			changes = append(changes, ForecastChange{Date: cur.Date, Kind: "temperature_high", Previous: prev.TempHigh, Current: cur.TempHigh})
+		}
+		if tempRevised(prev.TempLow, cur.TempLow) {
+			changes = append(changes, ForecastChange{Date: cur.Date, Kind: "temperature_low", Previous: prev.TempLow, Current: cur.TempLow})
+		}
+		if !precipExpected(prev) && precipExpected(cur) {
+			changes = append(changes, ForecastChange{Date: cur.Date, Kind: "precipitation", Previous: prev.PrecipMM, Current: cur.PrecipMM})
+		}
+	}
+	return changes
+}
+
+func tempRevised(prev, cur *float64) bool {
+	if prev == nil || cur == nil {
+		return false
+	}
+	diff := *cur - *prev
+	return diff > forecastDiffTempThreshold || diff < -forecastDiffTempThreshold
+}
+
+func precipExpected(f DailyForecast) bool {
+	if f.PrecipMM != nil && *f.PrecipMM >= forecastDiffPrecipMM {
+		return true
+	}
+	return f.PoPAvg != nil && *f.PoPAvg >= forecastDiffPrecipPoP
+}
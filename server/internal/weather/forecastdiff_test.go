@@ -0,0 +1,41 @@
+package weather
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffForecasts_ReportsOnlyMaterialChanges(t *testing.T) {
+	day1 := time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	previous := []DailyForecast{
+		{Date: day1, TempHigh: ptr(10), TempLow: ptr(2), PrecipMM: ptr(0)},
+		{Date: day2, TempHigh: ptr(12), TempLow: ptr(4), PrecipMM: ptr(0.1)},
+	}
+	current := []DailyForecast{
+		{Date: day1, TempHigh: ptr(11.5), TempLow: ptr(-0.5), PrecipMM: ptr(0.2)},
+		{Date: day2, TempHigh: ptr(12), TempLow: ptr(4), PrecipMM: ptr(3.4)},
+		{Date: day2.AddDate(0, 0, 1), TempHigh: ptr(20)},
+	}
+
+	changes := DiffForecasts(previous, current)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Kind != "temperature_low" || !changes[0].Date.Equal(day1) {
+		t.Fatalf("expected low revision on day 1, got %+v", changes[0])
+	}
+	if changes[1].Kind != "precipitation" || !changes[1].Date.Equal(day2) || *changes[1].Current != 3.4 {
+		t.Fatalf("expected newly expected precipitation on day 2, got %+v", changes[1])
+	}
+}
+
+func TestDiffForecasts_IgnoresPrecipitationAlreadyExpected(t *testing.T) {
+	day := time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC)
+	previous := []DailyForecast{{Date: day, PoPAvg: ptr(60), PrecipMM: ptr(0.2)}}
+	current := []DailyForecast{{Date: day, PoPAvg: ptr(80), PrecipMM: ptr(5)}}
+
+	if changes := DiffForecasts(previous, current); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", changes)
+	}
+}
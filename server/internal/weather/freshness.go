@@ -0,0 +1,77 @@
+package weather
+
+import "time"
+
+// FreshnessWindow controls how long one kind of data is reused before going
+// back upstream. CacheTTL bounds the in-process cache; MaxAge bounds how old
+// persisted rows may be before they are refetched from FMI. A zero MaxAge
+// means the data type is not served from Postgres.
+type FreshnessWindow struct {
+	CacheTTL time.Duration
+	MaxAge   time.Duration
+}
+
+// Freshness holds the freshness windows for every cached data type.
+type Freshness struct {
+	DailyForecast  FreshnessWindow
+	HourlyForecast FreshnessWindow
+	UV             FreshnessWindow
+	Leaderboard    FreshnessWindow
+	HomeSensors    FreshnessWindow
+	Environment    FreshnessWindow
+	Radar          FreshnessWindow
+}
+
+func DefaultFreshness() Freshness {
+	return Freshness{
+		DailyForecast:  FreshnessWindow{CacheTTL: 10 * time.Minute, MaxAge: 3 * time.Hour},
+		HourlyForecast: FreshnessWindow{CacheTTL: 10 * time.Minute, MaxAge: 90 * time.Minute},
+		// MaxAge bounds how long the last UV forecast is served while FMI
+		// fails.
+		UV:          FreshnessWindow{CacheTTL: 10 * time.Minute, MaxAge: 3 * time.Hour},
+		Leaderboard: FreshnessWindow{CacheTTL: 5 * time.Minute},
+		// Netatmo stations upload every 10 minutes.
+		HomeSensors: FreshnessWindow{CacheTTL: 5 * time.Minute},
+		// MaxAge bounds how long a failed environment source keeps serving
+		// its last known value, flagged as stale.
+		Environment: FreshnessWindow{CacheTTL: 15 * time.Minute, MaxAge: 6 * time.Hour},
+		Radar:       FreshnessWindow{CacheTTL: 5 * time.Minute},
+	}
+}
+
+// Named returns the windows keyed by the data type names used in
+// configuration files and the admin API.
+func (f Freshness) Named() map[string]FreshnessWindow {
+	return map[string]FreshnessWindow{
+		"daily_forecast":  f.DailyForecast,
+		"hourly_forecast": f.HourlyForecast,
+		"uv":              f.UV,
+		"leaderboard":     f.Leaderboard,
+		"home_sensors":    f.HomeSensors,
+		"environment":     f.Environment,
+		"radar":           f.Radar,
+	}
+}
+
+// Window returns a pointer to the named window so configuration loaders can
+// override it in place, or nil for an unknown name.
+func (f *Freshness) Window(name string) *FreshnessWindow {
+	switch name {
+	case "daily_forecast":
+		return &f.DailyForecast
+	case "hourly_forecast":
+		return &f.HourlyForecast
+	case "uv":
+		return &f.UV
+	case "leaderboard":
+		return &f.Leaderboard
+	case "home_sensors":
+		return &f.HomeSensors
+	case "environment":
+		return &f.Environment
+	case "radar":
+		return &f.Radar
+	default:
+		return nil
+	}
+}
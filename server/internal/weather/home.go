@@ -0,0 +1,74 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HomeSensorReading is one module of a user's home weather station, reported
+// by a third-party provider. Source names the provider so clients can label
+// it separately from official FMI observations.
+type HomeSensorReading struct {
+	Source      string
+	StationName string
+	ModuleName  string
+	ModuleType  string // indoor, outdoor, wind or rain
+	ObservedAt  time.Time
+	Temperature *float64
+	Humidity    *float64
+	Pressure    *float64
+	CO2         *float64
+	Noise       *float64
+	WindSpeed   *float64
+	WindGust    *float64
+	WindDir     *float64
+	Precip1h    *float64
+}
+
+// HomeSensorProvider fetches the home sensors linked to an API client. It
+// returns no readings, without error, for clients that have no linked account.
+type HomeSensorProvider interface {
+	HomeSensors(ctx context.Context, clientID string) ([]HomeSensorReading, error)
+}
+
+// SetHomeSensorProvider enables the optional home-sensor integration.
+func (s *Service) SetHomeSensorProvider(p HomeSensorProvider) {
+	s.homeSensors = p
+}
+
+const (
+	// homeSensorTimeout bounds the provider call, which sits on the
+	// /v1/weather request path; home sensors are an extra and must not
+	// hold up the rest of the response.
+	homeSensorTimeout = 2 * time.Second
+	// homeSensorFailureTTL is how long a failed fetch is remembered, so
+	// requests do not wait on a provider that is down or a revoked token.
+	homeSensorFailureTTL = time.Minute
+)
+
+// GetHomeSensors returns the client's home sensor readings, or nil when the
+// integration is disabled.
+func (s *Service) GetHomeSensors(ctx context.Context, clientID string) ([]HomeSensorReading, error) {
+	if s.homeSensors == nil || clientID == "" {
+		return nil, nil
+	}
+	if cached, ok := s.homeSensorCache.Get(clientID); ok {
+		return cached, nil
+	}
+	if err, failed := s.homeSensorFailures.Get(clientID); failed {
+		return nil, fmt.Errorf("fetch home sensors (recently failed): %w", err)
+	}
+	fetchCtx, cancel := context.WithTimeout(ctx, homeSensorTimeout)
+	defer cancel()
+	readings, err := s.homeSensors.HomeSensors(fetchCtx, clientID)
+	if err != nil {
+		// A request the caller abandoned says nothing about the provider.
+		if ctx.Err() == nil {
+			s.homeSensorFailures.Set(clientID, err)
+		}
+		return nil, fmt.Errorf("fetch home sensors: %w", err)
+	}
+	s.homeSensorCache.Set(clientID, readings)
+	return readings, nil
+}
@@ -0,0 +1,66 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type homeSensorProviderStub struct {
+	calls int
+	err   error
+	block bool
+}
+
+func (p *homeSensorProviderStub) HomeSensors(ctx context.Context, clientID string) ([]HomeSensorReading, error) {
+	p.calls++
+	if p.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return nil, p.err
+}
+
+func TestGetHomeSensors_RemembersFailures(t *testing.T) {
+	provider := &homeSensorProviderStub{err: errors.New("invalid_grant")}
+	s := NewService(nil, nil, DefaultFreshness())
+	s.SetHomeSensorProvider(provider)
+
+	for range 3 {
+		if _, err := s.GetHomeSensors(context.Background(), "app"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if provider.calls != 1 {
+		t.Fatalf("expected one provider call while the failure is cached, got %d", provider.calls)
+	}
+}
+
+func TestGetHomeSensors_BoundsSlowProvider(t *testing.T) {
+	provider := &homeSensorProviderStub{block: true}
+	s := NewService(nil, nil, DefaultFreshness())
+	s.SetHomeSensorProvider(provider)
+
+	start := time.Now()
+	if _, err := s.GetHomeSensors(context.Background(), "app"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > homeSensorTimeout+time.Second {
+		t.Fatalf("provider call was not bounded: took %v", elapsed)
+	}
+}
+
+func TestGetHomeSensors_IgnoresCallerCancellation(t *testing.T) {
+	provider := &homeSensorProviderStub{block: true}
+	s := NewService(nil, nil, DefaultFreshness())
+	s.SetHomeSensorProvider(provider)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.GetHomeSensors(ctx, "app")
+	provider.block = false
+	if _, err := s.GetHomeSensors(context.Background(), "app"); err != nil {
+		t.Fatalf("expected a cancelled request not to be remembered as a failure, got %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetLightning returns the strikes within radiusKM of a location during the
+// last window, oldest first.
+func (s *Service) GetLightning(ctx context.Context, lat, lon, radiusKM float64, window time.Duration) ([]LightningStrike, error) {
+	if lon < finlandMinLon || lon > finlandMaxLon || lat < finlandMinLat || lat > finlandMaxLat {
+		return nil, ErrOutOfCoverage
+	}
+	strikes, err := s.store.LightningNear(ctx, lat, lon, radiusKM, time.Now().Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("lightning near: %w", err)
+	}
+	return strikes, nil
+}
@@ -0,0 +1,44 @@
+package weather
+
+import (
+	"context"
+
+	"wby/internal/logging"
+)
+
+// LongRangeForecaster fetches a lower-confidence forecast that reaches
+// further than the configured model; *fmi.Client implements it with ECMWF.
+type LongRangeForecaster interface {
+	FetchForecastECMWF(ctx context.Context, lat, lon float64, days int) (ForecastData, error)
+}
+
+// SetLongRangeForecaster enables extending daily forecasts past the
+// configured model's horizon, up to MaxForecastDays.
+func (s *Service) SetLongRangeForecaster(f LongRangeForecaster) {
+	s.longRange = f
+}
+
+// extendForecast appends long-range days after the last day of forecasts
+// until there are days entries. Days both models cover keep the configured
+// model's values. A failed long-range fetch is logged and the forecast
+// served as it is.
+func (s *Service) extendForecast(ctx context.Context, gridLat, gridLon float64, days int, forecasts []DailyForecast) []DailyForecast {
+	if s.longRange == nil || len(forecasts) >= days {
+		return forecasts
+	}
+	tail, err := s.longRange.FetchForecastECMWF(ctx, gridLat, gridLon, days)
+	if err != nil {
+		logging.FromContext(ctx).Warn("long-range forecast fetch failed", "err", err, "lat", gridLat, "lon", gridLon)
+		return forecasts
+	}
+	for _, f := range tail.Forecasts {
+		if len(forecasts) >= days {
+			break
+		}
+		if len(forecasts) > 0 && !f.Date.After(forecasts[len(forecasts)-1].Date) {
+			continue
+		}
+		forecasts = append(forecasts, f)
+	}
+	return forecasts
+}
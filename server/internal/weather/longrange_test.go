@@ -0,0 +1,98 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// horizonForecastFetcher returns at most horizon days of edited forecast,
+// however many are asked for.
+type horizonForecastFetcher struct {
+	stubForecastFetcher
+	horizon int
+}
+
+func (f horizonForecastFetcher) FetchForecast(ctx context.Context, lat, lon float64, days int) (ForecastData, error) {
+	return ForecastData{Forecasts: sourcedForecastDays(min(days, f.horizon), ForecastSourceEdited, 1)}, nil
+}
+
+type stubLongRange struct {
+	err error
+}
+
+func (s stubLongRange) FetchForecastECMWF(ctx context.Context, lat, lon float64, days int) (ForecastData, error) {
+	if s.err != nil {
+		return ForecastData{}, s.err
+	}
+	return ForecastData{Forecasts: sourcedForecastDays(days, ForecastSourceECMWF, 9)}, nil
+}
+
+func sourcedForecastDays(n int, source string, temp float64) []DailyForecast {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	forecasts := make([]DailyForecast, n)
+	for i := range forecasts {
+		forecasts[i] = DailyForecast{Date: today.AddDate(0, 0, i), FetchedAt: time.Now(), Source: source, TempAvg: ptr(temp)}
+	}
+	return forecasts
+}
+
+func TestGetForecast_ExtendsWithLongRangeDays(t *testing.T) {
+	svc := NewService(emptyStore{}, horizonForecastFetcher{horizon: 10}, DefaultFreshness())
+	svc.SetLongRangeForecaster(stubLongRange{})
+
+	forecasts, _, _, err := svc.getForecast(context.Background(), 60.2, 24.9, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forecasts) != 15 {
+		t.Fatalf("expected 15 days, got %d", len(forecasts))
+	}
+	for i, f := range forecasts {
+		// Days both models cover keep the edited values.
+		wantSource, wantTemp := ForecastSourceEdited, 1.0
+		if i >= 10 {
+			wantSource, wantTemp = ForecastSourceECMWF, 9.0
+		}
+		if f.Source != wantSource || *f.TempAvg != wantTemp {
+			t.Errorf("day %d: expected %s %g, got %s %g", i, wantSource, wantTemp, f.Source, *f.TempAvg)
+		}
+		if i > 0 && !f.Date.After(forecasts[i-1].Date) {
+			t.Errorf("day %d: dates out of order", i)
+		}
+	}
+}
+
+func TestGetForecast_LongRangeOnlyWhenNeeded(t *testing.T) {
+	cases := []struct {
+		name      string
+		longRange LongRangeForecaster
+		days      int
+		want      int
+	}{
+		{"within the edited horizon", stubLongRange{err: errors.New("should not be called")}, 10, 10},
+		{"long-range fetch fails", stubLongRange{err: errors.New("fmi: 502 Bad Gateway")}, 15, 10},
+		{"long range disabled", nil, 15, 10},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewService(emptyStore{}, horizonForecastFetcher{horizon: 10}, DefaultFreshness())
+			if tc.longRange != nil {
+				svc.SetLongRangeForecaster(tc.longRange)
+			}
+			forecasts, _, _, err := svc.getForecast(context.Background(), 60.2, 24.9, tc.days)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(forecasts) != tc.want {
+				t.Fatalf("expected %d days, got %d", tc.want, len(forecasts))
+			}
+			for _, f := range forecasts {
+				if f.Source != ForecastSourceEdited {
+					t.Fatalf("expected only edited days, got %s", f.Source)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package weather
+
+import (
+	"context"
+	"time"
+
+	"wby/internal/logging"
+)
+
+// MarineObservation is one measurement from an FMI wave buoy or
+// mareograph. Buoys report waves and water temperature, mareographs only
+// sea level, so a single observation rarely has every field set.
+type MarineObservation struct {
+	FMISID           int
+	ObservedAt       time.Time
+	WaveHeight       *float64 // significant wave height, m
+	WaveDirection    *float64 // modal wave direction, degrees
+	WavePeriod       *float64 // peak wave period, s
+	WaterTemperature *float64 // °C
+	SeaLevel         *float64 // relative to the theoretical mean sea level, mm
+}
+
+// MarineReading is the latest marine observation near a location.
+type MarineReading struct {
+	Station    Station
+	DistanceKM float64
+	MarineObservation
+}
+
+const (
+	// DefaultMarineMaxDistanceKM is how far the nearest marine station may
+	// be before a location counts as inland.
+	DefaultMarineMaxDistanceKM = 30
+	// marineMaxAge bounds how old the latest marine observation may be.
+	marineMaxAge = 3 * time.Hour
+)
+
+// SetMarineMaxDistanceKM sets how far the nearest marine station may be for
+// a marine section to be included.
+func (s *Service) SetMarineMaxDistanceKM(km float64) {
+	if km > 0 {
+		s.marineMaxDistanceKM = km
+	}
+}
+
+// nearestMarine returns the latest observation of the nearest marine
+// station, or nil for inland locations and stations without recent data.
+// Marine data is supplementary, so store failures are logged and the
+// weather response is served without it.
+func (s *Service) nearestMarine(ctx context.Context, lat, lon float64, now time.Time) *MarineReading {
+	station, distKM, err := s.store.NearestMarineStation(ctx, lat, lon)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to find marine station", "err", err, "lat", lat, "lon", lon)
+		return nil
+	}
+	if station == nil || distKM > s.marineMaxDistanceKM {
+		return nil
+	}
+	obs, err := s.store.LatestMarineObservation(ctx, station.FMISID)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to load marine observation", "err", err, "fmisid", station.FMISID)
+		return nil
+	}
+	if obs == nil || now.Sub(obs.ObservedAt) > marineMaxAge {
+		return nil
+	}
+	return &MarineReading{Station: *station, DistanceKM: distKM, MarineObservation: *obs}
+}
@@ -0,0 +1,49 @@
+package weather
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type marineStore struct {
+	emptyStore
+	distKM float64
+	obs    *MarineObservation
+}
+
+func (s marineStore) NearestMarineStation(ctx context.Context, lat, lon float64) (*Station, float64, error) {
+	return &Station{FMISID: 134220, Name: "Helsinki Suomenlahti aaltopoiju"}, s.distKM, nil
+}
+
+func (s marineStore) LatestMarineObservation(ctx context.Context, fmisid int) (*MarineObservation, error) {
+	return s.obs, nil
+}
+
+func TestNearestMarine(t *testing.T) {
+	now := time.Date(2026, 7, 16, 7, 0, 0, 0, time.UTC)
+	obs := &MarineObservation{FMISID: 134220, ObservedAt: now.Add(-30 * time.Minute), WaveHeight: ptr(0.9)}
+
+	svc := NewService(marineStore{distKM: 12, obs: obs}, nil, DefaultFreshness())
+	got := svc.nearestMarine(context.Background(), 60.1, 25.0, now)
+	if got == nil || got.Station.FMISID != 134220 || got.WaveHeight == nil || *got.WaveHeight != 0.9 {
+		t.Fatalf("expected a marine reading, got %+v", got)
+	}
+
+	// Inland: the nearest marine station is beyond the configured distance.
+	svc = NewService(marineStore{distKM: 45, obs: obs}, nil, DefaultFreshness())
+	if got := svc.nearestMarine(context.Background(), 61.5, 23.8, now); got != nil {
+		t.Fatalf("expected no marine reading inland, got %+v", got)
+	}
+	svc.SetMarineMaxDistanceKM(50)
+	if got := svc.nearestMarine(context.Background(), 61.5, 23.8, now); got == nil {
+		t.Fatal("expected a marine reading within a raised distance limit")
+	}
+
+	// Stale observations are dropped.
+	stale := &MarineObservation{FMISID: 134220, ObservedAt: now.Add(-6 * time.Hour), WaveHeight: ptr(0.9)}
+	svc = NewService(marineStore{distKM: 12, obs: stale}, nil, DefaultFreshness())
+	if got := svc.nearestMarine(context.Background(), 60.1, 25.0, now); got != nil {
+		t.Fatalf("expected no reading for a stale observation, got %+v", got)
+	}
+}
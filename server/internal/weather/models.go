@@ -10,6 +10,29 @@ type Station struct {
 	Lat     float64
 	Lon     float64
 	WMOCode string
+	// Region is the municipality the station is in, such as "Helsinki" for
+	// Helsinki Kaisaniemi, empty when FMI did not report it.
+	Region string
+	// ElevationM is the station's height above sea level, nil when FMI did
+	// not report it.
+	ElevationM *float64
+	// Type is one of the StationType constants, empty when unknown.
+	Type string
+}
+
+// Station types, from the FMI station groups a station belongs to.
+const (
+	StationTypeAWS           = "aws"
+	StationTypePrecipitation = "precipitation"
+	StationTypeMareograph    = "mareograph"
+)
+
+// NearbyStation is a station with its distance from a requested point and
+// the time of its latest stored observation, nil if it has none.
+type NearbyStation struct {
+	Station
+	DistanceKM     float64
+	LastObservedAt *time.Time
 }
 
 type Observation struct {
@@ -32,13 +55,24 @@ type Observation struct {
 }
 
 type DailyForecast struct {
-	GridLat                        float64
-	GridLon                        float64
-	Date                           time.Time
-	FetchedAt                      time.Time
-	TempHigh                       *float64
-	TempLow                        *float64
-	TempAvg                        *float64
+	GridLat       float64
+	GridLon       float64
+	Date          time.Time
+	FetchedAt     time.Time
+	SchemaVersion int
+	Source        string
+	TempHigh      *float64
+	TempLow       *float64
+	TempAvg       *float64
+	TempHighRaw   *float64
+	TempLowRaw    *float64
+	TempAvgRaw    *float64
+	// TempDay* cover 06:00-18:00 local time and TempNight* the rest of the
+	// calendar day; each is nil when the forecast has no hours in it.
+	TempDayMax                     *float64
+	TempDayAvg                     *float64
+	TempNightMin                   *float64
+	TempNightAvg                   *float64
 	WindSpeed                      *float64
 	WindDir                        *float64
 	HumidityAvg                    *float64
@@ -71,19 +105,57 @@ type DailyForecast struct {
 	WindUMSAvg                     *float64
 	WindVMSAvg                     *float64
 	WindVectorMSAvg                *float64
-	UVIndexAvg                     *float64
+	UVIndexMax                     *float64
+	SunshineHours                  *float64
+	DayLengthHours                 *float64
+
+	// PrecipHoursCounted is how many hourly values PrecipMM sums; it is
+	// below 24 on today and other days the forecast only partly covers,
+	// and the same hours feed PoPAvg and the radiation averages.
+	PrecipHoursCounted int
+
+	// SnowAccumulationMM estimates the depth of fresh snow from the hourly
+	// precipitation, its form and the temperature; nil without
+	// precipitation data.
+	SnowAccumulationMM *float64
+
+	// Computed on each request, not stored.
+	Sunrise          *time.Time
+	Sunset           *time.Time
+	PolarDay         bool
+	PolarNight       bool
+	MoonPhase        *float64
+	MoonIllumination *float64
+	// NormalTempHigh and NormalTempLow are the 1991-2020 normals for the
+	// date at the nearest station that has them.
+	NormalTempHigh *float64
+	NormalTempLow  *float64
 }
 
 type HourlyForecast struct {
-	Time        time.Time
-	FetchedAt   time.Time
-	Temperature *float64
-	WindSpeed   *float64
-	WindDir     *float64
-	Humidity    *float64
-	Precip1h    *float64
-	Symbol      *string
+	Time           time.Time
+	FetchedAt      time.Time
+	SchemaVersion  int
+	Temperature    *float64
+	TemperatureRaw *float64
+	WindSpeed      *float64
+	WindDir        *float64
+	WindGust       *float64
+	Humidity       *float64
+	Pressure       *float64
+	DewPoint       *float64
+	Precip1h       *float64
+	// PoP is the probability of precipitation in percent, 0-100.
+	PoP    *float64
+	Symbol *string
+	// UVCumulated is FMI's running UV dose for the day, kept for
+	// debugging; UVIndex is the index clients show.
 	UVCumulated *float64
+	// UVIndex is the UV index over the hour ending at Time, derived from
+	// consecutive UVCumulated samples.
+	UVIndex      *float64
+	CloudCover   *float64
+	FogIntensity *float64
 }
 
 type UVDataPoint struct {
@@ -95,18 +167,102 @@ type CurrentWeather struct {
 	Station     Station
 	DistanceKM  float64
 	Observation Observation
+	// Contributors lists the stations a blended Observation drew on, nil
+	// when it is a single station's.
+	Contributors []BlendContribution
+	// TempAnomaly is how far the observed temperature is above (or, when
+	// negative, below) the normal average for the date, nil without
+	// normals nearby.
+	TempAnomaly *float64
 }
 
 type WeatherResponse struct {
-	Current  CurrentWeather
-	Hourly   []HourlyForecast
-	Forecast []DailyForecast
-	Timezone string
+	Current         CurrentWeather
+	Hourly          []HourlyForecast
+	Forecast        []DailyForecast
+	Timezone        string
+	FogAdvisory     *FogAdvisory
+	SynopticSummary string
+	Warnings        []Warning
+	AirQuality      *AirQualityReading
+	Marine          *MarineReading
+	Provenance      Provenance
+}
+
+// LastModified returns the newest of the observation time and the fetch
+// times of the forecast entries, zero when none is known.
+func (r *WeatherResponse) LastModified() time.Time {
+	latest := r.Current.Observation.ObservedAt
+	for _, f := range r.Forecast {
+		if f.FetchedAt.After(latest) {
+			latest = f.FetchedAt
+		}
+	}
+	for _, h := range r.Hourly {
+		if h.FetchedAt.After(latest) {
+			latest = h.FetchedAt
+		}
+	}
+	return latest
+}
+
+// Forecast models a DailyForecast's Source can name. ECMWF days extend
+// the forecast past the configured model's horizon and are less certain.
+const (
+	ForecastSourceEdited   = "edited"
+	ForecastSourceHarmonie = "harmonie"
+	ForecastSourceECMWF    = "ecmwf"
+)
+
+// Source says where a section of a response was read from.
+type Source string
+
+const (
+	// SourceCache is the service's in-memory cache.
+	SourceCache Source = "cache"
+	// SourceDB is the forecast tables in the store, including stale rows
+	// served because FMI was unreachable.
+	SourceDB Source = "db"
+	// SourceFMI is a fetch from FMI made for this request.
+	SourceFMI Source = "fmi"
+	// SourceUnavailable marks a best-effort section that could not be
+	// loaded.
+	SourceUnavailable Source = "unavailable"
+	// SourceSkipped marks a section the caller did not ask for.
+	SourceSkipped Source = "skipped"
+)
+
+// Provenance records how a WeatherResponse was assembled: the forecast grid
+// point the location snapped to and where each forecast section came from.
+type Provenance struct {
+	GridLat  float64
+	GridLon  float64
+	Forecast Source
+	Hourly   Source
+	UV       Source
+}
+
+type ForecastResponse struct {
+	Hourly          []HourlyForecast
+	Forecast        []DailyForecast
+	Timezone        string
+	SynopticSummary string
 }
 
 type ForecastData struct {
 	Forecasts []DailyForecast
-	Timezone  string
+	// Hourly is the hourly series the daily forecasts were aggregated
+	// from, when the fetcher returns it.
+	Hourly   []HourlyForecast
+	Timezone string
+}
+
+// BBox is a lon/lat bounding box in degrees.
+type BBox struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
 }
 
 type MapOverlayRequest struct {
@@ -167,3 +323,76 @@ type LeaderboardEntry struct {
 	DistanceKM  float64
 	ObservedAt  time.Time
 }
+
+// CustomObservation is a reading pushed by a user's own weather station. It
+// lives in its own namespace keyed by the owning API client and never mixes
+// with the official FMI station tables.
+type CustomObservation struct {
+	ClientID    string
+	StationID   string
+	Source      string
+	Lat         float64
+	Lon         float64
+	ObservedAt  time.Time
+	Temperature *float64
+	Humidity    *float64
+	DewPoint    *float64
+	WindSpeed   *float64
+	WindGust    *float64
+	WindDir     *float64
+	Pressure    *float64
+	Precip1h    *float64
+}
+
+// ForecastObservationPair joins one stored hourly forecast with what the
+// nearest station later observed at the forecast time.
+type ForecastObservationPair struct {
+	GridLat             float64
+	GridLon             float64
+	ForecastTime        time.Time
+	FetchedAt           time.Time
+	FMISID              int
+	StationDistanceKM   float64
+	ForecastTemperature *float64
+	ForecastWindSpeed   *float64
+	ForecastHumidity    *float64
+	ForecastPrecip1h    *float64
+	ObservedTemperature *float64
+	ObservedWindSpeed   *float64
+	ObservedHumidity    *float64
+	ObservedPrecip1h    *float64
+}
+
+// RadiationObservation is a radiation station's mean global radiation
+// (W/m²) around one whole hour.
+type RadiationObservation struct {
+	Lat             float64
+	Lon             float64
+	Time            time.Time
+	RadiationGlobal float64
+}
+
+// SunshineForecast is the stored sunshine estimate of one grid cell's daily
+// forecast.
+type SunshineForecast struct {
+	GridLat       float64
+	GridLon       float64
+	FetchedAt     time.Time
+	SunshineHours float64
+}
+
+// Warning is an official weather warning, such as a wind or forest fire
+// warning, parsed from an FMI CAP alert.
+type Warning struct {
+	ID          string
+	Event       string
+	Severity    string // CAP severity: Minor, Moderate, Severe or Extreme
+	Headline    string
+	Description string
+	AreaDesc    string
+	Onset       time.Time
+	Expires     time.Time
+	// Polygons holds the warning area as closed rings of (lat, lon)
+	// points. It is only set on parsed warnings, not on stored ones.
+	Polygons [][][2]float64
+}
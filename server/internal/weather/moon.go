@@ -0,0 +1,69 @@
+package weather
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+const synodicMonthDays = 29.530588853
+
+// referenceNewMoon is a well-documented new moon (2000-01-06 18:14 UTC)
+// used as the epoch for the mean lunar phase calculation.
+var referenceNewMoon = time.Date(2000, 1, 6, 18, 14, 0, 0, time.UTC)
+
+// MoonPhase returns the mean lunar phase at t as a fraction of the synodic
+// month: 0 is new moon, 0.25 first quarter, 0.5 full moon, 0.75 last quarter.
+func MoonPhase(t time.Time) float64 {
+	days := t.Sub(referenceNewMoon).Hours() / 24
+	phase := math.Mod(days, synodicMonthDays) / synodicMonthDays
+	if phase < 0 {
+		phase++
+	}
+	return phase
+}
+
+// Moon phase names returned by MoonPhaseName.
+const (
+	MoonNew            = "new_moon"
+	MoonWaxingCrescent = "waxing_crescent"
+	MoonFirstQuarter   = "first_quarter"
+	MoonWaxingGibbous  = "waxing_gibbous"
+	MoonFull           = "full_moon"
+	MoonWaningGibbous  = "waning_gibbous"
+	MoonLastQuarter    = "last_quarter"
+	MoonWaningCrescent = "waning_crescent"
+)
+
+var moonPhaseNames = [...]string{
+	MoonNew, MoonWaxingCrescent, MoonFirstQuarter, MoonWaxingGibbous,
+	MoonFull, MoonWaningGibbous, MoonLastQuarter, MoonWaningCrescent,
+}
+
+// MoonPhaseName names a phase as returned by MoonPhase. Each of the eight
+// names covers an eighth of the month centred on its phase, so "full_moon"
+// spans 0.4375-0.5625.
+func MoonPhaseName(phase float64) string {
+	return moonPhaseNames[int(math.Floor(phase*8+0.5))%8]
+}
+
+// withMoonPhases returns a copy of forecasts with the phase and
+// illumination of the moon at noon UTC on each date, rounded to three
+// decimals like the stargazing nights.
+func withMoonPhases(forecasts []DailyForecast) []DailyForecast {
+	out := slices.Clone(forecasts)
+	for i := range out {
+		phase := MoonPhase(out[i].Date.Add(12 * time.Hour))
+		rounded := math.Round(phase*1000) / 1000
+		illumination := math.Round(MoonIllumination(phase)*1000) / 1000
+		out[i].MoonPhase = &rounded
+		out[i].MoonIllumination = &illumination
+	}
+	return out
+}
+
+// MoonIllumination returns the illuminated fraction of the lunar disc (0-1)
+// for a phase as returned by MoonPhase.
+func MoonIllumination(phase float64) float64 {
+	return (1 - math.Cos(2*math.Pi*phase)) / 2
+}
@@ -0,0 +1,61 @@
+package weather
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMoonPhaseName_KnownDates(t *testing.T) {
+	tests := []struct {
+		name  string
+		at    time.Time
+		phase float64
+		named string
+	}{
+		{"new moon 2024-01-11", time.Date(2024, 1, 11, 11, 57, 0, 0, time.UTC), 0, MoonNew},
+		{"full moon 2024-01-25", time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC), 0.5, MoonFull},
+		{"new moon 2026-06-15", time.Date(2026, 6, 15, 2, 54, 0, 0, time.UTC), 0, MoonNew},
+		{"full moon 2026-06-29", time.Date(2026, 6, 29, 23, 57, 0, 0, time.UTC), 0.5, MoonFull},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phase := MoonPhase(tt.at)
+			// The mean phase may be off by up to about half a day.
+			diff := math.Abs(phase - tt.phase)
+			diff = math.Min(diff, 1-diff)
+			if diff > 0.03 {
+				t.Fatalf("expected phase near %v, got %.3f", tt.phase, phase)
+			}
+			if got := MoonPhaseName(phase); got != tt.named {
+				t.Fatalf("expected %s, got %s", tt.named, got)
+			}
+			if illum := MoonIllumination(phase); math.Abs(illum-tt.phase*2) > 0.01 {
+				t.Fatalf("expected illumination near %v, got %.3f", tt.phase*2, illum)
+			}
+		})
+	}
+}
+
+func TestMoonPhaseName_Boundaries(t *testing.T) {
+	for phase, want := range map[float64]string{
+		0.97: MoonNew, 0.06: MoonNew, 0.07: MoonWaxingCrescent, 0.25: MoonFirstQuarter,
+		0.44: MoonFull, 0.75: MoonLastQuarter, 0.9: MoonWaningCrescent,
+	} {
+		if got := MoonPhaseName(phase); got != want {
+			t.Errorf("MoonPhaseName(%v) = %s, want %s", phase, got, want)
+		}
+	}
+}
+
+func TestWithMoonPhases(t *testing.T) {
+	forecasts := []DailyForecast{{Date: time.Date(2024, 1, 25, 0, 0, 0, 0, time.UTC)}}
+
+	got := withMoonPhases(forecasts)
+	if got[0].MoonPhase == nil || got[0].MoonIllumination == nil || *got[0].MoonIllumination < 0.99 {
+		t.Fatalf("expected a full moon, got %+v", got[0])
+	}
+	if forecasts[0].MoonPhase != nil {
+		t.Fatal("withMoonPhases must not modify its input")
+	}
+}
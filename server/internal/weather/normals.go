@@ -0,0 +1,64 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"wby/internal/logging"
+)
+
+// climateNormalsPeriod is the reference period normals are served for.
+const climateNormalsPeriod = "1991-2020"
+
+const maxClimateNormalsDistanceKm = 50.0
+
+// normalsCacheTTL bounds how long a location's normals are reused. They
+// only change when cmd/import-normals is rerun.
+const normalsCacheTTL = 24 * time.Hour
+
+// nearestNormals returns the monthly normals of the nearest station that
+// has them, which need not be the nearest observation station, or nil when
+// none is within maxClimateNormalsDistanceKm. Normals are supplementary, so
+// a store failure is logged and the response is served without them.
+func (s *Service) nearestNormals(ctx context.Context, lat, lon float64) []ClimateNormal {
+	gridLat, gridLon := snapToGrid(lat, lon)
+	cacheKey := fmt.Sprintf("%.2f,%.2f", gridLat, gridLon)
+	if normals, ok := s.normalsCache.Get(cacheKey); ok {
+		return normals
+	}
+
+	var normals []ClimateNormal
+	station, distKM, err := s.store.NearestStationWithClimateNormals(ctx, lat, lon, climateNormalsPeriod)
+	switch {
+	case errors.Is(err, ErrNoClimateNormals):
+	case err != nil:
+		logging.FromContext(ctx).Warn("failed to find climate normals station", "err", err, "lat", lat, "lon", lon)
+		return nil
+	case distKM <= maxClimateNormalsDistanceKm:
+		if normals, err = s.store.GetClimateNormals(ctx, station.FMISID, climateNormalsPeriod); err != nil {
+			logging.FromContext(ctx).Warn("failed to load climate normals", "err", err, "fmisid", station.FMISID)
+			return nil
+		}
+	}
+	s.normalsCache.Set(cacheKey, normals)
+	return normals
+}
+
+// withNormals returns copies of the forecasts with the normal high and low
+// of each day set. The input is shared with the caches, so it is never
+// modified.
+func withNormals(forecasts []DailyForecast, normals []ClimateNormal) []DailyForecast {
+	if len(normals) == 0 || len(forecasts) == 0 {
+		return forecasts
+	}
+	forecasts = slices.Clone(forecasts)
+	for i := range forecasts {
+		normal := InterpolateNormals(normals, forecasts[i].Date, nil)
+		forecasts[i].NormalTempHigh = normal.TempHigh
+		forecasts[i].NormalTempLow = normal.TempLow
+	}
+	return forecasts
+}
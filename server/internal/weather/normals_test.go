@@ -0,0 +1,96 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// normalsStore is warningStore with the same normals for every month at a
+// station distKM away.
+type normalsStore struct {
+	warningStore
+	distKM  float64
+	err     error
+	lookups *int
+}
+
+func (normalsStore) LatestObservation(ctx context.Context, fmisid int) (Observation, error) {
+	return Observation{FMISID: fmisid, ObservedAt: time.Now(), Temperature: ptr(5)}, nil
+}
+
+func (s normalsStore) NearestStationWithClimateNormals(ctx context.Context, lat, lon float64, period string) (Station, float64, error) {
+	if s.lookups != nil {
+		*s.lookups++
+	}
+	if period != "1991-2020" {
+		return Station{}, 0, errors.New("unexpected period " + period)
+	}
+	return Station{FMISID: 100968, Name: "Helsinki-Vantaa"}, s.distKM, s.err
+}
+
+func (normalsStore) GetClimateNormals(ctx context.Context, fmisid int, period string) ([]ClimateNormal, error) {
+	normals := make([]ClimateNormal, 12)
+	for i := range normals {
+		normals[i] = ClimateNormal{FMISID: fmisid, Month: i + 1, Period: period, TempAvg: ptr(2), TempHigh: ptr(4), TempLow: ptr(0)}
+	}
+	return normals, nil
+}
+
+func TestGetWeather_AttachesClimateNormals(t *testing.T) {
+	lookups := 0
+	s := NewService(normalsStore{distKM: 15, lookups: &lookups}, stubForecastFetcher{}, DefaultFreshness())
+
+	for range 2 {
+		resp, err := s.GetWeather(context.Background(), 60.17, 24.94, WeatherOptions{})
+		if err != nil {
+			t.Fatalf("GetWeather: %v", err)
+		}
+		if resp.Current.TempAnomaly == nil || *resp.Current.TempAnomaly != 3 {
+			t.Fatalf("expected a +3 anomaly, got %v", resp.Current.TempAnomaly)
+		}
+		if len(resp.Forecast) == 0 {
+			t.Fatal("expected daily forecasts")
+		}
+		for _, f := range resp.Forecast {
+			if f.NormalTempHigh == nil || *f.NormalTempHigh != 4 || f.NormalTempLow == nil || *f.NormalTempLow != 0 {
+				t.Fatalf("%s: expected normals 4/0, got %v/%v", f.Date.Format(time.DateOnly), f.NormalTempHigh, f.NormalTempLow)
+			}
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("expected normals to be cached after one lookup, got %d", lookups)
+	}
+
+	// The cached forecast must not carry the normals of an earlier response.
+	cached, ok := s.forecastCache.Get("60.17,24.94")
+	if !ok || len(cached.forecasts) == 0 || cached.forecasts[0].NormalTempHigh != nil {
+		t.Fatalf("expected the cached forecast to stay unchanged, got %+v", cached)
+	}
+}
+
+func TestGetWeather_OmitsClimateNormalsWithoutStation(t *testing.T) {
+	cases := map[string]normalsStore{
+		"too far":       {distKM: 80},
+		"none imported": {err: ErrNoClimateNormals},
+		"lookup fails":  {err: errors.New("db down")},
+	}
+	for name, store := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := NewService(store, stubForecastFetcher{}, DefaultFreshness())
+			resp, err := s.GetWeather(context.Background(), 60.17, 24.94, WeatherOptions{})
+			if err != nil {
+				t.Fatalf("GetWeather: %v", err)
+			}
+			if resp.Current.TempAnomaly != nil {
+				t.Fatalf("expected no anomaly, got %v", *resp.Current.TempAnomaly)
+			}
+			for _, f := range resp.Forecast {
+				if f.NormalTempHigh != nil || f.NormalTempLow != nil {
+					t.Fatalf("expected no normals, got %v/%v", f.NormalTempHigh, f.NormalTempLow)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Place is a named location clients can search for instead of entering
+// coordinates. GeoID is the GeoNames ID FMI accepts as geoid, nil where it
+// is not known.
+type Place struct {
+	Name   string
+	Region string
+	Lat    float64
+	Lon    float64
+	GeoID  *int
+}
+
+const (
+	// MaxPlaceResults caps how many places one search returns.
+	MaxPlaceResults = 10
+	// maxPlaceQueryLength bounds the search text, in characters.
+	maxPlaceQueryLength = 100
+)
+
+// ErrInvalidPlaceQuery is returned for empty or overlong search text.
+var ErrInvalidPlaceQuery = errors.New("invalid place query")
+
+// SearchPlaces returns up to MaxPlaceResults places matching q: exact name
+// matches first, then prefix matches, then fuzzy (trigram) matches.
+func (s *Service) SearchPlaces(ctx context.Context, q string) ([]Place, error) {
+	q = strings.TrimSpace(q)
+	if q == "" || utf8.RuneCountInString(q) > maxPlaceQueryLength {
+		return nil, ErrInvalidPlaceQuery
+	}
+	places, err := s.store.SearchPlaces(ctx, q, MaxPlaceResults)
+	if err != nil {
+		return nil, fmt.Errorf("search places: %w", err)
+	}
+	return places, nil
+}
+
+// ResolvePlace looks up the single place named name, ignoring case. When no
+// place or more than one place has that name it returns nil together with
+// the closest matches, so callers can suggest alternatives.
+func (s *Service) ResolvePlace(ctx context.Context, name string) (*Place, []Place, error) {
+	places, err := s.SearchPlaces(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	name = strings.TrimSpace(name)
+	var exact []Place
+	for _, p := range places {
+		if strings.EqualFold(p.Name, name) {
+			exact = append(exact, p)
+		}
+	}
+	switch len(exact) {
+	case 1:
+		return &exact[0], nil, nil
+	case 0:
+		return nil, places, nil
+	default:
+		return nil, exact, nil
+	}
+}
@@ -0,0 +1,78 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type placesStore struct {
+	emptyStore
+	gotQuery string
+	gotLimit int
+}
+
+func (s *placesStore) SearchPlaces(ctx context.Context, q string, limit int) ([]Place, error) {
+	s.gotQuery, s.gotLimit = q, limit
+	return []Place{{Name: "Oulu"}}, nil
+}
+
+func TestSearchPlaces(t *testing.T) {
+	store := &placesStore{}
+	svc := NewService(store, nil, DefaultFreshness())
+
+	places, err := svc.SearchPlaces(context.Background(), "  oul ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(places) != 1 || store.gotQuery != "oul" || store.gotLimit != MaxPlaceResults {
+		t.Fatalf("unexpected search %q limit %d: %+v", store.gotQuery, store.gotLimit, places)
+	}
+
+	for _, q := range []string{"", "   ", strings.Repeat("ä", maxPlaceQueryLength+1)} {
+		if _, err := svc.SearchPlaces(context.Background(), q); !errors.Is(err, ErrInvalidPlaceQuery) {
+			t.Errorf("query of %d bytes: expected ErrInvalidPlaceQuery, got %v", len(q), err)
+		}
+	}
+}
+
+type resolveStore struct {
+	emptyStore
+	places []Place
+}
+
+func (s resolveStore) SearchPlaces(ctx context.Context, q string, limit int) ([]Place, error) {
+	return s.places, nil
+}
+
+func TestResolvePlace(t *testing.T) {
+	tests := []struct {
+		name        string
+		places      []Place
+		want        string
+		suggestions int
+	}{
+		{"unique exact match", []Place{{Name: "Tampere"}, {Name: "Tammela"}}, "Tampere", 0},
+		{"no exact match", []Place{{Name: "Tammela"}, {Name: "Tammisaari"}}, "", 2},
+		{"ambiguous", []Place{{Name: "Tampere", Region: "A"}, {Name: "Tampere", Region: "B"}, {Name: "Tammela"}}, "", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(resolveStore{places: tt.places}, nil, DefaultFreshness())
+			place, suggestions, err := svc.ResolvePlace(context.Background(), " TAMPERE ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == "" {
+				if place != nil || len(suggestions) != tt.suggestions {
+					t.Fatalf("expected %d suggestions and no place, got %+v %+v", tt.suggestions, place, suggestions)
+				}
+				return
+			}
+			if place == nil || place.Name != tt.want || suggestions != nil {
+				t.Fatalf("expected %s, got %+v %+v", tt.want, place, suggestions)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// ErrRadarUnavailable is returned when no radar source is configured or it
+// currently advertises no images.
+var ErrRadarUnavailable = errors.New("radar unavailable")
+
+// radarTimesTTL bounds how long the advertised radar timestamps are reused.
+// FMI publishes a new composite every 5 minutes.
+const radarTimesTTL = time.Minute
+
+const (
+	// radarCacheEntries bounds the radar image cache. At a few tens of
+	// kilobytes per PNG this stays within a few megabytes.
+	radarCacheEntries = 256
+	// Radar tiles are squares of a power of two degrees between these, so
+	// nearby requests share a tile and the number of tiles is bounded.
+	radarMinTileDeg = 1.0 / 64
+	radarMaxTileDeg = 64.0
+	// Radar image sides are rounded up to a power of two pixels.
+	radarMinDim = 64
+)
+
+// RadarRequest selects a radar composite image. A zero Time asks for the
+// latest one.
+type RadarRequest struct {
+	BBox   BBox
+	Width  int
+	Height int
+	Time   time.Time
+}
+
+// RadarImage is a PNG radar composite, the area it covers and the time it
+// is valid for. BBox can be larger than the requested one, see
+// snapRadarRequest.
+type RadarImage struct {
+	PNG  []byte
+	BBox BBox
+	Time time.Time
+}
+
+// RadarSource serves precipitation radar composites.
+type RadarSource interface {
+	// RadarTimes returns the timestamps images are available for.
+	RadarTimes(ctx context.Context) ([]time.Time, error)
+	// FetchRadarImage renders the composite at exactly req.Time.
+	FetchRadarImage(ctx context.Context, req RadarRequest) ([]byte, error)
+}
+
+// SetRadarSource enables the radar image proxy.
+func (s *Service) SetRadarSource(src RadarSource) {
+	s.radar = src
+}
+
+// GetRadarImage returns the radar composite covering the area, at the
+// latest available time not after req.Time. The area and size are snapped
+// to a tile grid first, and images are cached by tile, size and time, so
+// map clients panning over the same tiles share upstream fetches.
+func (s *Service) GetRadarImage(ctx context.Context, req RadarRequest) (*RadarImage, error) {
+	if s.radar == nil {
+		return nil, ErrRadarUnavailable
+	}
+	times, err := s.radarTimes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	at, ok := clampRadarTime(times, req.Time)
+	if !ok {
+		return nil, ErrRadarUnavailable
+	}
+	req = snapRadarRequest(req)
+	req.Time = at
+
+	cacheKey := fmt.Sprintf("%g,%g,%g,%g:%dx%d:%s",
+		req.BBox.MinLon, req.BBox.MinLat, req.BBox.MaxLon, req.BBox.MaxLat,
+		req.Width, req.Height, at.Format(time.RFC3339))
+	if cached, ok := s.radarCache.Get(cacheKey); ok {
+		return cached, nil
+	}
+
+	png, err := s.radar.FetchRadarImage(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch radar image: %w", err)
+	}
+	img := &RadarImage{PNG: png, BBox: req.BBox, Time: at}
+	s.radarCache.Set(cacheKey, img)
+	return img, nil
+}
+
+func (s *Service) radarTimes(ctx context.Context) ([]time.Time, error) {
+	if cached, ok := s.radarTimesCache.Get("times"); ok {
+		return cached, nil
+	}
+	times, err := s.radar.RadarTimes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("radar times: %w", err)
+	}
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+	s.radarTimesCache.Set("times", times)
+	return times, nil
+}
+
+// clampRadarTime picks the latest of the sorted times not after at, the
+// latest overall for a zero at, and the earliest when at predates them all.
+func clampRadarTime(times []time.Time, at time.Time) (time.Time, bool) {
+	if len(times) == 0 {
+		return time.Time{}, false
+	}
+	if at.IsZero() {
+		return times[len(times)-1], true
+	}
+	i, found := slices.BinarySearchFunc(times, at, func(t, target time.Time) int { return t.Compare(target) })
+	if found {
+		return times[i], true
+	}
+	if i == 0 {
+		return times[0], true
+	}
+	return times[i-1], true
+}
+
+// snapRadarRequest widens the bbox to whole tiles of the smallest power of
+// two degrees at least as wide and tall as it, which is one or two tiles
+// per axis, and rounds the size up to a power of two pixels. Arbitrary
+// client bboxes and sizes would otherwise each need their own upstream
+// fetch and cache entry.
+func snapRadarRequest(req RadarRequest) RadarRequest {
+	span := max(req.BBox.MaxLon-req.BBox.MinLon, req.BBox.MaxLat-req.BBox.MinLat)
+	tile := radarMinTileDeg
+	for tile < span && tile < radarMaxTileDeg {
+		tile *= 2
+	}
+	req.BBox = BBox{
+		MinLon: math.Floor(req.BBox.MinLon/tile) * tile,
+		MinLat: math.Floor(req.BBox.MinLat/tile) * tile,
+		MaxLon: math.Ceil(req.BBox.MaxLon/tile) * tile,
+		MaxLat: math.Ceil(req.BBox.MaxLat/tile) * tile,
+	}
+	req.Width = radarDim(req.Width)
+	req.Height = radarDim(req.Height)
+	return req
+}
+
+func radarDim(n int) int {
+	dim := radarMinDim
+	for dim < n {
+		dim *= 2
+	}
+	return dim
+}
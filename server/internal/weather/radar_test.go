@@ -0,0 +1,117 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubRadarSource struct {
+	times   []time.Time
+	fetched []RadarRequest
+	err     error
+}
+
+func (s *stubRadarSource) RadarTimes(ctx context.Context) ([]time.Time, error) {
+	return s.times, nil
+}
+
+func (s *stubRadarSource) FetchRadarImage(ctx context.Context, req RadarRequest) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.fetched = append(s.fetched, req)
+	return []byte(req.Time.Format(time.RFC3339)), nil
+}
+
+func TestClampRadarTime(t *testing.T) {
+	base := time.Date(2026, 1, 15, 6, 0, 0, 0, time.UTC)
+	times := []time.Time{base, base.Add(5 * time.Minute), base.Add(10 * time.Minute)}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{"zero is latest", time.Time{}, times[2]},
+		{"exact match", times[1], times[1]},
+		{"between rounds down", base.Add(7 * time.Minute), times[1]},
+		{"after latest", base.Add(time.Hour), times[2]},
+		{"before earliest", base.Add(-time.Hour), times[0]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := clampRadarTime(times, tt.at)
+			if !ok || !got.Equal(tt.want) {
+				t.Errorf("got %v, %v; want %v", got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := clampRadarTime(nil, base); ok {
+		t.Error("expected no time without available times")
+	}
+}
+
+func TestGetRadarImage_ClampsAndCaches(t *testing.T) {
+	base := time.Date(2026, 1, 15, 6, 0, 0, 0, time.UTC)
+	src := &stubRadarSource{times: []time.Time{base.Add(5 * time.Minute), base}}
+	s := NewService(emptyStore{}, stubForecastFetcher{}, DefaultFreshness())
+
+	req := RadarRequest{BBox: BBox{MinLon: 24, MinLat: 60, MaxLon: 25, MaxLat: 61}, Width: 256, Height: 256, Time: base.Add(3 * time.Minute)}
+	if _, err := s.GetRadarImage(context.Background(), req); !errors.Is(err, ErrRadarUnavailable) {
+		t.Fatalf("expected ErrRadarUnavailable without a source, got %v", err)
+	}
+
+	s.SetRadarSource(src)
+	img, err := s.GetRadarImage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetRadarImage: %v", err)
+	}
+	if !img.Time.Equal(base) {
+		t.Errorf("expected the image time clamped to %v, got %v", base, img.Time)
+	}
+
+	// Any time that clamps to the same composite is served from cache.
+	req.Time = base.Add(4 * time.Minute)
+	if _, err := s.GetRadarImage(context.Background(), req); err != nil {
+		t.Fatalf("GetRadarImage: %v", err)
+	}
+	if len(src.fetched) != 1 {
+		t.Fatalf("expected one upstream fetch, got %d", len(src.fetched))
+	}
+
+	req.Width = 512
+	img, err = s.GetRadarImage(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetRadarImage: %v", err)
+	}
+	if len(src.fetched) != 2 {
+		t.Fatalf("expected a new fetch for a different size, got %d", len(src.fetched))
+	}
+	if img.BBox != req.BBox {
+		t.Errorf("expected the whole-degree bbox kept, got %+v", img.BBox)
+	}
+}
+
+func TestSnapRadarRequest(t *testing.T) {
+	got := snapRadarRequest(RadarRequest{
+		BBox:   BBox{MinLon: 24.93, MinLat: 60.16, MaxLon: 25.41, MaxLat: 60.35},
+		Width:  300,
+		Height: 64,
+	})
+	want := BBox{MinLon: 24.5, MinLat: 60, MaxLon: 25.5, MaxLat: 60.5}
+	if got.BBox != want {
+		t.Errorf("bbox = %+v, want %+v", got.BBox, want)
+	}
+	if got.Width != 512 || got.Height != 64 {
+		t.Errorf("size = %dx%d, want 512x64", got.Width, got.Height)
+	}
+
+	// Panning within the same tiles reuses the snapped request.
+	panned := snapRadarRequest(RadarRequest{BBox: BBox{MinLon: 24.9, MinLat: 60.1, MaxLon: 25.4, MaxLat: 60.3}, Width: 400, Height: 50})
+	if panned != got {
+		t.Errorf("panned request %+v, want %+v", panned, got)
+	}
+}
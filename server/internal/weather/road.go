@@ -0,0 +1,55 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RoadObservation is one measurement from a road weather station.
+// Condition is the station's road surface state code, e.g. 1 dry, 3 wet,
+// 5 frost, 6 snow, 7 ice.
+type RoadObservation struct {
+	FMISID          int
+	ObservedAt      time.Time
+	RoadTemperature *float64
+	AirTemperature  *float64
+	Condition       *int
+}
+
+// RoadReading is the latest road weather near a location.
+type RoadReading struct {
+	Station    Station
+	DistanceKM float64
+	RoadObservation
+}
+
+const (
+	// roadMaxDistanceKM bounds how far the nearest road station may be;
+	// beyond it the road surface says little about the requested point.
+	roadMaxDistanceKM = 20
+	// roadMaxAge bounds how old the latest road observation may be. Road
+	// stations report every few minutes, so anything older is a dead
+	// sensor.
+	roadMaxAge = time.Hour
+)
+
+// GetRoadWeather returns the latest observation of the nearest road weather
+// station, or nil when there is none close enough or recent enough.
+func (s *Service) GetRoadWeather(ctx context.Context, lat, lon float64) (*RoadReading, error) {
+	station, distKM, err := s.store.NearestRoadStation(ctx, lat, lon)
+	if err != nil {
+		return nil, fmt.Errorf("nearest road station: %w", err)
+	}
+	if station == nil || distKM > roadMaxDistanceKM {
+		return nil, nil
+	}
+	obs, err := s.store.LatestRoadObservation(ctx, station.FMISID)
+	if err != nil {
+		return nil, fmt.Errorf("latest road observation: %w", err)
+	}
+	if obs == nil || time.Since(obs.ObservedAt) > roadMaxAge {
+		return nil, nil
+	}
+	return &RoadReading{Station: *station, DistanceKM: distKM, RoadObservation: *obs}, nil
+}
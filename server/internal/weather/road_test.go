@@ -0,0 +1,55 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type roadStore struct {
+	emptyStore
+	distKM float64
+	obs    *RoadObservation
+	err    error
+}
+
+func (s roadStore) NearestRoadStation(ctx context.Context, lat, lon float64) (*Station, float64, error) {
+	if s.err != nil {
+		return nil, 0, s.err
+	}
+	return &Station{FMISID: 100011, Name: "vt1 Espoo Nupuri"}, s.distKM, nil
+}
+
+func (s roadStore) LatestRoadObservation(ctx context.Context, fmisid int) (*RoadObservation, error) {
+	return s.obs, nil
+}
+
+func TestGetRoadWeather(t *testing.T) {
+	obs := &RoadObservation{FMISID: 100011, ObservedAt: time.Now().Add(-5 * time.Minute), RoadTemperature: ptr(-6.1)}
+
+	svc := NewService(roadStore{distKM: 0.4, obs: obs}, nil, DefaultFreshness())
+	got, err := svc.GetRoadWeather(context.Background(), 60.24, 24.62)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Station.FMISID != 100011 || *got.RoadTemperature != -6.1 {
+		t.Fatalf("unexpected road reading %+v", got)
+	}
+
+	svc = NewService(roadStore{distKM: 35, obs: obs}, nil, DefaultFreshness())
+	if got, err := svc.GetRoadWeather(context.Background(), 61.0, 26.0); err != nil || got != nil {
+		t.Fatalf("expected no reading for a distant road station, got %+v, %v", got, err)
+	}
+
+	stale := &RoadObservation{FMISID: 100011, ObservedAt: time.Now().Add(-2 * time.Hour), RoadTemperature: ptr(-6.1)}
+	svc = NewService(roadStore{distKM: 0.4, obs: stale}, nil, DefaultFreshness())
+	if got, err := svc.GetRoadWeather(context.Background(), 60.24, 24.62); err != nil || got != nil {
+		t.Fatalf("expected no reading for a stale observation, got %+v, %v", got, err)
+	}
+
+	svc = NewService(roadStore{err: errors.New("db down")}, nil, DefaultFreshness())
+	if _, err := svc.GetRoadWeather(context.Background(), 60.24, 24.62); err == nil {
+		t.Fatal("expected the store error to be returned")
+	}
+}
@@ -0,0 +1,66 @@
+package weather
+
+// Stored forecast rows carry the schema version of the build that wrote
+// them. When a change adds fields to a forecast payload, bump the version
+// and append an upgrade step describing how older rows are brought up to
+// date on read, instead of sniffing for missing fields.
+const (
+	DailyForecastSchemaVersion  = 5
+	HourlyForecastSchemaVersion = 3
+)
+
+// dailyForecastUpgrades[v] upgrades a row from version v to v+1 in place and
+// reports false when the row lacks data that can only be refetched.
+var dailyForecastUpgrades = []func(*DailyForecast) bool{
+	// Version 0 rows predate stamping. Those written before the expanded
+	// parameter set have no TempAvg, which every day with temperature data
+	// now carries.
+	0: func(f *DailyForecast) bool { return f.TempAvg != nil },
+	// Version 1 rows bucket hours by UTC date rather than the local
+	// calendar day, so every aggregate may be off and only a refetch helps.
+	1: func(*DailyForecast) bool { return false },
+	// Version 2 rows have no day/night temperature split.
+	2: func(*DailyForecast) bool { return false },
+	// Version 3 rows don't say how many hours their precipitation sum
+	// covers, so a partial day would pass for a dry one.
+	3: func(*DailyForecast) bool { return false },
+	// Version 4 rows have no snow accumulation.
+	4: func(*DailyForecast) bool { return false },
+}
+
+var hourlyForecastUpgrades = []func(*HourlyForecast) bool{
+	// Version 0 rows predate stamping; later hourly fields were optional
+	// additions, so they are served as is.
+	0: func(*HourlyForecast) bool { return true },
+	// Version 1 rows have no PoP. Hourly rows are short-lived, so a refetch
+	// is cheap and beats serving hours without it.
+	1: func(*HourlyForecast) bool { return false },
+	// Version 2 rows have no gust, pressure or dew point.
+	2: func(*HourlyForecast) bool { return false },
+}
+
+func upgradeDailyForecasts(forecasts []DailyForecast) ([]DailyForecast, bool) {
+	return upgradeRows(forecasts, func(f *DailyForecast) *int { return &f.SchemaVersion }, DailyForecastSchemaVersion, dailyForecastUpgrades)
+}
+
+func upgradeHourlyForecasts(hourly []HourlyForecast) ([]HourlyForecast, bool) {
+	return upgradeRows(hourly, func(h *HourlyForecast) *int { return &h.SchemaVersion }, HourlyForecastSchemaVersion, hourlyForecastUpgrades)
+}
+
+// upgradeRows returns copies of rows upgraded to current, or false if any
+// row cannot be upgraded. Rows from a newer build, e.g. during a rolling
+// deploy, are served unchanged since versions only add fields.
+func upgradeRows[T any](rows []T, version func(*T) *int, current int, steps []func(*T) bool) ([]T, bool) {
+	upgraded := make([]T, len(rows))
+	copy(upgraded, rows)
+	for i := range upgraded {
+		v := version(&upgraded[i])
+		for *v < current {
+			if !steps[*v](&upgraded[i]) {
+				return nil, false
+			}
+			*v++
+		}
+	}
+	return upgraded, true
+}
@@ -0,0 +1,41 @@
+package weather
+
+import "testing"
+
+func TestUpgradeDailyForecasts(t *testing.T) {
+	current := []DailyForecast{{SchemaVersion: DailyForecastSchemaVersion, TempAvg: ptr(1)}}
+	if got, ok := upgradeDailyForecasts(current); !ok || got[0].SchemaVersion != DailyForecastSchemaVersion {
+		t.Fatalf("expected current rows to be served, got %+v ok=%v", got, ok)
+	}
+
+	if _, ok := upgradeDailyForecasts([]DailyForecast{{TempAvg: ptr(1)}, {}}); ok {
+		t.Error("expected pre-expansion legacy rows to require a refetch")
+	}
+	// Version 0 and 1 rows were bucketed by UTC date.
+	for v := range DailyForecastSchemaVersion {
+		rows := []DailyForecast{{SchemaVersion: v, TempAvg: ptr(1)}}
+		if _, ok := upgradeDailyForecasts(rows); ok {
+			t.Errorf("expected version %d rows to require a refetch", v)
+		}
+		if rows[0].SchemaVersion != v {
+			t.Error("input rows were modified")
+		}
+	}
+
+	newer := []DailyForecast{{SchemaVersion: DailyForecastSchemaVersion + 1}}
+	if got, ok := upgradeDailyForecasts(newer); !ok || got[0].SchemaVersion != DailyForecastSchemaVersion+1 {
+		t.Error("expected rows from a newer build to be served unchanged")
+	}
+}
+
+func TestUpgradeHourlyForecasts(t *testing.T) {
+	for v := range HourlyForecastSchemaVersion {
+		if _, ok := upgradeHourlyForecasts([]HourlyForecast{{SchemaVersion: v, Temperature: ptr(3)}}); ok {
+			t.Errorf("expected version %d rows without PoP to require a refetch", v)
+		}
+	}
+	current := []HourlyForecast{{SchemaVersion: HourlyForecastSchemaVersion, Temperature: ptr(3)}}
+	if upgraded, ok := upgradeHourlyForecasts(current); !ok || upgraded[0].SchemaVersion != HourlyForecastSchemaVersion {
+		t.Fatalf("got %+v ok=%v", upgraded, ok)
+	}
+}
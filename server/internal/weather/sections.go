@@ -0,0 +1,31 @@
+package weather
+
+// Section is a part of a GetWeather response that callers can skip, so a
+// widget that shows only current conditions costs no forecast fetches.
+// Sections combine as bit flags.
+type Section uint
+
+const (
+	SectionCurrent Section = 1 << iota
+	SectionHourly
+	SectionDaily
+	SectionAlerts
+	SectionAirQuality
+	SectionMarine
+)
+
+// Includes reports whether s selects x. The zero Section selects
+// everything, as GetWeather did before sections existed.
+func (s Section) Includes(x Section) bool {
+	return s == 0 || s&x != 0
+}
+
+// WeatherOptions tunes GetWeather. The zero value loads every section with
+// the default number of hourly and daily entries.
+type WeatherOptions struct {
+	// Hours and Days cap the hourly and daily entries; 0 means the default.
+	Hours, Days int
+	// Sections selects what to load. Skipping SectionDaily also skips the
+	// UV enrichment, which needs the daily forecast's timezone.
+	Sections Section
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 	"math"
 	"strings"
+	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
+	"wby/internal/logging"
+	"wby/internal/metrics"
 )
 
 // Finland coverage bbox — must match the WFS observation query bounds in
@@ -21,9 +25,54 @@ const (
 
 var ErrOutOfCoverage = errors.New("location outside coverage area")
 
+var ErrStationNotFound = errors.New("station not found")
+
+// ErrNoStations is returned by stores that hold no stations yet, as on a
+// fresh deployment before the fetcher's first run.
+var ErrNoStations = errors.New("no station data available yet")
+
+// ErrNoClimateNormals is returned by stores that hold no climate normals
+// for the requested period, as before cmd/import-normals has run.
+var ErrNoClimateNormals = errors.New("no climate normals imported")
+
+// ErrUpstreamUnavailable wraps FMI failures that leave the service with
+// nothing stored to fall back on. It also matches FMI errors for an outage:
+// a 5xx, a timeout or a refused connection. A wrapped failure may match
+// ErrUpstreamRateLimited or ErrUpstreamMalformed as well, which say why.
+var ErrUpstreamUnavailable = errors.New("upstream weather service unavailable")
+
+// ErrUpstreamRejected matches FMI errors that reject the query itself,
+// such as an unknown stored query, which point at a bug here rather than
+// an outage.
+var ErrUpstreamRejected = errors.New("upstream weather service rejected the query")
+
+const (
+	// DefaultHourlyForecastHours is served when a request does not ask for a
+	// specific number of hourly entries.
+	DefaultHourlyForecastHours = 12
+	// DefaultMaxHourlyForecastHours caps requested hourly entries unless
+	// overridden with SetMaxHourlyForecastHours.
+	DefaultMaxHourlyForecastHours = 72
+
+	// DefaultForecastDays is served when a request does not ask for a
+	// specific number of days; it is also the narrowest window fetched from
+	// FMI so smaller requests can be sliced from it.
+	DefaultForecastDays = 10
+	MaxForecastDays     = 15
+)
+
+// MaxObservationRange caps how much observation history one request can read.
+const MaxObservationRange = 7 * 24 * time.Hour
+
 type WeatherStore interface {
 	NearestStation(ctx context.Context, lat, lon float64) (Station, float64, error)
+	ListStations(ctx context.Context, bbox *BBox) ([]Station, error)
+	NearestStations(ctx context.Context, lat, lon float64, n int) ([]NearbyStation, error)
+	SearchPlaces(ctx context.Context, q string, limit int) ([]Place, error)
+	GetStation(ctx context.Context, fmisid int) (*Station, error)
+	ObservationsRange(ctx context.Context, fmisid int, from, to time.Time) ([]Observation, error)
 	LatestObservation(ctx context.Context, fmisid int) (Observation, error)
+	LatestObservationsNear(ctx context.Context, lat, lon float64, n int, since time.Time) ([]StationObservation, error)
 	GetLatestTemperatureSamplesInBBox(ctx context.Context, minLon, minLat, maxLon, maxLat float64, limit int) ([]TemperatureSample, error)
 	GetForecasts(ctx context.Context, gridLat, gridLon float64) ([]DailyForecast, error)
 	UpsertForecasts(ctx context.Context, forecasts []DailyForecast) error
@@ -33,37 +82,152 @@ type WeatherStore interface {
 	GetClimateNormals(ctx context.Context, fmisid int, period string) ([]ClimateNormal, error)
 	NearestStationWithClimateNormals(ctx context.Context, lat, lon float64, period string) (Station, float64, error)
 	GetLeaderboard(ctx context.Context, lat, lon float64, timeframe string) ([]LeaderboardEntry, error)
+	UpsertCustomObservation(ctx context.Context, obs CustomObservation) error
+	RegisterCustomStation(ctx context.Context, st CustomStation, tokenHash []byte) error
+	CustomStationByToken(ctx context.Context, tokenHash []byte) (*CustomStation, error)
+	NearestCustomObservation(ctx context.Context, clientID string, lat, lon float64, maxAge time.Duration) (*CustomObservation, float64, error)
+	CreateSubscription(ctx context.Context, sub ForecastSubscription) (ForecastSubscription, error)
+	DeleteSubscription(ctx context.Context, clientID string, id int64) (bool, error)
+	ListSubscriptions(ctx context.Context) ([]ForecastSubscription, error)
+	UpdateSubscriptionSnapshot(ctx context.Context, id int64, snapshot []DailyForecast) error
+	WarningsForPoint(ctx context.Context, lat, lon float64, at time.Time) ([]Warning, error)
+	LightningNear(ctx context.Context, lat, lon, radiusKM float64, since time.Time) ([]LightningStrike, error)
+	NearestAirQualityStation(ctx context.Context, lat, lon float64) (*Station, float64, error)
+	LatestAirQuality(ctx context.Context, fmisid int) (*AirQuality, error)
+	NearestMarineStation(ctx context.Context, lat, lon float64) (*Station, float64, error)
+	LatestMarineObservation(ctx context.Context, fmisid int) (*MarineObservation, error)
+	ObservationStats(ctx context.Context, fmisid int, period string, loc *time.Location, from, to time.Time) ([]ObservationStats, error)
+	NearestRoadStation(ctx context.Context, lat, lon float64) (*Station, float64, error)
+	LatestRoadObservation(ctx context.Context, fmisid int) (*RoadObservation, error)
 }
 
 type ForecastFetcher interface {
-	FetchForecast(ctx context.Context, lat, lon float64) (ForecastData, error)
+	FetchForecast(ctx context.Context, lat, lon float64, days int) (ForecastData, error)
 	FetchHourlyForecast(ctx context.Context, lat, lon float64, limit int) ([]HourlyForecast, error)
 	FetchUVForecast(ctx context.Context, lat, lon float64) ([]UVDataPoint, error)
 }
 
+// cachedForecast remembers how many days were requested from FMI, which
+// can exceed len(forecasts) when the requested range goes past the end of
+// FMI's forecast.
+type cachedForecast struct {
+	forecasts []DailyForecast
+	days      int
+}
+
 type Service struct {
-	store            WeatherStore
-	fmi              ForecastFetcher
-	forecastCache    *Cache[[]DailyForecast]
-	timezoneCache    *Cache[string]
-	hourlyCache      *Cache[[]HourlyForecast]
-	uvCache          *Cache[[]UVDataPoint]
-	leaderboardCache *Cache[[]LeaderboardEntry]
+	store               WeatherStore
+	fmi                 ForecastFetcher
+	longRange           LongRangeForecaster
+	forecastBatch       ForecastBatchFetcher
+	freshness           Freshness
+	forecastCache       *Cache[cachedForecast]
+	timezoneCache       *Cache[string]
+	hourlyCache         *Cache[[]HourlyForecast]
+	uvCache             *Cache[[]UVDataPoint]
+	uvLastKnown         *Cache[[]UVDataPoint]
+	uvFailures          *Cache[error]
+	leaderboardCache    *Cache[[]LeaderboardEntry]
+	normalsCache        *Cache[[]ClimateNormal]
+	homeSensors         HomeSensorProvider
+	homeSensorCache     *Cache[[]HomeSensorReading]
+	homeSensorFailures  *Cache[error]
+	biasCorrector       BiasCorrector
+	maxHourlyHours      int
+	hourlyWatchers      *watchers
+	radar               RadarSource
+	radarCache          *Cache[*RadarImage]
+	radarTimesCache     *Cache[[]time.Time]
+	marineMaxDistanceKM float64
+	fetches             singleflight.Group
+	fmiBackoff          *rateLimitBackoff
+
+	environmentMu        sync.RWMutex
+	environmentProviders map[string]EnvironmentProvider
+	environmentCache     *Cache[EnvironmentSection]
+	environmentLastKnown *Cache[EnvironmentSection]
 }
 
-func NewService(store WeatherStore, fmiClient ForecastFetcher, forecastCacheTTL time.Duration) *Service {
-	return &Service{
-		store:            store,
-		fmi:              fmiClient,
-		forecastCache:    NewCache[[]DailyForecast](forecastCacheTTL),
-		timezoneCache:    NewCache[string](forecastCacheTTL),
-		hourlyCache:      NewCache[[]HourlyForecast](forecastCacheTTL),
-		uvCache:          NewCache[[]UVDataPoint](forecastCacheTTL),
-		leaderboardCache: NewCache[[]LeaderboardEntry](5 * time.Minute),
+func NewService(store WeatherStore, fmiClient ForecastFetcher, freshness Freshness) *Service {
+	s := &Service{
+		store:               store,
+		fmi:                 fmiClient,
+		freshness:           freshness,
+		forecastCache:       NewCache[cachedForecast](freshness.DailyForecast.CacheTTL),
+		timezoneCache:       NewCache[string](freshness.DailyForecast.CacheTTL),
+		hourlyCache:         NewCache[[]HourlyForecast](freshness.HourlyForecast.CacheTTL),
+		uvCache:             NewCache[[]UVDataPoint](freshness.UV.CacheTTL),
+		uvLastKnown:         NewCache[[]UVDataPoint](freshness.UV.MaxAge),
+		uvFailures:          NewCache[error](uvFailureTTL),
+		leaderboardCache:    NewCache[[]LeaderboardEntry](freshness.Leaderboard.CacheTTL),
+		normalsCache:        NewCache[[]ClimateNormal](normalsCacheTTL),
+		homeSensorCache:     NewCache[[]HomeSensorReading](freshness.HomeSensors.CacheTTL),
+		homeSensorFailures:  NewCache[error](homeSensorFailureTTL),
+		maxHourlyHours:      DefaultMaxHourlyForecastHours,
+		hourlyWatchers:      newWatchers(),
+		radarCache:          NewBoundedCache[*RadarImage](freshness.Radar.CacheTTL, radarCacheEntries),
+		radarTimesCache:     NewCache[[]time.Time](radarTimesTTL),
+		marineMaxDistanceKM: DefaultMarineMaxDistanceKM,
+		fmiBackoff:          newRateLimitBackoff(),
+
+		environmentProviders: map[string]EnvironmentProvider{},
+		environmentCache:     NewCache[EnvironmentSection](freshness.Environment.CacheTTL),
+		environmentLastKnown: NewCache[EnvironmentSection](freshness.Environment.MaxAge),
+	}
+	s.environmentProviders[EnvironmentUVMax] = environmentProviderFunc(s.uvMaxProvider)
+	return s
+}
+
+// SetMetrics counts hits and misses of the forecast, hourly forecast and UV
+// caches in reg.
+func (s *Service) SetMetrics(reg *metrics.Registry) {
+	lookups := reg.Counter("wby_cache_lookups_total", "Service cache lookups by result.", "cache", "result")
+	s.forecastCache.instrument("forecast", lookups)
+	s.hourlyCache.instrument("hourly_forecast", lookups)
+	s.uvCache.instrument("uv", lookups)
+}
+
+// Freshness returns the freshness windows the service was configured with.
+func (s *Service) Freshness() Freshness {
+	return s.freshness
+}
+
+// InvalidateForecastCaches drops the cached daily, hourly and UV forecasts so
+// the next request for each location refetches them, and returns how many
+// entries were dropped.
+func (s *Service) InvalidateForecastCaches() int {
+	return s.forecastCache.Clear() + s.hourlyCache.Clear() + s.uvCache.Clear() + s.uvFailures.Clear()
+}
+
+// SetMaxHourlyForecastHours sets the cap on requested hourly entries.
+func (s *Service) SetMaxHourlyForecastHours(n int) {
+	if n > 0 {
+		s.maxHourlyHours = n
+	}
+}
+
+// hourlyLimit resolves a requested number of hourly entries: zero or
+// negative means the default, anything above the cap is clamped.
+func (s *Service) hourlyLimit(hours int) int {
+	if hours <= 0 {
+		hours = DefaultHourlyForecastHours
 	}
+	return min(hours, s.maxHourlyHours)
 }
 
-func (s *Service) GetWeather(ctx context.Context, lat, lon float64) (*WeatherResponse, error) {
+// forecastDays resolves a requested number of daily entries: zero or
+// negative means the default, anything above MaxForecastDays is clamped.
+func forecastDays(days int) int {
+	if days < 1 {
+		return DefaultForecastDays
+	}
+	return min(days, MaxForecastDays)
+}
+
+// GetWeather returns current conditions and the forecast for a location,
+// loading only the sections opts selects. The nearest station is always
+// looked up, since it also picks the forecast bias corrections.
+func (s *Service) GetWeather(ctx context.Context, lat, lon float64, opts WeatherOptions) (*WeatherResponse, error) {
 	if lon < finlandMinLon || lon > finlandMaxLon || lat < finlandMinLat || lat > finlandMaxLat {
 		return nil, ErrOutOfCoverage
 	}
@@ -73,32 +237,84 @@ func (s *Service) GetWeather(ctx context.Context, lat, lon float64) (*WeatherRes
 		return nil, fmt.Errorf("nearest station: %w", err)
 	}
 
-	obs, err := s.store.LatestObservation(ctx, station.FMISID)
+	var obs Observation
+	if opts.Sections.Includes(SectionCurrent) {
+		if obs, err = s.store.LatestObservation(ctx, station.FMISID); err != nil {
+			return nil, fmt.Errorf("latest observation: %w", err)
+		}
+	}
+
+	hourly, forecast, forecastTimezone, provenance, err := s.loadForecasts(ctx, lat, lon, s.hourlyLimit(opts.Hours), forecastDays(opts.Days), opts.Sections)
 	if err != nil {
-		return nil, fmt.Errorf("latest observation: %w", err)
+		return nil, err
 	}
+	servedHourly, servedForecast := s.applyBiasCorrection(station.FMISID, hourly, forecast)
 
-	gridLat, gridLon := snapToGrid(lat, lon)
-	forecast, forecastTimezone, err := s.getForecast(ctx, gridLat, gridLon)
+	var anomaly *float64
+	if opts.Sections.Includes(SectionCurrent) || len(servedForecast) > 0 {
+		normals := s.nearestNormals(ctx, lat, lon)
+		anomaly = InterpolateNormals(normals, obs.ObservedAt.UTC(), obs.Temperature).TempDiff
+		servedForecast = withNormals(servedForecast, normals)
+	}
+
+	resp := &WeatherResponse{
+		Current: CurrentWeather{
+			Station:     station,
+			DistanceKM:  distKM,
+			Observation: obs,
+			TempAnomaly: anomaly,
+		},
+		Hourly:          servedHourly,
+		Forecast:        servedForecast,
+		Timezone:        forecastTimezone,
+		SynopticSummary: DescribePressureSituation(forecast),
+		Provenance:      provenance,
+	}
+	now := time.Now()
+	if opts.Sections.Includes(SectionCurrent) {
+		resp.FogAdvisory = BuildFogAdvisory(obs, hourly)
+	}
+	if opts.Sections.Includes(SectionAlerts) {
+		resp.Warnings = s.activeWarnings(ctx, lat, lon, now)
+	}
+	if opts.Sections.Includes(SectionAirQuality) {
+		resp.AirQuality = s.nearestAirQuality(ctx, lat, lon, now)
+	}
+	if opts.Sections.Includes(SectionMarine) {
+		resp.Marine = s.nearestMarine(ctx, lat, lon, now)
+	}
+	return resp, nil
+}
+
+// CompactHourlyForecastHours is how many hourly entries GetCompactWeather
+// returns.
+const CompactHourlyForecastHours = 6
+
+// GetCompactWeather returns current conditions and the next
+// CompactHourlyForecastHours hourly forecasts. Unlike GetWeather it never
+// loads the daily forecast, UV data or supplementary sections, so at most
+// the hourly forecast is fetched from FMI. It reads the default hourly
+// series, which /v1/weather requests share.
+func (s *Service) GetCompactWeather(ctx context.Context, lat, lon float64) (*WeatherResponse, error) {
+	if lon < finlandMinLon || lon > finlandMaxLon || lat < finlandMinLat || lat > finlandMaxLat {
+		return nil, ErrOutOfCoverage
+	}
+
+	station, distKM, err := s.store.NearestStation(ctx, lat, lon)
 	if err != nil {
-		return nil, fmt.Errorf("forecast: %w", err)
+		return nil, fmt.Errorf("nearest station: %w", err)
 	}
-	hourly, err := s.getHourlyForecast(ctx, gridLat, gridLon, 12)
+	obs, err := s.store.LatestObservation(ctx, station.FMISID)
 	if err != nil {
-		slog.Warn("hourly forecast unavailable", "err", err, "lat", gridLat, "lon", gridLon)
+		return nil, fmt.Errorf("latest observation: %w", err)
 	}
 
-	uvPoints := s.getUVData(ctx, gridLat, gridLon)
-	if len(uvPoints) > 0 {
-		applyUVToHourly(uvPoints, hourly)
-		applyUVToDaily(uvPoints, forecast)
-		if err := s.store.UpsertHourlyForecasts(ctx, gridLat, gridLon, hourly); err != nil {
-			slog.Warn("failed to persist UV-enriched hourly forecasts", "err", err)
-		}
-		if err := s.store.UpsertForecasts(ctx, forecast); err != nil {
-			slog.Warn("failed to persist UV-enriched daily forecasts", "err", err)
-		}
+	gridLat, gridLon := snapToGrid(lat, lon)
+	hourly, hourlySource, err := s.getHourlyForecast(ctx, gridLat, gridLon, s.hourlyLimit(0))
+	if err != nil {
+		logging.FromContext(ctx).Warn("hourly forecast unavailable", "err", err, "lat", gridLat, "lon", gridLon)
 	}
+	hourly, _ = s.applyBiasCorrection(station.FMISID, hourly, nil)
 
 	return &WeatherResponse{
 		Current: CurrentWeather{
@@ -106,12 +322,157 @@ func (s *Service) GetWeather(ctx context.Context, lat, lon float64) (*WeatherRes
 			DistanceKM:  distKM,
 			Observation: obs,
 		},
-		Hourly:   hourly,
-		Forecast: forecast,
-		Timezone: forecastTimezone,
+		Hourly: hourly[:min(len(hourly), CompactHourlyForecastHours)],
+		Provenance: Provenance{
+			GridLat: gridLat,
+			GridLon: gridLon,
+			Hourly:  hourlySource,
+		},
 	}, nil
 }
 
+// activeWarnings returns the warnings covering the point that have not
+// expired at now. Warnings are supplementary, so a store failure is logged
+// and the response is served without them.
+func (s *Service) activeWarnings(ctx context.Context, lat, lon float64, now time.Time) []Warning {
+	warnings, err := s.warningsAt(ctx, lat, lon, now)
+	if err != nil {
+		logging.FromContext(ctx).Warn("failed to load warnings", "err", err, "lat", lat, "lon", lon)
+		return nil
+	}
+	return warnings
+}
+
+// warningsAt returns the warnings covering the point that have not expired
+// at now.
+func (s *Service) warningsAt(ctx context.Context, lat, lon float64, now time.Time) ([]Warning, error) {
+	warnings, err := s.store.WarningsForPoint(ctx, lat, lon, now)
+	if err != nil {
+		return nil, err
+	}
+	// Expired warnings must never be served, whatever the store returns.
+	active := warnings[:0]
+	for _, w := range warnings {
+		if w.Expires.After(now) {
+			active = append(active, w)
+		}
+	}
+	return active, nil
+}
+
+// ListStations returns known stations, optionally limited to bbox.
+func (s *Service) ListStations(ctx context.Context, bbox *BBox) ([]Station, error) {
+	stations, err := s.store.ListStations(ctx, bbox)
+	if err != nil {
+		return nil, fmt.Errorf("list stations: %w", err)
+	}
+	return stations, nil
+}
+
+// MaxNearbyStations caps how many stations GetNearbyStations returns.
+const MaxNearbyStations = 20
+
+// GetNearbyStations returns up to n stations closest to the point, nearest
+// first. n is clamped to [1, MaxNearbyStations].
+func (s *Service) GetNearbyStations(ctx context.Context, lat, lon float64, n int) ([]NearbyStation, error) {
+	n = max(1, min(n, MaxNearbyStations))
+	stations, err := s.store.NearestStations(ctx, lat, lon, n)
+	if err != nil {
+		return nil, fmt.Errorf("nearby stations: %w", err)
+	}
+	return stations, nil
+}
+
+// GetStationObservations returns a station's observations between from and
+// to, oldest first.
+func (s *Service) GetStationObservations(ctx context.Context, fmisid int, from, to time.Time) (*Station, []Observation, error) {
+	station, err := s.store.GetStation(ctx, fmisid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if station == nil {
+		return nil, nil, ErrStationNotFound
+	}
+	observations, err := s.store.ObservationsRange(ctx, fmisid, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return station, observations, nil
+}
+
+// GetForecast returns the daily and hourly forecast for a location without
+// touching observations, so it works where no station data is available.
+func (s *Service) GetForecast(ctx context.Context, lat, lon float64, hours, days int) (*ForecastResponse, error) {
+	if lon < finlandMinLon || lon > finlandMaxLon || lat < finlandMinLat || lat > finlandMaxLat {
+		return nil, ErrOutOfCoverage
+	}
+
+	hourly, forecast, timezone, _, err := s.loadForecasts(ctx, lat, lon, s.hourlyLimit(hours), forecastDays(days), SectionHourly|SectionDaily)
+	if err != nil {
+		return nil, err
+	}
+	// Bias tables are per station; without one the raw forecast is served.
+	if station, _, err := s.store.NearestStation(ctx, lat, lon); err == nil {
+		hourly, forecast = s.applyBiasCorrection(station.FMISID, hourly, forecast)
+	}
+
+	return &ForecastResponse{
+		Hourly:          hourly,
+		Forecast:        forecast,
+		Timezone:        timezone,
+		SynopticSummary: DescribePressureSituation(forecast),
+	}, nil
+}
+
+// loadForecasts returns the UV-enriched hourly and daily forecasts for the
+// grid cell containing lat/lon and where each was loaded from, loading only
+// those sections selects. Hourly data is best effort.
+func (s *Service) loadForecasts(ctx context.Context, lat, lon float64, hours, days int, sections Section) ([]HourlyForecast, []DailyForecast, string, Provenance, error) {
+	gridLat, gridLon := snapToGrid(lat, lon)
+	provenance := Provenance{GridLat: gridLat, GridLon: gridLon, Forecast: SourceSkipped, Hourly: SourceSkipped, UV: SourceSkipped}
+
+	var (
+		forecast []DailyForecast
+		timezone string
+		err      error
+	)
+	if sections.Includes(SectionDaily) {
+		forecast, timezone, provenance.Forecast, err = s.getForecast(ctx, gridLat, gridLon, days)
+		if err != nil {
+			return nil, nil, "", Provenance{}, fmt.Errorf("forecast: %w", err)
+		}
+	} else {
+		timezone = s.cachedTimezoneForKey(fmt.Sprintf("%.2f,%.2f", gridLat, gridLon))
+	}
+
+	var hourly []HourlyForecast
+	if sections.Includes(SectionHourly) {
+		hourly, provenance.Hourly, err = s.getHourlyForecast(ctx, gridLat, gridLon, hours)
+		if err != nil {
+			logging.FromContext(ctx).Warn("hourly forecast unavailable", "err", err, "lat", gridLat, "lon", gridLon)
+		}
+	}
+	if !sections.Includes(SectionDaily) {
+		return hourly, nil, timezone, provenance, nil
+	}
+
+	uvPoints, source := s.getUVData(ctx, gridLat, gridLon)
+	provenance.UV = source
+	if len(uvPoints) > 0 {
+		applyUVToHourly(uvPoints, hourly)
+		applyUVToDaily(uvPoints, forecast, PlaceLocation(timezone))
+		if len(hourly) > 0 {
+			if err := s.store.UpsertHourlyForecasts(ctx, gridLat, gridLon, hourly); err != nil {
+				logging.FromContext(ctx).Warn("failed to persist UV-enriched hourly forecasts", "err", err)
+			}
+		}
+		if err := s.store.UpsertForecasts(ctx, forecast); err != nil {
+			logging.FromContext(ctx).Warn("failed to persist UV-enriched daily forecasts", "err", err)
+		}
+	}
+	return hourly, withMoonPhases(withSunTimes(lat, lon, forecast)), timezone, provenance, nil
+}
+
 func (s *Service) GetTemperatureSamples(ctx context.Context) (*TemperatureSamplesResponse, error) {
 	const margin = 0.2
 	samples, err := s.store.GetLatestTemperatureSamplesInBBox(
@@ -172,35 +533,99 @@ func (s *Service) GetTemperatureOverlay(ctx context.Context, req MapOverlayReque
 	return overlay, nil
 }
 
-func (s *Service) getForecast(ctx context.Context, gridLat, gridLon float64) ([]DailyForecast, string, error) {
+// getForecast returns up to days daily forecasts, slicing cached or stored
+// data when it covers the request and fetching a wider window otherwise.
+func (s *Service) getForecast(ctx context.Context, gridLat, gridLon float64, days int) ([]DailyForecast, string, Source, error) {
 	cacheKey := fmt.Sprintf("%.2f,%.2f", gridLat, gridLon)
 
-	if cached, ok := s.forecastCache.Get(cacheKey); ok {
-		if hasExpandedForecastData(cached) {
-			return cached, s.cachedTimezoneForKey(cacheKey), nil
+	forecasts, persisted, source, ok := s.lookupForecast(ctx, cacheKey, gridLat, gridLon, days)
+	if ok {
+		return forecasts, s.cachedTimezoneForKey(cacheKey), source, nil
+	}
+
+	window := max(days, DefaultForecastDays)
+	fetched, err := sharedFetch(ctx, &s.fetches, fmt.Sprintf("forecast:%s:%d", cacheKey, window), func(ctx context.Context) (fetchedForecast, error) {
+		return s.fetchForecast(ctx, cacheKey, gridLat, gridLon, window)
+	})
+	if errors.Is(err, ErrUpstreamRejected) {
+		return nil, "", SourceUnavailable, fmt.Errorf("fetch forecast: %w", err)
+	}
+	if err != nil {
+		// Stale days beat none while FMI is down, including while the
+		// client's circuit breaker is failing calls fast, while backing
+		// off a rate limit and when FMI sends something unparseable. They
+		// are not cached, so the next request tries FMI again.
+		if len(persisted) > 0 {
+			logging.FromContext(ctx).Warn("using stale persisted forecast", "err", err, "lat", gridLat, "lon", gridLon)
+			return firstDays(persisted, days), s.cachedTimezoneForKey(cacheKey), SourceDB, nil
 		}
+		return nil, "", SourceUnavailable, fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
 	}
+	return firstDays(fetched.forecasts, days), fetched.timezone, SourceFMI, nil
+}
 
-	forecasts, err := s.store.GetForecasts(ctx, gridLat, gridLon)
-	if err == nil && len(forecasts) > 0 && isFresh(forecasts, 3*time.Hour) && hasExpandedForecastData(forecasts) {
-		s.forecastCache.Set(cacheKey, forecasts)
-		return forecasts, s.cachedTimezoneForKey(cacheKey), nil
+// lookupForecast returns days forecasts from the cache or, when fresh
+// enough, the store. Otherwise ok is false and persisted holds the stored
+// days, if any, for serving stale when FMI fails.
+func (s *Service) lookupForecast(ctx context.Context, cacheKey string, gridLat, gridLon float64, days int) (forecasts, persisted []DailyForecast, source Source, ok bool) {
+	if cached, ok := s.forecastCache.Get(cacheKey); ok && cached.days >= days {
+		return firstDays(cached.forecasts, days), nil, SourceCache, true
 	}
 
-	forecastData, err := s.fmi.FetchForecast(ctx, gridLat, gridLon)
+	persisted, err := s.store.GetForecasts(ctx, gridLat, gridLon)
 	if err != nil {
-		return nil, "", err
+		persisted = nil
+	} else if upgraded, ok := upgradeDailyForecasts(persisted); ok {
+		persisted = upgraded
+	} else {
+		persisted = nil
+	}
+	if len(persisted) >= days && isFresh(persisted, s.freshness.DailyForecast.MaxAge) {
+		s.forecastCache.Set(cacheKey, cachedForecast{forecasts: persisted, days: len(persisted)})
+		return firstDays(persisted, days), persisted, SourceDB, true
+	}
+	return nil, persisted, SourceUnavailable, false
+}
+
+type fetchedForecast struct {
+	forecasts []DailyForecast
+	timezone  string
+}
+
+// fetchForecast fetches window days from FMI, then stores and caches them.
+func (s *Service) fetchForecast(ctx context.Context, cacheKey string, gridLat, gridLon float64, window int) (fetchedForecast, error) {
+	forecastData, err := callFMI(s.fmiBackoff, func() (ForecastData, error) {
+		return s.fmi.FetchForecast(ctx, gridLat, gridLon, window)
+	})
+	if err != nil {
+		return fetchedForecast{}, err
+	}
+	return s.saveForecast(ctx, cacheKey, gridLat, gridLon, window, forecastData), nil
+}
+
+// saveForecast extends, stores and caches window days fetched from FMI.
+func (s *Service) saveForecast(ctx context.Context, cacheKey string, gridLat, gridLon float64, window int, forecastData ForecastData) fetchedForecast {
+	forecasts := s.extendForecast(ctx, gridLat, gridLon, window, forecastData.Forecasts)
+	for i := range forecasts {
+		forecasts[i].SchemaVersion = DailyForecastSchemaVersion
 	}
-	forecasts = forecastData.Forecasts
 	timezone := normalizePlaceTimezone(forecastData.Timezone)
 
 	if storeErr := s.store.UpsertForecasts(ctx, forecasts); storeErr != nil {
-		slog.Warn("failed to store forecasts", "err", storeErr)
+		logging.FromContext(ctx).Warn("failed to store forecasts", "err", storeErr)
 	}
-	s.forecastCache.Set(cacheKey, forecasts)
+	s.forecastCache.Set(cacheKey, cachedForecast{forecasts: forecasts, days: window})
 	s.timezoneCache.Set(cacheKey, timezone)
+	// The hours the days were aggregated from serve hourly requests too,
+	// so the two agree and the hourly forecast needs no fetch of its own.
+	if len(forecastData.Hourly) > 0 {
+		s.storeHourly(ctx, gridLat, gridLon, forecastData.Hourly)
+	}
+	return fetchedForecast{forecasts: forecasts, timezone: timezone}
+}
 
-	return forecasts, timezone, nil
+func firstDays(forecasts []DailyForecast, days int) []DailyForecast {
+	return forecasts[:min(days, len(forecasts))]
 }
 
 func (s *Service) cachedTimezoneForKey(cacheKey string) string {
@@ -217,38 +642,80 @@ func normalizePlaceTimezone(value string) string {
 	return value
 }
 
-func (s *Service) getHourlyForecast(ctx context.Context, gridLat, gridLon float64, limit int) ([]HourlyForecast, error) {
-	cacheKey := fmt.Sprintf("%.2f,%.2f:%d", gridLat, gridLon, limit)
-	if cached, ok := s.hourlyCache.Get(cacheKey); ok {
-		return cached, nil
+// getHourlyForecast returns the first limit hours of the forecast. The
+// cache holds the longest series fetched for the grid point, such as the
+// one that came with the daily forecast, and serves any shorter limit.
+func (s *Service) getHourlyForecast(ctx context.Context, gridLat, gridLon float64, limit int) ([]HourlyForecast, Source, error) {
+	cacheKey := gridKey(gridLat, gridLon)
+	if cached, ok := s.hourlyCache.Get(cacheKey); ok && len(cached) >= limit {
+		return cached[:limit], SourceCache, nil
 	}
 
 	persistedHourly, storeErr := s.store.GetHourlyForecasts(ctx, gridLat, gridLon, limit)
-	if storeErr == nil && len(persistedHourly) > 0 && isHourlyFresh(persistedHourly, 90*time.Minute) {
+	if upgraded, ok := upgradeHourlyForecasts(persistedHourly); ok {
+		persistedHourly = upgraded
+	} else {
+		persistedHourly = nil
+	}
+	// A shorter persisted series was fetched for a smaller limit and can't
+	// satisfy this one.
+	if storeErr == nil && len(persistedHourly) >= limit && isHourlyFresh(persistedHourly, s.freshness.HourlyForecast.MaxAge) {
 		s.hourlyCache.Set(cacheKey, persistedHourly)
-		return persistedHourly, nil
+		return persistedHourly, SourceDB, nil
 	}
 
-	hourly, err := s.fmi.FetchHourlyForecast(ctx, gridLat, gridLon, limit)
+	hourly, err := sharedFetch(ctx, &s.fetches, fmt.Sprintf("hourly:%s:%d", cacheKey, limit), func(ctx context.Context) ([]HourlyForecast, error) {
+		return s.fetchHourlyForecast(ctx, gridLat, gridLon, limit)
+	})
 	if err != nil {
 		if len(persistedHourly) > 0 {
-			slog.Warn("using stale persisted hourly forecast", "err", err, "lat", gridLat, "lon", gridLon)
+			logging.FromContext(ctx).Warn("using stale persisted hourly forecast", "err", err, "lat", gridLat, "lon", gridLon)
 			s.hourlyCache.Set(cacheKey, persistedHourly)
-			return persistedHourly, nil
+			return persistedHourly, SourceDB, nil
 		}
+		return nil, SourceUnavailable, err
+	}
+	return hourly, SourceFMI, nil
+}
+
+// fetchHourlyForecast fetches limit hours from FMI and stores them.
+func (s *Service) fetchHourlyForecast(ctx context.Context, gridLat, gridLon float64, limit int) ([]HourlyForecast, error) {
+	hourly, err := callFMI(s.fmiBackoff, func() ([]HourlyForecast, error) {
+		return s.fmi.FetchHourlyForecast(ctx, gridLat, gridLon, limit)
+	})
+	if err != nil {
 		return nil, err
 	}
+	s.storeHourly(ctx, gridLat, gridLon, hourly)
+	return hourly, nil
+}
 
+// storeHourly stamps a freshly fetched hourly series, then stores and
+// caches it and notifies watchers.
+func (s *Service) storeHourly(ctx context.Context, gridLat, gridLon float64, hourly []HourlyForecast) {
 	fetchedAt := time.Now()
 	for i := range hourly {
 		hourly[i].FetchedAt = fetchedAt
+		hourly[i].SchemaVersion = HourlyForecastSchemaVersion
 	}
 
 	if upsertErr := s.store.UpsertHourlyForecasts(ctx, gridLat, gridLon, hourly); upsertErr != nil {
-		slog.Warn("failed to store hourly forecasts", "err", upsertErr)
+		logging.FromContext(ctx).Warn("failed to store hourly forecasts", "err", upsertErr)
 	}
-	s.hourlyCache.Set(cacheKey, hourly)
-	return hourly, nil
+	s.hourlyCache.Set(gridKey(gridLat, gridLon), hourly)
+	s.hourlyWatchers.notify(gridKey(gridLat, gridLon))
+}
+
+// WatchHourlyForecast returns a channel that receives a value whenever the
+// hourly forecast for the grid point covering lat, lon is refreshed from
+// FMI, so long-lived connections can push updates without polling. Signals
+// coalesce while unread. cancel stops them.
+func (s *Service) WatchHourlyForecast(lat, lon float64) (<-chan struct{}, func()) {
+	return s.hourlyWatchers.watch(gridKey(snapToGrid(lat, lon)))
+}
+
+func gridKey(gridLat, gridLon float64) string {
+	return fmt.Sprintf("%.2f,%.2f", gridLat, gridLon)
 }
 
 func snapToGrid(lat, lon float64) (float64, float64) {
@@ -265,33 +732,77 @@ func isFresh(forecasts []DailyForecast, maxAge time.Duration) bool {
 	return time.Since(oldest) < maxAge
 }
 
-func hasExpandedForecastData(forecasts []DailyForecast) bool {
-	for _, f := range forecasts {
-		// TempAvg is derived from Temperature and should be present for any day with temp data.
-		// If it's nil across all days, rows are likely from pre-migration/pre-rollout data.
-		if f.TempAvg != nil {
-			return true
-		}
-	}
-	return false
-}
+// uvFailureTTL is how long a failed UV fetch is remembered, so requests
+// for the location use the last known UV, or none, instead of each going
+// back to FMI while it fails.
+const uvFailureTTL = 2 * time.Minute
 
-func (s *Service) getUVData(ctx context.Context, gridLat, gridLon float64) []UVDataPoint {
+// getUVData returns the UV forecast for a grid point. When FMI fails, the
+// last forecast fetched within freshness.UV.MaxAge is served from the cache
+// instead; its past hours simply no longer match any forecast hour.
+func (s *Service) getUVData(ctx context.Context, gridLat, gridLon float64) ([]UVDataPoint, Source) {
 	cacheKey := fmt.Sprintf("uv:%.2f,%.2f", gridLat, gridLon)
 	if cached, ok := s.uvCache.Get(cacheKey); ok {
-		return cached
+		return cached, SourceCache
+	}
+	if _, failed := s.uvFailures.Get(cacheKey); failed {
+		return s.lastKnownUV(cacheKey)
 	}
 
-	points, err := s.fmi.FetchUVForecast(ctx, gridLat, gridLon)
+	points, err := sharedFetch(ctx, &s.fetches, cacheKey, func(ctx context.Context) ([]UVDataPoint, error) {
+		points, err := callFMI(s.fmiBackoff, func() ([]UVDataPoint, error) {
+			return s.fmi.FetchUVForecast(ctx, gridLat, gridLon)
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				s.uvFailures.Set(cacheKey, err)
+			}
+			return nil, err
+		}
+		logging.FromContext(ctx).Info("fetched UV forecast from FMI", "lat", gridLat, "lon", gridLon, "points", len(points), "data", points)
+		if len(points) > 0 {
+			s.uvCache.Set(cacheKey, points)
+			s.uvLastKnown.Set(cacheKey, points)
+		}
+		return points, nil
+	})
 	if err != nil {
-		slog.Warn("UV forecast fetch failed", "err", err)
-		return nil
+		logging.FromContext(ctx).Warn("UV forecast fetch failed", "err", err, "lat", gridLat, "lon", gridLon)
+		return s.lastKnownUV(cacheKey)
+	}
+	return points, SourceFMI
+}
+
+func (s *Service) lastKnownUV(cacheKey string) ([]UVDataPoint, Source) {
+	if last, ok := s.uvLastKnown.Get(cacheKey); ok {
+		return last, SourceCache
 	}
-	slog.Info("fetched UV forecast from FMI", "lat", gridLat, "lon", gridLon, "points", len(points), "data", points)
-	if len(points) > 0 {
-		s.uvCache.Set(cacheKey, points)
+	return nil, SourceUnavailable
+}
+
+// uvIndexes derives the UV index of each hour from FMI's cumulated UV dose:
+// the increase since the sample an hour earlier, keyed by the unix time of
+// the hour's end. The dose restarts from zero each day, so a sample below
+// the one before it is the dose since the reset. Hours without the sample
+// before them have no index.
+func uvIndexes(uvPoints []UVDataPoint) map[int64]float64 {
+	cumulated := make(map[int64]float64, len(uvPoints))
+	for _, p := range uvPoints {
+		cumulated[p.Time.Truncate(time.Hour).Unix()] = p.UVCumulated
+	}
+	indexes := make(map[int64]float64, len(cumulated))
+	for hour, dose := range cumulated {
+		prev, ok := cumulated[hour-int64(time.Hour/time.Second)]
+		if !ok {
+			continue
+		}
+		if dose >= prev {
+			indexes[hour] = dose - prev
+		} else {
+			indexes[hour] = dose
+		}
 	}
-	return points
+	return indexes
 }
 
 func applyUVToHourly(uvPoints []UVDataPoint, hourly []HourlyForecast) {
@@ -299,42 +810,41 @@ func applyUVToHourly(uvPoints []UVDataPoint, hourly []HourlyForecast) {
 	for _, p := range uvPoints {
 		uvByHour[p.Time.Truncate(time.Hour).Unix()] = p.UVCumulated
 	}
+	indexes := uvIndexes(uvPoints)
 	for i := range hourly {
-		if uv, ok := uvByHour[hourly[i].Time.Truncate(time.Hour).Unix()]; ok {
+		hour := hourly[i].Time.Truncate(time.Hour).Unix()
+		if uv, ok := uvByHour[hour]; ok {
 			hourly[i].UVCumulated = &uv
 		}
+		if index, ok := indexes[hour]; ok {
+			hourly[i].UVIndex = &index
+		}
 	}
 }
 
-func applyUVToDaily(uvPoints []UVDataPoint, forecasts []DailyForecast) {
-	type dailyUV struct {
-		sum   float64
-		count int
-	}
-	byDate := make(map[string]*dailyUV)
-	for _, p := range uvPoints {
-		date := p.Time.UTC().Format("2006-01-02")
-		d, ok := byDate[date]
-		if !ok {
-			d = &dailyUV{}
-			byDate[date] = d
+// applyUVToDaily sets the highest hourly UV index of each forecast day, with
+// days being calendar days in loc as they are when the daily forecast is
+// aggregated.
+func applyUVToDaily(uvPoints []UVDataPoint, forecasts []DailyForecast, loc *time.Location) {
+	byDate := make(map[string]float64)
+	for hour, index := range uvIndexes(uvPoints) {
+		date := time.Unix(hour, 0).In(loc).Format("2006-01-02")
+		if peak, ok := byDate[date]; !ok || index > peak {
+			byDate[date] = index
 		}
-		d.sum += p.UVCumulated
-		d.count++
 	}
 	for i := range forecasts {
-		date := forecasts[i].Date.UTC().Format("2006-01-02")
-		if d, ok := byDate[date]; ok && d.count > 0 {
-			avg := d.sum / float64(d.count)
-			forecasts[i].UVIndexAvg = &avg
+		if peak, ok := byDate[forecasts[i].Date.UTC().Format("2006-01-02")]; ok {
+			forecasts[i].UVIndexMax = &peak
 		}
 	}
 }
 
-const maxClimateNormalsDistanceKm = 50.0
-
 func (s *Service) GetClimateNormals(ctx context.Context, lat, lon float64, currentTemp *float64) (*Station, float64, []ClimateNormal, InterpolatedNormal, error) {
-	station, distKm, err := s.store.NearestStationWithClimateNormals(ctx, lat, lon, "1991-2020")
+	station, distKm, err := s.store.NearestStationWithClimateNormals(ctx, lat, lon, climateNormalsPeriod)
+	if errors.Is(err, ErrNoClimateNormals) {
+		return nil, 0, nil, InterpolatedNormal{}, nil
+	}
 	if err != nil {
 		return nil, 0, nil, InterpolatedNormal{}, fmt.Errorf("nearest station with climate normals: %w", err)
 	}
@@ -343,7 +853,7 @@ func (s *Service) GetClimateNormals(ctx context.Context, lat, lon float64, curre
 		return nil, 0, nil, InterpolatedNormal{}, nil
 	}
 
-	normals, err := s.store.GetClimateNormals(ctx, station.FMISID, "1991-2020")
+	normals, err := s.store.GetClimateNormals(ctx, station.FMISID, climateNormalsPeriod)
 	if err != nil {
 		return nil, 0, nil, InterpolatedNormal{}, fmt.Errorf("get climate normals: %w", err)
 	}
@@ -371,6 +881,19 @@ func (s *Service) GetLeaderboard(ctx context.Context, lat, lon float64, timefram
 	return entries, nil
 }
 
+func (s *Service) GetStargazing(ctx context.Context, lat, lon float64) ([]StargazingNight, error) {
+	if lon < finlandMinLon || lon > finlandMaxLon || lat < finlandMinLat || lat > finlandMaxLat {
+		return nil, ErrOutOfCoverage
+	}
+
+	gridLat, gridLon := snapToGrid(lat, lon)
+	hourly, _, err := s.getHourlyForecast(ctx, gridLat, gridLon, stargazingHours)
+	if err != nil {
+		return nil, fmt.Errorf("hourly forecast: %w", err)
+	}
+	return BuildStargazingNights(gridLat, gridLon, hourly), nil
+}
+
 func isHourlyFresh(hourly []HourlyForecast, maxAge time.Duration) bool {
 	oldest := hourly[0].FetchedAt
 	if oldest.IsZero() {
@@ -0,0 +1,575 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// emptyStore behaves like a fresh deploy: no stations, observations or
+// persisted forecasts.
+type emptyStore struct {
+	WeatherStore
+}
+
+func (emptyStore) NearestStation(ctx context.Context, lat, lon float64) (Station, float64, error) {
+	return Station{}, 0, errors.New("no rows")
+}
+
+func (emptyStore) LatestObservation(ctx context.Context, fmisid int) (Observation, error) {
+	return Observation{}, errors.New("no rows")
+}
+
+func (emptyStore) GetForecasts(ctx context.Context, gridLat, gridLon float64) ([]DailyForecast, error) {
+	return nil, nil
+}
+
+func (emptyStore) UpsertForecasts(ctx context.Context, forecasts []DailyForecast) error { return nil }
+
+func (emptyStore) GetHourlyForecasts(ctx context.Context, gridLat, gridLon float64, limit int) ([]HourlyForecast, error) {
+	return nil, nil
+}
+
+func (emptyStore) UpsertHourlyForecasts(ctx context.Context, gridLat, gridLon float64, hourly []HourlyForecast) error {
+	return nil
+}
+
+func (emptyStore) NearestAirQualityStation(ctx context.Context, lat, lon float64) (*Station, float64, error) {
+	return nil, 0, nil
+}
+
+func (emptyStore) LatestAirQuality(ctx context.Context, fmisid int) (*AirQuality, error) {
+	return nil, nil
+}
+
+func (emptyStore) NearestMarineStation(ctx context.Context, lat, lon float64) (*Station, float64, error) {
+	return nil, 0, nil
+}
+
+func (emptyStore) LatestMarineObservation(ctx context.Context, fmisid int) (*MarineObservation, error) {
+	return nil, nil
+}
+
+func (emptyStore) NearestStationWithClimateNormals(ctx context.Context, lat, lon float64, period string) (Station, float64, error) {
+	return Station{}, 0, ErrNoClimateNormals
+}
+
+type stubForecastFetcher struct{}
+
+func (stubForecastFetcher) FetchForecast(ctx context.Context, lat, lon float64, days int) (ForecastData, error) {
+	return ForecastData{
+		Forecasts: []DailyForecast{{GridLat: lat, GridLon: lon, Date: time.Now().UTC().Truncate(24 * time.Hour), TempAvg: ptr(2)}},
+		Timezone:  "Europe/Helsinki",
+	}, nil
+}
+
+func (stubForecastFetcher) FetchHourlyForecast(ctx context.Context, lat, lon float64, limit int) ([]HourlyForecast, error) {
+	start := time.Now().Truncate(time.Hour)
+	hourly := make([]HourlyForecast, limit)
+	for i := range hourly {
+		hourly[i] = HourlyForecast{Time: start.Add(time.Duration(i) * time.Hour), Temperature: ptr(1)}
+	}
+	return hourly, nil
+}
+
+func (stubForecastFetcher) FetchUVForecast(ctx context.Context, lat, lon float64) ([]UVDataPoint, error) {
+	return nil, nil
+}
+
+func TestGetForecast_WorksWithoutObservations(t *testing.T) {
+	s := NewService(emptyStore{}, stubForecastFetcher{}, DefaultFreshness())
+
+	if _, err := s.GetWeather(context.Background(), 60.17, 24.94, WeatherOptions{}); err == nil {
+		t.Fatal("expected GetWeather to fail without stations")
+	}
+
+	resp, err := s.GetForecast(context.Background(), 60.17, 24.94, 0, 0)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	if len(resp.Forecast) != 1 || len(resp.Hourly) != DefaultHourlyForecastHours {
+		t.Fatalf("expected forecast and hourly data, got %d daily and %d hourly", len(resp.Forecast), len(resp.Hourly))
+	}
+	if resp.Timezone != "Europe/Helsinki" {
+		t.Errorf("timezone = %q", resp.Timezone)
+	}
+}
+
+func TestGetForecast_ClampsHourlyLimit(t *testing.T) {
+	s := NewService(emptyStore{}, stubForecastFetcher{}, DefaultFreshness())
+
+	cases := []struct {
+		hours, max, want int
+	}{
+		{6, 0, 6},
+		{48, 0, 48},
+		{500, 0, DefaultMaxHourlyForecastHours},
+		{48, 24, 24},
+		{-1, 24, DefaultHourlyForecastHours},
+	}
+	for _, tc := range cases {
+		s.SetMaxHourlyForecastHours(tc.max)
+		resp, err := s.GetForecast(context.Background(), 60.17, 24.94, tc.hours, 0)
+		if err != nil {
+			t.Fatalf("GetForecast: %v", err)
+		}
+		if len(resp.Hourly) != tc.want {
+			t.Errorf("hours=%d max=%d: got %d entries, want %d", tc.hours, tc.max, len(resp.Hourly), tc.want)
+		}
+	}
+}
+
+// storedForecastStore has a fresh persisted daily forecast.
+type storedForecastStore struct {
+	emptyStore
+	days []DailyForecast
+}
+
+func (s storedForecastStore) GetForecasts(ctx context.Context, gridLat, gridLon float64) ([]DailyForecast, error) {
+	return s.days, nil
+}
+
+type countingForecastFetcher struct {
+	stubForecastFetcher
+	calls, lastDays int
+}
+
+func (f *countingForecastFetcher) FetchForecast(ctx context.Context, lat, lon float64, days int) (ForecastData, error) {
+	f.calls++
+	f.lastDays = days
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	forecasts := make([]DailyForecast, days)
+	for i := range forecasts {
+		forecasts[i] = DailyForecast{Date: today.AddDate(0, 0, i), FetchedAt: time.Now(), TempAvg: ptr(1), SchemaVersion: DailyForecastSchemaVersion}
+	}
+	return ForecastData{Forecasts: forecasts}, nil
+}
+
+func TestGetForecast_SlicesStoredDaysInsteadOfRefetching(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	stored := make([]DailyForecast, 11)
+	for i := range stored {
+		stored[i] = DailyForecast{Date: today.AddDate(0, 0, i), FetchedAt: time.Now(), TempAvg: ptr(1), SchemaVersion: DailyForecastSchemaVersion}
+	}
+	fetcher := &countingForecastFetcher{}
+	s := NewService(storedForecastStore{days: stored}, fetcher, DefaultFreshness())
+
+	resp, err := s.GetForecast(context.Background(), 60.17, 24.94, 0, 3)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	if len(resp.Forecast) != 3 || !resp.Forecast[0].Date.Equal(today) {
+		t.Fatalf("expected the first 3 stored days, got %d", len(resp.Forecast))
+	}
+	if fetcher.calls != 0 {
+		t.Fatalf("expected no FMI fetch, got %d", fetcher.calls)
+	}
+
+	// The cached 11 days also cover the default request.
+	resp, err = s.GetForecast(context.Background(), 60.17, 24.94, 0, 0)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	if len(resp.Forecast) != DefaultForecastDays || fetcher.calls != 0 {
+		t.Fatalf("expected %d cached days without fetching, got %d days and %d fetches", DefaultForecastDays, len(resp.Forecast), fetcher.calls)
+	}
+
+	// More days than stored widens the FMI window.
+	resp, err = s.GetForecast(context.Background(), 60.17, 24.94, 0, 14)
+	if err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	if fetcher.calls != 1 || fetcher.lastDays != 14 || len(resp.Forecast) != 14 {
+		t.Fatalf("expected one 14-day fetch, got %d fetches of %d days returning %d", fetcher.calls, fetcher.lastDays, len(resp.Forecast))
+	}
+}
+
+func TestWatchHourlyForecast_SignalsOnRefresh(t *testing.T) {
+	s := NewService(emptyStore{}, stubForecastFetcher{}, DefaultFreshness())
+	near, cancel := s.WatchHourlyForecast(60.17, 24.94)
+	defer cancel()
+	far, cancelFar := s.WatchHourlyForecast(65.01, 25.47)
+	defer cancelFar()
+
+	if _, err := s.GetForecast(context.Background(), 60.17, 24.94, 0, 0); err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	select {
+	case <-near:
+	default:
+		t.Fatal("expected a signal for the refreshed grid point")
+	}
+	select {
+	case <-far:
+		t.Fatal("unexpected signal for another grid point")
+	default:
+	}
+
+	// Served from cache: no refresh, no signal.
+	if _, err := s.GetForecast(context.Background(), 60.17, 24.94, 0, 0); err != nil {
+		t.Fatalf("GetForecast: %v", err)
+	}
+	select {
+	case <-near:
+		t.Fatal("unexpected signal for a cached forecast")
+	default:
+	}
+}
+
+// warningStore has one station and serves a fixed set of warnings.
+type warningStore struct {
+	emptyStore
+	warnings []Warning
+	err      error
+}
+
+func (warningStore) NearestStation(ctx context.Context, lat, lon float64) (Station, float64, error) {
+	return Station{FMISID: 100971, Name: "Helsinki Kaisaniemi"}, 1, nil
+}
+
+func (warningStore) LatestObservation(ctx context.Context, fmisid int) (Observation, error) {
+	return Observation{FMISID: fmisid, ObservedAt: time.Now()}, nil
+}
+
+func (s warningStore) WarningsForPoint(ctx context.Context, lat, lon float64, at time.Time) ([]Warning, error) {
+	return s.warnings, s.err
+}
+
+func TestGetWeather_AttachesActiveWarnings(t *testing.T) {
+	now := time.Now()
+	store := warningStore{warnings: []Warning{
+		{ID: "active", Event: "Wind warning", Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour)},
+		{ID: "expired", Event: "Wind warning", Onset: now.Add(-2 * time.Hour), Expires: now.Add(-time.Minute)},
+	}}
+	s := NewService(store, stubForecastFetcher{}, DefaultFreshness())
+
+	resp, err := s.GetWeather(context.Background(), 60.17, 24.94, WeatherOptions{})
+	if err != nil {
+		t.Fatalf("GetWeather: %v", err)
+	}
+	if len(resp.Warnings) != 1 || resp.Warnings[0].ID != "active" {
+		t.Fatalf("expected only the active warning, got %+v", resp.Warnings)
+	}
+
+	// A failing warnings lookup must not fail the weather response.
+	s = NewService(warningStore{err: errors.New("db down")}, stubForecastFetcher{}, DefaultFreshness())
+	resp, err = s.GetWeather(context.Background(), 60.17, 24.94, WeatherOptions{})
+	if err != nil {
+		t.Fatalf("GetWeather: %v", err)
+	}
+	if len(resp.Warnings) != 0 {
+		t.Fatalf("expected no warnings, got %+v", resp.Warnings)
+	}
+}
+
+// hourlyOnlyFetcher fails the test on anything but hourly forecast fetches.
+type hourlyOnlyFetcher struct {
+	stubForecastFetcher
+	t *testing.T
+}
+
+func (f hourlyOnlyFetcher) FetchForecast(ctx context.Context, lat, lon float64, days int) (ForecastData, error) {
+	f.t.Error("unexpected daily forecast fetch")
+	return ForecastData{}, nil
+}
+
+func (f hourlyOnlyFetcher) FetchUVForecast(ctx context.Context, lat, lon float64) ([]UVDataPoint, error) {
+	f.t.Error("unexpected UV forecast fetch")
+	return nil, nil
+}
+
+// dailyOnlyFetcher returns a day of hours with the daily forecast and fails
+// the test on hourly forecast fetches.
+type dailyOnlyFetcher struct {
+	stubForecastFetcher
+	t *testing.T
+}
+
+func (f dailyOnlyFetcher) FetchForecast(ctx context.Context, lat, lon float64, days int) (ForecastData, error) {
+	data, _ := f.stubForecastFetcher.FetchForecast(ctx, lat, lon, days)
+	data.Hourly, _ = f.stubForecastFetcher.FetchHourlyForecast(ctx, lat, lon, 24)
+	return data, nil
+}
+
+func (f dailyOnlyFetcher) FetchHourlyForecast(ctx context.Context, lat, lon float64, limit int) ([]HourlyForecast, error) {
+	f.t.Error("unexpected hourly forecast fetch")
+	return nil, nil
+}
+
+func TestGetForecast_HourlyComesWithDailyFetch(t *testing.T) {
+	store := &recordingHourlyStore{}
+	s := NewService(store, dailyOnlyFetcher{t: t}, DefaultFreshness())
+
+	for _, hours := range []int{12, 24, 6} {
+		resp, err := s.GetForecast(context.Background(), 60.17, 24.94, hours, 0)
+		if err != nil {
+			t.Fatalf("GetForecast: %v", err)
+		}
+		if len(resp.Hourly) != hours || len(resp.Forecast) != 1 {
+			t.Fatalf("expected %d hours and a day, got %d and %d", hours, len(resp.Hourly), len(resp.Forecast))
+		}
+	}
+	if store.hourly != 24 {
+		t.Fatalf("expected the 24 fetched hours to be stored, got %d", store.hourly)
+	}
+}
+
+// recordingHourlyStore is emptyStore remembering the most hours stored at
+// once.
+type recordingHourlyStore struct {
+	emptyStore
+	hourly int
+}
+
+func (s *recordingHourlyStore) UpsertHourlyForecasts(ctx context.Context, lat, lon float64, hourly []HourlyForecast) error {
+	s.hourly = max(s.hourly, len(hourly))
+	return nil
+}
+
+func TestGetCompactWeather_FetchesOnlyHourly(t *testing.T) {
+	s := NewService(warningStore{}, hourlyOnlyFetcher{t: t}, DefaultFreshness())
+
+	resp, err := s.GetCompactWeather(context.Background(), 60.17, 24.94)
+	if err != nil {
+		t.Fatalf("GetCompactWeather: %v", err)
+	}
+	if resp.Current.Station.FMISID != 100971 || len(resp.Hourly) != CompactHourlyForecastHours || len(resp.Forecast) != 0 {
+		t.Fatalf("unexpected compact weather: station %d, %d hourly, %d daily", resp.Current.Station.FMISID, len(resp.Hourly), len(resp.Forecast))
+	}
+	if resp.Provenance.Hourly != SourceFMI {
+		t.Fatalf("expected hourly source fmi, got %q", resp.Provenance.Hourly)
+	}
+
+	if _, err := s.GetCompactWeather(context.Background(), 40, 24.94); !errors.Is(err, ErrOutOfCoverage) {
+		t.Fatalf("expected ErrOutOfCoverage, got %v", err)
+	}
+}
+
+func TestGetWeather_ReportsProvenance(t *testing.T) {
+	s := NewService(warningStore{}, stubForecastFetcher{}, DefaultFreshness())
+
+	resp, err := s.GetWeather(context.Background(), 60.1712, 24.9449, WeatherOptions{})
+	if err != nil {
+		t.Fatalf("GetWeather: %v", err)
+	}
+	want := Provenance{GridLat: 60.17, GridLon: 24.94, Forecast: SourceFMI, Hourly: SourceFMI, UV: SourceFMI}
+	if resp.Provenance != want {
+		t.Fatalf("first request: got %+v, want %+v", resp.Provenance, want)
+	}
+
+	resp, err = s.GetWeather(context.Background(), 60.1712, 24.9449, WeatherOptions{})
+	if err != nil {
+		t.Fatalf("GetWeather: %v", err)
+	}
+	want.Forecast, want.Hourly = SourceCache, SourceCache
+	if resp.Provenance.Forecast != want.Forecast || resp.Provenance.Hourly != want.Hourly {
+		t.Fatalf("second request: got %+v, want %+v", resp.Provenance, want)
+	}
+}
+
+func TestApplyUVToDaily_UsesLocalDays(t *testing.T) {
+	loc := PlaceLocation(DefaultPlaceTimezone)
+	forecasts := []DailyForecast{
+		{Date: time.Date(2026, 6, 14, 0, 0, 0, 0, time.UTC)},
+		{Date: time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)},
+	}
+	// 22:00 UTC on the 14th is already 01:00 on the 15th in Helsinki.
+	applyUVToDaily([]UVDataPoint{
+		{Time: time.Date(2026, 6, 14, 10, 0, 0, 0, time.UTC), UVCumulated: 2},
+		{Time: time.Date(2026, 6, 14, 11, 0, 0, 0, time.UTC), UVCumulated: 6},
+		{Time: time.Date(2026, 6, 14, 12, 0, 0, 0, time.UTC), UVCumulated: 9},
+		{Time: time.Date(2026, 6, 14, 21, 0, 0, 0, time.UTC), UVCumulated: 20},
+		{Time: time.Date(2026, 6, 14, 22, 0, 0, 0, time.UTC), UVCumulated: 21},
+	}, forecasts, loc)
+
+	if got := forecasts[0].UVIndexMax; got == nil || *got != 4 {
+		t.Fatalf("expected UV index 4 on the 14th, got %v", got)
+	}
+	if got := forecasts[1].UVIndexMax; got == nil || *got != 1 {
+		t.Fatalf("expected UV index 1 on the 15th, got %v", got)
+	}
+}
+
+func TestUVIndexes_DifferencesCumulatedDose(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2026, 6, day, hour, 0, 0, 0, time.UTC) }
+	// A monotonic dose through the evening, reset at midnight, with 02:00
+	// missing.
+	points := []UVDataPoint{
+		{Time: at(14, 20), UVCumulated: 30},
+		{Time: at(14, 21), UVCumulated: 30.5},
+		{Time: at(14, 22), UVCumulated: 30.5},
+		{Time: at(15, 0), UVCumulated: 0},
+		{Time: at(15, 1), UVCumulated: 0.25},
+		{Time: at(15, 3), UVCumulated: 1.5},
+		{Time: at(15, 4), UVCumulated: 3},
+	}
+	want := map[time.Time]float64{
+		at(14, 21): 0.5,
+		at(14, 22): 0,
+		at(15, 1):  0.25,
+		at(15, 4):  1.5,
+	}
+	got := uvIndexes(points)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d indexes, got %v", len(want), got)
+	}
+	for hour, index := range want {
+		if v, ok := got[hour.Unix()]; !ok || v != index {
+			t.Errorf("%s: expected UV index %v, got %v (present %v)", hour.Format(time.RFC3339), index, v, ok)
+		}
+	}
+
+	// 23:00 is missing too, so midnight has nothing to difference against;
+	// a reset straight after a known hour counts from zero.
+	reset := uvIndexes([]UVDataPoint{{Time: at(14, 23), UVCumulated: 31}, {Time: at(15, 0), UVCumulated: 0.1}})
+	if v := reset[at(15, 0).Unix()]; v != 0.1 {
+		t.Errorf("expected the dose since the reset, got %v", v)
+	}
+
+	hourly := []HourlyForecast{{Time: at(15, 4)}, {Time: at(15, 3)}}
+	applyUVToHourly(points, hourly)
+	if hourly[0].UVIndex == nil || *hourly[0].UVIndex != 1.5 || *hourly[0].UVCumulated != 3 {
+		t.Fatalf("expected index 1.5 with dose 3 at 04:00, got %v/%v", hourly[0].UVIndex, hourly[0].UVCumulated)
+	}
+	if hourly[1].UVIndex != nil || hourly[1].UVCumulated == nil {
+		t.Fatalf("expected a dose but no index after a missing hour, got %v/%v", hourly[1].UVIndex, hourly[1].UVCumulated)
+	}
+}
+
+func TestPlaceLocation_FallsBack(t *testing.T) {
+	if got := PlaceLocation("Europe/Stockholm").String(); got != "Europe/Stockholm" {
+		t.Fatalf("expected Europe/Stockholm, got %s", got)
+	}
+	for _, name := range []string{"", "Not/AZone"} {
+		if got := PlaceLocation(name).String(); got != DefaultPlaceTimezone {
+			t.Fatalf("PlaceLocation(%q) = %s, want %s", name, got, DefaultPlaceTimezone)
+		}
+	}
+}
+
+type failingForecastFetcher struct {
+	stubForecastFetcher
+	err error
+}
+
+func (f failingForecastFetcher) FetchForecast(ctx context.Context, lat, lon float64, days int) (ForecastData, error) {
+	return ForecastData{}, f.err
+}
+
+func TestGetForecast_UpstreamFailureIsTyped(t *testing.T) {
+	svc := NewService(emptyStore{}, failingForecastFetcher{err: errors.New("fmi: 502 Bad Gateway")}, DefaultFreshness())
+	_, _, _, err := svc.getForecast(context.Background(), 60.2, 24.9, 10)
+	if !errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("expected ErrUpstreamUnavailable, got %v", err)
+	}
+}
+
+func TestGetForecast_RejectedQueryIsNotAnOutage(t *testing.T) {
+	rejected := fmt.Errorf("fetch forecast: %w", ErrUpstreamRejected)
+	svc := NewService(emptyStore{}, failingForecastFetcher{err: rejected}, DefaultFreshness())
+	_, _, _, err := svc.getForecast(context.Background(), 60.2, 24.9, 10)
+	if !errors.Is(err, ErrUpstreamRejected) || errors.Is(err, ErrUpstreamUnavailable) {
+		t.Fatalf("expected a rejected query, not ErrUpstreamUnavailable, got %v", err)
+	}
+}
+
+func TestGetWeather_SkipsUnrequestedSections(t *testing.T) {
+	now := time.Now()
+	store := warningStore{warnings: []Warning{{ID: "active", Onset: now.Add(-time.Hour), Expires: now.Add(time.Hour)}}}
+	fetcher := &countingForecastFetcher{}
+	s := NewService(store, fetcher, DefaultFreshness())
+
+	resp, err := s.GetWeather(context.Background(), 60.17, 24.94, WeatherOptions{Sections: SectionCurrent})
+	if err != nil {
+		t.Fatalf("GetWeather: %v", err)
+	}
+	if fetcher.calls != 0 || resp.Forecast != nil || resp.Hourly != nil {
+		t.Fatalf("expected no forecasts for a current-only request, got %d fetches", fetcher.calls)
+	}
+	if resp.Warnings != nil || resp.Current.Observation.ObservedAt.IsZero() {
+		t.Fatalf("expected only current conditions, got %+v", resp)
+	}
+	if p := resp.Provenance; p.Forecast != SourceSkipped || p.Hourly != SourceSkipped || p.UV != SourceSkipped {
+		t.Fatalf("expected skipped sources, got %+v", p)
+	}
+
+	resp, err = s.GetWeather(context.Background(), 60.17, 24.94, WeatherOptions{Sections: SectionDaily | SectionAlerts})
+	if err != nil {
+		t.Fatalf("GetWeather: %v", err)
+	}
+	if fetcher.calls != 1 || len(resp.Forecast) == 0 || resp.Hourly != nil {
+		t.Fatalf("expected only the daily forecast, got %d fetches, %d days, %d hours", fetcher.calls, len(resp.Forecast), len(resp.Hourly))
+	}
+	if len(resp.Warnings) != 1 || !resp.Current.Observation.ObservedAt.IsZero() {
+		t.Fatalf("expected warnings without an observation, got %+v", resp)
+	}
+}
+
+func TestSectionIncludes(t *testing.T) {
+	if !Section(0).Includes(SectionMarine) {
+		t.Error("expected the zero Section to include everything")
+	}
+	s := SectionCurrent | SectionDaily
+	if !s.Includes(SectionDaily) || s.Includes(SectionHourly) {
+		t.Errorf("unexpected selection for %b", s)
+	}
+}
+
+func TestGetForecast_ServesStaleDaysWhenUpstreamDown(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	stored := make([]DailyForecast, 10)
+	for i := range stored {
+		stored[i] = DailyForecast{Date: today.AddDate(0, 0, i), FetchedAt: time.Now().Add(-12 * time.Hour), TempAvg: ptr(1), SchemaVersion: DailyForecastSchemaVersion}
+	}
+	circuitOpen := fmt.Errorf("fetch forecast: %w", ErrUpstreamUnavailable)
+	svc := NewService(storedForecastStore{days: stored}, failingForecastFetcher{err: circuitOpen}, DefaultFreshness())
+
+	forecasts, _, source, err := svc.getForecast(context.Background(), 60.2, 24.9, 5)
+	if err != nil {
+		t.Fatalf("expected stale days, got %v", err)
+	}
+	if len(forecasts) != 5 || source != SourceDB {
+		t.Fatalf("expected 5 stale days from the db, got %d from %s", len(forecasts), source)
+	}
+}
+
+// uvFetcher serves points, or fails with err, counting UV fetches.
+type uvFetcher struct {
+	stubForecastFetcher
+	points []UVDataPoint
+	err    error
+	calls  int
+}
+
+func (f *uvFetcher) FetchUVForecast(ctx context.Context, lat, lon float64) ([]UVDataPoint, error) {
+	f.calls++
+	return f.points, f.err
+}
+
+func TestGetUVData_ServesLastKnownWhileFailing(t *testing.T) {
+	freshness := DefaultFreshness()
+	freshness.UV.CacheTTL = time.Nanosecond
+	f := &uvFetcher{points: []UVDataPoint{{Time: time.Now().Truncate(time.Hour), UVCumulated: 3}}}
+	s := NewService(emptyStore{}, f, freshness)
+
+	if points, source := s.getUVData(context.Background(), 60.17, 24.94); len(points) != 1 || source != SourceFMI {
+		t.Fatalf("expected a fresh fetch, got %d points from %s", len(points), source)
+	}
+
+	f.points, f.err = nil, errors.New("UV API: 503")
+	for range 2 {
+		points, source := s.getUVData(context.Background(), 60.17, 24.94)
+		if len(points) != 1 || source != SourceCache {
+			t.Fatalf("expected the last known UV, got %d points from %s", len(points), source)
+		}
+	}
+	if f.calls != 2 {
+		t.Fatalf("expected the failure to be cached after one retry, got %d fetches", f.calls)
+	}
+
+	if points, source := s.getUVData(context.Background(), 61.5, 23.76); points != nil || source != SourceUnavailable {
+		t.Fatalf("expected no UV without a last known forecast, got %d points from %s", len(points), source)
+	}
+}
@@ -0,0 +1,35 @@
+package weather
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// sharedFetch runs fetch once for all concurrent callers with the same key,
+// so a cold cache hit by many requests for one grid point makes a single
+// FMI call whose result or error they all get. Keys are the cache key the
+// result is stored under, prefixed by kind.
+//
+// The call runs detached from the cancellation of whichever caller started
+// it, keeping its deadline, so one client disconnecting doesn't fail the
+// others. A caller whose own context ends stops waiting with its error.
+func sharedFetch[T any](ctx context.Context, group *singleflight.Group, key string, fetch func(context.Context) (T, error)) (T, error) {
+	ch := group.DoChan(key, func() (any, error) {
+		fetchCtx := context.WithoutCancel(ctx)
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			fetchCtx, cancel = context.WithDeadline(fetchCtx, deadline)
+			defer cancel()
+		}
+		return fetch(fetchCtx)
+	})
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case res := <-ch:
+		v, _ := res.Val.(T)
+		return v, res.Err
+	}
+}
@@ -0,0 +1,138 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingForecastFetcher counts calls and holds each until release is
+// closed, so concurrent requests pile up on the first.
+type blockingForecastFetcher struct {
+	stubForecastFetcher
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+	ctxErr  atomic.Value
+}
+
+func newBlockingForecastFetcher() *blockingForecastFetcher {
+	return &blockingForecastFetcher{started: make(chan struct{}, 100), release: make(chan struct{})}
+}
+
+func (f *blockingForecastFetcher) wait(ctx context.Context) error {
+	f.calls.Add(1)
+	f.started <- struct{}{}
+	<-f.release
+	if err := ctx.Err(); err != nil {
+		f.ctxErr.Store(err)
+		return err
+	}
+	return nil
+}
+
+func (f *blockingForecastFetcher) FetchForecast(ctx context.Context, lat, lon float64, days int) (ForecastData, error) {
+	if err := f.wait(ctx); err != nil {
+		return ForecastData{}, err
+	}
+	return f.stubForecastFetcher.FetchForecast(ctx, lat, lon, days)
+}
+
+func (f *blockingForecastFetcher) FetchHourlyForecast(ctx context.Context, lat, lon float64, limit int) ([]HourlyForecast, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return []HourlyForecast{{Time: time.Now().Truncate(time.Hour)}}, nil
+}
+
+func (f *blockingForecastFetcher) FetchUVForecast(ctx context.Context, lat, lon float64) ([]UVDataPoint, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	return []UVDataPoint{{Time: time.Now().Truncate(time.Hour), UVCumulated: 2}}, nil
+}
+
+func TestService_SharesConcurrentFetches(t *testing.T) {
+	fetches := map[string]func(*Service) error{
+		"daily": func(s *Service) error {
+			_, _, _, err := s.getForecast(context.Background(), 60.17, 24.94, 1)
+			return err
+		},
+		"hourly": func(s *Service) error {
+			_, _, err := s.getHourlyForecast(context.Background(), 60.17, 24.94, 12)
+			return err
+		},
+		"uv": func(s *Service) error {
+			if points, _ := s.getUVData(context.Background(), 60.17, 24.94); len(points) == 0 {
+				return errors.New("no UV points")
+			}
+			return nil
+		},
+	}
+	for name, fetch := range fetches {
+		t.Run(name, func(t *testing.T) {
+			fetcher := newBlockingForecastFetcher()
+			svc := NewService(emptyStore{}, fetcher, DefaultFreshness())
+
+			const requests = 50
+			var wg sync.WaitGroup
+			errs := make(chan error, requests)
+			for range requests {
+				wg.Go(func() { errs <- fetch(svc) })
+			}
+			<-fetcher.started
+			// Give the other requests time to join the call in flight.
+			time.Sleep(50 * time.Millisecond)
+			close(fetcher.release)
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				if err != nil {
+					t.Fatalf("fetch: %v", err)
+				}
+			}
+			if got := fetcher.calls.Load(); got != 1 {
+				t.Fatalf("expected 1 upstream call for %d requests, got %d", requests, got)
+			}
+		})
+	}
+}
+
+func TestService_SharedFetchOutlivesCanceledCaller(t *testing.T) {
+	fetcher := newBlockingForecastFetcher()
+	svc := NewService(emptyStore{}, fetcher, DefaultFreshness())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, _, _, err := svc.getForecast(ctx, 60.17, 24.94, 1)
+		first <- err
+	}()
+	<-fetcher.started
+
+	second := make(chan error, 1)
+	go func() {
+		_, _, _, err := svc.getForecast(context.Background(), 60.17, 24.94, 1)
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the canceled caller to stop waiting, got %v", err)
+	}
+	close(fetcher.release)
+	if err := <-second; err != nil {
+		t.Fatalf("expected the other caller to get the result, got %v", err)
+	}
+	if err := fetcher.ctxErr.Load(); err != nil {
+		t.Fatalf("expected the shared call's context to stay live, got %v", err)
+	}
+	if got := fetcher.calls.Load(); got != 1 {
+		t.Fatalf("expected 1 upstream call, got %d", got)
+	}
+}
@@ -0,0 +1,121 @@
+package weather
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Hours count as dark once the sun is below nautical twilight. Finnish
+	// summers never reach astronomical darkness, so -18° would leave most of
+	// the year without any usable night.
+	stargazingDarkElevation = -12.0
+	// A full moon removes at most half of an otherwise perfect score.
+	stargazingMoonPenalty = 0.5
+	stargazingHours       = 36
+)
+
+// StargazingNight scores one contiguous dark period for stargazing.
+type StargazingNight struct {
+	Date             time.Time
+	DarkStart        time.Time
+	DarkEnd          time.Time
+	DarkHours        int
+	CloudCoverAvg    *float64
+	MoonPhase        float64
+	MoonIllumination float64
+	Score            *float64
+	Rating           string
+	BestHour         *time.Time
+}
+
+// BuildStargazingNights groups dark hourly forecast entries into nights and
+// scores each from cloud cover and moon illumination. Score is 0-100 and nil
+// when none of the dark hours carry a cloud cover forecast.
+func BuildStargazingNights(lat, lon float64, hourly []HourlyForecast) []StargazingNight {
+	var (
+		nights  []StargazingNight
+		current []HourlyForecast
+	)
+	flush := func() {
+		if len(current) > 0 {
+			nights = append(nights, scoreStargazingNight(current))
+			current = nil
+		}
+	}
+	for i, h := range hourly {
+		if SolarElevation(lat, lon, h.Time) >= stargazingDarkElevation {
+			flush()
+			continue
+		}
+		if len(current) > 0 && i > 0 && h.Time.Sub(hourly[i-1].Time) > time.Hour {
+			flush()
+		}
+		current = append(current, h)
+	}
+	flush()
+	return nights
+}
+
+func scoreStargazingNight(hours []HourlyForecast) StargazingNight {
+	start := hours[0].Time
+	end := hours[len(hours)-1].Time.Add(time.Hour)
+	midpoint := start.Add(end.Sub(start) / 2)
+	phase := MoonPhase(midpoint)
+	illumination := MoonIllumination(phase)
+	moonFactor := 1 - stargazingMoonPenalty*illumination
+
+	night := StargazingNight{
+		Date:             time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+		DarkStart:        start,
+		DarkEnd:          end,
+		DarkHours:        len(hours),
+		MoonPhase:        math.Round(phase*1000) / 1000,
+		MoonIllumination: math.Round(illumination*1000) / 1000,
+	}
+
+	var (
+		cloudSum  float64
+		scoreSum  float64
+		counted   int
+		bestScore = -1.0
+	)
+	for _, h := range hours {
+		if h.CloudCover == nil {
+			continue
+		}
+		cloud := math.Max(0, math.Min(100, *h.CloudCover))
+		hourScore := (1 - cloud/100) * moonFactor * 100
+		cloudSum += cloud
+		scoreSum += hourScore
+		counted++
+		if hourScore > bestScore {
+			bestScore = hourScore
+			best := h.Time
+			night.BestHour = &best
+		}
+	}
+	if counted == 0 {
+		return night
+	}
+
+	cloudAvg := cloudSum / float64(counted)
+	score := math.Round(scoreSum / float64(counted))
+	night.CloudCoverAvg = &cloudAvg
+	night.Score = &score
+	night.Rating = stargazingRating(score)
+	return night
+}
+
+func stargazingRating(score float64) string {
+	switch {
+	case score >= 80:
+		return "excellent"
+	case score >= 60:
+		return "good"
+	case score >= 35:
+		return "fair"
+	default:
+		return "poor"
+	}
+}
@@ -0,0 +1,87 @@
+package weather
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMoonPhase_KnownDates(t *testing.T) {
+	// Full moon 2024-01-25 17:54 UTC, new moon 2024-02-09 22:59 UTC.
+	full := MoonPhase(time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC))
+	if math.Abs(full-0.5) > 0.03 {
+		t.Fatalf("expected phase near 0.5 at full moon, got %.3f", full)
+	}
+	if illum := MoonIllumination(full); illum < 0.97 {
+		t.Fatalf("expected near-full illumination, got %.3f", illum)
+	}
+
+	newMoon := MoonPhase(time.Date(2024, 2, 9, 22, 59, 0, 0, time.UTC))
+	if newMoon > 0.03 && newMoon < 0.97 {
+		t.Fatalf("expected phase near 0 at new moon, got %.3f", newMoon)
+	}
+	if illum := MoonIllumination(newMoon); illum > 0.03 {
+		t.Fatalf("expected near-zero illumination, got %.3f", illum)
+	}
+}
+
+func TestBuildStargazingNights_ClearNightScoresHigh(t *testing.T) {
+	// Around the 2024-02-09 new moon in Helsinki: dark from ~17 UTC to ~04 UTC.
+	start := time.Date(2024, 2, 9, 12, 0, 0, 0, time.UTC)
+	var hourly []HourlyForecast
+	for h := 0; h < 24; h++ {
+		hourly = append(hourly, HourlyForecast{
+			Time:       start.Add(time.Duration(h) * time.Hour),
+			CloudCover: ptr(0),
+		})
+	}
+
+	nights := BuildStargazingNights(60.17, 24.94, hourly)
+	if len(nights) != 1 {
+		t.Fatalf("expected one night, got %d", len(nights))
+	}
+	n := nights[0]
+	if n.DarkHours < 8 {
+		t.Fatalf("expected a long winter night, got %d dark hours", n.DarkHours)
+	}
+	if n.Score == nil || *n.Score < 95 {
+		t.Fatalf("expected near-perfect score on clear new-moon night, got %v", n.Score)
+	}
+	if n.Rating != "excellent" {
+		t.Fatalf("expected excellent rating, got %q", n.Rating)
+	}
+}
+
+func TestBuildStargazingNights_CloudyNightScoresLow(t *testing.T) {
+	start := time.Date(2024, 2, 9, 12, 0, 0, 0, time.UTC)
+	var hourly []HourlyForecast
+	for h := 0; h < 24; h++ {
+		hourly = append(hourly, HourlyForecast{
+			Time:       start.Add(time.Duration(h) * time.Hour),
+			CloudCover: ptr(95),
+		})
+	}
+
+	nights := BuildStargazingNights(60.17, 24.94, hourly)
+	if len(nights) != 1 || nights[0].Score == nil {
+		t.Fatalf("expected one scored night, got %+v", nights)
+	}
+	if *nights[0].Score > 10 || nights[0].Rating != "poor" {
+		t.Fatalf("expected poor score under overcast sky, got %v (%s)", *nights[0].Score, nights[0].Rating)
+	}
+}
+
+func TestBuildStargazingNights_NoDarknessAtMidsummer(t *testing.T) {
+	start := time.Date(2026, 6, 21, 12, 0, 0, 0, time.UTC)
+	var hourly []HourlyForecast
+	for h := 0; h < 24; h++ {
+		hourly = append(hourly, HourlyForecast{
+			Time:       start.Add(time.Duration(h) * time.Hour),
+			CloudCover: ptr(0),
+		})
+	}
+
+	if nights := BuildStargazingNights(60.17, 24.94, hourly); len(nights) != 0 {
+		t.Fatalf("expected no dark nights at midsummer, got %d", len(nights))
+	}
+}
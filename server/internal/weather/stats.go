@@ -0,0 +1,77 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"time"
+	_ "time/tzdata" // the runtime image ships without a zoneinfo database
+)
+
+// Observation statistics bucket sizes.
+const (
+	StatsPeriodDay   = "day"
+	StatsPeriodMonth = "month"
+)
+
+// MaxStatsRange caps the range one statistics request can aggregate, per
+// bucket size.
+var MaxStatsRange = map[string]time.Duration{
+	StatsPeriodDay:   366 * 24 * time.Hour,
+	StatsPeriodMonth: 5 * 366 * 24 * time.Hour,
+}
+
+// ObservationStats aggregates a station's observations over one local day
+// or month. Samples is the number of observations in the bucket, so
+// clients can spot gaps; every aggregate is nil when no sample had the
+// value.
+type ObservationStats struct {
+	Start        time.Time // local midnight starting the bucket
+	Samples      int
+	TempMin      *float64
+	TempMax      *float64
+	TempAvg      *float64
+	PrecipTotal  *float64
+	GustMax      *float64
+	WindSpeedAvg *float64
+}
+
+// StatsLocation is the timezone statistics are bucketed in, so a day is
+// the calendar day users in Finland expect.
+func StatsLocation() *time.Location {
+	return PlaceLocation(DefaultPlaceTimezone)
+}
+
+// PlaceLocation loads the IANA zone name, such as the timezone FMI reports
+// for a forecast point, falling back to DefaultPlaceTimezone when the name
+// is empty or unknown and to UTC when neither loads.
+func PlaceLocation(name string) *time.Location {
+	for _, n := range []string{name, DefaultPlaceTimezone} {
+		if n == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(n); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// GetStationStats aggregates a station's observations in [from, to) into
+// day or month buckets in StatsLocation.
+func (s *Service) GetStationStats(ctx context.Context, fmisid int, period string, from, to time.Time) (*Station, []ObservationStats, error) {
+	if _, ok := MaxStatsRange[period]; !ok {
+		return nil, nil, fmt.Errorf("unknown stats period %q", period)
+	}
+	station, err := s.store.GetStation(ctx, fmisid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if station == nil {
+		return nil, nil, ErrStationNotFound
+	}
+	stats, err := s.store.ObservationStats(ctx, fmisid, period, StatsLocation(), from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	return station, stats, nil
+}
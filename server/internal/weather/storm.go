@@ -0,0 +1,178 @@
+package weather
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	// stormTrackWindow is how much activity, counted back from the latest
+	// strike, NowcastStorm tracks. Older strikes usually belong to cells
+	// that have died or moved on and would skew the track.
+	stormTrackWindow    = time.Hour
+	stormBinWidth       = 10 * time.Minute
+	stormMinBinStrikes  = 3
+	stormArrivalRadius  = 15.0 // km; cross-track distance still counted as a hit
+	stormMinSpeedKMH    = 5.0
+	kmPerDegreeLat      = 110.574
+	kmPerDegreeLonEquat = 111.320
+)
+
+type LightningStrike struct {
+	Time         time.Time
+	Lat          float64
+	Lon          float64
+	PeakCurrent  *float64 // kA; negative for negative polarity
+	Multiplicity *int
+	// DistanceKM is the distance from the queried point, set by
+	// LightningNear.
+	DistanceKM float64
+}
+
+// StormMotion describes the estimated track of a thunderstorm cell relative
+// to a location. ArrivalAt is nil when the cell is stationary, moving away,
+// or will pass further than stormArrivalRadius from the location.
+type StormMotion struct {
+	Lat               float64
+	Lon               float64
+	ObservedAt        time.Time
+	StrikeCount       int
+	SpeedKMH          float64
+	DirectionDeg      float64
+	DistanceKM        float64
+	ClosestApproachKM *float64
+	ArrivalAt         *time.Time
+}
+
+// NowcastStorm estimates the storm motion relative to a location from the
+// strikes of the last stormTrackWindow of activity, or nil when they are
+// too few to place a track.
+func NowcastStorm(strikes []LightningStrike, lat, lon float64) *StormMotion {
+	var latest time.Time
+	for _, s := range strikes {
+		if s.Time.After(latest) {
+			latest = s.Time
+		}
+	}
+	recent := slices.DeleteFunc(slices.Clone(strikes), func(s LightningStrike) bool {
+		return latest.Sub(s.Time) > stormTrackWindow
+	})
+	return EstimateStormMotion(recent, lat, lon)
+}
+
+// EstimateStormMotion treats the given strikes as a single storm cell, bins
+// them into 10-minute windows and fits a least-squares track through the bin
+// centroids. It returns nil when fewer than two bins have enough strikes to
+// place a centroid.
+func EstimateStormMotion(strikes []LightningStrike, lat, lon float64) *StormMotion {
+	if len(strikes) < 2*stormMinBinStrikes {
+		return nil
+	}
+	sorted := slices.Clone(strikes)
+	slices.SortFunc(sorted, func(a, b LightningStrike) int { return a.Time.Compare(b.Time) })
+
+	type centroid struct {
+		t      time.Time
+		x, y   float64
+		strike int
+	}
+	originCos := math.Cos(degToRad(lat))
+	toXY := func(sLat, sLon float64) (float64, float64) {
+		return (sLon - lon) * originCos * kmPerDegreeLonEquat, (sLat - lat) * kmPerDegreeLat
+	}
+
+	var centroids []centroid
+	binStart := sorted[0].Time
+	var sumX, sumY float64
+	var count int
+	var sumT time.Duration
+	flush := func() {
+		if count >= stormMinBinStrikes {
+			centroids = append(centroids, centroid{
+				t:      binStart.Add(sumT / time.Duration(count)),
+				x:      sumX / float64(count),
+				y:      sumY / float64(count),
+				strike: count,
+			})
+		}
+		sumX, sumY, sumT, count = 0, 0, 0, 0
+	}
+	for _, s := range sorted {
+		if s.Time.Sub(binStart) >= stormBinWidth {
+			flush()
+			binStart = s.Time
+		}
+		x, y := toXY(s.Lat, s.Lon)
+		sumX += x
+		sumY += y
+		sumT += s.Time.Sub(binStart)
+		count++
+	}
+	flush()
+	if len(centroids) < 2 {
+		return nil
+	}
+
+	// Least-squares velocity in km/h for x and y independently.
+	t0 := centroids[0].t
+	var meanT, meanX, meanY float64
+	for _, c := range centroids {
+		meanT += c.t.Sub(t0).Hours()
+		meanX += c.x
+		meanY += c.y
+	}
+	n := float64(len(centroids))
+	meanT /= n
+	meanX /= n
+	meanY /= n
+	var covTX, covTY, varT float64
+	for _, c := range centroids {
+		dt := c.t.Sub(t0).Hours() - meanT
+		covTX += dt * (c.x - meanX)
+		covTY += dt * (c.y - meanY)
+		varT += dt * dt
+	}
+	if varT == 0 {
+		return nil
+	}
+	vx := covTX / varT
+	vy := covTY / varT
+
+	latest := centroids[len(centroids)-1]
+	total := 0
+	for _, c := range centroids {
+		total += c.strike
+	}
+	motion := &StormMotion{
+		Lat:         lat + latest.y/kmPerDegreeLat,
+		Lon:         lon + latest.x/(originCos*kmPerDegreeLonEquat),
+		ObservedAt:  latest.t.UTC(),
+		StrikeCount: total,
+		SpeedKMH:    math.Hypot(vx, vy),
+		DistanceKM:  math.Hypot(latest.x, latest.y),
+	}
+	direction := radToDeg(math.Atan2(vx, vy))
+	if direction < 0 {
+		direction += 360
+	}
+	motion.DirectionDeg = direction
+
+	if motion.SpeedKMH < stormMinSpeedKMH {
+		return motion
+	}
+
+	// Project the location (origin) onto the storm track.
+	ux, uy := vx/motion.SpeedKMH, vy/motion.SpeedKMH
+	along := -latest.x*ux + -latest.y*uy
+	if along <= 0 {
+		return motion
+	}
+	cross := math.Abs(-latest.x*uy + latest.y*ux)
+	motion.ClosestApproachKM = &cross
+	if cross <= stormArrivalRadius {
+		arrival := latest.t.Add(time.Duration(along / motion.SpeedKMH * float64(time.Hour))).UTC()
+		motion.ArrivalAt = &arrival
+	}
+	return motion
+}
@@ -0,0 +1,86 @@
+package weather
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// stormTrack generates strikes for a cell moving due east at speedKMH,
+// starting west of (lat, lon).
+func stormTrack(lat, lon float64, startKM, speedKMH float64, start time.Time, minutes int) []LightningStrike {
+	var strikes []LightningStrike
+	kmPerLon := math.Cos(degToRad(lat)) * kmPerDegreeLonEquat
+	for m := 0; m < minutes; m += 2 {
+		at := start.Add(time.Duration(m) * time.Minute)
+		x := startKM + speedKMH*float64(m)/60
+		for _, jitter := range []float64{-0.01, 0, 0.01} {
+			strikes = append(strikes, LightningStrike{
+				Time: at,
+				Lat:  lat + jitter,
+				Lon:  lon + x/kmPerLon,
+			})
+		}
+	}
+	return strikes
+}
+
+func TestEstimateStormMotion_ApproachingFromWest(t *testing.T) {
+	start := time.Date(2026, 7, 20, 14, 0, 0, 0, time.UTC)
+	strikes := stormTrack(61.5, 23.8, -60, 40, start, 30)
+
+	got := EstimateStormMotion(strikes, 61.5, 23.8)
+	if got == nil {
+		t.Fatal("expected storm motion")
+	}
+	if math.Abs(got.SpeedKMH-40) > 3 {
+		t.Fatalf("expected speed near 40 km/h, got %.1f", got.SpeedKMH)
+	}
+	if math.Abs(got.DirectionDeg-90) > 5 {
+		t.Fatalf("expected eastward motion, got %.1f°", got.DirectionDeg)
+	}
+	if got.ArrivalAt == nil {
+		t.Fatal("expected arrival time for approaching storm")
+	}
+	// Storm starts 60 km west at 40 km/h, so it arrives ~90 minutes after start.
+	want := start.Add(90 * time.Minute)
+	if d := got.ArrivalAt.Sub(want); d < -10*time.Minute || d > 10*time.Minute {
+		t.Fatalf("expected arrival near %s, got %s", want, got.ArrivalAt)
+	}
+}
+
+func TestEstimateStormMotion_MovingAway(t *testing.T) {
+	start := time.Date(2026, 7, 20, 14, 0, 0, 0, time.UTC)
+	strikes := stormTrack(61.5, 23.8, 20, 40, start, 30)
+
+	got := EstimateStormMotion(strikes, 61.5, 23.8)
+	if got == nil {
+		t.Fatal("expected storm motion")
+	}
+	if got.ArrivalAt != nil || got.ClosestApproachKM != nil {
+		t.Fatalf("expected no arrival for receding storm, got %+v", got)
+	}
+}
+
+func TestEstimateStormMotion_TooFewStrikes(t *testing.T) {
+	start := time.Date(2026, 7, 20, 14, 0, 0, 0, time.UTC)
+	strikes := []LightningStrike{{Time: start, Lat: 61.5, Lon: 23.8}}
+	if got := EstimateStormMotion(strikes, 61.5, 23.8); got != nil {
+		t.Fatalf("expected nil, got %+v", got)
+	}
+}
+
+func TestNowcastStorm_TracksOnlyRecentActivity(t *testing.T) {
+	start := time.Date(2026, 7, 20, 14, 0, 0, 0, time.UTC)
+	strikes := stormTrack(61.5, 23.8, -60, 40, start, 30)
+	// An earlier cell far to the north, long before the current one.
+	strikes = append(strikes, stormTrack(62.5, 23.8, 0, 0, start.Add(-3*time.Hour), 30)...)
+
+	got := NowcastStorm(strikes, 61.5, 23.8)
+	if got == nil {
+		t.Fatal("expected storm motion")
+	}
+	if math.Abs(got.SpeedKMH-40) > 3 || math.Abs(got.DirectionDeg-90) > 5 {
+		t.Fatalf("expected the old cell ignored, got %.1f km/h towards %.1f°", got.SpeedKMH, got.DirectionDeg)
+	}
+}
@@ -0,0 +1,161 @@
+package weather
+
+import (
+	"sync"
+	"time"
+)
+
+// ObservationHub fans newly stored observations out to per-station
+// subscribers, e.g. live dashboards. It only forwards an observation when
+// it is newer than the last one published for its station, so refetching
+// an overlapping window does not repeat events. It is safe for concurrent
+// use.
+type ObservationHub struct {
+	mu     sync.Mutex
+	subs   map[int]map[*observationSub]struct{}
+	latest map[int]time.Time
+	closed bool
+}
+
+type observationSub struct {
+	ch chan Observation
+}
+
+func NewObservationHub() *ObservationHub {
+	return &ObservationHub{
+		subs:   map[int]map[*observationSub]struct{}{},
+		latest: map[int]time.Time{},
+	}
+}
+
+// Subscribe returns a channel of new observations for fmisid, holding up
+// to buffer undelivered ones; when it is full the oldest is dropped. The
+// channel is closed by cancel or when the hub closes.
+func (h *ObservationHub) Subscribe(fmisid, buffer int) (<-chan Observation, func()) {
+	if buffer < 1 {
+		buffer = 1
+	}
+	sub := &observationSub{ch: make(chan Observation, buffer)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	if h.subs[fmisid] == nil {
+		h.subs[fmisid] = map[*observationSub]struct{}{}
+	}
+	h.subs[fmisid][sub] = struct{}{}
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if _, ok := h.subs[fmisid][sub]; !ok {
+				return // already closed by Close
+			}
+			delete(h.subs[fmisid], sub)
+			if len(h.subs[fmisid]) == 0 {
+				delete(h.subs, fmisid)
+			}
+			close(sub.ch)
+		})
+	}
+	return sub.ch, cancel
+}
+
+// Publish forwards each observation that is newer than the last published
+// one for its station. It never blocks on slow subscribers.
+func (h *ObservationHub) Publish(observations []Observation) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	for _, o := range observations {
+		if !o.ObservedAt.After(h.latest[o.FMISID]) {
+			continue
+		}
+		h.latest[o.FMISID] = o.ObservedAt
+		for sub := range h.subs[o.FMISID] {
+			sub.send(o)
+		}
+	}
+}
+
+// send delivers o, dropping the oldest buffered observation if the
+// subscriber has fallen behind. Only Publish sends, under the hub lock.
+func (s *observationSub) send(o Observation) {
+	for {
+		select {
+		case s.ch <- o:
+			return
+		default:
+		}
+		select {
+		case <-s.ch:
+		default:
+		}
+	}
+}
+
+// Close closes every subscriber channel and rejects new subscriptions, so
+// open streams end when the server shuts down.
+func (h *ObservationHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for fmisid, subs := range h.subs {
+		for sub := range subs {
+			close(sub.ch)
+		}
+		delete(h.subs, fmisid)
+	}
+}
+
+// watchers signals subscribers of a key that something changed. Signals
+// coalesce: a subscriber that has not yet received the previous one gets
+// a single signal.
+type watchers struct {
+	mu   sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+func newWatchers() *watchers {
+	return &watchers{subs: map[string]map[chan struct{}]struct{}{}}
+}
+
+func (w *watchers) watch(key string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+	w.mu.Lock()
+	if w.subs[key] == nil {
+		w.subs[key] = map[chan struct{}]struct{}{}
+	}
+	w.subs[key][ch] = struct{}{}
+	w.mu.Unlock()
+
+	return ch, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subs[key], ch)
+		if len(w.subs[key]) == 0 {
+			delete(w.subs, key)
+		}
+	}
+}
+
+func (w *watchers) notify(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.subs[key] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
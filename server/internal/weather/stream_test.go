@@ -0,0 +1,65 @@
+package weather
+
+import (
+	"testing"
+	"time"
+)
+
+func TestObservationHub_PublishesOnlyNewerObservations(t *testing.T) {
+	hub := NewObservationHub()
+	ch, cancel := hub.Subscribe(100971, 4)
+	defer cancel()
+
+	t0 := time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)
+	hub.Publish([]Observation{
+		{FMISID: 100971, ObservedAt: t0},
+		{FMISID: 101004, ObservedAt: t0},
+		{FMISID: 100971, ObservedAt: t0.Add(10 * time.Minute)},
+	})
+	hub.Publish([]Observation{{FMISID: 100971, ObservedAt: t0.Add(10 * time.Minute)}})
+
+	for _, want := range []time.Time{t0, t0.Add(10 * time.Minute)} {
+		got := <-ch
+		if got.FMISID != 100971 || !got.ObservedAt.Equal(want) {
+			t.Fatalf("expected 100971 at %s, got %d at %s", want, got.FMISID, got.ObservedAt)
+		}
+	}
+	select {
+	case o := <-ch:
+		t.Fatalf("unexpected observation %+v", o)
+	default:
+	}
+}
+
+func TestObservationHub_DropsOldestWhenBufferFull(t *testing.T) {
+	hub := NewObservationHub()
+	ch, cancel := hub.Subscribe(100971, 2)
+	defer cancel()
+
+	t0 := time.Date(2026, 4, 18, 10, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		hub.Publish([]Observation{{FMISID: 100971, ObservedAt: t0.Add(time.Duration(i) * time.Minute)}})
+	}
+
+	if got := (<-ch).ObservedAt; !got.Equal(t0.Add(time.Minute)) {
+		t.Fatalf("expected oldest buffered to be %s, got %s", t0.Add(time.Minute), got)
+	}
+	if got := (<-ch).ObservedAt; !got.Equal(t0.Add(2 * time.Minute)) {
+		t.Fatalf("expected newest to be %s, got %s", t0.Add(2*time.Minute), got)
+	}
+}
+
+func TestObservationHub_CloseEndsSubscriptions(t *testing.T) {
+	hub := NewObservationHub()
+	ch, cancel := hub.Subscribe(100971, 1)
+	hub.Close()
+	cancel() // safe after Close
+
+	if _, ok := <-ch; ok {
+		t.Fatal("expected channel to be closed")
+	}
+	late, _ := hub.Subscribe(100971, 1)
+	if _, ok := <-late; ok {
+		t.Fatal("expected subscription after Close to be closed")
+	}
+}
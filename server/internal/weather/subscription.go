@@ -0,0 +1,83 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrSubscriptionNotFound = errors.New("subscription not found")
+
+// ForecastSubscription asks for a webhook push whenever the forecast for a
+// location changes materially. Snapshot is the forecast the device was last
+// notified about (or the forecast at subscription time).
+type ForecastSubscription struct {
+	ID         int64
+	ClientID   string
+	Lat        float64
+	Lon        float64
+	WebhookURL string
+	Snapshot   []DailyForecast
+	CreatedAt  time.Time
+}
+
+func (s *Service) CreateSubscription(ctx context.Context, sub ForecastSubscription) (ForecastSubscription, error) {
+	if sub.Lon < finlandMinLon || sub.Lon > finlandMaxLon || sub.Lat < finlandMinLat || sub.Lat > finlandMaxLat {
+		return ForecastSubscription{}, ErrOutOfCoverage
+	}
+	created, err := s.store.CreateSubscription(ctx, sub)
+	if err != nil {
+		return ForecastSubscription{}, fmt.Errorf("create subscription: %w", err)
+	}
+	return created, nil
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, clientID string, id int64) error {
+	deleted, err := s.store.DeleteSubscription(ctx, clientID, id)
+	if err != nil {
+		return fmt.Errorf("delete subscription: %w", err)
+	}
+	if !deleted {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context) ([]ForecastSubscription, error) {
+	subs, err := s.store.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// CheckSubscription diffs the current forecast against the subscription's
+// snapshot. It returns the material changes and, when the snapshot should
+// advance, the forecast to save with SaveSubscriptionSnapshot once the
+// changes have been delivered; saving first would lose them if the push
+// fails. The snapshot only advances when something material changed, so
+// slow drifts still add up to a push eventually. The first check returns no
+// changes, just the baseline to save.
+func (s *Service) CheckSubscription(ctx context.Context, sub ForecastSubscription) ([]ForecastChange, []DailyForecast, error) {
+	gridLat, gridLon := snapToGrid(sub.Lat, sub.Lon)
+	forecasts, _, _, err := s.getForecast(ctx, gridLat, gridLon, DefaultForecastDays)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get forecast: %w", err)
+	}
+
+	changes := DiffForecasts(sub.Snapshot, forecasts)
+	if len(sub.Snapshot) > 0 && len(changes) == 0 {
+		return nil, nil, nil
+	}
+	return changes, forecasts, nil
+}
+
+// SaveSubscriptionSnapshot records snapshot as the forecast the
+// subscription was last notified about.
+func (s *Service) SaveSubscriptionSnapshot(ctx context.Context, id int64, snapshot []DailyForecast) error {
+	if err := s.store.UpdateSubscriptionSnapshot(ctx, id, snapshot); err != nil {
+		return fmt.Errorf("update subscription snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package weather
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Clearness index bounds used to map measured/forecast global radiation
+	// onto a sunshine fraction: at or below the lower bound the sun is
+	// considered hidden, at or above the upper bound it is fully out.
+	sunshineOvercastClearness = 0.3
+	sunshineClearClearness    = 0.7
+	// Below this clear-sky irradiance (W/m²) the sun is too low for the
+	// clearness index to be meaningful, so only cloud cover is used.
+	sunshineMinClearSkyRadiation = 50.0
+)
+
+// SunshineHour holds the hourly forecast inputs used to estimate sunshine.
+type SunshineHour struct {
+	Time            time.Time
+	CloudCover      *float64
+	RadiationGlobal *float64
+}
+
+// EstimateSunshineHours estimates the number of sunshine hours for one day
+// from hourly cloud cover (%) and global radiation (W/m²). Each hour with the
+// sun above the horizon contributes a fraction between 0 and 1; the total is
+// capped at the astronomical day length. Returns nil when no hour carries any
+// usable input.
+func EstimateSunshineHours(lat, lon float64, date time.Time, hours []SunshineHour) *float64 {
+	var (
+		total float64
+		used  bool
+	)
+	for _, h := range hours {
+		if h.CloudCover == nil && h.RadiationGlobal == nil {
+			continue
+		}
+		used = true
+
+		elevation := SolarElevation(lat, lon, h.Time)
+		if elevation <= 0 {
+			continue
+		}
+		total += sunshineFraction(elevation, h.CloudCover, h.RadiationGlobal)
+	}
+	if !used {
+		return nil
+	}
+
+	dayLength := ComputeSunTimes(lat, lon, date).DayLength.Hours()
+	total = math.Min(total, dayLength)
+	total = math.Round(total*10) / 10
+	return &total
+}
+
+func sunshineFraction(elevation float64, cloudCover, radiation *float64) float64 {
+	var estimates []float64
+	if cloudCover != nil {
+		estimates = append(estimates, clampUnit(1-*cloudCover/100))
+	}
+	if radiation != nil {
+		if clear := clearSkyRadiation(elevation); clear >= sunshineMinClearSkyRadiation {
+			clearness := *radiation / clear
+			estimates = append(estimates, clampUnit((clearness-sunshineOvercastClearness)/(sunshineClearClearness-sunshineOvercastClearness)))
+		}
+	}
+	if len(estimates) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, e := range estimates {
+		sum += e
+	}
+	return sum / float64(len(estimates))
+}
+
+// clearSkyRadiation returns the Haurwitz clear-sky global horizontal
+// irradiance in W/m² for the given solar elevation in degrees.
+func clearSkyRadiation(elevation float64) float64 {
+	if elevation <= 0 {
+		return 0
+	}
+	cosZenith := math.Sin(degToRad(elevation))
+	return 1098 * cosZenith * math.Exp(-0.057/cosZenith)
+}
+
+func clampUnit(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// sunshineMinObservedHours is how many hourly radiation values a station
+// needs for a day before its sunshine is compared; gaps would otherwise read
+// as missing sunshine.
+const sunshineMinObservedHours = 20
+
+// SunshineComparison is one radiation station's observed sunshine for a day
+// next to the forecast for the nearest grid cell.
+type SunshineComparison struct {
+	Lat           float64
+	Lon           float64
+	GridLat       float64
+	GridLon       float64
+	DistanceKM    float64
+	FetchedAt     time.Time
+	ForecastHours float64
+	ObservedHours float64
+}
+
+// SunshineAccuracy summarises the comparisons of one day. MeanError is
+// forecast minus observed, so a positive value means too much sunshine was
+// forecast.
+type SunshineAccuracy struct {
+	Comparisons  []SunshineComparison
+	MeanError    float64
+	MeanAbsError float64
+}
+
+// ValidateSunshine checks the day's sunshine forecasts against radiation
+// observations. Each station's observed sunshine is estimated from its
+// hourly global radiation the same way EstimateSunshineHours treats forecast
+// radiation, and compared with the forecast of the nearest grid cell within
+// maxDistanceKM. Stations with fewer than sunshineMinObservedHours hours or
+// no grid cell in range are skipped.
+func ValidateSunshine(date time.Time, forecasts []SunshineForecast, observations []RadiationObservation, maxDistanceKM float64) SunshineAccuracy {
+	type station struct {
+		lat, lon float64
+		hours    []SunshineHour
+	}
+	var stations []*station
+	byPos := make(map[[2]float64]*station)
+	for _, o := range observations {
+		pos := [2]float64{o.Lat, o.Lon}
+		st := byPos[pos]
+		if st == nil {
+			st = &station{lat: o.Lat, lon: o.Lon}
+			byPos[pos] = st
+			stations = append(stations, st)
+		}
+		radiation := o.RadiationGlobal
+		st.hours = append(st.hours, SunshineHour{Time: o.Time, RadiationGlobal: &radiation})
+	}
+
+	var acc SunshineAccuracy
+	for _, st := range stations {
+		if len(st.hours) < sunshineMinObservedHours {
+			continue
+		}
+		nearest, distance := -1, maxDistanceKM
+		for i, f := range forecasts {
+			if d := approxDistanceKM(st.lat, st.lon, f.GridLat, f.GridLon); d <= distance {
+				nearest, distance = i, d
+			}
+		}
+		if nearest < 0 {
+			continue
+		}
+		observed := EstimateSunshineHours(st.lat, st.lon, date, st.hours)
+		if observed == nil {
+			continue
+		}
+		f := forecasts[nearest]
+		acc.Comparisons = append(acc.Comparisons, SunshineComparison{
+			Lat:           st.lat,
+			Lon:           st.lon,
+			GridLat:       f.GridLat,
+			GridLon:       f.GridLon,
+			DistanceKM:    distance,
+			FetchedAt:     f.FetchedAt,
+			ForecastHours: f.SunshineHours,
+			ObservedHours: *observed,
+		})
+	}
+	if len(acc.Comparisons) == 0 {
+		return acc
+	}
+	for _, c := range acc.Comparisons {
+		diff := c.ForecastHours - c.ObservedHours
+		acc.MeanError += diff
+		acc.MeanAbsError += math.Abs(diff)
+	}
+	n := float64(len(acc.Comparisons))
+	acc.MeanError = math.Round(acc.MeanError/n*100) / 100
+	acc.MeanAbsError = math.Round(acc.MeanAbsError/n*100) / 100
+	return acc
+}
+
+// approxDistanceKM is the equirectangular distance between two points,
+// accurate to well under a percent over the few kilometres compared here.
+func approxDistanceKM(lat1, lon1, lat2, lon2 float64) float64 {
+	x := (lon2 - lon1) * math.Cos(degToRad((lat1+lat2)/2)) * kmPerDegreeLonEquat
+	y := (lat2 - lat1) * kmPerDegreeLat
+	return math.Hypot(x, y)
+}
@@ -0,0 +1,113 @@
+package weather
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEstimateSunshineHours_ClearDayMatchesDayLength(t *testing.T) {
+	date := time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC)
+	var hours []SunshineHour
+	for h := 0; h < 24; h++ {
+		hours = append(hours, SunshineHour{
+			Time:       date.Add(time.Duration(h) * time.Hour),
+			CloudCover: ptr(0),
+		})
+	}
+
+	got := EstimateSunshineHours(60.17, 24.94, date, hours)
+	if got == nil {
+		t.Fatal("expected sunshine estimate")
+	}
+	dayLength := ComputeSunTimes(60.17, 24.94, date).DayLength.Hours()
+	if *got > dayLength || *got < dayLength-1.5 {
+		t.Fatalf("expected clear-sky sunshine close to day length %.1f, got %.1f", dayLength, *got)
+	}
+}
+
+func TestEstimateSunshineHours_OvercastIsZero(t *testing.T) {
+	date := time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC)
+	var hours []SunshineHour
+	for h := 0; h < 24; h++ {
+		hours = append(hours, SunshineHour{
+			Time:            date.Add(time.Duration(h) * time.Hour),
+			CloudCover:      ptr(100),
+			RadiationGlobal: ptr(40),
+		})
+	}
+
+	got := EstimateSunshineHours(60.17, 24.94, date, hours)
+	if got == nil || *got != 0 {
+		t.Fatalf("expected 0 sunshine hours for overcast day, got %v", got)
+	}
+}
+
+func TestEstimateSunshineHours_IgnoresNightHours(t *testing.T) {
+	date := time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC)
+	hours := []SunshineHour{
+		{Time: date.Add(0 * time.Hour), CloudCover: ptr(0)},
+		{Time: date.Add(22 * time.Hour), CloudCover: ptr(0)},
+	}
+
+	got := EstimateSunshineHours(60.17, 24.94, date, hours)
+	if got == nil || *got != 0 {
+		t.Fatalf("expected 0 sunshine hours for night-only samples, got %v", got)
+	}
+}
+
+func TestEstimateSunshineHours_NoInputs(t *testing.T) {
+	date := time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC)
+	if got := EstimateSunshineHours(60.17, 24.94, date, []SunshineHour{{Time: date}}); got != nil {
+		t.Fatalf("expected nil without inputs, got %v", *got)
+	}
+}
+
+func TestValidateSunshine_ComparesNearestForecast(t *testing.T) {
+	date := time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC)
+	var observations []RadiationObservation
+	for h := range 24 {
+		at := date.Add(time.Duration(h) * time.Hour)
+		// Clear sky all day at one station, too few hours at the other.
+		observations = append(observations, RadiationObservation{
+			Lat: 60.20, Lon: 24.96, Time: at,
+			RadiationGlobal: clearSkyRadiation(SolarElevation(60.20, 24.96, at)),
+		})
+		if h < 12 {
+			observations = append(observations, RadiationObservation{Lat: 61.84, Lon: 25.64, Time: at, RadiationGlobal: 500})
+		}
+	}
+	forecasts := []SunshineForecast{
+		{GridLat: 60.25, GridLon: 24.95, SunshineHours: 15},
+		{GridLat: 60.20, GridLon: 25.00, SunshineHours: 17},
+		{GridLat: 61.84, GridLon: 25.64, SunshineHours: 10},
+	}
+
+	acc := ValidateSunshine(date, forecasts, observations, 10)
+	if len(acc.Comparisons) != 1 {
+		t.Fatalf("expected one station with a full day, got %+v", acc.Comparisons)
+	}
+	c := acc.Comparisons[0]
+	if c.GridLat != 60.20 || c.GridLon != 25.00 || c.DistanceKM > 3 {
+		t.Fatalf("expected the nearest grid cell, got %+v", c)
+	}
+	if c.ObservedHours < 15 {
+		t.Fatalf("expected a clear midsummer day to observe most of its daylight, got %v", c.ObservedHours)
+	}
+	if want := math.Round((c.ForecastHours-c.ObservedHours)*100) / 100; acc.MeanError != want || acc.MeanAbsError != math.Abs(want) {
+		t.Fatalf("unexpected summary %+v", acc)
+	}
+}
+
+func TestValidateSunshine_SkipsStationsOutOfRange(t *testing.T) {
+	date := time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC)
+	var observations []RadiationObservation
+	for h := range 24 {
+		observations = append(observations, RadiationObservation{Lat: 60.20, Lon: 24.96, Time: date.Add(time.Duration(h) * time.Hour)})
+	}
+	forecasts := []SunshineForecast{{GridLat: 61.0, GridLon: 24.96, SunshineHours: 10}}
+
+	if acc := ValidateSunshine(date, forecasts, observations, 10); len(acc.Comparisons) != 0 {
+		t.Fatalf("expected no comparison beyond 10 km, got %+v", acc.Comparisons)
+	}
+}
@@ -0,0 +1,127 @@
+package weather
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Languages with symbol descriptions.
+const (
+	LangFinnish = "fi"
+	LangSwedish = "sv"
+	LangEnglish = "en"
+)
+
+type symbolText struct {
+	fi, sv, en string
+}
+
+// ConditionUnknown is the condition of symbol codes outside the table.
+const ConditionUnknown = "unknown"
+
+// Symbol describes a WeatherSymbol3 code, the values stored in Symbol, for
+// clients building icon sets.
+type Symbol struct {
+	Code int
+	// Condition is a stable slug such as "partly_cloudy" or "light_rain".
+	Condition string
+	// DayNight is true for symbols drawn with the sun by day and the moon
+	// by night; the others look the same around the clock.
+	DayNight bool
+	text     symbolText
+}
+
+// Description returns the symbol's short text in lang, or "" for unknown
+// languages.
+func (s Symbol) Description(lang string) string {
+	switch lang {
+	case LangFinnish:
+		return s.text.fi
+	case LangSwedish:
+		return s.text.sv
+	case LangEnglish:
+		return s.text.en
+	default:
+		return ""
+	}
+}
+
+// weatherSymbol3Table describes every FMI WeatherSymbol3 code, ordered by
+// code.
+var weatherSymbol3Table = []Symbol{
+	{1, "clear", true, symbolText{"selkeää", "klart", "clear"}},
+	{2, "partly_cloudy", true, symbolText{"puolipilvistä", "halvklart", "partly cloudy"}},
+	{3, "cloudy", false, symbolText{"pilvistä", "mulet", "cloudy"}},
+	{21, "light_showers", true, symbolText{"heikkoja sadekuuroja", "lätta regnskurar", "light showers"}},
+	{22, "showers", true, symbolText{"sadekuuroja", "regnskurar", "showers"}},
+	{23, "heavy_showers", true, symbolText{"voimakkaita sadekuuroja", "kraftiga regnskurar", "heavy showers"}},
+	{31, "light_rain", false, symbolText{"heikkoa vesisadetta", "lätt regn", "light rain"}},
+	{32, "rain", false, symbolText{"vesisadetta", "regn", "rain"}},
+	{33, "heavy_rain", false, symbolText{"voimakasta vesisadetta", "kraftigt regn", "heavy rain"}},
+	{41, "light_snow_showers", true, symbolText{"heikkoja lumikuuroja", "lätta snöbyar", "light snow showers"}},
+	{42, "snow_showers", true, symbolText{"lumikuuroja", "snöbyar", "snow showers"}},
+	{43, "heavy_snow_showers", true, symbolText{"voimakkaita lumikuuroja", "kraftiga snöbyar", "heavy snow showers"}},
+	{51, "light_snow", false, symbolText{"heikkoa lumisadetta", "lätt snöfall", "light snowfall"}},
+	{52, "snow", false, symbolText{"lumisadetta", "snöfall", "snowfall"}},
+	{53, "heavy_snow", false, symbolText{"voimakasta lumisadetta", "ymnigt snöfall", "heavy snowfall"}},
+	{61, "thundershowers", true, symbolText{"ukkoskuuroja", "åskskurar", "thundershowers"}},
+	{62, "heavy_thundershowers", true, symbolText{"voimakkaita ukkoskuuroja", "kraftiga åskskurar", "heavy thundershowers"}},
+	{63, "thunder", false, symbolText{"ukkosta", "åska", "thunder"}},
+	{64, "heavy_thunder", false, symbolText{"voimakasta ukkosta", "kraftigt åskväder", "heavy thunder"}},
+	{71, "light_sleet_showers", true, symbolText{"heikkoja räntäkuuroja", "lätta byar av snöblandat regn", "light sleet showers"}},
+	{72, "sleet_showers", true, symbolText{"räntäkuuroja", "byar av snöblandat regn", "sleet showers"}},
+	{73, "heavy_sleet_showers", true, symbolText{"voimakkaita räntäkuuroja", "kraftiga byar av snöblandat regn", "heavy sleet showers"}},
+	{81, "light_sleet", false, symbolText{"heikkoa räntäsadetta", "lätt snöblandat regn", "light sleet"}},
+	{82, "sleet", false, symbolText{"räntäsadetta", "snöblandat regn", "sleet"}},
+	{83, "heavy_sleet", false, symbolText{"voimakasta räntäsadetta", "kraftigt snöblandat regn", "heavy sleet"}},
+	{91, "haze", false, symbolText{"utua", "dis", "haze"}},
+	{92, "fog", false, symbolText{"sumua", "dimma", "fog"}},
+}
+
+var weatherSymbol3ByCode = func() map[int]Symbol {
+	byCode := make(map[int]Symbol, len(weatherSymbol3Table))
+	for _, s := range weatherSymbol3Table {
+		byCode[s.Code] = s
+	}
+	return byCode
+}()
+
+// Symbols returns the symbol table ordered by code.
+func Symbols() []Symbol {
+	return slices.Clone(weatherSymbol3Table)
+}
+
+// lookupSymbol finds a WeatherSymbol3 value such as "3" in the table.
+func lookupSymbol(symbol string) (Symbol, bool) {
+	code, err := strconv.Atoi(strings.TrimSpace(symbol))
+	if err != nil {
+		return Symbol{}, false
+	}
+	s, ok := weatherSymbol3ByCode[code]
+	return s, ok
+}
+
+// SymbolCondition returns the condition slug for a WeatherSymbol3 value, or
+// ConditionUnknown for codes outside the table.
+func SymbolCondition(symbol string) string {
+	if s, ok := lookupSymbol(symbol); ok {
+		return s.Condition
+	}
+	return ConditionUnknown
+}
+
+// ValidLang reports whether symbol descriptions exist for lang.
+func ValidLang(lang string) bool {
+	return lang == LangFinnish || lang == LangSwedish || lang == LangEnglish
+}
+
+// SymbolDescription returns the text for a WeatherSymbol3 value such as
+// "3", or "" for unknown symbols and languages.
+func SymbolDescription(symbol, lang string) string {
+	s, ok := lookupSymbol(symbol)
+	if !ok {
+		return ""
+	}
+	return s.Description(lang)
+}
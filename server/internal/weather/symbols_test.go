@@ -0,0 +1,82 @@
+package weather
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestSymbolDescription_CoversWeatherSymbol3(t *testing.T) {
+	codes := []int{1, 2, 3, 21, 22, 23, 31, 32, 33, 41, 42, 43, 51, 52, 53, 61, 62, 63, 64, 71, 72, 73, 81, 82, 83, 91, 92}
+	for _, code := range codes {
+		for _, lang := range []string{LangFinnish, LangSwedish, LangEnglish} {
+			if SymbolDescription(strconv.Itoa(code), lang) == "" {
+				t.Errorf("missing %s description for symbol %d", lang, code)
+			}
+		}
+	}
+	if len(weatherSymbol3ByCode) != len(codes) {
+		t.Errorf("table has %d symbols, want %d", len(weatherSymbol3ByCode), len(codes))
+	}
+}
+
+func TestSymbolDescription_UnknownValuesAreEmpty(t *testing.T) {
+	// SmartSymbol codes run up to 199 with night variants offset by 100;
+	// anything outside the WeatherSymbol3 table must come back empty.
+	for code := 0; code < 200; code++ {
+		_, known := weatherSymbol3ByCode[code]
+		got := SymbolDescription(strconv.Itoa(code), LangEnglish)
+		if known != (got != "") {
+			t.Errorf("symbol %d: known=%v description=%q", code, known, got)
+		}
+	}
+	for _, symbol := range []string{"", "abc", "3.5"} {
+		if got := SymbolDescription(symbol, LangEnglish); got != "" {
+			t.Errorf("symbol %q: expected empty description, got %q", symbol, got)
+		}
+	}
+	if got := SymbolDescription("1", "de"); got != "" {
+		t.Errorf("expected empty description for unknown language, got %q", got)
+	}
+	if got := SymbolDescription("1", LangFinnish); got != "selkeää" {
+		t.Errorf("expected Finnish text, got %q", got)
+	}
+}
+
+func TestSymbols_ConditionsAreUniqueSlugs(t *testing.T) {
+	symbols := Symbols()
+	if len(symbols) != len(weatherSymbol3ByCode) {
+		t.Fatalf("table has duplicate codes: %d symbols, %d codes", len(symbols), len(weatherSymbol3ByCode))
+	}
+	seen := map[string]int{}
+	for i, s := range symbols {
+		if i > 0 && s.Code <= symbols[i-1].Code {
+			t.Errorf("symbol %d is out of order", s.Code)
+		}
+		if !slugPattern.MatchString(s.Condition) || s.Condition == ConditionUnknown {
+			t.Errorf("symbol %d: bad condition %q", s.Code, s.Condition)
+		}
+		if other, ok := seen[s.Condition]; ok {
+			t.Errorf("symbols %d and %d share condition %q", other, s.Code, s.Condition)
+		}
+		seen[s.Condition] = s.Code
+	}
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z]+(_[a-z]+)*$`)
+
+func TestSymbolCondition(t *testing.T) {
+	for symbol, want := range map[string]string{
+		"1":   "clear",
+		" 2 ": "partly_cloudy",
+		"32":  "rain",
+		"92":  "fog",
+		"101": ConditionUnknown,
+		"":    ConditionUnknown,
+		"abc": ConditionUnknown,
+	} {
+		if got := SymbolCondition(symbol); got != want {
+			t.Errorf("SymbolCondition(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package weather
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	synopticHighPressure     = 1020.0 // hPa
+	synopticLowPressure      = 1005.0 // hPa
+	synopticPressureTrend    = 4.0    // hPa change over the lookahead
+	synopticGeopHeightTrend  = 40.0   // geopotential metres over the lookahead
+	synopticLookaheadMaxDays = 2
+)
+
+var compassPoints = []string{"north", "northeast", "east", "southeast", "south", "southwest", "west", "northwest"}
+
+// DescribePressureSituation produces a one-sentence synoptic narrative from
+// the daily pressure (falling back to geopotential height) trend over the
+// next couple of days. The direction is taken from the mean wind direction,
+// which in the westerlies is where approaching systems come from. Returns an
+// empty string when there isn't enough data or nothing notable is happening.
+func DescribePressureSituation(forecasts []DailyForecast) string {
+	if len(forecasts) < 2 {
+		return ""
+	}
+
+	trend, level, ok := pressureTrend(forecasts)
+	if !ok {
+		return ""
+	}
+
+	from := ""
+	if dir := meanWindDirection(forecasts); dir != nil {
+		from = " from the " + compassPoint(*dir)
+	}
+
+	switch {
+	case trend > 0 && (level == nil || *level >= synopticLowPressure):
+		return fmt.Sprintf("High pressure building%s, bringing more settled weather.", from)
+	case trend > 0:
+		return "Low pressure filling and moving away, conditions gradually improving."
+	case trend < 0 && level != nil && *level >= synopticHighPressure:
+		return fmt.Sprintf("High pressure weakening as a low approaches%s.", from)
+	case trend < 0:
+		return fmt.Sprintf("Low pressure approaching%s, bringing more unsettled weather.", from)
+	case level != nil && *level >= synopticHighPressure:
+		return "High pressure remains in control with settled weather."
+	case level != nil && *level <= synopticLowPressure:
+		return "Low pressure lingers over the area with changeable weather."
+	default:
+		return ""
+	}
+}
+
+// pressureTrend returns +1, -1 or 0 for the pressure tendency between the
+// first day and the last day within the lookahead, plus the first day's mean
+// sea-level pressure when known.
+func pressureTrend(forecasts []DailyForecast) (int, *float64, bool) {
+	last := min(len(forecasts)-1, synopticLookaheadMaxDays)
+	first := forecasts[0]
+
+	if first.PressureAvg != nil {
+		for i := last; i >= 1; i-- {
+			if p := forecasts[i].PressureAvg; p != nil {
+				return classifyTrend(*p-*first.PressureAvg, synopticPressureTrend), first.PressureAvg, true
+			}
+		}
+	}
+	if first.GeopHeightAvg != nil {
+		for i := last; i >= 1; i-- {
+			if g := forecasts[i].GeopHeightAvg; g != nil {
+				return classifyTrend(*g-*first.GeopHeightAvg, synopticGeopHeightTrend), first.PressureAvg, true
+			}
+		}
+	}
+	return 0, nil, false
+}
+
+func classifyTrend(delta, threshold float64) int {
+	switch {
+	case delta >= threshold:
+		return 1
+	case delta <= -threshold:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func meanWindDirection(forecasts []DailyForecast) *float64 {
+	last := min(len(forecasts)-1, synopticLookaheadMaxDays)
+	var sinSum, cosSum float64
+	var n int
+	for _, f := range forecasts[:last+1] {
+		if f.WindDir == nil {
+			continue
+		}
+		sinSum += math.Sin(degToRad(*f.WindDir))
+		cosSum += math.Cos(degToRad(*f.WindDir))
+		n++
+	}
+	if n == 0 || (sinSum == 0 && cosSum == 0) {
+		return nil
+	}
+	mean := radToDeg(math.Atan2(sinSum, cosSum))
+	if mean < 0 {
+		mean += 360
+	}
+	return &mean
+}
+
+func compassPoint(deg float64) string {
+	idx := int(math.Round(math.Mod(deg, 360)/45)) % len(compassPoints)
+	return compassPoints[idx]
+}
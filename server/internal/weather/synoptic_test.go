@@ -0,0 +1,63 @@
+package weather
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDescribePressureSituation_HighBuildingFromWest(t *testing.T) {
+	forecasts := []DailyForecast{
+		{PressureAvg: ptr(1008), WindDir: ptr(265)},
+		{PressureAvg: ptr(1014), WindDir: ptr(275)},
+		{PressureAvg: ptr(1021), WindDir: ptr(270)},
+	}
+
+	got := DescribePressureSituation(forecasts)
+	if got != "High pressure building from the west, bringing more settled weather." {
+		t.Fatalf("unexpected narrative: %q", got)
+	}
+}
+
+func TestDescribePressureSituation_LowApproaching(t *testing.T) {
+	forecasts := []DailyForecast{
+		{PressureAvg: ptr(1012), WindDir: ptr(200)},
+		{PressureAvg: ptr(1003), WindDir: ptr(220)},
+	}
+
+	got := DescribePressureSituation(forecasts)
+	if !strings.HasPrefix(got, "Low pressure approaching from the southwest") {
+		t.Fatalf("unexpected narrative: %q", got)
+	}
+}
+
+func TestDescribePressureSituation_SteadyHigh(t *testing.T) {
+	forecasts := []DailyForecast{
+		{PressureAvg: ptr(1031)},
+		{PressureAvg: ptr(1030)},
+		{PressureAvg: ptr(1029)},
+	}
+
+	if got := DescribePressureSituation(forecasts); got != "High pressure remains in control with settled weather." {
+		t.Fatalf("unexpected narrative: %q", got)
+	}
+}
+
+func TestDescribePressureSituation_GeopHeightFallback(t *testing.T) {
+	forecasts := []DailyForecast{
+		{GeopHeightAvg: ptr(5400)},
+		{GeopHeightAvg: ptr(5460)},
+	}
+
+	if got := DescribePressureSituation(forecasts); !strings.HasPrefix(got, "High pressure building") {
+		t.Fatalf("unexpected narrative: %q", got)
+	}
+}
+
+func TestDescribePressureSituation_NotEnoughData(t *testing.T) {
+	if got := DescribePressureSituation([]DailyForecast{{PressureAvg: ptr(1010)}}); got != "" {
+		t.Fatalf("expected empty narrative, got %q", got)
+	}
+	if got := DescribePressureSituation([]DailyForecast{{}, {}}); got != "" {
+		t.Fatalf("expected empty narrative without pressure data, got %q", got)
+	}
+}
@@ -0,0 +1,145 @@
+package weather
+
+import "math"
+
+// wawaConditions maps WMO code table 4680, the present weather automatic
+// stations report as wawa, to conditions named like the forecast symbol
+// conditions where one fits. Codes that say nothing about the weather at
+// the station now, such as 0 (no significant weather) or 20-26 (weather in
+// the past hour only), map to "" so the sky decides.
+var wawaConditions = map[int]string{
+	0:  "",
+	1:  "",
+	2:  "",
+	3:  "",
+	4:  "haze",
+	5:  "haze",
+	10: "mist",
+	11: "ice_crystals",
+	12: "",
+	18: "squalls",
+	20: "",
+	21: "",
+	22: "",
+	23: "",
+	24: "",
+	25: "",
+	26: "",
+	27: "blowing_snow",
+	28: "blowing_snow",
+	29: "blowing_snow",
+	30: "fog",
+	31: "fog",
+	32: "fog",
+	33: "fog",
+	34: "fog",
+	35: "fog",
+	40: "precipitation",
+	41: "precipitation",
+	42: "heavy_precipitation",
+	43: "rain",
+	44: "heavy_rain",
+	45: "snow",
+	46: "heavy_snow",
+	47: "freezing_rain",
+	48: "freezing_rain",
+	50: "drizzle",
+	51: "drizzle",
+	52: "drizzle",
+	53: "drizzle",
+	54: "freezing_drizzle",
+	55: "freezing_drizzle",
+	56: "freezing_drizzle",
+	57: "light_rain",
+	58: "rain",
+	60: "rain",
+	61: "light_rain",
+	62: "rain",
+	63: "heavy_rain",
+	64: "freezing_rain",
+	65: "freezing_rain",
+	66: "freezing_rain",
+	67: "light_sleet",
+	68: "sleet",
+	70: "snow",
+	71: "light_snow",
+	72: "snow",
+	73: "heavy_snow",
+	74: "ice_pellets",
+	75: "ice_pellets",
+	76: "ice_pellets",
+	77: "snow_grains",
+	78: "ice_crystals",
+	80: "showers",
+	81: "light_showers",
+	82: "showers",
+	83: "heavy_showers",
+	84: "heavy_showers",
+	85: "light_snow_showers",
+	86: "snow_showers",
+	87: "heavy_snow_showers",
+	89: "hail",
+	90: "thunder",
+	91: "thunder",
+	92: "thundershowers",
+	93: "thundershowers",
+	94: "heavy_thunder",
+	95: "heavy_thundershowers",
+	96: "heavy_thundershowers",
+	99: "tornado",
+}
+
+// Precipitation intensity bounds in mm/h for the fallback when a station
+// reports no wawa.
+const (
+	lightPrecipMax = 1.0
+	heavyPrecipMin = 4.0
+)
+
+// foggyVisibility is the visibility in metres below which a station
+// without wawa is taken to be in fog.
+const foggyVisibility = 1000
+
+// ObservationCondition describes an observation with a condition slug. It
+// decodes the station's wawa code and, when the code is missing or only
+// says there is no significant weather, estimates one from precipitation
+// intensity, temperature, visibility and cloud cover (in oktas). It is ""
+// when the observation has none of these.
+func ObservationCondition(obs Observation) string {
+	if obs.WeatherCode != nil {
+		if condition := wawaConditions[int(math.Round(*obs.WeatherCode))]; condition != "" {
+			return condition
+		}
+	}
+
+	if obs.PrecipIntensity != nil && *obs.PrecipIntensity > 0 {
+		kind := "rain"
+		if obs.Temperature != nil && *obs.Temperature <= 0 {
+			kind = "snow"
+		} else if obs.Temperature != nil && *obs.Temperature < 2 {
+			kind = "sleet"
+		}
+		switch intensity := *obs.PrecipIntensity; {
+		case intensity < lightPrecipMax:
+			return "light_" + kind
+		case intensity >= heavyPrecipMin:
+			return "heavy_" + kind
+		default:
+			return kind
+		}
+	}
+	if obs.Visibility != nil && *obs.Visibility < foggyVisibility {
+		return "fog"
+	}
+	if obs.TotalCloudCover != nil {
+		switch oktas := *obs.TotalCloudCover; {
+		case oktas <= 1:
+			return "clear"
+		case oktas <= 6:
+			return "partly_cloudy"
+		default:
+			return "cloudy"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package weather
+
+import (
+	"slices"
+	"testing"
+)
+
+// wmo4680Codes are the codes WMO code table 4680 defines.
+var wmo4680Codes = slices.Concat(
+	[]int{0, 1, 2, 3, 4, 5, 10, 11, 12, 18},
+	seq(20, 35), seq(40, 48), seq(50, 58), seq(60, 68), seq(70, 78), seq(80, 87),
+	[]int{89}, seq(90, 96), []int{99},
+)
+
+func seq(from, to int) []int {
+	var codes []int
+	for c := from; c <= to; c++ {
+		codes = append(codes, c)
+	}
+	return codes
+}
+
+func TestWawaConditions_CoverWMO4680(t *testing.T) {
+	for code := range 100 {
+		condition, ok := wawaConditions[code]
+		if defined := slices.Contains(wmo4680Codes, code); ok != defined {
+			t.Errorf("wawa %d: in table %v, defined %v", code, ok, defined)
+		}
+		if condition != "" && !slugPattern.MatchString(condition) {
+			t.Errorf("wawa %d: bad condition %q", code, condition)
+		}
+	}
+
+	// Weather now decodes directly; no weather and past-hour codes leave
+	// it to the sky.
+	for code, want := range map[int]string{
+		0: "", 4: "haze", 10: "mist", 21: "", 23: "", 30: "fog", 45: "snow", 51: "drizzle",
+		54: "freezing_drizzle", 61: "light_rain", 63: "heavy_rain", 68: "sleet", 71: "light_snow",
+		81: "light_showers", 86: "snow_showers", 89: "hail", 92: "thundershowers", 95: "heavy_thundershowers",
+	} {
+		if got := wawaConditions[code]; got != want {
+			t.Errorf("wawa %d = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestObservationCondition(t *testing.T) {
+	cases := []struct {
+		name string
+		obs  Observation
+		want string
+	}{
+		{"wawa", Observation{WeatherCode: ptr(61), TotalCloudCover: ptr(8)}, "light_rain"},
+		{"wawa wins over the fallback", Observation{WeatherCode: ptr(71), PrecipIntensity: ptr(6), Temperature: ptr(10)}, "light_snow"},
+		{"no significant weather uses the sky", Observation{WeatherCode: ptr(0), TotalCloudCover: ptr(0)}, "clear"},
+		{"past-hour rain uses the sky", Observation{WeatherCode: ptr(23), TotalCloudCover: ptr(4)}, "partly_cloudy"},
+		{"undefined code uses the fallback", Observation{WeatherCode: ptr(7), TotalCloudCover: ptr(8)}, "cloudy"},
+		{"light rain", Observation{PrecipIntensity: ptr(0.3), Temperature: ptr(8)}, "light_rain"},
+		{"heavy rain without temperature", Observation{PrecipIntensity: ptr(5)}, "heavy_rain"},
+		{"snow", Observation{PrecipIntensity: ptr(2), Temperature: ptr(-3)}, "snow"},
+		{"sleet", Observation{PrecipIntensity: ptr(0.5), Temperature: ptr(1)}, "light_sleet"},
+		{"fog", Observation{PrecipIntensity: ptr(0), Visibility: ptr(300), TotalCloudCover: ptr(8)}, "fog"},
+		{"mostly clear", Observation{Visibility: ptr(40000), TotalCloudCover: ptr(1)}, "clear"},
+		{"overcast", Observation{TotalCloudCover: ptr(7)}, "cloudy"},
+		{"nothing to go on", Observation{Temperature: ptr(12)}, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ObservationCondition(tc.obs); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
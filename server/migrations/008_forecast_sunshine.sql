@@ -0,0 +1,2 @@
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS sunshine_hours DOUBLE PRECISION;
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS day_length_hours DOUBLE PRECISION;
@@ -0,0 +1 @@
+ALTER TABLE hourly_forecasts ADD COLUMN IF NOT EXISTS fog_intensity DOUBLE PRECISION;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS custom_stations (
+    client_id  TEXT NOT NULL,
+    station_id TEXT NOT NULL,
+    geom       GEOGRAPHY(POINT, 4326) NOT NULL,
+    source     TEXT NOT NULL,
+    PRIMARY KEY (client_id, station_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_custom_stations_geom ON custom_stations USING GIST (geom);
+
+CREATE TABLE IF NOT EXISTS custom_observations (
+    client_id   TEXT NOT NULL,
+    station_id  TEXT NOT NULL,
+    observed_at TIMESTAMPTZ NOT NULL,
+    temperature DOUBLE PRECISION,
+    humidity    DOUBLE PRECISION,
+    dew_point   DOUBLE PRECISION,
+    wind_speed  DOUBLE PRECISION,
+    wind_gust   DOUBLE PRECISION,
+    wind_dir    DOUBLE PRECISION,
+    pressure    DOUBLE PRECISION,
+    precip_1h   DOUBLE PRECISION,
+    PRIMARY KEY (client_id, station_id, observed_at),
+    FOREIGN KEY (client_id, station_id) REFERENCES custom_stations (client_id, station_id)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS forecast_subscriptions (
+    id          BIGSERIAL PRIMARY KEY,
+    client_id   TEXT NOT NULL,
+    lat         DOUBLE PRECISION NOT NULL,
+    lon         DOUBLE PRECISION NOT NULL,
+    webhook_url TEXT NOT NULL,
+    snapshot    JSONB,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_forecast_subscriptions_client ON forecast_subscriptions (client_id);
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS fetcher_instances (
+    instance_id TEXT PRIMARY KEY,
+    seen_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+-- Rows written before versioning read as version 0 and are upgraded or
+-- refetched by the service.
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS schema_version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE hourly_forecasts ADD COLUMN IF NOT EXISTS schema_version INTEGER NOT NULL DEFAULT 0;
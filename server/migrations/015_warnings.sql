@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS warnings (
+    id          TEXT PRIMARY KEY,
+    event       TEXT NOT NULL,
+    severity    TEXT NOT NULL,
+    headline    TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    area_desc   TEXT NOT NULL DEFAULT '',
+    onset       TIMESTAMPTZ NOT NULL,
+    expires     TIMESTAMPTZ NOT NULL,
+    geom        GEOMETRY(MULTIPOLYGON, 4326) NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_warnings_geom ON warnings USING GIST (geom);
+CREATE INDEX IF NOT EXISTS idx_warnings_expires ON warnings (expires);
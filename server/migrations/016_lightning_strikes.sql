@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS lightning_strikes (
+    struck_at    TIMESTAMPTZ NOT NULL,
+    lat          DOUBLE PRECISION NOT NULL,
+    lon          DOUBLE PRECISION NOT NULL,
+    geom         GEOGRAPHY(POINT, 4326) NOT NULL,
+    peak_current DOUBLE PRECISION,
+    multiplicity INTEGER,
+    PRIMARY KEY (struck_at, lat, lon)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lightning_strikes_geom ON lightning_strikes USING GIST (geom);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS air_quality_stations (
+    fmisid INTEGER PRIMARY KEY,
+    name   TEXT NOT NULL,
+    geom   GEOGRAPHY(POINT, 4326) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_air_quality_stations_geom ON air_quality_stations USING GIST (geom);
+
+CREATE TABLE IF NOT EXISTS air_quality (
+    fmisid      INTEGER NOT NULL REFERENCES air_quality_stations(fmisid),
+    observed_at TIMESTAMPTZ NOT NULL,
+    pm25        DOUBLE PRECISION,
+    pm10        DOUBLE PRECISION,
+    o3          DOUBLE PRECISION,
+    no2         DOUBLE PRECISION,
+    PRIMARY KEY (fmisid, observed_at)
+);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS marine_stations (
+    fmisid INTEGER PRIMARY KEY,
+    name   TEXT NOT NULL,
+    geom   GEOGRAPHY(POINT, 4326) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_marine_stations_geom ON marine_stations USING GIST (geom);
+
+CREATE TABLE IF NOT EXISTS marine_observations (
+    fmisid            INTEGER NOT NULL REFERENCES marine_stations(fmisid),
+    observed_at       TIMESTAMPTZ NOT NULL,
+    wave_height       DOUBLE PRECISION,
+    wave_direction    DOUBLE PRECISION,
+    wave_period       DOUBLE PRECISION,
+    water_temperature DOUBLE PRECISION,
+    sea_level         DOUBLE PRECISION,
+    PRIMARY KEY (fmisid, observed_at)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS road_stations (
+    fmisid INTEGER PRIMARY KEY,
+    name   TEXT NOT NULL,
+    geom   GEOGRAPHY(POINT, 4326) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_road_stations_geom ON road_stations USING GIST (geom);
+
+CREATE TABLE IF NOT EXISTS road_observations (
+    fmisid           INTEGER NOT NULL REFERENCES road_stations(fmisid),
+    observed_at      TIMESTAMPTZ NOT NULL,
+    road_temperature DOUBLE PRECISION,
+    air_temperature  DOUBLE PRECISION,
+    condition        INTEGER,
+    PRIMARY KEY (fmisid, observed_at)
+);
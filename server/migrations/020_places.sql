@@ -0,0 +1,112 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS places (
+    name   TEXT NOT NULL,
+    region TEXT NOT NULL,
+    lat    DOUBLE PRECISION NOT NULL,
+    lon    DOUBLE PRECISION NOT NULL,
+    -- GeoNames ID, which FMI accepts as geoid; NULL where not known.
+    geoid  INTEGER,
+    PRIMARY KEY (name, region)
+);
+
+CREATE INDEX IF NOT EXISTS idx_places_name_trgm ON places USING GIN (lower(name) gin_trgm_ops);
+
+-- Finnish cities, towns and a few Lapland villages people look up for
+-- weather. Coordinates are town centres.
+INSERT INTO places (name, region, lat, lon, geoid) VALUES
+    ('Helsinki', 'Uusimaa', 60.1699, 24.9384, 658225),
+    ('Espoo', 'Uusimaa', 60.2055, 24.6559, 660129),
+    ('Vantaa', 'Uusimaa', 60.2934, 25.0378, 632453),
+    ('Tampere', 'Pirkanmaa', 61.4978, 23.7610, 634963),
+    ('Oulu', 'Pohjois-Pohjanmaa', 65.0121, 25.4651, 643492),
+    ('Turku', 'Varsinais-Suomi', 60.4518, 22.2666, 633679),
+    ('Jyväskylä', 'Keski-Suomi', 62.2426, 25.7473, 655195),
+    ('Lahti', 'Päijät-Häme', 60.9827, 25.6612, 649360),
+    ('Kuopio', 'Pohjois-Savo', 62.8924, 27.6770, 650225),
+    ('Pori', 'Satakunta', 61.4851, 21.7974, 640276),
+    ('Kouvola', 'Kymenlaakso', 60.8681, 26.7042, NULL),
+    ('Joensuu', 'Pohjois-Karjala', 62.6010, 29.7636, 655808),
+    ('Lappeenranta', 'Etelä-Karjala', 61.0587, 28.1887, 648900),
+    ('Hämeenlinna', 'Kanta-Häme', 60.9959, 24.4643, NULL),
+    ('Vaasa', 'Pohjanmaa', 63.0951, 21.6165, 632978),
+    ('Seinäjoki', 'Etelä-Pohjanmaa', 62.7903, 22.8403, NULL),
+    ('Rovaniemi', 'Lappi', 66.5039, 25.7294, 638936),
+    ('Mikkeli', 'Etelä-Savo', 61.6886, 27.2723, NULL),
+    ('Kotka', 'Kymenlaakso', 60.4664, 26.9458, NULL),
+    ('Salo', 'Varsinais-Suomi', 60.3831, 23.1332, NULL),
+    ('Porvoo', 'Uusimaa', 60.3923, 25.6651, NULL),
+    ('Kokkola', 'Keski-Pohjanmaa', 63.8385, 23.1307, NULL),
+    ('Hyvinkää', 'Uusimaa', 60.6305, 24.8597, NULL),
+    ('Lohja', 'Uusimaa', 60.2486, 24.0650, NULL),
+    ('Järvenpää', 'Uusimaa', 60.4737, 25.0899, NULL),
+    ('Rauma', 'Satakunta', 61.1272, 21.5113, NULL),
+    ('Kajaani', 'Kainuu', 64.2245, 27.7334, 654899),
+    ('Kerava', 'Uusimaa', 60.4034, 25.1050, NULL),
+    ('Savonlinna', 'Etelä-Savo', 61.8699, 28.8794, NULL),
+    ('Nokia', 'Pirkanmaa', 61.4775, 23.5082, NULL),
+    ('Kangasala', 'Pirkanmaa', 61.4639, 24.0650, NULL),
+    ('Kirkkonummi', 'Uusimaa', 60.1236, 24.4385, NULL),
+    ('Tuusula', 'Uusimaa', 60.4033, 25.0270, NULL),
+    ('Nurmijärvi', 'Uusimaa', 60.4642, 24.8070, NULL),
+    ('Ylöjärvi', 'Pirkanmaa', 61.5497, 23.5961, NULL),
+    ('Kaarina', 'Varsinais-Suomi', 60.4072, 22.3695, NULL),
+    ('Vihti', 'Uusimaa', 60.4167, 24.3200, NULL),
+    ('Riihimäki', 'Kanta-Häme', 60.7377, 24.7772, NULL),
+    ('Imatra', 'Etelä-Karjala', 61.1719, 28.7526, NULL),
+    ('Raahe', 'Pohjois-Pohjanmaa', 64.6848, 24.4790, NULL),
+    ('Raisio', 'Varsinais-Suomi', 60.4858, 22.1690, NULL),
+    ('Tornio', 'Lappi', 65.8481, 24.1466, NULL),
+    ('Iisalmi', 'Pohjois-Savo', 63.5581, 27.1907, NULL),
+    ('Kemi', 'Lappi', 65.7364, 24.5637, NULL),
+    ('Varkaus', 'Pohjois-Savo', 62.3153, 27.8730, NULL),
+    ('Hamina', 'Kymenlaakso', 60.5697, 27.1981, NULL),
+    ('Forssa', 'Kanta-Häme', 60.8147, 23.6219, NULL),
+    ('Heinola', 'Päijät-Häme', 61.2057, 26.0383, NULL),
+    ('Pietarsaari', 'Pohjanmaa', 63.6753, 22.7028, NULL),
+    ('Uusikaupunki', 'Varsinais-Suomi', 60.8000, 21.4083, NULL),
+    ('Kemijärvi', 'Lappi', 66.7131, 27.4306, NULL),
+    ('Kuusamo', 'Pohjois-Pohjanmaa', 65.9644, 29.1889, NULL),
+    ('Sodankylä', 'Lappi', 67.4167, 26.5833, NULL),
+    ('Inari', 'Lappi', 68.9064, 27.0288, NULL),
+    ('Ivalo', 'Lappi', 68.6583, 27.5400, NULL),
+    ('Utsjoki', 'Lappi', 69.9078, 27.0286, NULL),
+    ('Enontekiö', 'Lappi', 68.3833, 23.6333, NULL),
+    ('Kittilä', 'Lappi', 67.6547, 24.9111, NULL),
+    ('Muonio', 'Lappi', 67.9583, 23.6806, NULL),
+    ('Kilpisjärvi', 'Lappi', 69.0464, 20.7956, NULL),
+    ('Levi', 'Lappi', 67.8040, 24.8090, NULL),
+    ('Salla', 'Lappi', 66.8333, 28.6667, NULL),
+    ('Pello', 'Lappi', 66.7750, 23.9667, NULL),
+    ('Maarianhamina', 'Ahvenanmaa', 60.0973, 19.9348, NULL),
+    ('Hanko', 'Uusimaa', 59.8236, 22.9683, NULL),
+    ('Raasepori', 'Uusimaa', 59.9750, 23.4364, NULL),
+    ('Loviisa', 'Uusimaa', 60.4564, 26.2250, NULL),
+    ('Sipoo', 'Uusimaa', 60.3769, 25.2722, NULL),
+    ('Naantali', 'Varsinais-Suomi', 60.4672, 22.0260, NULL),
+    ('Parainen', 'Varsinais-Suomi', 60.3036, 22.3000, NULL),
+    ('Lieto', 'Varsinais-Suomi', 60.5000, 22.4500, NULL),
+    ('Valkeakoski', 'Pirkanmaa', 61.2644, 24.0311, NULL),
+    ('Mänttä-Vilppula', 'Pirkanmaa', 62.0292, 24.6236, NULL),
+    ('Orivesi', 'Pirkanmaa', 61.6772, 24.3572, NULL),
+    ('Pirkkala', 'Pirkanmaa', 61.4653, 23.6447, NULL),
+    ('Lempäälä', 'Pirkanmaa', 61.3139, 23.7528, NULL),
+    ('Hollola', 'Päijät-Häme', 60.9881, 25.5131, NULL),
+    ('Jämsä', 'Keski-Suomi', 61.8639, 25.1903, NULL),
+    ('Äänekoski', 'Keski-Suomi', 62.6042, 25.7264, NULL),
+    ('Pieksämäki', 'Etelä-Savo', 62.3000, 27.1333, NULL),
+    ('Siilinjärvi', 'Pohjois-Savo', 63.0750, 27.6600, NULL),
+    ('Nurmes', 'Pohjois-Karjala', 63.5444, 29.1347, NULL),
+    ('Lieksa', 'Pohjois-Karjala', 63.3167, 30.0167, NULL),
+    ('Kitee', 'Pohjois-Karjala', 62.1000, 30.1333, NULL),
+    ('Ylivieska', 'Pohjois-Pohjanmaa', 64.0722, 24.5375, NULL),
+    ('Kalajoki', 'Pohjois-Pohjanmaa', 64.2597, 23.9486, NULL),
+    ('Kempele', 'Pohjois-Pohjanmaa', 64.9128, 25.5083, NULL),
+    ('Pudasjärvi', 'Pohjois-Pohjanmaa', 65.3600, 26.9967, NULL),
+    ('Kristiinankaupunki', 'Pohjanmaa', 62.2742, 21.3764, NULL),
+    ('Lapua', 'Etelä-Pohjanmaa', 62.9700, 23.0069, NULL),
+    ('Kauhajoki', 'Etelä-Pohjanmaa', 62.4319, 22.1794, NULL),
+    ('Sotkamo', 'Kainuu', 64.1306, 28.3903, NULL),
+    ('Suomussalmi', 'Kainuu', 64.8867, 28.9078, NULL),
+    ('Kuhmo', 'Kainuu', 64.1250, 29.5167, NULL)
+ON CONFLICT (name, region) DO NOTHING;
@@ -0,0 +1,2 @@
+ALTER TABLE stations ADD COLUMN IF NOT EXISTS elevation_m DOUBLE PRECISION;
+ALTER TABLE stations ADD COLUMN IF NOT EXISTS station_type TEXT;
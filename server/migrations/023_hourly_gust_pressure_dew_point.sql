@@ -0,0 +1,3 @@
+ALTER TABLE hourly_forecasts ADD COLUMN IF NOT EXISTS wind_gust DOUBLE PRECISION;
+ALTER TABLE hourly_forecasts ADD COLUMN IF NOT EXISTS pressure DOUBLE PRECISION;
+ALTER TABLE hourly_forecasts ADD COLUMN IF NOT EXISTS dew_point DOUBLE PRECISION;
@@ -0,0 +1,4 @@
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS temp_day_max DOUBLE PRECISION;
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS temp_day_avg DOUBLE PRECISION;
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS temp_night_min DOUBLE PRECISION;
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS temp_night_avg DOUBLE PRECISION;
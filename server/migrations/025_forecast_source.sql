@@ -0,0 +1 @@
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS source TEXT NOT NULL DEFAULT 'edited';
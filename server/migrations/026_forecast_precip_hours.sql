@@ -0,0 +1 @@
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS precip_hours_counted INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE stations ADD COLUMN IF NOT EXISTS region TEXT;
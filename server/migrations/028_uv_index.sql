@@ -0,0 +1,5 @@
+ALTER TABLE hourly_forecasts ADD COLUMN IF NOT EXISTS uv_index DOUBLE PRECISION;
+-- uv_index_avg averaged the cumulated UV dose, not an index; the daily
+-- maximum is recomputed from the UV forecast on the next request.
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS uv_index_max DOUBLE PRECISION;
+ALTER TABLE forecasts DROP COLUMN IF EXISTS uv_index_avg;
@@ -0,0 +1 @@
+ALTER TABLE forecasts ADD COLUMN IF NOT EXISTS snow_accumulation_mm DOUBLE PRECISION;
@@ -0,0 +1,4 @@
+ALTER TABLE custom_stations ADD COLUMN IF NOT EXISTS token_hash BYTEA;
+ALTER TABLE custom_stations ADD COLUMN IF NOT EXISTS ingest_format TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_stations_token ON custom_stations (token_hash);
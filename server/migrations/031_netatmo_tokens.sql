@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS netatmo_tokens (
+    client_id TEXT PRIMARY KEY,
+    linked_token_hash BYTEA NOT NULL,
+    refresh_token TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
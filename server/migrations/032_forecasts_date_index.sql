@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_forecasts_forecast_for ON forecasts (forecast_for);